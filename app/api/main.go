@@ -1,21 +1,28 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
-	"github.com/gin-gonic/gin"
-
 	"github.com/satriowisnugroho/book-store/internal/config"
 	httpv1 "github.com/satriowisnugroho/book-store/internal/handler/http/v1"
 	"github.com/satriowisnugroho/book-store/internal/repository/postgres"
 	"github.com/satriowisnugroho/book-store/internal/usecase"
 	"github.com/satriowisnugroho/book-store/pkg/auth"
+	"github.com/satriowisnugroho/book-store/pkg/blobstore"
+	"github.com/satriowisnugroho/book-store/pkg/contentfilter"
 	"github.com/satriowisnugroho/book-store/pkg/httpserver"
 	"github.com/satriowisnugroho/book-store/pkg/logger"
+	"github.com/satriowisnugroho/book-store/pkg/mailer"
+	"github.com/satriowisnugroho/book-store/pkg/metrics"
+	"github.com/satriowisnugroho/book-store/pkg/oidc"
 	pkgpostgres "github.com/satriowisnugroho/book-store/pkg/postgres"
+	"github.com/satriowisnugroho/book-store/pkg/scheduler"
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
 )
 
 func main() {
@@ -24,8 +31,29 @@ func main() {
 	// Initialize logger
 	l := logger.New(cfg.LogLevel)
 
+	// Initialize tracing
+	shutdownTracing, err := tracing.New(context.Background(), tracing.Config{
+		Exporter:    cfg.TracingConfig.Exporter,
+		ServiceName: cfg.TracingConfig.ServiceName,
+		SampleRatio: cfg.TracingConfig.SampleRatio,
+	})
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - api - tracing.New: %w", err))
+	}
+
 	// Initialize password hasher
-	passwordHasher := &auth.BcryptPasswordHasher{}
+	passwordHasher, err := auth.NewPasswordHasher(
+		cfg.PasswordHasherConfig.Algorithm,
+		auth.Argon2idParams{
+			Memory:      cfg.PasswordHasherConfig.Argon2Memory,
+			Iterations:  cfg.PasswordHasherConfig.Argon2Iterations,
+			Parallelism: cfg.PasswordHasherConfig.Argon2Parallelism,
+		},
+		cfg.PasswordHasherConfig.BcryptCost,
+	)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - api - auth.NewPasswordHasher: %w", err))
+	}
 
 	// Initialize postgres
 	postgresDb, err := pkgpostgres.NewPostgres(&cfg.DatabaseConfig)
@@ -40,16 +68,78 @@ func main() {
 	orderRepo := postgres.NewOrderRepository(postgresDb.Db)
 	orderItemRepo := postgres.NewOrderItemRepository(postgresDb.Db)
 	userRepo := postgres.NewUserRepository(postgresDb.Db)
+	reviewRepo := postgres.NewReviewRepository(postgresDb.Db)
+	readingListRepo := postgres.NewReadingListRepository(postgresDb.Db)
+	recommendationRepo := postgres.NewRecommendationRepository(postgresDb.Db)
+	tokenRepo := postgres.NewTokenRepository(postgresDb.Db)
+	signingKeyRepo := postgres.NewSigningKeyRepository(postgresDb.Db)
+	loginFailureRepo := postgres.NewLoginFailureRepository(postgresDb.Db)
+	mfaRepo := postgres.NewMFARepository(postgresDb.Db)
+	apiKeyRepo := postgres.NewAPIKeyRepository(postgresDb.Db)
+	identityRepo := postgres.NewIdentityRepository(postgresDb.Db)
+	auditRepo := postgres.NewAuditRepository(postgresDb.Db)
+
+	// Initialize blob store
+	blobStore := blobstore.NewLocalBlobStore(cfg.BlobStoreConfig.Dir, cfg.BlobStoreConfig.BaseURL)
+
+	// Initialize mailer
+	fileMailer := mailer.NewFileMailer(cfg.MailerConfig.Dir, cfg.MailerConfig.From)
+
+	// Initialize review content filter
+	reviewContentFilter := contentfilter.NewBannedWordsFilter(cfg.ReviewBannedWords)
+
+	// Initialize OpenID Connect provider, the login is disabled without an issuer URL
+	var identityProvider oidc.IdentityProvider
+	if cfg.OIDCConfig.IssuerURL != "" {
+		identityProvider = oidc.NewProvider(oidc.Config{
+			IssuerURL:    cfg.OIDCConfig.IssuerURL,
+			ClientID:     cfg.OIDCConfig.ClientID,
+			ClientSecret: cfg.OIDCConfig.ClientSecret,
+			RedirectURL:  cfg.OIDCConfig.RedirectURL,
+		}, &http.Client{Timeout: config.OIDCRequestTimeout})
+	}
+
+	// Initialize JWT key set
+	keySet := auth.NewKeySet(cfg.JWTIssuer, cfg.JWTAudience, config.SigningKeyActivationDelay)
 
 	// Initialize usecases
-	bookUsecase := usecase.NewBookUsecase(bookRepo)
-	orderUsecase := usecase.NewOrderUsecase(dbTransactionRepo, bookRepo, orderRepo, orderItemRepo)
-	userUsecase := usecase.NewUserUsecase(cfg.JWTSecret, passwordHasher, userRepo)
+	auditUsecase := usecase.NewAuditUsecase(dbTransactionRepo, auditRepo)
+	bookUsecase := usecase.NewBookUsecase(l, dbTransactionRepo, bookRepo, blobStore, auditUsecase)
+	orderUsecase := usecase.NewOrderUsecase(cfg.RequireVerifiedEmailForOrders, dbTransactionRepo, bookRepo, orderRepo, orderItemRepo, userRepo, auditUsecase)
+	userUsecase := usecase.NewUserUsecase(l, cfg.AccessTokenTTL, cfg.RefreshTokenTTL, cfg.PasswordResetURL, cfg.EmailVerificationURL, keySet, passwordHasher, fileMailer, dbTransactionRepo, userRepo, tokenRepo, loginFailureRepo, mfaRepo, identityProvider, identityRepo, auditUsecase)
+	exportUsecase := usecase.NewExportUsecase(bookRepo, orderRepo, auditUsecase)
+	reviewUsecase := usecase.NewReviewUsecase(dbTransactionRepo, bookRepo, orderItemRepo, reviewRepo, reviewContentFilter, auditUsecase)
+	readingListUsecase := usecase.NewReadingListUsecase(bookRepo, readingListRepo, blobStore)
+	recommendationUsecase := usecase.NewRecommendationUsecase(dbTransactionRepo, bookRepo, recommendationRepo, blobStore)
+	signingKeyUsecase := usecase.NewSigningKeyUsecase(cfg.JWTSigningAlgorithm, cfg.JWTKeyRotationInterval, cfg.AccessTokenTTL, keySet, dbTransactionRepo, signingKeyRepo)
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(l, userRepo, apiKeyRepo, auditUsecase)
+	privacyUsecase := usecase.NewPrivacyUsecase(dbTransactionRepo, userRepo, orderRepo, orderItemRepo, reviewRepo, auditUsecase)
+
+	// Load the signing keys before serving, creating the first one on a new database
+	if err := signingKeyUsecase.RotateSigningKeys(context.Background()); err != nil {
+		l.Fatal(fmt.Errorf("app - api - signingKeyUsecase.RotateSigningKeys: %w", err))
+	}
+
+	// Background jobs
+	jobScheduler := scheduler.New(l)
+	jobScheduler.Every("refresh recommendations", cfg.RecommendationRefreshInterval, recommendationUsecase.RefreshRecommendations)
+	jobScheduler.Every("refresh bestsellers", cfg.BestsellerRefreshInterval, bookUsecase.RefreshBestsellers)
+	jobScheduler.Every("rotate signing keys", config.SigningKeyRefreshInterval, signingKeyUsecase.RotateSigningKeys)
+	jobScheduler.Every("delete expired tokens", config.ExpiredTokenPurgeInterval, userUsecase.DeleteExpiredTokens)
+	jobScheduler.Every("delete expired login failures", config.ExpiredTokenPurgeInterval, userUsecase.DeleteExpiredLoginFailures)
 
 	// HTTP Server
-	handler := gin.New()
-	httpv1.NewRouter(handler, l, cfg, bookUsecase, orderUsecase, userUsecase)
-	httpServer := httpserver.New(handler, httpserver.Port(fmt.Sprint(cfg.Port)))
+	handler, err := httpv1.NewEngine(cfg.TrustedProxies)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - api - httpv1.NewEngine: %w", err))
+	}
+	httpv1.NewRouter(handler, l, bookUsecase, orderUsecase, userUsecase, exportUsecase, reviewUsecase, readingListUsecase, recommendationUsecase, signingKeyUsecase, apiKeyUsecase, privacyUsecase, auditUsecase, blobStore)
+	httpServer := httpserver.New(handler, httpserver.Port(fmt.Sprint(cfg.Port)), httpserver.WriteTimeout(cfg.HTTPWriteTimeout))
+
+	// Prometheus metrics, served apart from the public API
+	metricsHandler := http.NewServeMux()
+	metricsHandler.Handle("/metrics", metrics.Handler())
+	metricsServer := httpserver.New(metricsHandler, httpserver.Addr(cfg.MetricsAddr))
 
 	// Waiting signal
 	interrupt := make(chan os.Signal, 1)
@@ -57,9 +147,11 @@ func main() {
 
 	select {
 	case s := <-interrupt:
-		l.Info("app - api - signal: " + s.String())
+		l.With(logger.String("signal", s.String())).Info("app - api - signal")
 	case err = <-httpServer.Notify():
 		l.Error(fmt.Errorf("app - api - httpServer.Notify: %w", err))
+	case err = <-metricsServer.Notify():
+		l.Error(fmt.Errorf("app - api - metricsServer.Notify: %w", err))
 	}
 
 	// Shutdown
@@ -67,4 +159,16 @@ func main() {
 	if err != nil {
 		l.Error(fmt.Errorf("app - api - httpServer.Shutdown: %w", err))
 	}
+
+	err = metricsServer.Shutdown()
+	if err != nil {
+		l.Error(fmt.Errorf("app - api - metricsServer.Shutdown: %w", err))
+	}
+
+	jobScheduler.Stop()
+
+	// Flush the spans left
+	if err := shutdownTracing(context.Background()); err != nil {
+		l.Error(fmt.Errorf("app - api - shutdownTracing: %w", err))
+	}
 }
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/blobstore"
+	"github.com/satriowisnugroho/book-store/pkg/catalogfeed"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+	pkgpostgres "github.com/satriowisnugroho/book-store/pkg/postgres"
+)
+
+const usage = `Usage: cli <command> [flags]
+
+Commands:
+  import-books               Upsert books by ISBN from a CSV or ONIX 3.0 feed
+  refresh-recommendations    Recompute the customers also bought recommendations
+  refresh-bestsellers        Recompute the bestseller lists
+  export-user-data           Write a ZIP of the personal data of a user
+  erase-user                 Anonymize a user and delete the personal data, the orders are kept
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg := config.NewConfig()
+
+	// Initialize logger
+	l := logger.New(cfg.LogLevel)
+
+	// Initialize postgres
+	postgresDb, err := pkgpostgres.NewPostgres(&cfg.DatabaseConfig)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - cli - postgres.NewPostgres: %w", err))
+	}
+	defer postgresDb.Db.Close()
+
+	// Initialize repositories
+	dbTransactionRepo := postgres.NewPostgresTransactionRepository(postgresDb.Db)
+	bookRepo := postgres.NewBookRepository(postgresDb.Db)
+	recommendationRepo := postgres.NewRecommendationRepository(postgresDb.Db)
+	userRepo := postgres.NewUserRepository(postgresDb.Db)
+	orderRepo := postgres.NewOrderRepository(postgresDb.Db)
+	orderItemRepo := postgres.NewOrderItemRepository(postgresDb.Db)
+	reviewRepo := postgres.NewReviewRepository(postgresDb.Db)
+	auditRepo := postgres.NewAuditRepository(postgresDb.Db)
+
+	// Initialize blob store
+	blobStore := blobstore.NewLocalBlobStore(cfg.BlobStoreConfig.Dir, cfg.BlobStoreConfig.BaseURL)
+
+	// Initialize usecases
+	auditUsecase := usecase.NewAuditUsecase(dbTransactionRepo, auditRepo)
+	bookUsecase := usecase.NewBookUsecase(l, dbTransactionRepo, bookRepo, blobStore, auditUsecase)
+	recommendationUsecase := usecase.NewRecommendationUsecase(dbTransactionRepo, bookRepo, recommendationRepo, blobStore)
+	privacyUsecase := usecase.NewPrivacyUsecase(dbTransactionRepo, userRepo, orderRepo, orderItemRepo, reviewRepo, auditUsecase)
+
+	switch os.Args[1] {
+	case "import-books":
+		err = importBooks(bookUsecase, os.Args[2:])
+	case "refresh-recommendations":
+		err = recommendationUsecase.RefreshRecommendations(context.Background())
+	case "refresh-bestsellers":
+		err = bookUsecase.RefreshBestsellers(context.Background())
+	case "export-user-data":
+		err = exportUserData(privacyUsecase, os.Args[2:])
+	case "erase-user":
+		err = eraseUser(privacyUsecase, os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - cli - %s: %w", os.Args[1], err))
+	}
+}
+
+func importBooks(bu usecase.BookUsecaseInterface, args []string) error {
+	fs := flag.NewFlagSet("import-books", flag.ExitOnError)
+	filePath := fs.String("file", "", "path of the catalog feed")
+	format := fs.String("format", "", "csv or onix, guessed from the file extension when empty")
+	fs.Parse(args)
+
+	if *filePath == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if *format == "" {
+		*format = catalogfeed.FormatFromFilename(*filePath)
+	}
+
+	file, err := os.Open(*filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	report, err := bu.ImportBooks(context.Background(), *format, file)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
+
+func exportUserData(pu usecase.PrivacyUsecaseInterface, args []string) error {
+	fs := flag.NewFlagSet("export-user-data", flag.ExitOnError)
+	userID := fs.Int("user-id", 0, "ID of the user")
+	filePath := fs.String("output", "", "path of the ZIP file, user-<id>-data.zip when empty")
+	fs.Parse(args)
+
+	if *userID <= 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if *filePath == "" {
+		*filePath = fmt.Sprintf("user-%d-data.zip", *userID)
+	}
+
+	// The file is only created once the data is read, so a failed export leaves nothing behind
+	var buf bytes.Buffer
+	if err := pu.ExportUserData(context.Background(), *userID, &buf); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(*filePath, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+
+	fmt.Println(*filePath)
+
+	return nil
+}
+
+func eraseUser(pu usecase.PrivacyUsecaseInterface, args []string) error {
+	fs := flag.NewFlagSet("erase-user", flag.ExitOnError)
+	userID := fs.Int("user-id", 0, "ID of the user")
+	confirm := fs.Bool("yes", false, "confirm the erasure, it can't be undone")
+	fs.Parse(args)
+
+	if *userID <= 0 || !*confirm {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	return pu.EraseUser(context.Background(), *userID)
+}
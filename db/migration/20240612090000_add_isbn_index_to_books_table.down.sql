@@ -0,0 +1,2 @@
+-- The ISBNs stay normalized, the original formatting is not kept
+DROP INDEX IF EXISTS books_isbn_idx;
@@ -0,0 +1,58 @@
+-- Books are looked up by the compact ISBN-13, so hyphenated and ISBN-10 values are converted first.
+-- Returns NULL for a value that is not a valid ISBN-10 or ISBN-13
+CREATE FUNCTION pg_temp.normalize_isbn(raw varchar) RETURNS varchar AS $$
+DECLARE
+  compact varchar := upper(regexp_replace(trim(raw), '[- ]', '', 'g'));
+  body varchar;
+  check_digit varchar;
+  total integer := 0;
+BEGIN
+  IF compact ~ '^[0-9]{9}[0-9X]$' THEN
+    FOR i IN 1..9 LOOP
+      total := total + (11 - i) * substr(compact, i, 1)::integer;
+    END LOOP;
+
+    check_digit := CASE (11 - total % 11) % 11 WHEN 10 THEN 'X' ELSE ((11 - total % 11) % 11)::varchar END;
+    IF check_digit <> substr(compact, 10, 1) THEN
+      RETURN NULL;
+    END IF;
+
+    body := '978' || substr(compact, 1, 9);
+  ELSIF compact ~ '^97[89][0-9]{10}$' THEN
+    body := substr(compact, 1, 12);
+  ELSE
+    RETURN NULL;
+  END IF;
+
+  total := 0;
+  FOR i IN 1..12 LOOP
+    total := total + (CASE WHEN i % 2 = 0 THEN 3 ELSE 1 END) * substr(body, i, 1)::integer;
+  END LOOP;
+
+  check_digit := ((10 - total % 10) % 10)::varchar;
+  IF length(compact) = 13 AND check_digit <> substr(compact, 13, 1) THEN
+    RETURN NULL;
+  END IF;
+
+  RETURN body || check_digit;
+END $$ LANGUAGE plpgsql IMMUTABLE;
+
+DO $$
+DECLARE
+  invalid_ids varchar;
+BEGIN
+  SELECT string_agg("id"::varchar, ', ' ORDER BY "id") INTO invalid_ids FROM "books" WHERE pg_temp.normalize_isbn("isbn") IS NULL;
+  IF invalid_ids IS NOT NULL THEN
+    RAISE EXCEPTION 'books % have an invalid ISBN, fix them before migrating', invalid_ids;
+  END IF;
+
+  IF EXISTS (SELECT 1 FROM "books" GROUP BY pg_temp.normalize_isbn("isbn") HAVING COUNT(*) > 1) THEN
+    RAISE EXCEPTION 'books share an ISBN once normalized, merge them before migrating';
+  END IF;
+END $$;
+
+UPDATE "books" SET "isbn" = pg_temp.normalize_isbn("isbn") WHERE "isbn" <> pg_temp.normalize_isbn("isbn");
+
+DROP FUNCTION pg_temp.normalize_isbn(varchar);
+
+CREATE UNIQUE INDEX ON "books" ("isbn");
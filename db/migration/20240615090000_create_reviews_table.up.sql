@@ -0,0 +1,13 @@
+CREATE TABLE "reviews" (
+  "id" serial PRIMARY KEY,
+  "book_id" integer NOT NULL,
+  "user_id" integer NOT NULL,
+  "rating" smallint NOT NULL CHECK ("rating" BETWEEN 1 AND 5),
+  "body" text NOT NULL DEFAULT '',
+  "verified_purchase" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "reviews" ("book_id", "user_id");
+CREATE INDEX ON "reviews" ("book_id", "created_at");
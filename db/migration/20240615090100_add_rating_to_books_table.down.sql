@@ -0,0 +1,2 @@
+ALTER TABLE "books" DROP COLUMN IF EXISTS "rating_count";
+ALTER TABLE "books" DROP COLUMN IF EXISTS "rating_average";
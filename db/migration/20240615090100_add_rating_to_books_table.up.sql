@@ -0,0 +1,4 @@
+ALTER TABLE "books" ADD COLUMN "rating_average" numeric(3, 2) NOT NULL DEFAULT 0;
+ALTER TABLE "books" ADD COLUMN "rating_count" integer NOT NULL DEFAULT 0;
+
+CREATE INDEX ON "books" ("rating_average" DESC, "rating_count" DESC);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS review_reports;
+
+ALTER TABLE "reviews" DROP COLUMN IF EXISTS "report_count";
+ALTER TABLE "reviews" DROP COLUMN IF EXISTS "status";
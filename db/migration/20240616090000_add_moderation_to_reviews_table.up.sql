@@ -0,0 +1,14 @@
+ALTER TABLE "reviews" ADD COLUMN "status" varchar NOT NULL DEFAULT 'approved';
+ALTER TABLE "reviews" ADD COLUMN "report_count" integer NOT NULL DEFAULT 0;
+
+CREATE INDEX ON "reviews" ("status", "report_count" DESC, "created_at");
+
+CREATE TABLE "review_reports" (
+  "id" serial PRIMARY KEY,
+  "review_id" integer NOT NULL,
+  "user_id" integer NOT NULL,
+  "reason" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "review_reports" ("review_id", "user_id");
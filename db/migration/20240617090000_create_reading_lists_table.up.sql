@@ -0,0 +1,22 @@
+CREATE TABLE "reading_lists" (
+  "id" serial PRIMARY KEY,
+  "user_id" integer NOT NULL,
+  "name" varchar NOT NULL,
+  "is_wishlist" boolean NOT NULL DEFAULT false,
+  "is_public" boolean NOT NULL DEFAULT false,
+  "share_slug" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "reading_lists" ("user_id", "created_at");
+CREATE UNIQUE INDEX ON "reading_lists" ("user_id") WHERE "is_wishlist";
+CREATE UNIQUE INDEX ON "reading_lists" ("share_slug") WHERE "share_slug" <> '';
+CREATE INDEX ON "reading_lists" ("created_at") WHERE "is_public";
+
+CREATE TABLE "reading_list_items" (
+  "reading_list_id" integer NOT NULL REFERENCES "reading_lists" ("id") ON DELETE CASCADE,
+  "book_id" integer NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("reading_list_id", "book_id")
+);
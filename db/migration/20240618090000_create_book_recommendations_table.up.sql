@@ -0,0 +1,9 @@
+CREATE TABLE "book_recommendations" (
+  "book_id" integer NOT NULL,
+  "related_book_id" integer NOT NULL,
+  "score" double precision NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("book_id", "related_book_id")
+);
+
+CREATE INDEX ON "book_recommendations" ("book_id", "score" DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE "books" ADD COLUMN "category" varchar NOT NULL DEFAULT '';
+
+CREATE INDEX ON "books" ("category");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS book_bestsellers;
+
+DROP INDEX IF EXISTS order_items_created_at_idx;
@@ -0,0 +1,12 @@
+CREATE TABLE "book_bestsellers" (
+  "period" varchar NOT NULL,
+  "book_id" integer NOT NULL,
+  "category" varchar NOT NULL DEFAULT '',
+  "units_sold" integer NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("period", "book_id")
+);
+
+CREATE INDEX ON "book_bestsellers" ("period", "category", "units_sold" DESC);
+
+CREATE INDEX ON "order_items" ("created_at");
@@ -0,0 +1,21 @@
+CREATE TABLE "refresh_tokens" (
+  "id" serial PRIMARY KEY,
+  "user_id" integer NOT NULL,
+  "family_id" varchar NOT NULL,
+  "token_hash" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "refresh_tokens" ("token_hash");
+CREATE INDEX ON "refresh_tokens" ("family_id");
+CREATE INDEX ON "refresh_tokens" ("expires_at");
+
+CREATE TABLE "revoked_access_tokens" (
+  "jti" varchar PRIMARY KEY,
+  "expires_at" timestamptz NOT NULL
+);
+
+CREATE INDEX ON "revoked_access_tokens" ("expires_at");
@@ -0,0 +1,10 @@
+CREATE TABLE "signing_keys" (
+  "id" serial PRIMARY KEY,
+  "key_id" varchar NOT NULL,
+  "algorithm" varchar NOT NULL,
+  "private_key" text NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "signing_keys" ("key_id");
+CREATE INDEX ON "signing_keys" ("created_at");
@@ -0,0 +1,12 @@
+CREATE TABLE "password_reset_tokens" (
+  "id" serial PRIMARY KEY,
+  "user_id" integer NOT NULL,
+  "token_hash" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "password_reset_tokens" ("token_hash");
+CREATE INDEX ON "password_reset_tokens" ("user_id");
+CREATE INDEX ON "password_reset_tokens" ("expires_at");
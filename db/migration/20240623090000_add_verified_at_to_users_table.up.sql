@@ -0,0 +1,4 @@
+ALTER TABLE "users" ADD COLUMN "verified_at" timestamptz;
+
+-- Accounts registered before email verification existed are considered verified
+UPDATE "users" SET "verified_at" = "created_at";
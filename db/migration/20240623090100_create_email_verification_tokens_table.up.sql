@@ -0,0 +1,12 @@
+CREATE TABLE "email_verification_tokens" (
+  "id" serial PRIMARY KEY,
+  "user_id" integer NOT NULL,
+  "token_hash" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "email_verification_tokens" ("token_hash");
+CREATE INDEX ON "email_verification_tokens" ("user_id", "created_at");
+CREATE INDEX ON "email_verification_tokens" ("expires_at");
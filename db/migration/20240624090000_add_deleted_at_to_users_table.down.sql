@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "users_email_idx";
+CREATE UNIQUE INDEX "users_email_idx" ON "users" ("email");
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "deleted_at";
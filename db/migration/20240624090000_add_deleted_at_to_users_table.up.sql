@@ -0,0 +1,5 @@
+ALTER TABLE "users" ADD COLUMN "deleted_at" timestamptz;
+
+-- The email of a deleted account can be registered again
+DROP INDEX IF EXISTS "users_email_idx";
+CREATE UNIQUE INDEX "users_email_idx" ON "users" ("email") WHERE "deleted_at" IS NULL;
@@ -0,0 +1,10 @@
+CREATE TABLE "login_failures" (
+  "id" serial PRIMARY KEY,
+  "email" varchar NOT NULL,
+  "ip_address" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "login_failures" ("email", "created_at");
+CREATE INDEX ON "login_failures" ("ip_address", "created_at");
+CREATE INDEX ON "login_failures" ("created_at");
@@ -0,0 +1,8 @@
+CREATE TABLE "totp_credentials" (
+  "user_id" integer PRIMARY KEY,
+  "secret" varchar NOT NULL,
+  "enabled_at" timestamptz,
+  "last_used_step" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
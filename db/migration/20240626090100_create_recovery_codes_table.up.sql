@@ -0,0 +1,9 @@
+CREATE TABLE "recovery_codes" (
+  "id" serial PRIMARY KEY,
+  "user_id" integer NOT NULL,
+  "code_hash" varchar NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "recovery_codes" ("user_id", "code_hash");
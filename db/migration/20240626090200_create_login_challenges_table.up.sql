@@ -0,0 +1,11 @@
+CREATE TABLE "login_challenges" (
+  "id" serial PRIMARY KEY,
+  "user_id" integer NOT NULL,
+  "token_hash" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "login_challenges" ("token_hash");
+CREATE INDEX ON "login_challenges" ("expires_at");
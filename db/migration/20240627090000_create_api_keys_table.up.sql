@@ -0,0 +1,14 @@
+CREATE TABLE "api_keys" (
+  "id" serial PRIMARY KEY,
+  "user_id" integer NOT NULL,
+  "name" varchar NOT NULL,
+  "key_prefix" varchar NOT NULL,
+  "key_hash" varchar NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "last_used_at" timestamptz,
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "api_keys" ("key_hash");
+CREATE INDEX ON "api_keys" ("user_id");
@@ -0,0 +1,12 @@
+CREATE TABLE "oidc_login_states" (
+  "id" serial PRIMARY KEY,
+  "state_hash" varchar NOT NULL,
+  "nonce" varchar NOT NULL,
+  "code_verifier" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "oidc_login_states" ("state_hash");
+CREATE INDEX ON "oidc_login_states" ("expires_at");
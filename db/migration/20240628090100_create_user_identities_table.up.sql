@@ -0,0 +1,11 @@
+CREATE TABLE "user_identities" (
+  "id" serial PRIMARY KEY,
+  "user_id" integer NOT NULL,
+  "provider" varchar NOT NULL,
+  "subject" varchar NOT NULL,
+  "email" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "user_identities" ("provider", "subject");
+CREATE INDEX ON "user_identities" ("user_id");
@@ -0,0 +1,23 @@
+CREATE TABLE "sessions" (
+  "id" serial PRIMARY KEY,
+  "user_id" integer NOT NULL,
+  "family_id" varchar NOT NULL,
+  "device" varchar NOT NULL,
+  "user_agent" varchar NOT NULL,
+  "ip_address" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "revoked_at" timestamptz,
+  "last_seen_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "sessions" ("family_id");
+CREATE INDEX ON "sessions" ("user_id");
+CREATE INDEX ON "sessions" ("expires_at");
+
+-- Logins from before sessions were tracked keep working as sessions of an unknown device
+INSERT INTO "sessions" ("user_id", "family_id", "device", "user_agent", "ip_address", "expires_at", "last_seen_at", "created_at")
+SELECT "user_id", "family_id", 'Unknown device', '', '', MAX("expires_at"), MAX("created_at"), MIN("created_at")
+FROM "refresh_tokens"
+WHERE "revoked_at" IS NULL
+GROUP BY "user_id", "family_id";
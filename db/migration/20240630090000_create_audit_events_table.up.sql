@@ -0,0 +1,34 @@
+CREATE TABLE "audit_events" (
+  "id" serial PRIMARY KEY,
+  "actor_id" integer NOT NULL DEFAULT 0,
+  "action" varchar NOT NULL,
+  "target_type" varchar NOT NULL,
+  "target_id" varchar NOT NULL,
+  "request_id" varchar NOT NULL DEFAULT '',
+  "ip_address" varchar NOT NULL DEFAULT '',
+  "diff" json NOT NULL DEFAULT '{}',
+  "prev_hash" varchar NOT NULL,
+  "hash" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "audit_events" ("hash");
+CREATE INDEX ON "audit_events" ("action", "created_at");
+CREATE INDEX ON "audit_events" ("actor_id", "created_at");
+CREATE INDEX ON "audit_events" ("target_type", "target_id");
+CREATE INDEX ON "audit_events" ("created_at");
+
+-- The audit log is append-only, an event can't be changed or deleted once written
+CREATE FUNCTION "audit_events_append_only"() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "audit_events_append_only"
+BEFORE UPDATE OR DELETE ON "audit_events"
+FOR EACH ROW EXECUTE FUNCTION "audit_events_append_only"();
+
+CREATE TRIGGER "audit_events_no_truncate"
+BEFORE TRUNCATE ON "audit_events"
+FOR EACH STATEMENT EXECUTE FUNCTION "audit_events_append_only"();
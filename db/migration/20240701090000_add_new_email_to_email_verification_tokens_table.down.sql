@@ -0,0 +1 @@
+ALTER TABLE "email_verification_tokens" DROP COLUMN IF EXISTS "new_email";
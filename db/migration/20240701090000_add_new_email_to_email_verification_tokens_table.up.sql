@@ -0,0 +1 @@
+ALTER TABLE "email_verification_tokens" ADD COLUMN "new_email" varchar;
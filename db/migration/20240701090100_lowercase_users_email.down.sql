@@ -0,0 +1,3 @@
+-- The emails are left lowercased
+DROP INDEX IF EXISTS "users_email_idx";
+CREATE UNIQUE INDEX "users_email_idx" ON "users" ("email") WHERE "deleted_at" IS NULL;
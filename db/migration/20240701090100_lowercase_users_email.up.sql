@@ -0,0 +1,11 @@
+-- Emails are looked up lowercased, two users left with the same email would have to be merged by hand first
+DO $$
+BEGIN
+  IF EXISTS (SELECT 1 FROM "users" WHERE "deleted_at" IS NULL GROUP BY lower(trim("email")) HAVING COUNT(*) > 1) THEN
+    RAISE EXCEPTION 'users share an email differing only by case or spaces, merge them before migrating';
+  END IF;
+END $$;
+
+DROP INDEX IF EXISTS "users_email_idx";
+UPDATE "users" SET "email" = lower(trim("email")) WHERE "email" <> lower(trim("email"));
+CREATE UNIQUE INDEX "users_email_idx" ON "users" (lower("email")) WHERE "deleted_at" IS NULL;
@@ -11,21 +11,21 @@
 TRUNCATE public.books RESTART IDENTITY CASCADE;
 
 COPY public.books (isbn, title, price, created_at, updated_at) FROM stdin;
-978-0-545-01022-1	Harry Potter	25000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
-978-0-545-01022-2	Narnia	20000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
-978-0-545-01022-3	The Hunger Games	35000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
-978-0-545-01022-4	The Godfather	35000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
-978-0-545-01022-5	Book 5	30000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
-978-0-545-01022-6	Book 6	25000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
-978-0-545-01022-7	Book 7	20000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
-978-0-545-01022-8	Book 8	10000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
-978-0-545-01022-9	Book 9	15000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
-978-0-545-01022-10	Book 10	15000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
-978-0-545-01022-11	Book 11	30000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
-978-0-545-01022-12	Book 12	10000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
-978-0-545-01022-13	Book 13	40000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
-978-0-545-01022-14	Book 14	20000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
-978-0-545-01022-15	Book 15	50000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
+9780545010207	Harry Potter	25000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
+9780545010214	Narnia	20000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
+9780545010221	The Hunger Games	35000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
+9780545010238	The Godfather	35000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
+9780545010245	Book 5	30000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
+9780545010252	Book 6	25000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
+9780545010269	Book 7	20000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
+9780545010276	Book 8	10000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
+9780545010283	Book 9	15000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
+9780545010290	Book 10	15000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
+9780545010306	Book 11	30000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
+9780545010313	Book 12	10000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
+9780545010320	Book 13	40000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
+9780545010337	Book 14	20000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
+9780545010344	Book 15	50000	2024-06-07 22:43:27.750419+00	2024-06-07 22:43:27.750419+00
 \.
 
 --
@@ -1,15 +1,70 @@
 package config
 
 import (
+	"time"
+
 	"github.com/joeshaw/envdecode"
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Port           uint16 `env:"PORT,default=9999"`
-	LogLevel       string `env:"LOG_LEVEL,default=debug"`
-	JWTSecret      string `env:"JWT_SECRET,default=secret"`
-	DatabaseConfig DatabaseConfig
+	Port                          uint16        `env:"PORT,default=9999"`
+	LogLevel                      string        `env:"LOG_LEVEL,default=debug"`
+	JWTSigningAlgorithm           string        `env:"JWT_SIGNING_ALGORITHM,default=RS256"`
+	JWTIssuer                     string        `env:"JWT_ISSUER,default=book-store"`
+	JWTAudience                   string        `env:"JWT_AUDIENCE,default=book-store-api"`
+	JWTKeyRotationInterval        time.Duration `env:"JWT_KEY_ROTATION_INTERVAL,default=720h"`
+	AccessTokenTTL                time.Duration `env:"ACCESS_TOKEN_TTL,default=15m"`
+	RefreshTokenTTL               time.Duration `env:"REFRESH_TOKEN_TTL,default=720h"`
+	PasswordResetURL              string        `env:"PASSWORD_RESET_URL,default=http://localhost:3000/reset-password"`
+	EmailVerificationURL          string        `env:"EMAIL_VERIFICATION_URL,default=http://localhost:9999/v1/users/verify"`
+	RequireVerifiedEmailForOrders bool          `env:"REQUIRE_VERIFIED_EMAIL_FOR_ORDERS,default=false"`
+	HTTPWriteTimeout              time.Duration `env:"HTTP_WRITE_TIMEOUT,default=5s"`
+	MetricsAddr                   string        `env:"METRICS_ADDR,default=127.0.0.1:9100"`
+	TrustedProxies                []string      `env:"TRUSTED_PROXIES"`
+	ReviewBannedWords             []string      `env:"REVIEW_BANNED_WORDS,default=viagra;casino;free money;click here"`
+	RecommendationRefreshInterval time.Duration `env:"RECOMMENDATION_REFRESH_INTERVAL,default=1h"`
+	BestsellerRefreshInterval     time.Duration `env:"BESTSELLER_REFRESH_INTERVAL,default=15m"`
+	DatabaseConfig                DatabaseConfig
+	BlobStoreConfig               BlobStoreConfig
+	MailerConfig                  MailerConfig
+	PasswordHasherConfig          PasswordHasherConfig
+	OIDCConfig                    OIDCConfig
+	TracingConfig                 TracingConfig
+}
+
+// TracingConfig holds where the spans are exported, they are not recorded without an exporter.
+// The OTLP exporter is configured by the standard OTEL_EXPORTER_OTLP_* variables
+type TracingConfig struct {
+	Exporter    string  `env:"TRACING_EXPORTER"`
+	ServiceName string  `env:"TRACING_SERVICE_NAME,default=book-store"`
+	SampleRatio float64 `env:"TRACING_SAMPLE_RATIO,default=1"`
+}
+
+// OIDCConfig holds the client registration at the OpenID Connect provider, the login is disabled without an issuer URL
+type OIDCConfig struct {
+	IssuerURL    string `env:"OIDC_ISSUER_URL"`
+	ClientID     string `env:"OIDC_CLIENT_ID"`
+	ClientSecret string `env:"OIDC_CLIENT_SECRET"`
+	RedirectURL  string `env:"OIDC_REDIRECT_URL,default=http://localhost:3000/oidc/callback"`
+}
+
+type PasswordHasherConfig struct {
+	Algorithm         string `env:"PASSWORD_HASH_ALGORITHM,default=argon2id"`
+	Argon2Memory      uint32 `env:"ARGON2_MEMORY,default=65536"`
+	Argon2Iterations  uint32 `env:"ARGON2_ITERATIONS,default=3"`
+	Argon2Parallelism uint8  `env:"ARGON2_PARALLELISM,default=4"`
+	BcryptCost        int    `env:"BCRYPT_COST,default=10"`
+}
+
+type MailerConfig struct {
+	Dir  string `env:"MAILER_DIR,default=./mail"`
+	From string `env:"MAILER_FROM,default=Book Store <no-reply@book-store.local>"`
+}
+
+type BlobStoreConfig struct {
+	Dir     string `env:"BLOB_STORE_DIR,default=./storage"`
+	BaseURL string `env:"BLOB_STORE_BASE_URL,default=http://localhost:9999/blobs"`
 }
 
 type DatabaseConfig struct {
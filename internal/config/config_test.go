@@ -3,6 +3,7 @@ package config_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/satriowisnugroho/book-store/internal/config"
 	"github.com/stretchr/testify/assert"
@@ -15,6 +16,10 @@ func TestNewConfig(t *testing.T) {
 	cfg := config.NewConfig()
 
 	assert.NotEmpty(t, cfg)
+	// The write timeout stays short, the exports extend their own deadline
+	assert.Equal(t, 5*time.Second, cfg.HTTPWriteTimeout)
+	// The metrics are not served on the public port
+	assert.Equal(t, "127.0.0.1:9100", cfg.MetricsAddr)
 }
 
 func setupEnv() {
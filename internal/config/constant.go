@@ -1,14 +1,85 @@
 package config
 
+import "time"
+
 const (
 	// ServiceFee is a service fee
 	ServiceFee = 1000
 	// UniqueConstraintViolationCode is the pgError code for unique constraint violation error
 	UniqueConstraintViolationCode = "23505"
+	// DataExceptionClass is the pgError class of the errors caused by an invalid value
+	DataExceptionClass = "22"
+	// IntegrityConstraintViolationClass is the pgError class of the errors caused by a violated constraint
+	IntegrityConstraintViolationClass = "23"
 	// MinPasswordLen is the the minimum length of password
 	MinPasswordLen = 5
 	// AuthorizationHeader is a header for authorization
 	AuthorizationHeader = "Authorization"
 	// AuthorizationHeaderBearer is an authorization header format
 	AuthorizationHeaderBearer = "Bearer"
+	// AuthorizationHeaderAPIKey is the authorization header format of an API key
+	AuthorizationHeaderAPIKey = "ApiKey"
+	// RequestIDHeader is a header for the ID of a request, it is echoed in the response
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDMaxLen is the maximum length of a request ID given by the client, a longer one is replaced
+	RequestIDMaxLen = 128
+	// TotalCountHeader is a header for the number of rows of a streamed export
+	TotalCountHeader = "X-Total-Count"
+	// BookImportBatchSize is the number of books upserted in a single transaction during import
+	BookImportBatchSize = 100
+	// BookImportMaxFileSize is the maximum size in bytes of an uploaded catalog feed
+	BookImportMaxFileSize = 32 << 20
+	// BookCoverMaxFileSize is the maximum size in bytes of an uploaded book cover
+	BookCoverMaxFileSize = 5 << 20
+	// BookCoverMaxDimension is the maximum width and height in pixels of an uploaded book cover
+	BookCoverMaxDimension = 6000
+	// ReviewReportThreshold is the number of abuse reports that puts a published review back on hold
+	ReviewReportThreshold = 3
+	// RecommendationsPerBook is the number of co-purchased books kept for every book
+	RecommendationsPerBook = 20
+	// ExpiredTokenPurgeInterval is how often the expired refresh tokens and revoked access tokens, and the expired failed logins are deleted
+	ExpiredTokenPurgeInterval = time.Hour
+	// PasswordResetTokenTTL is how long a password reset link can be used
+	PasswordResetTokenTTL = time.Hour
+	// EmailVerificationTokenTTL is how long an email verification link can be used
+	EmailVerificationTokenTTL = 24 * time.Hour
+	// EmailVerificationResendLimit is the number of verification emails a user may receive within EmailVerificationResendWindow
+	EmailVerificationResendLimit = 3
+	// EmailVerificationResendWindow is the period over which the verification emails are throttled
+	EmailVerificationResendWindow = time.Hour
+	// LoginFailureWindow is the period over which the failed logins of an email or an IP address are counted
+	LoginFailureWindow = 24 * time.Hour
+	// LoginAccountFailureLimit is the number of failed logins of an email within LoginFailureWindow before it is locked out
+	LoginAccountFailureLimit = 5
+	// LoginIPAddressFailureLimit is the number of failed logins from an IP address within LoginFailureWindow before it is locked out
+	LoginIPAddressFailureLimit = 20
+	// LoginLockoutDuration is how long the first lockout lasts, every further failed login doubles it
+	LoginLockoutDuration = time.Minute
+	// LoginMaxLockoutDuration is the longest lockout
+	LoginMaxLockoutDuration = time.Hour
+	// LoginChallengeTTL is how long the second step of a login with two-factor authentication can be completed
+	LoginChallengeTTL = 5 * time.Minute
+	// OIDCLoginStateTTL is how long a login started at the OpenID Connect provider can be completed
+	OIDCLoginStateTTL = 10 * time.Minute
+	// OIDCRequestTimeout is how long a request to the OpenID Connect provider may take
+	OIDCRequestTimeout = 10 * time.Second
+	// TOTPIssuer is the name the authenticator apps show next to the TOTP codes
+	TOTPIssuer = "Book Store"
+	// RecoveryCodeCount is the number of recovery codes given when TOTP is enabled
+	RecoveryCodeCount = 10
+	// APIKeyPrefix starts every API key, so a leaked key is easy to recognize
+	APIKeyPrefix = "bsk_"
+	// APIKeyDisplayPrefixLen is the number of leading characters of an API key kept to tell the keys apart
+	APIKeyDisplayPrefixLen = 12
+	// APIKeyLastUsedInterval is how stale the last use of an API key may get before it is written again
+	APIKeyLastUsedInterval = time.Minute
+	// ExportWriteTimeout is how long the admin exports may stream, in place of the write timeout of the server
+	ExportWriteTimeout = 5 * time.Minute
+	// SigningKeyRefreshInterval is how often the JWT signing keys are rotated when due and reloaded from the database
+	SigningKeyRefreshInterval = time.Minute
+	// SigningKeyActivationDelay is how long a new signing key is published before it signs tokens,
+	// long enough for every API instance and JWKS cache to pick it up
+	SigningKeyActivationDelay = 5 * time.Minute
+	// JWKSMaxAge is how long the verifiers may cache the JWKS
+	JWKSMaxAge = time.Minute
 )
@@ -0,0 +1,94 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/satriowisnugroho/book-store/internal/response"
+)
+
+const (
+	// APIKeyScopeReadCatalog lets an API key export the catalog
+	APIKeyScopeReadCatalog = "read:catalog"
+	// APIKeyScopeWriteOrders lets an API key place orders and read the order history
+	APIKeyScopeWriteOrders = "write:orders"
+	// APIKeyScopeAdmin lets an API key do everything its user can, only the API keys of admins may have it
+	APIKeyScopeAdmin = "admin:*"
+
+	// APIKeyMaxNameLen is the maximum number of characters of an API key name
+	APIKeyMaxNameLen = 100
+)
+
+// APIKeyScopes list every scope an API key may have
+var APIKeyScopes = []string{APIKeyScopeReadCatalog, APIKeyScopeWriteOrders, APIKeyScopeAdmin}
+
+// APIKey struct holds entity of an API key, only the hash of the key is stored
+type APIKey struct {
+	ID     int    `json:"id"`
+	UserID int    `json:"user_id"`
+	Name   string `json:"name"`
+	// Prefix is the start of the key, to tell the keys apart
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// HasScope reports whether the API key has the scope, admin:* has every scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == APIKeyScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreatedAPIKey holds a new API key together with the key itself, which is shown once
+type CreatedAPIKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyPayload holds create API key payload representative
+type APIKeyPayload struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// Validate is func to validate API key payload
+func (p *APIKeyPayload) Validate() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" || utf8.RuneCountInString(p.Name) > APIKeyMaxNameLen {
+		return response.ErrInvalidAPIKeyName
+	}
+
+	if len(p.Scopes) == 0 {
+		return response.ErrInvalidAPIKeyScope
+	}
+
+	seen := make(map[string]bool, len(p.Scopes))
+	for _, scope := range p.Scopes {
+		if seen[scope] || !isAPIKeyScope(scope) {
+			return response.ErrInvalidAPIKeyScope
+		}
+
+		seen[scope] = true
+	}
+
+	return nil
+}
+
+func isAPIKeyScope(scope string) bool {
+	for _, s := range APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
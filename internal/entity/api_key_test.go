@@ -0,0 +1,72 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyHasScope(t *testing.T) {
+	key := &entity.APIKey{Scopes: []string{entity.APIKeyScopeReadCatalog}}
+	assert.True(t, key.HasScope(entity.APIKeyScopeReadCatalog))
+	assert.False(t, key.HasScope(entity.APIKeyScopeWriteOrders))
+	assert.False(t, key.HasScope(entity.APIKeyScopeAdmin))
+
+	adminKey := &entity.APIKey{Scopes: []string{entity.APIKeyScopeAdmin}}
+	assert.True(t, adminKey.HasScope(entity.APIKeyScopeReadCatalog))
+	assert.True(t, adminKey.HasScope(entity.APIKeyScopeWriteOrders))
+	assert.True(t, adminKey.HasScope(entity.APIKeyScopeAdmin))
+}
+
+func TestAPIKeyPayloadValidate(t *testing.T) {
+	testcases := []struct {
+		name         string
+		payload      entity.APIKeyPayload
+		expectedName string
+		wantErr      error
+	}{
+		{
+			name:    "empty name",
+			payload: entity.APIKeyPayload{Name: "   ", Scopes: []string{"read:catalog"}},
+			wantErr: response.ErrInvalidAPIKeyName,
+		},
+		{
+			name:    "name too long",
+			payload: entity.APIKeyPayload{Name: strings.Repeat("a", entity.APIKeyMaxNameLen+1), Scopes: []string{"read:catalog"}},
+			wantErr: response.ErrInvalidAPIKeyName,
+		},
+		{
+			name:    "no scope",
+			payload: entity.APIKeyPayload{Name: "Catalog sync"},
+			wantErr: response.ErrInvalidAPIKeyScope,
+		},
+		{
+			name:    "unknown scope",
+			payload: entity.APIKeyPayload{Name: "Catalog sync", Scopes: []string{"write:catalog"}},
+			wantErr: response.ErrInvalidAPIKeyScope,
+		},
+		{
+			name:    "duplicated scope",
+			payload: entity.APIKeyPayload{Name: "Catalog sync", Scopes: []string{"read:catalog", "read:catalog"}},
+			wantErr: response.ErrInvalidAPIKeyScope,
+		},
+		{
+			name:         "success",
+			payload:      entity.APIKeyPayload{Name: "  Catalog sync  ", Scopes: []string{"read:catalog", "write:orders"}},
+			expectedName: "Catalog sync",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.payload.Validate()
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr == nil {
+				assert.Equal(t, tc.expectedName, tc.payload.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/response"
+)
+
+const (
+	// AuditActionLoginSucceeded is written when a user logs in and a session is started
+	AuditActionLoginSucceeded = "login.succeeded"
+	// AuditActionLoginFailed is written for a wrong password or a wrong second factor
+	AuditActionLoginFailed = "login.failed"
+	// AuditActionPasswordChanged is written when a logged in user changes the password
+	AuditActionPasswordChanged = "password.changed"
+	// AuditActionPasswordReset is written when the password is reset with a password reset link
+	AuditActionPasswordReset = "password.reset"
+	// AuditActionOrderCreated is written when an order is placed
+	AuditActionOrderCreated = "order.created"
+	// AuditActionBookPriceChanged is written when the price of a book is changed by a catalog import
+	AuditActionBookPriceChanged = "book.price_changed"
+	// AuditActionBookCoverUploaded is written when an admin uploads the cover of a book
+	AuditActionBookCoverUploaded = "book.cover_uploaded"
+	// AuditActionReviewsModerated is written when an admin approves or rejects reviews
+	AuditActionReviewsModerated = "reviews.moderated"
+	// AuditActionAPIKeyCreated is written when a user creates an API key
+	AuditActionAPIKeyCreated = "api_key.created"
+	// AuditActionAPIKeyRevoked is written when a user revokes an API key
+	AuditActionAPIKeyRevoked = "api_key.revoked"
+	// AuditActionUserDataExported is written when the personal data of a user is exported
+	AuditActionUserDataExported = "user.data_exported"
+	// AuditActionUserErased is written when an admin erases a user
+	AuditActionUserErased = "user.erased"
+	// AuditActionUserDeleted is written when a user deletes the account
+	AuditActionUserDeleted = "user.deleted"
+	// AuditActionTOTPEnabled is written when a user turns two-factor authentication on
+	AuditActionTOTPEnabled = "totp.enabled"
+	// AuditActionTOTPDisabled is written when a user turns two-factor authentication off
+	AuditActionTOTPDisabled = "totp.disabled"
+	// AuditActionSessionRevoked is written when a user signs a session out
+	AuditActionSessionRevoked = "session.revoked"
+	// AuditActionBooksExported is written when an admin or an API key exports the catalog
+	AuditActionBooksExported = "books.exported"
+	// AuditActionOrdersExported is written when an admin exports the orders
+	AuditActionOrdersExported = "orders.exported"
+
+	// AuditTargetUser is the target type of the events about a user
+	AuditTargetUser = "user"
+	// AuditTargetOrder is the target type of the events about an order
+	AuditTargetOrder = "order"
+	// AuditTargetBook is the target type of the events about a book
+	AuditTargetBook = "book"
+	// AuditTargetReview is the target type of the events about reviews
+	AuditTargetReview = "review"
+	// AuditTargetAPIKey is the target type of the events about an API key
+	AuditTargetAPIKey = "api_key"
+	// AuditTargetSession is the target type of the events about a login session
+	AuditTargetSession = "session"
+)
+
+// AuditGenesisHash is the previous hash of the first audit event
+var AuditGenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// AuditChange holds the value of a field before and after an audited action, From is empty for a new value
+type AuditChange struct {
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// AuditDiff holds the changes of an audited action by field
+type AuditDiff map[string]AuditChange
+
+// AuditEvent struct holds entity of an entry of the append-only audit log.
+// Every event is chained to the one before it by PrevHash, so an event changed or deleted
+// behind the application's back breaks the chain
+type AuditEvent struct {
+	ID int `json:"id"`
+	// ActorID is the user who did the action, zero for an anonymous request such as a failed login
+	ActorID    int             `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id"`
+	RequestID  string          `json:"request_id"`
+	IPAddress  string          `json:"ip_address"`
+	Diff       json.RawMessage `json:"diff" swaggertype:"object"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// NewAuditEvent returns an audit event of the action on the target with the JSON of the diff
+func NewAuditEvent(action string, targetType string, targetID string, diff AuditDiff) *AuditEvent {
+	if diff == nil {
+		diff = AuditDiff{}
+	}
+
+	// A map of plain values always marshals
+	b, _ := json.Marshal(diff)
+
+	return &AuditEvent{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Diff:       b,
+	}
+}
+
+// ComputeHash returns the SHA-256 of the previous hash and the fields of the event as hex.
+// CreatedAt is hashed in UTC with the microsecond precision the database keeps
+func (e *AuditEvent) ComputeHash() string {
+	diff := e.Diff
+	if len(diff) == 0 {
+		diff = json.RawMessage("{}")
+	}
+
+	b, _ := json.Marshal([]interface{}{
+		e.PrevHash,
+		e.ActorID,
+		e.Action,
+		e.TargetType,
+		e.TargetID,
+		e.RequestID,
+		e.IPAddress,
+		diff,
+		e.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditVerification holds the result of checking the hash chain of the audit log
+type AuditVerification struct {
+	Valid bool `json:"valid"`
+	// Checked is the number of events checked, up to the first broken one
+	Checked int `json:"checked"`
+	// BrokenEventID is the first event whose hash or previous hash doesn't match, zero when the chain is valid
+	BrokenEventID int `json:"broken_event_id"`
+}
+
+// GetAuditEventsPayload holds audit event list payload representative
+type GetAuditEventsPayload struct {
+	Action     string
+	ActorID    int
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time
+	Offset     int
+	Limit      int
+}
+
+// Validate is func to validate audit event list payload
+func (p *GetAuditEventsPayload) Validate() error {
+	if p.From != nil && p.To != nil && p.From.After(*p.To) {
+		return response.ErrInvalidAuditTimeRange
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuditEvent(t *testing.T) {
+	event := entity.NewAuditEvent(entity.AuditActionBookPriceChanged, entity.AuditTargetBook, "7", entity.AuditDiff{
+		"price": {From: 10000, To: 12000},
+	})
+	assert.Equal(t, entity.AuditActionBookPriceChanged, event.Action)
+	assert.Equal(t, entity.AuditTargetBook, event.TargetType)
+	assert.Equal(t, "7", event.TargetID)
+	assert.JSONEq(t, `{"price":{"from":10000,"to":12000}}`, string(event.Diff))
+
+	event = entity.NewAuditEvent(entity.AuditActionLoginFailed, entity.AuditTargetUser, "", nil)
+	assert.Equal(t, "{}", string(event.Diff))
+}
+
+func TestAuditEventComputeHash(t *testing.T) {
+	createdAt := time.Date(2024, 6, 30, 9, 0, 0, 123456789, time.UTC)
+	newEvent := func() *entity.AuditEvent {
+		event := entity.NewAuditEvent(entity.AuditActionOrderCreated, entity.AuditTargetOrder, "1", entity.AuditDiff{"total_price": {To: 21000}})
+		event.ActorID = 2
+		event.RequestID = "request-id"
+		event.IPAddress = "127.0.0.1"
+		event.PrevHash = entity.AuditGenesisHash
+		event.CreatedAt = createdAt
+
+		return event
+	}
+
+	event := newEvent()
+	hash := event.ComputeHash()
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, newEvent().ComputeHash())
+
+	// The database keeps microseconds in its own time zone
+	event.CreatedAt = createdAt.Truncate(time.Microsecond).In(time.FixedZone("WIB", 7*60*60))
+	assert.Equal(t, hash, event.ComputeHash())
+
+	tampered := newEvent()
+	tampered.Diff = []byte(`{"total_price":{"to":1000}}`)
+	assert.NotEqual(t, hash, tampered.ComputeHash())
+
+	rechained := newEvent()
+	rechained.PrevHash = hash
+	assert.NotEqual(t, hash, rechained.ComputeHash())
+}
+
+func TestGetAuditEventsPayloadValidate(t *testing.T) {
+	from := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	payload := entity.GetAuditEventsPayload{From: &from, To: &to}
+	assert.NoError(t, payload.Validate())
+
+	payload = entity.GetAuditEventsPayload{From: &to, To: &from}
+	assert.Equal(t, response.ErrInvalidAuditTimeRange, payload.Validate())
+
+	payload = entity.GetAuditEventsPayload{From: &from}
+	assert.NoError(t, payload.Validate())
+}
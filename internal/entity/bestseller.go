@@ -0,0 +1,54 @@
+package entity
+
+import "github.com/satriowisnugroho/book-store/internal/response"
+
+const (
+	// BestsellerPeriodDay ranks the books by the copies sold in the last day
+	BestsellerPeriodDay = "day"
+	// BestsellerPeriodWeek ranks the books by the copies sold in the last 7 days
+	BestsellerPeriodWeek = "week"
+	// BestsellerPeriodMonth ranks the books by the copies sold in the last 30 days
+	BestsellerPeriodMonth = "month"
+	// BestsellerPeriodAllTime ranks the books by every copy ever sold
+	BestsellerPeriodAllTime = "all-time"
+)
+
+// BestsellerPeriods list the bestseller periods, the shortest first
+var BestsellerPeriods = []string{BestsellerPeriodDay, BestsellerPeriodWeek, BestsellerPeriodMonth, BestsellerPeriodAllTime}
+
+// BestsellerPeriodDays maps the bestseller periods to their window in days, the all-time period has no window
+var BestsellerPeriodDays = map[string]int{
+	BestsellerPeriodDay:     1,
+	BestsellerPeriodWeek:    7,
+	BestsellerPeriodMonth:   30,
+	BestsellerPeriodAllTime: 0,
+}
+
+// Bestseller holds a book of a bestseller list with its rank in the list
+type Bestseller struct {
+	Rank      int   `json:"rank"`
+	BookID    int   `json:"book_id"`
+	UnitsSold int   `json:"units_sold"`
+	Book      *Book `json:"book"`
+}
+
+// GetBestsellersPayload holds bestseller list payload representative
+type GetBestsellersPayload struct {
+	Period   string
+	Category string
+	Offset   int
+	Limit    int
+}
+
+// Validate is func to validate bestseller list payload, an empty period defaults to the week
+func (p *GetBestsellersPayload) Validate() error {
+	if p.Period == "" {
+		p.Period = BestsellerPeriodWeek
+	}
+
+	if _, ok := BestsellerPeriodDays[p.Period]; !ok {
+		return response.ErrInvalidBestsellerPeriod
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBestsellersPayloadValidate(t *testing.T) {
+	testcases := []struct {
+		name           string
+		payload        entity.GetBestsellersPayload
+		expectedPeriod string
+		wantErr        error
+	}{
+		{
+			name:    "unknown period",
+			payload: entity.GetBestsellersPayload{Period: "year"},
+			wantErr: response.ErrInvalidBestsellerPeriod,
+		},
+		{
+			name:           "default period",
+			payload:        entity.GetBestsellersPayload{},
+			expectedPeriod: entity.BestsellerPeriodWeek,
+		},
+		{
+			name:           "success",
+			payload:        entity.GetBestsellersPayload{Period: entity.BestsellerPeriodAllTime},
+			expectedPeriod: entity.BestsellerPeriodAllTime,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.payload.Validate()
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr == nil {
+				assert.Equal(t, tc.expectedPeriod, tc.payload.Period)
+			}
+		})
+	}
+}
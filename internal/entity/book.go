@@ -1,22 +1,114 @@
 package entity
 
 import (
+	"strings"
 	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/pkg/isbn"
 )
 
 // Book struct holds entity of book
 type Book struct {
-	ID        int       `json:"id"`
-	Isbn      string    `json:"isbn"`
-	Title     string    `json:"title"`
-	Price     int       `json:"price"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            int               `json:"id"`
+	Isbn          string            `json:"isbn"`
+	Title         string            `json:"title"`
+	Price         int               `json:"price"`
+	Category      string            `json:"category"`
+	CoverKey      string            `json:"-"`
+	CoverURLs     map[string]string `json:"cover_urls,omitempty"`
+	RatingAverage float64           `json:"rating_average"`
+	RatingCount   int               `json:"rating_count"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+const (
+	// BookCoverOriginal is the name of the uploaded cover image in the cover URLs
+	BookCoverOriginal = "original"
+	// BookCoverKeyPrefix is the prefix of the blob keys of the covers, the only blobs served publicly
+	BookCoverKeyPrefix = "covers/"
+)
+
+// BookCoverThumbnailWidths maps the cover thumbnail names to their width in pixels
+var BookCoverThumbnailWidths = map[string]int{
+	"small":  120,
+	"medium": 300,
+	"large":  600,
 }
 
+const (
+	// BookSortRating sorts books by the highest average rating first
+	BookSortRating = "rating"
+	// BookSortPopularity sorts books by the most copies sold of all time first
+	BookSortPopularity = "popularity"
+)
+
 // GetBooksPayload holds login payload representative
 type GetBooksPayload struct {
 	TitleKeyword string
+	SortBy       string
 	Offset       int
 	Limit        int
 }
+
+// IsValidBookSort reports whether the sort option is known, an empty sort keeps the default order
+func IsValidBookSort(sortBy string) bool {
+	switch sortBy {
+	case "", BookSortRating, BookSortPopularity:
+		return true
+	}
+
+	return false
+}
+
+const (
+	// BookImportStatusCreated marks an imported row that created a new book
+	BookImportStatusCreated = "created"
+	// BookImportStatusUpdated marks an imported row that updated an existing book
+	BookImportStatusUpdated = "updated"
+	// BookImportStatusRejected marks an imported row that was not imported
+	BookImportStatusRejected = "rejected"
+)
+
+// BookPayload holds book payload representative
+type BookPayload struct {
+	Isbn     string `json:"isbn"`
+	Title    string `json:"title"`
+	Price    int    `json:"price"`
+	Category string `json:"category"`
+}
+
+// Validate is func to validate book payload
+func (b *BookPayload) Validate() error {
+	if !isbn.IsValid(b.Isbn) {
+		return response.ErrInvalidIsbn
+	}
+
+	if len(strings.TrimSpace(b.Title)) == 0 {
+		return response.ErrInvalidTitle
+	}
+
+	if b.Price <= 0 {
+		return response.ErrInvalidPrice
+	}
+
+	return nil
+}
+
+// BookImportRowResult holds the outcome of a single imported row
+type BookImportRowResult struct {
+	Row    int    `json:"row"`
+	Isbn   string `json:"isbn"`
+	BookID int    `json:"book_id,omitempty"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// BookImportReport holds the summary and per-row outcome of a catalog import
+type BookImportReport struct {
+	Created  int                    `json:"created"`
+	Updated  int                    `json:"updated"`
+	Rejected int                    `json:"rejected"`
+	Rows     []*BookImportRowResult `json:"rows"`
+}
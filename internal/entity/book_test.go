@@ -0,0 +1,47 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookPayloadValidate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		payload *entity.BookPayload
+		wantErr bool
+	}{
+		{
+			name:    "invalid isbn",
+			payload: &entity.BookPayload{Isbn: "978-0-545-01022-10"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid title",
+			payload: &entity.BookPayload{Isbn: "978-0-545-01022-1", Title: " "},
+			wantErr: true,
+		},
+		{
+			name:    "invalid price",
+			payload: &entity.BookPayload{Isbn: "978-0-545-01022-1", Title: "Harry Potter"},
+			wantErr: true,
+		},
+		{
+			name:    "success",
+			payload: &entity.BookPayload{Isbn: "978-0-545-01022-1", Title: "Harry Potter", Price: 25000},
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		assert.Equal(t, tc.wantErr, tc.payload.Validate() != nil)
+	}
+}
+
+func TestIsValidBookSort(t *testing.T) {
+	assert.True(t, entity.IsValidBookSort(""))
+	assert.True(t, entity.IsValidBookSort(entity.BookSortRating))
+	assert.False(t, entity.IsValidBookSort("price"))
+}
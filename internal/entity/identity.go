@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// UserIdentity struct holds entity of an account at an OpenID Connect provider linked to a user
+type UserIdentity struct {
+	ID     int
+	UserID int
+	// Provider is the issuer URL of the provider
+	Provider string
+	// Subject is the ID of the account at the provider
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
+
+// OIDCAuthorization holds the URL of the provider the user is sent to for signing in
+type OIDCAuthorization struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// OIDCCallbackPayload holds the OpenID Connect callback payload representative, as given by the provider on the redirect
+type OIDCCallbackPayload struct {
+	State string `json:"state"`
+	Code  string `json:"code"`
+}
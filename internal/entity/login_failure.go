@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/config"
+)
+
+// LoginFailure struct holds entity of failed login, it is kept for unknown emails too
+type LoginFailure struct {
+	ID        int
+	Email     string
+	IPAddress string
+	CreatedAt time.Time
+}
+
+// LoginFailureSummary holds the number of failed logins of an email or an IP address and the time of the last one
+type LoginFailureSummary struct {
+	Count        int
+	LastFailedAt *time.Time
+}
+
+// LockedUntil returns until when the logins are refused once the failures reach the limit, the zero time when they are not.
+// The lockout lasts LoginLockoutDuration at the limit and doubles with every further failure up to LoginMaxLockoutDuration
+func (s *LoginFailureSummary) LockedUntil(limit int) time.Time {
+	if s.Count < limit || s.LastFailedAt == nil {
+		return time.Time{}
+	}
+
+	lockout := config.LoginLockoutDuration
+	for i := limit; i < s.Count && lockout < config.LoginMaxLockoutDuration; i++ {
+		lockout *= 2
+	}
+	if lockout > config.LoginMaxLockoutDuration {
+		lockout = config.LoginMaxLockoutDuration
+	}
+
+	return s.LastFailedAt.Add(lockout)
+}
@@ -0,0 +1,51 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginFailureSummaryLockedUntil(t *testing.T) {
+	lastFailedAt := time.Now()
+
+	testcases := []struct {
+		name    string
+		summary *entity.LoginFailureSummary
+		want    time.Time
+	}{
+		{
+			name:    "no failure",
+			summary: &entity.LoginFailureSummary{},
+			want:    time.Time{},
+		},
+		{
+			name:    "below the limit",
+			summary: &entity.LoginFailureSummary{Count: 4, LastFailedAt: &lastFailedAt},
+			want:    time.Time{},
+		},
+		{
+			name:    "at the limit",
+			summary: &entity.LoginFailureSummary{Count: 5, LastFailedAt: &lastFailedAt},
+			want:    lastFailedAt.Add(time.Minute),
+		},
+		{
+			name:    "above the limit",
+			summary: &entity.LoginFailureSummary{Count: 7, LastFailedAt: &lastFailedAt},
+			want:    lastFailedAt.Add(4 * time.Minute),
+		},
+		{
+			name:    "capped",
+			summary: &entity.LoginFailureSummary{Count: 500, LastFailedAt: &lastFailedAt},
+			want:    lastFailedAt.Add(time.Hour),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.summary.LockedUntil(5))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/response"
+)
+
+// TOTPCredential struct holds entity of the TOTP secret of a user, it is enabled once the user confirmed a code of it
+type TOTPCredential struct {
+	UserID int
+	Secret string
+	// EnabledAt is nil while the enrolment is not confirmed
+	EnabledAt *time.Time
+	// LastUsedStep is the time step of the last accepted code, a code is accepted once
+	LastUsedStep int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// IsEnabled reports whether the login asks for a code of the TOTP secret
+func (c *TOTPCredential) IsEnabled() bool {
+	return c.EnabledAt != nil
+}
+
+// TOTPEnrollment holds the secret of a started TOTP enrolment, to be added to an authenticator app
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	// URI is the otpauth URI of the secret, usually shown as a QR code
+	URI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesResponse holds the recovery codes of a user, they are shown once and only their hashes are stored
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TOTPCodePayload holds confirm TOTP enrolment payload representative
+type TOTPCodePayload struct {
+	Code string `json:"code"`
+}
+
+// Validate is func to validate TOTP code payload
+func (p *TOTPCodePayload) Validate() error {
+	if p.Code == "" {
+		return response.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// DisableTOTPPayload holds disable TOTP payload representative
+type DisableTOTPPayload struct {
+	Password string `json:"password"`
+}
+
+// LoginChallengePayload holds the second step login payload representative, the code is a TOTP code or a recovery code
+type LoginChallengePayload struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+// Validate is func to validate login challenge payload
+func (p *LoginChallengePayload) Validate() error {
+	if p.Code == "" {
+		return response.ErrInvalidMFACode
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/satriowisnugroho/book-store/internal/response"
+)
+
+const (
+	// ReadingListMaxNameLen is the maximum number of characters of a reading list name
+	ReadingListMaxNameLen = 100
+	// WishlistName is the name of the wishlist every user gets on first use
+	WishlistName = "Wishlist"
+)
+
+// ReadingList struct holds entity of a reading list, the wishlist of a user is a private reading list
+type ReadingList struct {
+	ID         int       `json:"id"`
+	UserID     int       `json:"user_id"`
+	Name       string    `json:"name"`
+	IsWishlist bool      `json:"is_wishlist"`
+	IsPublic   bool      `json:"is_public"`
+	ShareSlug  string    `json:"share_slug,omitempty"`
+	Books      []*Book   `json:"books"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// OrderPayload builds an order payload holding one copy of every book of the list
+func (r *ReadingList) OrderPayload() *OrderPayload {
+	payload := &OrderPayload{OrderItems: []OrderItemPayload{}}
+	for _, book := range r.Books {
+		payload.OrderItems = append(payload.OrderItems, OrderItemPayload{BookID: book.ID, Quantity: 1})
+	}
+
+	return payload
+}
+
+// ReadingListPayload holds reading list payload representative
+type ReadingListPayload struct {
+	Name     string `json:"name"`
+	IsPublic bool   `json:"is_public"`
+}
+
+// Validate is func to validate reading list payload
+func (r *ReadingListPayload) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" || utf8.RuneCountInString(r.Name) > ReadingListMaxNameLen {
+		return response.ErrInvalidReadingListName
+	}
+
+	return nil
+}
+
+// ReadingListBookPayload holds the payload to add a book to a reading list
+type ReadingListBookPayload struct {
+	BookID int `json:"book_id"`
+}
@@ -0,0 +1,52 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadingListPayloadValidate(t *testing.T) {
+	testcases := []struct {
+		name         string
+		payload      entity.ReadingListPayload
+		expectedName string
+		wantErr      error
+	}{
+		{
+			name:    "empty name",
+			payload: entity.ReadingListPayload{Name: "   "},
+			wantErr: response.ErrInvalidReadingListName,
+		},
+		{
+			name:    "name too long",
+			payload: entity.ReadingListPayload{Name: strings.Repeat("a", entity.ReadingListMaxNameLen+1)},
+			wantErr: response.ErrInvalidReadingListName,
+		},
+		{
+			name:         "success",
+			payload:      entity.ReadingListPayload{Name: "  Summer reads  ", IsPublic: true},
+			expectedName: "Summer reads",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.payload.Validate()
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr == nil {
+				assert.Equal(t, tc.expectedName, tc.payload.Name)
+			}
+		})
+	}
+}
+
+func TestReadingListOrderPayload(t *testing.T) {
+	readingList := &entity.ReadingList{Books: []*entity.Book{{ID: 1}, {ID: 2}}}
+
+	assert.Equal(t, &entity.OrderPayload{OrderItems: []entity.OrderItemPayload{{BookID: 1, Quantity: 1}, {BookID: 2, Quantity: 1}}}, readingList.OrderPayload())
+	assert.Empty(t, (&entity.ReadingList{}).OrderPayload().OrderItems)
+}
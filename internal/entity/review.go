@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/satriowisnugroho/book-store/internal/response"
+)
+
+const (
+	// ReviewMinRating is the lowest star rating of a review
+	ReviewMinRating = 1
+	// ReviewMaxRating is the highest star rating of a review
+	ReviewMaxRating = 5
+	// ReviewMaxBodyLen is the maximum number of characters of a review text
+	ReviewMaxBodyLen = 5000
+	// ReviewReportMaxReasonLen is the maximum number of characters of an abuse report reason
+	ReviewReportMaxReasonLen = 1000
+	// ReviewModerationMaxBatch is the maximum number of reviews moderated at once
+	ReviewModerationMaxBatch = 100
+
+	// ReviewStatusPending marks a review held for moderation, it is not published
+	ReviewStatusPending = "pending"
+	// ReviewStatusApproved marks a published review
+	ReviewStatusApproved = "approved"
+	// ReviewStatusRejected marks a review rejected by a moderator
+	ReviewStatusRejected = "rejected"
+)
+
+// Review struct holds entity of review
+type Review struct {
+	ID               int       `json:"id"`
+	BookID           int       `json:"book_id"`
+	UserID           int       `json:"user_id"`
+	Rating           int       `json:"rating"`
+	Body             string    `json:"body"`
+	VerifiedPurchase bool      `json:"verified_purchase"`
+	Status           string    `json:"status"`
+	ReportCount      int       `json:"report_count"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// ReviewPayload holds review payload representative
+type ReviewPayload struct {
+	Rating int    `json:"rating"`
+	Body   string `json:"body"`
+}
+
+// Validate is func to validate review payload
+func (r *ReviewPayload) Validate() error {
+	if r.Rating < ReviewMinRating || r.Rating > ReviewMaxRating {
+		return response.ErrInvalidRating
+	}
+
+	r.Body = strings.TrimSpace(r.Body)
+	if utf8.RuneCountInString(r.Body) > ReviewMaxBodyLen {
+		return response.ErrInvalidReviewBody
+	}
+
+	return nil
+}
+
+// IsValidReviewStatus reports whether the status is a known review status
+func IsValidReviewStatus(status string) bool {
+	switch status {
+	case ReviewStatusPending, ReviewStatusApproved, ReviewStatusRejected:
+		return true
+	}
+
+	return false
+}
+
+// ReviewReport struct holds entity of an abuse report on a review
+type ReviewReport struct {
+	ID        int       `json:"id"`
+	ReviewID  int       `json:"review_id"`
+	UserID    int       `json:"user_id"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReviewReportPayload holds review report payload representative
+type ReviewReportPayload struct {
+	Reason string `json:"reason"`
+}
+
+// Validate is func to validate review report payload
+func (r *ReviewReportPayload) Validate() error {
+	r.Reason = strings.TrimSpace(r.Reason)
+	if utf8.RuneCountInString(r.Reason) > ReviewReportMaxReasonLen {
+		return response.ErrInvalidReportReason
+	}
+
+	return nil
+}
+
+// ModerateReviewsPayload holds the payload to approve or reject reviews in bulk
+type ModerateReviewsPayload struct {
+	ReviewIDs []int  `json:"review_ids"`
+	Status    string `json:"status"`
+}
+
+// Validate is func to validate moderate reviews payload, reviews can only be approved or rejected
+func (m *ModerateReviewsPayload) Validate() error {
+	if m.Status != ReviewStatusApproved && m.Status != ReviewStatusRejected {
+		return response.ErrInvalidReviewStatus
+	}
+
+	if len(m.ReviewIDs) == 0 || len(m.ReviewIDs) > ReviewModerationMaxBatch {
+		return response.ErrInvalidReviewIDs
+	}
+
+	return nil
+}
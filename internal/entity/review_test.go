@@ -0,0 +1,95 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewPayloadValidate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		payload entity.ReviewPayload
+		wantErr error
+	}{
+		{
+			name:    "rating too low",
+			payload: entity.ReviewPayload{Rating: 0},
+			wantErr: response.ErrInvalidRating,
+		},
+		{
+			name:    "rating too high",
+			payload: entity.ReviewPayload{Rating: 6},
+			wantErr: response.ErrInvalidRating,
+		},
+		{
+			name:    "body too long",
+			payload: entity.ReviewPayload{Rating: 5, Body: strings.Repeat("a", entity.ReviewMaxBodyLen+1)},
+			wantErr: response.ErrInvalidReviewBody,
+		},
+		{
+			name:    "rating without body",
+			payload: entity.ReviewPayload{Rating: 1},
+		},
+		{
+			name:    "success",
+			payload: entity.ReviewPayload{Rating: 5, Body: "  A magical story  "},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.payload.Validate()
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestIsValidReviewStatus(t *testing.T) {
+	assert.True(t, entity.IsValidReviewStatus(entity.ReviewStatusPending))
+	assert.True(t, entity.IsValidReviewStatus(entity.ReviewStatusRejected))
+	assert.False(t, entity.IsValidReviewStatus("deleted"))
+}
+
+func TestReviewReportPayloadValidate(t *testing.T) {
+	assert.Nil(t, (&entity.ReviewReportPayload{}).Validate())
+	assert.Nil(t, (&entity.ReviewReportPayload{Reason: "Spam"}).Validate())
+	assert.Equal(t, response.ErrInvalidReportReason, (&entity.ReviewReportPayload{Reason: strings.Repeat("a", entity.ReviewReportMaxReasonLen+1)}).Validate())
+}
+
+func TestModerateReviewsPayloadValidate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		payload entity.ModerateReviewsPayload
+		wantErr error
+	}{
+		{
+			name:    "invalid status",
+			payload: entity.ModerateReviewsPayload{ReviewIDs: []int{1}, Status: entity.ReviewStatusPending},
+			wantErr: response.ErrInvalidReviewStatus,
+		},
+		{
+			name:    "no review ids",
+			payload: entity.ModerateReviewsPayload{Status: entity.ReviewStatusApproved},
+			wantErr: response.ErrInvalidReviewIDs,
+		},
+		{
+			name:    "too many review ids",
+			payload: entity.ModerateReviewsPayload{ReviewIDs: make([]int, entity.ReviewModerationMaxBatch+1), Status: entity.ReviewStatusApproved},
+			wantErr: response.ErrInvalidReviewIDs,
+		},
+		{
+			name:    "success",
+			payload: entity.ModerateReviewsPayload{ReviewIDs: []int{1, 2}, Status: entity.ReviewStatusRejected},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantErr, tc.payload.Validate())
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// UnknownDevice is the device of a session whose user agent isn't recognised
+const UnknownDevice = "Unknown device"
+
+// Session struct holds entity of a login of a user, it lasts as long as the refresh tokens rotated from the login
+type Session struct {
+	ID     int `json:"id"`
+	UserID int `json:"-"`
+	// FamilyID is the family of the refresh tokens of the session
+	FamilyID  string `json:"-"`
+	Device    string `json:"device"`
+	UserAgent string `json:"user_agent"`
+	IPAddress string `json:"ip_address"`
+	// Current is whether the session is the one of the request
+	Current   bool       `json:"current"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"-"`
+	// LastSeenAt is when the session last logged in or refreshed its access token
+	LastSeenAt time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// userAgentBrowsers are the browsers told apart by DeviceFromUserAgent, in the order they are looked for.
+// Most browsers send the tokens of the browsers they are built on too, so Edge and Opera come before Chrome before Safari
+var userAgentBrowsers = []struct{ token, name string }{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"curl/", "curl"},
+	{"PostmanRuntime/", "Postman"},
+	{"okhttp/", "OkHttp"},
+}
+
+// userAgentPlatforms are the operating systems told apart by DeviceFromUserAgent, in the order they are looked for
+var userAgentPlatforms = []struct{ token, name string }{
+	{"iPhone", "iPhone"},
+	{"iPad", "iPad"},
+	{"Android", "Android"},
+	{"Windows", "Windows"},
+	{"Mac OS X", "macOS"},
+	{"CrOS", "ChromeOS"},
+	{"Linux", "Linux"},
+}
+
+// DeviceFromUserAgent returns a readable name of the browser and operating system of a user agent, such as "Firefox on Windows"
+func DeviceFromUserAgent(userAgent string) string {
+	browser := ""
+	for _, b := range userAgentBrowsers {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	platform := ""
+	for _, p := range userAgentPlatforms {
+		if strings.Contains(userAgent, p.token) {
+			platform = p.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	case platform != "":
+		return platform
+	}
+
+	return UnknownDevice
+}
@@ -0,0 +1,54 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceFromUserAgent(t *testing.T) {
+	testcases := []struct {
+		userAgent string
+		expected  string
+	}{
+		{
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+			expected:  "Chrome on Windows",
+		},
+		{
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36 Edg/126.0.0.0",
+			expected:  "Edge on Windows",
+		},
+		{
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Safari/605.1.15",
+			expected:  "Safari on macOS",
+		},
+		{
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1",
+			expected:  "Safari on iPhone",
+		},
+		{
+			userAgent: "Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Mobile Safari/537.36",
+			expected:  "Chrome on Android",
+		},
+		{
+			userAgent: "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0",
+			expected:  "Firefox on Linux",
+		},
+		{
+			userAgent: "curl/8.7.1",
+			expected:  "curl",
+		},
+		{
+			userAgent: "",
+			expected:  entity.UnknownDevice,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, entity.DeviceFromUserAgent(tc.userAgent))
+		})
+	}
+}
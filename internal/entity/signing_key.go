@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// SigningKey struct holds entity of JWT signing key
+type SigningKey struct {
+	ID int
+	// KeyID is the kid header of the tokens signed by the key
+	KeyID     string
+	Algorithm string
+	// PrivateKey is the PKCS #8 PEM encoded private key
+	PrivateKey string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/internal/response"
+)
+
+// RefreshToken struct holds entity of refresh token, only the hash of the token is stored
+type RefreshToken struct {
+	ID     int
+	UserID int
+	// FamilyID is shared by every token rotated from the same login, so a reused token can revoke them all
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// IsExpired reports whether the refresh token can no longer be used
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// RefreshTokenPayload holds refresh and logout payload representative
+type RefreshTokenPayload struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// PasswordResetToken struct holds entity of password reset token, only the hash of the token is stored
+type PasswordResetToken struct {
+	ID        int
+	UserID    int
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// IsUsable reports whether the password reset token can still reset the password
+func (t *PasswordResetToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// EmailVerificationToken struct holds entity of email verification token, only the hash of the token is stored
+type EmailVerificationToken struct {
+	ID     int
+	UserID int
+	// NewEmail is the email the user is changing to, which replaces the email of the user once verified.
+	// The token verifies the current email of the user when it is nil
+	NewEmail  *string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// IsUsable reports whether the email verification token can still verify the email
+func (t *EmailVerificationToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// LoginChallenge struct holds entity of the challenge of a password login which still needs a second factor,
+// only the hash of the challenge token is stored
+type LoginChallenge struct {
+	ID        int
+	UserID    int
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// IsUsable reports whether the login challenge can still be exchanged for an access token
+func (t *LoginChallenge) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// OIDCLoginState struct holds entity of a login started at the OpenID Connect provider, only the hash of the state is stored.
+// The nonce and the PKCE code verifier are kept for the callback
+type OIDCLoginState struct {
+	ID           int
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	UsedAt       *time.Time
+	CreatedAt    time.Time
+}
+
+// IsUsable reports whether the login state can still complete a login
+func (t *OIDCLoginState) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// ForgotPasswordPayload holds forgot password payload representative
+type ForgotPasswordPayload struct {
+	Email string `json:"email"`
+}
+
+// Validate is func to validate forgot password payload, the email is normalized
+func (p *ForgotPasswordPayload) Validate() error {
+	p.Email = NormalizeEmail(p.Email)
+	if !emailRegex.MatchString(p.Email) {
+		return response.ErrInvalidEmail
+	}
+
+	return nil
+}
+
+// ResetPasswordPayload holds reset password payload representative
+type ResetPasswordPayload struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// Validate is func to validate reset password payload
+func (p *ResetPasswordPayload) Validate() error {
+	if p.Token == "" {
+		return response.ErrInvalidPasswordResetToken
+	}
+
+	if len(p.Password) < config.MinPasswordLen {
+		return response.ErrInvalidPasswordLength
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordResetTokenIsUsable(t *testing.T) {
+	now := time.Now()
+
+	assert.True(t, (&entity.PasswordResetToken{ExpiresAt: now.Add(time.Minute)}).IsUsable(now))
+	assert.False(t, (&entity.PasswordResetToken{ExpiresAt: now}).IsUsable(now))
+	assert.False(t, (&entity.PasswordResetToken{ExpiresAt: now.Add(time.Minute), UsedAt: &now}).IsUsable(now))
+}
+
+func TestEmailVerificationTokenIsUsable(t *testing.T) {
+	now := time.Now()
+
+	assert.True(t, (&entity.EmailVerificationToken{ExpiresAt: now.Add(time.Minute)}).IsUsable(now))
+	assert.False(t, (&entity.EmailVerificationToken{ExpiresAt: now}).IsUsable(now))
+	assert.False(t, (&entity.EmailVerificationToken{ExpiresAt: now.Add(time.Minute), UsedAt: &now}).IsUsable(now))
+}
+
+func TestForgotPasswordPayloadValidate(t *testing.T) {
+	assert.Equal(t, response.ErrInvalidEmail, (&entity.ForgotPasswordPayload{Email: "foo"}).Validate())
+	assert.Nil(t, (&entity.ForgotPasswordPayload{Email: "foo@bar.com"}).Validate())
+
+	payload := &entity.ForgotPasswordPayload{Email: " Foo@Bar.com "}
+	assert.Nil(t, payload.Validate())
+	assert.Equal(t, "foo@bar.com", payload.Email)
+}
+
+func TestResetPasswordPayloadValidate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		payload *entity.ResetPasswordPayload
+		wantErr error
+	}{
+		{
+			name:    "empty token",
+			payload: &entity.ResetPasswordPayload{Password: "12345"},
+			wantErr: response.ErrInvalidPasswordResetToken,
+		},
+		{
+			name:    "invalid password length",
+			payload: &entity.ResetPasswordPayload{Token: "token", Password: "123"},
+			wantErr: response.ErrInvalidPasswordLength,
+		},
+		{
+			name:    "success",
+			payload: &entity.ResetPasswordPayload{Token: "token", Password: "12345"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantErr, tc.payload.Validate())
+		})
+	}
+}
@@ -2,6 +2,7 @@ package entity
 
 import (
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/satriowisnugroho/book-store/internal/config"
@@ -10,14 +11,41 @@ import (
 
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 
+const (
+	// UserRoleCustomer is the role of a regular customer
+	UserRoleCustomer = "customer"
+	// UserRoleAdmin is the role of a catalog or store administrator
+	UserRoleAdmin = "admin"
+
+	// ErasedUserEmailFormat formats the email an erased user is left with given the user ID,
+	// the .invalid domain never receives mail
+	ErasedUserEmailFormat = "erased-%d@erased.invalid"
+	// ErasedUserFullname is the name an erased user is left with
+	ErasedUserFullname = "Erased user"
+)
+
 // User struct holds entity of user
 type User struct {
-	ID              int       `json:"id"`
-	Email           string    `json:"email"`
-	Fullname        string    `json:"fullname"`
-	CryptedPassword string    `json:"-"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              int        `json:"id"`
+	Email           string     `json:"email"`
+	Fullname        string     `json:"fullname"`
+	CryptedPassword string     `json:"-"`
+	Role            string     `json:"role"`
+	VerifiedAt      *time.Time `json:"verified_at"`
+	DeletedAt       *time.Time `json:"-"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// NormalizeEmail trims and lowercases the email, emails are stored and looked up normalized
+// so an email matches whatever case it is typed in
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// IsVerified reports whether the user has verified the email
+func (u *User) IsVerified() bool {
+	return u.VerifiedAt != nil
 }
 
 // RegisterPayload holds register payload representative
@@ -27,8 +55,9 @@ type RegisterPayload struct {
 	Password string `json:"password"`
 }
 
-// Validate is func to validate register payload
+// Validate is func to validate register payload, the email is normalized
 func (u *RegisterPayload) Validate() error {
+	u.Email = NormalizeEmail(u.Email)
 	if !emailRegex.MatchString(u.Email) {
 		return response.ErrInvalidEmail
 	}
@@ -44,13 +73,59 @@ func (u *RegisterPayload) Validate() error {
 	return nil
 }
 
+// UpdateProfilePayload holds update profile payload representative, only the given fields are changed
+type UpdateProfilePayload struct {
+	Email    *string `json:"email"`
+	Fullname *string `json:"fullname"`
+	// CurrentPassword is required to change the email
+	CurrentPassword string `json:"current_password"`
+}
+
+// Validate is func to validate update profile payload, the email is normalized
+func (u *UpdateProfilePayload) Validate() error {
+	if u.Email != nil {
+		email := NormalizeEmail(*u.Email)
+		u.Email = &email
+		if !emailRegex.MatchString(email) {
+			return response.ErrInvalidEmail
+		}
+	}
+
+	if u.Fullname != nil && len(*u.Fullname) == 0 {
+		return response.ErrInvalidFullname
+	}
+
+	return nil
+}
+
+// ChangePasswordPayload holds change password payload representative
+type ChangePasswordPayload struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// Validate is func to validate change password payload
+func (u *ChangePasswordPayload) Validate() error {
+	if len(u.NewPassword) < config.MinPasswordLen {
+		return response.ErrInvalidPasswordLength
+	}
+
+	return nil
+}
+
 // LoginPayload holds login payload representative
 type LoginPayload struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
 
-// LoginResponse holds login response
+// LoginResponse holds login response. A user with two-factor authentication gets a challenge token instead of the tokens,
+// which is exchanged for them together with a code
 type LoginResponse struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// ExpiresIn is the lifetime of the access token in seconds
+	ExpiresIn      int    `json:"expires_in,omitempty"`
+	MFARequired    bool   `json:"mfa_required,omitempty"`
+	ChallengeToken string `json:"challenge_token,omitempty"`
 }
@@ -51,4 +51,64 @@ func TestRegisterPayloadValidate(t *testing.T) {
 	for _, tc := range testcases {
 		assert.Equal(t, tc.wantErr, tc.payload.Validate() != nil)
 	}
+
+	payload := &entity.RegisterPayload{Email: " Foo@Bar.com ", Fullname: "Foo Bar", Password: "12345"}
+	assert.Nil(t, payload.Validate())
+	assert.Equal(t, "foo@bar.com", payload.Email)
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	assert.Equal(t, "foo@bar.com", entity.NormalizeEmail(" Foo@Bar.COM\n"))
+	assert.Equal(t, "foo@bar.com", entity.NormalizeEmail("foo@bar.com"))
+}
+
+func TestUpdateProfilePayloadValidate(t *testing.T) {
+	invalidEmail := "foo"
+	validEmail := "foo@bar.com"
+	emptyFullname := ""
+	validFullname := "Foo Bar"
+
+	testcases := []struct {
+		name    string
+		payload *entity.UpdateProfilePayload
+		wantErr bool
+	}{
+		{
+			name:    "invalid email",
+			payload: &entity.UpdateProfilePayload{Email: &invalidEmail},
+			wantErr: true,
+		},
+		{
+			name:    "invalid fullname",
+			payload: &entity.UpdateProfilePayload{Fullname: &emptyFullname},
+			wantErr: true,
+		},
+		{
+			name:    "no changes",
+			payload: &entity.UpdateProfilePayload{},
+			wantErr: false,
+		},
+		{
+			name:    "success",
+			payload: &entity.UpdateProfilePayload{Email: &validEmail, Fullname: &validFullname},
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantErr, tc.payload.Validate() != nil)
+		})
+	}
+
+	mixedCaseEmail := " Foo@Bar.com "
+	payload := &entity.UpdateProfilePayload{Email: &mixedCaseEmail}
+	assert.Nil(t, payload.Validate())
+	assert.Equal(t, "foo@bar.com", *payload.Email)
+	assert.Equal(t, " Foo@Bar.com ", mixedCaseEmail)
+}
+
+func TestChangePasswordPayloadValidate(t *testing.T) {
+	assert.NotNil(t, (&entity.ChangePasswordPayload{CurrentPassword: "12345", NewPassword: "123"}).Validate())
+	assert.Nil(t, (&entity.ChangePasswordPayload{CurrentPassword: "12345", NewPassword: "54321"}).Validate())
 }
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+)
+
+// AccessLogMiddleware logs every request once it is served with the fields of its context, its status and latency.
+// It must run after RequestInfoMiddleware, client errors are logged at warn level and server errors at error level
+func AccessLogMiddleware(l logger.LoggerInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		rl := l.WithContext(c.Request.Context()).With(
+			logger.String("method", c.Request.Method),
+			logger.String("path", c.Request.URL.Path),
+			logger.Int("status", status),
+			logger.Duration("latency", time.Since(start)),
+			logger.String("ip", c.ClientIP()),
+		)
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			rl.Error("http - request")
+		case status >= http.StatusBadRequest:
+			rl.Warn("http - request")
+		default:
+			rl.Info("http - request")
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/handler/http/middleware"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAccessLogMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		expectedLevel string
+	}{
+		{
+			name:          "success",
+			status:        http.StatusOK,
+			expectedLevel: "Info",
+		},
+		{
+			name:          "client error",
+			status:        http.StatusNotFound,
+			expectedLevel: "Warn",
+		},
+		{
+			name:          "server error",
+			status:        http.StatusInternalServerError,
+			expectedLevel: "Error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &testmock.LoggerInterface{}
+			l.On("WithContext", mock.Anything).Return(l)
+			l.On("With", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(l)
+			l.On(tt.expectedLevel, "http - request")
+
+			r := gin.New()
+			r.Use(middleware.RequestInfoMiddleware(), middleware.AccessLogMiddleware(l))
+			r.GET("/books/:isbn", func(c *gin.Context) {
+				c.Status(tt.status)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/books/9780132350884", nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			l.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	"github.com/satriowisnugroho/book-store/internal/response"
+)
+
+// AdminMiddleware only lets through users with the admin role, it must run after AuthMiddleware
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if helper.GetUserRoleFromContext(c) != entity.UserRoleAdmin {
+			response.Error(c, response.ErrForbidden)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,54 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/handler/http/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		role           interface{}
+		expectedStatus int
+	}{
+		{
+			name:           "no role",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "customer role",
+			role:           entity.UserRoleCustomer,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "admin role",
+			role:           entity.UserRoleAdmin,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.Default()
+			r.Use(func(c *gin.Context) {
+				c.Set("role", tt.role)
+			})
+			r.Use(middleware.AdminMiddleware())
+			r.GET("/admin", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "Authorized!"})
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/admin", nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
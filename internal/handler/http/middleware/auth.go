@@ -1,15 +1,37 @@
 package middleware
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
 	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/internal/entity"
 	"github.com/satriowisnugroho/book-store/internal/response"
 )
 
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// TokenParser verifies the signature and claims of an access token
+type TokenParser interface {
+	ParseAccessToken(tokenString string) (jwt.MapClaims, error)
+}
+
+// TokenDenylist reports whether an access token or its session was revoked before it expired
+type TokenDenylist interface {
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	IsSessionRevoked(ctx context.Context, sessionID int) (bool, error)
+}
+
+// APIKeyAuthenticator returns the API key and its user given the key
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*entity.APIKey, *entity.User, error)
+}
+
+// AuthMiddleware authenticates the request with a Bearer access token or with an API key.
+// An API key is let through when it has one of the given scopes, an API key with admin:* is always let through.
+// API keys are refused when apiKeys is nil
+func AuthMiddleware(parser TokenParser, denylist TokenDenylist, apiKeys APIKeyAuthenticator, scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader(config.AuthorizationHeader)
 		if authHeader == "" {
@@ -19,27 +41,110 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 		}
 
 		parts := strings.Split(authHeader, " ")
+		if len(parts) == 2 && parts[0] == config.AuthorizationHeaderAPIKey {
+			if apiKeys == nil {
+				response.Error(c, response.ErrUnauthorized("API keys are not accepted, use an access token"))
+				c.Abort()
+				return
+			}
+
+			authenticateAPIKey(c, apiKeys, parts[1], scopes)
+			return
+		}
+
 		if len(parts) != 2 || parts[0] != config.AuthorizationHeaderBearer {
 			response.Error(c, response.ErrUnauthorized("Invalid Authorization header format"))
 			c.Abort()
 			return
 		}
 
-		tokenString := parts[1]
-		claims := &jwt.MapClaims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
+		claims, err := parser.ParseAccessToken(parts[1])
+		if err != nil {
+			response.Error(c, response.ErrUnauthorized("Invalid token"))
+			c.Abort()
+			return
+		}
 
-		if err != nil || !token.Valid {
+		jti, _ := claims["jti"].(string)
+		if jti == "" {
 			response.Error(c, response.ErrUnauthorized("Invalid token"))
 			c.Abort()
 			return
 		}
 
-		userID, _ := (*claims)["user_id"].(float64)
+		revoked, err := denylist.IsAccessTokenRevoked(c.Request.Context(), jti)
+		if err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+
+		if revoked {
+			response.Error(c, response.ErrUnauthorized("Token has been revoked"))
+			c.Abort()
+			return
+		}
+
+		// Access tokens issued before sessions were tracked carry no session
+		sessionID, _ := claims["sid"].(float64)
+		if sessionID != 0 {
+			revoked, err := denylist.IsSessionRevoked(c.Request.Context(), int(sessionID))
+			if err != nil {
+				response.Error(c, err)
+				c.Abort()
+				return
+			}
+
+			if revoked {
+				response.Error(c, response.ErrUnauthorized("Session has been revoked"))
+				c.Abort()
+				return
+			}
+		}
+
+		userID, _ := claims["user_id"].(float64)
+		exp, _ := claims["exp"].(float64)
+		c.Set("jti", jti)
+		c.Set("token_expires_at", time.Unix(int64(exp), 0))
+		c.Set("session_id", int(sessionID))
 		c.Set("user_id", int(userID))
-		c.Set("email", (*claims)["email"])
+		c.Set("email", claims["email"])
+		c.Set("role", claims["role"])
+		setRequestActor(c, int(userID))
 		c.Next()
 	}
 }
+
+// SessionAuthMiddleware authenticates the request with a Bearer access token only, for the routes managing the login
+// session and the credentials of the user, which need the ID and expiry of the access token and its session
+func SessionAuthMiddleware(parser TokenParser, denylist TokenDenylist) gin.HandlerFunc {
+	return AuthMiddleware(parser, denylist, nil)
+}
+
+// authenticateAPIKey lets the request through with the user of the API key when the key has one of the scopes
+func authenticateAPIKey(c *gin.Context, apiKeys APIKeyAuthenticator, key string, scopes []string) {
+	apiKey, user, err := apiKeys.AuthenticateAPIKey(c.Request.Context(), key)
+	if err != nil {
+		response.Error(c, err)
+		c.Abort()
+		return
+	}
+
+	allowed := apiKey.HasScope(entity.APIKeyScopeAdmin)
+	for _, scope := range scopes {
+		allowed = allowed || apiKey.HasScope(scope)
+	}
+
+	if !allowed {
+		response.Error(c, response.ErrForbidden)
+		c.Abort()
+		return
+	}
+
+	c.Set("api_key_id", apiKey.ID)
+	c.Set("user_id", user.ID)
+	c.Set("email", user.Email)
+	c.Set("role", user.Role)
+	setRequestActor(c, user.ID)
+	c.Next()
+}
@@ -1,33 +1,65 @@
 package middleware_test
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
+	"github.com/satriowisnugroho/book-store/internal/entity"
 	"github.com/satriowisnugroho/book-store/internal/handler/http/middleware"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/pkg/auth"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
-func setupTestRouterAuthMiddleware(secret string) *gin.Engine {
+type keySetParser struct {
+	*auth.KeySet
+}
+
+func (p keySetParser) ParseAccessToken(tokenString string) (jwt.MapClaims, error) {
+	return p.Parse(tokenString)
+}
+
+func setupTestRouterAuthMiddleware(parser middleware.TokenParser, denylist middleware.TokenDenylist, apiKeys middleware.APIKeyAuthenticator, scopes ...string) *gin.Engine {
 	r := gin.Default()
-	r.Use(middleware.AuthMiddleware(secret))
+	r.Use(middleware.AuthMiddleware(parser, denylist, apiKeys, scopes...))
 	r.GET("/protected", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "Authorized!"})
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "Authorized!",
+			"actor_id":   helper.GetRequestInfo(c).ActorID,
+			"log_fields": logger.FieldsFromContext(c.Request.Context()),
+		})
 	})
 
 	return r
 }
 
 func TestAuthMiddleware(t *testing.T) {
-	secret := "secret"
-	router := setupTestRouterAuthMiddleware(secret)
+	key, _ := auth.GenerateSigningKey(auth.SigningAlgorithmEdDSA)
+	keySet := auth.NewKeySet("book-store", "book-store-api", time.Minute)
+	keySet.SetKeys([]*auth.SigningKey{key})
+
+	signToken := func(claims jwt.MapClaims) string {
+		claims["exp"] = time.Now().Add(time.Minute).Unix()
+		tokenString, _ := keySet.Sign(claims)
+		return "Bearer " + tokenString
+	}
 
 	tests := []struct {
 		name           string
 		token          string
+		revoked        bool
+		revokedErr     error
+		sessionRevoked bool
+		sessionErr     error
 		expectedStatus int
 	}{
 		{
@@ -46,18 +78,63 @@ func TestAuthMiddleware(t *testing.T) {
 			expectedStatus: http.StatusUnauthorized,
 		},
 		{
-			name: "valid token",
+			name: "token signed with a shared secret",
 			token: func() string {
-				token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"})
-				tokenString, _ := token.SignedString([]byte(secret))
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"jti": "abc"})
+				token.Header["kid"] = key.ID
+				tokenString, _ := token.SignedString([]byte("secret"))
 				return "Bearer " + tokenString
 			}(),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "token without id",
+			token:          signToken(jwt.MapClaims{"foo": "bar"}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "error checking denylist",
+			token:          signToken(jwt.MapClaims{"jti": "abc"}),
+			revokedErr:     errors.New("error checking denylist"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "revoked token",
+			token:          signToken(jwt.MapClaims{"jti": "abc"}),
+			revoked:        true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "error checking session",
+			token:          signToken(jwt.MapClaims{"jti": "abc", "sid": 7}),
+			sessionErr:     errors.New("error checking session"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "revoked session",
+			token:          signToken(jwt.MapClaims{"jti": "abc", "sid": 7}),
+			sessionRevoked: true,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "valid token",
+			token:          signToken(jwt.MapClaims{"jti": "abc"}),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "valid token of a session",
+			token:          signToken(jwt.MapClaims{"jti": "abc", "sid": 7}),
 			expectedStatus: http.StatusOK,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			denylist := &testmock.TokenDenylist{}
+			denylist.On("IsAccessTokenRevoked", mock.Anything, mock.Anything).Return(tt.revoked, tt.revokedErr)
+			denylist.On("IsSessionRevoked", mock.Anything, 7).Return(tt.sessionRevoked, tt.sessionErr)
+			router := setupTestRouterAuthMiddleware(keySetParser{keySet}, denylist, &testmock.APIKeyAuthenticator{})
+
 			w := httptest.NewRecorder()
 			req, _ := http.NewRequest("GET", "/protected", nil)
 			if tt.token != "" {
@@ -69,3 +146,119 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthMiddlewareAPIKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		token          string
+		scopes         []string
+		apiKey         *entity.APIKey
+		apiKeyErr      error
+		expectedStatus int
+	}{
+		{
+			name:           "invalid API key",
+			token:          "ApiKey bsk_invalid",
+			apiKeyErr:      response.ErrUnauthorized("Invalid API key"),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "error authenticating API key",
+			token:          "ApiKey bsk_key",
+			apiKeyErr:      errors.New("error authenticating API key"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "API key without the scope",
+			token:          "ApiKey bsk_key",
+			scopes:         []string{entity.APIKeyScopeWriteOrders},
+			apiKey:         &entity.APIKey{ID: 1, Scopes: []string{entity.APIKeyScopeReadCatalog}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "API key on a route without scope",
+			token:          "ApiKey bsk_key",
+			apiKey:         &entity.APIKey{ID: 1, Scopes: []string{entity.APIKeyScopeReadCatalog}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "API key with the scope",
+			token:          "ApiKey bsk_key",
+			scopes:         []string{entity.APIKeyScopeWriteOrders},
+			apiKey:         &entity.APIKey{ID: 1, Scopes: []string{entity.APIKeyScopeWriteOrders}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "API key with the admin scope",
+			token:          "ApiKey bsk_key",
+			apiKey:         &entity.APIKey{ID: 1, Scopes: []string{entity.APIKeyScopeAdmin}},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiKeys := &testmock.APIKeyAuthenticator{}
+			apiKeys.On("AuthenticateAPIKey", mock.Anything, mock.Anything).Return(tt.apiKey, &entity.User{ID: 123, Role: entity.UserRoleCustomer}, tt.apiKeyErr)
+			router := setupTestRouterAuthMiddleware(nil, nil, apiKeys, tt.scopes...)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/protected", nil)
+			req.Header.Set("Authorization", tt.token)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"actor_id":123`)
+				assert.Contains(t, w.Body.String(), `{"Key":"user_id","Value":123}`)
+			}
+		})
+	}
+}
+
+func TestSessionAuthMiddleware(t *testing.T) {
+	key, _ := auth.GenerateSigningKey(auth.SigningAlgorithmEdDSA)
+	keySet := auth.NewKeySet("book-store", "book-store-api", time.Minute)
+	keySet.SetKeys([]*auth.SigningKey{key})
+	tokenString, _ := keySet.Sign(jwt.MapClaims{"jti": "jti", "user_id": 123, "exp": time.Now().Add(time.Minute).Unix()})
+
+	denylist := &testmock.TokenDenylist{}
+	denylist.On("IsAccessTokenRevoked", mock.Anything, "jti").Return(false, nil)
+
+	r := gin.Default()
+	r.Use(middleware.SessionAuthMiddleware(keySetParser{keySet}, denylist))
+	r.GET("/protected", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"jti": c.GetString("jti")})
+	})
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedStatus int
+	}{
+		{
+			name:           "API key",
+			token:          "ApiKey bsk_key",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "access token",
+			token:          "Bearer " + tokenString,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/protected", nil)
+			req.Header.Set("Authorization", tt.token)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, w.Body.String(), `"jti":"jti"`)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/satriowisnugroho/book-store/internal/handler/http/middleware"
+	"github.com/satriowisnugroho/book-store/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		expectedRoute string
+		expectedCode  string
+	}{
+		{
+			name:          "route",
+			path:          "/metrics-test/books/9780132350884",
+			expectedRoute: "/metrics-test/books/:isbn",
+			expectedCode:  "200",
+		},
+		{
+			name:          "unknown path",
+			path:          "/metrics-test/unknown",
+			expectedRoute: "unmatched",
+			expectedCode:  "404",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(middleware.MetricsMiddleware())
+			r.GET("/metrics-test/books/:isbn", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			counter := metrics.HTTPRequestsTotal.WithLabelValues("GET", tt.expectedRoute, tt.expectedCode)
+			before := testutil.ToFloat64(counter)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
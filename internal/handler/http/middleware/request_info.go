@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+)
+
+// RequestInfoMiddleware puts the request ID and the client IP address in the context of the request and echoes
+// the request ID in the response. The X-Request-ID of the client is kept when it is well-formed, else a new ID is generated.
+// The request ID and the route are also added to the entries of the loggers given the context
+func RequestInfoMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(config.RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = generateRequestID()
+		}
+
+		c.Set("request_id", requestID)
+		c.Header(config.RequestIDHeader, requestID)
+		ctx := helper.WithRequestInfo(c.Request.Context(), helper.RequestInfo{
+			RequestID: requestID,
+			IPAddress: c.ClientIP(),
+		})
+		ctx = logger.ContextWithFields(ctx, logger.String("request_id", requestID), logger.String("route", c.FullPath()))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// setRequestActor records the authenticated user in the request info and the log fields of the context of the request
+func setRequestActor(c *gin.Context, userID int) {
+	info := helper.GetRequestInfo(c.Request.Context())
+	info.ActorID = userID
+	ctx := helper.WithRequestInfo(c.Request.Context(), info)
+	c.Request = c.Request.WithContext(logger.ContextWithFields(ctx, logger.Int("user_id", userID)))
+}
+
+// isValidRequestID reports whether a request ID given by the client is short and only holds printable ASCII without spaces
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > config.RequestIDMaxLen {
+		return false
+	}
+
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] <= ' ' || requestID[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// generateRequestID returns 16 random bytes as hex
+func generateRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
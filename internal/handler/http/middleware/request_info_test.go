@@ -0,0 +1,76 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/internal/handler/http/middleware"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestInfoMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestID     string
+		keepRequestID bool
+	}{
+		{
+			name:          "no request ID",
+			keepRequestID: false,
+		},
+		{
+			name:          "request ID of the client",
+			requestID:     "3f2c7a1e-client",
+			keepRequestID: true,
+		},
+		{
+			name:          "request ID with spaces",
+			requestID:     "not a request id",
+			keepRequestID: false,
+		},
+		{
+			name:          "request ID too long",
+			requestID:     strings.Repeat("a", config.RequestIDMaxLen+1),
+			keepRequestID: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info helper.RequestInfo
+			var fields []logger.Field
+			r := gin.Default()
+			r.Use(middleware.RequestInfoMiddleware())
+			r.GET("/books/:isbn", func(c *gin.Context) {
+				// Usecases are given the gin context itself
+				info = helper.GetRequestInfo(c)
+				fields = logger.FieldsFromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/books/9780132350884", nil)
+			req.RemoteAddr = "10.0.0.1:1234"
+			if tt.requestID != "" {
+				req.Header.Set(config.RequestIDHeader, tt.requestID)
+			}
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "10.0.0.1", info.IPAddress)
+			assert.Equal(t, w.Header().Get(config.RequestIDHeader), info.RequestID)
+			if tt.keepRequestID {
+				assert.Equal(t, tt.requestID, info.RequestID)
+			} else {
+				assert.Len(t, info.RequestID, 32)
+			}
+			assert.Equal(t, []logger.Field{logger.String("request_id", info.RequestID), logger.String("route", "/books/:isbn")}, fields)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/handler/http/middleware"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		traceparent    string
+		handlerErr     error
+		expectedStatus codes.Code
+	}{
+		{
+			name:           "new trace",
+			expectedStatus: codes.Unset,
+		},
+		{
+			name:           "trace of the client",
+			traceparent:    "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedStatus: codes.Unset,
+		},
+		{
+			name:           "server error",
+			handlerErr:     errors.New("error get book"),
+			expectedStatus: codes.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			provider := tracing.Install(sdktrace.NewSimpleSpanProcessor(exporter), "book-store-test", 1)
+			defer provider.Shutdown(context.Background())
+
+			r := gin.New()
+			r.Use(middleware.TracingMiddleware())
+			r.GET("/books/:isbn", func(c *gin.Context) {
+				if tt.handlerErr != nil {
+					response.Error(c, tt.handlerErr)
+
+					return
+				}
+
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/books/9780132350884", nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			r.ServeHTTP(w, req)
+
+			spans := exporter.GetSpans()
+			assert.Len(t, spans, 1)
+			assert.Equal(t, "GET /books/:isbn", spans[0].Name)
+			assert.Equal(t, tt.expectedStatus, spans[0].Status.Code)
+			if tt.traceparent != "" {
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+				assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+			}
+			if tt.handlerErr != nil {
+				assert.Len(t, spans[0].Events, 1)
+				assert.Contains(t, w.Body.String(), spans[0].SpanContext.TraceID().String())
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/handler/http/middleware"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+)
+
+type APIKeyHandler struct {
+	Logger        logger.LoggerInterface
+	APIKeyUsecase usecase.APIKeyUsecaseInterface
+}
+
+func newAPIKeyHandler(handler *gin.RouterGroup, l logger.LoggerInterface, authMiddleware gin.HandlerFunc, aku usecase.APIKeyUsecaseInterface) {
+	r := &APIKeyHandler{l, aku}
+
+	h := handler.Group("/users/me/api-keys")
+	h.Use(authMiddleware)
+	{
+		h.POST("/", r.CreateAPIKey)
+		h.GET("/", r.GetAPIKeys)
+		h.DELETE("/:id", r.RevokeAPIKey)
+	}
+
+	a := handler.Group("/admin/users/:id/api-keys")
+	a.Use(authMiddleware, middleware.AdminMiddleware())
+	{
+		a.POST("/", r.CreateUserAPIKey)
+		a.GET("/", r.GetUserAPIKeys)
+		a.DELETE("/:key_id", r.RevokeUserAPIKey)
+	}
+}
+
+// @Summary     Create API Key
+// @Description An API to create an API key of the logged in user, the key is shown once. Only an admin may create a key with the admin:* scope
+// @ID          create api key
+// @Tags  	    API Key
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Param       request		body		entity.APIKeyPayload		true		"payload"
+// @Success     200 {object} response.SuccessBody{data=entity.CreatedAPIKey,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/me/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	h.createAPIKey(c, helper.GetUserIDFromContext(c), "http - v1 - API key - CreateAPIKey")
+}
+
+// @Summary     Get API Keys
+// @Description An API to get the API keys of the logged in user including the revoked ones
+// @ID          get api keys
+// @Tags  	    API Key
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Success     200 {object} response.SuccessBody{data=[]entity.APIKey,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/me/api-keys [get]
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	h.getAPIKeys(c, helper.GetUserIDFromContext(c), "http - v1 - API key - GetAPIKeys")
+}
+
+// @Summary     Revoke API Key
+// @Description An API to revoke an API key of the logged in user
+// @ID          revoke api key
+// @Tags  	    API Key
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Param       id 				path		integer 	true		"API key ID"
+// @Success     200 {object} response.SuccessBody{meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/me/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	apiKeyID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	h.revokeAPIKey(c, helper.GetUserIDFromContext(c), apiKeyID, "http - v1 - API key - RevokeAPIKey")
+}
+
+// @Summary     Create User API Key
+// @Description An API for admins to create an API key on behalf of a user such as a service account, the key is shown once
+// @ID          create user api key
+// @Tags  	    API Key
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Param       id 				path		integer 							true		"user ID"
+// @Param       request		body		entity.APIKeyPayload		true		"payload"
+// @Success     200 {object} response.SuccessBody{data=entity.CreatedAPIKey,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /admin/users/{id}/api-keys [post]
+func (h *APIKeyHandler) CreateUserAPIKey(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	h.createAPIKey(c, userID, "http - v1 - API key - CreateUserAPIKey")
+}
+
+// @Summary     Get User API Keys
+// @Description An API for admins to get the API keys of a user including the revoked ones
+// @ID          get user api keys
+// @Tags  	    API Key
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Param       id 				path		integer 	true		"user ID"
+// @Success     200 {object} response.SuccessBody{data=[]entity.APIKey,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /admin/users/{id}/api-keys [get]
+func (h *APIKeyHandler) GetUserAPIKeys(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	h.getAPIKeys(c, userID, "http - v1 - API key - GetUserAPIKeys")
+}
+
+// @Summary     Revoke User API Key
+// @Description An API for admins to revoke an API key of a user
+// @ID          revoke user api key
+// @Tags  	    API Key
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Param       id 				path		integer 	true		"user ID"
+// @Param       key_id 		path		integer 	true		"API key ID"
+// @Success     200 {object} response.SuccessBody{meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /admin/users/{id}/api-keys/{key_id} [delete]
+func (h *APIKeyHandler) RevokeUserAPIKey(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	apiKeyID, err := strconv.Atoi(c.Param("key_id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	h.revokeAPIKey(c, userID, apiKeyID, "http - v1 - API key - RevokeUserAPIKey")
+}
+
+func (h *APIKeyHandler) createAPIKey(c *gin.Context, userID int, msg string) {
+	var payload entity.APIKeyPayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	apiKey, err := h.APIKeyUsecase.CreateAPIKey(c.Request.Context(), userID, &payload)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: CreateAPIKey", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, apiKey, "Successfully create an API key, it will not be shown again")
+}
+
+func (h *APIKeyHandler) getAPIKeys(c *gin.Context, userID int, msg string) {
+	apiKeys, err := h.APIKeyUsecase.GetAPIKeys(c.Request.Context(), userID)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: GetAPIKeys", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, apiKeys, "")
+}
+
+func (h *APIKeyHandler) revokeAPIKey(c *gin.Context, userID int, apiKeyID int, msg string) {
+	if err := h.APIKeyUsecase.RevokeAPIKey(c.Request.Context(), userID, apiKeyID); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: RevokeAPIKey", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, nil, "Successfully revoke an API key")
+}
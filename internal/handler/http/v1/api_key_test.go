@@ -0,0 +1,326 @@
+package v1_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	httpv1 "github.com/satriowisnugroho/book-store/internal/handler/http/v1"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	testcases := []struct {
+		name              string
+		body              string
+		uAPIKeyRes        *entity.CreatedAPIKey
+		uAPIKeyErr        error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to decode payload",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "admin scope for a customer",
+			body:              `{"name":"Catalog sync","scopes":["admin:*"]}`,
+			uAPIKeyErr:        response.ErrForbidden,
+			httpStatusCodeRes: http.StatusForbidden,
+		},
+		{
+			name:              "failed to create API key",
+			body:              `{"name":"Catalog sync","scopes":["read:catalog"]}`,
+			uAPIKeyErr:        errors.New("error create API key"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			body:              `{"name":"Catalog sync","scopes":["read:catalog"]}`,
+			uAPIKeyRes:        &entity.CreatedAPIKey{APIKey: &entity.APIKey{ID: 1}, Key: "bsk_key"},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/users/me/api-keys", strings.NewReader(tc.body))
+			ctx.Set("user_id", 123)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			apiKeyUsecase := &testmock.APIKeyUsecaseInterface{}
+			apiKeyUsecase.On("CreateAPIKey", mock.Anything, 123, mock.Anything).Return(tc.uAPIKeyRes, tc.uAPIKeyErr)
+
+			h := &httpv1.APIKeyHandler{l, apiKeyUsecase}
+			h.CreateAPIKey(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestGetAPIKeys(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uAPIKeyRes        []*entity.APIKey
+		uAPIKeyErr        error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to get API keys",
+			uAPIKeyErr:        errors.New("error get API keys"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			uAPIKeyRes:        []*entity.APIKey{{ID: 1}},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("GET", "/users/me/api-keys", nil)
+			ctx.Set("user_id", 123)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			apiKeyUsecase := &testmock.APIKeyUsecaseInterface{}
+			apiKeyUsecase.On("GetAPIKeys", mock.Anything, 123).Return(tc.uAPIKeyRes, tc.uAPIKeyErr)
+
+			h := &httpv1.APIKeyHandler{l, apiKeyUsecase}
+			h.GetAPIKeys(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	testcases := []struct {
+		name              string
+		apiKeyID          string
+		uAPIKeyErr        error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid API key id",
+			apiKeyID:          "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "API key not found",
+			apiKeyID:          "1",
+			uAPIKeyErr:        response.ErrNotFound,
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to revoke API key",
+			apiKeyID:          "1",
+			uAPIKeyErr:        errors.New("error revoke API key"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			apiKeyID:          "1",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("DELETE", "/users/me/api-keys/"+tc.apiKeyID, nil)
+			ctx.Params = gin.Params{{Key: "id", Value: tc.apiKeyID}}
+			ctx.Set("user_id", 123)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			apiKeyUsecase := &testmock.APIKeyUsecaseInterface{}
+			apiKeyUsecase.On("RevokeAPIKey", mock.Anything, 123, 1).Return(tc.uAPIKeyErr)
+
+			h := &httpv1.APIKeyHandler{l, apiKeyUsecase}
+			h.RevokeAPIKey(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestCreateUserAPIKey(t *testing.T) {
+	testcases := []struct {
+		name              string
+		userID            string
+		body              string
+		uAPIKeyRes        *entity.CreatedAPIKey
+		uAPIKeyErr        error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid user id",
+			userID:            "abc",
+			body:              `{"name":"Catalog sync","scopes":["read:catalog"]}`,
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "user not found",
+			userID:            "7",
+			body:              `{"name":"Catalog sync","scopes":["read:catalog"]}`,
+			uAPIKeyErr:        response.ErrNotFound,
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "success",
+			userID:            "7",
+			body:              `{"name":"Catalog sync","scopes":["read:catalog"]}`,
+			uAPIKeyRes:        &entity.CreatedAPIKey{APIKey: &entity.APIKey{ID: 1, UserID: 7}, Key: "bsk_key"},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/admin/users/"+tc.userID+"/api-keys", strings.NewReader(tc.body))
+			ctx.Params = gin.Params{{Key: "id", Value: tc.userID}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			apiKeyUsecase := &testmock.APIKeyUsecaseInterface{}
+			apiKeyUsecase.On("CreateAPIKey", mock.Anything, 7, mock.Anything).Return(tc.uAPIKeyRes, tc.uAPIKeyErr)
+
+			h := &httpv1.APIKeyHandler{l, apiKeyUsecase}
+			h.CreateUserAPIKey(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestGetUserAPIKeys(t *testing.T) {
+	testcases := []struct {
+		name              string
+		userID            string
+		uAPIKeyRes        []*entity.APIKey
+		uAPIKeyErr        error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid user id",
+			userID:            "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to get API keys",
+			userID:            "7",
+			uAPIKeyErr:        errors.New("error get API keys"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			userID:            "7",
+			uAPIKeyRes:        []*entity.APIKey{{ID: 1, UserID: 7}},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("GET", "/admin/users/"+tc.userID+"/api-keys", nil)
+			ctx.Params = gin.Params{{Key: "id", Value: tc.userID}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			apiKeyUsecase := &testmock.APIKeyUsecaseInterface{}
+			apiKeyUsecase.On("GetAPIKeys", mock.Anything, 7).Return(tc.uAPIKeyRes, tc.uAPIKeyErr)
+
+			h := &httpv1.APIKeyHandler{l, apiKeyUsecase}
+			h.GetUserAPIKeys(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestRevokeUserAPIKey(t *testing.T) {
+	testcases := []struct {
+		name              string
+		userID            string
+		apiKeyID          string
+		uAPIKeyErr        error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid user id",
+			userID:            "abc",
+			apiKeyID:          "1",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "invalid API key id",
+			userID:            "7",
+			apiKeyID:          "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to revoke API key",
+			userID:            "7",
+			apiKeyID:          "1",
+			uAPIKeyErr:        errors.New("error revoke API key"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			userID:            "7",
+			apiKeyID:          "1",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("DELETE", "/admin/users/"+tc.userID+"/api-keys/"+tc.apiKeyID, nil)
+			ctx.Params = gin.Params{{Key: "id", Value: tc.userID}, {Key: "key_id", Value: tc.apiKeyID}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			apiKeyUsecase := &testmock.APIKeyUsecaseInterface{}
+			apiKeyUsecase.On("RevokeAPIKey", mock.Anything, 7, 1).Return(tc.uAPIKeyErr)
+
+			h := &httpv1.APIKeyHandler{l, apiKeyUsecase}
+			h.RevokeUserAPIKey(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package v1
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/handler/http/middleware"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+)
+
+type AuditHandler struct {
+	Logger       logger.LoggerInterface
+	AuditUsecase usecase.AuditUsecaseInterface
+}
+
+func newAuditHandler(handler *gin.RouterGroup, l logger.LoggerInterface, authMiddleware gin.HandlerFunc, au usecase.AuditUsecaseInterface) {
+	r := &AuditHandler{l, au}
+
+	a := handler.Group("/admin/audit-events")
+	a.Use(authMiddleware, middleware.AdminMiddleware())
+	{
+		a.GET("/", r.GetAuditEvents)
+		a.GET("/verify", r.VerifyAuditEvents)
+	}
+}
+
+// @Summary     Show Audit Events
+// @Description An API to search the audit log of logins, password changes, orders, price changes and admin actions, newest first
+// @ID          audit event list
+// @Tags  	    Admin
+// @Accept      json
+// @Produce     json
+// @Param       action 				query		string 		false 	"action such as login.failed or order.created"
+// @Param       actor_id 			query		integer 	false 	"ID of the user who did the action"
+// @Param       target_type 	query		string 		false 	"user, order, book, review or api_key"
+// @Param       target_id 		query		string 		false 	"ID of the target, requires target_type to be meaningful"
+// @Param       from 					query		string 		false 	"RFC 3339 time, only events at or after it"
+// @Param       to 						query		string 		false 	"RFC 3339 time, only events before it"
+// @Param       offset 				query 	integer 	false		"offset"
+// @Param       limit 				query 	integer 	false 	"limit"
+// @Success     200 {object} response.SuccessBody{data=[]entity.AuditEvent,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /admin/audit-events [get]
+func (h *AuditHandler) GetAuditEvents(c *gin.Context) {
+	limit, offset := helper.GetLimitOffsetFromURLQuery(c)
+	actorID, _ := strconv.Atoi(c.Query("actor_id"))
+	payload := entity.GetAuditEventsPayload{
+		Action:     c.Query("action"),
+		ActorID:    actorID,
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+		Offset:     offset,
+		Limit:      limit,
+	}
+
+	var err error
+	if payload.From, err = parseTimeQuery(c, "from"); err != nil {
+		response.Error(c, response.ErrInvalidAuditTimeRange)
+
+		return
+	}
+
+	if payload.To, err = parseTimeQuery(c, "to"); err != nil {
+		response.Error(c, response.ErrInvalidAuditTimeRange)
+
+		return
+	}
+
+	events, count, err := h.AuditUsecase.GetAuditEvents(c.Request.Context(), payload)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - audit - GetAuditEvents: GetAuditEvents")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OKWithPagination(c, events, "", count, offset, limit)
+}
+
+// @Summary     Verify Audit Events
+// @Description An API to check the hash chain of the audit log, it reports the first event which was changed or follows deleted events
+// @ID          verify audit events
+// @Tags  	    Admin
+// @Accept      json
+// @Produce     json
+// @Success     200 {object} response.SuccessBody{data=entity.AuditVerification,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /admin/audit-events/verify [get]
+func (h *AuditHandler) VerifyAuditEvents(c *gin.Context) {
+	verification, err := h.AuditUsecase.VerifyAuditEvents(c.Request.Context())
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - audit - VerifyAuditEvents: VerifyAuditEvents")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, verification, "")
+}
+
+// parseTimeQuery parses the RFC 3339 time of the URL query key, it is nil when the key is empty
+func parseTimeQuery(c *gin.Context, key string) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
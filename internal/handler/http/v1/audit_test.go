@@ -0,0 +1,114 @@
+package v1_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	httpv1 "github.com/satriowisnugroho/book-store/internal/handler/http/v1"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetAuditEvents(t *testing.T) {
+	testcases := []struct {
+		name              string
+		query             string
+		uAuditRes         []*entity.AuditEvent
+		uAuditErr         error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid from",
+			query:             "?from=yesterday",
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "invalid to",
+			query:             "?to=2024-13-01",
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "from after to",
+			query:             "?from=2024-07-02T00:00:00Z&to=2024-07-01T00:00:00Z",
+			uAuditErr:         response.ErrInvalidAuditTimeRange,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to get audit events",
+			uAuditErr:         errors.New("error get audit events"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			query:             "?action=login.failed&actor_id=7&from=2024-07-01T00:00:00Z&to=2024-07-02T00:00:00Z",
+			uAuditRes:         []*entity.AuditEvent{{ID: 1, Action: entity.AuditActionLoginFailed}},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("GET", "/admin/audit-events"+tc.query, nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			auditUsecase := &testmock.AuditUsecaseInterface{}
+			auditUsecase.On("GetAuditEvents", mock.Anything, mock.Anything).Return(tc.uAuditRes, len(tc.uAuditRes), tc.uAuditErr)
+
+			h := &httpv1.AuditHandler{l, auditUsecase}
+			h.GetAuditEvents(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestVerifyAuditEvents(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uAuditRes         *entity.AuditVerification
+		uAuditErr         error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to verify audit events",
+			uAuditErr:         errors.New("error verify audit events"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			uAuditRes:         &entity.AuditVerification{Valid: false, Checked: 2, BrokenEventID: 3},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("GET", "/admin/audit-events/verify", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			auditUsecase := &testmock.AuditUsecaseInterface{}
+			auditUsecase.On("VerifyAuditEvents", mock.Anything).Return(tc.uAuditRes, tc.uAuditErr)
+
+			h := &httpv1.AuditHandler{l, auditUsecase}
+			h.VerifyAuditEvents(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
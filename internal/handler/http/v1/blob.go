@@ -0,0 +1,60 @@
+package v1
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/pkg/blobstore"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+)
+
+type BlobHandler struct {
+	Logger    logger.LoggerInterface
+	BlobStore blobstore.BlobStore
+}
+
+func newBlobHandler(handler *gin.RouterGroup, l logger.LoggerInterface, bs blobstore.BlobStore) {
+	r := &BlobHandler{l, bs}
+
+	handler.GET("/*key", r.GetBlob)
+}
+
+// GetBlob serves a stored book cover, blob keys are never reused so the response can be cached forever.
+// Other blobs of the store are not served
+func (h *BlobHandler) GetBlob(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if !strings.HasPrefix(path.Clean(key), entity.BookCoverKeyPrefix) {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	rc, err := h.BlobStore.Get(c.Request.Context(), key)
+	if err != nil {
+		if err != blobstore.ErrNotFound && err != blobstore.ErrInvalidKey {
+			h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - blob - GetBlob: Get")
+		}
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+	defer rc.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("X-Content-Type-Options", "nosniff")
+	if _, err := io.Copy(c.Writer, rc); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("http - v1 - blob - GetBlob: Copy %s", key))
+	}
+}
@@ -0,0 +1,79 @@
+package v1_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	httpv1 "github.com/satriowisnugroho/book-store/internal/handler/http/v1"
+	"github.com/satriowisnugroho/book-store/pkg/blobstore"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetBlob(t *testing.T) {
+	blobStore := blobstore.NewLocalBlobStore(t.TempDir(), "http://localhost:9999/blobs")
+	blobStore.Put(context.Background(), "covers/1/1/small.jpg", strings.NewReader("image"))
+	blobStore.Put(context.Background(), "mail/reset.eml", strings.NewReader("reset link"))
+
+	testcases := []struct {
+		name              string
+		key               string
+		httpStatusCodeRes int
+		contentType       string
+	}{
+		{
+			name:              "blob not found",
+			key:               "/covers/1/1/large.jpg",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "invalid key",
+			key:               "/covers/../../small.jpg",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "blob outside the covers",
+			key:               "/mail/reset.eml",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "blob outside the covers through the covers",
+			key:               "/covers/../mail/reset.eml",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "success",
+			key:               "/covers/1/1/small.jpg",
+			httpStatusCodeRes: http.StatusOK,
+			contentType:       "image/jpeg",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/blobs"+tc.key, nil)
+			ctx.Params = gin.Params{{Key: "key", Value: tc.key}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			h := &httpv1.BlobHandler{l, blobStore}
+			h.GetBlob(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+			if tc.contentType != "" {
+				assert.Equal(t, tc.contentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, "image", w.Body.String())
+			}
+		})
+	}
+}
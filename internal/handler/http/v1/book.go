@@ -1,11 +1,18 @@
 package v1
 
 import (
+	"fmt"
+	"net/http"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/config"
 	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/handler/http/middleware"
 	"github.com/satriowisnugroho/book-store/internal/helper"
 	"github.com/satriowisnugroho/book-store/internal/response"
 	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/catalogfeed"
 	"github.com/satriowisnugroho/book-store/pkg/logger"
 )
 
@@ -14,12 +21,21 @@ type BookHandler struct {
 	BookUsecase usecase.BookUsecaseInterface
 }
 
-func newBookHandler(handler *gin.RouterGroup, l logger.LoggerInterface, bu usecase.BookUsecaseInterface) {
+func newBookHandler(handler *gin.RouterGroup, l logger.LoggerInterface, authMiddleware gin.HandlerFunc, bu usecase.BookUsecaseInterface) {
 	r := &BookHandler{l, bu}
 
 	h := handler.Group("/books")
 	{
 		h.GET("/", r.GetBooks)
+		h.GET("/bestsellers", r.GetBestsellers)
+		h.GET("/isbn/:isbn", r.GetBookByIsbn)
+	}
+
+	a := handler.Group("/admin/books")
+	a.Use(authMiddleware, middleware.AdminMiddleware())
+	{
+		a.POST("/import", r.ImportBooks)
+		a.PUT("/:id/cover", r.UploadBookCover)
 	}
 }
 
@@ -30,21 +46,24 @@ func newBookHandler(handler *gin.RouterGroup, l logger.LoggerInterface, bu useca
 // @Accept      json
 // @Produce     json
 // @Param       keyword 		query		string 		false 	"title search by keyword"
+// @Param       sort 				query		string 		false 	"rating for the highest rated first, popularity for the most copies sold first"
 // @Param       offset 			query 	integer 	false		"offset"
 // @Param       limit 			query 	integer 	false 	"limit"
 // @Success     200 {object} response.SuccessBody{data=[]entity.Book,meta=response.MetaInfo}
+// @Failure     422 {object} response.ErrorBody
 // @Failure     500 {object} response.ErrorBody
 // @Router      /books [get]
 func (h *BookHandler) GetBooks(c *gin.Context) {
 	limit, offset := helper.GetLimitOffsetFromURLQuery(c)
 	payload := entity.GetBooksPayload{
 		TitleKeyword: c.Query("keyword"),
+		SortBy:       c.Query("sort"),
 		Offset:       offset,
 		Limit:        limit,
 	}
 	books, count, err := h.BookUsecase.GetBooks(c.Request.Context(), payload)
 	if err != nil {
-		h.Logger.Error(err, "http - v1 - book - GetBooks: GetBooks")
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - book - GetBooks: GetBooks")
 		response.Error(c, err)
 
 		return
@@ -52,3 +71,169 @@ func (h *BookHandler) GetBooks(c *gin.Context) {
 
 	response.OKWithPagination(c, books, "", count, offset, limit)
 }
+
+// @Summary     Show Bestsellers
+// @Description An API to show the books with the most copies sold in a period, the bestsellers are refreshed periodically
+// @ID          bestseller list
+// @Tags  	    Book
+// @Accept      json
+// @Produce     json
+// @Param       period 			query		string 		false 	"day, week (default), month or all-time"
+// @Param       category 		query		string 		false 	"only rank the books of this category"
+// @Param       offset 			query 	integer 	false		"offset"
+// @Param       limit 			query 	integer 	false 	"limit"
+// @Success     200 {object} response.SuccessBody{data=[]entity.Bestseller,meta=response.MetaInfo}
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /books/bestsellers [get]
+func (h *BookHandler) GetBestsellers(c *gin.Context) {
+	limit, offset := helper.GetLimitOffsetFromURLQuery(c)
+	payload := entity.GetBestsellersPayload{
+		Period:   c.Query("period"),
+		Category: c.Query("category"),
+		Offset:   offset,
+		Limit:    limit,
+	}
+	bestsellers, count, err := h.BookUsecase.GetBestsellers(c.Request.Context(), payload)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - book - GetBestsellers: GetBestsellers")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OKWithPagination(c, bestsellers, "", count, offset, limit)
+}
+
+// @Summary     Show a Book by ISBN
+// @Description An API to show a book by its ISBN-10 or ISBN-13
+// @ID          book by isbn
+// @Tags  	    Book
+// @Accept      json
+// @Produce     json
+// @Param       isbn 			path		string 		true 	"ISBN-10 or ISBN-13, hyphens are allowed"
+// @Success     200 {object} response.SuccessBody{data=entity.Book,meta=response.MetaInfo}
+// @Failure     404 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /books/isbn/{isbn} [get]
+func (h *BookHandler) GetBookByIsbn(c *gin.Context) {
+	book, err := h.BookUsecase.GetBookByIsbn(c.Request.Context(), c.Param("isbn"))
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - book - GetBookByIsbn: GetBookByIsbn")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, book, "")
+}
+
+// @Summary     Import Books
+// @Description An API to upsert books by ISBN from a CSV (isbn, title, price and optional category columns) or ONIX 3.0 feed
+// @ID          import books
+// @Tags  	    Admin
+// @Accept      multipart/form-data
+// @Produce     json
+// @Param       file 			formData	file 		true 	"catalog feed"
+// @Param       format 		formData	string 		false 	"csv or onix, guessed from the file extension when empty"
+// @Success     200 {object} response.SuccessBody{data=entity.BookImportReport,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /admin/books/import [post]
+func (h *BookHandler) ImportBooks(c *gin.Context) {
+	msg := "http - v1 - book - ImportBooks"
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.BookImportMaxFileSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: FormFile", msg))
+		response.Error(c, response.ErrInvalidFile)
+
+		return
+	}
+
+	format := c.PostForm("format")
+	if format == "" {
+		format = catalogfeed.FormatFromFilename(fileHeader.Filename)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Open", msg))
+		response.Error(c, response.ErrInvalidFile)
+
+		return
+	}
+	defer file.Close()
+
+	report, err := h.BookUsecase.ImportBooks(c.Request.Context(), format, file)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: ImportBooks", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, report, "Successfully import books")
+}
+
+// @Summary     Upload Book Cover
+// @Description An API to replace the cover image of a book, small, medium and large JPEG thumbnails are generated
+// @ID          upload book cover
+// @Tags  	    Admin
+// @Accept      multipart/form-data
+// @Produce     json
+// @Param       id 				path			integer 	true 	"book ID"
+// @Param       file 			formData	file 			true 	"JPEG, PNG or GIF cover image of at most 5 MB"
+// @Success     200 {object} response.SuccessBody{data=entity.Book,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     415 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /admin/books/{id}/cover [put]
+func (h *BookHandler) UploadBookCover(c *gin.Context) {
+	msg := "http - v1 - book - UploadBookCover"
+
+	bookID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	// Leave room for the multipart headers, the usecase enforces the exact image size
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.BookCoverMaxFileSize+(1<<20))
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: FormFile", msg))
+		response.Error(c, response.ErrInvalidImage)
+
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Open", msg))
+		response.Error(c, response.ErrInvalidImage)
+
+		return
+	}
+	defer file.Close()
+
+	book, err := h.BookUsecase.UploadBookCover(c.Request.Context(), bookID, file)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: UploadBookCover", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, book, "Successfully upload book cover")
+}
@@ -1,7 +1,9 @@
 package v1_test
 
 import (
+	"bytes"
 	"errors"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -9,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/satriowisnugroho/book-store/internal/entity"
 	httpv1 "github.com/satriowisnugroho/book-store/internal/handler/http/v1"
+	"github.com/satriowisnugroho/book-store/internal/response"
 	testmock "github.com/satriowisnugroho/book-store/test/mock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -40,6 +43,7 @@ func TestGetBooks(t *testing.T) {
 
 			l := &testmock.LoggerInterface{}
 			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
 
 			bookUsecase := &testmock.BookUsecaseInterface{}
 			bookUsecase.On("GetBooks", mock.Anything, mock.Anything).Return([]*entity.Book{{}}, 10, tc.uBookErr)
@@ -51,3 +55,220 @@ func TestGetBooks(t *testing.T) {
 		})
 	}
 }
+
+func TestGetBestsellers(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uBestsellerErr    error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid period",
+			uBestsellerErr:    response.ErrInvalidBestsellerPeriod,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to get bestsellers",
+			uBestsellerErr:    errors.New("error get bestsellers"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/books/bestsellers?period=month&category=Fantasy", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			payload := entity.GetBestsellersPayload{Period: entity.BestsellerPeriodMonth, Category: "Fantasy", Limit: 10}
+			bookUsecase := &testmock.BookUsecaseInterface{}
+			bookUsecase.On("GetBestsellers", mock.Anything, payload).Return([]*entity.Bestseller{{Rank: 1}}, 10, tc.uBestsellerErr)
+
+			h := &httpv1.BookHandler{l, bookUsecase}
+			h.GetBestsellers(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestGetBookByIsbn(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uBookErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid isbn",
+			uBookErr:          response.ErrInvalidIsbn,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to get book",
+			uBookErr:          errors.New("error get book"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/books/isbn/9780545010221", nil)
+			ctx.Params = gin.Params{{Key: "isbn", Value: "9780545010221"}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			bookUsecase := &testmock.BookUsecaseInterface{}
+			bookUsecase.On("GetBookByIsbn", mock.Anything, "9780545010221").Return(&entity.Book{}, tc.uBookErr)
+
+			h := &httpv1.BookHandler{l, bookUsecase}
+			h.GetBookByIsbn(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestImportBooks(t *testing.T) {
+	testcases := []struct {
+		name              string
+		filename          string
+		uBookErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "missing file",
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to import books",
+			filename:          "books.csv",
+			uBookErr:          errors.New("error import books"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			filename:          "books.csv",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			if tc.filename != "" {
+				part, _ := writer.CreateFormFile("file", tc.filename)
+				part.Write([]byte("isbn,title,price\n"))
+			}
+			writer.Close()
+
+			ctx.Request, _ = http.NewRequest("POST", "/admin/books/import", body)
+			ctx.Request.Header.Set("Content-Type", writer.FormDataContentType())
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			bookUsecase := &testmock.BookUsecaseInterface{}
+			bookUsecase.On("ImportBooks", mock.Anything, "csv", mock.Anything).Return(&entity.BookImportReport{}, tc.uBookErr)
+
+			h := &httpv1.BookHandler{l, bookUsecase}
+			h.ImportBooks(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestUploadBookCover(t *testing.T) {
+	testcases := []struct {
+		name              string
+		bookID            string
+		filename          string
+		uBookErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid book id",
+			bookID:            "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "missing file",
+			bookID:            "1",
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "unsupported image type",
+			bookID:            "1",
+			filename:          "cover.svg",
+			uBookErr:          response.ErrUnsupportedImageType,
+			httpStatusCodeRes: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:              "failed to upload book cover",
+			bookID:            "1",
+			filename:          "cover.png",
+			uBookErr:          errors.New("error upload book cover"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			bookID:            "1",
+			filename:          "cover.png",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			if tc.filename != "" {
+				part, _ := writer.CreateFormFile("file", tc.filename)
+				part.Write([]byte("image"))
+			}
+			writer.Close()
+
+			ctx.Request, _ = http.NewRequest("PUT", "/admin/books/"+tc.bookID+"/cover", body)
+			ctx.Request.Header.Set("Content-Type", writer.FormDataContentType())
+			ctx.Params = gin.Params{{Key: "id", Value: tc.bookID}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			bookUsecase := &testmock.BookUsecaseInterface{}
+			bookUsecase.On("UploadBookCover", mock.Anything, 1, mock.Anything).Return(&entity.Book{}, tc.uBookErr)
+
+			h := &httpv1.BookHandler{l, bookUsecase}
+			h.UploadBookCover(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
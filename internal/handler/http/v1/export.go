@@ -0,0 +1,157 @@
+package v1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/handler/http/middleware"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/exportfile"
+	"github.com/satriowisnugroho/book-store/pkg/httpserver"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+)
+
+type ExportHandler struct {
+	Logger        logger.LoggerInterface
+	ExportUsecase usecase.ExportUsecaseInterface
+}
+
+func newExportHandler(handler *gin.RouterGroup, l logger.LoggerInterface, authMiddleware gin.HandlerFunc, catalogAuthMiddleware gin.HandlerFunc, eu usecase.ExportUsecaseInterface) {
+	r := &ExportHandler{l, eu}
+
+	h := handler.Group("/admin/export")
+	{
+		h.GET("/books", catalogAuthMiddleware, middleware.AdminMiddleware(), r.ExportBooks)
+		h.GET("/orders", authMiddleware, middleware.AdminMiddleware(), r.ExportOrders)
+	}
+}
+
+// @Summary     Export Books
+// @Description An API to download every book matching the filters, the row count is sent in the X-Total-Count header
+// @ID          export books
+// @Tags  	    Admin
+// @Produce     text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param       format 			query		string 		false 	"csv (default), ndjson or xlsx"
+// @Param       keyword 		query		string 		false 	"title search by keyword"
+// @Success     200 {file} file
+// @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /admin/export/books [get]
+func (h *ExportHandler) ExportBooks(c *gin.Context) {
+	msg := "http - v1 - export - ExportBooks"
+
+	format := strings.ToLower(c.DefaultQuery("format", exportfile.FormatCSV))
+	if !exportfile.IsSupported(format) {
+		response.Error(c, response.ErrUnsupportedFileFormat)
+
+		return
+	}
+
+	extendExportWriteDeadline(c, h.Logger, msg)
+
+	payload := entity.GetBooksPayload{TitleKeyword: c.Query("keyword")}
+	count, err := h.ExportUsecase.GetBooksCount(c.Request.Context(), payload)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: GetBooksCount", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	setExportHeaders(c, "books", format, count)
+	if err := h.ExportUsecase.ExportBooks(c.Request.Context(), payload, format, c.Writer); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: ExportBooks", msg))
+		abortExport(c, err)
+	}
+}
+
+// @Summary     Export Orders
+// @Description An API to download orders, the row count is sent in the X-Total-Count header
+// @ID          export orders
+// @Tags  	    Admin
+// @Produce     text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param       format 			query		string 		false 	"csv (default), ndjson or xlsx"
+// @Param       user_id 		query		integer 	false 	"only export the orders of this user"
+// @Success     200 {file} file
+// @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /admin/export/orders [get]
+func (h *ExportHandler) ExportOrders(c *gin.Context) {
+	msg := "http - v1 - export - ExportOrders"
+
+	format := strings.ToLower(c.DefaultQuery("format", exportfile.FormatCSV))
+	if !exportfile.IsSupported(format) {
+		response.Error(c, response.ErrUnsupportedFileFormat)
+
+		return
+	}
+
+	// Every order is exported without a user_id, so a malformed one is refused rather than ignored
+	userID := 0
+	if userIDStr, ok := c.GetQuery("user_id"); ok {
+		var err error
+		userID, err = strconv.Atoi(userIDStr)
+		if err != nil || userID <= 0 {
+			response.Error(c, response.ErrInvalidUserID)
+
+			return
+		}
+	}
+
+	extendExportWriteDeadline(c, h.Logger, msg)
+
+	count, err := h.ExportUsecase.GetOrdersCount(c.Request.Context(), userID)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: GetOrdersCount", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	setExportHeaders(c, "orders", format, count)
+	if err := h.ExportUsecase.ExportOrders(c.Request.Context(), userID, format, c.Writer); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: ExportOrders", msg))
+		abortExport(c, err)
+	}
+}
+
+// extendExportWriteDeadline lets the export stream for longer than the write timeout of the server,
+// which stays short for every other route
+func extendExportWriteDeadline(c *gin.Context, l logger.LoggerInterface, msg string) {
+	if err := httpserver.SetWriteDeadline(c.Request.Context(), time.Now().Add(config.ExportWriteTimeout)); err != nil {
+		l.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: SetWriteDeadline", msg))
+	}
+}
+
+func setExportHeaders(c *gin.Context, name, format string, count int) {
+	c.Header("Content-Type", exportfile.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, exportfile.Filename(name, format)))
+	c.Header(config.TotalCountHeader, strconv.Itoa(count))
+}
+
+// abortExport sends the error response when nothing has been streamed yet,
+// otherwise the status is already sent and the truncated body is all the client gets
+func abortExport(c *gin.Context, err error) {
+	if !c.Writer.Written() {
+		c.Header("Content-Type", "")
+		c.Header("Content-Disposition", "")
+		c.Header(config.TotalCountHeader, "")
+		response.Error(c, err)
+
+		return
+	}
+
+	c.Abort()
+}
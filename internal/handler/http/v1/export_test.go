@@ -0,0 +1,162 @@
+package v1_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	httpv1 "github.com/satriowisnugroho/book-store/internal/handler/http/v1"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExportBooks(t *testing.T) {
+	testcases := []struct {
+		name              string
+		query             string
+		uCountErr         error
+		uExportErr        error
+		written           string
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "unsupported format",
+			query:             "?format=pdf",
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to get books count",
+			uCountErr:         errors.New("error get books count"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "failed to export books before streaming",
+			uExportErr:        errors.New("error export books"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "failed to export books while streaming",
+			uExportErr:        errors.New("error export books"),
+			written:           "id,isbn\n",
+			httpStatusCodeRes: http.StatusOK,
+		},
+		{
+			name:              "success",
+			query:             "?format=xlsx",
+			written:           "id,isbn\n",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/admin/export/books"+tc.query, nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			exportUsecase := &testmock.ExportUsecaseInterface{}
+			exportUsecase.On("GetBooksCount", mock.Anything, mock.Anything).Return(1, tc.uCountErr)
+			exportUsecase.On("ExportBooks", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(func(_ context.Context, _ entity.GetBooksPayload, _ string, w io.Writer) error {
+				if tc.written != "" {
+					w.Write([]byte(tc.written))
+				}
+
+				return tc.uExportErr
+			})
+
+			h := &httpv1.ExportHandler{l, exportUsecase}
+			h.ExportBooks(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+			if tc.httpStatusCodeRes == http.StatusOK {
+				assert.Equal(t, "1", w.Header().Get("X-Total-Count"))
+			}
+		})
+	}
+}
+
+func TestExportOrders(t *testing.T) {
+	testcases := []struct {
+		name              string
+		query             string
+		uCountErr         error
+		uExportErr        error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "unsupported format",
+			query:             "?format=pdf",
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "malformed user ID",
+			query:             "?user_id=abc",
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "negative user ID",
+			query:             "?user_id=-1",
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "empty user ID",
+			query:             "?user_id=",
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to get orders count",
+			uCountErr:         errors.New("error get orders count"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "failed to export orders",
+			uExportErr:        errors.New("error export orders"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			query:             "?format=ndjson&user_id=1",
+			httpStatusCodeRes: http.StatusOK,
+		},
+		{
+			name:              "success with every order",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/admin/export/orders"+tc.query, nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			exportUsecase := &testmock.ExportUsecaseInterface{}
+			exportUsecase.On("GetOrdersCount", mock.Anything, mock.Anything).Return(1, tc.uCountErr)
+			exportUsecase.On("ExportOrders", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.uExportErr)
+
+			h := &httpv1.ExportHandler{l, exportUsecase}
+			h.ExportOrders(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+			if tc.httpStatusCodeRes == http.StatusUnprocessableEntity {
+				exportUsecase.AssertNotCalled(t, "GetOrdersCount", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
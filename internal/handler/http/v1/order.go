@@ -2,12 +2,9 @@ package v1
 
 import (
 	"encoding/json"
-	"fmt"
 
 	"github.com/gin-gonic/gin"
-	"github.com/satriowisnugroho/book-store/internal/config"
 	"github.com/satriowisnugroho/book-store/internal/entity"
-	"github.com/satriowisnugroho/book-store/internal/handler/http/middleware"
 	"github.com/satriowisnugroho/book-store/internal/helper"
 	"github.com/satriowisnugroho/book-store/internal/response"
 	"github.com/satriowisnugroho/book-store/internal/usecase"
@@ -19,11 +16,11 @@ type OrderHandler struct {
 	OrderUsecase usecase.OrderUsecaseInterface
 }
 
-func newOrderHandler(handler *gin.RouterGroup, l logger.LoggerInterface, cfg *config.Config, bu usecase.OrderUsecaseInterface) {
+func newOrderHandler(handler *gin.RouterGroup, l logger.LoggerInterface, authMiddleware gin.HandlerFunc, bu usecase.OrderUsecaseInterface) {
 	r := &OrderHandler{l, bu}
 
 	h := handler.Group("/orders")
-	h.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+	h.Use(authMiddleware)
 	{
 		h.POST("/", r.CreateOrder)
 		h.GET("/", r.GetOrderHistory)
@@ -39,17 +36,16 @@ func newOrderHandler(handler *gin.RouterGroup, l logger.LoggerInterface, cfg *co
 // @Param       request		body		entity.OrderPayload		true		"payload"
 // @Success     200 {object} response.SuccessBody{data=entity.Order,meta=response.MetaInfo}
 // @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
 // @Failure     404 {object} response.ErrorBody
 // @Failure     422 {object} response.ErrorBody
 // @Failure     500 {object} response.ErrorBody
 // @Security		BearerAuth
 // @Router      /orders [post]
 func (h *OrderHandler) CreateOrder(c *gin.Context) {
-	msg := "http - v1 - order - CreateOrder"
-
 	var payload entity.OrderPayload
 	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
-		h.Logger.Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - order - CreateOrder: Decode payload")
 		response.Error(c, err)
 
 		return
@@ -57,7 +53,7 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 
 	order, err := h.OrderUsecase.CreateOrder(c, &payload)
 	if err != nil {
-		h.Logger.Error(err, fmt.Sprintf("%s: CreateOrder", msg))
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - order - CreateOrder: CreateOrder")
 		response.Error(c, err)
 
 		return
@@ -83,7 +79,7 @@ func (h *OrderHandler) GetOrderHistory(c *gin.Context) {
 	limit, offset := helper.GetLimitOffsetFromURLQuery(c)
 	orders, count, err := h.OrderUsecase.GetOrdersByUserID(c, limit, offset)
 	if err != nil {
-		h.Logger.Error(err, "http - v1 - order - GetOrderHistory: GetOrderHistory")
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - order - GetOrderHistory: GetOrderHistory")
 		response.Error(c, err)
 
 		return
@@ -56,6 +56,7 @@ func TestCreateOrder(t *testing.T) {
 
 			l := &testmock.LoggerInterface{}
 			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
 
 			orderUsecase := &testmock.OrderUsecaseInterface{}
 			orderUsecase.On("CreateOrder", mock.Anything, mock.Anything).Return(tc.uOrderRes, tc.uOrderErr)
@@ -94,6 +95,7 @@ func TestGetOrderHistory(t *testing.T) {
 
 			l := &testmock.LoggerInterface{}
 			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
 
 			orderUsecase := &testmock.OrderUsecaseInterface{}
 			orderUsecase.On("GetOrdersByUserID", mock.Anything, mock.Anything, mock.Anything).Return([]*entity.Order{{}}, 10, tc.uOrderErr)
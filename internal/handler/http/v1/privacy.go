@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/handler/http/middleware"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+)
+
+type PrivacyHandler struct {
+	Logger         logger.LoggerInterface
+	PrivacyUsecase usecase.PrivacyUsecaseInterface
+}
+
+func newPrivacyHandler(handler *gin.RouterGroup, l logger.LoggerInterface, authMiddleware gin.HandlerFunc, pu usecase.PrivacyUsecaseInterface) {
+	r := &PrivacyHandler{l, pu}
+
+	handler.POST("/users/me/export", authMiddleware, r.ExportUserData)
+
+	a := handler.Group("/admin/users/:id")
+	a.Use(authMiddleware, middleware.AdminMiddleware())
+	{
+		a.POST("/export", r.ExportUserDataByID)
+		a.POST("/erase", r.EraseUser)
+	}
+}
+
+// @Summary     Export User Data
+// @Description An API to download a ZIP of the profile, the orders with their items and the reviews of the logged in user as JSON
+// @ID          export user data
+// @Tags  	    User
+// @Produce     application/zip
+// @Security		BearerAuth
+// @Success     200 {file} file
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/me/export [post]
+func (h *PrivacyHandler) ExportUserData(c *gin.Context) {
+	h.exportUserData(c, helper.GetUserIDFromContext(c), "http - v1 - privacy - ExportUserData")
+}
+
+// @Summary     Export User Data By ID
+// @Description An API for admins to download the data of a user to answer a data subject request
+// @ID          export user data by id
+// @Tags  	    Admin
+// @Produce     application/zip
+// @Security		BearerAuth
+// @Param       id 				path		integer 	true		"user ID"
+// @Success     200 {file} file
+// @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /admin/users/{id}/export [post]
+func (h *PrivacyHandler) ExportUserDataByID(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	h.exportUserData(c, userID, "http - v1 - privacy - ExportUserDataByID")
+}
+
+// @Summary     Erase User
+// @Description An API for admins to erase a user, the user is anonymized and the personal data is deleted while the orders are kept for accounting
+// @ID          erase user
+// @Tags  	    Admin
+// @Produce     json
+// @Security		BearerAuth
+// @Param       id 				path		integer 	true		"user ID"
+// @Success     200 {object} response.SuccessBody{meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /admin/users/{id}/erase [post]
+func (h *PrivacyHandler) EraseUser(c *gin.Context) {
+	msg := "http - v1 - privacy - EraseUser"
+
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	if err := h.PrivacyUsecase.EraseUser(c.Request.Context(), userID); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: EraseUser", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, nil, "Successfully erase user")
+}
+
+func (h *PrivacyHandler) exportUserData(c *gin.Context, userID int, msg string) {
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%d-data.zip"`, userID))
+	if err := h.PrivacyUsecase.ExportUserData(c.Request.Context(), userID, c.Writer); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: ExportUserData", msg))
+		abortExport(c, err)
+	}
+}
@@ -0,0 +1,175 @@
+package v1_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	httpv1 "github.com/satriowisnugroho/book-store/internal/handler/http/v1"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExportUserData(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uExportErr        error
+		written           string
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "user not found",
+			uExportErr:        response.ErrNotFound,
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to export user data",
+			uExportErr:        errors.New("error export user data"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			written:           "PK",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/users/me/export", nil)
+			ctx.Set("user_id", 123)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			privacyUsecase := &testmock.PrivacyUsecaseInterface{}
+			privacyUsecase.On("ExportUserData", mock.Anything, 123, mock.Anything).Return(func(_ context.Context, _ int, w io.Writer) error {
+				if tc.written != "" {
+					w.Write([]byte(tc.written))
+				}
+
+				return tc.uExportErr
+			})
+
+			h := &httpv1.PrivacyHandler{l, privacyUsecase}
+			h.ExportUserData(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+			if tc.httpStatusCodeRes == http.StatusOK {
+				assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+				assert.Equal(t, `attachment; filename="user-123-data.zip"`, w.Header().Get("Content-Disposition"))
+			} else {
+				assert.Empty(t, w.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
+
+func TestExportUserDataByID(t *testing.T) {
+	testcases := []struct {
+		name              string
+		id                string
+		uExportErr        error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid user id",
+			id:                "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "user not found",
+			id:                "123",
+			uExportErr:        response.ErrNotFound,
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "success",
+			id:                "123",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/admin/users/"+tc.id+"/export", nil)
+			ctx.Params = gin.Params{{Key: "id", Value: tc.id}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			privacyUsecase := &testmock.PrivacyUsecaseInterface{}
+			privacyUsecase.On("ExportUserData", mock.Anything, 123, mock.Anything).Return(tc.uExportErr)
+
+			h := &httpv1.PrivacyHandler{l, privacyUsecase}
+			h.ExportUserDataByID(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestEraseUser(t *testing.T) {
+	testcases := []struct {
+		name              string
+		id                string
+		uEraseErr         error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid user id",
+			id:                "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "user not found",
+			id:                "123",
+			uEraseErr:         response.ErrNotFound,
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to erase user",
+			id:                "123",
+			uEraseErr:         errors.New("error erase user"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			id:                "123",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/admin/users/"+tc.id+"/erase", nil)
+			ctx.Params = gin.Params{{Key: "id", Value: tc.id}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			privacyUsecase := &testmock.PrivacyUsecaseInterface{}
+			privacyUsecase.On("EraseUser", mock.Anything, 123).Return(tc.uEraseErr)
+
+			h := &httpv1.PrivacyHandler{l, privacyUsecase}
+			h.EraseUser(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
@@ -0,0 +1,496 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+)
+
+type ReadingListHandler struct {
+	Logger             logger.LoggerInterface
+	ReadingListUsecase usecase.ReadingListUsecaseInterface
+}
+
+func newReadingListHandler(handler *gin.RouterGroup, l logger.LoggerInterface, authMiddleware gin.HandlerFunc, rlu usecase.ReadingListUsecaseInterface) {
+	r := &ReadingListHandler{l, rlu}
+
+	w := handler.Group("/wishlist")
+	w.Use(authMiddleware)
+	{
+		w.GET("/", r.GetWishlist)
+		w.POST("/books", r.AddWishlistBook)
+		w.DELETE("/books/:book_id", r.RemoveWishlistBook)
+		w.GET("/order-payload", r.GetWishlistOrderPayload)
+	}
+
+	h := handler.Group("/reading-lists")
+	{
+		h.GET("/public", r.GetPublicReadingLists)
+		h.GET("/shared/:slug", r.GetSharedReadingList)
+	}
+
+	a := handler.Group("/reading-lists")
+	a.Use(authMiddleware)
+	{
+		a.GET("/", r.GetReadingLists)
+		a.POST("/", r.CreateReadingList)
+		a.GET("/:id", r.GetReadingList)
+		a.PUT("/:id", r.UpdateReadingList)
+		a.DELETE("/:id", r.DeleteReadingList)
+		a.POST("/:id/books", r.AddReadingListBook)
+		a.DELETE("/:id/books/:book_id", r.RemoveReadingListBook)
+		a.GET("/:id/order-payload", r.GetReadingListOrderPayload)
+	}
+}
+
+// @Summary     Show Wishlist
+// @Description An API to show the wishlist of the logged in user
+// @ID          wishlist
+// @Tags  	    Reading List
+// @Accept      json
+// @Produce     json
+// @Success     200 {object} response.SuccessBody{data=entity.ReadingList,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /wishlist [get]
+func (h *ReadingListHandler) GetWishlist(c *gin.Context) {
+	wishlist, err := h.ReadingListUsecase.GetWishlist(c)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - reading list - GetWishlist: GetWishlist")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, wishlist, "")
+}
+
+// @Summary     Add a Book to Wishlist
+// @Description An API to save a book for later, adding a book already in the wishlist is a no-op
+// @ID          add wishlist book
+// @Tags  	    Reading List
+// @Accept      json
+// @Produce     json
+// @Param       request		body		entity.ReadingListBookPayload	true		"payload"
+// @Success     200 {object} response.SuccessBody{data=entity.ReadingList,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /wishlist/books [post]
+func (h *ReadingListHandler) AddWishlistBook(c *gin.Context) {
+	msg := "http - v1 - reading list - AddWishlistBook"
+
+	var payload entity.ReadingListBookPayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	wishlist, err := h.ReadingListUsecase.AddWishlistBook(c, &payload)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: AddWishlistBook", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, wishlist, "Successfully add a book to the wishlist")
+}
+
+// @Summary     Remove a Book from Wishlist
+// @Description An API to remove a book from the wishlist of the logged in user
+// @ID          remove wishlist book
+// @Tags  	    Reading List
+// @Accept      json
+// @Produce     json
+// @Param       book_id 	path		integer 	true		"book ID"
+// @Success     200 {object} response.SuccessBody{data=entity.ReadingList,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /wishlist/books/{book_id} [delete]
+func (h *ReadingListHandler) RemoveWishlistBook(c *gin.Context) {
+	bookID, err := strconv.Atoi(c.Param("book_id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	wishlist, err := h.ReadingListUsecase.RemoveWishlistBook(c, bookID)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - reading list - RemoveWishlistBook: RemoveWishlistBook")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, wishlist, "Successfully remove a book from the wishlist")
+}
+
+// @Summary     Build Order Payload from Wishlist
+// @Description An API to build the payload of an order holding one copy of every book of the wishlist, the payload can be sent as is to create an order
+// @ID          wishlist order payload
+// @Tags  	    Reading List
+// @Accept      json
+// @Produce     json
+// @Success     200 {object} response.SuccessBody{data=entity.OrderPayload,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /wishlist/order-payload [get]
+func (h *ReadingListHandler) GetWishlistOrderPayload(c *gin.Context) {
+	payload, err := h.ReadingListUsecase.GetWishlistOrderPayload(c)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - reading list - GetWishlistOrderPayload: GetWishlistOrderPayload")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, payload, "")
+}
+
+// @Summary     Show List of Public Reading Lists
+// @Description An API to show the public reading lists of every user with their books, the newest first
+// @ID          public reading list list
+// @Tags  	    Reading List
+// @Accept      json
+// @Produce     json
+// @Param       offset 		query 	integer 	false		"offset"
+// @Param       limit 		query 	integer 	false 	"limit"
+// @Success     200 {object} response.SuccessBody{data=[]entity.ReadingList,meta=response.MetaInfo}
+// @Failure     500 {object} response.ErrorBody
+// @Router      /reading-lists/public [get]
+func (h *ReadingListHandler) GetPublicReadingLists(c *gin.Context) {
+	limit, offset := helper.GetLimitOffsetFromURLQuery(c)
+	readingLists, count, err := h.ReadingListUsecase.GetPublicReadingLists(c, limit, offset)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - reading list - GetPublicReadingLists: GetPublicReadingLists")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OKWithPagination(c, readingLists, "", count, offset, limit)
+}
+
+// @Summary     Show Shared Reading List
+// @Description An API to show a public reading list by its share slug
+// @ID          shared reading list
+// @Tags  	    Reading List
+// @Accept      json
+// @Produce     json
+// @Param       slug 			path		string 		true		"share slug"
+// @Success     200 {object} response.SuccessBody{data=entity.ReadingList,meta=response.MetaInfo}
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /reading-lists/shared/{slug} [get]
+func (h *ReadingListHandler) GetSharedReadingList(c *gin.Context) {
+	readingList, err := h.ReadingListUsecase.GetSharedReadingList(c, c.Param("slug"))
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - reading list - GetSharedReadingList: GetSharedReadingList")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, readingList, "")
+}
+
+// @Summary     Show List of Reading Lists
+// @Description An API to show the reading lists of the logged in user, the newest first
+// @ID          reading list list
+// @Tags  	    Reading List
+// @Accept      json
+// @Produce     json
+// @Param       offset 		query 	integer 	false		"offset"
+// @Param       limit 		query 	integer 	false 	"limit"
+// @Success     200 {object} response.SuccessBody{data=[]entity.ReadingList,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /reading-lists [get]
+func (h *ReadingListHandler) GetReadingLists(c *gin.Context) {
+	limit, offset := helper.GetLimitOffsetFromURLQuery(c)
+	readingLists, count, err := h.ReadingListUsecase.GetReadingLists(c, limit, offset)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - reading list - GetReadingLists: GetReadingLists")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OKWithPagination(c, readingLists, "", count, offset, limit)
+}
+
+// @Summary     Create Reading List
+// @Description An API to create a named reading list, a public list gets a share slug
+// @ID          create reading list
+// @Tags  	    Reading List
+// @Accept      json
+// @Produce     json
+// @Param       request		body		entity.ReadingListPayload	true		"payload"
+// @Success     200 {object} response.SuccessBody{data=entity.ReadingList,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /reading-lists [post]
+func (h *ReadingListHandler) CreateReadingList(c *gin.Context) {
+	msg := "http - v1 - reading list - CreateReadingList"
+
+	var payload entity.ReadingListPayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	readingList, err := h.ReadingListUsecase.CreateReadingList(c, &payload)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: CreateReadingList", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, readingList, "Successfully create a reading list")
+}
+
+// @Summary     Show Reading List
+// @Description An API to show a reading list of the logged in user
+// @ID          reading list
+// @Tags  	    Reading List
+// @Accept      json
+// @Produce     json
+// @Param       id 				path		integer 	true		"reading list ID"
+// @Success     200 {object} response.SuccessBody{data=entity.ReadingList,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /reading-lists/{id} [get]
+func (h *ReadingListHandler) GetReadingList(c *gin.Context) {
+	readingListID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	readingList, err := h.ReadingListUsecase.GetReadingList(c, readingListID)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - reading list - GetReadingList: GetReadingList")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, readingList, "")
+}
+
+// @Summary     Update Reading List
+// @Description An API to rename a reading list and change its visibility, making a list private revokes its share slug
+// @ID          update reading list
+// @Tags  	    Reading List
+// @Accept      json
+// @Produce     json
+// @Param       id 				path		integer 										true		"reading list ID"
+// @Param       request		body		entity.ReadingListPayload		true		"payload"
+// @Success     200 {object} response.SuccessBody{data=entity.ReadingList,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /reading-lists/{id} [put]
+func (h *ReadingListHandler) UpdateReadingList(c *gin.Context) {
+	msg := "http - v1 - reading list - UpdateReadingList"
+
+	readingListID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	var payload entity.ReadingListPayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	readingList, err := h.ReadingListUsecase.UpdateReadingList(c, readingListID, &payload)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: UpdateReadingList", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, readingList, "Successfully update a reading list")
+}
+
+// @Summary     Delete Reading List
+// @Description An API to delete a reading list of the logged in user
+// @ID          delete reading list
+// @Tags  	    Reading List
+// @Accept      json
+// @Produce     json
+// @Param       id 				path		integer 	true		"reading list ID"
+// @Success     200 {object} response.SuccessBody{meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /reading-lists/{id} [delete]
+func (h *ReadingListHandler) DeleteReadingList(c *gin.Context) {
+	readingListID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	if err := h.ReadingListUsecase.DeleteReadingList(c, readingListID); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - reading list - DeleteReadingList: DeleteReadingList")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, nil, "Successfully delete a reading list")
+}
+
+// @Summary     Add a Book to Reading List
+// @Description An API to add a book to a reading list, adding a book already in the list is a no-op
+// @ID          add reading list book
+// @Tags  	    Reading List
+// @Accept      json
+// @Produce     json
+// @Param       id 				path		integer 												true		"reading list ID"
+// @Param       request		body		entity.ReadingListBookPayload		true		"payload"
+// @Success     200 {object} response.SuccessBody{data=entity.ReadingList,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /reading-lists/{id}/books [post]
+func (h *ReadingListHandler) AddReadingListBook(c *gin.Context) {
+	msg := "http - v1 - reading list - AddReadingListBook"
+
+	readingListID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	var payload entity.ReadingListBookPayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	readingList, err := h.ReadingListUsecase.AddReadingListBook(c, readingListID, &payload)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: AddReadingListBook", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, readingList, "Successfully add a book to the reading list")
+}
+
+// @Summary     Remove a Book from Reading List
+// @Description An API to remove a book from a reading list
+// @ID          remove reading list book
+// @Tags  	    Reading List
+// @Accept      json
+// @Produce     json
+// @Param       id 				path		integer 	true		"reading list ID"
+// @Param       book_id 	path		integer 	true		"book ID"
+// @Success     200 {object} response.SuccessBody{data=entity.ReadingList,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /reading-lists/{id}/books/{book_id} [delete]
+func (h *ReadingListHandler) RemoveReadingListBook(c *gin.Context) {
+	readingListID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	bookID, err := strconv.Atoi(c.Param("book_id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	readingList, err := h.ReadingListUsecase.RemoveReadingListBook(c, readingListID, bookID)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - reading list - RemoveReadingListBook: RemoveReadingListBook")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, readingList, "Successfully remove a book from the reading list")
+}
+
+// @Summary     Build Order Payload from Reading List
+// @Description An API to build the payload of an order holding one copy of every book of a reading list, the payload can be sent as is to create an order
+// @ID          reading list order payload
+// @Tags  	    Reading List
+// @Accept      json
+// @Produce     json
+// @Param       id 				path		integer 	true		"reading list ID"
+// @Success     200 {object} response.SuccessBody{data=entity.OrderPayload,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /reading-lists/{id}/order-payload [get]
+func (h *ReadingListHandler) GetReadingListOrderPayload(c *gin.Context) {
+	readingListID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	payload, err := h.ReadingListUsecase.GetReadingListOrderPayload(c, readingListID)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - reading list - GetReadingListOrderPayload: GetReadingListOrderPayload")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, payload, "")
+}
@@ -0,0 +1,688 @@
+package v1_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	httpv1 "github.com/satriowisnugroho/book-store/internal/handler/http/v1"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetWishlist(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uWishlistErr      error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to get wishlist",
+			uWishlistErr:      errors.New("error get wishlist"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/wishlist", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			readingListUsecase := &testmock.ReadingListUsecaseInterface{}
+			readingListUsecase.On("GetWishlist", mock.Anything).Return(&entity.ReadingList{}, tc.uWishlistErr)
+
+			h := &httpv1.ReadingListHandler{l, readingListUsecase}
+			h.GetWishlist(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestAddWishlistBook(t *testing.T) {
+	testcases := []struct {
+		name              string
+		body              string
+		uWishlistErr      error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to decode payload",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "book is not found",
+			body:              `{"book_id":7}`,
+			uWishlistErr:      response.ErrNotFound,
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "success",
+			body:              `{"book_id":7}`,
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request = &http.Request{
+				Header: make(http.Header),
+				Method: "POST",
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			readingListUsecase := &testmock.ReadingListUsecaseInterface{}
+			readingListUsecase.On("AddWishlistBook", mock.Anything, &entity.ReadingListBookPayload{BookID: 7}).Return(&entity.ReadingList{}, tc.uWishlistErr)
+
+			h := &httpv1.ReadingListHandler{l, readingListUsecase}
+			h.AddWishlistBook(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestRemoveWishlistBook(t *testing.T) {
+	testcases := []struct {
+		name              string
+		bookID            string
+		uWishlistErr      error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid book id",
+			bookID:            "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to remove book",
+			bookID:            "7",
+			uWishlistErr:      errors.New("error remove book"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			bookID:            "7",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("DELETE", "/wishlist/books/"+tc.bookID, nil)
+			ctx.Params = gin.Params{{Key: "book_id", Value: tc.bookID}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			readingListUsecase := &testmock.ReadingListUsecaseInterface{}
+			readingListUsecase.On("RemoveWishlistBook", mock.Anything, 7).Return(&entity.ReadingList{}, tc.uWishlistErr)
+
+			h := &httpv1.ReadingListHandler{l, readingListUsecase}
+			h.RemoveWishlistBook(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestGetWishlistOrderPayload(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uPayloadErr       error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "empty wishlist",
+			uPayloadErr:       response.ErrEmptyReadingList,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "success",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/wishlist/order-payload", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			readingListUsecase := &testmock.ReadingListUsecaseInterface{}
+			readingListUsecase.On("GetWishlistOrderPayload", mock.Anything).Return(&entity.OrderPayload{}, tc.uPayloadErr)
+
+			h := &httpv1.ReadingListHandler{l, readingListUsecase}
+			h.GetWishlistOrderPayload(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestGetPublicReadingLists(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uReadingListErr   error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to get public reading lists",
+			uReadingListErr:   errors.New("error get public reading lists"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/reading-lists/public?limit=5", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			readingListUsecase := &testmock.ReadingListUsecaseInterface{}
+			readingListUsecase.On("GetPublicReadingLists", mock.Anything, 5, 0).Return([]*entity.ReadingList{{}}, 1, tc.uReadingListErr)
+
+			h := &httpv1.ReadingListHandler{l, readingListUsecase}
+			h.GetPublicReadingLists(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestGetSharedReadingList(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uReadingListErr   error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "reading list is not found",
+			uReadingListErr:   response.ErrNotFound,
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "success",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/reading-lists/shared/slug", nil)
+			ctx.Params = gin.Params{{Key: "slug", Value: "slug"}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			readingListUsecase := &testmock.ReadingListUsecaseInterface{}
+			readingListUsecase.On("GetSharedReadingList", mock.Anything, "slug").Return(&entity.ReadingList{}, tc.uReadingListErr)
+
+			h := &httpv1.ReadingListHandler{l, readingListUsecase}
+			h.GetSharedReadingList(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestGetReadingLists(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uReadingListErr   error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to get reading lists",
+			uReadingListErr:   errors.New("error get reading lists"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/reading-lists", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			readingListUsecase := &testmock.ReadingListUsecaseInterface{}
+			readingListUsecase.On("GetReadingLists", mock.Anything, 10, 0).Return([]*entity.ReadingList{{}}, 1, tc.uReadingListErr)
+
+			h := &httpv1.ReadingListHandler{l, readingListUsecase}
+			h.GetReadingLists(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestCreateReadingList(t *testing.T) {
+	testcases := []struct {
+		name              string
+		body              string
+		uReadingListErr   error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to decode payload",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "invalid name",
+			body:              `{"name":""}`,
+			uReadingListErr:   response.ErrInvalidReadingListName,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "success",
+			body:              `{"name":"Summer reads","is_public":true}`,
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request = &http.Request{
+				Header: make(http.Header),
+				Method: "POST",
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			readingListUsecase := &testmock.ReadingListUsecaseInterface{}
+			readingListUsecase.On("CreateReadingList", mock.Anything, mock.Anything).Return(&entity.ReadingList{}, tc.uReadingListErr)
+
+			h := &httpv1.ReadingListHandler{l, readingListUsecase}
+			h.CreateReadingList(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestGetReadingList(t *testing.T) {
+	testcases := []struct {
+		name              string
+		readingListID     string
+		uReadingListErr   error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid reading list id",
+			readingListID:     "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "reading list is not found",
+			readingListID:     "1",
+			uReadingListErr:   response.ErrNotFound,
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "success",
+			readingListID:     "1",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/reading-lists/"+tc.readingListID, nil)
+			ctx.Params = gin.Params{{Key: "id", Value: tc.readingListID}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			readingListUsecase := &testmock.ReadingListUsecaseInterface{}
+			readingListUsecase.On("GetReadingList", mock.Anything, 1).Return(&entity.ReadingList{}, tc.uReadingListErr)
+
+			h := &httpv1.ReadingListHandler{l, readingListUsecase}
+			h.GetReadingList(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestUpdateReadingList(t *testing.T) {
+	testcases := []struct {
+		name              string
+		readingListID     string
+		body              string
+		uReadingListErr   error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid reading list id",
+			readingListID:     "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to decode payload",
+			readingListID:     "1",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "failed to update reading list",
+			readingListID:     "1",
+			body:              `{"name":"Summer reads"}`,
+			uReadingListErr:   errors.New("error update reading list"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			readingListID:     "1",
+			body:              `{"name":"Summer reads"}`,
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request = &http.Request{
+				Header: make(http.Header),
+				Method: "PUT",
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			}
+			ctx.Params = gin.Params{{Key: "id", Value: tc.readingListID}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			readingListUsecase := &testmock.ReadingListUsecaseInterface{}
+			readingListUsecase.On("UpdateReadingList", mock.Anything, 1, mock.Anything).Return(&entity.ReadingList{}, tc.uReadingListErr)
+
+			h := &httpv1.ReadingListHandler{l, readingListUsecase}
+			h.UpdateReadingList(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestDeleteReadingList(t *testing.T) {
+	testcases := []struct {
+		name              string
+		readingListID     string
+		uReadingListErr   error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid reading list id",
+			readingListID:     "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to delete reading list",
+			readingListID:     "1",
+			uReadingListErr:   errors.New("error delete reading list"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			readingListID:     "1",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("DELETE", "/reading-lists/"+tc.readingListID, nil)
+			ctx.Params = gin.Params{{Key: "id", Value: tc.readingListID}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			readingListUsecase := &testmock.ReadingListUsecaseInterface{}
+			readingListUsecase.On("DeleteReadingList", mock.Anything, 1).Return(tc.uReadingListErr)
+
+			h := &httpv1.ReadingListHandler{l, readingListUsecase}
+			h.DeleteReadingList(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestAddReadingListBook(t *testing.T) {
+	testcases := []struct {
+		name              string
+		readingListID     string
+		body              string
+		uReadingListErr   error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid reading list id",
+			readingListID:     "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to decode payload",
+			readingListID:     "1",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "book is not found",
+			readingListID:     "1",
+			body:              `{"book_id":7}`,
+			uReadingListErr:   response.ErrNotFound,
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "success",
+			readingListID:     "1",
+			body:              `{"book_id":7}`,
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request = &http.Request{
+				Header: make(http.Header),
+				Method: "POST",
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			}
+			ctx.Params = gin.Params{{Key: "id", Value: tc.readingListID}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			readingListUsecase := &testmock.ReadingListUsecaseInterface{}
+			readingListUsecase.On("AddReadingListBook", mock.Anything, 1, &entity.ReadingListBookPayload{BookID: 7}).Return(&entity.ReadingList{}, tc.uReadingListErr)
+
+			h := &httpv1.ReadingListHandler{l, readingListUsecase}
+			h.AddReadingListBook(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestRemoveReadingListBook(t *testing.T) {
+	testcases := []struct {
+		name              string
+		readingListID     string
+		bookID            string
+		uReadingListErr   error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid reading list id",
+			readingListID:     "abc",
+			bookID:            "7",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "invalid book id",
+			readingListID:     "1",
+			bookID:            "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to remove book",
+			readingListID:     "1",
+			bookID:            "7",
+			uReadingListErr:   errors.New("error remove book"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			readingListID:     "1",
+			bookID:            "7",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("DELETE", "/reading-lists/"+tc.readingListID+"/books/"+tc.bookID, nil)
+			ctx.Params = gin.Params{{Key: "id", Value: tc.readingListID}, {Key: "book_id", Value: tc.bookID}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			readingListUsecase := &testmock.ReadingListUsecaseInterface{}
+			readingListUsecase.On("RemoveReadingListBook", mock.Anything, 1, 7).Return(&entity.ReadingList{}, tc.uReadingListErr)
+
+			h := &httpv1.ReadingListHandler{l, readingListUsecase}
+			h.RemoveReadingListBook(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestGetReadingListOrderPayload(t *testing.T) {
+	testcases := []struct {
+		name              string
+		readingListID     string
+		uPayloadErr       error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid reading list id",
+			readingListID:     "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "empty reading list",
+			readingListID:     "1",
+			uPayloadErr:       response.ErrEmptyReadingList,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "success",
+			readingListID:     "1",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/reading-lists/"+tc.readingListID+"/order-payload", nil)
+			ctx.Params = gin.Params{{Key: "id", Value: tc.readingListID}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			readingListUsecase := &testmock.ReadingListUsecaseInterface{}
+			readingListUsecase.On("GetReadingListOrderPayload", mock.Anything, 1).Return(&entity.OrderPayload{}, tc.uPayloadErr)
+
+			h := &httpv1.ReadingListHandler{l, readingListUsecase}
+			h.GetReadingListOrderPayload(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+)
+
+type RecommendationHandler struct {
+	Logger                logger.LoggerInterface
+	RecommendationUsecase usecase.RecommendationUsecaseInterface
+}
+
+func newRecommendationHandler(handler *gin.RouterGroup, l logger.LoggerInterface, authMiddleware gin.HandlerFunc, ru usecase.RecommendationUsecaseInterface) {
+	r := &RecommendationHandler{l, ru}
+
+	handler.GET("/books/:id/related", r.GetRelatedBooks)
+	handler.GET("/users/me/recommendations", authMiddleware, r.GetUserRecommendations)
+}
+
+// @Summary     Show Related Books
+// @Description An API to show the books customers also bought together with a book, the most related first
+// @ID          related books
+// @Tags  	    Recommendation
+// @Accept      json
+// @Produce     json
+// @Param       id 				path		integer 	true		"book ID"
+// @Param       limit 		query 	integer 	false 	"limit"
+// @Success     200 {object} response.SuccessBody{data=[]entity.Book,meta=response.MetaInfo}
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /books/{id}/related [get]
+func (h *RecommendationHandler) GetRelatedBooks(c *gin.Context) {
+	bookID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	limit, _ := helper.GetLimitOffsetFromURLQuery(c)
+	books, err := h.RecommendationUsecase.GetRelatedBooks(c.Request.Context(), bookID, limit)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - recommendation - GetRelatedBooks: GetRelatedBooks")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, books, "")
+}
+
+// @Summary     Show Recommended Books
+// @Description An API to show the books recommended from the purchases of the logged in user, the bestsellers are shown until the user has bought something
+// @ID          user recommendations
+// @Tags  	    Recommendation
+// @Accept      json
+// @Produce     json
+// @Param       limit 		query 	integer 	false 	"limit"
+// @Success     200 {object} response.SuccessBody{data=[]entity.Book,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /users/me/recommendations [get]
+func (h *RecommendationHandler) GetUserRecommendations(c *gin.Context) {
+	limit, _ := helper.GetLimitOffsetFromURLQuery(c)
+	books, err := h.RecommendationUsecase.GetUserRecommendations(c, limit)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - recommendation - GetUserRecommendations: GetUserRecommendations")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, books, "")
+}
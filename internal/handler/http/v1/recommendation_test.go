@@ -0,0 +1,109 @@
+package v1_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	httpv1 "github.com/satriowisnugroho/book-store/internal/handler/http/v1"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetRelatedBooks(t *testing.T) {
+	testcases := []struct {
+		name              string
+		bookID            string
+		uRelatedErr       error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid book id",
+			bookID:            "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "book is not found",
+			bookID:            "1",
+			uRelatedErr:       response.ErrNotFound,
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to get related books",
+			bookID:            "1",
+			uRelatedErr:       errors.New("error get related books"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			bookID:            "1",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Params = []gin.Param{{Key: "id", Value: tc.bookID}}
+			ctx.Request, _ = http.NewRequest("GET", "/books/"+tc.bookID+"/related", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			recommendationUsecase := &testmock.RecommendationUsecaseInterface{}
+			recommendationUsecase.On("GetRelatedBooks", mock.Anything, 1, 10).Return([]*entity.Book{}, tc.uRelatedErr)
+
+			h := &httpv1.RecommendationHandler{l, recommendationUsecase}
+			h.GetRelatedBooks(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestGetUserRecommendations(t *testing.T) {
+	testcases := []struct {
+		name               string
+		uRecommendationErr error
+		httpStatusCodeRes  int
+	}{
+		{
+			name:               "failed to get recommendations",
+			uRecommendationErr: errors.New("error get recommendations"),
+			httpStatusCodeRes:  http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/users/me/recommendations?limit=5", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			recommendationUsecase := &testmock.RecommendationUsecaseInterface{}
+			recommendationUsecase.On("GetUserRecommendations", mock.Anything, 5).Return([]*entity.Book{}, tc.uRecommendationErr)
+
+			h := &httpv1.RecommendationHandler{l, recommendationUsecase}
+			h.GetUserRecommendations(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/handler/http/middleware"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+)
+
+type ReviewHandler struct {
+	Logger        logger.LoggerInterface
+	ReviewUsecase usecase.ReviewUsecaseInterface
+}
+
+func newReviewHandler(handler *gin.RouterGroup, l logger.LoggerInterface, authMiddleware gin.HandlerFunc, ru usecase.ReviewUsecaseInterface) {
+	r := &ReviewHandler{l, ru}
+
+	h := handler.Group("/books/:id/reviews")
+	{
+		h.GET("/", r.GetReviews)
+		h.POST("/", authMiddleware, r.CreateReview)
+	}
+
+	rr := handler.Group("/reviews")
+	rr.Use(authMiddleware)
+	{
+		rr.POST("/:id/reports", r.ReportReview)
+	}
+
+	a := handler.Group("/admin/reviews")
+	a.Use(authMiddleware, middleware.AdminMiddleware())
+	{
+		a.GET("/", r.GetModerationQueue)
+		a.POST("/moderate", r.ModerateReviews)
+	}
+}
+
+// @Summary     Review a Book
+// @Description An API to rate and review a book, a user can review a book once. Reviews flagged by the content filter are held for moderation
+// @ID          create review
+// @Tags  	    Review
+// @Accept      json
+// @Produce     json
+// @Param       id 				path		integer 							true		"book ID"
+// @Param       request		body		entity.ReviewPayload	true		"payload"
+// @Success     200 {object} response.SuccessBody{data=entity.Review,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /books/{id}/reviews [post]
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	msg := "http - v1 - review - CreateReview"
+
+	bookID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	var payload entity.ReviewPayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	review, err := h.ReviewUsecase.CreateReview(c, bookID, &payload)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: CreateReview", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, review, "Successfully review a book")
+}
+
+// @Summary     Show List of Book Reviews
+// @Description An API to show the approved reviews of a book, the newest first
+// @ID          review list
+// @Tags  	    Review
+// @Accept      json
+// @Produce     json
+// @Param       id 				path		integer 	true		"book ID"
+// @Param       offset 		query 	integer 	false		"offset"
+// @Param       limit 		query 	integer 	false 	"limit"
+// @Success     200 {object} response.SuccessBody{data=[]entity.Review,meta=response.MetaInfo}
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /books/{id}/reviews [get]
+func (h *ReviewHandler) GetReviews(c *gin.Context) {
+	bookID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	limit, offset := helper.GetLimitOffsetFromURLQuery(c)
+	reviews, count, err := h.ReviewUsecase.GetReviewsByBookID(c, bookID, limit, offset)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - review - GetReviews: GetReviewsByBookID")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OKWithPagination(c, reviews, "", count, offset, limit)
+}
+
+// @Summary     Report a Review
+// @Description An API to report an abusive review, a review reported by several users is held for moderation
+// @ID          report review
+// @Tags  	    Review
+// @Accept      json
+// @Produce     json
+// @Param       id 				path		integer 										true		"review ID"
+// @Param       request		body		entity.ReviewReportPayload	true		"payload"
+// @Success     200 {object} response.SuccessBody{data=entity.ReviewReport,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /reviews/{id}/reports [post]
+func (h *ReviewHandler) ReportReview(c *gin.Context) {
+	msg := "http - v1 - review - ReportReview"
+
+	reviewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	var payload entity.ReviewReportPayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	report, err := h.ReviewUsecase.ReportReview(c, reviewID, &payload)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: ReportReview", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, report, "Successfully report a review")
+}
+
+// @Summary     Show Review Moderation Queue
+// @Description An API to show the reviews of a moderation status, the most reported first
+// @ID          review moderation queue
+// @Tags  	    Admin
+// @Accept      json
+// @Produce     json
+// @Param       status 		query		string 		false 	"pending (default), approved or rejected"
+// @Param       offset 		query 	integer 	false		"offset"
+// @Param       limit 		query 	integer 	false 	"limit"
+// @Success     200 {object} response.SuccessBody{data=[]entity.Review,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /admin/reviews [get]
+func (h *ReviewHandler) GetModerationQueue(c *gin.Context) {
+	limit, offset := helper.GetLimitOffsetFromURLQuery(c)
+	status := c.DefaultQuery("status", entity.ReviewStatusPending)
+	reviews, count, err := h.ReviewUsecase.GetReviewsByStatus(c, status, limit, offset)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - review - GetModerationQueue: GetReviewsByStatus")
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OKWithPagination(c, reviews, "", count, offset, limit)
+}
+
+// @Summary     Moderate Reviews
+// @Description An API to approve or reject up to 100 reviews at once
+// @ID          moderate reviews
+// @Tags  	    Admin
+// @Accept      json
+// @Produce     json
+// @Param       request		body		entity.ModerateReviewsPayload	true		"payload"
+// @Success     200 {object} response.SuccessBody{meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Security		BearerAuth
+// @Router      /admin/reviews/moderate [post]
+func (h *ReviewHandler) ModerateReviews(c *gin.Context) {
+	msg := "http - v1 - review - ModerateReviews"
+
+	var payload entity.ModerateReviewsPayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	if err := h.ReviewUsecase.ModerateReviews(c, &payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: ModerateReviews", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, nil, "Successfully moderate reviews")
+}
@@ -0,0 +1,319 @@
+package v1_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	httpv1 "github.com/satriowisnugroho/book-store/internal/handler/http/v1"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateReview(t *testing.T) {
+	testcases := []struct {
+		name              string
+		bookID            string
+		body              string
+		uReviewRes        *entity.Review
+		uReviewErr        error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid book id",
+			bookID:            "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to decode payload",
+			bookID:            "1",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "duplicate review",
+			bookID:            "1",
+			body:              `{"rating":5}`,
+			uReviewErr:        response.ErrDuplicateReview,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to create review",
+			bookID:            "1",
+			body:              `{"rating":5}`,
+			uReviewErr:        errors.New("error create review"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			bookID:            "1",
+			body:              `{"rating":5,"body":"Magical"}`,
+			uReviewRes:        &entity.Review{},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request = &http.Request{
+				Header: make(http.Header),
+				Method: "POST",
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			}
+			ctx.Params = gin.Params{{Key: "id", Value: tc.bookID}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			reviewUsecase := &testmock.ReviewUsecaseInterface{}
+			reviewUsecase.On("CreateReview", mock.Anything, 1, mock.Anything).Return(tc.uReviewRes, tc.uReviewErr)
+
+			h := &httpv1.ReviewHandler{l, reviewUsecase}
+			h.CreateReview(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestGetReviews(t *testing.T) {
+	testcases := []struct {
+		name              string
+		bookID            string
+		uReviewErr        error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid book id",
+			bookID:            "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to get reviews",
+			bookID:            "1",
+			uReviewErr:        errors.New("error get reviews"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			bookID:            "1",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/books/"+tc.bookID+"/reviews", nil)
+			ctx.Params = gin.Params{{Key: "id", Value: tc.bookID}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			reviewUsecase := &testmock.ReviewUsecaseInterface{}
+			reviewUsecase.On("GetReviewsByBookID", mock.Anything, 1, mock.Anything, mock.Anything).Return([]*entity.Review{{}}, 1, tc.uReviewErr)
+
+			h := &httpv1.ReviewHandler{l, reviewUsecase}
+			h.GetReviews(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestReportReview(t *testing.T) {
+	testcases := []struct {
+		name              string
+		reviewID          string
+		body              string
+		uReportRes        *entity.ReviewReport
+		uReportErr        error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid review id",
+			reviewID:          "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to decode payload",
+			reviewID:          "1",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "duplicate report",
+			reviewID:          "1",
+			body:              `{"reason":"Spam"}`,
+			uReportErr:        response.ErrDuplicateReviewReport,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to report review",
+			reviewID:          "1",
+			body:              `{"reason":"Spam"}`,
+			uReportErr:        errors.New("error report review"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			reviewID:          "1",
+			body:              `{"reason":"Spam"}`,
+			uReportRes:        &entity.ReviewReport{},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request = &http.Request{
+				Header: make(http.Header),
+				Method: "POST",
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			}
+			ctx.Params = gin.Params{{Key: "id", Value: tc.reviewID}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			reviewUsecase := &testmock.ReviewUsecaseInterface{}
+			reviewUsecase.On("ReportReview", mock.Anything, 1, mock.Anything).Return(tc.uReportRes, tc.uReportErr)
+
+			h := &httpv1.ReviewHandler{l, reviewUsecase}
+			h.ReportReview(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestGetModerationQueue(t *testing.T) {
+	testcases := []struct {
+		name              string
+		query             string
+		expectedStatus    string
+		uReviewErr        error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid status",
+			query:             "?status=deleted",
+			expectedStatus:    "deleted",
+			uReviewErr:        response.ErrInvalidReviewStatus,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to get reviews",
+			expectedStatus:    entity.ReviewStatusPending,
+			uReviewErr:        errors.New("error get reviews by status"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success default to pending",
+			expectedStatus:    entity.ReviewStatusPending,
+			httpStatusCodeRes: http.StatusOK,
+		},
+		{
+			name:              "success rejected",
+			query:             "?status=rejected",
+			expectedStatus:    entity.ReviewStatusRejected,
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/admin/reviews"+tc.query, nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			reviewUsecase := &testmock.ReviewUsecaseInterface{}
+			reviewUsecase.On("GetReviewsByStatus", mock.Anything, tc.expectedStatus, mock.Anything, mock.Anything).Return([]*entity.Review{{}}, 1, tc.uReviewErr)
+
+			h := &httpv1.ReviewHandler{l, reviewUsecase}
+			h.GetModerationQueue(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestModerateReviews(t *testing.T) {
+	testcases := []struct {
+		name              string
+		body              string
+		uModerateErr      error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to decode payload",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "invalid review ids",
+			body:              `{"review_ids":[],"status":"approved"}`,
+			uModerateErr:      response.ErrInvalidReviewIDs,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to moderate reviews",
+			body:              `{"review_ids":[1],"status":"approved"}`,
+			uModerateErr:      errors.New("error moderate reviews"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			body:              `{"review_ids":[1],"status":"approved"}`,
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request = &http.Request{
+				Header: make(http.Header),
+				Method: "POST",
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			reviewUsecase := &testmock.ReviewUsecaseInterface{}
+			reviewUsecase.On("ModerateReviews", mock.Anything, mock.Anything).Return(tc.uModerateErr)
+
+			h := &httpv1.ReviewHandler{l, reviewUsecase}
+			h.ModerateReviews(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
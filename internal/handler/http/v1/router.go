@@ -9,11 +9,25 @@ import (
 
 	// Swagger docs.
 	_ "github.com/satriowisnugroho/book-store/docs"
-	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/handler/http/middleware"
 	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/blobstore"
 	"github.com/satriowisnugroho/book-store/pkg/logger"
 )
 
+// NewEngine returns the engine serving the API. The client IP address is taken from the X-Forwarded-For
+// and X-Real-IP headers only when the request comes from one of the trusted proxies, IP addresses or CIDRs,
+// else any client could pick the IP address the login throttling, sessions and audit events see
+func NewEngine(trustedProxies []string) (*gin.Engine, error) {
+	handler := gin.New()
+	if err := handler.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+
+	return handler, nil
+}
+
 // NewRouter -.
 // Swagger spec:
 // @title       Book Store API
@@ -27,13 +41,24 @@ import (
 func NewRouter(
 	handler *gin.Engine,
 	l logger.LoggerInterface,
-	cfg *config.Config,
 	bu usecase.BookUsecaseInterface,
 	ou usecase.OrderUsecaseInterface,
 	uu usecase.UserUsecaseInterface,
+	eu usecase.ExportUsecaseInterface,
+	ru usecase.ReviewUsecaseInterface,
+	rlu usecase.ReadingListUsecaseInterface,
+	rcu usecase.RecommendationUsecaseInterface,
+	sku usecase.SigningKeyUsecaseInterface,
+	aku usecase.APIKeyUsecaseInterface,
+	pu usecase.PrivacyUsecaseInterface,
+	au usecase.AuditUsecaseInterface,
+	bs blobstore.BlobStore,
 ) {
 	// Options
-	handler.Use(gin.Logger())
+	handler.Use(middleware.TracingMiddleware())
+	handler.Use(middleware.RequestInfoMiddleware())
+	handler.Use(middleware.AccessLogMiddleware(l))
+	handler.Use(middleware.MetricsMiddleware())
 	handler.Use(gin.Recovery())
 
 	// Swagger
@@ -43,11 +68,31 @@ func NewRouter(
 	// K8s probe
 	handler.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
 
+	// Uploaded blobs such as book covers
+	newBlobHandler(handler.Group("/blobs"), l, bs)
+
+	// Public keys verifying the access tokens
+	newSigningKeyHandler(handler.Group("/.well-known"), l, sku)
+
+	// Access tokens revoked by a logout are rejected until they expire. API keys are let through
+	// the catalog export and the orders with their scopes, and everywhere else with admin:* but the session routes
+	authMiddleware := middleware.AuthMiddleware(sku, uu, aku)
+	sessionAuthMiddleware := middleware.SessionAuthMiddleware(sku, uu)
+	catalogAuthMiddleware := middleware.AuthMiddleware(sku, uu, aku, entity.APIKeyScopeReadCatalog)
+	orderAuthMiddleware := middleware.AuthMiddleware(sku, uu, aku, entity.APIKeyScopeWriteOrders)
+
 	// Routers
 	h := handler.Group("/v1")
 	{
-		newBookHandler(h, l, bu)
-		newOrderHandler(h, l, cfg, ou)
-		newUserHandler(h, l, uu)
+		newBookHandler(h, l, authMiddleware, bu)
+		newOrderHandler(h, l, orderAuthMiddleware, ou)
+		newUserHandler(h, l, authMiddleware, sessionAuthMiddleware, uu)
+		newExportHandler(h, l, authMiddleware, catalogAuthMiddleware, eu)
+		newReviewHandler(h, l, authMiddleware, ru)
+		newReadingListHandler(h, l, authMiddleware, rlu)
+		newRecommendationHandler(h, l, authMiddleware, rcu)
+		newAPIKeyHandler(h, l, authMiddleware, aku)
+		newPrivacyHandler(h, l, authMiddleware, pu)
+		newAuditHandler(h, l, authMiddleware, au)
 	}
 }
@@ -1,24 +1,97 @@
 package v1_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
-	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/internal/entity"
 	v1 "github.com/satriowisnugroho/book-store/internal/handler/http/v1"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	"github.com/satriowisnugroho/book-store/pkg/metrics"
 	mocks "github.com/satriowisnugroho/book-store/test/mock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestNewRouter(t *testing.T) {
+	l := &mocks.LoggerInterface{}
+	l.On("WithContext", mock.Anything).Return(l)
+	l.On("With", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(l)
+	l.On("Info", mock.Anything)
+	l.On("Warn", mock.Anything)
+
 	r := gin.Default()
-	v1.NewRouter(r, &mocks.LoggerInterface{}, &config.Config{}, &mocks.BookUsecaseInterface{}, &mocks.OrderUsecaseInterface{}, &mocks.UserUsecaseInterface{})
+	v1.NewRouter(r, l, &mocks.BookUsecaseInterface{}, &mocks.OrderUsecaseInterface{}, &mocks.UserUsecaseInterface{}, &mocks.ExportUsecaseInterface{}, &mocks.ReviewUsecaseInterface{}, &mocks.ReadingListUsecaseInterface{}, &mocks.RecommendationUsecaseInterface{}, &mocks.SigningKeyUsecaseInterface{}, &mocks.APIKeyUsecaseInterface{}, &mocks.PrivacyUsecaseInterface{}, &mocks.AuditUsecaseInterface{}, &mocks.BlobStore{})
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/healthz", nil)
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+
+	// The metrics are served on their own listener, not by the public router
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/metrics", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `book_store_http_requests_total{method="GET",route="/healthz",status="200"}`)
+}
+
+func TestNewEngine(t *testing.T) {
+	_, err := v1.NewEngine([]string{"not an address"})
+	assert.Error(t, err)
+
+	testcases := []struct {
+		name           string
+		trustedProxies []string
+		wantIPAddress  string
+	}{
+		{
+			name:          "spoofed X-Forwarded-For without trusted proxies",
+			wantIPAddress: "203.0.113.7",
+		},
+		{
+			name:           "X-Forwarded-For of a trusted proxy",
+			trustedProxies: []string{"203.0.113.0/24"},
+			wantIPAddress:  "198.51.100.9",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := &mocks.LoggerInterface{}
+			l.On("WithContext", mock.Anything).Return(l)
+			l.On("With", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(l)
+			l.On("Info", mock.Anything)
+
+			// The IP address counted by the login throttling and the one recorded in the request info are the same
+			userUsecase := &mocks.UserUsecaseInterface{}
+			userUsecase.On("Login", mock.MatchedBy(func(ctx context.Context) bool {
+				return helper.GetRequestInfo(ctx).IPAddress == tc.wantIPAddress
+			}), tc.wantIPAddress, mock.Anything, mock.Anything).Return(&entity.LoginResponse{}, nil)
+
+			r, err := v1.NewEngine(tc.trustedProxies)
+			assert.NoError(t, err)
+			v1.NewRouter(r, l, &mocks.BookUsecaseInterface{}, &mocks.OrderUsecaseInterface{}, userUsecase, &mocks.ExportUsecaseInterface{}, &mocks.ReviewUsecaseInterface{}, &mocks.ReadingListUsecaseInterface{}, &mocks.RecommendationUsecaseInterface{}, &mocks.SigningKeyUsecaseInterface{}, &mocks.APIKeyUsecaseInterface{}, &mocks.PrivacyUsecaseInterface{}, &mocks.AuditUsecaseInterface{}, &mocks.BlobStore{})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/v1/users/login", strings.NewReader(`{"email":"foo@bar.com","password":"password"}`))
+			req.RemoteAddr = "203.0.113.7:54321"
+			req.Header.Set("X-Forwarded-For", "198.51.100.9")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			userUsecase.AssertExpectations(t)
+		})
+	}
 }
@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+)
+
+type SigningKeyHandler struct {
+	Logger            logger.LoggerInterface
+	SigningKeyUsecase usecase.SigningKeyUsecaseInterface
+}
+
+func newSigningKeyHandler(handler *gin.RouterGroup, l logger.LoggerInterface, sku usecase.SigningKeyUsecaseInterface) {
+	r := &SigningKeyHandler{l, sku}
+
+	handler.GET("/jwks.json", r.GetJWKS)
+}
+
+// @Summary     Get JWKS
+// @Description An API to get the public keys verifying the access tokens, selected by the kid header of a token
+// @ID          get jwks
+// @Tags  	    User
+// @Produce     json
+// @Success     200 {object} auth.JWKS
+// @Failure     500 {object} response.ErrorBody
+// @Router      /.well-known/jwks.json [get]
+func (h *SigningKeyHandler) GetJWKS(c *gin.Context) {
+	jwks, err := h.SigningKeyUsecase.GetJWKS(c.Request.Context())
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, "http - v1 - signing key - GetJWKS: GetJWKS")
+		response.Error(c, err)
+
+		return
+	}
+
+	// The JWKS is served as is, verifiers expect the standard format rather than the API envelope
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(config.JWKSMaxAge.Seconds())))
+	c.JSON(http.StatusOK, jwks)
+}
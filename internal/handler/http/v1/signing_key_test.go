@@ -0,0 +1,62 @@
+package v1_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	httpv1 "github.com/satriowisnugroho/book-store/internal/handler/http/v1"
+	"github.com/satriowisnugroho/book-store/pkg/auth"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetJWKS(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uJWKSRes          *auth.JWKS
+		uJWKSErr          error
+		httpStatusCodeRes int
+		body              string
+	}{
+		{
+			name:              "failed to get jwks",
+			uJWKSErr:          errors.New("error get jwks"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			uJWKSRes:          &auth.JWKS{Keys: []auth.JWK{{KeyType: "OKP", KeyID: "kid", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519", X: "x"}}},
+			httpStatusCodeRes: http.StatusOK,
+			body:              `{"keys":[{"kty":"OKP","kid":"kid","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"x"}]}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request, _ = http.NewRequest("GET", "/.well-known/jwks.json", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			signingKeyUsecase := &testmock.SigningKeyUsecaseInterface{}
+			signingKeyUsecase.On("GetJWKS", mock.Anything).Return(tc.uJWKSRes, tc.uJWKSErr)
+
+			h := &httpv1.SigningKeyHandler{l, signingKeyUsecase}
+			h.GetJWKS(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+			if tc.body != "" {
+				assert.JSONEq(t, tc.body, w.Body.String())
+				assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package v1
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/satriowisnugroho/book-store/internal/entity"
@@ -16,13 +18,31 @@ type UserHandler struct {
 	UserUsecase usecase.UserUsecaseInterface
 }
 
-func newUserHandler(handler *gin.RouterGroup, l logger.LoggerInterface, uu usecase.UserUsecaseInterface) {
+func newUserHandler(handler *gin.RouterGroup, l logger.LoggerInterface, authMiddleware gin.HandlerFunc, sessionAuthMiddleware gin.HandlerFunc, uu usecase.UserUsecaseInterface) {
 	r := &UserHandler{l, uu}
 
 	h := handler.Group("/users")
 	{
 		h.POST("/register", r.Register)
 		h.POST("/login", r.Login)
+		h.POST("/login/mfa", r.VerifyLoginChallenge)
+		h.GET("/oidc/authorize", r.StartOIDCLogin)
+		h.POST("/oidc/callback", r.OIDCLogin)
+		h.POST("/refresh", r.RefreshToken)
+		h.POST("/logout", sessionAuthMiddleware, r.Logout)
+		h.POST("/password/forgot", r.ForgotPassword)
+		h.POST("/password/reset", r.ResetPassword)
+		h.GET("/verify", r.VerifyEmail)
+		h.POST("/verify/resend", authMiddleware, r.ResendVerificationEmail)
+		h.GET("/me", authMiddleware, r.GetProfile)
+		h.PATCH("/me", sessionAuthMiddleware, r.UpdateProfile)
+		h.POST("/me/password", sessionAuthMiddleware, r.ChangePassword)
+		h.DELETE("/me", sessionAuthMiddleware, r.DeleteAccount)
+		h.GET("/me/sessions", sessionAuthMiddleware, r.GetSessions)
+		h.DELETE("/me/sessions/:id", sessionAuthMiddleware, r.RevokeSession)
+		h.POST("/me/mfa/totp", sessionAuthMiddleware, r.EnrollTOTP)
+		h.POST("/me/mfa/totp/confirm", sessionAuthMiddleware, r.ConfirmTOTP)
+		h.POST("/me/mfa/totp/disable", sessionAuthMiddleware, r.DisableTOTP)
 	}
 }
 
@@ -42,7 +62,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 
 	var payload entity.RegisterPayload
 	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
-		h.Logger.Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
 		response.Error(c, err)
 
 		return
@@ -50,7 +70,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 
 	user, err := h.UserUsecase.CreateUser(c.Request.Context(), &payload)
 	if err != nil {
-		h.Logger.Error(err, fmt.Sprintf("%s: CreateUser", msg))
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: CreateUser", msg))
 		response.Error(c, err)
 
 		return
@@ -66,8 +86,9 @@ func (h *UserHandler) Register(c *gin.Context) {
 // @Accept      json
 // @Produce     json
 // @Param       request		body		entity.LoginPayload		true		"payload"
-// @Success     200 {object} response.SuccessBody{data=entity.User,meta=response.MetaInfo}
+// @Success     200 {object} response.SuccessBody{data=entity.LoginResponse,meta=response.MetaInfo}
 // @Failure     401 {object} response.ErrorBody
+// @Failure     429 {object} response.ErrorBody
 // @Failure     500 {object} response.ErrorBody
 // @Router      /users/login [post]
 func (h *UserHandler) Login(c *gin.Context) {
@@ -75,15 +96,15 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 	var payload entity.LoginPayload
 	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
-		h.Logger.Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
 		response.Error(c, err)
 
 		return
 	}
 
-	res, err := h.UserUsecase.Login(c.Request.Context(), &payload)
+	res, err := h.UserUsecase.Login(c.Request.Context(), c.ClientIP(), c.Request.UserAgent(), &payload)
 	if err != nil {
-		h.Logger.Error(err, fmt.Sprintf("%s: Login", msg))
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Login", msg))
 		response.Error(c, err)
 
 		return
@@ -91,3 +112,553 @@ func (h *UserHandler) Login(c *gin.Context) {
 
 	response.OK(c, res, "")
 }
+
+// @Summary     Verify Login Challenge
+// @Description An API to complete a login with two-factor authentication, the challenge token given by the login is exchanged together with a TOTP code or a recovery code
+// @ID          verify login challenge
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Param       request		body		entity.LoginChallengePayload		true		"payload"
+// @Success     200 {object} response.SuccessBody{data=entity.LoginResponse,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     429 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/login/mfa [post]
+func (h *UserHandler) VerifyLoginChallenge(c *gin.Context) {
+	msg := "http - v1 - User - VerifyLoginChallenge"
+
+	var payload entity.LoginChallengePayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	res, err := h.UserUsecase.VerifyLoginChallenge(c.Request.Context(), c.ClientIP(), c.Request.UserAgent(), &payload)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: VerifyLoginChallenge", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, res, "")
+}
+
+// @Summary     Start OpenID Connect Login
+// @Description An API to start a login at the OpenID Connect provider, the user is sent to the returned authorization URL
+// @ID          start oidc login
+// @Tags  	    User
+// @Produce     json
+// @Success     200 {object} response.SuccessBody{data=entity.OIDCAuthorization,meta=response.MetaInfo}
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/oidc/authorize [get]
+func (h *UserHandler) StartOIDCLogin(c *gin.Context) {
+	msg := "http - v1 - User - StartOIDCLogin"
+
+	res, err := h.UserUsecase.StartOIDCLogin(c.Request.Context())
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: StartOIDCLogin", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, res, "")
+}
+
+// @Summary     OpenID Connect Login
+// @Description An API to complete a login at the OpenID Connect provider with the state and code it redirected back with. An account of the provider is linked to the user of its verified email, or a new user is registered
+// @ID          oidc login
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Param       request		body		entity.OIDCCallbackPayload		true		"payload"
+// @Success     200 {object} response.SuccessBody{data=entity.LoginResponse,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     403 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     409 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/oidc/callback [post]
+func (h *UserHandler) OIDCLogin(c *gin.Context) {
+	msg := "http - v1 - User - OIDCLogin"
+
+	var payload entity.OIDCCallbackPayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	res, err := h.UserUsecase.OIDCLogin(c.Request.Context(), c.ClientIP(), c.Request.UserAgent(), &payload)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: OIDCLogin", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, res, "")
+}
+
+// @Summary     Refresh Token
+// @Description An API to exchange a refresh token for a new access token and refresh token
+// @ID          refresh token
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Param       request		body		entity.RefreshTokenPayload		true		"payload"
+// @Success     200 {object} response.SuccessBody{data=entity.LoginResponse,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/refresh [post]
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	msg := "http - v1 - User - RefreshToken"
+
+	var payload entity.RefreshTokenPayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	res, err := h.UserUsecase.RefreshToken(c.Request.Context(), c.ClientIP(), &payload)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: RefreshToken", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, res, "")
+}
+
+// @Summary     Logout
+// @Description An API to revoke the access token, and the refresh token when given
+// @ID          logout
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Param       request		body		entity.RefreshTokenPayload		false		"payload"
+// @Success     200 {object} response.SuccessBody{meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/logout [post]
+func (h *UserHandler) Logout(c *gin.Context) {
+	msg := "http - v1 - User - Logout"
+
+	// The refresh token is optional, so an empty body is fine
+	var payload entity.RefreshTokenPayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil && err != io.EOF {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	if err := h.UserUsecase.Logout(c, &payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Logout", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, nil, "Successfully logged out")
+}
+
+// @Summary     Forgot Password
+// @Description An API to email a password reset link, it succeeds whether the email is registered or not
+// @ID          forgot password
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Param       request		body		entity.ForgotPasswordPayload		true		"payload"
+// @Success     200 {object} response.SuccessBody{meta=response.MetaInfo}
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/password/forgot [post]
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	msg := "http - v1 - User - ForgotPassword"
+
+	var payload entity.ForgotPasswordPayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	if err := h.UserUsecase.ForgotPassword(c.Request.Context(), &payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: ForgotPassword", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, nil, "If the email is registered, a password reset link has been sent")
+}
+
+// @Summary     Reset Password
+// @Description An API to set a new password with a password reset token, every session of the user is logged out
+// @ID          reset password
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Param       request		body		entity.ResetPasswordPayload		true		"payload"
+// @Success     200 {object} response.SuccessBody{meta=response.MetaInfo}
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/password/reset [post]
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	msg := "http - v1 - User - ResetPassword"
+
+	var payload entity.ResetPasswordPayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	if err := h.UserUsecase.ResetPassword(c.Request.Context(), &payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: ResetPassword", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, nil, "Successfully reset password")
+}
+
+// @Summary     Verify Email
+// @Description An API to verify the email of the user with the token of the verification email
+// @ID          verify email
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Param       token 			query		string 		true 		"email verification token"
+// @Success     200 {object} response.SuccessBody{meta=response.MetaInfo}
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/verify [get]
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	msg := "http - v1 - User - VerifyEmail"
+
+	if err := h.UserUsecase.VerifyEmail(c.Request.Context(), c.Query("token")); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: VerifyEmail", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, nil, "Successfully verified email")
+}
+
+// @Summary     Resend Verification Email
+// @Description An API to email a new verification link to the logged in user, the emails are throttled
+// @ID          resend verification email
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Success     200 {object} response.SuccessBody{meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     429 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/verify/resend [post]
+func (h *UserHandler) ResendVerificationEmail(c *gin.Context) {
+	msg := "http - v1 - User - ResendVerificationEmail"
+
+	if err := h.UserUsecase.ResendVerificationEmail(c); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: ResendVerificationEmail", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, nil, "Successfully sent a verification email")
+}
+
+// @Summary     Get Profile
+// @Description An API to get the logged in user
+// @ID          get profile
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Success     200 {object} response.SuccessBody{data=entity.User,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/me [get]
+func (h *UserHandler) GetProfile(c *gin.Context) {
+	msg := "http - v1 - User - GetProfile"
+
+	user, err := h.UserUsecase.GetProfile(c)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: GetProfile", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, user, "")
+}
+
+// @Summary     Update Profile
+// @Description An API to change the fullname and email of the logged in user. A new email needs the current_password and replaces the email once it is verified with the link emailed to it
+// @ID          update profile
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Param       request		body		entity.UpdateProfilePayload		true		"payload"
+// @Success     200 {object} response.SuccessBody{data=entity.User,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     429 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/me [patch]
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	msg := "http - v1 - User - UpdateProfile"
+
+	var payload entity.UpdateProfilePayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	user, err := h.UserUsecase.UpdateProfile(c, &payload)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: UpdateProfile", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, user, "Successfully update profile")
+}
+
+// @Summary     Change Password
+// @Description An API to change the password of the logged in user, the other sessions of the user are logged out
+// @ID          change password
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Param       request		body		entity.ChangePasswordPayload		true		"payload"
+// @Success     200 {object} response.SuccessBody{meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/me/password [post]
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	msg := "http - v1 - User - ChangePassword"
+
+	var payload entity.ChangePasswordPayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	if err := h.UserUsecase.ChangePassword(c, &payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: ChangePassword", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, nil, "Successfully change password")
+}
+
+// @Summary     Delete Account
+// @Description An API to delete the account of the logged in user, every session of the user is logged out
+// @ID          delete account
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Success     200 {object} response.SuccessBody{meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/me [delete]
+func (h *UserHandler) DeleteAccount(c *gin.Context) {
+	msg := "http - v1 - User - DeleteAccount"
+
+	if err := h.UserUsecase.DeleteAccount(c); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: DeleteAccount", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, nil, "Successfully delete account")
+}
+
+// @Summary     Get Sessions
+// @Description An API to list the active sessions of the logged in user, the session of the request is marked as current
+// @ID          get sessions
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Success     200 {object} response.SuccessBody{data=[]entity.Session,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/me/sessions [get]
+func (h *UserHandler) GetSessions(c *gin.Context) {
+	msg := "http - v1 - User - GetSessions"
+
+	res, err := h.UserUsecase.GetSessions(c)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: GetSessions", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, res, "")
+}
+
+// @Summary     Revoke Session
+// @Description An API to sign a session of the logged in user out, its refresh token and access tokens stop working
+// @ID          revoke session
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Param       id 				path		integer 	true		"session ID"
+// @Success     200 {object} response.SuccessBody{meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     404 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/me/sessions/{id} [delete]
+func (h *UserHandler) RevokeSession(c *gin.Context) {
+	msg := "http - v1 - User - RevokeSession"
+
+	sessionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.Error(c, response.ErrNotFound)
+
+		return
+	}
+
+	if err := h.UserUsecase.RevokeSession(c, sessionID); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: RevokeSession", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, nil, "Successfully revoke session")
+}
+
+// @Summary     Enroll TOTP
+// @Description An API to start the TOTP enrolment of the logged in user, the secret has to be confirmed with a code before the login asks for one
+// @ID          enroll totp
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Success     200 {object} response.SuccessBody{data=entity.TOTPEnrollment,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/me/mfa/totp [post]
+func (h *UserHandler) EnrollTOTP(c *gin.Context) {
+	msg := "http - v1 - User - EnrollTOTP"
+
+	res, err := h.UserUsecase.EnrollTOTP(c)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: EnrollTOTP", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, res, "")
+}
+
+// @Summary     Confirm TOTP
+// @Description An API to enable TOTP for the logged in user with a code of the enrolled secret, the recovery codes are returned once
+// @ID          confirm totp
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Param       request		body		entity.TOTPCodePayload		true		"payload"
+// @Success     200 {object} response.SuccessBody{data=entity.RecoveryCodesResponse,meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/me/mfa/totp/confirm [post]
+func (h *UserHandler) ConfirmTOTP(c *gin.Context) {
+	msg := "http - v1 - User - ConfirmTOTP"
+
+	var payload entity.TOTPCodePayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	res, err := h.UserUsecase.ConfirmTOTP(c, &payload)
+	if err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: ConfirmTOTP", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, res, "Successfully enable two-factor authentication")
+}
+
+// @Summary     Disable TOTP
+// @Description An API to turn off two-factor authentication of the logged in user given the password
+// @ID          disable totp
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Security		BearerAuth
+// @Param       request		body		entity.DisableTOTPPayload		true		"payload"
+// @Success     200 {object} response.SuccessBody{meta=response.MetaInfo}
+// @Failure     401 {object} response.ErrorBody
+// @Failure     422 {object} response.ErrorBody
+// @Failure     500 {object} response.ErrorBody
+// @Router      /users/me/mfa/totp/disable [post]
+func (h *UserHandler) DisableTOTP(c *gin.Context) {
+	msg := "http - v1 - User - DisableTOTP"
+
+	var payload entity.DisableTOTPPayload
+	if err := json.NewDecoder(c.Request.Body).Decode(&payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: Decode payload", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	if err := h.UserUsecase.DisableTOTP(c, &payload); err != nil {
+		h.Logger.WithContext(c.Request.Context()).Error(err, fmt.Sprintf("%s: DisableTOTP", msg))
+		response.Error(c, err)
+
+		return
+	}
+
+	response.OK(c, nil, "Successfully disable two-factor authentication")
+}
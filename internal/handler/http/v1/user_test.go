@@ -11,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/satriowisnugroho/book-store/internal/entity"
 	httpv1 "github.com/satriowisnugroho/book-store/internal/handler/http/v1"
+	"github.com/satriowisnugroho/book-store/internal/response"
 	testmock "github.com/satriowisnugroho/book-store/test/mock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -56,6 +57,7 @@ func TestRegister(t *testing.T) {
 
 			l := &testmock.LoggerInterface{}
 			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
 
 			orderUsecase := &testmock.UserUsecaseInterface{}
 			orderUsecase.On("CreateUser", mock.Anything, mock.Anything).Return(tc.uUserRes, tc.uUserErr)
@@ -108,9 +110,10 @@ func TestLogin(t *testing.T) {
 
 			l := &testmock.LoggerInterface{}
 			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
 
 			orderUsecase := &testmock.UserUsecaseInterface{}
-			orderUsecase.On("Login", mock.Anything, mock.Anything).Return(tc.uUserRes, tc.uUserErr)
+			orderUsecase.On("Login", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.uUserRes, tc.uUserErr)
 
 			h := &httpv1.UserHandler{l, orderUsecase}
 			h.Login(ctx)
@@ -119,3 +122,897 @@ func TestLogin(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyLoginChallenge(t *testing.T) {
+	testcases := []struct {
+		name              string
+		body              string
+		uUserRes          *entity.LoginResponse
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to decode payload",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "invalid code",
+			body:              `{"challenge_token":"token","code":"123456"}`,
+			uUserErr:          response.ErrInvalidMFACode,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to verify login challenge",
+			body:              `{"challenge_token":"token","code":"123456"}`,
+			uUserErr:          errors.New("error verify login challenge"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			body:              `{"challenge_token":"token","code":"123456"}`,
+			uUserRes:          &entity.LoginResponse{AccessToken: "anaccesstoken"},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/users/login/mfa", strings.NewReader(tc.body))
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("VerifyLoginChallenge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.uUserRes, tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.VerifyLoginChallenge(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestStartOIDCLogin(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uUserRes          *entity.OIDCAuthorization
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "OpenID Connect login disabled",
+			uUserErr:          response.ErrOIDCNotEnabled,
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to start login",
+			uUserErr:          errors.New("error start login"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			uUserRes:          &entity.OIDCAuthorization{AuthorizationURL: "https://accounts.example.com/authorize"},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("GET", "/users/oidc/authorize", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("StartOIDCLogin", mock.Anything).Return(tc.uUserRes, tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.StartOIDCLogin(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestOIDCLogin(t *testing.T) {
+	testcases := []struct {
+		name              string
+		body              string
+		uUserRes          *entity.LoginResponse
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to decode payload",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "invalid state",
+			body:              `{"state":"state","code":"code"}`,
+			uUserErr:          response.ErrUnauthorized("Invalid or expired login state"),
+			httpStatusCodeRes: http.StatusUnauthorized,
+		},
+		{
+			name:              "account not linkable",
+			body:              `{"state":"state","code":"code"}`,
+			uUserErr:          response.ErrOIDCAccountNotLinkable,
+			httpStatusCodeRes: http.StatusConflict,
+		},
+		{
+			name:              "failed to login",
+			body:              `{"state":"state","code":"code"}`,
+			uUserErr:          errors.New("error login"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			body:              `{"state":"state","code":"code"}`,
+			uUserRes:          &entity.LoginResponse{AccessToken: "anaccesstoken"},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/users/oidc/callback", strings.NewReader(tc.body))
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("OIDCLogin", mock.Anything, mock.Anything, mock.Anything, &entity.OIDCCallbackPayload{State: "state", Code: "code"}).Return(tc.uUserRes, tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.OIDCLogin(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	testcases := []struct {
+		name              string
+		body              string
+		uUserRes          *entity.LoginResponse
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to decode payload",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "failed to refresh token",
+			body:              `{"refresh_token":"arefreshtoken"}`,
+			uUserErr:          response.ErrUnauthorized("Invalid refresh token"),
+			httpStatusCodeRes: http.StatusUnauthorized,
+		},
+		{
+			name:              "success",
+			body:              `{"refresh_token":"arefreshtoken"}`,
+			uUserRes:          &entity.LoginResponse{AccessToken: "anaccesstoken", RefreshToken: "anotherrefreshtoken"},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request = &http.Request{
+				Header: make(http.Header),
+				Method: "POST",
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("RefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(tc.uUserRes, tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.RefreshToken(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	testcases := []struct {
+		name              string
+		body              string
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to decode payload",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "failed to logout",
+			body:              `{}`,
+			uUserErr:          errors.New("error logout"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success without body",
+			body:              ``,
+			httpStatusCodeRes: http.StatusOK,
+		},
+		{
+			name:              "success with refresh token",
+			body:              `{"refresh_token":"arefreshtoken"}`,
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request = &http.Request{
+				Header: make(http.Header),
+				Method: "POST",
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("Logout", mock.Anything, mock.Anything).Return(tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.Logout(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestForgotPassword(t *testing.T) {
+	testcases := []struct {
+		name              string
+		body              string
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to decode payload",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "invalid payload",
+			body:              `{}`,
+			uUserErr:          response.ErrInvalidEmail,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "success",
+			body:              `{"email":"foo@bar.com"}`,
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request = &http.Request{
+				Header: make(http.Header),
+				Method: "POST",
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("ForgotPassword", mock.Anything, mock.Anything).Return(tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.ForgotPassword(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	testcases := []struct {
+		name              string
+		body              string
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to decode payload",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "invalid payload",
+			body:              `{}`,
+			uUserErr:          response.ErrInvalidPasswordResetToken,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "success",
+			body:              `{"token":"atoken","password":"12345"}`,
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			ctx.Request = &http.Request{
+				Header: make(http.Header),
+				Method: "POST",
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("ResetPassword", mock.Anything, mock.Anything).Return(tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.ResetPassword(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid token",
+			uUserErr:          response.ErrInvalidEmailVerificationToken,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to verify email",
+			uUserErr:          errors.New("error verify email"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("GET", "/users/verify?token=atoken", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("VerifyEmail", mock.Anything, "atoken").Return(tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.VerifyEmail(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestResendVerificationEmail(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "too many verification emails",
+			uUserErr:          response.ErrTooManyVerificationEmails,
+			httpStatusCodeRes: http.StatusTooManyRequests,
+		},
+		{
+			name:              "failed to resend verification email",
+			uUserErr:          errors.New("error resend verification email"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/users/verify/resend", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("ResendVerificationEmail", mock.Anything).Return(tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.ResendVerificationEmail(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestGetProfile(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uUserRes          *entity.User
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "user not found",
+			uUserErr:          response.ErrNotFound,
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to get profile",
+			uUserErr:          errors.New("error get profile"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			uUserRes:          &entity.User{ID: 123},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("GET", "/users/me", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("GetProfile", mock.Anything).Return(tc.uUserRes, tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.GetProfile(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	testcases := []struct {
+		name              string
+		body              string
+		uUserRes          *entity.User
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to decode payload",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "duplicate email",
+			body:              `{"email":"foo@bar.com"}`,
+			uUserErr:          response.ErrDuplicateEmail,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to update profile",
+			body:              `{"fullname":"Foo Bar"}`,
+			uUserErr:          errors.New("error update profile"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			body:              `{"fullname":"Foo Bar"}`,
+			uUserRes:          &entity.User{ID: 123, Fullname: "Foo Bar"},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("PATCH", "/users/me", strings.NewReader(tc.body))
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("UpdateProfile", mock.Anything, mock.Anything).Return(tc.uUserRes, tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.UpdateProfile(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	testcases := []struct {
+		name              string
+		body              string
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to decode payload",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "wrong current password",
+			body:              `{"current_password":"wrong","new_password":"12345"}`,
+			uUserErr:          response.ErrInvalidPassword,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to change password",
+			body:              `{"current_password":"54321","new_password":"12345"}`,
+			uUserErr:          errors.New("error change password"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			body:              `{"current_password":"54321","new_password":"12345"}`,
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/users/me/password", strings.NewReader(tc.body))
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("ChangePassword", mock.Anything, mock.Anything).Return(tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.ChangePassword(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "user not found",
+			uUserErr:          response.ErrNotFound,
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to delete account",
+			uUserErr:          errors.New("error delete account"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("DELETE", "/users/me", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("DeleteAccount", mock.Anything).Return(tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.DeleteAccount(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestGetSessions(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uSessionsRes      []*entity.Session
+		uSessionsErr      error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to get sessions",
+			uSessionsErr:      errors.New("error get sessions"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			uSessionsRes:      []*entity.Session{{ID: 7, Device: "Firefox on Linux", Current: true}},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("GET", "/users/me/sessions", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("GetSessions", mock.Anything).Return(tc.uSessionsRes, tc.uSessionsErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.GetSessions(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	testcases := []struct {
+		name              string
+		id                string
+		uSessionErr       error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "invalid session id",
+			id:                "abc",
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "session not found",
+			id:                "7",
+			uSessionErr:       response.ErrNotFound,
+			httpStatusCodeRes: http.StatusNotFound,
+		},
+		{
+			name:              "failed to revoke session",
+			id:                "7",
+			uSessionErr:       errors.New("error revoke session"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			id:                "7",
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("DELETE", "/users/me/sessions/"+tc.id, nil)
+			ctx.Params = gin.Params{{Key: "id", Value: tc.id}}
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("RevokeSession", mock.Anything, 7).Return(tc.uSessionErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.RevokeSession(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestEnrollTOTP(t *testing.T) {
+	testcases := []struct {
+		name              string
+		uUserRes          *entity.TOTPEnrollment
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "TOTP already enabled",
+			uUserErr:          response.ErrTOTPAlreadyEnabled,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to enroll TOTP",
+			uUserErr:          errors.New("error enroll TOTP"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			uUserRes:          &entity.TOTPEnrollment{Secret: "secret", URI: "otpauth://totp/Book%20Store:foo@bar.com?secret=secret"},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/users/me/mfa/totp", nil)
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("EnrollTOTP", mock.Anything).Return(tc.uUserRes, tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.EnrollTOTP(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestConfirmTOTP(t *testing.T) {
+	testcases := []struct {
+		name              string
+		body              string
+		uUserRes          *entity.RecoveryCodesResponse
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to decode payload",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "invalid code",
+			body:              `{"code":"123456"}`,
+			uUserErr:          response.ErrInvalidMFACode,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to confirm TOTP",
+			body:              `{"code":"123456"}`,
+			uUserErr:          errors.New("error confirm TOTP"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			body:              `{"code":"123456"}`,
+			uUserRes:          &entity.RecoveryCodesResponse{RecoveryCodes: []string{"abcd-efgh-ijkl-mnop"}},
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/users/me/mfa/totp/confirm", strings.NewReader(tc.body))
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("ConfirmTOTP", mock.Anything, mock.Anything).Return(tc.uUserRes, tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.ConfirmTOTP(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
+
+func TestDisableTOTP(t *testing.T) {
+	testcases := []struct {
+		name              string
+		body              string
+		uUserErr          error
+		httpStatusCodeRes int
+	}{
+		{
+			name:              "failed to decode payload",
+			body:              `{failed}`,
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "wrong password",
+			body:              `{"password":"wrong"}`,
+			uUserErr:          response.ErrInvalidPassword,
+			httpStatusCodeRes: http.StatusUnprocessableEntity,
+		},
+		{
+			name:              "failed to disable TOTP",
+			body:              `{"password":"12345"}`,
+			uUserErr:          errors.New("error disable TOTP"),
+			httpStatusCodeRes: http.StatusInternalServerError,
+		},
+		{
+			name:              "success",
+			body:              `{"password":"12345"}`,
+			httpStatusCodeRes: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request, _ = http.NewRequest("POST", "/users/me/mfa/totp/disable", strings.NewReader(tc.body))
+
+			l := &testmock.LoggerInterface{}
+			l.On("Error", mock.Anything, mock.Anything)
+			l.On("WithContext", mock.Anything).Return(l)
+
+			userUsecase := &testmock.UserUsecaseInterface{}
+			userUsecase.On("DisableTOTP", mock.Anything, mock.Anything).Return(tc.uUserErr)
+
+			h := &httpv1.UserHandler{l, userUsecase}
+			h.DisableTOTP(ctx)
+
+			assert.Equal(t, tc.httpStatusCodeRes, w.Code)
+		})
+	}
+}
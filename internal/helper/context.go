@@ -1,6 +1,9 @@
 package helper
 
 import (
+	"context"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -11,3 +14,62 @@ func GetUserIDFromContext(c *gin.Context) int {
 
 	return userID
 }
+
+// GetUserRoleFromContext get the user role from context
+func GetUserRoleFromContext(c *gin.Context) string {
+	iRole, _ := c.Get("role")
+	role, _ := iRole.(string)
+
+	return role
+}
+
+// GetTokenIDFromContext get the ID of the access token from context
+func GetTokenIDFromContext(c *gin.Context) string {
+	iTokenID, _ := c.Get("jti")
+	tokenID, _ := iTokenID.(string)
+
+	return tokenID
+}
+
+// GetTokenExpiresAtFromContext get the expiry of the access token from context
+func GetTokenExpiresAtFromContext(c *gin.Context) time.Time {
+	iExpiresAt, _ := c.Get("token_expires_at")
+	expiresAt, _ := iExpiresAt.(time.Time)
+
+	return expiresAt
+}
+
+// GetSessionIDFromContext get the ID of the session of the access token from context
+func GetSessionIDFromContext(c *gin.Context) int {
+	iSessionID, _ := c.Get("session_id")
+	sessionID, _ := iSessionID.(int)
+
+	return sessionID
+}
+
+// RequestInfo holds who made a request and where it came from, it is carried by the context of the request for the audit log
+type RequestInfo struct {
+	RequestID string
+	IPAddress string
+	// ActorID is the authenticated user, zero before the authentication
+	ActorID int
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns a copy of ctx carrying the request info
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// GetRequestInfo get the request info from context, it is empty outside of a request such as in a background job.
+// A gin context only looks up its own keys, so the request info is read from the context of its request
+func GetRequestInfo(ctx context.Context) RequestInfo {
+	if c, ok := ctx.(*gin.Context); ok && c.Request != nil {
+		ctx = c.Request.Context()
+	}
+
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+
+	return info
+}
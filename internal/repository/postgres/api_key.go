@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	dbentity "github.com/satriowisnugroho/book-store/internal/repository/postgres/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+)
+
+// APIKeyRepositoryInterface define contract for API key related functions to repository
+type APIKeyRepositoryInterface interface {
+	CreateAPIKey(ctx context.Context, apiKey *entity.APIKey) error
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+	GetAPIKeysByUserID(ctx context.Context, userID int) ([]*entity.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID int, apiKeyID int) error
+	UpdateAPIKeyLastUsedAt(ctx context.Context, apiKeyID int, lastUsedAt time.Time) error
+}
+
+// APIKeyRepository holds database connection
+type APIKeyRepository struct {
+	db *sqlx.DB
+}
+
+var (
+	// APIKeyTableName hold table name for api_keys
+	APIKeyTableName = "api_keys"
+	// APIKeyColumns list all columns on api_keys table
+	APIKeyColumns = []string{"id", "user_id", "name", "key_prefix", "key_hash", "scopes", "last_used_at", "revoked_at", "created_at"}
+	// APIKeyAttributes hold string format of all api_keys table columns
+	APIKeyAttributes = strings.Join(APIKeyColumns, ", ")
+
+	// APIKeyCreationColumns list all columns used for create API key
+	APIKeyCreationColumns = []string{"user_id", "name", "key_prefix", "key_hash", "scopes", "created_at"}
+	// APIKeyCreationAttributes hold string format of all creation API key columns
+	APIKeyCreationAttributes = strings.Join(APIKeyCreationColumns, ", ")
+)
+
+// NewAPIKeyRepository create initiate API key repository with given database
+func NewAPIKeyRepository(db *sqlx.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (r *APIKeyRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]*entity.APIKey, error) {
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	result := make([]*entity.APIKey, 0)
+
+	for rows.Next() {
+		tmpEntity := dbentity.APIKey{}
+		if err := rows.StructScan(&tmpEntity); err != nil {
+			return nil, errors.Wrap(err, "fetch")
+		}
+
+		result = append(result, tmpEntity.ToEntity())
+	}
+
+	return result, nil
+}
+
+// CreateAPIKey insert API key data into database
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, apiKey *entity.APIKey) error {
+	functionName := "APIKeyRepository.CreateAPIKey"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	apiKey.CreatedAt = time.Now()
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING id`, APIKeyTableName, APIKeyCreationAttributes, EnumeratedBindvars(APIKeyCreationColumns))
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		apiKey.UserID,
+		apiKey.Name,
+		apiKey.Prefix,
+		apiKey.KeyHash,
+		pq.Array(apiKey.Scopes),
+		apiKey.CreatedAt,
+	).Scan(&apiKey.ID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// GetAPIKeyByHash query to get API key by the hash of the key
+func (r *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	functionName := "APIKeyRepository.GetAPIKeyByHash"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE key_hash = $1 LIMIT 1", APIKeyAttributes, APIKeyTableName)
+	rows, err := r.fetch(ctx, query, keyHash)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if len(rows) == 0 {
+		return nil, response.ErrNotFound
+	}
+
+	return rows[0], nil
+}
+
+// GetAPIKeysByUserID query to get the API keys of the user including the revoked ones, the newest first
+func (r *APIKeyRepository) GetAPIKeysByUserID(ctx context.Context, userID int) ([]*entity.APIKey, error) {
+	functionName := "APIKeyRepository.GetAPIKeysByUserID"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return []*entity.APIKey{}, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = $1 ORDER BY created_at DESC", APIKeyAttributes, APIKeyTableName)
+	rows, err := r.fetch(ctx, query, userID)
+	if err != nil {
+		return rows, errors.Wrap(err, functionName)
+	}
+
+	return rows, nil
+}
+
+// RevokeAPIKey revoke the API key of the user, ErrNotFound is returned when the user has no such API key left to revoke
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, userID int, apiKeyID int) error {
+	functionName := "APIKeyRepository.RevokeAPIKey"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET revoked_at = $1 WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL", APIKeyTableName)
+	result, err := r.db.ExecContext(ctx, query, time.Now(), apiKeyID, userID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if affected == 0 {
+		return response.ErrNotFound
+	}
+
+	return nil
+}
+
+// UpdateAPIKeyLastUsedAt update the time the API key was last used
+func (r *APIKeyRepository) UpdateAPIKeyLastUsedAt(ctx context.Context, apiKeyID int, lastUsedAt time.Time) error {
+	functionName := "APIKeyRepository.UpdateAPIKeyLastUsedAt"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET last_used_at = $1 WHERE id = $2", APIKeyTableName)
+	if _, err := r.db.ExecContext(ctx, query, lastUsedAt, apiKeyID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
@@ -0,0 +1,334 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	"github.com/stretchr/testify/assert"
+)
+
+func addAPIKeyRow(rows *sqlmock.Rows, apiKey *entity.APIKey) *sqlmock.Rows {
+	return rows.AddRow(
+		apiKey.ID,
+		apiKey.UserID,
+		apiKey.Name,
+		apiKey.Prefix,
+		apiKey.KeyHash,
+		"{read:catalog,write:orders}",
+		apiKey.LastUsedAt,
+		apiKey.RevokedAt,
+		apiKey.CreatedAt,
+	)
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		createErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail create",
+			ctx:       context.Background(),
+			createErr: errors.New("fail create"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("INSERT INTO api_keys \\(user_id, name, key_prefix, key_hash, scopes, created_at\\) VALUES (.+) RETURNING id").
+				WithArgs(2, "Catalog sync", "bsk_abcdefgh", "hash", sqlmock.AnyArg(), sqlmock.AnyArg())
+			if tc.createErr != nil {
+				mockExpectedQuery.WillReturnError(tc.createErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewAPIKeyRepository(dbx)
+			apiKey := &entity.APIKey{UserID: 2, Name: "Catalog sync", Prefix: "bsk_abcdefgh", KeyHash: "hash", Scopes: []string{"read:catalog"}}
+			err = repo.CreateAPIKey(tc.ctx, apiKey)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, 1, apiKey.ID)
+			}
+		})
+	}
+}
+
+func TestGetAPIKeyByHash(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  *entity.APIKey
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "not found",
+			ctx:       context.Background(),
+			fetchRows: postgres.APIKeyColumns,
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.APIKeyColumns,
+			expected:  &entity.APIKey{ID: 1, UserID: 2, Name: "Catalog sync", Prefix: "bsk_abcdefgh", KeyHash: "hash", Scopes: []string{"read:catalog", "write:orders"}},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM api_keys WHERE key_hash = \\$1 LIMIT 1").WithArgs("hash")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = addAPIKeyRow(rows, tc.expected)
+				}
+				if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewAPIKeyRepository(dbx)
+			result, err := repo.GetAPIKeyByHash(tc.ctx, "hash")
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestGetAPIKeysByUserID(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  []*entity.APIKey
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.APIKeyColumns,
+			expected:  []*entity.APIKey{{ID: 1, UserID: 2, Name: "Catalog sync", Prefix: "bsk_abcdefgh", KeyHash: "hash", Scopes: []string{"read:catalog", "write:orders"}}},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM api_keys WHERE user_id = \\$1 ORDER BY created_at DESC").WithArgs(2)
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				for _, apiKey := range tc.expected {
+					rows = addAPIKeyRow(rows, apiKey)
+				}
+				if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewAPIKeyRepository(dbx)
+			result, err := repo.GetAPIKeysByUserID(tc.ctx, 2)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	testcases := []struct {
+		name       string
+		ctx        context.Context
+		updateErr  error
+		affected   int64
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.CtxEnded(),
+			wantAnyErr: true,
+		},
+		{
+			name:       "fail update",
+			ctx:        context.Background(),
+			updateErr:  errors.New("fail update"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "API key not found",
+			ctx:        context.Background(),
+			affected:   0,
+			wantErr:    response.ErrNotFound,
+			wantAnyErr: true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			affected: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE api_keys SET revoked_at = \\$1 WHERE id = \\$2 AND user_id = \\$3 AND revoked_at IS NULL").WithArgs(sqlmock.AnyArg(), 1, 2)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, tc.affected))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewAPIKeyRepository(dbx)
+			err = repo.RevokeAPIKey(tc.ctx, 2, 1)
+			assert.Equal(t, tc.wantAnyErr, err != nil, err)
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestUpdateAPIKeyLastUsedAt(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		updateErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			lastUsedAt := time.Now()
+			mockExpectedExec := mock.ExpectExec("UPDATE api_keys SET last_used_at = \\$1 WHERE id = \\$2").WithArgs(lastUsedAt, 1)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewAPIKeyRepository(dbx)
+			err = repo.UpdateAPIKeyLastUsedAt(tc.ctx, 1, lastUsedAt)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	dbentity "github.com/satriowisnugroho/book-store/internal/repository/postgres/entity"
+)
+
+// AuditRepositoryInterface define contract for audit log related functions to repository
+type AuditRepositoryInterface interface {
+	CreateAuditEvent(ctx context.Context, dbTrx interface{}, event *entity.AuditEvent) error
+	GetAuditEvents(ctx context.Context, payload entity.GetAuditEventsPayload) ([]*entity.AuditEvent, error)
+	GetAuditEventsCount(ctx context.Context, payload entity.GetAuditEventsPayload) (int, error)
+	StreamAuditEvents(ctx context.Context, fn func(*entity.AuditEvent) error) error
+}
+
+// AuditRepository holds database connection
+type AuditRepository struct {
+	db *sqlx.DB
+}
+
+var (
+	// AuditEventTableName hold table name for audit_events
+	AuditEventTableName = "audit_events"
+	// AuditEventColumns list all columns on audit_events table
+	AuditEventColumns = []string{"id", "actor_id", "action", "target_type", "target_id", "request_id", "ip_address", "diff", "prev_hash", "hash", "created_at"}
+	// AuditEventAttributes hold string format of all audit_events table columns
+	AuditEventAttributes = strings.Join(AuditEventColumns, ", ")
+
+	// AuditEventCreationColumns list all columns used for create audit event
+	AuditEventCreationColumns = AuditEventColumns[1:]
+	// AuditEventCreationAttributes hold string format of all creation audit event columns
+	AuditEventCreationAttributes = strings.Join(AuditEventCreationColumns, ", ")
+)
+
+// auditEventsLockKey is the advisory lock key held while an audit event is appended,
+// so two events written at the same time are not both chained to the same previous event
+const auditEventsLockKey = 20240630
+
+// NewAuditRepository create initiate audit repository with given database
+func NewAuditRepository(db *sqlx.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]*entity.AuditEvent, error) {
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	result := make([]*entity.AuditEvent, 0)
+
+	for rows.Next() {
+		tmpEntity := dbentity.AuditEvent{}
+		if err := rows.StructScan(&tmpEntity); err != nil {
+			return nil, errors.Wrap(err, "fetch")
+		}
+
+		result = append(result, tmpEntity.ToEntity())
+	}
+
+	return result, nil
+}
+
+// CreateAuditEvent appends the audit event to the hash chain and inserts it. It has to run in a transaction,
+// the advisory lock keeping the chain in order is held until the transaction ends
+func (r *AuditRepository) CreateAuditEvent(ctx context.Context, dbTrx interface{}, event *entity.AuditEvent) error {
+	functionName := "AuditRepository.CreateAuditEvent"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	tx := Tx(r.db, dbTrx)
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", auditEventsLockKey); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT hash FROM %s ORDER BY id DESC LIMIT 1", AuditEventTableName)
+	err := tx.QueryRowxContext(ctx, query).Scan(&event.PrevHash)
+	if err == sql.ErrNoRows {
+		event.PrevHash = entity.AuditGenesisHash
+	} else if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	// The database keeps microseconds, the hash is computed over the time as it is stored
+	event.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	if len(event.Diff) == 0 {
+		event.Diff = []byte("{}")
+	}
+	event.Hash = event.ComputeHash()
+
+	query = fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING id`, AuditEventTableName, AuditEventCreationAttributes, EnumeratedBindvars(AuditEventCreationColumns))
+	err = tx.QueryRowxContext(
+		ctx,
+		query,
+		event.ActorID,
+		event.Action,
+		event.TargetType,
+		event.TargetID,
+		event.RequestID,
+		event.IPAddress,
+		string(event.Diff),
+		event.PrevHash,
+		event.Hash,
+		event.CreatedAt,
+	).Scan(&event.ID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// GetAuditEvents query to get audit events matching the payload filters, newest first
+func (r *AuditRepository) GetAuditEvents(ctx context.Context, payload entity.GetAuditEventsPayload) ([]*entity.AuditEvent, error) {
+	functionName := "AuditRepository.GetAuditEvents"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	filterQuery, args := r.constructSearchQuery(payload)
+	query := fmt.Sprintf("SELECT %s FROM %s %s ORDER BY id DESC LIMIT %d OFFSET %d", AuditEventAttributes, AuditEventTableName, filterQuery, payload.Limit, payload.Offset)
+	rows, err := r.fetch(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return rows, nil
+}
+
+// GetAuditEventsCount query to get the count of audit events matching the payload filters
+func (r *AuditRepository) GetAuditEventsCount(ctx context.Context, payload entity.GetAuditEventsPayload) (int, error) {
+	functionName := "AuditRepository.GetAuditEventsCount"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return 0, errors.Wrap(err, functionName)
+	}
+
+	filterQuery, args := r.constructSearchQuery(payload)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s %s", AuditEventTableName, filterQuery)
+
+	count := 0
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&count); err != nil {
+		return count, errors.Wrap(err, functionName)
+	}
+
+	return count, nil
+}
+
+// StreamAuditEvents query every audit event in the order they were written and pass them one by one to fn,
+// the rows are read from the connection as they are consumed instead of being loaded at once
+func (r *AuditRepository) StreamAuditEvents(ctx context.Context, fn func(*entity.AuditEvent) error) error {
+	functionName := "AuditRepository.StreamAuditEvents"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY id", AuditEventAttributes, AuditEventTableName)
+	rows, err := r.db.QueryxContext(ctx, query)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		tmpEntity := dbentity.AuditEvent{}
+		if err := rows.StructScan(&tmpEntity); err != nil {
+			return errors.Wrap(err, functionName)
+		}
+
+		if err := fn(tmpEntity.ToEntity()); err != nil {
+			return errors.Wrap(err, functionName)
+		}
+	}
+
+	return errors.Wrap(rows.Err(), functionName)
+}
+
+// constructSearchQuery construct the audit event filter query
+func (r *AuditRepository) constructSearchQuery(payload entity.GetAuditEventsPayload) (string, []interface{}) {
+	wheres := []string{}
+	args := []interface{}{}
+
+	if payload.Action != "" {
+		wheres = append(wheres, "action = ?")
+		args = append(args, payload.Action)
+	}
+
+	if payload.ActorID > 0 {
+		wheres = append(wheres, "actor_id = ?")
+		args = append(args, payload.ActorID)
+	}
+
+	if payload.TargetType != "" {
+		wheres = append(wheres, "target_type = ?")
+		args = append(args, payload.TargetType)
+	}
+
+	if payload.TargetID != "" {
+		wheres = append(wheres, "target_id = ?")
+		args = append(args, payload.TargetID)
+	}
+
+	if payload.From != nil {
+		wheres = append(wheres, "created_at >= ?")
+		args = append(args, *payload.From)
+	}
+
+	if payload.To != nil {
+		wheres = append(wheres, "created_at < ?")
+		args = append(args, *payload.To)
+	}
+
+	filterQuery := ""
+	if len(wheres) > 0 {
+		filterQuery = fmt.Sprintf("WHERE %s", strings.Join(wheres, " AND "))
+	}
+
+	// Rebind the query with $ bind type
+	return sqlx.Rebind(sqlx.DOLLAR, filterQuery), args
+}
@@ -0,0 +1,354 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	"github.com/stretchr/testify/assert"
+)
+
+func addAuditEventRow(rows *sqlmock.Rows, event *entity.AuditEvent) *sqlmock.Rows {
+	return rows.AddRow(
+		event.ID,
+		event.ActorID,
+		event.Action,
+		event.TargetType,
+		event.TargetID,
+		event.RequestID,
+		event.IPAddress,
+		[]byte(event.Diff),
+		event.PrevHash,
+		event.Hash,
+		event.CreatedAt,
+	)
+}
+
+func TestCreateAuditEvent(t *testing.T) {
+	testcases := []struct {
+		name         string
+		ctx          context.Context
+		lockErr      error
+		lastHashErr  error
+		lastHash     string
+		createErr    error
+		expectedPrev string
+		wantErr      bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "fail lock",
+			ctx:     context.Background(),
+			lockErr: errors.New("fail lock"),
+			wantErr: true,
+		},
+		{
+			name:        "fail get last hash",
+			ctx:         context.Background(),
+			lastHashErr: errors.New("fail get last hash"),
+			wantErr:     true,
+		},
+		{
+			name:      "fail create",
+			ctx:       context.Background(),
+			lastHash:  "last-hash",
+			createErr: errors.New("fail create"),
+			wantErr:   true,
+		},
+		{
+			name:         "success first event",
+			ctx:          context.Background(),
+			expectedPrev: entity.AuditGenesisHash,
+			wantErr:      false,
+		},
+		{
+			name:         "success",
+			ctx:          context.Background(),
+			lastHash:     "last-hash",
+			expectedPrev: "last-hash",
+			wantErr:      false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedLock := mock.ExpectExec("SELECT pg_advisory_xact_lock\\(\\$1\\)")
+			if tc.lockErr != nil {
+				mockExpectedLock.WillReturnError(tc.lockErr)
+			} else {
+				mockExpectedLock.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			mockExpectedLastHash := mock.ExpectQuery("SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1")
+			if tc.lastHashErr != nil {
+				mockExpectedLastHash.WillReturnError(tc.lastHashErr)
+			} else {
+				rows := sqlmock.NewRows([]string{"hash"})
+				if tc.lastHash != "" {
+					rows = rows.AddRow(tc.lastHash)
+				}
+
+				mockExpectedLastHash.WillReturnRows(rows)
+			}
+
+			mockExpectedQuery := mock.ExpectQuery("INSERT INTO audit_events \\(actor_id, action, target_type, target_id, request_id, ip_address, diff, prev_hash, hash, created_at\\) VALUES (.+) RETURNING id").
+				WithArgs(2, entity.AuditActionOrderCreated, entity.AuditTargetOrder, "1", "request-id", "127.0.0.1", `{"total_price":{"to":21000}}`, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg())
+			if tc.createErr != nil {
+				mockExpectedQuery.WillReturnError(tc.createErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewAuditRepository(dbx)
+			event := entity.NewAuditEvent(entity.AuditActionOrderCreated, entity.AuditTargetOrder, "1", entity.AuditDiff{"total_price": {To: 21000}})
+			event.ActorID = 2
+			event.RequestID = "request-id"
+			event.IPAddress = "127.0.0.1"
+			err = repo.CreateAuditEvent(tc.ctx, nil, event)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, 1, event.ID)
+				assert.Equal(t, tc.expectedPrev, event.PrevHash)
+				assert.Equal(t, event.ComputeHash(), event.Hash)
+				assert.Equal(t, event.CreatedAt, event.CreatedAt.Truncate(time.Microsecond))
+			}
+		})
+	}
+}
+
+func TestGetAuditEvents(t *testing.T) {
+	from := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		name        string
+		ctx         context.Context
+		fetchErr    error
+		fetchRows   []string
+		payload     entity.GetAuditEventsPayload
+		filterQuery string
+		expected    []*entity.AuditEvent
+		wantErr     bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.AuditEventColumns,
+			payload:   entity.GetAuditEventsPayload{Limit: 10},
+			expected:  []*entity.AuditEvent{{ID: 1, Action: entity.AuditActionLoginFailed, Diff: []byte("{}")}},
+			wantErr:   false,
+		},
+		{
+			name:        "success with filters",
+			ctx:         context.Background(),
+			fetchRows:   postgres.AuditEventColumns,
+			payload:     entity.GetAuditEventsPayload{Action: entity.AuditActionOrderCreated, ActorID: 2, TargetType: entity.AuditTargetOrder, TargetID: "1", From: &from, Limit: 10},
+			filterQuery: "WHERE action = \\$1 AND actor_id = \\$2 AND target_type = \\$3 AND target_id = \\$4 AND created_at >= \\$5 ",
+			expected:    []*entity.AuditEvent{{ID: 1, ActorID: 2, Action: entity.AuditActionOrderCreated, TargetType: entity.AuditTargetOrder, TargetID: "1", Diff: []byte("{}")}},
+			wantErr:     false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT .+ FROM audit_events " + tc.filterQuery + "ORDER BY id DESC LIMIT 10 OFFSET 0")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				for _, event := range tc.expected {
+					rows = addAuditEventRow(rows, event)
+				}
+				if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewAuditRepository(dbx)
+			if tc.payload.Limit == 0 {
+				tc.payload.Limit = 10
+			}
+			result, err := repo.GetAuditEvents(tc.ctx, tc.payload)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetAuditEventsCount(t *testing.T) {
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		fetchErr error
+		expected int
+		wantErr  bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			expected: 3,
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM audit_events WHERE action = \\$1").WithArgs(entity.AuditActionLoginFailed)
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tc.expected))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewAuditRepository(dbx)
+			result, err := repo.GetAuditEventsCount(tc.ctx, entity.GetAuditEventsPayload{Action: entity.AuditActionLoginFailed})
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestStreamAuditEvents(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		fnErr     error
+		expected  []*entity.AuditEvent
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "fail callback",
+			ctx:       context.Background(),
+			fetchRows: postgres.AuditEventColumns,
+			fnErr:     errors.New("fail verify"),
+			expected:  []*entity.AuditEvent{{ID: 1, Diff: []byte("{}")}},
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.AuditEventColumns,
+			expected:  []*entity.AuditEvent{{ID: 1, Diff: []byte("{}")}, {ID: 2, Diff: []byte("{}")}},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT .+ FROM audit_events ORDER BY id")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				for _, event := range tc.expected {
+					rows = addAuditEventRow(rows, event)
+				}
+				if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewAuditRepository(dbx)
+
+			result := []*entity.AuditEvent{}
+			err = repo.StreamAuditEvents(tc.ctx, func(event *entity.AuditEvent) error {
+				result = append(result, event)
+				return tc.fnErr
+			})
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}
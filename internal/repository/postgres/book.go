@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/satriowisnugroho/book-store/internal/entity"
 	"github.com/satriowisnugroho/book-store/internal/helper"
@@ -18,6 +20,15 @@ type BookRepositoryInterface interface {
 	GetBooks(ctx context.Context, payload entity.GetBooksPayload) ([]*entity.Book, error)
 	GetBooksCount(ctx context.Context, payload entity.GetBooksPayload) (int, error)
 	GetBookByID(ctx context.Context, bookID int) (*entity.Book, error)
+	GetBooksByIDs(ctx context.Context, bookIDs []int) ([]*entity.Book, error)
+	GetBookByIsbn(ctx context.Context, isbn string) (*entity.Book, error)
+	UpsertBook(ctx context.Context, dbTrx interface{}, book *entity.Book) (int, bool, error)
+	StreamBooks(ctx context.Context, payload entity.GetBooksPayload, fn func(*entity.Book) error) error
+	UpdateBookCoverKey(ctx context.Context, bookID int, coverKey string) error
+	UpdateBookRating(ctx context.Context, dbTrx interface{}, bookID int) error
+	RefreshBestsellers(ctx context.Context, dbTrx interface{}) (bool, error)
+	GetBestsellers(ctx context.Context, payload entity.GetBestsellersPayload) ([]*entity.Bestseller, error)
+	GetBestsellersCount(ctx context.Context, payload entity.GetBestsellersPayload) (int, error)
 }
 
 // BookRepository holds database connection
@@ -29,11 +40,29 @@ var (
 	// BookTableName hold table name for books
 	BookTableName = "books"
 	// BookColumns list all columns on books table
-	BookColumns = []string{"id", "isbn", "title", "price", "created_at", "updated_at"}
+	BookColumns = []string{"id", "isbn", "title", "price", "category", "cover_key", "rating_average", "rating_count", "created_at", "updated_at"}
 	// BookAttributes hold string format of all books table columns
 	BookAttributes = strings.Join(BookColumns, ", ")
+
+	// BookCreationColumns list all columns used for create book
+	BookCreationColumns = BookColumns[1:]
+	// BookCreationAttributes hold string format of all creation book columns
+	BookCreationAttributes = strings.Join(BookCreationColumns, ", ")
+
+	// BookBestsellerTableName hold table name for book_bestsellers
+	BookBestsellerTableName = "book_bestsellers"
+
+	// bookSortQueries maps the book sort options to their order clause
+	bookSortQueries = map[string]string{
+		entity.BookSortRating:     "ORDER BY rating_average DESC, rating_count DESC, id",
+		entity.BookSortPopularity: "ORDER BY (SELECT units_sold FROM book_bestsellers WHERE book_id = books.id AND period = 'all-time') DESC NULLS LAST, id",
+	}
 )
 
+// bookBestsellersLockKey is the advisory lock key held while the bestsellers are refreshed,
+// so several API instances running the refresh job do not recompute them at the same time
+const bookBestsellersLockKey = 20240619
+
 // NewBookRepository create initiate book repository with given database
 func NewBookRepository(db *sqlx.DB) *BookRepository {
 	return &BookRepository{db: db}
@@ -70,7 +99,7 @@ func (r *BookRepository) GetBooks(ctx context.Context, payload entity.GetBooksPa
 	}
 
 	filterQuery, args := r.constructSearchQuery(payload)
-	query := fmt.Sprintf("SELECT %s FROM %s %s LIMIT %d OFFSET %d", BookAttributes, BookTableName, filterQuery, payload.Limit, payload.Offset)
+	query := fmt.Sprintf("SELECT %s FROM %s %s %s LIMIT %d OFFSET %d", BookAttributes, BookTableName, filterQuery, bookSortQueries[payload.SortBy], payload.Limit, payload.Offset)
 	rows, err := r.fetch(ctx, query, args...)
 	if err != nil {
 		return rows, errors.Wrap(err, functionName)
@@ -119,6 +148,266 @@ func (r *BookRepository) GetBookByID(ctx context.Context, bookID int) (*entity.B
 	return rows[0], nil
 }
 
+// GetBooksByIDs query to get the books with the given IDs, unknown IDs are skipped
+func (r *BookRepository) GetBooksByIDs(ctx context.Context, bookIDs []int) ([]*entity.Book, error) {
+	functionName := "BookRepository.GetBooksByIDs"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return []*entity.Book{}, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = ANY($1)", BookAttributes, BookTableName)
+	rows, err := r.fetch(ctx, query, pq.Array(bookIDs))
+	if err != nil {
+		return rows, errors.Wrap(err, functionName)
+	}
+
+	return rows, nil
+}
+
+// GetBookByIsbn query to get book by ISBN
+func (r *BookRepository) GetBookByIsbn(ctx context.Context, isbn string) (*entity.Book, error) {
+	functionName := "BookRepository.GetBookByIsbn"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE isbn = $1 LIMIT 1", BookAttributes, BookTableName)
+	rows, err := r.fetch(ctx, query, isbn)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if len(rows) == 0 {
+		return nil, response.ErrNotFound
+	}
+
+	return rows[0], nil
+}
+
+// UpsertBook insert a book or update the existing book with the same ISBN, an empty category keeps the current one.
+// It returns the price of the existing book before the update, zero for a new book, and true when a new book is inserted
+func (r *BookRepository) UpsertBook(ctx context.Context, dbTrx interface{}, book *entity.Book) (int, bool, error) {
+	functionName := "BookRepository.UpsertBook"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return 0, false, errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	book.CreatedAt = now
+	book.UpdatedAt = now
+
+	query := fmt.Sprintf(
+		`WITH previous AS (SELECT price FROM %s WHERE isbn = $1 FOR UPDATE) INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (isbn) DO UPDATE SET title = EXCLUDED.title, price = EXCLUDED.price, category = COALESCE(NULLIF(EXCLUDED.category, ''), %s.category), updated_at = EXCLUDED.updated_at RETURNING id, created_at, (xmax = 0) AS inserted, COALESCE((SELECT price FROM previous), 0) AS previous_price`,
+		BookTableName,
+		BookTableName,
+		BookCreationAttributes,
+		EnumeratedBindvars(BookCreationColumns),
+		BookTableName,
+	)
+
+	inserted := false
+	previousPrice := 0
+	tx := Tx(r.db, dbTrx)
+	err := tx.QueryRowxContext(
+		ctx,
+		query,
+		book.Isbn,
+		book.Title,
+		book.Price,
+		book.Category,
+		book.CoverKey,
+		book.RatingAverage,
+		book.RatingCount,
+		book.CreatedAt,
+		book.UpdatedAt,
+	).Scan(&book.ID, &book.CreatedAt, &inserted, &previousPrice)
+	if err != nil {
+		return 0, false, errors.Wrap(err, functionName)
+	}
+
+	return previousPrice, inserted, nil
+}
+
+// StreamBooks query every book matching the payload filters ordered by ID and pass them one by one to fn,
+// the rows are read from the connection as they are consumed instead of being loaded at once
+func (r *BookRepository) StreamBooks(ctx context.Context, payload entity.GetBooksPayload, fn func(*entity.Book) error) error {
+	functionName := "BookRepository.StreamBooks"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	filterQuery, args := r.constructSearchQuery(payload)
+	query := fmt.Sprintf("SELECT %s FROM %s %s ORDER BY id", BookAttributes, BookTableName, filterQuery)
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		tmpEntity := dbentity.Book{}
+		if err := rows.StructScan(&tmpEntity); err != nil {
+			return errors.Wrap(err, functionName)
+		}
+
+		if err := fn(tmpEntity.ToEntity()); err != nil {
+			return errors.Wrap(err, functionName)
+		}
+	}
+
+	return errors.Wrap(rows.Err(), functionName)
+}
+
+// UpdateBookCoverKey set the blob key of the book cover image
+func (r *BookRepository) UpdateBookCoverKey(ctx context.Context, bookID int, coverKey string) error {
+	functionName := "BookRepository.UpdateBookCoverKey"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET cover_key = $1, updated_at = $2 WHERE id = $3", BookTableName)
+	if _, err := r.db.ExecContext(ctx, query, coverKey, time.Now(), bookID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// UpdateBookRating recalculate the denormalized rating average and count of a book from its approved reviews
+func (r *BookRepository) UpdateBookRating(ctx context.Context, dbTrx interface{}, bookID int) error {
+	functionName := "BookRepository.UpdateBookRating"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	tx := Tx(r.db, dbTrx)
+
+	// Lock the book first so the aggregate below runs after concurrent reviews of the book are committed,
+	// otherwise two reviews written at the same time could each miss the other one
+	lockQuery := fmt.Sprintf("SELECT id FROM %s WHERE id = $1 FOR UPDATE", BookTableName)
+	if _, err := tx.ExecContext(ctx, lockQuery, bookID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET (rating_average, rating_count) = (SELECT COALESCE(ROUND(AVG(rating), 2), 0), COUNT(*) FROM %s WHERE book_id = $1 AND status = $2) WHERE id = $1",
+		BookTableName,
+		ReviewTableName,
+	)
+	if _, err := tx.ExecContext(ctx, query, bookID, entity.ReviewStatusApproved); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// RefreshBestsellers recompute the copies sold of every book for each bestseller period from the order items.
+// It returns false without refreshing when another refresh holds the lock
+func (r *BookRepository) RefreshBestsellers(ctx context.Context, dbTrx interface{}) (bool, error) {
+	functionName := "BookRepository.RefreshBestsellers"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	tx := Tx(r.db, dbTrx)
+
+	locked := false
+	if err := tx.QueryRowxContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", bookBestsellersLockKey).Scan(&locked); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	if !locked {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", BookBestsellerTableName)); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	for _, period := range entity.BestsellerPeriods {
+		windowQuery := ""
+		args := []interface{}{period, now}
+		if days := entity.BestsellerPeriodDays[period]; days > 0 {
+			windowQuery = "WHERE oi.created_at >= $3"
+			args = append(args, now.AddDate(0, 0, -days))
+		}
+
+		query := fmt.Sprintf(
+			`INSERT INTO %s (period, book_id, category, units_sold, updated_at)
+			SELECT $1, oi.book_id, b.category, SUM(oi.quantity), $2 FROM %s oi JOIN %s b ON b.id = oi.book_id %s
+			GROUP BY oi.book_id, b.category`,
+			BookBestsellerTableName,
+			OrderItemTableName,
+			BookTableName,
+			windowQuery,
+		)
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return false, errors.Wrap(err, functionName)
+		}
+	}
+
+	return true, nil
+}
+
+// GetBestsellers query to get the bestsellers of a period, the most copies sold first
+func (r *BookRepository) GetBestsellers(ctx context.Context, payload entity.GetBestsellersPayload) ([]*entity.Bestseller, error) {
+	functionName := "BookRepository.GetBestsellers"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return []*entity.Bestseller{}, errors.Wrap(err, functionName)
+	}
+
+	filterQuery, args := r.constructBestsellerQuery(payload)
+	query := fmt.Sprintf("SELECT book_id, units_sold FROM %s %s ORDER BY units_sold DESC, book_id LIMIT %d OFFSET %d", BookBestsellerTableName, filterQuery, payload.Limit, payload.Offset)
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return []*entity.Bestseller{}, errors.Wrap(err, functionName)
+	}
+
+	defer rows.Close()
+
+	result := make([]*entity.Bestseller, 0)
+
+	for rows.Next() {
+		bestseller := &entity.Bestseller{}
+		if err := rows.Scan(&bestseller.BookID, &bestseller.UnitsSold); err != nil {
+			return []*entity.Bestseller{}, errors.Wrap(err, functionName)
+		}
+
+		result = append(result, bestseller)
+	}
+
+	return result, nil
+}
+
+// GetBestsellersCount query to get the count of bestsellers of a period
+func (r *BookRepository) GetBestsellersCount(ctx context.Context, payload entity.GetBestsellersPayload) (int, error) {
+	functionName := "BookRepository.GetBestsellersCount"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return 0, errors.Wrap(err, functionName)
+	}
+
+	filterQuery, args := r.constructBestsellerQuery(payload)
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s %s", BookBestsellerTableName, filterQuery)
+
+	count := 0
+	if err := r.db.QueryRowxContext(ctx, query, args...).Scan(&count); err != nil {
+		return count, errors.Wrap(err, functionName)
+	}
+
+	return count, nil
+}
+
 // constructSearchQuery construct search query
 func (r *BookRepository) constructSearchQuery(payload entity.GetBooksPayload) (string, []interface{}) {
 	wheres := []string{}
@@ -139,3 +428,17 @@ func (r *BookRepository) constructSearchQuery(payload entity.GetBooksPayload) (s
 
 	return filterQuery, args
 }
+
+// constructBestsellerQuery construct the bestseller filter query, an empty category matches every category
+func (r *BookRepository) constructBestsellerQuery(payload entity.GetBestsellersPayload) (string, []interface{}) {
+	wheres := []string{"period = ?"}
+	args := []interface{}{payload.Period}
+
+	if payload.Category != "" {
+		wheres = append(wheres, "category = ?")
+		args = append(args, payload.Category)
+	}
+
+	// Rebind the query with $ bind type
+	return sqlx.Rebind(sqlx.DOLLAR, fmt.Sprintf("WHERE %s", strings.Join(wheres, " AND "))), args
+}
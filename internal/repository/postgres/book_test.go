@@ -2,7 +2,9 @@ package postgres_test
 
 import (
 	"context"
+	"database/sql/driver"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
@@ -21,6 +23,7 @@ func TestGetBooks(t *testing.T) {
 		fetchRows   []string
 		payload     entity.GetBooksPayload
 		filterQuery string
+		sortQuery   string
 		expected    []*entity.Book
 		wantErr     bool
 	}{
@@ -50,6 +53,24 @@ func TestGetBooks(t *testing.T) {
 			expected:    []*entity.Book{{}},
 			wantErr:     false,
 		},
+		{
+			name:      "success sort by rating",
+			ctx:       context.Background(),
+			fetchRows: postgres.BookColumns,
+			payload:   entity.GetBooksPayload{SortBy: entity.BookSortRating},
+			sortQuery: "ORDER BY rating_average DESC, rating_count DESC, id",
+			expected:  []*entity.Book{{RatingAverage: 4.5, RatingCount: 2}},
+			wantErr:   false,
+		},
+		{
+			name:      "success sort by popularity",
+			ctx:       context.Background(),
+			fetchRows: postgres.BookColumns,
+			payload:   entity.GetBooksPayload{SortBy: entity.BookSortPopularity},
+			sortQuery: "ORDER BY \\(SELECT units_sold FROM book_bestsellers WHERE book_id = books.id AND period = 'all-time'\\) DESC NULLS LAST, id",
+			expected:  []*entity.Book{{Category: "Fantasy"}},
+			wantErr:   false,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -64,6 +85,9 @@ func TestGetBooks(t *testing.T) {
 			if tc.filterQuery != "" {
 				expectedQuery = expectedQuery + " WHERE " + tc.filterQuery
 			}
+			if tc.sortQuery != "" {
+				expectedQuery = expectedQuery + " +" + tc.sortQuery
+			}
 			expectedQuery = expectedQuery + " LIMIT .+ OFFSET .+"
 			mockExpectedQuery := mock.ExpectQuery(expectedQuery)
 
@@ -77,6 +101,10 @@ func TestGetBooks(t *testing.T) {
 						tc.expected[0].Isbn,
 						tc.expected[0].Title,
 						tc.expected[0].Price,
+						tc.expected[0].Category,
+						tc.expected[0].CoverKey,
+						tc.expected[0].RatingAverage,
+						tc.expected[0].RatingCount,
 						tc.expected[0].CreatedAt,
 						tc.expected[0].UpdatedAt,
 					)
@@ -223,6 +251,10 @@ func TestGetBookByID(t *testing.T) {
 						tc.expected.Isbn,
 						tc.expected.Title,
 						tc.expected.Price,
+						tc.expected.Category,
+						tc.expected.CoverKey,
+						tc.expected.RatingAverage,
+						tc.expected.RatingCount,
 						tc.expected.CreatedAt,
 						tc.expected.UpdatedAt,
 					)
@@ -243,3 +275,647 @@ func TestGetBookByID(t *testing.T) {
 		})
 	}
 }
+
+func TestGetBooksByIDs(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  []*entity.Book
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.BookColumns,
+			expected:  []*entity.Book{{ID: 1}, {ID: 2}},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM books WHERE id = ANY\\(\\$1\\)")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				for _, book := range tc.expected {
+					rows = rows.AddRow(
+						book.ID,
+						book.Isbn,
+						book.Title,
+						book.Price,
+						book.Category,
+						book.CoverKey,
+						book.RatingAverage,
+						book.RatingCount,
+						book.CreatedAt,
+						book.UpdatedAt,
+					)
+				}
+				if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewBookRepository(dbx)
+			result, err := repo.GetBooksByIDs(tc.ctx, []int{1, 2})
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetBookByIsbn(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  *entity.Book
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "record not found",
+			ctx:       context.Background(),
+			fetchRows: postgres.BookColumns,
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.BookColumns,
+			expected:  &entity.Book{Isbn: "9780545010221"},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM books WHERE isbn = .+ LIMIT 1")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = rows.AddRow(
+						tc.expected.ID,
+						tc.expected.Isbn,
+						tc.expected.Title,
+						tc.expected.Price,
+						tc.expected.Category,
+						tc.expected.CoverKey,
+						tc.expected.RatingAverage,
+						tc.expected.RatingCount,
+						tc.expected.CreatedAt,
+						tc.expected.UpdatedAt,
+					)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewBookRepository(dbx)
+			result, err := repo.GetBookByIsbn(tc.ctx, "9780545010221")
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestUpsertBook(t *testing.T) {
+	testcases := []struct {
+		name          string
+		ctx           context.Context
+		upsertErr     error
+		inserted      bool
+		previousPrice int
+		wantErr       bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail exec query",
+			ctx:       context.Background(),
+			upsertErr: errors.New("fail exec"),
+			wantErr:   true,
+		},
+		{
+			name:     "success insert",
+			ctx:      context.Background(),
+			inserted: true,
+			wantErr:  false,
+		},
+		{
+			name:          "success update",
+			ctx:           context.Background(),
+			inserted:      false,
+			previousPrice: 20000,
+			wantErr:       false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			expectedQuery := "WITH previous AS \\(SELECT price FROM books WHERE isbn = \\$1 FOR UPDATE\\) INSERT INTO books (.+) VALUES (.+) ON CONFLICT \\(isbn\\) DO UPDATE SET .+ RETURNING id, created_at, \\(xmax = 0\\) AS inserted, COALESCE\\(\\(SELECT price FROM previous\\), 0\\) AS previous_price"
+			if tc.upsertErr != nil {
+				mock.ExpectQuery(expectedQuery).WillReturnError(tc.upsertErr)
+			} else {
+				row := sqlmock.NewRows([]string{"id", "created_at", "inserted", "previous_price"})
+				result := row.AddRow(1, time.Now(), tc.inserted, tc.previousPrice)
+				mock.ExpectQuery(expectedQuery).WillReturnRows(result)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewBookRepository(dbx)
+
+			book := &entity.Book{Isbn: "9780545010221", Title: "Harry Potter", Price: 25000}
+			previousPrice, inserted, err := repo.UpsertBook(tc.ctx, nil, book)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				assert.Equal(t, 1, book.ID)
+				assert.Equal(t, tc.inserted, inserted)
+				assert.Equal(t, tc.previousPrice, previousPrice)
+			}
+		})
+	}
+}
+
+func TestStreamBooks(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		fnErr     error
+		expected  []*entity.Book
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "fail callback",
+			ctx:       context.Background(),
+			fetchRows: postgres.BookColumns,
+			fnErr:     errors.New("fail write"),
+			expected:  []*entity.Book{{ID: 1}},
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.BookColumns,
+			expected:  []*entity.Book{{ID: 1}, {ID: 2}},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT .+ FROM books WHERE title ILIKE \\$1 ORDER BY id")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				for _, book := range tc.expected {
+					rows = rows.AddRow(book.ID, book.Isbn, book.Title, book.Price, book.Category, book.CoverKey, book.RatingAverage, book.RatingCount, book.CreatedAt, book.UpdatedAt)
+				}
+				if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewBookRepository(dbx)
+
+			result := []*entity.Book{}
+			err = repo.StreamBooks(tc.ctx, entity.GetBooksPayload{TitleKeyword: "foo"}, func(book *entity.Book) error {
+				result = append(result, book)
+				return tc.fnErr
+			})
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestUpdateBookCoverKey(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		updateErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE books SET cover_key = \\$1, updated_at = \\$2 WHERE id = \\$3").
+				WithArgs("covers/1/1/original.png", sqlmock.AnyArg(), 1)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewBookRepository(dbx)
+			err = repo.UpdateBookCoverKey(tc.ctx, 1, "covers/1/1/original.png")
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestUpdateBookRating(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		lockErr   error
+		updateErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "fail lock",
+			ctx:     context.Background(),
+			lockErr: errors.New("fail lock"),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedLock := mock.ExpectExec("SELECT id FROM books WHERE id = \\$1 FOR UPDATE").WithArgs(1)
+			if tc.lockErr != nil {
+				mockExpectedLock.WillReturnError(tc.lockErr)
+			} else {
+				mockExpectedLock.WillReturnResult(sqlmock.NewResult(0, 1))
+
+				mockExpectedExec := mock.ExpectExec("UPDATE books SET \\(rating_average, rating_count\\) = \\(SELECT .+ FROM reviews WHERE book_id = \\$1 AND status = \\$2\\) WHERE id = \\$1").WithArgs(1, "approved")
+				if tc.updateErr != nil {
+					mockExpectedExec.WillReturnError(tc.updateErr)
+				} else {
+					mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewBookRepository(dbx)
+			err = repo.UpdateBookRating(tc.ctx, nil, 1)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestRefreshBestsellers(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		lockErr   error
+		locked    bool
+		deleteErr error
+		insertErr error
+		expected  bool
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "fail lock",
+			ctx:     context.Background(),
+			lockErr: errors.New("fail lock"),
+			wantErr: true,
+		},
+		{
+			name:     "another refresh holds the lock",
+			ctx:      context.Background(),
+			locked:   false,
+			expected: false,
+			wantErr:  false,
+		},
+		{
+			name:      "fail delete",
+			ctx:       context.Background(),
+			locked:    true,
+			deleteErr: errors.New("fail delete"),
+			wantErr:   true,
+		},
+		{
+			name:      "fail insert",
+			ctx:       context.Background(),
+			locked:    true,
+			insertErr: errors.New("fail insert"),
+			wantErr:   true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			locked:   true,
+			expected: true,
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedLock := mock.ExpectQuery("SELECT pg_try_advisory_xact_lock\\(\\$1\\)")
+			if tc.lockErr != nil {
+				mockExpectedLock.WillReturnError(tc.lockErr)
+			} else {
+				mockExpectedLock.WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(tc.locked))
+			}
+
+			mockExpectedDelete := mock.ExpectExec("DELETE FROM book_bestsellers")
+			if tc.deleteErr != nil {
+				mockExpectedDelete.WillReturnError(tc.deleteErr)
+			} else {
+				mockExpectedDelete.WillReturnResult(sqlmock.NewResult(0, 10))
+			}
+
+			for _, period := range entity.BestsellerPeriods {
+				expectedQuery := "INSERT INTO book_bestsellers \\(period, book_id, category, units_sold, updated_at\\) SELECT (.+) FROM order_items oi JOIN books b ON b.id = oi.book_id +GROUP BY"
+				args := []driver.Value{period, sqlmock.AnyArg()}
+				if period != entity.BestsellerPeriodAllTime {
+					expectedQuery = "INSERT INTO book_bestsellers (.+) WHERE oi.created_at >= \\$3"
+					args = append(args, sqlmock.AnyArg())
+				}
+
+				mockExpectedInsert := mock.ExpectExec(expectedQuery).WithArgs(args...)
+				if tc.insertErr != nil {
+					mockExpectedInsert.WillReturnError(tc.insertErr)
+					break
+				}
+				mockExpectedInsert.WillReturnResult(sqlmock.NewResult(0, 10))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewBookRepository(dbx)
+			refreshed, err := repo.RefreshBestsellers(tc.ctx, nil)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			assert.Equal(t, tc.expected, refreshed)
+		})
+	}
+}
+
+func TestGetBestsellers(t *testing.T) {
+	testcases := []struct {
+		name        string
+		ctx         context.Context
+		fetchErr    error
+		fetchRows   []string
+		payload     entity.GetBestsellersPayload
+		filterQuery string
+		expected    []*entity.Bestseller
+		wantErr     bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:        "fail fetch query error",
+			ctx:         context.Background(),
+			fetchErr:    errors.New("fail fetch"),
+			payload:     entity.GetBestsellersPayload{Period: entity.BestsellerPeriodWeek},
+			filterQuery: "period = \\$1",
+			wantErr:     true,
+		},
+		{
+			name:        "fail scan",
+			ctx:         context.Background(),
+			fetchRows:   []string{"book_id"},
+			payload:     entity.GetBestsellersPayload{Period: entity.BestsellerPeriodWeek},
+			filterQuery: "period = \\$1",
+			wantErr:     true,
+		},
+		{
+			name:        "success",
+			ctx:         context.Background(),
+			fetchRows:   []string{"book_id", "units_sold"},
+			payload:     entity.GetBestsellersPayload{Period: entity.BestsellerPeriodWeek, Category: "Fantasy"},
+			filterQuery: "period = \\$1 AND category = \\$2",
+			expected:    []*entity.Bestseller{{BookID: 8, UnitsSold: 5}},
+			wantErr:     false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT book_id, units_sold FROM book_bestsellers WHERE " + tc.filterQuery + " ORDER BY units_sold DESC, book_id LIMIT .+ OFFSET .+")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = rows.AddRow(tc.expected[0].BookID, tc.expected[0].UnitsSold)
+				} else if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewBookRepository(dbx)
+			result, err := repo.GetBestsellers(tc.ctx, tc.payload)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetBestsellersCount(t *testing.T) {
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		fetchErr error
+		expected int
+		wantErr  bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			expected: 10,
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM book_bestsellers WHERE period = \\$1").WithArgs(entity.BestsellerPeriodDay)
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tc.expected))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewBookRepository(dbx)
+			result, err := repo.GetBestsellersCount(tc.ctx, entity.GetBestsellersPayload{Period: entity.BestsellerPeriodDay})
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
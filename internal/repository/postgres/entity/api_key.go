@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+)
+
+// APIKey struct holds API key database representative
+type APIKey struct {
+	ID         int            `db:"id"`
+	UserID     int            `db:"user_id"`
+	Name       string         `db:"name"`
+	KeyPrefix  string         `db:"key_prefix"`
+	KeyHash    string         `db:"key_hash"`
+	Scopes     pq.StringArray `db:"scopes"`
+	LastUsedAt *time.Time     `db:"last_used_at"`
+	RevokedAt  *time.Time     `db:"revoked_at"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+// ToEntity to convert API key from database to entity contract
+func (e *APIKey) ToEntity() *entity.APIKey {
+	return &entity.APIKey{
+		ID:         e.ID,
+		UserID:     e.UserID,
+		Name:       e.Name,
+		Prefix:     e.KeyPrefix,
+		KeyHash:    e.KeyHash,
+		Scopes:     []string(e.Scopes),
+		LastUsedAt: e.LastUsedAt,
+		RevokedAt:  e.RevokedAt,
+		CreatedAt:  e.CreatedAt,
+	}
+}
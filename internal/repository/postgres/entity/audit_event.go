@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+)
+
+// AuditEvent struct holds audit event database representative
+type AuditEvent struct {
+	ID         int       `db:"id"`
+	ActorID    int       `db:"actor_id"`
+	Action     string    `db:"action"`
+	TargetType string    `db:"target_type"`
+	TargetID   string    `db:"target_id"`
+	RequestID  string    `db:"request_id"`
+	IPAddress  string    `db:"ip_address"`
+	Diff       []byte    `db:"diff"`
+	PrevHash   string    `db:"prev_hash"`
+	Hash       string    `db:"hash"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// ToEntity to convert audit event from database to entity contract
+func (e *AuditEvent) ToEntity() *entity.AuditEvent {
+	return &entity.AuditEvent{
+		ID:         e.ID,
+		ActorID:    e.ActorID,
+		Action:     e.Action,
+		TargetType: e.TargetType,
+		TargetID:   e.TargetID,
+		RequestID:  e.RequestID,
+		IPAddress:  e.IPAddress,
+		Diff:       json.RawMessage(e.Diff),
+		PrevHash:   e.PrevHash,
+		Hash:       e.Hash,
+		CreatedAt:  e.CreatedAt,
+	}
+}
@@ -8,22 +8,30 @@ import (
 
 // Book struct holds book database representative
 type Book struct {
-	ID        int       `db:"id"`
-	Isbn      string    `db:"isbn"`
-	Title     string    `db:"title"`
-	Price     int       `db:"price"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID            int       `db:"id"`
+	Isbn          string    `db:"isbn"`
+	Title         string    `db:"title"`
+	Price         int       `db:"price"`
+	Category      string    `db:"category"`
+	CoverKey      string    `db:"cover_key"`
+	RatingAverage float64   `db:"rating_average"`
+	RatingCount   int       `db:"rating_count"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
 }
 
 // ToEntity to convert book from database to entity contract
 func (e *Book) ToEntity() *entity.Book {
 	return &entity.Book{
-		ID:        e.ID,
-		Isbn:      e.Isbn,
-		Title:     e.Title,
-		Price:     e.Price,
-		CreatedAt: e.CreatedAt,
-		UpdatedAt: e.UpdatedAt,
+		ID:            e.ID,
+		Isbn:          e.Isbn,
+		Title:         e.Title,
+		Price:         e.Price,
+		Category:      e.Category,
+		CoverKey:      e.CoverKey,
+		RatingAverage: e.RatingAverage,
+		RatingCount:   e.RatingCount,
+		CreatedAt:     e.CreatedAt,
+		UpdatedAt:     e.UpdatedAt,
 	}
 }
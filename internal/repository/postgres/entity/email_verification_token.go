@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+)
+
+// EmailVerificationToken struct holds email verification token database representative
+type EmailVerificationToken struct {
+	ID        int        `db:"id"`
+	UserID    int        `db:"user_id"`
+	NewEmail  *string    `db:"new_email"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// ToEntity to convert email verification token from database to entity contract
+func (e *EmailVerificationToken) ToEntity() *entity.EmailVerificationToken {
+	return &entity.EmailVerificationToken{
+		ID:        e.ID,
+		UserID:    e.UserID,
+		NewEmail:  e.NewEmail,
+		TokenHash: e.TokenHash,
+		ExpiresAt: e.ExpiresAt,
+		UsedAt:    e.UsedAt,
+		CreatedAt: e.CreatedAt,
+	}
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+)
+
+// LoginChallenge struct holds login challenge database representative
+type LoginChallenge struct {
+	ID        int        `db:"id"`
+	UserID    int        `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// ToEntity to convert login challenge from database to entity contract
+func (e *LoginChallenge) ToEntity() *entity.LoginChallenge {
+	return &entity.LoginChallenge{
+		ID:        e.ID,
+		UserID:    e.UserID,
+		TokenHash: e.TokenHash,
+		ExpiresAt: e.ExpiresAt,
+		UsedAt:    e.UsedAt,
+		CreatedAt: e.CreatedAt,
+	}
+}
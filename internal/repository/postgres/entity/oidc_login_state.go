@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+)
+
+// OIDCLoginState struct holds OpenID Connect login state database representative
+type OIDCLoginState struct {
+	ID           int        `db:"id"`
+	StateHash    string     `db:"state_hash"`
+	Nonce        string     `db:"nonce"`
+	CodeVerifier string     `db:"code_verifier"`
+	ExpiresAt    time.Time  `db:"expires_at"`
+	UsedAt       *time.Time `db:"used_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+// ToEntity to convert OpenID Connect login state from database to entity contract
+func (e *OIDCLoginState) ToEntity() *entity.OIDCLoginState {
+	return &entity.OIDCLoginState{
+		ID:           e.ID,
+		StateHash:    e.StateHash,
+		Nonce:        e.Nonce,
+		CodeVerifier: e.CodeVerifier,
+		ExpiresAt:    e.ExpiresAt,
+		UsedAt:       e.UsedAt,
+		CreatedAt:    e.CreatedAt,
+	}
+}
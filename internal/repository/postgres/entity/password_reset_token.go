@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+)
+
+// PasswordResetToken struct holds password reset token database representative
+type PasswordResetToken struct {
+	ID        int        `db:"id"`
+	UserID    int        `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// ToEntity to convert password reset token from database to entity contract
+func (e *PasswordResetToken) ToEntity() *entity.PasswordResetToken {
+	return &entity.PasswordResetToken{
+		ID:        e.ID,
+		UserID:    e.UserID,
+		TokenHash: e.TokenHash,
+		ExpiresAt: e.ExpiresAt,
+		UsedAt:    e.UsedAt,
+		CreatedAt: e.CreatedAt,
+	}
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+)
+
+// ReadingList struct holds reading list database representative
+type ReadingList struct {
+	ID         int       `db:"id"`
+	UserID     int       `db:"user_id"`
+	Name       string    `db:"name"`
+	IsWishlist bool      `db:"is_wishlist"`
+	IsPublic   bool      `db:"is_public"`
+	ShareSlug  string    `db:"share_slug"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// ToEntity to convert reading list from database to entity contract
+func (e *ReadingList) ToEntity() *entity.ReadingList {
+	return &entity.ReadingList{
+		ID:         e.ID,
+		UserID:     e.UserID,
+		Name:       e.Name,
+		IsWishlist: e.IsWishlist,
+		IsPublic:   e.IsPublic,
+		ShareSlug:  e.ShareSlug,
+		Books:      []*entity.Book{},
+		CreatedAt:  e.CreatedAt,
+		UpdatedAt:  e.UpdatedAt,
+	}
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+)
+
+// RefreshToken struct holds refresh token database representative
+type RefreshToken struct {
+	ID        int        `db:"id"`
+	UserID    int        `db:"user_id"`
+	FamilyID  string     `db:"family_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
+
+// ToEntity to convert refresh token from database to entity contract
+func (e *RefreshToken) ToEntity() *entity.RefreshToken {
+	return &entity.RefreshToken{
+		ID:        e.ID,
+		UserID:    e.UserID,
+		FamilyID:  e.FamilyID,
+		TokenHash: e.TokenHash,
+		ExpiresAt: e.ExpiresAt,
+		RevokedAt: e.RevokedAt,
+		CreatedAt: e.CreatedAt,
+		UpdatedAt: e.UpdatedAt,
+	}
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+)
+
+// Review struct holds review database representative
+type Review struct {
+	ID               int       `db:"id"`
+	BookID           int       `db:"book_id"`
+	UserID           int       `db:"user_id"`
+	Rating           int       `db:"rating"`
+	Body             string    `db:"body"`
+	VerifiedPurchase bool      `db:"verified_purchase"`
+	Status           string    `db:"status"`
+	ReportCount      int       `db:"report_count"`
+	CreatedAt        time.Time `db:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at"`
+}
+
+// ToEntity to convert review from database to entity contract
+func (e *Review) ToEntity() *entity.Review {
+	return &entity.Review{
+		ID:               e.ID,
+		BookID:           e.BookID,
+		UserID:           e.UserID,
+		Rating:           e.Rating,
+		Body:             e.Body,
+		VerifiedPurchase: e.VerifiedPurchase,
+		Status:           e.Status,
+		ReportCount:      e.ReportCount,
+		CreatedAt:        e.CreatedAt,
+		UpdatedAt:        e.UpdatedAt,
+	}
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+)
+
+// ReviewReport struct holds review report database representative
+type ReviewReport struct {
+	ID        int       `db:"id"`
+	ReviewID  int       `db:"review_id"`
+	UserID    int       `db:"user_id"`
+	Reason    string    `db:"reason"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ToEntity to convert review report from database to entity contract
+func (e *ReviewReport) ToEntity() *entity.ReviewReport {
+	return &entity.ReviewReport{
+		ID:        e.ID,
+		ReviewID:  e.ReviewID,
+		UserID:    e.UserID,
+		Reason:    e.Reason,
+		CreatedAt: e.CreatedAt,
+	}
+}
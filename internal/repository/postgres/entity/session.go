@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+)
+
+// Session struct holds session database representative
+type Session struct {
+	ID         int        `db:"id"`
+	UserID     int        `db:"user_id"`
+	FamilyID   string     `db:"family_id"`
+	Device     string     `db:"device"`
+	UserAgent  string     `db:"user_agent"`
+	IPAddress  string     `db:"ip_address"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	LastSeenAt time.Time  `db:"last_seen_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+// ToEntity to convert session from database to entity contract
+func (e *Session) ToEntity() *entity.Session {
+	return &entity.Session{
+		ID:         e.ID,
+		UserID:     e.UserID,
+		FamilyID:   e.FamilyID,
+		Device:     e.Device,
+		UserAgent:  e.UserAgent,
+		IPAddress:  e.IPAddress,
+		ExpiresAt:  e.ExpiresAt,
+		RevokedAt:  e.RevokedAt,
+		LastSeenAt: e.LastSeenAt,
+		CreatedAt:  e.CreatedAt,
+	}
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+)
+
+// SigningKey struct holds signing key database representative
+type SigningKey struct {
+	ID         int       `db:"id"`
+	KeyID      string    `db:"key_id"`
+	Algorithm  string    `db:"algorithm"`
+	PrivateKey string    `db:"private_key"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// ToEntity to convert signing key from database to entity contract
+func (e *SigningKey) ToEntity() *entity.SigningKey {
+	return &entity.SigningKey{
+		ID:         e.ID,
+		KeyID:      e.KeyID,
+		Algorithm:  e.Algorithm,
+		PrivateKey: e.PrivateKey,
+		CreatedAt:  e.CreatedAt,
+	}
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+)
+
+// TOTPCredential struct holds TOTP credential database representative
+type TOTPCredential struct {
+	UserID       int        `db:"user_id"`
+	Secret       string     `db:"secret"`
+	EnabledAt    *time.Time `db:"enabled_at"`
+	LastUsedStep int64      `db:"last_used_step"`
+	CreatedAt    time.Time  `db:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+}
+
+// ToEntity to convert TOTP credential from database to entity contract
+func (e *TOTPCredential) ToEntity() *entity.TOTPCredential {
+	return &entity.TOTPCredential{
+		UserID:       e.UserID,
+		Secret:       e.Secret,
+		EnabledAt:    e.EnabledAt,
+		LastUsedStep: e.LastUsedStep,
+		CreatedAt:    e.CreatedAt,
+		UpdatedAt:    e.UpdatedAt,
+	}
+}
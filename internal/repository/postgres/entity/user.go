@@ -8,12 +8,15 @@ import (
 
 // User struct holds user database representative
 type User struct {
-	ID              int       `db:"id"`
-	Email           string    `db:"email"`
-	Fullname        string    `db:"fullname"`
-	CryptedPassword string    `db:"crypted_password"`
-	CreatedAt       time.Time `db:"created_at"`
-	UpdatedAt       time.Time `db:"updated_at"`
+	ID              int        `db:"id"`
+	Email           string     `db:"email"`
+	Fullname        string     `db:"fullname"`
+	CryptedPassword string     `db:"crypted_password"`
+	Role            string     `db:"role"`
+	VerifiedAt      *time.Time `db:"verified_at"`
+	DeletedAt       *time.Time `db:"deleted_at"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
 }
 
 // ToEntity to convert user from database to entity contract
@@ -23,6 +26,9 @@ func (e *User) ToEntity() *entity.User {
 		Email:           e.Email,
 		Fullname:        e.Fullname,
 		CryptedPassword: e.CryptedPassword,
+		Role:            e.Role,
+		VerifiedAt:      e.VerifiedAt,
+		DeletedAt:       e.DeletedAt,
 		CreatedAt:       e.CreatedAt,
 		UpdatedAt:       e.UpdatedAt,
 	}
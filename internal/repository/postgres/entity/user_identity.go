@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+)
+
+// UserIdentity struct holds user identity database representative
+type UserIdentity struct {
+	ID        int       `db:"id"`
+	UserID    int       `db:"user_id"`
+	Provider  string    `db:"provider"`
+	Subject   string    `db:"subject"`
+	Email     string    `db:"email"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ToEntity to convert user identity from database to entity contract
+func (e *UserIdentity) ToEntity() *entity.UserIdentity {
+	return &entity.UserIdentity{
+		ID:        e.ID,
+		UserID:    e.UserID,
+		Provider:  e.Provider,
+		Subject:   e.Subject,
+		Email:     e.Email,
+		CreatedAt: e.CreatedAt,
+	}
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	dbentity "github.com/satriowisnugroho/book-store/internal/repository/postgres/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+)
+
+// IdentityRepositoryInterface define contract for the OpenID Connect accounts linked to users related functions to repository
+type IdentityRepositoryInterface interface {
+	GetUserIdentity(ctx context.Context, provider string, subject string) (*entity.UserIdentity, error)
+	UpsertUserIdentity(ctx context.Context, identity *entity.UserIdentity) error
+	DeleteUserIdentities(ctx context.Context, dbTrx interface{}, userID int) error
+}
+
+// IdentityRepository holds database connection
+type IdentityRepository struct {
+	db *sqlx.DB
+}
+
+var (
+	// UserIdentityTableName hold table name for user_identities
+	UserIdentityTableName = "user_identities"
+	// UserIdentityColumns list all columns on user_identities table
+	UserIdentityColumns = []string{"id", "user_id", "provider", "subject", "email", "created_at"}
+	// UserIdentityAttributes hold string format of all user_identities table columns
+	UserIdentityAttributes = strings.Join(UserIdentityColumns, ", ")
+
+	// UserIdentityCreationColumns list all columns used for create user identity
+	UserIdentityCreationColumns = UserIdentityColumns[1:]
+	// UserIdentityCreationAttributes hold string format of all creation user identity columns
+	UserIdentityCreationAttributes = strings.Join(UserIdentityCreationColumns, ", ")
+)
+
+// NewIdentityRepository create initiate identity repository with given database
+func NewIdentityRepository(db *sqlx.DB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// GetUserIdentity query to get the user identity of the account at the provider
+func (r *IdentityRepository) GetUserIdentity(ctx context.Context, provider string, subject string) (*entity.UserIdentity, error) {
+	functionName := "IdentityRepository.GetUserIdentity"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE provider = $1 AND subject = $2 LIMIT 1", UserIdentityAttributes, UserIdentityTableName)
+	rows, err := r.db.QueryxContext(ctx, query, provider, subject)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, response.ErrNotFound
+	}
+
+	tmpEntity := dbentity.UserIdentity{}
+	if err := rows.StructScan(&tmpEntity); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return tmpEntity.ToEntity(), nil
+}
+
+// UpsertUserIdentity link the account at the provider to the user, moving it over from the user it was linked to before
+func (r *IdentityRepository) UpsertUserIdentity(ctx context.Context, identity *entity.UserIdentity) error {
+	functionName := "IdentityRepository.UpsertUserIdentity"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	identity.CreatedAt = time.Now()
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (provider, subject) DO UPDATE SET user_id = EXCLUDED.user_id, email = EXCLUDED.email RETURNING id`,
+		UserIdentityTableName,
+		UserIdentityCreationAttributes,
+		EnumeratedBindvars(UserIdentityCreationColumns),
+	)
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+		identity.CreatedAt,
+	).Scan(&identity.ID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// DeleteUserIdentities unlink every account at a provider from the user
+func (r *IdentityRepository) DeleteUserIdentities(ctx context.Context, dbTrx interface{}, userID int) error {
+	functionName := "IdentityRepository.DeleteUserIdentities"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", UserIdentityTableName)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, userID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
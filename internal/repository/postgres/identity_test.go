@@ -0,0 +1,202 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUserIdentity(t *testing.T) {
+	testcases := []struct {
+		name        string
+		ctx         context.Context
+		fetchErr    error
+		fetchRows   []string
+		expected    *entity.UserIdentity
+		expectedErr error
+		wantErr     bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:        "record not found",
+			ctx:         context.Background(),
+			fetchRows:   postgres.UserIdentityColumns,
+			expectedErr: response.ErrNotFound,
+			wantErr:     true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.UserIdentityColumns,
+			expected:  &entity.UserIdentity{ID: 1, UserID: 2, Provider: "https://accounts.example.com", Subject: "sub", Email: "foo@bar.com"},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM user_identities WHERE provider = \\$1 AND subject = \\$2 LIMIT 1$").WithArgs("https://accounts.example.com", "sub")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = rows.AddRow(
+						tc.expected.ID,
+						tc.expected.UserID,
+						tc.expected.Provider,
+						tc.expected.Subject,
+						tc.expected.Email,
+						tc.expected.CreatedAt,
+					)
+				} else if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewIdentityRepository(dbx)
+			result, err := repo.GetUserIdentity(tc.ctx, "https://accounts.example.com", "sub")
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestUpsertUserIdentity(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		upsertErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail upsert",
+			ctx:       context.Background(),
+			upsertErr: errors.New("fail upsert"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("INSERT INTO user_identities \\(user_id, provider, subject, email, created_at\\) VALUES (.+) ON CONFLICT \\(provider, subject\\) DO UPDATE SET user_id = EXCLUDED.user_id, email = EXCLUDED.email RETURNING id").
+				WithArgs(2, "https://accounts.example.com", "sub", "foo@bar.com", sqlmock.AnyArg())
+			if tc.upsertErr != nil {
+				mockExpectedQuery.WillReturnError(tc.upsertErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewIdentityRepository(dbx)
+			identity := &entity.UserIdentity{UserID: 2, Provider: "https://accounts.example.com", Subject: "sub", Email: "foo@bar.com"}
+			err = repo.UpsertUserIdentity(tc.ctx, identity)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, 1, identity.ID)
+			}
+		})
+	}
+}
+
+func TestDeleteUserIdentities(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		deleteErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail delete",
+			ctx:       context.Background(),
+			deleteErr: errors.New("fail delete"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("DELETE FROM user_identities WHERE user_id = \\$1").WithArgs(2)
+			if tc.deleteErr != nil {
+				mockExpectedExec.WillReturnError(tc.deleteErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewIdentityRepository(dbx)
+			err = repo.DeleteUserIdentities(tc.ctx, nil, 2)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
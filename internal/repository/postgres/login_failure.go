@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+)
+
+// LoginFailureRepositoryInterface define contract for failed login related functions to repository
+type LoginFailureRepositoryInterface interface {
+	CreateLoginFailure(ctx context.Context, failure *entity.LoginFailure) error
+	GetLoginFailureSummaryByEmail(ctx context.Context, email string, since time.Time) (*entity.LoginFailureSummary, error)
+	GetLoginFailureSummaryByIPAddress(ctx context.Context, ipAddress string, since time.Time) (*entity.LoginFailureSummary, error)
+	DeleteLoginFailuresByEmail(ctx context.Context, email string) error
+	DeleteLoginFailuresBefore(ctx context.Context, before time.Time) error
+}
+
+// LoginFailureRepository holds database connection
+type LoginFailureRepository struct {
+	db *sqlx.DB
+}
+
+var (
+	// LoginFailureTableName hold table name for login_failures
+	LoginFailureTableName = "login_failures"
+	// LoginFailureColumns list all columns on login_failures table
+	LoginFailureColumns = []string{"id", "email", "ip_address", "created_at"}
+
+	// LoginFailureCreationColumns list all columns used for create login failure
+	LoginFailureCreationColumns = LoginFailureColumns[1:]
+	// LoginFailureCreationAttributes hold string format of all creation login failure columns
+	LoginFailureCreationAttributes = strings.Join(LoginFailureCreationColumns, ", ")
+)
+
+// NewLoginFailureRepository create initiate login failure repository with given database
+func NewLoginFailureRepository(db *sqlx.DB) *LoginFailureRepository {
+	return &LoginFailureRepository{db: db}
+}
+
+// CreateLoginFailure insert failed login data into database
+func (r *LoginFailureRepository) CreateLoginFailure(ctx context.Context, failure *entity.LoginFailure) error {
+	functionName := "LoginFailureRepository.CreateLoginFailure"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	failure.Email = strings.ToLower(failure.Email)
+	failure.CreatedAt = time.Now()
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING id`, LoginFailureTableName, LoginFailureCreationAttributes, EnumeratedBindvars(LoginFailureCreationColumns))
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		failure.Email,
+		failure.IPAddress,
+		failure.CreatedAt,
+	).Scan(&failure.ID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// GetLoginFailureSummaryByEmail query the number of failed logins of the email since the given time
+func (r *LoginFailureRepository) GetLoginFailureSummaryByEmail(ctx context.Context, email string, since time.Time) (*entity.LoginFailureSummary, error) {
+	functionName := "LoginFailureRepository.GetLoginFailureSummaryByEmail"
+
+	summary, err := r.getSummary(ctx, "email", entity.NormalizeEmail(email), since)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return summary, nil
+}
+
+// GetLoginFailureSummaryByIPAddress query the number of failed logins from the IP address since the given time
+func (r *LoginFailureRepository) GetLoginFailureSummaryByIPAddress(ctx context.Context, ipAddress string, since time.Time) (*entity.LoginFailureSummary, error) {
+	functionName := "LoginFailureRepository.GetLoginFailureSummaryByIPAddress"
+
+	summary, err := r.getSummary(ctx, "ip_address", ipAddress, since)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return summary, nil
+}
+
+// DeleteLoginFailuresByEmail delete the failed logins of the email, the failures of its IP addresses are kept
+func (r *LoginFailureRepository) DeleteLoginFailuresByEmail(ctx context.Context, email string) error {
+	functionName := "LoginFailureRepository.DeleteLoginFailuresByEmail"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE email = $1", LoginFailureTableName)
+	if _, err := r.db.ExecContext(ctx, query, entity.NormalizeEmail(email)); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// DeleteLoginFailuresBefore delete the failed logins older than the given time
+func (r *LoginFailureRepository) DeleteLoginFailuresBefore(ctx context.Context, before time.Time) error {
+	functionName := "LoginFailureRepository.DeleteLoginFailuresBefore"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE created_at < $1", LoginFailureTableName)
+	if _, err := r.db.ExecContext(ctx, query, before); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+func (r *LoginFailureRepository) getSummary(ctx context.Context, column string, value string, since time.Time) (*entity.LoginFailureSummary, error) {
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, err
+	}
+
+	summary := &entity.LoginFailureSummary{}
+	query := fmt.Sprintf("SELECT COUNT(*), MAX(created_at) FROM %s WHERE %s = $1 AND created_at >= $2", LoginFailureTableName, column)
+	if err := r.db.QueryRowxContext(ctx, query, value, since).Scan(&summary.Count, &summary.LastFailedAt); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,251 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateLoginFailure(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		createErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail create",
+			ctx:       context.Background(),
+			createErr: errors.New("fail create"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("INSERT INTO login_failures \\(email, ip_address, created_at\\) VALUES (.+) RETURNING id").WithArgs("foo@bar.com", "127.0.0.1", sqlmock.AnyArg())
+			if tc.createErr != nil {
+				mockExpectedQuery.WillReturnError(tc.createErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewLoginFailureRepository(dbx)
+			failure := &entity.LoginFailure{Email: "Foo@Bar.com", IPAddress: "127.0.0.1"}
+			err = repo.CreateLoginFailure(tc.ctx, failure)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, 1, failure.ID)
+			}
+		})
+	}
+}
+
+func TestGetLoginFailureSummary(t *testing.T) {
+	lastFailedAt := time.Now()
+
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		column   string
+		fetchErr error
+		expected *entity.LoginFailureSummary
+		wantErr  bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			column:  "email",
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch",
+			ctx:      context.Background(),
+			column:   "email",
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:     "success by email",
+			ctx:      context.Background(),
+			column:   "email",
+			expected: &entity.LoginFailureSummary{Count: 2, LastFailedAt: &lastFailedAt},
+			wantErr:  false,
+		},
+		{
+			name:     "success by email without failure",
+			ctx:      context.Background(),
+			column:   "email",
+			expected: &entity.LoginFailureSummary{},
+			wantErr:  false,
+		},
+		{
+			name:     "success by IP address",
+			ctx:      context.Background(),
+			column:   "ip_address",
+			expected: &entity.LoginFailureSummary{Count: 2, LastFailedAt: &lastFailedAt},
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			since := time.Now()
+			value := "foo@bar.com"
+			if tc.column == "ip_address" {
+				value = "127.0.0.1"
+			}
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT COUNT\\(\\*\\), MAX\\(created_at\\) FROM login_failures WHERE "+tc.column+" = \\$1 AND created_at >= \\$2").WithArgs(value, since)
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else if tc.expected != nil {
+				var lastFailedAt interface{}
+				if tc.expected.LastFailedAt != nil {
+					lastFailedAt = *tc.expected.LastFailedAt
+				}
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"count", "max"}).AddRow(tc.expected.Count, lastFailedAt))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewLoginFailureRepository(dbx)
+
+			var result *entity.LoginFailureSummary
+			if tc.column == "ip_address" {
+				result, err = repo.GetLoginFailureSummaryByIPAddress(tc.ctx, value, since)
+			} else {
+				result, err = repo.GetLoginFailureSummaryByEmail(tc.ctx, "Foo@Bar.com", since)
+			}
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestDeleteLoginFailuresByEmail(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		deleteErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail delete",
+			ctx:       context.Background(),
+			deleteErr: errors.New("fail delete"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("DELETE FROM login_failures WHERE email = \\$1").WithArgs("foo@bar.com")
+			if tc.deleteErr != nil {
+				mockExpectedExec.WillReturnError(tc.deleteErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewLoginFailureRepository(dbx)
+			err = repo.DeleteLoginFailuresByEmail(tc.ctx, "Foo@Bar.com")
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestDeleteLoginFailuresBefore(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		deleteErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail delete",
+			ctx:       context.Background(),
+			deleteErr: errors.New("fail delete"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			before := time.Now()
+			mockExpectedExec := mock.ExpectExec("DELETE FROM login_failures WHERE created_at < \\$1").WithArgs(before)
+			if tc.deleteErr != nil {
+				mockExpectedExec.WillReturnError(tc.deleteErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewLoginFailureRepository(dbx)
+			err = repo.DeleteLoginFailuresBefore(tc.ctx, before)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	dbentity "github.com/satriowisnugroho/book-store/internal/repository/postgres/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+)
+
+// MFARepositoryInterface define contract for TOTP credential and recovery code related functions to repository
+type MFARepositoryInterface interface {
+	GetTOTPCredential(ctx context.Context, dbTrx interface{}, userID int) (*entity.TOTPCredential, error)
+	UpsertTOTPCredential(ctx context.Context, credential *entity.TOTPCredential) error
+	EnableTOTPCredential(ctx context.Context, dbTrx interface{}, userID int, step int64) error
+	UpdateTOTPLastUsedStep(ctx context.Context, dbTrx interface{}, userID int, step int64) error
+	DeleteTOTPCredential(ctx context.Context, dbTrx interface{}, userID int) error
+	ReplaceRecoveryCodes(ctx context.Context, dbTrx interface{}, userID int, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, dbTrx interface{}, userID int, codeHash string) (bool, error)
+	DeleteRecoveryCodes(ctx context.Context, dbTrx interface{}, userID int) error
+}
+
+// MFARepository holds database connection
+type MFARepository struct {
+	db *sqlx.DB
+}
+
+var (
+	// TOTPCredentialTableName hold table name for totp_credentials
+	TOTPCredentialTableName = "totp_credentials"
+	// TOTPCredentialColumns list all columns on totp_credentials table
+	TOTPCredentialColumns = []string{"user_id", "secret", "enabled_at", "last_used_step", "created_at", "updated_at"}
+	// TOTPCredentialAttributes hold string format of all totp_credentials table columns
+	TOTPCredentialAttributes = strings.Join(TOTPCredentialColumns, ", ")
+
+	// RecoveryCodeTableName hold table name for recovery_codes
+	RecoveryCodeTableName = "recovery_codes"
+)
+
+// NewMFARepository create initiate MFA repository with given database
+func NewMFARepository(db *sqlx.DB) *MFARepository {
+	return &MFARepository{db: db}
+}
+
+// GetTOTPCredential query to get the TOTP credential of the user,
+// the row is locked until the end of the transaction when called within one
+func (r *MFARepository) GetTOTPCredential(ctx context.Context, dbTrx interface{}, userID int) (*entity.TOTPCredential, error) {
+	functionName := "MFARepository.GetTOTPCredential"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = $1 LIMIT 1", TOTPCredentialAttributes, TOTPCredentialTableName)
+	if dbTrx != nil {
+		query += " FOR UPDATE"
+	}
+
+	rows, err := Tx(r.db, dbTrx).QueryxContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, response.ErrNotFound
+	}
+
+	tmpEntity := dbentity.TOTPCredential{}
+	if err := rows.StructScan(&tmpEntity); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return tmpEntity.ToEntity(), nil
+}
+
+// UpsertTOTPCredential insert the not yet enabled TOTP credential of the user, replacing the secret of an unconfirmed enrolment.
+// An enabled credential is kept and ErrTOTPAlreadyEnabled is returned
+func (r *MFARepository) UpsertTOTPCredential(ctx context.Context, credential *entity.TOTPCredential) error {
+	functionName := "MFARepository.UpsertTOTPCredential"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	credential.EnabledAt = nil
+	credential.LastUsedStep = 0
+	credential.CreatedAt = now
+	credential.UpdatedAt = now
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_used_step = EXCLUDED.last_used_step, updated_at = EXCLUDED.updated_at WHERE %s.enabled_at IS NULL`,
+		TOTPCredentialTableName,
+		TOTPCredentialAttributes,
+		EnumeratedBindvars(TOTPCredentialColumns),
+		TOTPCredentialTableName,
+	)
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		credential.UserID,
+		credential.Secret,
+		credential.EnabledAt,
+		credential.LastUsedStep,
+		credential.CreatedAt,
+		credential.UpdatedAt,
+	)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if affected == 0 {
+		return response.ErrTOTPAlreadyEnabled
+	}
+
+	return nil
+}
+
+// EnableTOTPCredential enable the TOTP credential of the user, the code of the given time step confirmed it
+func (r *MFARepository) EnableTOTPCredential(ctx context.Context, dbTrx interface{}, userID int, step int64) error {
+	functionName := "MFARepository.EnableTOTPCredential"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	query := fmt.Sprintf("UPDATE %s SET enabled_at = $1, last_used_step = $2, updated_at = $3 WHERE user_id = $4", TOTPCredentialTableName)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, now, step, now, userID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// UpdateTOTPLastUsedStep update the time step of the last accepted code of the user
+func (r *MFARepository) UpdateTOTPLastUsedStep(ctx context.Context, dbTrx interface{}, userID int, step int64) error {
+	functionName := "MFARepository.UpdateTOTPLastUsedStep"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET last_used_step = $1, updated_at = $2 WHERE user_id = $3", TOTPCredentialTableName)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, step, time.Now(), userID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// DeleteTOTPCredential delete the TOTP credential of the user
+func (r *MFARepository) DeleteTOTPCredential(ctx context.Context, dbTrx interface{}, userID int) error {
+	functionName := "MFARepository.DeleteTOTPCredential"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", TOTPCredentialTableName)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, userID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes delete the recovery codes of the user and insert the hashes of the new ones
+func (r *MFARepository) ReplaceRecoveryCodes(ctx context.Context, dbTrx interface{}, userID int, codeHashes []string) error {
+	functionName := "MFARepository.ReplaceRecoveryCodes"
+
+	if err := r.DeleteRecoveryCodes(ctx, dbTrx, userID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (user_id, code_hash, created_at) SELECT $1, unnest($2::varchar[]), $3", RecoveryCodeTableName)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, userID, pq.Array(codeHashes), time.Now()); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// UseRecoveryCode mark the unused recovery code of the user as used, it reports whether there was such a code
+func (r *MFARepository) UseRecoveryCode(ctx context.Context, dbTrx interface{}, userID int, codeHash string) (bool, error) {
+	functionName := "MFARepository.UseRecoveryCode"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET used_at = $1 WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL", RecoveryCodeTableName)
+	result, err := Tx(r.db, dbTrx).ExecContext(ctx, query, time.Now(), userID, codeHash)
+	if err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	return affected > 0, nil
+}
+
+// DeleteRecoveryCodes delete every recovery code of the user
+func (r *MFARepository) DeleteRecoveryCodes(ctx context.Context, dbTrx interface{}, userID int) error {
+	functionName := "MFARepository.DeleteRecoveryCodes"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", RecoveryCodeTableName)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, userID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
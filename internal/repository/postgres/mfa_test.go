@@ -0,0 +1,472 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTOTPCredential(t *testing.T) {
+	enabledAt := time.Now()
+
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  *entity.TOTPCredential
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "record not found",
+			ctx:       context.Background(),
+			fetchRows: postgres.TOTPCredentialColumns,
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.TOTPCredentialColumns,
+			expected:  &entity.TOTPCredential{UserID: 2, Secret: "secret", EnabledAt: &enabledAt, LastUsedStep: 3},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM totp_credentials WHERE user_id = \\$1 LIMIT 1$").WithArgs(2)
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = rows.AddRow(
+						tc.expected.UserID,
+						tc.expected.Secret,
+						tc.expected.EnabledAt,
+						tc.expected.LastUsedStep,
+						tc.expected.CreatedAt,
+						tc.expected.UpdatedAt,
+					)
+				} else if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewMFARepository(dbx)
+			result, err := repo.GetTOTPCredential(tc.ctx, nil, 2)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestUpsertTOTPCredential(t *testing.T) {
+	testcases := []struct {
+		name          string
+		ctx           context.Context
+		upsertErr     error
+		affectedRows  int64
+		wantErr       bool
+		wantCustomErr error
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail upsert",
+			ctx:       context.Background(),
+			upsertErr: errors.New("fail upsert"),
+			wantErr:   true,
+		},
+		{
+			name:          "already enabled",
+			ctx:           context.Background(),
+			affectedRows:  0,
+			wantErr:       true,
+			wantCustomErr: response.ErrTOTPAlreadyEnabled,
+		},
+		{
+			name:         "success",
+			ctx:          context.Background(),
+			affectedRows: 1,
+			wantErr:      false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("INSERT INTO totp_credentials \\(user_id, secret, enabled_at, last_used_step, created_at, updated_at\\) VALUES (.+) ON CONFLICT \\(user_id\\) DO UPDATE SET .+ WHERE totp_credentials.enabled_at IS NULL")
+			if tc.upsertErr != nil {
+				mockExpectedExec.WillReturnError(tc.upsertErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, tc.affectedRows))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewMFARepository(dbx)
+			err = repo.UpsertTOTPCredential(tc.ctx, &entity.TOTPCredential{UserID: 2, Secret: "secret"})
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if tc.wantCustomErr != nil {
+				assert.Equal(t, tc.wantCustomErr, err)
+			}
+		})
+	}
+}
+
+func TestEnableTOTPCredential(t *testing.T) {
+	testcases := []struct {
+		name    string
+		ctx     context.Context
+		execErr error
+		wantErr bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "fail update",
+			ctx:     context.Background(),
+			execErr: errors.New("fail update"),
+			wantErr: true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE totp_credentials SET enabled_at = \\$1, last_used_step = \\$2, updated_at = \\$3 WHERE user_id = \\$4").WithArgs(sqlmock.AnyArg(), int64(3), sqlmock.AnyArg(), 2)
+			if tc.execErr != nil {
+				mockExpectedExec.WillReturnError(tc.execErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewMFARepository(dbx)
+			err = repo.EnableTOTPCredential(tc.ctx, nil, 2, 3)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestUpdateTOTPLastUsedStep(t *testing.T) {
+	testcases := []struct {
+		name    string
+		ctx     context.Context
+		execErr error
+		wantErr bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "fail update",
+			ctx:     context.Background(),
+			execErr: errors.New("fail update"),
+			wantErr: true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE totp_credentials SET last_used_step = \\$1, updated_at = \\$2 WHERE user_id = \\$3").WithArgs(int64(3), sqlmock.AnyArg(), 2)
+			if tc.execErr != nil {
+				mockExpectedExec.WillReturnError(tc.execErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewMFARepository(dbx)
+			err = repo.UpdateTOTPLastUsedStep(tc.ctx, nil, 2, 3)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestDeleteTOTPCredential(t *testing.T) {
+	testcases := []struct {
+		name    string
+		ctx     context.Context
+		execErr error
+		wantErr bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "fail delete",
+			ctx:     context.Background(),
+			execErr: errors.New("fail delete"),
+			wantErr: true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("DELETE FROM totp_credentials WHERE user_id = \\$1").WithArgs(2)
+			if tc.execErr != nil {
+				mockExpectedExec.WillReturnError(tc.execErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewMFARepository(dbx)
+			err = repo.DeleteTOTPCredential(tc.ctx, nil, 2)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestDeleteRecoveryCodes(t *testing.T) {
+	testcases := []struct {
+		name    string
+		ctx     context.Context
+		execErr error
+		wantErr bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "fail delete",
+			ctx:     context.Background(),
+			execErr: errors.New("fail delete"),
+			wantErr: true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("DELETE FROM recovery_codes WHERE user_id = \\$1").WithArgs(2)
+			if tc.execErr != nil {
+				mockExpectedExec.WillReturnError(tc.execErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewMFARepository(dbx)
+			err = repo.DeleteRecoveryCodes(tc.ctx, nil, 2)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestReplaceRecoveryCodes(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		deleteErr error
+		insertErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail delete",
+			ctx:       context.Background(),
+			deleteErr: errors.New("fail delete"),
+			wantErr:   true,
+		},
+		{
+			name:      "fail insert",
+			ctx:       context.Background(),
+			insertErr: errors.New("fail insert"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedDelete := mock.ExpectExec("DELETE FROM recovery_codes WHERE user_id = \\$1").WithArgs(2)
+			if tc.deleteErr != nil {
+				mockExpectedDelete.WillReturnError(tc.deleteErr)
+			} else {
+				mockExpectedDelete.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			mockExpectedInsert := mock.ExpectExec("INSERT INTO recovery_codes \\(user_id, code_hash, created_at\\) SELECT \\$1, unnest\\(\\$2::varchar\\[\\]\\), \\$3")
+			if tc.insertErr != nil {
+				mockExpectedInsert.WillReturnError(tc.insertErr)
+			} else {
+				mockExpectedInsert.WillReturnResult(sqlmock.NewResult(0, 2))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewMFARepository(dbx)
+			err = repo.ReplaceRecoveryCodes(tc.ctx, nil, 2, []string{"hash1", "hash2"})
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	testcases := []struct {
+		name         string
+		ctx          context.Context
+		updateErr    error
+		affectedRows int64
+		expected     bool
+		wantErr      bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:         "unknown or used code",
+			ctx:          context.Background(),
+			affectedRows: 0,
+			expected:     false,
+		},
+		{
+			name:         "success",
+			ctx:          context.Background(),
+			affectedRows: 1,
+			expected:     true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE recovery_codes SET used_at = \\$1 WHERE user_id = \\$2 AND code_hash = \\$3 AND used_at IS NULL").WithArgs(sqlmock.AnyArg(), 2, "hash")
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, tc.affectedRows))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewMFARepository(dbx)
+			result, err := repo.UseRecoveryCode(tc.ctx, nil, 2, "hash")
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
@@ -19,6 +19,8 @@ type OrderRepositoryInterface interface {
 	GetOrdersByUserID(ctx context.Context, userID, limit, offset int) ([]*entity.Order, error)
 	GetOrdersByUserIDCount(ctx context.Context, userID int) (int, error)
 	UpdateOrder(ctx context.Context, dbTrx interface{}, order *entity.Order) error
+	GetOrdersCount(ctx context.Context, userID int) (int, error)
+	StreamOrders(ctx context.Context, userID int, fn func(*entity.Order) error) error
 }
 
 // OrderRepository holds database connection
@@ -164,3 +166,61 @@ func (r *OrderRepository) UpdateOrder(ctx context.Context, dbTrx interface{}, or
 
 	return nil
 }
+
+// GetOrdersCount query to get the count of orders, a zero user ID counts the orders of every user
+func (r *OrderRepository) GetOrdersCount(ctx context.Context, userID int) (int, error) {
+	functionName := "OrderRepository.GetOrdersCount"
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return 0, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s %s", OrderTableName, r.constructUserFilterQuery(userID))
+
+	count := 0
+	rows := r.db.QueryRowxContext(ctx, query)
+	if err := rows.Scan(&count); err != nil {
+		return count, errors.Wrap(err, functionName)
+	}
+
+	return count, nil
+}
+
+// StreamOrders query orders ordered by ID and pass them one by one to fn, a zero user ID streams
+// the orders of every user. The rows are read from the connection as they are consumed
+func (r *OrderRepository) StreamOrders(ctx context.Context, userID int, fn func(*entity.Order) error) error {
+	functionName := "OrderRepository.StreamOrders"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s %s ORDER BY id", OrderAttributes, OrderTableName, r.constructUserFilterQuery(userID))
+	rows, err := r.db.QueryxContext(ctx, query)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		tmpEntity := dbentity.Order{}
+		if err := rows.StructScan(&tmpEntity); err != nil {
+			return errors.Wrap(err, functionName)
+		}
+
+		if err := fn(tmpEntity.ToEntity()); err != nil {
+			return errors.Wrap(err, functionName)
+		}
+	}
+
+	return errors.Wrap(rows.Err(), functionName)
+}
+
+// constructUserFilterQuery construct the user filter query, a zero user ID matches every user
+func (r *OrderRepository) constructUserFilterQuery(userID int) string {
+	if userID == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("WHERE user_id = %d", userID)
+}
@@ -17,6 +17,7 @@ import (
 type OrderItemRepositoryInterface interface {
 	CreateOrderItem(ctx context.Context, dbTrx interface{}, orderItem *entity.OrderItem) error
 	GetOrderItemsByOrderID(ctx context.Context, orderID int) ([]*entity.OrderItem, error)
+	HasUserOrderedBook(ctx context.Context, userID, bookID int) (bool, error)
 }
 
 // OrderItemRepository holds database connection
@@ -114,3 +115,25 @@ func (r *OrderItemRepository) GetOrderItemsByOrderID(ctx context.Context, orderI
 
 	return rows, nil
 }
+
+// HasUserOrderedBook query whether any order of the user contains the book
+func (r *OrderItemRepository) HasUserOrderedBook(ctx context.Context, userID, bookID int) (bool, error) {
+	functionName := "OrderItemRepository.HasUserOrderedBook"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf(
+		"SELECT EXISTS (SELECT 1 FROM %s oi JOIN %s o ON o.id = oi.order_id WHERE o.user_id = $1 AND oi.book_id = $2)",
+		OrderItemTableName,
+		OrderTableName,
+	)
+
+	exists := false
+	if err := r.db.QueryRowxContext(ctx, query, userID, bookID).Scan(&exists); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	return exists, nil
+}
@@ -146,3 +146,55 @@ func TestGetOrderItemsByOrderID(t *testing.T) {
 		})
 	}
 }
+
+func TestHasUserOrderedBook(t *testing.T) {
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		fetchErr error
+		expected bool
+		wantErr  bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			expected: true,
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM order_items oi JOIN orders o ON .+ WHERE o.user_id = \\$1 AND oi.book_id = \\$2\\)").
+				WithArgs(1, 2)
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(tc.expected))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewOrderItemRepository(dbx)
+			result, err := repo.HasUserOrderedBook(tc.ctx, 1, 2)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
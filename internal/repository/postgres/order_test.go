@@ -248,3 +248,154 @@ func TestUpdateOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestGetOrdersCount(t *testing.T) {
+	testcases := []struct {
+		name          string
+		ctx           context.Context
+		userID        int
+		fetchErr      error
+		expectedQuery string
+		expected      int
+		wantErr       bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:          "fail fetch query error",
+			ctx:           context.Background(),
+			fetchErr:      errors.New("fail fetch"),
+			expectedQuery: "SELECT COUNT\\(\\*\\) FROM orders",
+			wantErr:       true,
+		},
+		{
+			name:          "success all users",
+			ctx:           context.Background(),
+			expectedQuery: "^SELECT COUNT\\(\\*\\) FROM orders$",
+			expected:      2,
+			wantErr:       false,
+		},
+		{
+			name:          "success single user",
+			ctx:           context.Background(),
+			userID:        1,
+			expectedQuery: "SELECT COUNT\\(\\*\\) FROM orders WHERE user_id = 1",
+			expected:      1,
+			wantErr:       false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery(tc.expectedQuery)
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows([]string{"COUNT(*)"})
+				rows = rows.AddRow(tc.expected)
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewOrderRepository(dbx)
+			result, err := repo.GetOrdersCount(tc.ctx, tc.userID)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestStreamOrders(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		fnErr     error
+		expected  []*entity.Order
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "fail callback",
+			ctx:       context.Background(),
+			fetchRows: postgres.OrderColumns,
+			fnErr:     errors.New("fail write"),
+			expected:  []*entity.Order{{ID: 1}},
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.OrderColumns,
+			expected:  []*entity.Order{{ID: 1}, {ID: 2}},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT .+ FROM orders WHERE user_id = 1 ORDER BY id")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				for _, order := range tc.expected {
+					rows = rows.AddRow(order.ID, order.UserID, order.Fee, order.TotalPrice, order.CreatedAt, order.UpdatedAt)
+				}
+				if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewOrderRepository(dbx)
+
+			result := []*entity.Order{}
+			err = repo.StreamOrders(tc.ctx, 1, func(order *entity.Order) error {
+				result = append(result, order)
+				return tc.fnErr
+			})
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -38,6 +39,18 @@ func Tx(db *sqlx.DB, iTrx interface{}) sqlx.ExtContext {
 	return iTrx.(*sqlx.Tx)
 }
 
+// IsRowError reports whether err was caused by the values of a row, such as a value out of range or a violated
+// constraint, rather than by the connection or the context
+func IsRowError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	class := pqErr.Code.Class()
+	return class == config.DataExceptionClass || class == config.IntegrityConstraintViolationClass
+}
+
 func isUniqueConstraintViolation(err error) bool {
 	pqErr, ok := err.(*pq.Error)
 	return ok && pqErr.Code == config.UniqueConstraintViolationCode
@@ -1,9 +1,13 @@
 package postgres_test
 
 import (
+	"context"
+	"database/sql/driver"
 	"testing"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
 	"github.com/satriowisnugroho/book-store/internal/repository/postgres"
 	"github.com/stretchr/testify/assert"
 )
@@ -14,3 +18,11 @@ func TestTx(t *testing.T) {
 
 	assert.Equal(t, dbTrx, tx)
 }
+
+func TestIsRowError(t *testing.T) {
+	assert.True(t, postgres.IsRowError(&pq.Error{Code: "22003"}))
+	assert.True(t, postgres.IsRowError(errors.Wrap(&pq.Error{Code: "23514"}, "BookRepository.UpsertBook")))
+	assert.False(t, postgres.IsRowError(&pq.Error{Code: "57014"}))
+	assert.False(t, postgres.IsRowError(driver.ErrBadConn))
+	assert.False(t, postgres.IsRowError(context.DeadlineExceeded))
+}
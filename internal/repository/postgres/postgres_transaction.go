@@ -7,6 +7,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/pkg/metrics"
 )
 
 // PostgresTransactionRepository holds database connection
@@ -42,6 +43,8 @@ func (r *PostgresTransactionRepository) CommitTransactionQuery(ctx context.Conte
 	}
 
 	err := tx.Commit()
+	metrics.DBTransactionsTotal.WithLabelValues(metrics.TransactionCommit, metrics.Status(err)).Inc()
+
 	return errors.Wrap(err, functionName)
 }
 
@@ -53,5 +56,7 @@ func (r *PostgresTransactionRepository) RollbackTransactionQuery(ctx context.Con
 	}
 
 	err := tx.Rollback()
+	metrics.DBTransactionsTotal.WithLabelValues(metrics.TransactionRollback, metrics.Status(err)).Inc()
+
 	return errors.Wrap(err, functionName)
 }
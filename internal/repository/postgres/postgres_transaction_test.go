@@ -7,7 +7,9 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/pkg/metrics"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -63,6 +65,8 @@ func TestCommitTransactionQuery(t *testing.T) {
 		errCommit error
 		txNil     bool
 		wantErr   bool
+		// counted is the status counted for the commit, none when empty
+		counted string
 	}{
 		{
 			name:    "error tx nil",
@@ -75,11 +79,13 @@ func TestCommitTransactionQuery(t *testing.T) {
 			ctx:       context.Background(),
 			errCommit: errors.New("error commit tx"),
 			wantErr:   true,
+			counted:   metrics.StatusError,
 		},
 		{
 			name:    "success",
 			ctx:     context.Background(),
 			wantErr: false,
+			counted: metrics.StatusSuccess,
 		},
 	}
 
@@ -102,6 +108,7 @@ func TestCommitTransactionQuery(t *testing.T) {
 				tx, _ = repo.StartTransactionQuery(tc.ctx)
 			}
 
+			before := transactionCounts(metrics.TransactionCommit)
 			err = repo.CommitTransactionQuery(tc.ctx, tx)
 
 			if tc.wantErr {
@@ -109,6 +116,14 @@ func TestCommitTransactionQuery(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 			}
+			after := transactionCounts(metrics.TransactionCommit)
+			for _, status := range []string{metrics.StatusSuccess, metrics.StatusError} {
+				expected := before[status]
+				if status == tc.counted {
+					expected++
+				}
+				assert.Equal(t, expected, after[status], status)
+			}
 		})
 	}
 }
@@ -120,6 +135,8 @@ func TestRollbackTransactionQuery(t *testing.T) {
 		errRollback error
 		txNil       bool
 		wantErr     bool
+		// counted is the status counted for the rollback, none when empty
+		counted string
 	}{
 		{
 			name:    "error tx nil",
@@ -132,11 +149,13 @@ func TestRollbackTransactionQuery(t *testing.T) {
 			ctx:         context.Background(),
 			errRollback: errors.New("error rollback tx"),
 			wantErr:     true,
+			counted:     metrics.StatusError,
 		},
 		{
 			name:    "success",
 			ctx:     context.Background(),
 			wantErr: false,
+			counted: metrics.StatusSuccess,
 		},
 	}
 
@@ -159,6 +178,7 @@ func TestRollbackTransactionQuery(t *testing.T) {
 				tx, _ = repo.StartTransactionQuery(tc.ctx)
 			}
 
+			before := transactionCounts(metrics.TransactionRollback)
 			err = repo.RollbackTransactionQuery(tc.ctx, tx)
 
 			if tc.wantErr {
@@ -166,6 +186,22 @@ func TestRollbackTransactionQuery(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 			}
+			after := transactionCounts(metrics.TransactionRollback)
+			for _, status := range []string{metrics.StatusSuccess, metrics.StatusError} {
+				expected := before[status]
+				if status == tc.counted {
+					expected++
+				}
+				assert.Equal(t, expected, after[status], status)
+			}
 		})
 	}
 }
+
+// transactionCounts returns the transactions ended with the operation by status
+func transactionCounts(operation string) map[string]float64 {
+	return map[string]float64{
+		metrics.StatusSuccess: testutil.ToFloat64(metrics.DBTransactionsTotal.WithLabelValues(operation, metrics.StatusSuccess)),
+		metrics.StatusError:   testutil.ToFloat64(metrics.DBTransactionsTotal.WithLabelValues(operation, metrics.StatusError)),
+	}
+}
@@ -0,0 +1,370 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	dbentity "github.com/satriowisnugroho/book-store/internal/repository/postgres/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+)
+
+// ReadingListRepositoryInterface define contract for reading list related functions to repository
+type ReadingListRepositoryInterface interface {
+	CreateReadingList(ctx context.Context, readingList *entity.ReadingList) error
+	GetOrCreateWishlist(ctx context.Context, userID int) (*entity.ReadingList, error)
+	GetReadingListByID(ctx context.Context, readingListID int) (*entity.ReadingList, error)
+	GetReadingListByShareSlug(ctx context.Context, shareSlug string) (*entity.ReadingList, error)
+	GetReadingListsByUserID(ctx context.Context, userID, limit, offset int) ([]*entity.ReadingList, error)
+	GetReadingListsByUserIDCount(ctx context.Context, userID int) (int, error)
+	GetPublicReadingLists(ctx context.Context, limit, offset int) ([]*entity.ReadingList, error)
+	GetPublicReadingListsCount(ctx context.Context) (int, error)
+	UpdateReadingList(ctx context.Context, readingList *entity.ReadingList) error
+	DeleteReadingList(ctx context.Context, readingListID int) error
+	AddReadingListBook(ctx context.Context, readingListID, bookID int) error
+	RemoveReadingListBook(ctx context.Context, readingListID, bookID int) error
+	GetReadingListBookIDs(ctx context.Context, readingListIDs []int) (map[int][]int, error)
+}
+
+// ReadingListRepository holds database connection
+type ReadingListRepository struct {
+	db *sqlx.DB
+}
+
+var (
+	// ReadingListTableName hold table name for reading_lists
+	ReadingListTableName = "reading_lists"
+	// ReadingListColumns list all columns on reading_lists table
+	ReadingListColumns = []string{"id", "user_id", "name", "is_wishlist", "is_public", "share_slug", "created_at", "updated_at"}
+	// ReadingListAttributes hold string format of all reading_lists table columns
+	ReadingListAttributes = strings.Join(ReadingListColumns, ", ")
+
+	// ReadingListCreationColumns list all columns used for create reading list
+	ReadingListCreationColumns = ReadingListColumns[1:]
+	// ReadingListCreationAttributes hold string format of all creation reading list columns
+	ReadingListCreationAttributes = strings.Join(ReadingListCreationColumns, ", ")
+
+	// ReadingListItemTableName hold table name for reading_list_items
+	ReadingListItemTableName = "reading_list_items"
+)
+
+// NewReadingListRepository create initiate reading list repository with given database
+func NewReadingListRepository(db *sqlx.DB) *ReadingListRepository {
+	return &ReadingListRepository{db: db}
+}
+
+func (r *ReadingListRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]*entity.ReadingList, error) {
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	result := make([]*entity.ReadingList, 0)
+
+	for rows.Next() {
+		tmpEntity := dbentity.ReadingList{}
+		if err := rows.StructScan(&tmpEntity); err != nil {
+			return nil, errors.Wrap(err, "fetch")
+		}
+
+		result = append(result, tmpEntity.ToEntity())
+	}
+
+	return result, nil
+}
+
+// CreateReadingList insert reading list data into database
+func (r *ReadingListRepository) CreateReadingList(ctx context.Context, readingList *entity.ReadingList) error {
+	functionName := "ReadingListRepository.CreateReadingList"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	readingList.CreatedAt = now
+	readingList.UpdatedAt = now
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING id`, ReadingListTableName, ReadingListCreationAttributes, EnumeratedBindvars(ReadingListCreationColumns))
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		readingList.UserID,
+		readingList.Name,
+		readingList.IsWishlist,
+		readingList.IsPublic,
+		readingList.ShareSlug,
+		readingList.CreatedAt,
+		readingList.UpdatedAt,
+	).Scan(&readingList.ID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// GetOrCreateWishlist query to get the wishlist of the user, the wishlist is created on first use
+func (r *ReadingListRepository) GetOrCreateWishlist(ctx context.Context, userID int) (*entity.ReadingList, error) {
+	functionName := "ReadingListRepository.GetOrCreateWishlist"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	// The insert and the select are separate statements so a wishlist created concurrently is visible to the select
+	now := time.Now()
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (user_id) WHERE is_wishlist DO NOTHING`,
+		ReadingListTableName,
+		ReadingListCreationAttributes,
+		EnumeratedBindvars(ReadingListCreationColumns),
+	)
+	if _, err := r.db.ExecContext(ctx, query, userID, entity.WishlistName, true, false, "", now, now); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query = fmt.Sprintf("SELECT %s FROM %s WHERE user_id = $1 AND is_wishlist LIMIT 1", ReadingListAttributes, ReadingListTableName)
+	rows, err := r.fetch(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.Wrap(response.ErrNotFound, functionName)
+	}
+
+	return rows[0], nil
+}
+
+// GetReadingListByID query to get reading list by ID
+func (r *ReadingListRepository) GetReadingListByID(ctx context.Context, readingListID int) (*entity.ReadingList, error) {
+	functionName := "ReadingListRepository.GetReadingListByID"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1 LIMIT 1", ReadingListAttributes, ReadingListTableName)
+	rows, err := r.fetch(ctx, query, readingListID)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if len(rows) == 0 {
+		return nil, response.ErrNotFound
+	}
+
+	return rows[0], nil
+}
+
+// GetReadingListByShareSlug query to get a public reading list by its share slug
+func (r *ReadingListRepository) GetReadingListByShareSlug(ctx context.Context, shareSlug string) (*entity.ReadingList, error) {
+	functionName := "ReadingListRepository.GetReadingListByShareSlug"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE share_slug = $1 AND is_public LIMIT 1", ReadingListAttributes, ReadingListTableName)
+	rows, err := r.fetch(ctx, query, shareSlug)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if len(rows) == 0 {
+		return nil, response.ErrNotFound
+	}
+
+	return rows[0], nil
+}
+
+// GetReadingListsByUserID query to get list of reading lists by user ID without the wishlist, the newest first
+func (r *ReadingListRepository) GetReadingListsByUserID(ctx context.Context, userID, limit, offset int) ([]*entity.ReadingList, error) {
+	functionName := "ReadingListRepository.GetReadingListsByUserID"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return []*entity.ReadingList{}, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = $1 AND NOT is_wishlist ORDER BY created_at DESC LIMIT %d OFFSET %d", ReadingListAttributes, ReadingListTableName, limit, offset)
+
+	rows, err := r.fetch(ctx, query, userID)
+	if err != nil {
+		return rows, errors.Wrap(err, functionName)
+	}
+
+	return rows, nil
+}
+
+// GetReadingListsByUserIDCount query to get the count of reading lists by user ID without the wishlist
+func (r *ReadingListRepository) GetReadingListsByUserIDCount(ctx context.Context, userID int) (int, error) {
+	functionName := "ReadingListRepository.GetReadingListsByUserIDCount"
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return 0, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE user_id = $1 AND NOT is_wishlist", ReadingListTableName)
+
+	count := 0
+	rows := r.db.QueryRowxContext(ctx, query, userID)
+	if err := rows.Scan(&count); err != nil {
+		return count, errors.Wrap(err, functionName)
+	}
+
+	return count, nil
+}
+
+// GetPublicReadingLists query to get list of public reading lists, the newest first
+func (r *ReadingListRepository) GetPublicReadingLists(ctx context.Context, limit, offset int) ([]*entity.ReadingList, error) {
+	functionName := "ReadingListRepository.GetPublicReadingLists"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return []*entity.ReadingList{}, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE is_public ORDER BY created_at DESC LIMIT %d OFFSET %d", ReadingListAttributes, ReadingListTableName, limit, offset)
+
+	rows, err := r.fetch(ctx, query)
+	if err != nil {
+		return rows, errors.Wrap(err, functionName)
+	}
+
+	return rows, nil
+}
+
+// GetPublicReadingListsCount query to get the count of public reading lists
+func (r *ReadingListRepository) GetPublicReadingListsCount(ctx context.Context) (int, error) {
+	functionName := "ReadingListRepository.GetPublicReadingListsCount"
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return 0, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE is_public", ReadingListTableName)
+
+	count := 0
+	rows := r.db.QueryRowxContext(ctx, query)
+	if err := rows.Scan(&count); err != nil {
+		return count, errors.Wrap(err, functionName)
+	}
+
+	return count, nil
+}
+
+// UpdateReadingList update the name, visibility and share slug of a reading list
+func (r *ReadingListRepository) UpdateReadingList(ctx context.Context, readingList *entity.ReadingList) error {
+	functionName := "ReadingListRepository.UpdateReadingList"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	readingList.UpdatedAt = time.Now()
+
+	query := fmt.Sprintf("UPDATE %s SET name = $1, is_public = $2, share_slug = $3, updated_at = $4 WHERE id = $5", ReadingListTableName)
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		readingList.Name,
+		readingList.IsPublic,
+		readingList.ShareSlug,
+		readingList.UpdatedAt,
+		readingList.ID,
+	)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// DeleteReadingList delete a reading list, its books are removed by the foreign key cascade
+func (r *ReadingListRepository) DeleteReadingList(ctx context.Context, readingListID int) error {
+	functionName := "ReadingListRepository.DeleteReadingList"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", ReadingListTableName)
+	if _, err := r.db.ExecContext(ctx, query, readingListID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// AddReadingListBook add a book to a reading list, adding a book already in the list is a no-op
+func (r *ReadingListRepository) AddReadingListBook(ctx context.Context, readingListID, bookID int) error {
+	functionName := "ReadingListRepository.AddReadingListBook"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (reading_list_id, book_id, created_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING", ReadingListItemTableName)
+	if _, err := r.db.ExecContext(ctx, query, readingListID, bookID, time.Now()); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// RemoveReadingListBook remove a book from a reading list
+func (r *ReadingListRepository) RemoveReadingListBook(ctx context.Context, readingListID, bookID int) error {
+	functionName := "ReadingListRepository.RemoveReadingListBook"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE reading_list_id = $1 AND book_id = $2", ReadingListItemTableName)
+	if _, err := r.db.ExecContext(ctx, query, readingListID, bookID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// GetReadingListBookIDs query to get the book IDs of the reading lists in the order they were added,
+// the result maps every reading list ID to its book IDs
+func (r *ReadingListRepository) GetReadingListBookIDs(ctx context.Context, readingListIDs []int) (map[int][]int, error) {
+	functionName := "ReadingListRepository.GetReadingListBookIDs"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT reading_list_id, book_id FROM %s WHERE reading_list_id = ANY($1) ORDER BY created_at, book_id", ReadingListItemTableName)
+	rows, err := r.db.QueryxContext(ctx, query, pq.Array(readingListIDs))
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	defer rows.Close()
+
+	bookIDs := map[int][]int{}
+	for rows.Next() {
+		readingListID, bookID := 0, 0
+		if err := rows.Scan(&readingListID, &bookID); err != nil {
+			return nil, errors.Wrap(err, functionName)
+		}
+
+		bookIDs[readingListID] = append(bookIDs[readingListID], bookID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return bookIDs, nil
+}
@@ -0,0 +1,807 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	"github.com/stretchr/testify/assert"
+)
+
+func addReadingListRow(rows *sqlmock.Rows, readingList *entity.ReadingList) *sqlmock.Rows {
+	return rows.AddRow(
+		readingList.ID,
+		readingList.UserID,
+		readingList.Name,
+		readingList.IsWishlist,
+		readingList.IsPublic,
+		readingList.ShareSlug,
+		readingList.CreatedAt,
+		readingList.UpdatedAt,
+	)
+}
+
+func TestCreateReadingList(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		input     *entity.ReadingList
+		createErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail exec query",
+			ctx:       context.Background(),
+			input:     &entity.ReadingList{},
+			createErr: errors.New("fail exec"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			input:   &entity.ReadingList{},
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			expectedQuery := "INSERT INTO reading_lists (.+) VALUES (.+) RETURNING id"
+			if tc.createErr != nil {
+				mock.ExpectQuery(expectedQuery).WillReturnError(tc.createErr)
+			} else {
+				mock.ExpectQuery(expectedQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReadingListRepository(dbx)
+
+			err = repo.CreateReadingList(tc.ctx, tc.input)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				assert.Equal(t, 1, tc.input.ID)
+			}
+		})
+	}
+}
+
+func TestGetOrCreateWishlist(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		insertErr error
+		fetchErr  error
+		expected  *entity.ReadingList
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail insert",
+			ctx:       context.Background(),
+			insertErr: errors.New("fail insert"),
+			wantErr:   true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:    "wishlist not found",
+			ctx:     context.Background(),
+			wantErr: true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			expected: &entity.ReadingList{ID: 1, UserID: 2, Name: entity.WishlistName, IsWishlist: true, Books: []*entity.Book{}},
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("INSERT INTO reading_lists (.+) VALUES (.+) ON CONFLICT \\(user_id\\) WHERE is_wishlist DO NOTHING").
+				WithArgs(2, entity.WishlistName, true, false, "", sqlmock.AnyArg(), sqlmock.AnyArg())
+			if tc.insertErr != nil {
+				mockExpectedExec.WillReturnError(tc.insertErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(1, 1))
+
+				mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM reading_lists WHERE user_id = \\$1 AND is_wishlist LIMIT 1").WithArgs(2)
+				if tc.fetchErr != nil {
+					mockExpectedQuery.WillReturnError(tc.fetchErr)
+				} else {
+					rows := sqlmock.NewRows(postgres.ReadingListColumns)
+					if tc.expected != nil {
+						rows = addReadingListRow(rows, tc.expected)
+					}
+
+					mockExpectedQuery.WillReturnRows(rows)
+				}
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReadingListRepository(dbx)
+			result, err := repo.GetOrCreateWishlist(tc.ctx, 2)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetReadingListByID(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  *entity.ReadingList
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "record not found",
+			ctx:       context.Background(),
+			fetchRows: postgres.ReadingListColumns,
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.ReadingListColumns,
+			expected:  &entity.ReadingList{ID: 1, Name: "Summer reads", Books: []*entity.Book{}},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM reading_lists WHERE id = \\$1 LIMIT 1").WithArgs(1)
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = addReadingListRow(rows, tc.expected)
+				} else if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReadingListRepository(dbx)
+			result, err := repo.GetReadingListByID(tc.ctx, 1)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetReadingListByShareSlug(t *testing.T) {
+	testcases := []struct {
+		name        string
+		ctx         context.Context
+		fetchErr    error
+		expected    *entity.ReadingList
+		expectedErr error
+		wantErr     bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:        "record not found",
+			ctx:         context.Background(),
+			expectedErr: response.ErrNotFound,
+			wantErr:     true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			expected: &entity.ReadingList{ID: 1, IsPublic: true, ShareSlug: "slug", Books: []*entity.Book{}},
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM reading_lists WHERE share_slug = \\$1 AND is_public LIMIT 1").WithArgs("slug")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(postgres.ReadingListColumns)
+				if tc.expected != nil {
+					rows = addReadingListRow(rows, tc.expected)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReadingListRepository(dbx)
+			result, err := repo.GetReadingListByShareSlug(tc.ctx, "slug")
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetReadingListsByUserID(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  []*entity.ReadingList
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.ReadingListColumns,
+			expected:  []*entity.ReadingList{{ID: 1, UserID: 2, Books: []*entity.Book{}}},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM reading_lists WHERE user_id = \\$1 AND NOT is_wishlist ORDER BY created_at DESC LIMIT 10 OFFSET 0").WithArgs(2)
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				for _, readingList := range tc.expected {
+					rows = addReadingListRow(rows, readingList)
+				}
+				if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReadingListRepository(dbx)
+			result, err := repo.GetReadingListsByUserID(tc.ctx, 2, 10, 0)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetReadingListsByUserIDCount(t *testing.T) {
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		fetchErr error
+		expected int
+		wantErr  bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			expected: 1,
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM reading_lists WHERE user_id = \\$1 AND NOT is_wishlist").WithArgs(2)
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(tc.expected))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReadingListRepository(dbx)
+			result, err := repo.GetReadingListsByUserIDCount(tc.ctx, 2)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetPublicReadingLists(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  []*entity.ReadingList
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.ReadingListColumns,
+			expected:  []*entity.ReadingList{{ID: 1, IsPublic: true, ShareSlug: "slug", Books: []*entity.Book{}}},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM reading_lists WHERE is_public ORDER BY created_at DESC LIMIT 10 OFFSET 0")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				for _, readingList := range tc.expected {
+					rows = addReadingListRow(rows, readingList)
+				}
+				if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReadingListRepository(dbx)
+			result, err := repo.GetPublicReadingLists(tc.ctx, 10, 0)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetPublicReadingListsCount(t *testing.T) {
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		fetchErr error
+		expected int
+		wantErr  bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			expected: 1,
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM reading_lists WHERE is_public")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(tc.expected))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReadingListRepository(dbx)
+			result, err := repo.GetPublicReadingListsCount(tc.ctx)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestUpdateReadingList(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		updateErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE reading_lists SET name = \\$1, is_public = \\$2, share_slug = \\$3, updated_at = \\$4 WHERE id = \\$5").
+				WithArgs("Summer reads", true, "slug", sqlmock.AnyArg(), 1)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReadingListRepository(dbx)
+			err = repo.UpdateReadingList(tc.ctx, &entity.ReadingList{ID: 1, Name: "Summer reads", IsPublic: true, ShareSlug: "slug"})
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestDeleteReadingList(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		deleteErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail delete",
+			ctx:       context.Background(),
+			deleteErr: errors.New("fail delete"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("DELETE FROM reading_lists WHERE id = \\$1").WithArgs(1)
+			if tc.deleteErr != nil {
+				mockExpectedExec.WillReturnError(tc.deleteErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReadingListRepository(dbx)
+			err = repo.DeleteReadingList(tc.ctx, 1)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestAddReadingListBook(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		insertErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail insert",
+			ctx:       context.Background(),
+			insertErr: errors.New("fail insert"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("INSERT INTO reading_list_items \\(reading_list_id, book_id, created_at\\) VALUES \\(\\$1, \\$2, \\$3\\) ON CONFLICT DO NOTHING").
+				WithArgs(1, 2, sqlmock.AnyArg())
+			if tc.insertErr != nil {
+				mockExpectedExec.WillReturnError(tc.insertErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReadingListRepository(dbx)
+			err = repo.AddReadingListBook(tc.ctx, 1, 2)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestRemoveReadingListBook(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		deleteErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail delete",
+			ctx:       context.Background(),
+			deleteErr: errors.New("fail delete"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("DELETE FROM reading_list_items WHERE reading_list_id = \\$1 AND book_id = \\$2").WithArgs(1, 2)
+			if tc.deleteErr != nil {
+				mockExpectedExec.WillReturnError(tc.deleteErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReadingListRepository(dbx)
+			err = repo.RemoveReadingListBook(tc.ctx, 1, 2)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestGetReadingListBookIDs(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  map[int][]int
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail scan",
+			ctx:       context.Background(),
+			fetchRows: []string{"reading_list_id", "book_id"},
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: []string{"reading_list_id", "book_id"},
+			expected:  map[int][]int{1: {7, 8}, 2: {7}},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT reading_list_id, book_id FROM reading_list_items WHERE reading_list_id = ANY\\(\\$1\\) ORDER BY created_at, book_id")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else if tc.fetchRows != nil {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected == nil {
+					rows = rows.AddRow(1, "not a number")
+				} else {
+					rows = rows.AddRow(1, 7).AddRow(2, 7).AddRow(1, 8)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReadingListRepository(dbx)
+			result, err := repo.GetReadingListBookIDs(tc.ctx, []int{1, 2})
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
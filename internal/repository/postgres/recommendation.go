@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+)
+
+// RecommendationRepositoryInterface define contract for book recommendation related functions to repository
+type RecommendationRepositoryInterface interface {
+	RefreshBookRecommendations(ctx context.Context, dbTrx interface{}, perBook int) (bool, error)
+	GetRelatedBookIDs(ctx context.Context, bookID, limit int) ([]int, error)
+	GetUserRecommendedBookIDs(ctx context.Context, userID, limit int) ([]int, error)
+	GetBestsellingBookIDs(ctx context.Context, limit int) ([]int, error)
+}
+
+// RecommendationRepository holds database connection
+type RecommendationRepository struct {
+	db *sqlx.DB
+}
+
+var (
+	// BookRecommendationTableName hold table name for book_recommendations
+	BookRecommendationTableName = "book_recommendations"
+)
+
+// bookRecommendationsLockKey is the advisory lock key held while the recommendations are refreshed,
+// so several API instances running the refresh job do not recompute them at the same time
+const bookRecommendationsLockKey = 20240618
+
+// NewRecommendationRepository create initiate recommendation repository with given database
+func NewRecommendationRepository(db *sqlx.DB) *RecommendationRepository {
+	return &RecommendationRepository{db: db}
+}
+
+func (r *RecommendationRepository) fetchIDs(ctx context.Context, query string, args ...interface{}) ([]int, error) {
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	result := make([]int, 0)
+
+	for rows.Next() {
+		id := 0
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, "fetchIDs")
+		}
+
+		result = append(result, id)
+	}
+
+	return result, rows.Err()
+}
+
+// RefreshBookRecommendations recompute the co-purchase scores of every pair of books bought by the same customers.
+// The score is the cosine similarity of the sets of customers who bought each book and only the best scores of
+// every book are kept. It returns false without refreshing when another refresh holds the lock
+func (r *RecommendationRepository) RefreshBookRecommendations(ctx context.Context, dbTrx interface{}, perBook int) (bool, error) {
+	functionName := "RecommendationRepository.RefreshBookRecommendations"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	tx := Tx(r.db, dbTrx)
+
+	locked := false
+	if err := tx.QueryRowxContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", bookRecommendationsLockKey).Scan(&locked); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	if !locked {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", BookRecommendationTableName)); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf(`WITH buyers AS (
+		SELECT DISTINCT o.user_id, oi.book_id FROM %s oi JOIN %s o ON o.id = oi.order_id
+	), book_buyers AS (
+		SELECT book_id, COUNT(*) AS buyer_count FROM buyers GROUP BY book_id
+	), pairs AS (
+		SELECT a.book_id, b.book_id AS related_book_id, COUNT(*) AS co_count
+		FROM buyers a JOIN buyers b ON a.user_id = b.user_id AND a.book_id <> b.book_id
+		GROUP BY a.book_id, b.book_id
+	), scored AS (
+		SELECT p.book_id, p.related_book_id, p.co_count / SQRT(ba.buyer_count * bb.buyer_count) AS score
+		FROM pairs p
+		JOIN book_buyers ba ON ba.book_id = p.book_id
+		JOIN book_buyers bb ON bb.book_id = p.related_book_id
+	), ranked AS (
+		SELECT book_id, related_book_id, score,
+			ROW_NUMBER() OVER (PARTITION BY book_id ORDER BY score DESC, related_book_id) AS rank
+		FROM scored
+	)
+	INSERT INTO %s (book_id, related_book_id, score, updated_at)
+	SELECT book_id, related_book_id, score, $1 FROM ranked WHERE rank <= $2`,
+		OrderItemTableName,
+		OrderTableName,
+		BookRecommendationTableName,
+	)
+	if _, err := tx.ExecContext(ctx, query, time.Now(), perBook); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	return true, nil
+}
+
+// GetRelatedBookIDs query to get the IDs of the books most bought together with the book, the best score first
+func (r *RecommendationRepository) GetRelatedBookIDs(ctx context.Context, bookID, limit int) ([]int, error) {
+	functionName := "RecommendationRepository.GetRelatedBookIDs"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return []int{}, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT related_book_id FROM %s WHERE book_id = $1 ORDER BY score DESC, related_book_id LIMIT %d", BookRecommendationTableName, limit)
+	ids, err := r.fetchIDs(ctx, query, bookID)
+	if err != nil {
+		return ids, errors.Wrap(err, functionName)
+	}
+
+	return ids, nil
+}
+
+// GetUserRecommendedBookIDs query to get the IDs of the books most bought together with the books the user bought,
+// the books the user already bought are left out
+func (r *RecommendationRepository) GetUserRecommendedBookIDs(ctx context.Context, userID, limit int) ([]int, error) {
+	functionName := "RecommendationRepository.GetUserRecommendedBookIDs"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return []int{}, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf(`WITH bought AS (
+		SELECT DISTINCT oi.book_id FROM %s oi JOIN %s o ON o.id = oi.order_id WHERE o.user_id = $1
+	)
+	SELECT r.related_book_id FROM %s r
+	WHERE r.book_id IN (SELECT book_id FROM bought) AND r.related_book_id NOT IN (SELECT book_id FROM bought)
+	GROUP BY r.related_book_id ORDER BY SUM(r.score) DESC, r.related_book_id LIMIT %d`,
+		OrderItemTableName,
+		OrderTableName,
+		BookRecommendationTableName,
+		limit,
+	)
+	ids, err := r.fetchIDs(ctx, query, userID)
+	if err != nil {
+		return ids, errors.Wrap(err, functionName)
+	}
+
+	return ids, nil
+}
+
+// GetBestsellingBookIDs query to get the IDs of the books with the most copies sold of all time,
+// as last computed by the bestseller refresh
+func (r *RecommendationRepository) GetBestsellingBookIDs(ctx context.Context, limit int) ([]int, error) {
+	functionName := "RecommendationRepository.GetBestsellingBookIDs"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return []int{}, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT book_id FROM %s WHERE period = $1 ORDER BY units_sold DESC, book_id LIMIT %d", BookBestsellerTableName, limit)
+	ids, err := r.fetchIDs(ctx, query, entity.BestsellerPeriodAllTime)
+	if err != nil {
+		return ids, errors.Wrap(err, functionName)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,188 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshBookRecommendations(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		lockErr   error
+		locked    bool
+		deleteErr error
+		insertErr error
+		expected  bool
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "fail lock",
+			ctx:     context.Background(),
+			lockErr: errors.New("fail lock"),
+			wantErr: true,
+		},
+		{
+			name:     "another refresh holds the lock",
+			ctx:      context.Background(),
+			locked:   false,
+			expected: false,
+			wantErr:  false,
+		},
+		{
+			name:      "fail delete",
+			ctx:       context.Background(),
+			locked:    true,
+			deleteErr: errors.New("fail delete"),
+			wantErr:   true,
+		},
+		{
+			name:      "fail insert",
+			ctx:       context.Background(),
+			locked:    true,
+			insertErr: errors.New("fail insert"),
+			wantErr:   true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			locked:   true,
+			expected: true,
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedLock := mock.ExpectQuery("SELECT pg_try_advisory_xact_lock\\(\\$1\\)")
+			if tc.lockErr != nil {
+				mockExpectedLock.WillReturnError(tc.lockErr)
+			} else {
+				mockExpectedLock.WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(tc.locked))
+			}
+
+			mockExpectedDelete := mock.ExpectExec("DELETE FROM book_recommendations")
+			if tc.deleteErr != nil {
+				mockExpectedDelete.WillReturnError(tc.deleteErr)
+			} else {
+				mockExpectedDelete.WillReturnResult(sqlmock.NewResult(0, 10))
+			}
+
+			mockExpectedInsert := mock.ExpectExec("WITH buyers AS (.+) INSERT INTO book_recommendations \\(book_id, related_book_id, score, updated_at\\)").
+				WithArgs(sqlmock.AnyArg(), 20)
+			if tc.insertErr != nil {
+				mockExpectedInsert.WillReturnError(tc.insertErr)
+			} else {
+				mockExpectedInsert.WillReturnResult(sqlmock.NewResult(0, 10))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewRecommendationRepository(dbx)
+			refreshed, err := repo.RefreshBookRecommendations(tc.ctx, nil, 20)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			assert.Equal(t, tc.expected, refreshed)
+		})
+	}
+}
+
+func TestGetRecommendedBookIDs(t *testing.T) {
+	testcases := []struct {
+		name          string
+		ctx           context.Context
+		expectedQuery string
+		call          func(repo *postgres.RecommendationRepository, ctx context.Context) ([]int, error)
+	}{
+		{
+			name:          "related books",
+			expectedQuery: "SELECT related_book_id FROM book_recommendations WHERE book_id = \\$1 ORDER BY score DESC, related_book_id LIMIT 10",
+			call: func(repo *postgres.RecommendationRepository, ctx context.Context) ([]int, error) {
+				return repo.GetRelatedBookIDs(ctx, 1, 10)
+			},
+		},
+		{
+			name:          "user recommended books",
+			expectedQuery: "WITH bought AS (.+) SELECT r.related_book_id FROM book_recommendations r (.+) LIMIT 10",
+			call: func(repo *postgres.RecommendationRepository, ctx context.Context) ([]int, error) {
+				return repo.GetUserRecommendedBookIDs(ctx, 2, 10)
+			},
+		},
+		{
+			name:          "bestselling books",
+			expectedQuery: "SELECT book_id FROM book_bestsellers WHERE period = \\$1 ORDER BY units_sold DESC, book_id LIMIT 10",
+			call: func(repo *postgres.RecommendationRepository, ctx context.Context) ([]int, error) {
+				return repo.GetBestsellingBookIDs(ctx, 10)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name+" deadline context", func(t *testing.T) {
+			db, _, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			_, err = tc.call(postgres.NewRecommendationRepository(sqlx.NewDb(db, "mock")), fixture.CtxEnded())
+			assert.Error(t, err)
+		})
+
+		t.Run(tc.name+" fail fetch query error", func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mock.ExpectQuery(tc.expectedQuery).WillReturnError(errors.New("fail fetch"))
+
+			_, err = tc.call(postgres.NewRecommendationRepository(sqlx.NewDb(db, "mock")), context.Background())
+			assert.Error(t, err)
+		})
+
+		t.Run(tc.name+" fail scan", func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mock.ExpectQuery(tc.expectedQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("not a number"))
+
+			_, err = tc.call(postgres.NewRecommendationRepository(sqlx.NewDb(db, "mock")), context.Background())
+			assert.Error(t, err)
+		})
+
+		t.Run(tc.name+" success", func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mock.ExpectQuery(tc.expectedQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8).AddRow(7))
+
+			ids, err := tc.call(postgres.NewRecommendationRepository(sqlx.NewDb(db, "mock")), context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, []int{8, 7}, ids)
+		})
+	}
+}
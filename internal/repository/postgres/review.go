@@ -0,0 +1,331 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	dbentity "github.com/satriowisnugroho/book-store/internal/repository/postgres/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+)
+
+// ReviewRepositoryInterface define contract for review related functions to repository
+type ReviewRepositoryInterface interface {
+	CreateReview(ctx context.Context, dbTrx interface{}, review *entity.Review) error
+	GetReviewByID(ctx context.Context, reviewID int) (*entity.Review, error)
+	GetReviewsByBookID(ctx context.Context, bookID, limit, offset int) ([]*entity.Review, error)
+	GetReviewsByBookIDCount(ctx context.Context, bookID int) (int, error)
+	GetReviewsByUserID(ctx context.Context, userID int) ([]*entity.Review, error)
+	GetReviewsByStatus(ctx context.Context, status string, limit, offset int) ([]*entity.Review, error)
+	GetReviewsByStatusCount(ctx context.Context, status string) (int, error)
+	CreateReviewReport(ctx context.Context, dbTrx interface{}, report *entity.ReviewReport) error
+	IncrementReviewReportCount(ctx context.Context, dbTrx interface{}, reviewID, threshold int) (string, error)
+	UpdateReviewsStatus(ctx context.Context, dbTrx interface{}, reviewIDs []int, status string) ([]int, error)
+}
+
+// ReviewRepository holds database connection
+type ReviewRepository struct {
+	db *sqlx.DB
+}
+
+var (
+	// ReviewTableName hold table name for reviews
+	ReviewTableName = "reviews"
+	// ReviewColumns list all columns on reviews table
+	ReviewColumns = []string{"id", "book_id", "user_id", "rating", "body", "verified_purchase", "status", "report_count", "created_at", "updated_at"}
+	// ReviewAttributes hold string format of all reviews table columns
+	ReviewAttributes = strings.Join(ReviewColumns, ", ")
+
+	// ReviewCreationColumns list all columns used for create review
+	ReviewCreationColumns = ReviewColumns[1:]
+	// ReviewCreationAttributes hold string format of all creation review columns
+	ReviewCreationAttributes = strings.Join(ReviewCreationColumns, ", ")
+
+	// ReviewReportTableName hold table name for review_reports
+	ReviewReportTableName = "review_reports"
+	// ReviewReportColumns list all columns on review_reports table
+	ReviewReportColumns = []string{"id", "review_id", "user_id", "reason", "created_at"}
+	// ReviewReportCreationColumns list all columns used for create review report
+	ReviewReportCreationColumns = ReviewReportColumns[1:]
+	// ReviewReportCreationAttributes hold string format of all creation review report columns
+	ReviewReportCreationAttributes = strings.Join(ReviewReportCreationColumns, ", ")
+)
+
+// NewReviewRepository create initiate review repository with given database
+func NewReviewRepository(db *sqlx.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+func (r *ReviewRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]*entity.Review, error) {
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	result := make([]*entity.Review, 0)
+
+	for rows.Next() {
+		tmpEntity := dbentity.Review{}
+		if err := rows.StructScan(&tmpEntity); err != nil {
+			return nil, errors.Wrap(err, "fetch")
+		}
+
+		result = append(result, tmpEntity.ToEntity())
+	}
+
+	return result, nil
+}
+
+// CreateReview insert review data into database
+func (r *ReviewRepository) CreateReview(ctx context.Context, dbTrx interface{}, review *entity.Review) error {
+	functionName := "ReviewRepository.CreateReview"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	review.CreatedAt = now
+	review.UpdatedAt = now
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING id`, ReviewTableName, ReviewCreationAttributes, EnumeratedBindvars(ReviewCreationColumns))
+
+	tx := Tx(r.db, dbTrx)
+	err := tx.QueryRowxContext(
+		ctx,
+		query,
+		review.BookID,
+		review.UserID,
+		review.Rating,
+		review.Body,
+		review.VerifiedPurchase,
+		review.Status,
+		review.ReportCount,
+		review.CreatedAt,
+		review.UpdatedAt,
+	).Scan(&review.ID)
+	if err != nil {
+		if isUniqueConstraintViolation(err) {
+			return response.ErrDuplicateReview
+		}
+
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// GetReviewByID query to get review by ID
+func (r *ReviewRepository) GetReviewByID(ctx context.Context, reviewID int) (*entity.Review, error) {
+	functionName := "ReviewRepository.GetReviewByID"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1 LIMIT 1", ReviewAttributes, ReviewTableName)
+	rows, err := r.fetch(ctx, query, reviewID)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if len(rows) == 0 {
+		return nil, response.ErrNotFound
+	}
+
+	return rows[0], nil
+}
+
+// GetReviewsByBookID query to get list of approved reviews by book ID, the newest first
+func (r *ReviewRepository) GetReviewsByBookID(ctx context.Context, bookID, limit, offset int) ([]*entity.Review, error) {
+	functionName := "ReviewRepository.GetReviewsByBookID"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return []*entity.Review{}, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE book_id = %d AND status = $1 ORDER BY created_at DESC LIMIT %d OFFSET %d", ReviewAttributes, ReviewTableName, bookID, limit, offset)
+
+	rows, err := r.fetch(ctx, query, entity.ReviewStatusApproved)
+	if err != nil {
+		return rows, errors.Wrap(err, functionName)
+	}
+
+	return rows, nil
+}
+
+// GetReviewsByUserID query to get every review written by the user whatever its status
+func (r *ReviewRepository) GetReviewsByUserID(ctx context.Context, userID int) ([]*entity.Review, error) {
+	functionName := "ReviewRepository.GetReviewsByUserID"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return []*entity.Review{}, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = $1 ORDER BY created_at", ReviewAttributes, ReviewTableName)
+
+	rows, err := r.fetch(ctx, query, userID)
+	if err != nil {
+		return rows, errors.Wrap(err, functionName)
+	}
+
+	return rows, nil
+}
+
+// GetReviewsByBookIDCount query to get the count of approved reviews by book ID
+func (r *ReviewRepository) GetReviewsByBookIDCount(ctx context.Context, bookID int) (int, error) {
+	functionName := "ReviewRepository.GetReviewsByBookIDCount"
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return 0, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE book_id = %d AND status = $1", ReviewTableName, bookID)
+
+	count := 0
+	rows := r.db.QueryRowxContext(ctx, query, entity.ReviewStatusApproved)
+	if err := rows.Scan(&count); err != nil {
+		return count, errors.Wrap(err, functionName)
+	}
+
+	return count, nil
+}
+
+// GetReviewsByStatus query to get list of reviews by status, the most reported first then the oldest first
+func (r *ReviewRepository) GetReviewsByStatus(ctx context.Context, status string, limit, offset int) ([]*entity.Review, error) {
+	functionName := "ReviewRepository.GetReviewsByStatus"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return []*entity.Review{}, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE status = $1 ORDER BY report_count DESC, created_at LIMIT %d OFFSET %d", ReviewAttributes, ReviewTableName, limit, offset)
+
+	rows, err := r.fetch(ctx, query, status)
+	if err != nil {
+		return rows, errors.Wrap(err, functionName)
+	}
+
+	return rows, nil
+}
+
+// GetReviewsByStatusCount query to get the count of reviews by status
+func (r *ReviewRepository) GetReviewsByStatusCount(ctx context.Context, status string) (int, error) {
+	functionName := "ReviewRepository.GetReviewsByStatusCount"
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return 0, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE status = $1", ReviewTableName)
+
+	count := 0
+	rows := r.db.QueryRowxContext(ctx, query, status)
+	if err := rows.Scan(&count); err != nil {
+		return count, errors.Wrap(err, functionName)
+	}
+
+	return count, nil
+}
+
+// CreateReviewReport insert review report data into database
+func (r *ReviewRepository) CreateReviewReport(ctx context.Context, dbTrx interface{}, report *entity.ReviewReport) error {
+	functionName := "ReviewRepository.CreateReviewReport"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	report.CreatedAt = time.Now()
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING id`, ReviewReportTableName, ReviewReportCreationAttributes, EnumeratedBindvars(ReviewReportCreationColumns))
+
+	tx := Tx(r.db, dbTrx)
+	err := tx.QueryRowxContext(
+		ctx,
+		query,
+		report.ReviewID,
+		report.UserID,
+		report.Reason,
+		report.CreatedAt,
+	).Scan(&report.ID)
+	if err != nil {
+		if isUniqueConstraintViolation(err) {
+			return response.ErrDuplicateReviewReport
+		}
+
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// IncrementReviewReportCount increment the report count of a review and put an approved review back
+// on hold once the count reaches the threshold, it returns the resulting review status
+func (r *ReviewRepository) IncrementReviewReportCount(ctx context.Context, dbTrx interface{}, reviewID, threshold int) (string, error) {
+	functionName := "ReviewRepository.IncrementReviewReportCount"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return "", errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf(
+		"UPDATE %s SET report_count = report_count + 1, status = CASE WHEN status = $1 AND report_count + 1 >= $2 THEN $3 ELSE status END WHERE id = $4 RETURNING status",
+		ReviewTableName,
+	)
+
+	status := ""
+	tx := Tx(r.db, dbTrx)
+	err := tx.QueryRowxContext(ctx, query, entity.ReviewStatusApproved, threshold, entity.ReviewStatusPending, reviewID).Scan(&status)
+	if err != nil {
+		return "", errors.Wrap(err, functionName)
+	}
+
+	return status, nil
+}
+
+// UpdateReviewsStatus set the status of the reviews and clear their report count,
+// it returns the IDs of the books of the updated reviews
+func (r *ReviewRepository) UpdateReviewsStatus(ctx context.Context, dbTrx interface{}, reviewIDs []int, status string) ([]int, error) {
+	functionName := "ReviewRepository.UpdateReviewsStatus"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET status = $1, report_count = 0, updated_at = $2 WHERE id = ANY($3) RETURNING book_id", ReviewTableName)
+
+	tx := Tx(r.db, dbTrx)
+	rows, err := tx.QueryxContext(ctx, query, status, time.Now(), pq.Array(reviewIDs))
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	defer rows.Close()
+
+	bookIDs := []int{}
+	seenBookIDs := map[int]bool{}
+	for rows.Next() {
+		bookID := 0
+		if err := rows.Scan(&bookID); err != nil {
+			return nil, errors.Wrap(err, functionName)
+		}
+
+		if !seenBookIDs[bookID] {
+			seenBookIDs[bookID] = true
+			bookIDs = append(bookIDs, bookID)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return bookIDs, nil
+}
@@ -0,0 +1,674 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateReview(t *testing.T) {
+	testcases := []struct {
+		name        string
+		ctx         context.Context
+		input       *entity.Review
+		createErr   error
+		expectedErr error
+		wantErr     bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:        "duplicate book & user",
+			ctx:         context.Background(),
+			input:       &entity.Review{},
+			createErr:   &pq.Error{Code: pq.ErrorCode(config.UniqueConstraintViolationCode)},
+			expectedErr: response.ErrDuplicateReview,
+			wantErr:     true,
+		},
+		{
+			name:      "fail exec query",
+			ctx:       context.Background(),
+			input:     &entity.Review{},
+			createErr: errors.New("fail exec"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			input:   &entity.Review{},
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			expectedQuery := "INSERT INTO reviews (.+) VALUES (.+) RETURNING id"
+			if tc.createErr != nil {
+				mock.ExpectQuery(expectedQuery).WillReturnError(tc.createErr)
+			} else {
+				row := sqlmock.NewRows([]string{"id"})
+				result := row.AddRow(1)
+				mock.ExpectQuery(expectedQuery).WillReturnRows(result)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReviewRepository(dbx)
+
+			err = repo.CreateReview(tc.ctx, nil, tc.input)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+			if !tc.wantErr {
+				assert.Equal(t, 1, tc.input.ID)
+			}
+		})
+	}
+}
+
+func TestGetReviewsByBookID(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  []*entity.Review
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.ReviewColumns,
+			expected:  []*entity.Review{{Rating: 5, Body: "Magical", VerifiedPurchase: true, Status: entity.ReviewStatusApproved}},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT .+ FROM reviews WHERE book_id = .+ AND status = \\$1 ORDER BY .+ LIMIT .+ OFFSET .+").WithArgs("approved")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = rows.AddRow(
+						tc.expected[0].ID,
+						tc.expected[0].BookID,
+						tc.expected[0].UserID,
+						tc.expected[0].Rating,
+						tc.expected[0].Body,
+						tc.expected[0].VerifiedPurchase,
+						tc.expected[0].Status,
+						tc.expected[0].ReportCount,
+						tc.expected[0].CreatedAt,
+						tc.expected[0].UpdatedAt,
+					)
+				} else if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReviewRepository(dbx)
+			result, err := repo.GetReviewsByBookID(tc.ctx, 1, 10, 0)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetReviewsByUserID(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  []*entity.Review
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.ReviewColumns,
+			expected:  []*entity.Review{{UserID: 2, Rating: 1, Body: "Boring", Status: entity.ReviewStatusRejected}},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT .+ FROM reviews WHERE user_id = \\$1 ORDER BY created_at").WithArgs(2)
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = rows.AddRow(
+						tc.expected[0].ID,
+						tc.expected[0].BookID,
+						tc.expected[0].UserID,
+						tc.expected[0].Rating,
+						tc.expected[0].Body,
+						tc.expected[0].VerifiedPurchase,
+						tc.expected[0].Status,
+						tc.expected[0].ReportCount,
+						tc.expected[0].CreatedAt,
+						tc.expected[0].UpdatedAt,
+					)
+				} else if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReviewRepository(dbx)
+			result, err := repo.GetReviewsByUserID(tc.ctx, 2)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetReviewsByBookIDCount(t *testing.T) {
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		fetchErr error
+		expected int
+		wantErr  bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			expected: 1,
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM reviews WHERE book_id = .+ AND status = \\$1").WithArgs("approved")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows([]string{"COUNT(*)"})
+				rows = rows.AddRow(tc.expected)
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReviewRepository(dbx)
+			result, err := repo.GetReviewsByBookIDCount(tc.ctx, 1)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetReviewByID(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  *entity.Review
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "record not found",
+			ctx:       context.Background(),
+			fetchRows: postgres.ReviewColumns,
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.ReviewColumns,
+			expected:  &entity.Review{ID: 1, Rating: 4, Status: entity.ReviewStatusPending},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM reviews WHERE id = \\$1 LIMIT 1").WithArgs(1)
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = rows.AddRow(
+						tc.expected.ID,
+						tc.expected.BookID,
+						tc.expected.UserID,
+						tc.expected.Rating,
+						tc.expected.Body,
+						tc.expected.VerifiedPurchase,
+						tc.expected.Status,
+						tc.expected.ReportCount,
+						tc.expected.CreatedAt,
+						tc.expected.UpdatedAt,
+					)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReviewRepository(dbx)
+			result, err := repo.GetReviewByID(tc.ctx, 1)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetReviewsByStatus(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  []*entity.Review
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.ReviewColumns,
+			expected:  []*entity.Review{{Status: entity.ReviewStatusPending, ReportCount: 3}},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT .+ FROM reviews WHERE status = \\$1 ORDER BY report_count DESC, created_at LIMIT .+ OFFSET .+").WithArgs("pending")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				for _, review := range tc.expected {
+					rows = rows.AddRow(review.ID, review.BookID, review.UserID, review.Rating, review.Body, review.VerifiedPurchase, review.Status, review.ReportCount, review.CreatedAt, review.UpdatedAt)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReviewRepository(dbx)
+			result, err := repo.GetReviewsByStatus(tc.ctx, entity.ReviewStatusPending, 10, 0)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetReviewsByStatusCount(t *testing.T) {
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		fetchErr error
+		expected int
+		wantErr  bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			expected: 1,
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM reviews WHERE status = \\$1").WithArgs("pending")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(tc.expected))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReviewRepository(dbx)
+			result, err := repo.GetReviewsByStatusCount(tc.ctx, entity.ReviewStatusPending)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestCreateReviewReport(t *testing.T) {
+	testcases := []struct {
+		name        string
+		ctx         context.Context
+		createErr   error
+		expectedErr error
+		wantErr     bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:        "duplicate review & user",
+			ctx:         context.Background(),
+			createErr:   &pq.Error{Code: pq.ErrorCode(config.UniqueConstraintViolationCode)},
+			expectedErr: response.ErrDuplicateReviewReport,
+			wantErr:     true,
+		},
+		{
+			name:      "fail exec query",
+			ctx:       context.Background(),
+			createErr: errors.New("fail exec"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			expectedQuery := "INSERT INTO review_reports (.+) VALUES (.+) RETURNING id"
+			if tc.createErr != nil {
+				mock.ExpectQuery(expectedQuery).WillReturnError(tc.createErr)
+			} else {
+				mock.ExpectQuery(expectedQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReviewRepository(dbx)
+
+			report := &entity.ReviewReport{ReviewID: 1, UserID: 2, Reason: "spam"}
+			err = repo.CreateReviewReport(tc.ctx, nil, report)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+			if !tc.wantErr {
+				assert.Equal(t, 1, report.ID)
+			}
+		})
+	}
+}
+
+func TestIncrementReviewReportCount(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		updateErr error
+		expected  string
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			expected: entity.ReviewStatusPending,
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("UPDATE reviews SET report_count = report_count \\+ 1, status = CASE .+ END WHERE id = \\$4 RETURNING status").
+				WithArgs("approved", 3, "pending", 1)
+			if tc.updateErr != nil {
+				mockExpectedQuery.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(tc.expected))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReviewRepository(dbx)
+			status, err := repo.IncrementReviewReportCount(tc.ctx, nil, 1, 3)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.expected, status)
+		})
+	}
+}
+
+func TestUpdateReviewsStatus(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		updateErr error
+		fetchRows []string
+		expected  []int
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:      "fail scan",
+			ctx:       context.Background(),
+			fetchRows: []string{"book_id"},
+			expected:  nil,
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: []string{"book_id"},
+			expected:  []int{7, 8},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("UPDATE reviews SET status = \\$1, report_count = 0, updated_at = \\$2 WHERE id = ANY\\(\\$3\\) RETURNING book_id").
+				WithArgs("rejected", sqlmock.AnyArg(), sqlmock.AnyArg())
+			if tc.updateErr != nil {
+				mockExpectedQuery.WillReturnError(tc.updateErr)
+			} else if tc.fetchRows != nil {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected == nil {
+					rows = rows.AddRow("not a number")
+				} else {
+					rows = rows.AddRow(7).AddRow(8).AddRow(7)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewReviewRepository(dbx)
+			bookIDs, err := repo.UpdateReviewsStatus(tc.ctx, nil, []int{1, 2, 3}, entity.ReviewStatusRejected)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.expected, bookIDs)
+		})
+	}
+}
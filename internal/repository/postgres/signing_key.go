@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	dbentity "github.com/satriowisnugroho/book-store/internal/repository/postgres/entity"
+)
+
+// SigningKeyRepositoryInterface define contract for JWT signing key related functions to repository
+type SigningKeyRepositoryInterface interface {
+	LockSigningKeys(ctx context.Context, dbTrx interface{}) error
+	GetSigningKeys(ctx context.Context, dbTrx interface{}) ([]*entity.SigningKey, error)
+	CreateSigningKey(ctx context.Context, dbTrx interface{}, key *entity.SigningKey) error
+	DeleteSigningKeysCreatedBefore(ctx context.Context, dbTrx interface{}, createdBefore time.Time) error
+}
+
+// SigningKeyRepository holds database connection
+type SigningKeyRepository struct {
+	db *sqlx.DB
+}
+
+var (
+	// SigningKeyTableName hold table name for signing_keys
+	SigningKeyTableName = "signing_keys"
+	// SigningKeyColumns list all columns on signing_keys table
+	SigningKeyColumns = []string{"id", "key_id", "algorithm", "private_key", "created_at"}
+	// SigningKeyAttributes hold string format of all signing_keys table columns
+	SigningKeyAttributes = strings.Join(SigningKeyColumns, ", ")
+
+	// SigningKeyCreationColumns list all columns used for create signing key
+	SigningKeyCreationColumns = SigningKeyColumns[1:]
+	// SigningKeyCreationAttributes hold string format of all creation signing key columns
+	SigningKeyCreationAttributes = strings.Join(SigningKeyCreationColumns, ", ")
+)
+
+// signingKeysLockKey is the advisory lock key held while the signing keys are rotated,
+// so several API instances starting at the same time do not each create a new key
+const signingKeysLockKey = 20240621
+
+// NewSigningKeyRepository create initiate signing key repository with given database
+func NewSigningKeyRepository(db *sqlx.DB) *SigningKeyRepository {
+	return &SigningKeyRepository{db: db}
+}
+
+// LockSigningKeys waits for the advisory lock of the signing keys, which is released at the end of the transaction
+func (r *SigningKeyRepository) LockSigningKeys(ctx context.Context, dbTrx interface{}) error {
+	functionName := "SigningKeyRepository.LockSigningKeys"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", signingKeysLockKey); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// GetSigningKeys query to get every signing key, newest first
+func (r *SigningKeyRepository) GetSigningKeys(ctx context.Context, dbTrx interface{}) ([]*entity.SigningKey, error) {
+	functionName := "SigningKeyRepository.GetSigningKeys"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY created_at DESC, id DESC", SigningKeyAttributes, SigningKeyTableName)
+	rows, err := Tx(r.db, dbTrx).QueryxContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	defer rows.Close()
+
+	result := make([]*entity.SigningKey, 0)
+
+	for rows.Next() {
+		tmpEntity := dbentity.SigningKey{}
+		if err := rows.StructScan(&tmpEntity); err != nil {
+			return nil, errors.Wrap(err, functionName)
+		}
+
+		result = append(result, tmpEntity.ToEntity())
+	}
+
+	return result, nil
+}
+
+// CreateSigningKey insert signing key data into database
+func (r *SigningKeyRepository) CreateSigningKey(ctx context.Context, dbTrx interface{}, key *entity.SigningKey) error {
+	functionName := "SigningKeyRepository.CreateSigningKey"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING id`, SigningKeyTableName, SigningKeyCreationAttributes, EnumeratedBindvars(SigningKeyCreationColumns))
+
+	err := Tx(r.db, dbTrx).QueryRowxContext(
+		ctx,
+		query,
+		key.KeyID,
+		key.Algorithm,
+		key.PrivateKey,
+		key.CreatedAt,
+	).Scan(&key.ID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// DeleteSigningKeysCreatedBefore delete the signing keys created before the given time, the newest key is always kept
+func (r *SigningKeyRepository) DeleteSigningKeysCreatedBefore(ctx context.Context, dbTrx interface{}, createdBefore time.Time) error {
+	functionName := "SigningKeyRepository.DeleteSigningKeysCreatedBefore"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf(
+		"DELETE FROM %s WHERE created_at < $1 AND id <> (SELECT id FROM %s ORDER BY created_at DESC, id DESC LIMIT 1)",
+		SigningKeyTableName,
+		SigningKeyTableName,
+	)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, createdBefore); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
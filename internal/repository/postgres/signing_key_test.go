@@ -0,0 +1,234 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockSigningKeys(t *testing.T) {
+	testcases := []struct {
+		name    string
+		ctx     context.Context
+		lockErr error
+		wantErr bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "fail lock",
+			ctx:     context.Background(),
+			lockErr: errors.New("fail lock"),
+			wantErr: true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("SELECT pg_advisory_xact_lock\\(\\$1\\)")
+			if tc.lockErr != nil {
+				mockExpectedExec.WillReturnError(tc.lockErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewSigningKeyRepository(dbx)
+			err = repo.LockSigningKeys(tc.ctx, nil)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestGetSigningKeys(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  []*entity.SigningKey
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.SigningKeyColumns,
+			expected:  []*entity.SigningKey{{ID: 1, KeyID: "kid", Algorithm: "EdDSA", PrivateKey: "pem"}},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM signing_keys ORDER BY created_at DESC, id DESC$")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				for _, key := range tc.expected {
+					rows = rows.AddRow(key.ID, key.KeyID, key.Algorithm, key.PrivateKey, key.CreatedAt)
+				}
+				if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewSigningKeyRepository(dbx)
+			result, err := repo.GetSigningKeys(tc.ctx, nil)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestCreateSigningKey(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		createErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail create",
+			ctx:       context.Background(),
+			createErr: errors.New("fail create"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("INSERT INTO signing_keys \\(key_id, algorithm, private_key, created_at\\) VALUES (.+) RETURNING id")
+			if tc.createErr != nil {
+				mockExpectedQuery.WillReturnError(tc.createErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewSigningKeyRepository(dbx)
+			key := &entity.SigningKey{KeyID: "kid", Algorithm: "EdDSA", PrivateKey: "pem"}
+			err = repo.CreateSigningKey(tc.ctx, nil, key)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, 1, key.ID)
+				assert.False(t, key.CreatedAt.IsZero())
+			}
+		})
+	}
+}
+
+func TestDeleteSigningKeysCreatedBefore(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		deleteErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail delete",
+			ctx:       context.Background(),
+			deleteErr: errors.New("fail delete"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			createdBefore := time.Now()
+			mockExpectedExec := mock.ExpectExec("DELETE FROM signing_keys WHERE created_at < \\$1 AND id <> \\(SELECT id FROM signing_keys ORDER BY created_at DESC, id DESC LIMIT 1\\)").WithArgs(createdBefore)
+			if tc.deleteErr != nil {
+				mockExpectedExec.WillReturnError(tc.deleteErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewSigningKeyRepository(dbx)
+			err = repo.DeleteSigningKeysCreatedBefore(tc.ctx, nil, createdBefore)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
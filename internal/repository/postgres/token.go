@@ -0,0 +1,834 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	dbentity "github.com/satriowisnugroho/book-store/internal/repository/postgres/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+)
+
+// TokenRepositoryInterface define contract for refresh token and revoked access token related functions to repository
+type TokenRepositoryInterface interface {
+	CreateRefreshToken(ctx context.Context, dbTrx interface{}, token *entity.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, dbTrx interface{}, tokenHash string) (*entity.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, dbTrx interface{}, tokenID int) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, dbTrx interface{}, familyID string) error
+	RevokeUserRefreshTokens(ctx context.Context, dbTrx interface{}, userID int, keepSessionID int) error
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	CreatePasswordResetToken(ctx context.Context, token *entity.PasswordResetToken) error
+	GetPasswordResetTokenByHash(ctx context.Context, dbTrx interface{}, tokenHash string) (*entity.PasswordResetToken, error)
+	UsePasswordResetTokens(ctx context.Context, dbTrx interface{}, userID int) error
+	CreateEmailVerificationToken(ctx context.Context, token *entity.EmailVerificationToken) error
+	GetEmailVerificationTokenByHash(ctx context.Context, dbTrx interface{}, tokenHash string) (*entity.EmailVerificationToken, error)
+	UseEmailVerificationTokens(ctx context.Context, dbTrx interface{}, userID int) error
+	CountEmailVerificationTokensSince(ctx context.Context, userID int, since time.Time) (int, error)
+	CreateLoginChallenge(ctx context.Context, challenge *entity.LoginChallenge) error
+	GetLoginChallengeByHash(ctx context.Context, dbTrx interface{}, tokenHash string) (*entity.LoginChallenge, error)
+	UseLoginChallenge(ctx context.Context, dbTrx interface{}, challengeID int) error
+	CreateOIDCLoginState(ctx context.Context, state *entity.OIDCLoginState) error
+	GetOIDCLoginStateByHash(ctx context.Context, dbTrx interface{}, stateHash string) (*entity.OIDCLoginState, error)
+	UseOIDCLoginState(ctx context.Context, dbTrx interface{}, stateID int) error
+	CreateSession(ctx context.Context, dbTrx interface{}, session *entity.Session) error
+	GetSessionByFamilyID(ctx context.Context, dbTrx interface{}, familyID string) (*entity.Session, error)
+	GetSessionsByUserID(ctx context.Context, userID int) ([]*entity.Session, error)
+	TouchSession(ctx context.Context, dbTrx interface{}, sessionID int, ipAddress string, expiresAt time.Time) error
+	RevokeSession(ctx context.Context, dbTrx interface{}, userID int, sessionID int) error
+	IsSessionRevoked(ctx context.Context, sessionID int) (bool, error)
+	DeleteExpiredTokens(ctx context.Context) error
+}
+
+// TokenRepository holds database connection
+type TokenRepository struct {
+	db *sqlx.DB
+}
+
+var (
+	// RefreshTokenTableName hold table name for refresh_tokens
+	RefreshTokenTableName = "refresh_tokens"
+	// RefreshTokenColumns list all columns on refresh_tokens table
+	RefreshTokenColumns = []string{"id", "user_id", "family_id", "token_hash", "expires_at", "revoked_at", "created_at", "updated_at"}
+	// RefreshTokenAttributes hold string format of all refresh_tokens table columns
+	RefreshTokenAttributes = strings.Join(RefreshTokenColumns, ", ")
+
+	// RefreshTokenCreationColumns list all columns used for create refresh token
+	RefreshTokenCreationColumns = RefreshTokenColumns[1:]
+	// RefreshTokenCreationAttributes hold string format of all creation refresh token columns
+	RefreshTokenCreationAttributes = strings.Join(RefreshTokenCreationColumns, ", ")
+
+	// RevokedAccessTokenTableName hold table name for revoked_access_tokens
+	RevokedAccessTokenTableName = "revoked_access_tokens"
+
+	// PasswordResetTokenTableName hold table name for password_reset_tokens
+	PasswordResetTokenTableName = "password_reset_tokens"
+	// PasswordResetTokenColumns list all columns on password_reset_tokens table
+	PasswordResetTokenColumns = []string{"id", "user_id", "token_hash", "expires_at", "used_at", "created_at"}
+	// PasswordResetTokenAttributes hold string format of all password_reset_tokens table columns
+	PasswordResetTokenAttributes = strings.Join(PasswordResetTokenColumns, ", ")
+
+	// PasswordResetTokenCreationColumns list all columns used for create password reset token
+	PasswordResetTokenCreationColumns = PasswordResetTokenColumns[1:]
+	// PasswordResetTokenCreationAttributes hold string format of all creation password reset token columns
+	PasswordResetTokenCreationAttributes = strings.Join(PasswordResetTokenCreationColumns, ", ")
+
+	// EmailVerificationTokenTableName hold table name for email_verification_tokens
+	EmailVerificationTokenTableName = "email_verification_tokens"
+	// EmailVerificationTokenColumns list all columns on email_verification_tokens table
+	EmailVerificationTokenColumns = []string{"id", "user_id", "new_email", "token_hash", "expires_at", "used_at", "created_at"}
+	// EmailVerificationTokenAttributes hold string format of all email_verification_tokens table columns
+	EmailVerificationTokenAttributes = strings.Join(EmailVerificationTokenColumns, ", ")
+
+	// EmailVerificationTokenCreationColumns list all columns used for create email verification token
+	EmailVerificationTokenCreationColumns = EmailVerificationTokenColumns[1:]
+	// EmailVerificationTokenCreationAttributes hold string format of all creation email verification token columns
+	EmailVerificationTokenCreationAttributes = strings.Join(EmailVerificationTokenCreationColumns, ", ")
+
+	// LoginChallengeTableName hold table name for login_challenges
+	LoginChallengeTableName = "login_challenges"
+	// LoginChallengeColumns list all columns on login_challenges table
+	LoginChallengeColumns = []string{"id", "user_id", "token_hash", "expires_at", "used_at", "created_at"}
+	// LoginChallengeAttributes hold string format of all login_challenges table columns
+	LoginChallengeAttributes = strings.Join(LoginChallengeColumns, ", ")
+
+	// LoginChallengeCreationColumns list all columns used for create login challenge
+	LoginChallengeCreationColumns = LoginChallengeColumns[1:]
+	// LoginChallengeCreationAttributes hold string format of all creation login challenge columns
+	LoginChallengeCreationAttributes = strings.Join(LoginChallengeCreationColumns, ", ")
+
+	// OIDCLoginStateTableName hold table name for oidc_login_states
+	OIDCLoginStateTableName = "oidc_login_states"
+	// OIDCLoginStateColumns list all columns on oidc_login_states table
+	OIDCLoginStateColumns = []string{"id", "state_hash", "nonce", "code_verifier", "expires_at", "used_at", "created_at"}
+	// OIDCLoginStateAttributes hold string format of all oidc_login_states table columns
+	OIDCLoginStateAttributes = strings.Join(OIDCLoginStateColumns, ", ")
+
+	// OIDCLoginStateCreationColumns list all columns used for create OpenID Connect login state
+	OIDCLoginStateCreationColumns = OIDCLoginStateColumns[1:]
+	// OIDCLoginStateCreationAttributes hold string format of all creation OpenID Connect login state columns
+	OIDCLoginStateCreationAttributes = strings.Join(OIDCLoginStateCreationColumns, ", ")
+
+	// SessionTableName hold table name for sessions
+	SessionTableName = "sessions"
+	// SessionColumns list all columns on sessions table
+	SessionColumns = []string{"id", "user_id", "family_id", "device", "user_agent", "ip_address", "expires_at", "revoked_at", "last_seen_at", "created_at"}
+	// SessionAttributes hold string format of all sessions table columns
+	SessionAttributes = strings.Join(SessionColumns, ", ")
+
+	// SessionCreationColumns list all columns used for create session
+	SessionCreationColumns = SessionColumns[1:]
+	// SessionCreationAttributes hold string format of all creation session columns
+	SessionCreationAttributes = strings.Join(SessionCreationColumns, ", ")
+)
+
+// NewTokenRepository create initiate token repository with given database
+func NewTokenRepository(db *sqlx.DB) *TokenRepository {
+	return &TokenRepository{db: db}
+}
+
+func (r *TokenRepository) fetch(ctx context.Context, dbTrx interface{}, query string, args ...interface{}) ([]*entity.RefreshToken, error) {
+	rows, err := Tx(r.db, dbTrx).QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	result := make([]*entity.RefreshToken, 0)
+
+	for rows.Next() {
+		tmpEntity := dbentity.RefreshToken{}
+		if err := rows.StructScan(&tmpEntity); err != nil {
+			return nil, errors.Wrap(err, "fetch")
+		}
+
+		result = append(result, tmpEntity.ToEntity())
+	}
+
+	return result, nil
+}
+
+// CreateRefreshToken insert refresh token data into database
+func (r *TokenRepository) CreateRefreshToken(ctx context.Context, dbTrx interface{}, token *entity.RefreshToken) error {
+	functionName := "TokenRepository.CreateRefreshToken"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	token.CreatedAt = now
+	token.UpdatedAt = now
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING id`, RefreshTokenTableName, RefreshTokenCreationAttributes, EnumeratedBindvars(RefreshTokenCreationColumns))
+
+	err := Tx(r.db, dbTrx).QueryRowxContext(
+		ctx,
+		query,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.RevokedAt,
+		token.CreatedAt,
+		token.UpdatedAt,
+	).Scan(&token.ID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// GetRefreshTokenByHash query to get refresh token by the hash of the token,
+// the row is locked until the end of the transaction when called within one
+func (r *TokenRepository) GetRefreshTokenByHash(ctx context.Context, dbTrx interface{}, tokenHash string) (*entity.RefreshToken, error) {
+	functionName := "TokenRepository.GetRefreshTokenByHash"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE token_hash = $1 LIMIT 1", RefreshTokenAttributes, RefreshTokenTableName)
+	if dbTrx != nil {
+		query += " FOR UPDATE"
+	}
+
+	rows, err := r.fetch(ctx, dbTrx, query, tokenHash)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if len(rows) == 0 {
+		return nil, response.ErrNotFound
+	}
+
+	return rows[0], nil
+}
+
+// RevokeRefreshToken revoke a refresh token, it returns false when the token was already revoked
+func (r *TokenRepository) RevokeRefreshToken(ctx context.Context, dbTrx interface{}, tokenID int) (bool, error) {
+	functionName := "TokenRepository.RevokeRefreshToken"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	query := fmt.Sprintf("UPDATE %s SET revoked_at = $1, updated_at = $1 WHERE id = $2 AND revoked_at IS NULL", RefreshTokenTableName)
+	result, err := Tx(r.db, dbTrx).ExecContext(ctx, query, now, tokenID)
+	if err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	return affected > 0, nil
+}
+
+// RevokeRefreshTokenFamily revoke every refresh token rotated from the same login
+func (r *TokenRepository) RevokeRefreshTokenFamily(ctx context.Context, dbTrx interface{}, familyID string) error {
+	functionName := "TokenRepository.RevokeRefreshTokenFamily"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	query := fmt.Sprintf("UPDATE %s SET revoked_at = $1, updated_at = $1 WHERE family_id = $2 AND revoked_at IS NULL", RefreshTokenTableName)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, now, familyID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL", SessionTableName)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, now, familyID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// RevokeUserRefreshTokens revoke every refresh token and session of the user but the session keepSessionID,
+// logging out all of its other sessions. Every session is logged out with a keepSessionID of 0
+func (r *TokenRepository) RevokeUserRefreshTokens(ctx context.Context, dbTrx interface{}, userID int, keepSessionID int) error {
+	functionName := "TokenRepository.RevokeUserRefreshTokens"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	query := fmt.Sprintf(
+		"UPDATE %s SET revoked_at = $1, updated_at = $1 WHERE user_id = $2 AND revoked_at IS NULL AND family_id NOT IN (SELECT family_id FROM %s WHERE id = $3)",
+		RefreshTokenTableName,
+		SessionTableName,
+	)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, now, userID, keepSessionID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET revoked_at = $1 WHERE user_id = $2 AND id <> $3 AND revoked_at IS NULL", SessionTableName)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, now, userID, keepSessionID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// RevokeAccessToken add the ID of an access token to the denylist until the token expires
+func (r *TokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	functionName := "TokenRepository.RevokeAccessToken"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING", RevokedAccessTokenTableName)
+	if _, err := r.db.ExecContext(ctx, query, jti, expiresAt); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// IsAccessTokenRevoked query whether the ID of an access token is in the denylist
+func (r *TokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	functionName := "TokenRepository.IsAccessTokenRevoked"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	revoked := false
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE jti = $1)", RevokedAccessTokenTableName)
+	if err := r.db.QueryRowxContext(ctx, query, jti).Scan(&revoked); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	return revoked, nil
+}
+
+// CreatePasswordResetToken insert password reset token data into database
+func (r *TokenRepository) CreatePasswordResetToken(ctx context.Context, token *entity.PasswordResetToken) error {
+	functionName := "TokenRepository.CreatePasswordResetToken"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	token.CreatedAt = time.Now()
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING id`, PasswordResetTokenTableName, PasswordResetTokenCreationAttributes, EnumeratedBindvars(PasswordResetTokenCreationColumns))
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.UsedAt,
+		token.CreatedAt,
+	).Scan(&token.ID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// GetPasswordResetTokenByHash query to get password reset token by the hash of the token,
+// the row is locked until the end of the transaction when called within one
+func (r *TokenRepository) GetPasswordResetTokenByHash(ctx context.Context, dbTrx interface{}, tokenHash string) (*entity.PasswordResetToken, error) {
+	functionName := "TokenRepository.GetPasswordResetTokenByHash"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE token_hash = $1 LIMIT 1", PasswordResetTokenAttributes, PasswordResetTokenTableName)
+	if dbTrx != nil {
+		query += " FOR UPDATE"
+	}
+
+	rows, err := Tx(r.db, dbTrx).QueryxContext(ctx, query, tokenHash)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, response.ErrNotFound
+	}
+
+	tmpEntity := dbentity.PasswordResetToken{}
+	if err := rows.StructScan(&tmpEntity); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return tmpEntity.ToEntity(), nil
+}
+
+// UsePasswordResetTokens mark every unused password reset token of the user as used
+func (r *TokenRepository) UsePasswordResetTokens(ctx context.Context, dbTrx interface{}, userID int) error {
+	functionName := "TokenRepository.UsePasswordResetTokens"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL", PasswordResetTokenTableName)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, time.Now(), userID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// CreateEmailVerificationToken insert email verification token data into database
+func (r *TokenRepository) CreateEmailVerificationToken(ctx context.Context, token *entity.EmailVerificationToken) error {
+	functionName := "TokenRepository.CreateEmailVerificationToken"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	token.CreatedAt = time.Now()
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING id`, EmailVerificationTokenTableName, EmailVerificationTokenCreationAttributes, EnumeratedBindvars(EmailVerificationTokenCreationColumns))
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		token.UserID,
+		token.NewEmail,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.UsedAt,
+		token.CreatedAt,
+	).Scan(&token.ID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// GetEmailVerificationTokenByHash query to get email verification token by the hash of the token,
+// the row is locked until the end of the transaction when called within one
+func (r *TokenRepository) GetEmailVerificationTokenByHash(ctx context.Context, dbTrx interface{}, tokenHash string) (*entity.EmailVerificationToken, error) {
+	functionName := "TokenRepository.GetEmailVerificationTokenByHash"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE token_hash = $1 LIMIT 1", EmailVerificationTokenAttributes, EmailVerificationTokenTableName)
+	if dbTrx != nil {
+		query += " FOR UPDATE"
+	}
+
+	rows, err := Tx(r.db, dbTrx).QueryxContext(ctx, query, tokenHash)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, response.ErrNotFound
+	}
+
+	tmpEntity := dbentity.EmailVerificationToken{}
+	if err := rows.StructScan(&tmpEntity); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return tmpEntity.ToEntity(), nil
+}
+
+// UseEmailVerificationTokens mark every unused email verification token of the user as used
+func (r *TokenRepository) UseEmailVerificationTokens(ctx context.Context, dbTrx interface{}, userID int) error {
+	functionName := "TokenRepository.UseEmailVerificationTokens"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL", EmailVerificationTokenTableName)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, time.Now(), userID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// CountEmailVerificationTokensSince query the number of email verification tokens issued to the user since the given time
+func (r *TokenRepository) CountEmailVerificationTokensSince(ctx context.Context, userID int, since time.Time) (int, error) {
+	functionName := "TokenRepository.CountEmailVerificationTokensSince"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return 0, errors.Wrap(err, functionName)
+	}
+
+	count := 0
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE user_id = $1 AND created_at >= $2", EmailVerificationTokenTableName)
+	if err := r.db.QueryRowxContext(ctx, query, userID, since).Scan(&count); err != nil {
+		return 0, errors.Wrap(err, functionName)
+	}
+
+	return count, nil
+}
+
+// CreateLoginChallenge insert login challenge data into database
+func (r *TokenRepository) CreateLoginChallenge(ctx context.Context, challenge *entity.LoginChallenge) error {
+	functionName := "TokenRepository.CreateLoginChallenge"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	challenge.CreatedAt = time.Now()
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING id`, LoginChallengeTableName, LoginChallengeCreationAttributes, EnumeratedBindvars(LoginChallengeCreationColumns))
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		challenge.UserID,
+		challenge.TokenHash,
+		challenge.ExpiresAt,
+		challenge.UsedAt,
+		challenge.CreatedAt,
+	).Scan(&challenge.ID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// GetLoginChallengeByHash query to get login challenge by the hash of the challenge token,
+// the row is locked until the end of the transaction when called within one
+func (r *TokenRepository) GetLoginChallengeByHash(ctx context.Context, dbTrx interface{}, tokenHash string) (*entity.LoginChallenge, error) {
+	functionName := "TokenRepository.GetLoginChallengeByHash"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE token_hash = $1 LIMIT 1", LoginChallengeAttributes, LoginChallengeTableName)
+	if dbTrx != nil {
+		query += " FOR UPDATE"
+	}
+
+	rows, err := Tx(r.db, dbTrx).QueryxContext(ctx, query, tokenHash)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, response.ErrNotFound
+	}
+
+	tmpEntity := dbentity.LoginChallenge{}
+	if err := rows.StructScan(&tmpEntity); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return tmpEntity.ToEntity(), nil
+}
+
+// UseLoginChallenge mark the login challenge as used
+func (r *TokenRepository) UseLoginChallenge(ctx context.Context, dbTrx interface{}, challengeID int) error {
+	functionName := "TokenRepository.UseLoginChallenge"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET used_at = $1 WHERE id = $2", LoginChallengeTableName)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, time.Now(), challengeID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// CreateOIDCLoginState insert OpenID Connect login state data into database
+func (r *TokenRepository) CreateOIDCLoginState(ctx context.Context, state *entity.OIDCLoginState) error {
+	functionName := "TokenRepository.CreateOIDCLoginState"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	state.CreatedAt = time.Now()
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING id`, OIDCLoginStateTableName, OIDCLoginStateCreationAttributes, EnumeratedBindvars(OIDCLoginStateCreationColumns))
+
+	err := r.db.QueryRowxContext(
+		ctx,
+		query,
+		state.StateHash,
+		state.Nonce,
+		state.CodeVerifier,
+		state.ExpiresAt,
+		state.UsedAt,
+		state.CreatedAt,
+	).Scan(&state.ID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// GetOIDCLoginStateByHash query to get OpenID Connect login state by the hash of the state,
+// the row is locked until the end of the transaction when called within one
+func (r *TokenRepository) GetOIDCLoginStateByHash(ctx context.Context, dbTrx interface{}, stateHash string) (*entity.OIDCLoginState, error) {
+	functionName := "TokenRepository.GetOIDCLoginStateByHash"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE state_hash = $1 LIMIT 1", OIDCLoginStateAttributes, OIDCLoginStateTableName)
+	if dbTrx != nil {
+		query += " FOR UPDATE"
+	}
+
+	rows, err := Tx(r.db, dbTrx).QueryxContext(ctx, query, stateHash)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, response.ErrNotFound
+	}
+
+	tmpEntity := dbentity.OIDCLoginState{}
+	if err := rows.StructScan(&tmpEntity); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return tmpEntity.ToEntity(), nil
+}
+
+// UseOIDCLoginState mark the OpenID Connect login state as used
+func (r *TokenRepository) UseOIDCLoginState(ctx context.Context, dbTrx interface{}, stateID int) error {
+	functionName := "TokenRepository.UseOIDCLoginState"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET used_at = $1 WHERE id = $2", OIDCLoginStateTableName)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, time.Now(), stateID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+func (r *TokenRepository) fetchSessions(ctx context.Context, dbTrx interface{}, query string, args ...interface{}) ([]*entity.Session, error) {
+	rows, err := Tx(r.db, dbTrx).QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	result := make([]*entity.Session, 0)
+
+	for rows.Next() {
+		tmpEntity := dbentity.Session{}
+		if err := rows.StructScan(&tmpEntity); err != nil {
+			return nil, errors.Wrap(err, "fetchSessions")
+		}
+
+		result = append(result, tmpEntity.ToEntity())
+	}
+
+	return result, nil
+}
+
+// CreateSession insert session data into database
+func (r *TokenRepository) CreateSession(ctx context.Context, dbTrx interface{}, session *entity.Session) error {
+	functionName := "TokenRepository.CreateSession"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	session.LastSeenAt = now
+	session.CreatedAt = now
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING id`, SessionTableName, SessionCreationAttributes, EnumeratedBindvars(SessionCreationColumns))
+
+	err := Tx(r.db, dbTrx).QueryRowxContext(
+		ctx,
+		query,
+		session.UserID,
+		session.FamilyID,
+		session.Device,
+		session.UserAgent,
+		session.IPAddress,
+		session.ExpiresAt,
+		session.RevokedAt,
+		session.LastSeenAt,
+		session.CreatedAt,
+	).Scan(&session.ID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// GetSessionByFamilyID query to get session by the family of its refresh tokens,
+// the row is locked until the end of the transaction when called within one
+func (r *TokenRepository) GetSessionByFamilyID(ctx context.Context, dbTrx interface{}, familyID string) (*entity.Session, error) {
+	functionName := "TokenRepository.GetSessionByFamilyID"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE family_id = $1 LIMIT 1", SessionAttributes, SessionTableName)
+	if dbTrx != nil {
+		query += " FOR UPDATE"
+	}
+
+	rows, err := r.fetchSessions(ctx, dbTrx, query, familyID)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if len(rows) == 0 {
+		return nil, response.ErrNotFound
+	}
+
+	return rows[0], nil
+}
+
+// GetSessionsByUserID query to get the sessions of the user which are neither revoked nor expired, the last seen first
+func (r *TokenRepository) GetSessionsByUserID(ctx context.Context, userID int) ([]*entity.Session, error) {
+	functionName := "TokenRepository.GetSessionsByUserID"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return []*entity.Session{}, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2 ORDER BY last_seen_at DESC", SessionAttributes, SessionTableName)
+	rows, err := r.fetchSessions(ctx, nil, query, userID, time.Now())
+	if err != nil {
+		return rows, errors.Wrap(err, functionName)
+	}
+
+	return rows, nil
+}
+
+// TouchSession record that the session refreshed its access token from the IP address, and extend it until expiresAt
+func (r *TokenRepository) TouchSession(ctx context.Context, dbTrx interface{}, sessionID int, ipAddress string, expiresAt time.Time) error {
+	functionName := "TokenRepository.TouchSession"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET ip_address = $1, expires_at = $2, last_seen_at = $3 WHERE id = $4", SessionTableName)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, ipAddress, expiresAt, time.Now(), sessionID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// RevokeSession revoke the session of the user together with its refresh tokens,
+// ErrNotFound is returned when the user has no such session left to revoke
+func (r *TokenRepository) RevokeSession(ctx context.Context, dbTrx interface{}, userID int, sessionID int) error {
+	functionName := "TokenRepository.RevokeSession"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	query := fmt.Sprintf("UPDATE %s SET revoked_at = $1 WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL AND expires_at > $1", SessionTableName)
+	result, err := Tx(r.db, dbTrx).ExecContext(ctx, query, now, sessionID, userID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if affected == 0 {
+		return response.ErrNotFound
+	}
+
+	query = fmt.Sprintf(
+		"UPDATE %s SET revoked_at = $1, updated_at = $1 WHERE family_id IN (SELECT family_id FROM %s WHERE id = $2) AND revoked_at IS NULL",
+		RefreshTokenTableName,
+		SessionTableName,
+	)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, now, sessionID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// IsSessionRevoked check whether the session was revoked, a session which no longer exists counts as revoked
+func (r *TokenRepository) IsSessionRevoked(ctx context.Context, sessionID int) (bool, error) {
+	functionName := "TokenRepository.IsSessionRevoked"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	active := false
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1 AND revoked_at IS NULL)", SessionTableName)
+	if err := r.db.QueryRowxContext(ctx, query, sessionID).Scan(&active); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	return !active, nil
+}
+
+// DeleteExpiredTokens delete the expired refresh, password reset and email verification tokens, the expired login challenges,
+// OpenID Connect login states and sessions, and the denylisted access tokens which expired anyway
+func (r *TokenRepository) DeleteExpiredTokens(ctx context.Context) error {
+	functionName := "TokenRepository.DeleteExpiredTokens"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	for _, tableName := range []string{RefreshTokenTableName, RevokedAccessTokenTableName, PasswordResetTokenTableName, EmailVerificationTokenTableName, LoginChallengeTableName, OIDCLoginStateTableName, SessionTableName} {
+		query := fmt.Sprintf("DELETE FROM %s WHERE expires_at < $1", tableName)
+		if _, err := r.db.ExecContext(ctx, query, now); err != nil {
+			return errors.Wrap(err, functionName)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,1790 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRefreshToken(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		createErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail create",
+			ctx:       context.Background(),
+			createErr: errors.New("fail create"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("INSERT INTO refresh_tokens \\(user_id, family_id, token_hash, expires_at, revoked_at, created_at, updated_at\\) VALUES (.+) RETURNING id")
+			if tc.createErr != nil {
+				mockExpectedQuery.WillReturnError(tc.createErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			token := &entity.RefreshToken{UserID: 2, FamilyID: "family", TokenHash: "hash", ExpiresAt: time.Now()}
+			err = repo.CreateRefreshToken(tc.ctx, nil, token)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, 1, token.ID)
+			}
+		})
+	}
+}
+
+func TestGetRefreshTokenByHash(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  *entity.RefreshToken
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "record not found",
+			ctx:       context.Background(),
+			fetchRows: postgres.RefreshTokenColumns,
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.RefreshTokenColumns,
+			expected:  &entity.RefreshToken{ID: 1, UserID: 2, FamilyID: "family", TokenHash: "hash"},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM refresh_tokens WHERE token_hash = \\$1 LIMIT 1$").WithArgs("hash")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = rows.AddRow(
+						tc.expected.ID,
+						tc.expected.UserID,
+						tc.expected.FamilyID,
+						tc.expected.TokenHash,
+						tc.expected.ExpiresAt,
+						tc.expected.RevokedAt,
+						tc.expected.CreatedAt,
+						tc.expected.UpdatedAt,
+					)
+				} else if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			result, err := repo.GetRefreshTokenByHash(tc.ctx, nil, "hash")
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetRefreshTokenByHashInTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("^SELECT .+ FROM refresh_tokens WHERE token_hash = \\$1 LIMIT 1 FOR UPDATE$").WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows(postgres.RefreshTokenColumns).AddRow(1, 2, "family", "hash", time.Time{}, nil, time.Time{}, time.Time{}))
+
+	dbx := sqlx.NewDb(db, "mock")
+	tx, err := dbx.Beginx()
+	assert.Nil(t, err)
+
+	repo := postgres.NewTokenRepository(dbx)
+	result, err := repo.GetRefreshTokenByHash(context.Background(), tx, "hash")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.ID)
+}
+
+func TestRevokeRefreshToken(t *testing.T) {
+	testcases := []struct {
+		name         string
+		ctx          context.Context
+		updateErr    error
+		rowsAffected int64
+		expected     bool
+		wantErr      bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:         "already revoked",
+			ctx:          context.Background(),
+			rowsAffected: 0,
+			expected:     false,
+			wantErr:      false,
+		},
+		{
+			name:         "success",
+			ctx:          context.Background(),
+			rowsAffected: 1,
+			expected:     true,
+			wantErr:      false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE refresh_tokens SET revoked_at = \\$1, updated_at = \\$1 WHERE id = \\$2 AND revoked_at IS NULL").WithArgs(sqlmock.AnyArg(), 1)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			revoked, err := repo.RevokeRefreshToken(tc.ctx, nil, 1)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			assert.Equal(t, tc.expected, revoked)
+		})
+	}
+}
+
+func TestRevokeRefreshTokenFamily(t *testing.T) {
+	testcases := []struct {
+		name             string
+		ctx              context.Context
+		updateErr        error
+		updateSessionErr error
+		wantErr          bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:             "fail update sessions",
+			ctx:              context.Background(),
+			updateSessionErr: errors.New("fail update"),
+			wantErr:          true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE refresh_tokens SET revoked_at = \\$1, updated_at = \\$1 WHERE family_id = \\$2 AND revoked_at IS NULL").WithArgs(sqlmock.AnyArg(), "family")
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 3))
+			}
+
+			mockExpectedSessionExec := mock.ExpectExec("UPDATE sessions SET revoked_at = \\$1 WHERE family_id = \\$2 AND revoked_at IS NULL").WithArgs(sqlmock.AnyArg(), "family")
+			if tc.updateSessionErr != nil {
+				mockExpectedSessionExec.WillReturnError(tc.updateSessionErr)
+			} else {
+				mockExpectedSessionExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			err = repo.RevokeRefreshTokenFamily(tc.ctx, nil, "family")
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestRevokeAccessToken(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		insertErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail insert",
+			ctx:       context.Background(),
+			insertErr: errors.New("fail insert"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			expiresAt := time.Now()
+			mockExpectedExec := mock.ExpectExec("INSERT INTO revoked_access_tokens \\(jti, expires_at\\) VALUES \\(\\$1, \\$2\\) ON CONFLICT \\(jti\\) DO NOTHING").WithArgs("jti", expiresAt)
+			if tc.insertErr != nil {
+				mockExpectedExec.WillReturnError(tc.insertErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			err = repo.RevokeAccessToken(tc.ctx, "jti", expiresAt)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestIsAccessTokenRevoked(t *testing.T) {
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		fetchErr error
+		expected bool
+		wantErr  bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:     "success revoked",
+			ctx:      context.Background(),
+			expected: true,
+			wantErr:  false,
+		},
+		{
+			name:     "success not revoked",
+			ctx:      context.Background(),
+			expected: false,
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM revoked_access_tokens WHERE jti = \\$1\\)").WithArgs("jti")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(tc.expected))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			revoked, err := repo.IsAccessTokenRevoked(tc.ctx, "jti")
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			assert.Equal(t, tc.expected, revoked)
+		})
+	}
+}
+
+func TestDeleteExpiredTokens(t *testing.T) {
+	testcases := []struct {
+		name                  string
+		ctx                   context.Context
+		deleteRefreshErr      error
+		deleteRevokedTokenErr error
+		deleteResetTokenErr   error
+		deleteVerifyTokenErr  error
+		deleteChallengeErr    error
+		deleteOIDCStateErr    error
+		deleteSessionErr      error
+		wantErr               bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:             "fail delete refresh tokens",
+			ctx:              context.Background(),
+			deleteRefreshErr: errors.New("fail delete"),
+			wantErr:          true,
+		},
+		{
+			name:                  "fail delete revoked access tokens",
+			ctx:                   context.Background(),
+			deleteRevokedTokenErr: errors.New("fail delete"),
+			wantErr:               true,
+		},
+		{
+			name:                "fail delete password reset tokens",
+			ctx:                 context.Background(),
+			deleteResetTokenErr: errors.New("fail delete"),
+			wantErr:             true,
+		},
+		{
+			name:                 "fail delete email verification tokens",
+			ctx:                  context.Background(),
+			deleteVerifyTokenErr: errors.New("fail delete"),
+			wantErr:              true,
+		},
+		{
+			name:               "fail delete login challenges",
+			ctx:                context.Background(),
+			deleteChallengeErr: errors.New("fail delete"),
+			wantErr:            true,
+		},
+		{
+			name:               "fail delete OpenID Connect login states",
+			ctx:                context.Background(),
+			deleteOIDCStateErr: errors.New("fail delete"),
+			wantErr:            true,
+		},
+		{
+			name:             "fail delete sessions",
+			ctx:              context.Background(),
+			deleteSessionErr: errors.New("fail delete"),
+			wantErr:          true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedRefresh := mock.ExpectExec("DELETE FROM refresh_tokens WHERE expires_at < \\$1")
+			if tc.deleteRefreshErr != nil {
+				mockExpectedRefresh.WillReturnError(tc.deleteRefreshErr)
+			} else {
+				mockExpectedRefresh.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			mockExpectedRevoked := mock.ExpectExec("DELETE FROM revoked_access_tokens WHERE expires_at < \\$1")
+			if tc.deleteRevokedTokenErr != nil {
+				mockExpectedRevoked.WillReturnError(tc.deleteRevokedTokenErr)
+			} else {
+				mockExpectedRevoked.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			mockExpectedReset := mock.ExpectExec("DELETE FROM password_reset_tokens WHERE expires_at < \\$1")
+			if tc.deleteResetTokenErr != nil {
+				mockExpectedReset.WillReturnError(tc.deleteResetTokenErr)
+			} else {
+				mockExpectedReset.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			mockExpectedVerify := mock.ExpectExec("DELETE FROM email_verification_tokens WHERE expires_at < \\$1")
+			if tc.deleteVerifyTokenErr != nil {
+				mockExpectedVerify.WillReturnError(tc.deleteVerifyTokenErr)
+			} else {
+				mockExpectedVerify.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			mockExpectedChallenge := mock.ExpectExec("DELETE FROM login_challenges WHERE expires_at < \\$1")
+			if tc.deleteChallengeErr != nil {
+				mockExpectedChallenge.WillReturnError(tc.deleteChallengeErr)
+			} else {
+				mockExpectedChallenge.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			mockExpectedOIDCState := mock.ExpectExec("DELETE FROM oidc_login_states WHERE expires_at < \\$1")
+			if tc.deleteOIDCStateErr != nil {
+				mockExpectedOIDCState.WillReturnError(tc.deleteOIDCStateErr)
+			} else {
+				mockExpectedOIDCState.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			mockExpectedSession := mock.ExpectExec("DELETE FROM sessions WHERE expires_at < \\$1")
+			if tc.deleteSessionErr != nil {
+				mockExpectedSession.WillReturnError(tc.deleteSessionErr)
+			} else {
+				mockExpectedSession.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			err = repo.DeleteExpiredTokens(tc.ctx)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestRevokeUserRefreshTokens(t *testing.T) {
+	testcases := []struct {
+		name             string
+		ctx              context.Context
+		updateErr        error
+		updateSessionErr error
+		wantErr          bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:             "fail update sessions",
+			ctx:              context.Background(),
+			updateSessionErr: errors.New("fail update"),
+			wantErr:          true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE refresh_tokens SET revoked_at = \\$1, updated_at = \\$1 WHERE user_id = \\$2 AND revoked_at IS NULL AND family_id NOT IN \\(SELECT family_id FROM sessions WHERE id = \\$3\\)").WithArgs(sqlmock.AnyArg(), 2, 5)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 3))
+			}
+
+			mockExpectedSessionExec := mock.ExpectExec("UPDATE sessions SET revoked_at = \\$1 WHERE user_id = \\$2 AND id <> \\$3 AND revoked_at IS NULL").WithArgs(sqlmock.AnyArg(), 2, 5)
+			if tc.updateSessionErr != nil {
+				mockExpectedSessionExec.WillReturnError(tc.updateSessionErr)
+			} else {
+				mockExpectedSessionExec.WillReturnResult(sqlmock.NewResult(0, 2))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			err = repo.RevokeUserRefreshTokens(tc.ctx, nil, 2, 5)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestCreatePasswordResetToken(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		createErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail create",
+			ctx:       context.Background(),
+			createErr: errors.New("fail create"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("INSERT INTO password_reset_tokens \\(user_id, token_hash, expires_at, used_at, created_at\\) VALUES (.+) RETURNING id")
+			if tc.createErr != nil {
+				mockExpectedQuery.WillReturnError(tc.createErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			token := &entity.PasswordResetToken{UserID: 2, TokenHash: "hash", ExpiresAt: time.Now()}
+			err = repo.CreatePasswordResetToken(tc.ctx, token)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, 1, token.ID)
+			}
+		})
+	}
+}
+
+func TestGetPasswordResetTokenByHash(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  *entity.PasswordResetToken
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "record not found",
+			ctx:       context.Background(),
+			fetchRows: postgres.PasswordResetTokenColumns,
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.PasswordResetTokenColumns,
+			expected:  &entity.PasswordResetToken{ID: 1, UserID: 2, TokenHash: "hash"},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM password_reset_tokens WHERE token_hash = \\$1 LIMIT 1$").WithArgs("hash")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = rows.AddRow(
+						tc.expected.ID,
+						tc.expected.UserID,
+						tc.expected.TokenHash,
+						tc.expected.ExpiresAt,
+						tc.expected.UsedAt,
+						tc.expected.CreatedAt,
+					)
+				} else if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			result, err := repo.GetPasswordResetTokenByHash(tc.ctx, nil, "hash")
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestUsePasswordResetTokens(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		updateErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE password_reset_tokens SET used_at = \\$1 WHERE user_id = \\$2 AND used_at IS NULL").WithArgs(sqlmock.AnyArg(), 2)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			err = repo.UsePasswordResetTokens(tc.ctx, nil, 2)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestCreateEmailVerificationToken(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		createErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail create",
+			ctx:       context.Background(),
+			createErr: errors.New("fail create"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("INSERT INTO email_verification_tokens \\(user_id, new_email, token_hash, expires_at, used_at, created_at\\) VALUES (.+) RETURNING id")
+			if tc.createErr != nil {
+				mockExpectedQuery.WillReturnError(tc.createErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			token := &entity.EmailVerificationToken{UserID: 2, TokenHash: "hash", ExpiresAt: time.Now()}
+			err = repo.CreateEmailVerificationToken(tc.ctx, token)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, 1, token.ID)
+			}
+		})
+	}
+}
+
+func TestGetEmailVerificationTokenByHash(t *testing.T) {
+	newEmail := "baz@bar.com"
+
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  *entity.EmailVerificationToken
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "record not found",
+			ctx:       context.Background(),
+			fetchRows: postgres.EmailVerificationTokenColumns,
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.EmailVerificationTokenColumns,
+			expected:  &entity.EmailVerificationToken{ID: 1, UserID: 2, TokenHash: "hash"},
+			wantErr:   false,
+		},
+		{
+			name:      "success with a new email",
+			ctx:       context.Background(),
+			fetchRows: postgres.EmailVerificationTokenColumns,
+			expected:  &entity.EmailVerificationToken{ID: 1, UserID: 2, NewEmail: &newEmail, TokenHash: "hash"},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM email_verification_tokens WHERE token_hash = \\$1 LIMIT 1$").WithArgs("hash")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = rows.AddRow(
+						tc.expected.ID,
+						tc.expected.UserID,
+						tc.expected.NewEmail,
+						tc.expected.TokenHash,
+						tc.expected.ExpiresAt,
+						tc.expected.UsedAt,
+						tc.expected.CreatedAt,
+					)
+				} else if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			result, err := repo.GetEmailVerificationTokenByHash(tc.ctx, nil, "hash")
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestUseEmailVerificationTokens(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		updateErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE email_verification_tokens SET used_at = \\$1 WHERE user_id = \\$2 AND used_at IS NULL").WithArgs(sqlmock.AnyArg(), 2)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			err = repo.UseEmailVerificationTokens(tc.ctx, nil, 2)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestCountEmailVerificationTokensSince(t *testing.T) {
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		fetchErr error
+		expected int
+		wantErr  bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			expected: 2,
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			since := time.Now()
+			mockExpectedQuery := mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM email_verification_tokens WHERE user_id = \\$1 AND created_at >= \\$2").WithArgs(2, since)
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tc.expected))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			result, err := repo.CountEmailVerificationTokensSince(tc.ctx, 2, since)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestCreateLoginChallenge(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		createErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail create",
+			ctx:       context.Background(),
+			createErr: errors.New("fail create"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("INSERT INTO login_challenges \\(user_id, token_hash, expires_at, used_at, created_at\\) VALUES (.+) RETURNING id")
+			if tc.createErr != nil {
+				mockExpectedQuery.WillReturnError(tc.createErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			challenge := &entity.LoginChallenge{UserID: 2, TokenHash: "hash", ExpiresAt: time.Now()}
+			err = repo.CreateLoginChallenge(tc.ctx, challenge)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, 1, challenge.ID)
+			}
+		})
+	}
+}
+
+func TestGetLoginChallengeByHash(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  *entity.LoginChallenge
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "record not found",
+			ctx:       context.Background(),
+			fetchRows: postgres.LoginChallengeColumns,
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.LoginChallengeColumns,
+			expected:  &entity.LoginChallenge{ID: 1, UserID: 2, TokenHash: "hash"},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM login_challenges WHERE token_hash = \\$1 LIMIT 1$").WithArgs("hash")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = rows.AddRow(
+						tc.expected.ID,
+						tc.expected.UserID,
+						tc.expected.TokenHash,
+						tc.expected.ExpiresAt,
+						tc.expected.UsedAt,
+						tc.expected.CreatedAt,
+					)
+				} else if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			result, err := repo.GetLoginChallengeByHash(tc.ctx, nil, "hash")
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestUseLoginChallenge(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		updateErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE login_challenges SET used_at = \\$1 WHERE id = \\$2").WithArgs(sqlmock.AnyArg(), 1)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			err = repo.UseLoginChallenge(tc.ctx, nil, 1)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestCreateOIDCLoginState(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		createErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail create",
+			ctx:       context.Background(),
+			createErr: errors.New("fail create"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("INSERT INTO oidc_login_states \\(state_hash, nonce, code_verifier, expires_at, used_at, created_at\\) VALUES (.+) RETURNING id")
+			if tc.createErr != nil {
+				mockExpectedQuery.WillReturnError(tc.createErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			state := &entity.OIDCLoginState{StateHash: "hash", Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: time.Now()}
+			err = repo.CreateOIDCLoginState(tc.ctx, state)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, 1, state.ID)
+			}
+		})
+	}
+}
+
+func TestGetOIDCLoginStateByHash(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  *entity.OIDCLoginState
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "record not found",
+			ctx:       context.Background(),
+			fetchRows: postgres.OIDCLoginStateColumns,
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.OIDCLoginStateColumns,
+			expected:  &entity.OIDCLoginState{ID: 1, StateHash: "hash", Nonce: "nonce", CodeVerifier: "verifier"},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM oidc_login_states WHERE state_hash = \\$1 LIMIT 1$").WithArgs("hash")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = rows.AddRow(
+						tc.expected.ID,
+						tc.expected.StateHash,
+						tc.expected.Nonce,
+						tc.expected.CodeVerifier,
+						tc.expected.ExpiresAt,
+						tc.expected.UsedAt,
+						tc.expected.CreatedAt,
+					)
+				} else if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			result, err := repo.GetOIDCLoginStateByHash(tc.ctx, nil, "hash")
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestUseOIDCLoginState(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		updateErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE oidc_login_states SET used_at = \\$1 WHERE id = \\$2").WithArgs(sqlmock.AnyArg(), 1)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			err = repo.UseOIDCLoginState(tc.ctx, nil, 1)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func addSessionRow(rows *sqlmock.Rows, session *entity.Session) *sqlmock.Rows {
+	return rows.AddRow(
+		session.ID,
+		session.UserID,
+		session.FamilyID,
+		session.Device,
+		session.UserAgent,
+		session.IPAddress,
+		session.ExpiresAt,
+		session.RevokedAt,
+		session.LastSeenAt,
+		session.CreatedAt,
+	)
+}
+
+func TestCreateSession(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		createErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail create",
+			ctx:       context.Background(),
+			createErr: errors.New("fail create"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("INSERT INTO sessions \\(user_id, family_id, device, user_agent, ip_address, expires_at, revoked_at, last_seen_at, created_at\\) VALUES (.+) RETURNING id")
+			if tc.createErr != nil {
+				mockExpectedQuery.WillReturnError(tc.createErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			session := &entity.Session{UserID: 2, FamilyID: "family", Device: "Firefox on Linux", UserAgent: "Mozilla/5.0", IPAddress: "127.0.0.1", ExpiresAt: time.Now()}
+			err = repo.CreateSession(tc.ctx, nil, session)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, 1, session.ID)
+				assert.False(t, session.LastSeenAt.IsZero())
+			}
+		})
+	}
+}
+
+func TestGetSessionByFamilyID(t *testing.T) {
+	testcases := []struct {
+		name        string
+		ctx         context.Context
+		fetchErr    error
+		fetchRows   []string
+		expected    *entity.Session
+		expectedErr error
+		wantErr     bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:        "record not found",
+			ctx:         context.Background(),
+			fetchRows:   postgres.SessionColumns,
+			expectedErr: response.ErrNotFound,
+			wantErr:     true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.SessionColumns,
+			expected:  &entity.Session{ID: 1, UserID: 2, FamilyID: "family", Device: "Firefox on Linux"},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM sessions WHERE family_id = \\$1 LIMIT 1$").WithArgs("family")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = addSessionRow(rows, tc.expected)
+				} else if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			result, err := repo.GetSessionByFamilyID(tc.ctx, nil, "family")
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetSessionsByUserID(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  []*entity.Session
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.SessionColumns,
+			expected: []*entity.Session{
+				{ID: 2, UserID: 2, FamilyID: "family2", Device: "Safari on iPhone"},
+				{ID: 1, UserID: 2, FamilyID: "family1", Device: "Firefox on Linux"},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM sessions WHERE user_id = \\$1 AND revoked_at IS NULL AND expires_at > \\$2 ORDER BY last_seen_at DESC$").WithArgs(2, sqlmock.AnyArg())
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				for _, session := range tc.expected {
+					rows = addSessionRow(rows, session)
+				}
+				if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			result, err := repo.GetSessionsByUserID(tc.ctx, 2)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestTouchSession(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		updateErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			expiresAt := time.Now().Add(time.Hour)
+			mockExpectedExec := mock.ExpectExec("UPDATE sessions SET ip_address = \\$1, expires_at = \\$2, last_seen_at = \\$3 WHERE id = \\$4").WithArgs("127.0.0.1", expiresAt, sqlmock.AnyArg(), 1)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			err = repo.TouchSession(tc.ctx, nil, 1, "127.0.0.1", expiresAt)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	testcases := []struct {
+		name             string
+		ctx              context.Context
+		updateErr        error
+		affected         int64
+		updateTokensErr  error
+		expectedErr      error
+		wantErr          bool
+		wantRevokeTokens bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:        "session not found",
+			ctx:         context.Background(),
+			affected:    0,
+			expectedErr: response.ErrNotFound,
+			wantErr:     true,
+		},
+		{
+			name:             "fail revoke refresh tokens",
+			ctx:              context.Background(),
+			affected:         1,
+			updateTokensErr:  errors.New("fail update"),
+			wantErr:          true,
+			wantRevokeTokens: true,
+		},
+		{
+			name:             "success",
+			ctx:              context.Background(),
+			affected:         1,
+			wantErr:          false,
+			wantRevokeTokens: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE sessions SET revoked_at = \\$1 WHERE id = \\$2 AND user_id = \\$3 AND revoked_at IS NULL AND expires_at > \\$1").WithArgs(sqlmock.AnyArg(), 1, 2)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, tc.affected))
+			}
+
+			if tc.wantRevokeTokens {
+				mockExpectedTokensExec := mock.ExpectExec("UPDATE refresh_tokens SET revoked_at = \\$1, updated_at = \\$1 WHERE family_id IN \\(SELECT family_id FROM sessions WHERE id = \\$2\\) AND revoked_at IS NULL").WithArgs(sqlmock.AnyArg(), 1)
+				if tc.updateTokensErr != nil {
+					mockExpectedTokensExec.WillReturnError(tc.updateTokensErr)
+				} else {
+					mockExpectedTokensExec.WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			err = repo.RevokeSession(tc.ctx, nil, 2, 1)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestIsSessionRevoked(t *testing.T) {
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		fetchErr error
+		active   bool
+		expected bool
+		wantErr  bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:     "revoked or gone",
+			ctx:      context.Background(),
+			active:   false,
+			expected: true,
+		},
+		{
+			name:     "active",
+			ctx:      context.Background(),
+			active:   true,
+			expected: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM sessions WHERE id = \\$1 AND revoked_at IS NULL\\)").WithArgs(1)
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				mockExpectedQuery.WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(tc.active))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewTokenRepository(dbx)
+			revoked, err := repo.IsSessionRevoked(tc.ctx, 1)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, tc.expected, revoked)
+			}
+		})
+	}
+}
@@ -18,6 +18,14 @@ import (
 type UserRepositoryInterface interface {
 	CreateUser(ctx context.Context, user *entity.User) error
 	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
+	GetUserByID(ctx context.Context, userID int) (*entity.User, error)
+	GetUserByIDUnscoped(ctx context.Context, userID int) (*entity.User, error)
+	UpdateUserPassword(ctx context.Context, dbTrx interface{}, userID int, cryptedPassword string) error
+	VerifyUser(ctx context.Context, dbTrx interface{}, userID int) error
+	UpdateUser(ctx context.Context, dbTrx interface{}, user *entity.User) error
+	ChangeUserEmail(ctx context.Context, dbTrx interface{}, userID int, email string) error
+	DeleteUser(ctx context.Context, dbTrx interface{}, userID int) error
+	EraseUser(ctx context.Context, dbTrx interface{}, userID int) error
 }
 
 // UserRepository holds database connection
@@ -29,7 +37,7 @@ var (
 	// UserTableName hold table name for users
 	UserTableName = "users"
 	// UserColumns list all columns on users table
-	UserColumns = []string{"id", "email", "fullname", "crypted_password", "created_at", "updated_at"}
+	UserColumns = []string{"id", "email", "fullname", "crypted_password", "role", "verified_at", "deleted_at", "created_at", "updated_at"}
 	// UserAttributes hold string format of all users table columns
 	UserAttributes = strings.Join(UserColumns, ", ")
 
@@ -74,8 +82,7 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *entity.User) erro
 		return errors.Wrap(err, functionName)
 	}
 
-	// Handle case-insensitive
-	user.Email = strings.ToLower(user.Email)
+	user.Email = entity.NormalizeEmail(user.Email)
 
 	now := time.Now()
 	user.CreatedAt = now
@@ -87,6 +94,9 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *entity.User) erro
 		user.Email,
 		user.Fullname,
 		user.CryptedPassword,
+		user.Role,
+		user.VerifiedAt,
+		user.DeletedAt,
 		user.CreatedAt,
 		user.UpdatedAt,
 	).Scan(&user.ID)
@@ -101,7 +111,7 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *entity.User) erro
 	return nil
 }
 
-// GetUserByEmail query to get user by email
+// GetUserByEmail query to get user by email whatever case it is given in, deleted users are left out
 func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
 	functionName := "UserRepository.GetUserByEmail"
 
@@ -109,8 +119,8 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*ent
 		return nil, errors.Wrap(err, functionName)
 	}
 
-	query := fmt.Sprintf("SELECT %s FROM %s WHERE email = $1 LIMIT 1", UserAttributes, UserTableName)
-	rows, err := r.fetch(ctx, query, strings.ToLower(email))
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE lower(email) = $1 AND deleted_at IS NULL LIMIT 1", UserAttributes, UserTableName)
+	rows, err := r.fetch(ctx, query, entity.NormalizeEmail(email))
 	if err != nil {
 		return nil, errors.Wrap(err, functionName)
 	}
@@ -121,3 +131,234 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*ent
 
 	return rows[0], nil
 }
+
+// GetUserByID query to get user by ID, deleted users are left out
+func (r *UserRepository) GetUserByID(ctx context.Context, userID int) (*entity.User, error) {
+	functionName := "UserRepository.GetUserByID"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1 AND deleted_at IS NULL LIMIT 1", UserAttributes, UserTableName)
+	rows, err := r.fetch(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if len(rows) == 0 {
+		return nil, response.ErrNotFound
+	}
+
+	return rows[0], nil
+}
+
+// GetUserByIDUnscoped query to get user by ID, deleted users included
+func (r *UserRepository) GetUserByIDUnscoped(ctx context.Context, userID int) (*entity.User, error) {
+	functionName := "UserRepository.GetUserByIDUnscoped"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1 LIMIT 1", UserAttributes, UserTableName)
+	rows, err := r.fetch(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if len(rows) == 0 {
+		return nil, response.ErrNotFound
+	}
+
+	return rows[0], nil
+}
+
+// UpdateUserPassword update the crypted password of the user
+func (r *UserRepository) UpdateUserPassword(ctx context.Context, dbTrx interface{}, userID int, cryptedPassword string) error {
+	functionName := "UserRepository.UpdateUserPassword"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET crypted_password = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL", UserTableName)
+	result, err := Tx(r.db, dbTrx).ExecContext(ctx, query, cryptedPassword, time.Now(), userID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if affected == 0 {
+		return response.ErrNotFound
+	}
+
+	return nil
+}
+
+// VerifyUser mark the email of the user as verified, verifying a verified user keeps the first verification time
+func (r *UserRepository) VerifyUser(ctx context.Context, dbTrx interface{}, userID int) error {
+	functionName := "UserRepository.VerifyUser"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	query := fmt.Sprintf("UPDATE %s SET verified_at = $1, updated_at = $2 WHERE id = $3 AND verified_at IS NULL", UserTableName)
+	if _, err := Tx(r.db, dbTrx).ExecContext(ctx, query, now, now, userID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// UpdateUser update the email, fullname and verification time of the user
+func (r *UserRepository) UpdateUser(ctx context.Context, dbTrx interface{}, user *entity.User) error {
+	functionName := "UserRepository.UpdateUser"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	user.Email = entity.NormalizeEmail(user.Email)
+	user.UpdatedAt = time.Now()
+
+	query := fmt.Sprintf("UPDATE %s SET email = $1, fullname = $2, verified_at = $3, updated_at = $4 WHERE id = $5 AND deleted_at IS NULL", UserTableName)
+	result, err := Tx(r.db, dbTrx).ExecContext(ctx, query, user.Email, user.Fullname, user.VerifiedAt, user.UpdatedAt, user.ID)
+	if err != nil {
+		if isUniqueConstraintViolation(err) {
+			return response.ErrDuplicateEmail
+		}
+
+		return errors.Wrap(err, functionName)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if affected == 0 {
+		return response.ErrNotFound
+	}
+
+	return nil
+}
+
+// ChangeUserEmail replace the email of the user with a verified one
+func (r *UserRepository) ChangeUserEmail(ctx context.Context, dbTrx interface{}, userID int, email string) error {
+	functionName := "UserRepository.ChangeUserEmail"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	query := fmt.Sprintf("UPDATE %s SET email = $1, verified_at = $2, updated_at = $3 WHERE id = $4 AND deleted_at IS NULL", UserTableName)
+	result, err := Tx(r.db, dbTrx).ExecContext(ctx, query, entity.NormalizeEmail(email), now, now, userID)
+	if err != nil {
+		if isUniqueConstraintViolation(err) {
+			return response.ErrDuplicateEmail
+		}
+
+		return errors.Wrap(err, functionName)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if affected == 0 {
+		return response.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteUser soft delete the user, the row is kept for the orders and reviews of the user
+func (r *UserRepository) DeleteUser(ctx context.Context, dbTrx interface{}, userID int) error {
+	functionName := "UserRepository.DeleteUser"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	query := fmt.Sprintf("UPDATE %s SET deleted_at = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL", UserTableName)
+	result, err := Tx(r.db, dbTrx).ExecContext(ctx, query, now, now, userID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if affected == 0 {
+		return response.ErrNotFound
+	}
+
+	return nil
+}
+
+// EraseUser anonymizes the user and deletes the personal data kept about the user such as the reading lists,
+// the sessions and the credentials, and blanks the text of the reviews and review reports of the user.
+// The row is kept deleted for the orders and reviews of the user, which are no longer tied to a person
+func (r *UserRepository) EraseUser(ctx context.Context, dbTrx interface{}, userID int) error {
+	functionName := "UserRepository.EraseUser"
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	tx := Tx(r.db, dbTrx)
+
+	// The login failures are kept by email, so they go before the email is anonymized
+	query := fmt.Sprintf("DELETE FROM %s WHERE email = (SELECT email FROM %s WHERE id = $1)", LoginFailureTableName, UserTableName)
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	now := time.Now()
+	query = fmt.Sprintf("UPDATE %s SET email = $1, fullname = $2, crypted_password = '', verified_at = NULL, deleted_at = COALESCE(deleted_at, $3), updated_at = $3 WHERE id = $4", UserTableName)
+	result, err := tx.ExecContext(ctx, query, fmt.Sprintf(entity.ErasedUserEmailFormat, userID), entity.ErasedUserFullname, now, userID)
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if affected == 0 {
+		return response.ErrNotFound
+	}
+
+	// The free text of the reviews and reports is personal data, the ratings and reports are kept for the book ratings
+	query = fmt.Sprintf("UPDATE %s SET body = '', updated_at = $1 WHERE user_id = $2 AND body <> ''", ReviewTableName)
+	if _, err := tx.ExecContext(ctx, query, now, userID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET reason = '' WHERE user_id = $1 AND reason <> ''", ReviewReportTableName)
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	for _, tableName := range []string{ReadingListTableName, SessionTableName, RefreshTokenTableName, PasswordResetTokenTableName, EmailVerificationTokenTableName, LoginChallengeTableName, TOTPCredentialTableName, RecoveryCodeTableName, APIKeyTableName, UserIdentityTableName} {
+		query := fmt.Sprintf("DELETE FROM %s WHERE user_id = $1", tableName)
+		if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+			return errors.Wrap(err, functionName)
+		}
+	}
+
+	return nil
+}
@@ -2,7 +2,9 @@ package postgres_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
@@ -11,6 +13,7 @@ import (
 	"github.com/satriowisnugroho/book-store/internal/config"
 	"github.com/satriowisnugroho/book-store/internal/entity"
 	"github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/internal/response"
 	"github.com/satriowisnugroho/book-store/test/fixture"
 	"github.com/stretchr/testify/assert"
 )
@@ -128,7 +131,7 @@ func TestGetUserByEmail(t *testing.T) {
 			}
 			defer db.Close()
 
-			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM users WHERE email = .+ LIMIT 1")
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM users WHERE lower\\(email\\) = \\$1 AND deleted_at IS NULL LIMIT 1").WithArgs("foo@bar.com")
 			if tc.fetchErr != nil {
 				mockExpectedQuery.WillReturnError(tc.fetchErr)
 			} else {
@@ -139,6 +142,9 @@ func TestGetUserByEmail(t *testing.T) {
 						tc.expected.Email,
 						tc.expected.Fullname,
 						tc.expected.CryptedPassword,
+						tc.expected.Role,
+						tc.expected.VerifiedAt,
+						tc.expected.DeletedAt,
 						tc.expected.CreatedAt,
 						tc.expected.UpdatedAt,
 					)
@@ -151,7 +157,7 @@ func TestGetUserByEmail(t *testing.T) {
 
 			dbx := sqlx.NewDb(db, "mock")
 			repo := postgres.NewUserRepository(dbx)
-			result, err := repo.GetUserByEmail(tc.ctx, "foo@bar.com")
+			result, err := repo.GetUserByEmail(tc.ctx, " Foo@Bar.com ")
 			assert.Equal(t, tc.wantErr, err != nil, err)
 			if !tc.wantErr {
 				assert.EqualValues(t, tc.expected, result)
@@ -159,3 +165,601 @@ func TestGetUserByEmail(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUserByID(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  *entity.User
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "record not found",
+			ctx:       context.Background(),
+			fetchRows: postgres.UserColumns,
+			wantErr:   true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			fetchRows: postgres.UserColumns,
+			expected:  &entity.User{},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM users WHERE id = \\$1 AND deleted_at IS NULL LIMIT 1")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = rows.AddRow(
+						tc.expected.ID,
+						tc.expected.Email,
+						tc.expected.Fullname,
+						tc.expected.CryptedPassword,
+						tc.expected.Role,
+						tc.expected.VerifiedAt,
+						tc.expected.DeletedAt,
+						tc.expected.CreatedAt,
+						tc.expected.UpdatedAt,
+					)
+				} else if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewUserRepository(dbx)
+			result, err := repo.GetUserByID(tc.ctx, 1)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetUserByIDUnscoped(t *testing.T) {
+	deletedAt := time.Now()
+
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		fetchErr  error
+		fetchRows []string
+		expected  *entity.User
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "fail fetch query error",
+			ctx:      context.Background(),
+			fetchErr: errors.New("fail fetch"),
+			wantErr:  true,
+		},
+		{
+			name:      "fail fetch return error rows",
+			ctx:       context.Background(),
+			fetchRows: []string{"unknown_column"},
+			wantErr:   true,
+		},
+		{
+			name:      "record not found",
+			ctx:       context.Background(),
+			fetchRows: postgres.UserColumns,
+			wantErr:   true,
+		},
+		{
+			name:      "success with a deleted user",
+			ctx:       context.Background(),
+			fetchRows: postgres.UserColumns,
+			expected:  &entity.User{DeletedAt: &deletedAt},
+			wantErr:   false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedQuery := mock.ExpectQuery("^SELECT .+ FROM users WHERE id = \\$1 LIMIT 1$")
+			if tc.fetchErr != nil {
+				mockExpectedQuery.WillReturnError(tc.fetchErr)
+			} else {
+				rows := sqlmock.NewRows(tc.fetchRows)
+				if tc.expected != nil {
+					rows = rows.AddRow(
+						tc.expected.ID,
+						tc.expected.Email,
+						tc.expected.Fullname,
+						tc.expected.CryptedPassword,
+						tc.expected.Role,
+						tc.expected.VerifiedAt,
+						tc.expected.DeletedAt,
+						tc.expected.CreatedAt,
+						tc.expected.UpdatedAt,
+					)
+				} else if len(tc.fetchRows) == 1 {
+					rows = rows.AddRow(1)
+				}
+
+				mockExpectedQuery.WillReturnRows(rows)
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewUserRepository(dbx)
+			result, err := repo.GetUserByIDUnscoped(tc.ctx, 1)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.EqualValues(t, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestUpdateUserPassword(t *testing.T) {
+	testcases := []struct {
+		name         string
+		ctx          context.Context
+		updateErr    error
+		rowsAffected int64
+		wantErr      bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:         "user not found",
+			ctx:          context.Background(),
+			rowsAffected: 0,
+			wantErr:      true,
+		},
+		{
+			name:         "success",
+			ctx:          context.Background(),
+			rowsAffected: 1,
+			wantErr:      false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE users SET crypted_password = \\$1, updated_at = \\$2 WHERE id = \\$3 AND deleted_at IS NULL").WithArgs("crypted", sqlmock.AnyArg(), 1)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewUserRepository(dbx)
+			err = repo.UpdateUserPassword(tc.ctx, nil, 1, "crypted")
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestVerifyUser(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		updateErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail update",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE users SET verified_at = \\$1, updated_at = \\$2 WHERE id = \\$3 AND verified_at IS NULL").WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewUserRepository(dbx)
+			err = repo.VerifyUser(tc.ctx, nil, 1)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	testcases := []struct {
+		name         string
+		ctx          context.Context
+		updateErr    error
+		rowsAffected int64
+		wantErr      error
+		wantAnyErr   bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.CtxEnded(),
+			wantAnyErr: true,
+		},
+		{
+			name:       "duplicate email",
+			ctx:        context.Background(),
+			updateErr:  &pq.Error{Code: pq.ErrorCode(config.UniqueConstraintViolationCode)},
+			wantErr:    response.ErrDuplicateEmail,
+			wantAnyErr: true,
+		},
+		{
+			name:       "fail update",
+			ctx:        context.Background(),
+			updateErr:  errors.New("fail update"),
+			wantAnyErr: true,
+		},
+		{
+			name:         "user not found",
+			ctx:          context.Background(),
+			rowsAffected: 0,
+			wantErr:      response.ErrNotFound,
+			wantAnyErr:   true,
+		},
+		{
+			name:         "success",
+			ctx:          context.Background(),
+			rowsAffected: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE users SET email = \\$1, fullname = \\$2, verified_at = \\$3, updated_at = \\$4 WHERE id = \\$5 AND deleted_at IS NULL").
+				WithArgs("foo@bar.com", "Foo Bar", nil, sqlmock.AnyArg(), 1)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewUserRepository(dbx)
+			err = repo.UpdateUser(tc.ctx, nil, &entity.User{ID: 1, Email: "Foo@Bar.com", Fullname: "Foo Bar"})
+			assert.Equal(t, tc.wantAnyErr, err != nil, err)
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestChangeUserEmail(t *testing.T) {
+	testcases := []struct {
+		name         string
+		ctx          context.Context
+		updateErr    error
+		rowsAffected int64
+		wantErr      error
+		wantAnyErr   bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.CtxEnded(),
+			wantAnyErr: true,
+		},
+		{
+			name:       "duplicate email",
+			ctx:        context.Background(),
+			updateErr:  &pq.Error{Code: pq.ErrorCode(config.UniqueConstraintViolationCode)},
+			wantErr:    response.ErrDuplicateEmail,
+			wantAnyErr: true,
+		},
+		{
+			name:       "fail update",
+			ctx:        context.Background(),
+			updateErr:  errors.New("fail update"),
+			wantAnyErr: true,
+		},
+		{
+			name:         "user not found",
+			ctx:          context.Background(),
+			rowsAffected: 0,
+			wantErr:      response.ErrNotFound,
+			wantAnyErr:   true,
+		},
+		{
+			name:         "success",
+			ctx:          context.Background(),
+			rowsAffected: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE users SET email = \\$1, verified_at = \\$2, updated_at = \\$3 WHERE id = \\$4 AND deleted_at IS NULL").
+				WithArgs("baz@bar.com", sqlmock.AnyArg(), sqlmock.AnyArg(), 1)
+			if tc.updateErr != nil {
+				mockExpectedExec.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewUserRepository(dbx)
+			err = repo.ChangeUserEmail(tc.ctx, nil, 1, "Baz@Bar.com")
+			assert.Equal(t, tc.wantAnyErr, err != nil, err)
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	testcases := []struct {
+		name         string
+		ctx          context.Context
+		deleteErr    error
+		rowsAffected int64
+		wantErr      bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "fail delete",
+			ctx:       context.Background(),
+			deleteErr: errors.New("fail delete"),
+			wantErr:   true,
+		},
+		{
+			name:         "user not found",
+			ctx:          context.Background(),
+			rowsAffected: 0,
+			wantErr:      true,
+		},
+		{
+			name:         "success",
+			ctx:          context.Background(),
+			rowsAffected: 1,
+			wantErr:      false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("UPDATE users SET deleted_at = \\$1, updated_at = \\$2 WHERE id = \\$3 AND deleted_at IS NULL").WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1)
+			if tc.deleteErr != nil {
+				mockExpectedExec.WillReturnError(tc.deleteErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewUserRepository(dbx)
+			err = repo.DeleteUser(tc.ctx, nil, 1)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestEraseUser(t *testing.T) {
+	testcases := []struct {
+		name            string
+		ctx             context.Context
+		deleteFailedErr error
+		updateErr       error
+		rowsAffected    int64
+		blankReviewsErr error
+		blankReportsErr error
+		deleteErr       error
+		expectedErr     error
+		wantErr         bool
+		wantDelete      bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:            "fail delete login failures",
+			ctx:             context.Background(),
+			deleteFailedErr: errors.New("fail delete"),
+			wantErr:         true,
+		},
+		{
+			name:      "fail anonymize",
+			ctx:       context.Background(),
+			updateErr: errors.New("fail update"),
+			wantErr:   true,
+		},
+		{
+			name:         "user not found",
+			ctx:          context.Background(),
+			rowsAffected: 0,
+			expectedErr:  response.ErrNotFound,
+			wantErr:      true,
+		},
+		{
+			name:            "fail blank reviews",
+			ctx:             context.Background(),
+			rowsAffected:    1,
+			blankReviewsErr: errors.New("fail update"),
+			wantErr:         true,
+		},
+		{
+			name:            "fail blank review reports",
+			ctx:             context.Background(),
+			rowsAffected:    1,
+			blankReportsErr: errors.New("fail update"),
+			wantErr:         true,
+		},
+		{
+			name:         "fail delete personal data",
+			ctx:          context.Background(),
+			rowsAffected: 1,
+			deleteErr:    errors.New("fail delete"),
+			wantErr:      true,
+			wantDelete:   true,
+		},
+		{
+			name:         "success",
+			ctx:          context.Background(),
+			rowsAffected: 1,
+			wantErr:      false,
+			wantDelete:   true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			mockExpectedExec := mock.ExpectExec("DELETE FROM login_failures WHERE email = \\(SELECT email FROM users WHERE id = \\$1\\)").WithArgs(1)
+			if tc.deleteFailedErr != nil {
+				mockExpectedExec.WillReturnError(tc.deleteFailedErr)
+			} else {
+				mockExpectedExec.WillReturnResult(sqlmock.NewResult(0, 2))
+			}
+
+			mockExpectedUpdate := mock.ExpectExec("UPDATE users SET email = \\$1, fullname = \\$2, crypted_password = '', verified_at = NULL, deleted_at = COALESCE\\(deleted_at, \\$3\\), updated_at = \\$3 WHERE id = \\$4").WithArgs("erased-1@erased.invalid", "Erased user", sqlmock.AnyArg(), 1)
+			if tc.updateErr != nil {
+				mockExpectedUpdate.WillReturnError(tc.updateErr)
+			} else {
+				mockExpectedUpdate.WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+			}
+
+			if tc.rowsAffected > 0 {
+				mockExpectedBlank := mock.ExpectExec("UPDATE reviews SET body = '', updated_at = \\$1 WHERE user_id = \\$2 AND body <> ''").WithArgs(sqlmock.AnyArg(), 1)
+				if tc.blankReviewsErr != nil {
+					mockExpectedBlank.WillReturnError(tc.blankReviewsErr)
+				} else {
+					mockExpectedBlank.WillReturnResult(sqlmock.NewResult(0, 2))
+					mockExpectedBlank = mock.ExpectExec("UPDATE review_reports SET reason = '' WHERE user_id = \\$1 AND reason <> ''").WithArgs(1)
+					if tc.blankReportsErr != nil {
+						mockExpectedBlank.WillReturnError(tc.blankReportsErr)
+					} else {
+						mockExpectedBlank.WillReturnResult(sqlmock.NewResult(0, 1))
+					}
+				}
+			}
+
+			if tc.wantDelete {
+				mockExpectedDelete := mock.ExpectExec("DELETE FROM reading_lists WHERE user_id = \\$1").WithArgs(1)
+				if tc.deleteErr != nil {
+					mockExpectedDelete.WillReturnError(tc.deleteErr)
+				} else {
+					mockExpectedDelete.WillReturnResult(sqlmock.NewResult(0, 1))
+					for _, tableName := range []string{"sessions", "refresh_tokens", "password_reset_tokens", "email_verification_tokens", "login_challenges", "totp_credentials", "recovery_codes", "api_keys", "user_identities"} {
+						mock.ExpectExec(fmt.Sprintf("DELETE FROM %s WHERE user_id = \\$1", tableName)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+					}
+				}
+			}
+
+			dbx := sqlx.NewDb(db, "mock")
+			repo := postgres.NewUserRepository(dbx)
+			err = repo.EraseUser(tc.ctx, nil, 1)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+			if !tc.wantErr {
+				assert.NoError(t, mock.ExpectationsWereMet())
+			}
+		})
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
 	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // SuccessBody holds data for success response
@@ -86,6 +88,8 @@ type MetaInfo struct {
 	Offset     int `json:"offset,omitempty"`
 	Limit      int `json:"limit,omitempty"`
 	Total      int `json:"total,omitempty"`
+	// TraceID is the trace of a failed request, to look up its spans and logs
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 const (
@@ -112,6 +116,74 @@ const (
 	ErrorCodeInvalidPasswordLength = 10007
 	// ErrorCodeInvalidPassword Error code for invalid password
 	ErrorCodeInvalidPassword = 10008
+	// ErrorCodeInvalidIsbn Error code for invalid ISBN
+	ErrorCodeInvalidIsbn = 10009
+	// ErrorCodeInvalidTitle Error code for invalid title
+	ErrorCodeInvalidTitle = 10010
+	// ErrorCodeInvalidPrice Error code for invalid price
+	ErrorCodeInvalidPrice = 10011
+	// ErrorCodeUnsupportedFileFormat Error code for unsupported file format
+	ErrorCodeUnsupportedFileFormat = 10012
+	// ErrorCodeInvalidFile Error code for invalid file
+	ErrorCodeInvalidFile = 10013
+	// ErrorCodeUnsupportedImageType Error code for unsupported image type
+	ErrorCodeUnsupportedImageType = 10014
+	// ErrorCodeInvalidImage Error code for invalid image
+	ErrorCodeInvalidImage = 10015
+	// ErrorCodeInvalidRating Error code for invalid rating
+	ErrorCodeInvalidRating = 10016
+	// ErrorCodeInvalidReviewBody Error code for invalid review body
+	ErrorCodeInvalidReviewBody = 10017
+	// ErrorCodeDuplicateReview Error code for duplicate review
+	ErrorCodeDuplicateReview = 10018
+	// ErrorCodeInvalidSort Error code for invalid sort
+	ErrorCodeInvalidSort = 10019
+	// ErrorCodeDuplicateReviewReport Error code for duplicate review report
+	ErrorCodeDuplicateReviewReport = 10020
+	// ErrorCodeInvalidReportReason Error code for invalid report reason
+	ErrorCodeInvalidReportReason = 10021
+	// ErrorCodeInvalidReviewStatus Error code for invalid review status
+	ErrorCodeInvalidReviewStatus = 10022
+	// ErrorCodeInvalidReviewIDs Error code for invalid review IDs
+	ErrorCodeInvalidReviewIDs = 10023
+	// ErrorCodeInvalidReadingListName Error code for invalid reading list name
+	ErrorCodeInvalidReadingListName = 10024
+	// ErrorCodeEmptyReadingList Error code for empty reading list
+	ErrorCodeEmptyReadingList = 10025
+	// ErrorCodeInvalidBestsellerPeriod Error code for invalid bestseller period
+	ErrorCodeInvalidBestsellerPeriod = 10026
+	// ErrorCodeInvalidPasswordResetToken Error code for invalid password reset token
+	ErrorCodeInvalidPasswordResetToken = 10027
+	// ErrorCodeInvalidEmailVerificationToken Error code for invalid email verification token
+	ErrorCodeInvalidEmailVerificationToken = 10028
+	// ErrorCodeEmailAlreadyVerified Error code for email already verified
+	ErrorCodeEmailAlreadyVerified = 10029
+	// ErrorCodeTooManyVerificationEmails Error code for too many verification emails
+	ErrorCodeTooManyVerificationEmails = 10030
+	// ErrorCodeEmailNotVerified Error code for email not verified
+	ErrorCodeEmailNotVerified = 10031
+	// ErrorCodeInvalidCredentials Error code for invalid credentials
+	ErrorCodeInvalidCredentials = 10032
+	// ErrorCodeTooManyLoginAttempts Error code for too many login attempts
+	ErrorCodeTooManyLoginAttempts = 10033
+	// ErrorCodeInvalidMFACode Error code for invalid MFA code
+	ErrorCodeInvalidMFACode = 10034
+	// ErrorCodeTOTPAlreadyEnabled Error code for TOTP already enabled
+	ErrorCodeTOTPAlreadyEnabled = 10035
+	// ErrorCodeTOTPNotEnrolled Error code for TOTP not enrolled
+	ErrorCodeTOTPNotEnrolled = 10036
+	// ErrorCodeInvalidAPIKeyName Error code for invalid API key name
+	ErrorCodeInvalidAPIKeyName = 10037
+	// ErrorCodeInvalidAPIKeyScope Error code for invalid API key scope
+	ErrorCodeInvalidAPIKeyScope = 10038
+	// ErrorCodeOIDCNotEnabled Error code for OpenID Connect login not enabled
+	ErrorCodeOIDCNotEnabled = 10039
+	// ErrorCodeOIDCAccountNotLinkable Error code for OpenID Connect account not linkable
+	ErrorCodeOIDCAccountNotLinkable = 10040
+	// ErrorCodeInvalidAuditTimeRange Error code for invalid audit event time range
+	ErrorCodeInvalidAuditTimeRange = 10041
+	// ErrorCodeInvalidUserID Error code for invalid user ID
+	ErrorCodeInvalidUserID = 10042
 )
 
 var (
@@ -175,6 +247,233 @@ var (
 		Code:     ErrorCodeInvalidPassword,
 		HTTPCode: http.StatusUnprocessableEntity,
 	}
+	// ErrInvalidIsbn define error when invalid ISBN
+	ErrInvalidIsbn = CustomError{
+		Message:  "Invalid ISBN. The ISBN must be a valid ISBN-10 or ISBN-13",
+		Code:     ErrorCodeInvalidIsbn,
+		Field:    "isbn",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidTitle define error when invalid title
+	ErrInvalidTitle = CustomError{
+		Message:  "Invalid title. The title must not be empty",
+		Code:     ErrorCodeInvalidTitle,
+		Field:    "title",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidPrice define error when invalid price
+	ErrInvalidPrice = CustomError{
+		Message:  "Invalid price. The price must be a whole amount greater than 0",
+		Code:     ErrorCodeInvalidPrice,
+		Field:    "price",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrUnsupportedFileFormat define error when the uploaded file format is not supported
+	ErrUnsupportedFileFormat = CustomError{
+		Message:  "Unsupported file format",
+		Code:     ErrorCodeUnsupportedFileFormat,
+		Field:    "format",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidFile define error when the uploaded file is missing or cannot be read
+	ErrInvalidFile = CustomError{
+		Message:  "Invalid file. The file is missing, too large or malformed",
+		Code:     ErrorCodeInvalidFile,
+		Field:    "file",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrUnsupportedImageType define error when the uploaded image is not a JPEG, PNG or GIF
+	ErrUnsupportedImageType = CustomError{
+		Message:  "Unsupported image type. Supported types are JPEG, PNG and GIF",
+		Code:     ErrorCodeUnsupportedImageType,
+		Field:    "file",
+		HTTPCode: http.StatusUnsupportedMediaType,
+	}
+	// ErrInvalidImage define error when the uploaded image is too large or cannot be decoded
+	ErrInvalidImage = CustomError{
+		Message:  "Invalid image. The image is missing, too large or malformed",
+		Code:     ErrorCodeInvalidImage,
+		Field:    "file",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidRating define error when the rating is out of range
+	ErrInvalidRating = CustomError{
+		Message:  "Invalid rating. The rating must be between 1 and 5",
+		Code:     ErrorCodeInvalidRating,
+		Field:    "rating",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidReviewBody define error when the review text is too long
+	ErrInvalidReviewBody = CustomError{
+		Message:  "Invalid review. The review must be at most 5000 characters",
+		Code:     ErrorCodeInvalidReviewBody,
+		Field:    "body",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrDuplicateReview define error when the user already reviewed the book
+	ErrDuplicateReview = CustomError{
+		Message:  "You have already reviewed this book",
+		Code:     ErrorCodeDuplicateReview,
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidSort define error when the sort option is unknown
+	ErrInvalidSort = CustomError{
+		Message:  "Invalid sort option",
+		Code:     ErrorCodeInvalidSort,
+		Field:    "sort",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrDuplicateReviewReport define error when the user already reported the review
+	ErrDuplicateReviewReport = CustomError{
+		Message:  "You have already reported this review",
+		Code:     ErrorCodeDuplicateReviewReport,
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidReportReason define error when the report reason is too long
+	ErrInvalidReportReason = CustomError{
+		Message:  "Invalid reason. The reason must be at most 1000 characters",
+		Code:     ErrorCodeInvalidReportReason,
+		Field:    "reason",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidReviewStatus define error when the review status is unknown
+	ErrInvalidReviewStatus = CustomError{
+		Message:  "Invalid status. The status must be pending, approved or rejected",
+		Code:     ErrorCodeInvalidReviewStatus,
+		Field:    "status",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidReviewIDs define error when no review or too many reviews are moderated at once
+	ErrInvalidReviewIDs = CustomError{
+		Message:  "Invalid review IDs. Between 1 and 100 reviews can be moderated at once",
+		Code:     ErrorCodeInvalidReviewIDs,
+		Field:    "review_ids",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidReadingListName define error when the reading list name is empty or too long
+	ErrInvalidReadingListName = CustomError{
+		Message:  "Invalid name. The name must be between 1 and 100 characters",
+		Code:     ErrorCodeInvalidReadingListName,
+		Field:    "name",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrEmptyReadingList define error when an order is built from a reading list without books
+	ErrEmptyReadingList = CustomError{
+		Message:  "The reading list has no books",
+		Code:     ErrorCodeEmptyReadingList,
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidBestsellerPeriod define error when the bestseller period is unknown
+	ErrInvalidBestsellerPeriod = CustomError{
+		Message:  "Invalid period. The period must be day, week, month or all-time",
+		Code:     ErrorCodeInvalidBestsellerPeriod,
+		Field:    "period",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidPasswordResetToken define error when the password reset token is unknown, used or expired
+	ErrInvalidPasswordResetToken = CustomError{
+		Message:  "Invalid or expired password reset token",
+		Code:     ErrorCodeInvalidPasswordResetToken,
+		Field:    "token",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidEmailVerificationToken define error when the email verification token is unknown, used or expired
+	ErrInvalidEmailVerificationToken = CustomError{
+		Message:  "Invalid or expired email verification token",
+		Code:     ErrorCodeInvalidEmailVerificationToken,
+		Field:    "token",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrEmailAlreadyVerified define error when a verification email is requested for a verified email
+	ErrEmailAlreadyVerified = CustomError{
+		Message:  "Email already verified",
+		Code:     ErrorCodeEmailAlreadyVerified,
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrTooManyVerificationEmails define error when the verification email is resent too often
+	ErrTooManyVerificationEmails = CustomError{
+		Message:  "Too many verification emails. Please try again later",
+		Code:     ErrorCodeTooManyVerificationEmails,
+		HTTPCode: http.StatusTooManyRequests,
+	}
+	// ErrEmailNotVerified define error when an action requires a verified email
+	ErrEmailNotVerified = CustomError{
+		Message:  "Email not verified. Please verify your email first",
+		Code:     ErrorCodeEmailNotVerified,
+		HTTPCode: http.StatusForbidden,
+	}
+	// ErrInvalidCredentials define error when the email is unknown or the password is wrong, they are not told apart
+	ErrInvalidCredentials = CustomError{
+		Message:  "Invalid email or password",
+		Code:     ErrorCodeInvalidCredentials,
+		HTTPCode: http.StatusUnauthorized,
+	}
+	// ErrTooManyLoginAttempts define error when the email or the IP address is locked out after failed logins
+	ErrTooManyLoginAttempts = CustomError{
+		Message:  "Too many failed login attempts. Please try again later",
+		Code:     ErrorCodeTooManyLoginAttempts,
+		HTTPCode: http.StatusTooManyRequests,
+	}
+	// ErrInvalidMFACode define error when the TOTP code or the recovery code is wrong or was used before
+	ErrInvalidMFACode = CustomError{
+		Message:  "Invalid code",
+		Code:     ErrorCodeInvalidMFACode,
+		Field:    "code",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrTOTPAlreadyEnabled define error when a TOTP enrolment is started while TOTP is enabled
+	ErrTOTPAlreadyEnabled = CustomError{
+		Message:  "Two-factor authentication is already enabled",
+		Code:     ErrorCodeTOTPAlreadyEnabled,
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrTOTPNotEnrolled define error when TOTP is confirmed or disabled without an enrolment
+	ErrTOTPNotEnrolled = CustomError{
+		Message:  "Two-factor authentication is not enrolled",
+		Code:     ErrorCodeTOTPNotEnrolled,
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidAPIKeyName define error when the API key name is empty or too long
+	ErrInvalidAPIKeyName = CustomError{
+		Message:  "Invalid name. The name must be between 1 and 100 characters",
+		Code:     ErrorCodeInvalidAPIKeyName,
+		Field:    "name",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidAPIKeyScope define error when an API key is created without scopes or with an unknown scope
+	ErrInvalidAPIKeyScope = CustomError{
+		Message:  "Invalid scopes. The scopes must be one or more of read:catalog, write:orders and admin:*",
+		Code:     ErrorCodeInvalidAPIKeyScope,
+		Field:    "scopes",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrOIDCNotEnabled define error when logging in with OpenID Connect while no provider is configured
+	ErrOIDCNotEnabled = CustomError{
+		Message:  "OpenID Connect login is not enabled",
+		Code:     ErrorCodeOIDCNotEnabled,
+		HTTPCode: http.StatusNotFound,
+	}
+	// ErrOIDCAccountNotLinkable define error when the email of the provider account belongs to a user whose email is not verified,
+	// linking it would let whoever registered the email keep access to the account
+	ErrOIDCAccountNotLinkable = CustomError{
+		Message:  "An account with this email exists but its email is not verified. Please verify it and log in with your password first",
+		Code:     ErrorCodeOIDCAccountNotLinkable,
+		HTTPCode: http.StatusConflict,
+	}
+	// ErrInvalidAuditTimeRange define error when the time range of the audit events is malformed
+	ErrInvalidAuditTimeRange = CustomError{
+		Message:  "Invalid time range. from and to must be RFC 3339 times and from must not be after to",
+		Code:     ErrorCodeInvalidAuditTimeRange,
+		Field:    "from",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
+	// ErrInvalidUserID define error when a user ID filter is not a positive integer
+	ErrInvalidUserID = CustomError{
+		Message:  "Invalid user ID. The user ID must be a positive integer",
+		Code:     ErrorCodeInvalidUserID,
+		Field:    "user_id",
+		HTTPCode: http.StatusUnprocessableEntity,
+	}
 )
 
 func ErrUnauthorized(msg string) CustomError {
@@ -272,16 +571,19 @@ func Error(c *gin.Context, err error) {
 		return
 	}
 
-	if ce, ok := err.(CustomError); ok {
-		errorResponse := BuildError(ce)
-		c.AbortWithStatusJSON(ce.HTTPCode, errorResponse)
-		return
+	// The error is recorded on the span of the request, and the ID of its trace is returned to find it
+	ctx := context.Background()
+	if c.Request != nil {
+		ctx = c.Request.Context()
 	}
+	trace.SpanFromContext(ctx).RecordError(err)
 
-	causer := errors.Cause(err)
-	customError := causer
-
-	errorResponse := BuildError(customError)
+	var errorResponse ErrorBody
+	if ce, ok := err.(CustomError); ok {
+		errorResponse = BuildError(ce)
+	} else {
+		errorResponse = BuildError(errors.Cause(err))
+	}
 
 	// case for error info with no http status
 	if errorResponse.Meta == nil {
@@ -291,5 +593,7 @@ func Error(c *gin.Context, err error) {
 	}
 
 	meta := errorResponse.Meta.(MetaInfo)
+	meta.TraceID = tracing.TraceID(ctx)
+	errorResponse.Meta = meta
 	c.AbortWithStatusJSON(meta.HTTPStatus, errorResponse)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/satriowisnugroho/book-store/internal/response"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestCustomErrorError(t *testing.T) {
@@ -157,7 +158,23 @@ func TestError(t *testing.T) {
 
 			if tt.expectedStatus != 0 {
 				assert.Equal(t, tt.expectedStatus, w.Code)
+				assert.NotContains(t, w.Body.String(), "trace_id")
 			}
 		})
 	}
 }
+
+func TestErrorTraceID(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequestWithContext(ctx, "GET", "/v1/books", nil)
+
+	response.Error(c, errors.New("generic error"))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+}
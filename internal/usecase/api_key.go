@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	repo "github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/pkg/auth"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
+)
+
+// APIKeyUsecaseInterface define contract for API key related functions to usecase
+type APIKeyUsecaseInterface interface {
+	CreateAPIKey(ctx context.Context, userID int, payload *entity.APIKeyPayload) (*entity.CreatedAPIKey, error)
+	GetAPIKeys(ctx context.Context, userID int) ([]*entity.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID int, apiKeyID int) error
+	AuthenticateAPIKey(ctx context.Context, key string) (*entity.APIKey, *entity.User, error)
+}
+
+type APIKeyUsecase struct {
+	logger     logger.LoggerInterface
+	userRepo   repo.UserRepositoryInterface
+	apiKeyRepo repo.APIKeyRepositoryInterface
+	auditor    AuditRecorder
+}
+
+func NewAPIKeyUsecase(l logger.LoggerInterface, ur repo.UserRepositoryInterface, akr repo.APIKeyRepositoryInterface, ar AuditRecorder) *APIKeyUsecase {
+	return &APIKeyUsecase{
+		logger:     l,
+		userRepo:   ur,
+		apiKeyRepo: akr,
+		auditor:    ar,
+	}
+}
+
+// CreateAPIKey creates a new API key of the user and returns the key, which is shown once as only its hash is stored.
+// Only an admin may have an API key with the admin:* scope
+func (uc *APIKeyUsecase) CreateAPIKey(ctx context.Context, userID int, payload *entity.APIKeyPayload) (*entity.CreatedAPIKey, error) {
+	functionName := "APIKeyUsecase.CreateAPIKey"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.userRepo.GetUserByID: %w", err), functionName)
+	}
+
+	apiKey := &entity.APIKey{
+		UserID: user.ID,
+		Name:   payload.Name,
+		Scopes: payload.Scopes,
+	}
+	if apiKey.HasScope(entity.APIKeyScopeAdmin) && user.Role != entity.UserRoleAdmin {
+		return nil, response.ErrForbidden
+	}
+
+	token, err := auth.GenerateToken()
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("auth.GenerateToken: %w", err), functionName)
+	}
+
+	key := config.APIKeyPrefix + token
+	apiKey.Prefix = key[:config.APIKeyDisplayPrefixLen]
+	apiKey.KeyHash = auth.HashToken(key)
+	if err := uc.apiKeyRepo.CreateAPIKey(ctx, apiKey); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.apiKeyRepo.CreateAPIKey: %w", err), functionName)
+	}
+
+	event := entity.NewAuditEvent(entity.AuditActionAPIKeyCreated, entity.AuditTargetAPIKey, strconv.Itoa(apiKey.ID), entity.AuditDiff{
+		"name":   {To: apiKey.Name},
+		"scopes": {To: apiKey.Scopes},
+	})
+	recordAuditEvent(ctx, uc.auditor, uc.logger, functionName, event)
+
+	return &entity.CreatedAPIKey{APIKey: apiKey, Key: key}, nil
+}
+
+// GetAPIKeys returns the API keys of the user including the revoked ones, the newest first
+func (uc *APIKeyUsecase) GetAPIKeys(ctx context.Context, userID int) ([]*entity.APIKey, error) {
+	functionName := "APIKeyUsecase.GetAPIKeys"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	apiKeys, err := uc.apiKeyRepo.GetAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.apiKeyRepo.GetAPIKeysByUserID: %w", err), functionName)
+	}
+
+	return apiKeys, nil
+}
+
+// RevokeAPIKey revokes the API key of the user, the key is refused from then on
+func (uc *APIKeyUsecase) RevokeAPIKey(ctx context.Context, userID int, apiKeyID int) error {
+	functionName := "APIKeyUsecase.RevokeAPIKey"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if err := uc.apiKeyRepo.RevokeAPIKey(ctx, userID, apiKeyID); err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return err
+		}
+
+		return errors.Wrap(fmt.Errorf("uc.apiKeyRepo.RevokeAPIKey: %w", err), functionName)
+	}
+
+	recordAuditEvent(ctx, uc.auditor, uc.logger, functionName, entity.NewAuditEvent(entity.AuditActionAPIKeyRevoked, entity.AuditTargetAPIKey, strconv.Itoa(apiKeyID), nil))
+
+	return nil
+}
+
+// AuthenticateAPIKey returns the API key and its user given the key. The last use of the key is recorded
+// at most once every APIKeyLastUsedInterval, so an API key used by every request does not write every time
+func (uc *APIKeyUsecase) AuthenticateAPIKey(ctx context.Context, key string) (*entity.APIKey, *entity.User, error) {
+	functionName := "APIKeyUsecase.AuthenticateAPIKey"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, nil, errors.Wrap(err, functionName)
+	}
+
+	if !strings.HasPrefix(key, config.APIKeyPrefix) {
+		return nil, nil, response.ErrUnauthorized("Invalid API key")
+	}
+
+	apiKey, err := uc.apiKeyRepo.GetAPIKeyByHash(ctx, auth.HashToken(key))
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, nil, response.ErrUnauthorized("Invalid API key")
+		}
+
+		return nil, nil, errors.Wrap(fmt.Errorf("uc.apiKeyRepo.GetAPIKeyByHash: %w", err), functionName)
+	}
+
+	if apiKey.RevokedAt != nil {
+		return nil, nil, response.ErrUnauthorized("API key has been revoked")
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, apiKey.UserID)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, nil, response.ErrUnauthorized("Invalid API key")
+		}
+
+		return nil, nil, errors.Wrap(fmt.Errorf("uc.userRepo.GetUserByID: %w", err), functionName)
+	}
+
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= config.APIKeyLastUsedInterval {
+		// The last use is informative, a failed update does not refuse the request
+		if err := uc.apiKeyRepo.UpdateAPIKeyLastUsedAt(ctx, apiKey.ID, now); err == nil {
+			apiKey.LastUsedAt = &now
+		}
+	}
+
+	return apiKey, user, nil
+}
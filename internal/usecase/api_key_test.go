@@ -0,0 +1,351 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/auth"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	testcases := []struct {
+		name       string
+		ctx        context.Context
+		payload    *entity.APIKeyPayload
+		rUserRes   *entity.User
+		rUserErr   error
+		rCreateErr error
+		rRecordErr error
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.CtxEnded(),
+			payload:    &entity.APIKeyPayload{Name: "Catalog sync", Scopes: []string{"read:catalog"}},
+			wantAnyErr: true,
+		},
+		{
+			name:       "invalid payload",
+			ctx:        context.Background(),
+			payload:    &entity.APIKeyPayload{Name: "Catalog sync"},
+			wantErr:    response.ErrInvalidAPIKeyScope,
+			wantAnyErr: true,
+		},
+		{
+			name:       "user not found",
+			ctx:        context.Background(),
+			payload:    &entity.APIKeyPayload{Name: "Catalog sync", Scopes: []string{"read:catalog"}},
+			rUserErr:   response.ErrNotFound,
+			wantErr:    response.ErrNotFound,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to get user",
+			ctx:        context.Background(),
+			payload:    &entity.APIKeyPayload{Name: "Catalog sync", Scopes: []string{"read:catalog"}},
+			rUserErr:   errors.New("error get user"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "admin scope for a customer",
+			ctx:        context.Background(),
+			payload:    &entity.APIKeyPayload{Name: "Catalog sync", Scopes: []string{"admin:*"}},
+			rUserRes:   &entity.User{ID: 123, Role: entity.UserRoleCustomer},
+			wantErr:    response.ErrForbidden,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to create API key",
+			ctx:        context.Background(),
+			payload:    &entity.APIKeyPayload{Name: "Catalog sync", Scopes: []string{"read:catalog"}},
+			rUserRes:   &entity.User{ID: 123, Role: entity.UserRoleCustomer},
+			rCreateErr: errors.New("error create API key"),
+			wantAnyErr: true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			payload:  &entity.APIKeyPayload{Name: "Catalog sync", Scopes: []string{"read:catalog", "write:orders"}},
+			rUserRes: &entity.User{ID: 123, Role: entity.UserRoleCustomer},
+		},
+		{
+			name:     "success with admin scope for an admin",
+			ctx:      context.Background(),
+			payload:  &entity.APIKeyPayload{Name: "Back office", Scopes: []string{"admin:*"}},
+			rUserRes: &entity.User{ID: 123, Role: entity.UserRoleAdmin},
+		},
+		{
+			name:       "success with the audit event not recorded",
+			ctx:        context.Background(),
+			payload:    &entity.APIKeyPayload{Name: "Catalog sync", Scopes: []string{"read:catalog"}},
+			rUserRes:   &entity.User{ID: 123, Role: entity.UserRoleCustomer},
+			rRecordErr: errors.New("error record"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("GetUserByID", mock.Anything, 123).Return(tc.rUserRes, tc.rUserErr)
+
+			apiKeyRepo := &testmock.APIKeyRepositoryInterface{}
+			apiKeyRepo.On("CreateAPIKey", mock.Anything, mock.Anything).Return(tc.rCreateErr)
+
+			// The actor is left to the request info, so an admin acting for the user is recorded as the actor
+			auditRecorder := &testmock.AuditRecorder{}
+			auditRecorder.On("Record", mock.Anything, nil, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+				return event.Action == entity.AuditActionAPIKeyCreated && event.ActorID == 0
+			})).Return(tc.rRecordErr)
+
+			l := newLogger()
+			uc := usecase.NewAPIKeyUsecase(l, userRepo, apiKeyRepo, auditRecorder)
+			res, err := uc.CreateAPIKey(tc.ctx, 123, tc.payload)
+			assert.Equal(t, tc.wantAnyErr, err != nil)
+
+			if tc.rRecordErr != nil {
+				l.AssertCalled(t, "Error", mock.Anything, mock.Anything)
+			} else {
+				l.AssertNotCalled(t, "Error", mock.Anything, mock.Anything)
+			}
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if !tc.wantAnyErr {
+				assert.True(t, strings.HasPrefix(res.Key, "bsk_"))
+				assert.Equal(t, res.Key[:12], res.Prefix)
+				assert.Equal(t, auth.HashToken(res.Key), res.KeyHash)
+				assert.Equal(t, 123, res.UserID)
+				assert.Equal(t, tc.payload.Scopes, res.Scopes)
+			}
+		})
+	}
+}
+
+func TestGetAPIKeys(t *testing.T) {
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		rKeysRes []*entity.APIKey
+		rKeysErr error
+		wantErr  bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "failed to get API keys",
+			ctx:      context.Background(),
+			rKeysErr: errors.New("error get API keys"),
+			wantErr:  true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			rKeysRes: []*entity.APIKey{{ID: 1, UserID: 123}},
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiKeyRepo := &testmock.APIKeyRepositoryInterface{}
+			apiKeyRepo.On("GetAPIKeysByUserID", mock.Anything, 123).Return(tc.rKeysRes, tc.rKeysErr)
+
+			uc := usecase.NewAPIKeyUsecase(newLogger(), &testmock.UserRepositoryInterface{}, apiKeyRepo, newAuditRecorder())
+			res, err := uc.GetAPIKeys(tc.ctx, 123)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				assert.Equal(t, tc.rKeysRes, res)
+			}
+		})
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	testcases := []struct {
+		name       string
+		ctx        context.Context
+		rRevokeErr error
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.CtxEnded(),
+			wantAnyErr: true,
+		},
+		{
+			name:       "API key not found",
+			ctx:        context.Background(),
+			rRevokeErr: response.ErrNotFound,
+			wantErr:    response.ErrNotFound,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to revoke API key",
+			ctx:        context.Background(),
+			rRevokeErr: errors.New("error revoke API key"),
+			wantAnyErr: true,
+		},
+		{
+			name: "success",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiKeyRepo := &testmock.APIKeyRepositoryInterface{}
+			apiKeyRepo.On("RevokeAPIKey", mock.Anything, 123, 1).Return(tc.rRevokeErr)
+
+			uc := usecase.NewAPIKeyUsecase(newLogger(), &testmock.UserRepositoryInterface{}, apiKeyRepo, newAuditRecorder())
+			err := uc.RevokeAPIKey(tc.ctx, 123, 1)
+			assert.Equal(t, tc.wantAnyErr, err != nil)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	now := time.Now()
+	recentlyUsedAt := now.Add(-time.Second)
+
+	testcases := []struct {
+		name           string
+		ctx            context.Context
+		key            string
+		rKeyRes        *entity.APIKey
+		rKeyErr        error
+		rUserErr       error
+		rUpdateErr     error
+		wantErr        error
+		wantAnyErr     bool
+		wantLastUsedAt bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.CtxEnded(),
+			key:        "bsk_key",
+			wantAnyErr: true,
+		},
+		{
+			name:       "key without prefix",
+			ctx:        context.Background(),
+			key:        "key",
+			wantErr:    response.ErrUnauthorized("Invalid API key"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "API key not found",
+			ctx:        context.Background(),
+			key:        "bsk_key",
+			rKeyErr:    response.ErrNotFound,
+			wantErr:    response.ErrUnauthorized("Invalid API key"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to get API key",
+			ctx:        context.Background(),
+			key:        "bsk_key",
+			rKeyErr:    errors.New("error get API key"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "revoked API key",
+			ctx:        context.Background(),
+			key:        "bsk_key",
+			rKeyRes:    &entity.APIKey{ID: 1, UserID: 123, RevokedAt: &now},
+			wantErr:    response.ErrUnauthorized("API key has been revoked"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "user not found",
+			ctx:        context.Background(),
+			key:        "bsk_key",
+			rUserErr:   response.ErrNotFound,
+			wantErr:    response.ErrUnauthorized("Invalid API key"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to get user",
+			ctx:        context.Background(),
+			key:        "bsk_key",
+			rUserErr:   errors.New("error get user"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "success even though the last use is not recorded",
+			ctx:        context.Background(),
+			key:        "bsk_key",
+			rUpdateErr: errors.New("error update last used at"),
+		},
+		{
+			name:           "success",
+			ctx:            context.Background(),
+			key:            "bsk_key",
+			wantLastUsedAt: true,
+		},
+		{
+			name:    "success with a recently used API key",
+			ctx:     context.Background(),
+			key:     "bsk_key",
+			rKeyRes: &entity.APIKey{ID: 1, UserID: 123, LastUsedAt: &recentlyUsedAt},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiKey := tc.rKeyRes
+			if apiKey == nil {
+				apiKey = &entity.APIKey{ID: 1, UserID: 123}
+			}
+
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("GetUserByID", mock.Anything, 123).Return(&entity.User{ID: 123}, tc.rUserErr)
+
+			apiKeyRepo := &testmock.APIKeyRepositoryInterface{}
+			apiKeyRepo.On("GetAPIKeyByHash", mock.Anything, auth.HashToken(tc.key)).Return(apiKey, tc.rKeyErr)
+			apiKeyRepo.On("UpdateAPIKeyLastUsedAt", mock.Anything, 1, mock.Anything).Return(tc.rUpdateErr)
+
+			uc := usecase.NewAPIKeyUsecase(newLogger(), userRepo, apiKeyRepo, newAuditRecorder())
+			resKey, resUser, err := uc.AuthenticateAPIKey(tc.ctx, tc.key)
+			assert.Equal(t, tc.wantAnyErr, err != nil)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if !tc.wantAnyErr {
+				assert.Equal(t, 1, resKey.ID)
+				assert.Equal(t, 123, resUser.ID)
+			}
+
+			if tc.wantLastUsedAt {
+				apiKeyRepo.AssertCalled(t, "UpdateAPIKeyLastUsedAt", mock.Anything, 1, mock.Anything)
+				assert.NotNil(t, resKey.LastUsedAt)
+			}
+
+			if tc.rKeyRes != nil && tc.rKeyRes.LastUsedAt != nil {
+				apiKeyRepo.AssertNotCalled(t, "UpdateAPIKeyLastUsedAt", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
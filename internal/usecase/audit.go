@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	repo "github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
+)
+
+// AuditRecorder writes the security- and money-relevant actions to the audit log
+type AuditRecorder interface {
+	Record(ctx context.Context, dbTrx interface{}, event *entity.AuditEvent) error
+}
+
+// AuditUsecaseInterface define contract for audit log related functions to usecase
+type AuditUsecaseInterface interface {
+	AuditRecorder
+	GetAuditEvents(ctx context.Context, payload entity.GetAuditEventsPayload) ([]*entity.AuditEvent, int, error)
+	VerifyAuditEvents(ctx context.Context) (*entity.AuditVerification, error)
+}
+
+type AuditUsecase struct {
+	dbTransactionRepo repo.PostgresTransactionRepositoryInterface
+	auditRepo         repo.AuditRepositoryInterface
+}
+
+func NewAuditUsecase(ptr repo.PostgresTransactionRepositoryInterface, ar repo.AuditRepositoryInterface) *AuditUsecase {
+	return &AuditUsecase{
+		dbTransactionRepo: ptr,
+		auditRepo:         ar,
+	}
+}
+
+// Record appends the event to the audit log with the request ID and the IP address of the request in ctx,
+// and its authenticated user as the actor unless the event has one. The event is written within dbTrx when given,
+// so it is kept or rolled back together with the action, else in a transaction of its own
+func (uc *AuditUsecase) Record(ctx context.Context, dbTrx interface{}, event *entity.AuditEvent) error {
+	functionName := "AuditUsecase.Record"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	info := helper.GetRequestInfo(ctx)
+	event.RequestID = info.RequestID
+	event.IPAddress = info.IPAddress
+	if event.ActorID == 0 {
+		event.ActorID = info.ActorID
+	}
+
+	if dbTrx != nil {
+		if err := uc.auditRepo.CreateAuditEvent(ctx, dbTrx, event); err != nil {
+			return errors.Wrap(fmt.Errorf("uc.auditRepo.CreateAuditEvent: %w", err), functionName)
+		}
+
+		return nil
+	}
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	if err := uc.auditRepo.CreateAuditEvent(ctx, tx, event); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.auditRepo.CreateAuditEvent: %w", err), functionName)
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	return nil
+}
+
+// recordAuditEvent writes the event in a transaction of its own after an action which is already done,
+// so a failure does not fail the action, it is logged instead
+func recordAuditEvent(ctx context.Context, auditor AuditRecorder, l logger.LoggerInterface, functionName string, event *entity.AuditEvent) {
+	if err := auditor.Record(ctx, nil, event); err != nil {
+		err = errors.Wrap(fmt.Errorf("uc.auditor.Record: %w", err), functionName)
+		tracing.RecordError(ctx, err)
+		l.WithContext(ctx).Error(err, fmt.Sprintf("usecase - audit - %s: %s not recorded", functionName, event.Action))
+	}
+}
+
+// GetAuditEvents returns the audit events matching the payload filters, newest first, and their count
+func (uc *AuditUsecase) GetAuditEvents(ctx context.Context, payload entity.GetAuditEventsPayload) ([]*entity.AuditEvent, int, error) {
+	functionName := "AuditUsecase.GetAuditEvents"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, 0, errors.Wrap(err, functionName)
+	}
+
+	if err := payload.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	events, err := uc.auditRepo.GetAuditEvents(ctx, payload)
+	if err != nil {
+		return nil, 0, errors.Wrap(fmt.Errorf("uc.auditRepo.GetAuditEvents: %w", err), functionName)
+	}
+
+	count, err := uc.auditRepo.GetAuditEventsCount(ctx, payload)
+	if err != nil {
+		return nil, 0, errors.Wrap(fmt.Errorf("uc.auditRepo.GetAuditEventsCount: %w", err), functionName)
+	}
+
+	return events, count, nil
+}
+
+// VerifyAuditEvents walks the audit log from the first event and checks every event is chained to the one before it
+// and still has the hash it was written with. It stops at the first broken event, which was changed, or follows
+// deleted events, since it was written
+func (uc *AuditUsecase) VerifyAuditEvents(ctx context.Context) (*entity.AuditVerification, error) {
+	functionName := "AuditUsecase.VerifyAuditEvents"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	verification := &entity.AuditVerification{Valid: true}
+	prevHash := entity.AuditGenesisHash
+	err := uc.auditRepo.StreamAuditEvents(ctx, func(event *entity.AuditEvent) error {
+		if !verification.Valid {
+			return nil
+		}
+
+		if event.PrevHash != prevHash || event.ComputeHash() != event.Hash {
+			verification.Valid = false
+			verification.BrokenEventID = event.ID
+
+			return nil
+		}
+
+		verification.Checked++
+		prevHash = event.Hash
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.auditRepo.StreamAuditEvents: %w", err), functionName)
+	}
+
+	return verification, nil
+}
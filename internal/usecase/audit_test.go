@@ -0,0 +1,280 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newAuditRecorder returns an audit recorder accepting every event
+func newAuditRecorder() *testmock.AuditRecorder {
+	auditRecorder := &testmock.AuditRecorder{}
+	auditRecorder.On("Record", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	return auditRecorder
+}
+
+func TestRecord(t *testing.T) {
+	requestCtx := helper.WithRequestInfo(context.Background(), helper.RequestInfo{RequestID: "request-id", IPAddress: "127.0.0.1", ActorID: 2})
+
+	testcases := []struct {
+		name          string
+		ctx           context.Context
+		dbTrx         interface{}
+		actorID       int
+		rStartTrxErr  error
+		rCreateErr    error
+		rCommitTrxErr error
+		expectedActor int
+		wantErr       bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          requestCtx,
+			rStartTrxErr: errors.New("error start transaction"),
+			wantErr:      true,
+		},
+		{
+			name:       "failed to create audit event",
+			ctx:        requestCtx,
+			rCreateErr: errors.New("error create audit event"),
+			wantErr:    true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           requestCtx,
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantErr:       true,
+		},
+		{
+			name:       "failed to create audit event within transaction",
+			ctx:        requestCtx,
+			dbTrx:      &sqlx.Tx{},
+			rCreateErr: errors.New("error create audit event"),
+			wantErr:    true,
+		},
+		{
+			name:          "success",
+			ctx:           requestCtx,
+			expectedActor: 2,
+			wantErr:       false,
+		},
+		{
+			name:          "success within transaction",
+			ctx:           requestCtx,
+			dbTrx:         &sqlx.Tx{},
+			expectedActor: 2,
+			wantErr:       false,
+		},
+		{
+			name:          "success with actor of the event",
+			ctx:           requestCtx,
+			actorID:       7,
+			expectedActor: 7,
+			wantErr:       false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			auditRepo := &testmock.AuditRepositoryInterface{}
+			auditRepo.On("CreateAuditEvent", mock.Anything, mock.Anything, mock.Anything).Return(tc.rCreateErr)
+
+			uc := usecase.NewAuditUsecase(dbTransactionRepo, auditRepo)
+			event := entity.NewAuditEvent(entity.AuditActionOrderCreated, entity.AuditTargetOrder, "1", nil)
+			event.ActorID = tc.actorID
+			err := uc.Record(tc.ctx, tc.dbTrx, event)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, tc.expectedActor, event.ActorID)
+				assert.Equal(t, "request-id", event.RequestID)
+				assert.Equal(t, "127.0.0.1", event.IPAddress)
+			}
+
+			if tc.dbTrx != nil {
+				dbTransactionRepo.AssertNotCalled(t, "StartTransactionQuery", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestGetAuditEvents(t *testing.T) {
+	from := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+
+	testcases := []struct {
+		name       string
+		ctx        context.Context
+		payload    entity.GetAuditEventsPayload
+		rFetchErr  error
+		rCountErr  error
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.CtxEnded(),
+			wantAnyErr: true,
+		},
+		{
+			name:       "invalid time range",
+			ctx:        context.Background(),
+			payload:    entity.GetAuditEventsPayload{From: &from, To: &to},
+			wantErr:    response.ErrInvalidAuditTimeRange,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to get audit events",
+			ctx:        context.Background(),
+			rFetchErr:  errors.New("error get audit events"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to count audit events",
+			ctx:        context.Background(),
+			rCountErr:  errors.New("error count audit events"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "success",
+			ctx:        context.Background(),
+			wantAnyErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			auditRepo := &testmock.AuditRepositoryInterface{}
+			auditRepo.On("GetAuditEvents", mock.Anything, mock.Anything).Return([]*entity.AuditEvent{{ID: 1}}, tc.rFetchErr)
+			auditRepo.On("GetAuditEventsCount", mock.Anything, mock.Anything).Return(1, tc.rCountErr)
+
+			uc := usecase.NewAuditUsecase(&testmock.PostgresTransactionRepositoryInterface{}, auditRepo)
+			events, count, err := uc.GetAuditEvents(tc.ctx, tc.payload)
+			assert.Equal(t, tc.wantAnyErr, err != nil, err)
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+			if !tc.wantAnyErr {
+				assert.Equal(t, []*entity.AuditEvent{{ID: 1}}, events)
+				assert.Equal(t, 1, count)
+			}
+		})
+	}
+}
+
+func TestVerifyAuditEvents(t *testing.T) {
+	newChain := func() []*entity.AuditEvent {
+		prevHash := entity.AuditGenesisHash
+		events := []*entity.AuditEvent{}
+		for i := 1; i <= 3; i++ {
+			event := entity.NewAuditEvent(entity.AuditActionLoginSucceeded, entity.AuditTargetUser, "2", nil)
+			event.ID = i
+			event.PrevHash = prevHash
+			event.CreatedAt = time.Date(2024, 6, 30, 9, i, 0, 0, time.UTC)
+			event.Hash = event.ComputeHash()
+			prevHash = event.Hash
+			events = append(events, event)
+		}
+
+		return events
+	}
+
+	testcases := []struct {
+		name       string
+		ctx        context.Context
+		events     func() []*entity.AuditEvent
+		rStreamErr error
+		expected   *entity.AuditVerification
+		wantErr    bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			events:  newChain,
+			wantErr: true,
+		},
+		{
+			name:       "failed to stream audit events",
+			ctx:        context.Background(),
+			events:     newChain,
+			rStreamErr: errors.New("error stream audit events"),
+			wantErr:    true,
+		},
+		{
+			name:     "valid chain",
+			ctx:      context.Background(),
+			events:   newChain,
+			expected: &entity.AuditVerification{Valid: true, Checked: 3},
+			wantErr:  false,
+		},
+		{
+			name: "changed event",
+			ctx:  context.Background(),
+			events: func() []*entity.AuditEvent {
+				events := newChain()
+				events[1].TargetID = "3"
+				return events
+			},
+			expected: &entity.AuditVerification{Valid: false, Checked: 1, BrokenEventID: 2},
+			wantErr:  false,
+		},
+		{
+			name: "deleted event",
+			ctx:  context.Background(),
+			events: func() []*entity.AuditEvent {
+				events := newChain()
+				return append(events[:1], events[2:]...)
+			},
+			expected: &entity.AuditVerification{Valid: false, Checked: 1, BrokenEventID: 3},
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			events := tc.events()
+			auditRepo := &testmock.AuditRepositoryInterface{}
+			auditRepo.On("StreamAuditEvents", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(*entity.AuditEvent) error) error {
+				if tc.rStreamErr != nil {
+					return tc.rStreamErr
+				}
+
+				for _, event := range events {
+					if err := fn(event); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			})
+
+			uc := usecase.NewAuditUsecase(&testmock.PostgresTransactionRepositoryInterface{}, auditRepo)
+			result, err := uc.VerifyAuditEvents(tc.ctx)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+			if !tc.wantErr {
+				assert.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}
@@ -1,37 +1,83 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/png"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/config"
 	"github.com/satriowisnugroho/book-store/internal/entity"
 	"github.com/satriowisnugroho/book-store/internal/helper"
 	repo "github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/pkg/blobstore"
+	"github.com/satriowisnugroho/book-store/pkg/catalogfeed"
+	"github.com/satriowisnugroho/book-store/pkg/isbn"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+	"github.com/satriowisnugroho/book-store/pkg/thumbnail"
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
 )
 
+// bookImportBatchFailedReason rejects the rows of an import batch which failed to be written
+const bookImportBatchFailedReason = "Batch of the row could not be written, import the row again"
+
+// bookCoverExtensions maps the supported cover content types to the extension of the stored original
+var bookCoverExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
 // BookUsecaseInterface define contract for book related functions to usecase
 type BookUsecaseInterface interface {
 	GetBooks(ctx context.Context, payload entity.GetBooksPayload) ([]*entity.Book, int, error)
+	GetBookByIsbn(ctx context.Context, isbnStr string) (*entity.Book, error)
+	ImportBooks(ctx context.Context, format string, r io.Reader) (*entity.BookImportReport, error)
+	UploadBookCover(ctx context.Context, bookID int, r io.Reader) (*entity.Book, error)
+	RefreshBestsellers(ctx context.Context) error
+	GetBestsellers(ctx context.Context, payload entity.GetBestsellersPayload) ([]*entity.Bestseller, int, error)
 }
 
 type BookUsecase struct {
-	repo repo.BookRepositoryInterface
+	logger            logger.LoggerInterface
+	dbTransactionRepo repo.PostgresTransactionRepositoryInterface
+	repo              repo.BookRepositoryInterface
+	blobStore         blobstore.BlobStore
+	auditor           AuditRecorder
 }
 
-func NewBookUsecase(r repo.BookRepositoryInterface) *BookUsecase {
+func NewBookUsecase(l logger.LoggerInterface, ptr repo.PostgresTransactionRepositoryInterface, r repo.BookRepositoryInterface, bs blobstore.BlobStore, ar AuditRecorder) *BookUsecase {
 	return &BookUsecase{
-		repo: r,
+		logger:            l,
+		dbTransactionRepo: ptr,
+		repo:              r,
+		blobStore:         bs,
+		auditor:           ar,
 	}
 }
 
 func (uc *BookUsecase) GetBooks(ctx context.Context, payload entity.GetBooksPayload) ([]*entity.Book, int, error) {
 	functionName := "BookUsecase.GetBooks"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
 
 	if err := helper.CheckDeadline(ctx); err != nil {
 		return nil, 0, errors.Wrap(err, functionName)
 	}
 
+	if !entity.IsValidBookSort(payload.SortBy) {
+		return nil, 0, response.ErrInvalidSort
+	}
+
 	books, err := uc.repo.GetBooks(ctx, payload)
 	if err != nil {
 		return nil, 0, errors.Wrap(fmt.Errorf("uc.repo.GetBooks: %w", err), functionName)
@@ -42,5 +88,423 @@ func (uc *BookUsecase) GetBooks(ctx context.Context, payload entity.GetBooksPayl
 		return nil, 0, errors.Wrap(fmt.Errorf("uc.repo.GetBooksCount: %w", err), functionName)
 	}
 
+	for _, book := range books {
+		setBookCoverURLs(uc.blobStore, book)
+	}
+
 	return books, count, nil
 }
+
+func (uc *BookUsecase) GetBookByIsbn(ctx context.Context, isbnStr string) (*entity.Book, error) {
+	functionName := "BookUsecase.GetBookByIsbn"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	normalizedIsbn, err := isbn.Normalize(isbnStr)
+	if err != nil {
+		return nil, response.ErrInvalidIsbn
+	}
+
+	book, err := uc.repo.GetBookByIsbn(ctx, normalizedIsbn)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.repo.GetBookByIsbn: %w", err), functionName)
+	}
+
+	setBookCoverURLs(uc.blobStore, book)
+
+	return book, nil
+}
+
+// ImportBooks upserts the books of a catalog feed by ISBN, the valid rows are written in batches
+// with one transaction per batch. The rows of a batch refused by the database for their values are rejected
+// and the import goes on with the next batch, so the report tells which rows have to be imported again.
+// Any other failure, such as a lost connection, stops the import, the batches written before are kept
+// and the feed can be imported again as a whole
+func (uc *BookUsecase) ImportBooks(ctx context.Context, format string, r io.Reader) (*entity.BookImportReport, error) {
+	functionName := "BookUsecase.ImportBooks"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	reader, err := catalogfeed.NewReader(format, r)
+	if err != nil {
+		if err == catalogfeed.ErrUnsupportedFormat {
+			return nil, response.ErrUnsupportedFileFormat
+		}
+
+		return nil, response.ErrInvalidFile
+	}
+
+	report := &entity.BookImportReport{Rows: []*entity.BookImportRowResult{}}
+	seenIsbns := map[string]bool{}
+	books := make([]*entity.Book, 0, config.BookImportBatchSize)
+	results := make([]*entity.BookImportRowResult, 0, config.BookImportBatchSize)
+	lastRow := 0
+
+	// The rows of a batch are rejected when the database refuses the values of a row, any other error is returned
+	writeBatch := func() error {
+		err := uc.upsertBooksBatch(ctx, books, results)
+		if err == nil {
+			return nil
+		}
+
+		err = errors.Wrap(fmt.Errorf("uc.upsertBooksBatch: %w", err), functionName)
+		if !repo.IsRowError(err) {
+			return err
+		}
+
+		tracing.RecordError(ctx, err)
+		uc.logger.WithContext(ctx).Error(err, "usecase - book - ImportBooks: batch rejected")
+		for _, result := range results {
+			result.Status = entity.BookImportStatusRejected
+			result.Reason = bookImportBatchFailedReason
+		}
+
+		return nil
+	}
+
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			// The rest of the feed cannot be read, keep what has been read so far
+			report.Rows = append(report.Rows, rejectedImportRow(lastRow+1, "", fmt.Sprintf("Feed could not be read any further: %s", err)))
+			break
+		}
+		lastRow = record.Row
+
+		book, reason := uc.bookFromFeedRecord(record, seenIsbns)
+		if book == nil {
+			report.Rows = append(report.Rows, rejectedImportRow(record.Row, record.Isbn, reason))
+			continue
+		}
+
+		result := &entity.BookImportRowResult{Row: record.Row, Isbn: book.Isbn}
+		report.Rows = append(report.Rows, result)
+		books = append(books, book)
+		results = append(results, result)
+
+		if len(books) == config.BookImportBatchSize {
+			if err := writeBatch(); err != nil {
+				return nil, err
+			}
+
+			books = books[:0]
+			results = results[:0]
+		}
+	}
+
+	if len(books) > 0 {
+		if err := writeBatch(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, row := range report.Rows {
+		switch row.Status {
+		case entity.BookImportStatusCreated:
+			report.Created++
+		case entity.BookImportStatusUpdated:
+			report.Updated++
+		default:
+			report.Rejected++
+		}
+	}
+
+	return report, nil
+}
+
+// bookFromFeedRecord validates a feed record and returns the book to upsert or the rejection reason
+func (uc *BookUsecase) bookFromFeedRecord(record *catalogfeed.Record, seenIsbns map[string]bool) (*entity.Book, string) {
+	if record.Err != nil {
+		return nil, fmt.Sprintf("Malformed row: %s", record.Err)
+	}
+
+	price, err := catalogfeed.ParsePrice(record.Price)
+	if err != nil {
+		return nil, response.ErrInvalidPrice.Message
+	}
+
+	payload := &entity.BookPayload{Isbn: record.Isbn, Title: record.Title, Price: price, Category: record.Category}
+	if err := payload.Validate(); err != nil {
+		return nil, err.Error()
+	}
+
+	normalizedIsbn, _ := isbn.Normalize(payload.Isbn)
+	if seenIsbns[normalizedIsbn] {
+		return nil, "Duplicate ISBN in the feed"
+	}
+	seenIsbns[normalizedIsbn] = true
+
+	return &entity.Book{Isbn: normalizedIsbn, Title: payload.Title, Price: payload.Price, Category: payload.Category}, ""
+}
+
+// upsertBooksBatch upserts the books in a single transaction and fills in the matching row results,
+// the price changes are written to the audit log within the transaction
+func (uc *BookUsecase) upsertBooksBatch(ctx context.Context, books []*entity.Book, results []*entity.BookImportRowResult) error {
+	// Begin transaction
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	statuses := make([]string, len(books))
+	for i, book := range books {
+		previousPrice, created, err := uc.repo.UpsertBook(ctx, tx, book)
+		if err != nil {
+			return fmt.Errorf("uc.repo.UpsertBook: %w", err)
+		}
+
+		if !created && previousPrice != book.Price {
+			event := entity.NewAuditEvent(entity.AuditActionBookPriceChanged, entity.AuditTargetBook, strconv.Itoa(book.ID), entity.AuditDiff{
+				"price": {From: previousPrice, To: book.Price},
+			})
+			if err := uc.auditor.Record(ctx, tx, event); err != nil {
+				return fmt.Errorf("uc.auditor.Record: %w", err)
+			}
+		}
+
+		statuses[i] = entity.BookImportStatusUpdated
+		if created {
+			statuses[i] = entity.BookImportStatusCreated
+		}
+	}
+
+	// Commit transaction
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err)
+	}
+	rollbackProcess = false
+
+	for i, book := range books {
+		results[i].BookID = book.ID
+		results[i].Status = statuses[i]
+	}
+
+	return nil
+}
+
+func rejectedImportRow(row int, isbnStr, reason string) *entity.BookImportRowResult {
+	return &entity.BookImportRowResult{
+		Row:    row,
+		Isbn:   isbnStr,
+		Status: entity.BookImportStatusRejected,
+		Reason: reason,
+	}
+}
+
+// UploadBookCover stores the image as the book cover along with its thumbnails.
+// Every upload is stored under a new key so cached covers never go stale, the previous cover is removed
+func (uc *BookUsecase) UploadBookCover(ctx context.Context, bookID int, r io.Reader) (*entity.Book, error) {
+	functionName := "BookUsecase.UploadBookCover"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	book, err := uc.repo.GetBookByID(ctx, bookID)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.repo.GetBookByID: %w", err), functionName)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, config.BookCoverMaxFileSize+1))
+	if err != nil || len(data) == 0 || len(data) > config.BookCoverMaxFileSize {
+		return nil, response.ErrInvalidImage
+	}
+
+	// Sniff the content instead of trusting the client supplied content type
+	ext, ok := bookCoverExtensions[http.DetectContentType(data)]
+	if !ok {
+		return nil, response.ErrUnsupportedImageType
+	}
+
+	// Check the dimensions before decoding so a small file cannot allocate a huge image
+	imgConfig, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || imgConfig.Width > config.BookCoverMaxDimension || imgConfig.Height > config.BookCoverMaxDimension {
+		return nil, response.ErrInvalidImage
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, response.ErrInvalidImage
+	}
+
+	coverKey := fmt.Sprintf("%s%d/%d/%s%s", entity.BookCoverKeyPrefix, book.ID, time.Now().UnixNano(), entity.BookCoverOriginal, ext)
+	if err := uc.storeBookCover(ctx, coverKey, data, img); err != nil {
+		uc.blobStore.DeletePrefix(ctx, path.Dir(coverKey))
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := uc.repo.UpdateBookCoverKey(ctx, book.ID, coverKey); err != nil {
+		uc.blobStore.DeletePrefix(ctx, path.Dir(coverKey))
+		return nil, errors.Wrap(fmt.Errorf("uc.repo.UpdateBookCoverKey: %w", err), functionName)
+	}
+
+	// The new cover is already saved, a leftover previous cover only wastes space
+	if book.CoverKey != "" {
+		uc.blobStore.DeletePrefix(ctx, path.Dir(book.CoverKey))
+	}
+
+	recordAuditEvent(ctx, uc.auditor, uc.logger, functionName, entity.NewAuditEvent(entity.AuditActionBookCoverUploaded, entity.AuditTargetBook, strconv.Itoa(book.ID), entity.AuditDiff{
+		"cover_key": {From: book.CoverKey, To: coverKey},
+	}))
+
+	book.CoverKey = coverKey
+	setBookCoverURLs(uc.blobStore, book)
+
+	return book, nil
+}
+
+// storeBookCover stores the original image and a JPEG thumbnail for every thumbnail width next to it
+func (uc *BookUsecase) storeBookCover(ctx context.Context, coverKey string, data []byte, img image.Image) error {
+	if err := uc.blobStore.Put(ctx, coverKey, bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("uc.blobStore.Put: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+	for name, width := range entity.BookCoverThumbnailWidths {
+		buf.Reset()
+		if err := thumbnail.Encode(buf, img, width); err != nil {
+			return fmt.Errorf("thumbnail.Encode: %w", err)
+		}
+
+		if err := uc.blobStore.Put(ctx, bookCoverThumbnailKey(coverKey, name), buf); err != nil {
+			return fmt.Errorf("uc.blobStore.Put: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RefreshBestsellers recomputes the bestsellers of every period from the order items,
+// it is a no-op when another instance is already refreshing them
+func (uc *BookUsecase) RefreshBestsellers(ctx context.Context) error {
+	functionName := "BookUsecase.RefreshBestsellers"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	if _, err := uc.repo.RefreshBestsellers(ctx, tx); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.repo.RefreshBestsellers: %w", err), functionName)
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	return nil
+}
+
+// GetBestsellers returns the ranked bestsellers of a period, optionally of a single category
+func (uc *BookUsecase) GetBestsellers(ctx context.Context, payload entity.GetBestsellersPayload) ([]*entity.Bestseller, int, error) {
+	functionName := "BookUsecase.GetBestsellers"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, 0, errors.Wrap(err, functionName)
+	}
+
+	if err := payload.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	bestsellers, err := uc.repo.GetBestsellers(ctx, payload)
+	if err != nil {
+		return nil, 0, errors.Wrap(fmt.Errorf("uc.repo.GetBestsellers: %w", err), functionName)
+	}
+
+	count, err := uc.repo.GetBestsellersCount(ctx, payload)
+	if err != nil {
+		return nil, 0, errors.Wrap(fmt.Errorf("uc.repo.GetBestsellersCount: %w", err), functionName)
+	}
+
+	if len(bestsellers) == 0 {
+		return bestsellers, count, nil
+	}
+
+	bookIDs := make([]int, 0, len(bestsellers))
+	for _, bestseller := range bestsellers {
+		bookIDs = append(bookIDs, bestseller.BookID)
+	}
+
+	books, err := uc.repo.GetBooksByIDs(ctx, bookIDs)
+	if err != nil {
+		return nil, 0, errors.Wrap(fmt.Errorf("uc.repo.GetBooksByIDs: %w", err), functionName)
+	}
+
+	booksByID := make(map[int]*entity.Book, len(books))
+	for _, book := range books {
+		setBookCoverURLs(uc.blobStore, book)
+		booksByID[book.ID] = book
+	}
+
+	for i, bestseller := range bestsellers {
+		bestseller.Rank = payload.Offset + i + 1
+		bestseller.Book = booksByID[bestseller.BookID]
+	}
+
+	return bestsellers, count, nil
+}
+
+// setBookCoverURLs fills the URLs of the book cover and its thumbnails, books without a cover are left untouched
+func setBookCoverURLs(bs blobstore.BlobStore, book *entity.Book) {
+	if book.CoverKey == "" {
+		return
+	}
+
+	book.CoverURLs = map[string]string{entity.BookCoverOriginal: bs.URL(book.CoverKey)}
+	for name := range entity.BookCoverThumbnailWidths {
+		book.CoverURLs[name] = bs.URL(bookCoverThumbnailKey(book.CoverKey, name))
+	}
+}
+
+// bookCoverThumbnailKey returns the key of a thumbnail, thumbnails are stored next to the original cover
+func bookCoverThumbnailKey(coverKey, name string) string {
+	return path.Join(path.Dir(coverKey), name+".jpg")
+}
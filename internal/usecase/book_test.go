@@ -1,22 +1,37 @@
 package usecase_test
 
 import (
+	"bytes"
 	"context"
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
 	"testing"
 
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
 	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/blobstore"
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
 	"github.com/satriowisnugroho/book-store/test/fixture"
 	testmock "github.com/satriowisnugroho/book-store/test/mock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestGetBooks(t *testing.T) {
 	testcases := []struct {
 		name              string
 		ctx               context.Context
+		payload           entity.GetBooksPayload
 		rGetBooksRes      []*entity.Book
 		rGetBooksErr      error
 		rGetBooksCountRes int
@@ -28,6 +43,12 @@ func TestGetBooks(t *testing.T) {
 			ctx:     fixture.CtxEnded(),
 			wantErr: true,
 		},
+		{
+			name:    "invalid sort",
+			ctx:     context.Background(),
+			payload: entity.GetBooksPayload{SortBy: "price"},
+			wantErr: true,
+		},
 		{
 			name:         "failed to get books",
 			ctx:          context.Background(),
@@ -53,9 +74,642 @@ func TestGetBooks(t *testing.T) {
 			bookRepo.On("GetBooks", mock.Anything, mock.Anything).Return(tc.rGetBooksRes, tc.rGetBooksErr)
 			bookRepo.On("GetBooksCount", mock.Anything, mock.Anything).Return(tc.rGetBooksCountRes, tc.rGetBooksCountErr)
 
-			uc := usecase.NewBookUsecase(bookRepo)
-			_, _, err := uc.GetBooks(tc.ctx, entity.GetBooksPayload{})
+			uc := usecase.NewBookUsecase(newLogger(), &testmock.PostgresTransactionRepositoryInterface{}, bookRepo, &testmock.BlobStore{}, newAuditRecorder())
+			_, _, err := uc.GetBooks(tc.ctx, tc.payload)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestGetBookByIsbn(t *testing.T) {
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		isbn     string
+		rBookRes *entity.Book
+		rBookErr error
+		wantErr  bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid isbn",
+			ctx:     context.Background(),
+			isbn:    "978-0-545-01022-10",
+			wantErr: true,
+		},
+		{
+			name:     "record not found",
+			ctx:      context.Background(),
+			isbn:     "978-0-545-01022-1",
+			rBookErr: response.ErrNotFound,
+			wantErr:  true,
+		},
+		{
+			name:     "failed to get book by isbn",
+			ctx:      context.Background(),
+			isbn:     "978-0-545-01022-1",
+			rBookErr: errors.New("error get book by isbn"),
+			wantErr:  true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			isbn:     "0-545-01022-5",
+			rBookRes: &entity.Book{},
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("GetBookByIsbn", mock.Anything, "9780545010221").Return(tc.rBookRes, tc.rBookErr)
+
+			uc := usecase.NewBookUsecase(newLogger(), &testmock.PostgresTransactionRepositoryInterface{}, bookRepo, &testmock.BlobStore{}, newAuditRecorder())
+			_, err := uc.GetBookByIsbn(tc.ctx, tc.isbn)
 			assert.Equal(t, tc.wantErr, err != nil)
 		})
 	}
 }
+
+func TestImportBooks(t *testing.T) {
+	feed := "isbn,title,price\n" +
+		"978-0-545-01022-1,Harry Potter,25000\n" +
+		"0-545-01022-5,Harry Potter,25000\n" +
+		"978-0-545-01022-10,Narnia,20000\n" +
+		"978-0-545-01023-8,,20000\n" +
+		"978-0-545-01023-8,The Godfather,free\n" +
+		"978-0-545-01023-8,The Godfather,35000\n"
+
+	// The rows of the feed are written in a single batch, which is rejected when the database refuses a row
+	failedBatchReport := &entity.BookImportReport{
+		Rejected: 6,
+		Rows: []*entity.BookImportRowResult{
+			{Row: 2, Isbn: "9780545010221", Status: entity.BookImportStatusRejected, Reason: "Batch of the row could not be written, import the row again"},
+			{Row: 3, Isbn: "0-545-01022-5", Status: entity.BookImportStatusRejected, Reason: "Duplicate ISBN in the feed"},
+			{Row: 4, Isbn: "978-0-545-01022-10", Status: entity.BookImportStatusRejected, Reason: response.ErrInvalidIsbn.Message},
+			{Row: 5, Isbn: "978-0-545-01023-8", Status: entity.BookImportStatusRejected, Reason: response.ErrInvalidTitle.Message},
+			{Row: 6, Isbn: "978-0-545-01023-8", Status: entity.BookImportStatusRejected, Reason: response.ErrInvalidPrice.Message},
+			{Row: 7, Isbn: "9780545010238", Status: entity.BookImportStatusRejected, Reason: "Batch of the row could not be written, import the row again"},
+		},
+	}
+
+	testcases := []struct {
+		name          string
+		ctx           context.Context
+		format        string
+		feed          string
+		rStartTrxErr  error
+		rCommitTrxErr error
+		// rPreviousPrice is the price of the existing books, zero when the books are new
+		rPreviousPrice int
+		rUpsertErr     error
+		rRecordErr     error
+		expectedEvents int
+		expected       *entity.BookImportReport
+		wantErr        bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "unsupported format",
+			ctx:     context.Background(),
+			format:  "pdf",
+			wantErr: true,
+		},
+		{
+			name:    "missing csv header",
+			ctx:     context.Background(),
+			format:  "csv",
+			feed:    "title,price\n",
+			wantErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          context.Background(),
+			format:       "csv",
+			feed:         feed,
+			rStartTrxErr: errors.New("error start transaction"),
+			wantErr:      true,
+		},
+		{
+			name:       "failed to upsert book",
+			ctx:        context.Background(),
+			format:     "csv",
+			feed:       feed,
+			rUpsertErr: errors.New("error upsert book"),
+			wantErr:    true,
+		},
+		{
+			name:       "book refused by the database",
+			ctx:        context.Background(),
+			format:     "csv",
+			feed:       feed,
+			rUpsertErr: &pq.Error{Code: "23514"},
+			expected:   failedBatchReport,
+			wantErr:    false,
+		},
+		{
+			name:           "failed to record price change",
+			ctx:            context.Background(),
+			format:         "csv",
+			feed:           feed,
+			rPreviousPrice: 30000,
+			rRecordErr:     errors.New("error record audit event"),
+			expectedEvents: 1,
+			wantErr:        true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           context.Background(),
+			format:        "csv",
+			feed:          feed,
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantErr:       true,
+		},
+		{
+			name:   "success",
+			ctx:    context.Background(),
+			format: "csv",
+			feed:   feed,
+			expected: &entity.BookImportReport{
+				Created:  2,
+				Rejected: 4,
+				Rows: []*entity.BookImportRowResult{
+					{Row: 2, Isbn: "9780545010221", Status: entity.BookImportStatusCreated},
+					{Row: 3, Isbn: "0-545-01022-5", Status: entity.BookImportStatusRejected, Reason: "Duplicate ISBN in the feed"},
+					{Row: 4, Isbn: "978-0-545-01022-10", Status: entity.BookImportStatusRejected, Reason: response.ErrInvalidIsbn.Message},
+					{Row: 5, Isbn: "978-0-545-01023-8", Status: entity.BookImportStatusRejected, Reason: response.ErrInvalidTitle.Message},
+					{Row: 6, Isbn: "978-0-545-01023-8", Status: entity.BookImportStatusRejected, Reason: response.ErrInvalidPrice.Message},
+					{Row: 7, Isbn: "9780545010238", Status: entity.BookImportStatusCreated},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:           "success with a price change",
+			ctx:            context.Background(),
+			format:         "csv",
+			feed:           feed,
+			rPreviousPrice: 25000,
+			expectedEvents: 1,
+			expected: &entity.BookImportReport{
+				Updated:  2,
+				Rejected: 4,
+				Rows: []*entity.BookImportRowResult{
+					{Row: 2, Isbn: "9780545010221", Status: entity.BookImportStatusUpdated},
+					{Row: 3, Isbn: "0-545-01022-5", Status: entity.BookImportStatusRejected, Reason: "Duplicate ISBN in the feed"},
+					{Row: 4, Isbn: "978-0-545-01022-10", Status: entity.BookImportStatusRejected, Reason: response.ErrInvalidIsbn.Message},
+					{Row: 5, Isbn: "978-0-545-01023-8", Status: entity.BookImportStatusRejected, Reason: response.ErrInvalidTitle.Message},
+					{Row: 6, Isbn: "978-0-545-01023-8", Status: entity.BookImportStatusRejected, Reason: response.ErrInvalidPrice.Message},
+					{Row: 7, Isbn: "9780545010238", Status: entity.BookImportStatusUpdated},
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("UpsertBook", mock.Anything, mock.Anything, mock.Anything).Return(tc.rPreviousPrice, tc.rPreviousPrice == 0, tc.rUpsertErr)
+
+			auditRecorder := &testmock.AuditRecorder{}
+			auditRecorder.On("Record", mock.Anything, mock.Anything, mock.Anything).Return(tc.rRecordErr)
+
+			uc := usecase.NewBookUsecase(newLogger(), dbTransactionRepo, bookRepo, &testmock.BlobStore{}, auditRecorder)
+			report, err := uc.ImportBooks(tc.ctx, tc.format, strings.NewReader(tc.feed))
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				assert.Equal(t, tc.expected, report)
+			}
+			auditRecorder.AssertNumberOfCalls(t, "Record", tc.expectedEvents)
+		})
+	}
+}
+
+func TestImportBooksFailedBatch(t *testing.T) {
+	// 150 books are written in a batch of 100 and a batch of 50, the second batch fails
+	var feed strings.Builder
+	feed.WriteString("isbn,title,price\n")
+	isbns := make([]string, 150)
+	for i := range isbns {
+		isbns[i] = isbn13(i)
+		fmt.Fprintf(&feed, "%s,Book %d,25000\n", isbns[i], i)
+	}
+
+	testcases := []struct {
+		name       string
+		rUpsertErr error
+		wantErr    bool
+	}{
+		{
+			name:       "book refused by the database",
+			rUpsertErr: &pq.Error{Code: "22003"},
+			wantErr:    false,
+		},
+		{
+			name:       "connection lost",
+			rUpsertErr: driver.ErrBadConn,
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, nil)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("UpsertBook", mock.Anything, mock.Anything, mock.Anything).Return(0, true, nil).Times(100)
+			bookRepo.On("UpsertBook", mock.Anything, mock.Anything, mock.Anything).Return(0, false, tc.rUpsertErr)
+
+			l := newLogger()
+
+			uc := usecase.NewBookUsecase(l, dbTransactionRepo, bookRepo, &testmock.BlobStore{}, newAuditRecorder())
+			report, err := uc.ImportBooks(context.Background(), "csv", strings.NewReader(feed.String()))
+			assert.Equal(t, tc.wantErr, err != nil)
+			dbTransactionRepo.AssertNumberOfCalls(t, "CommitTransactionQuery", 1)
+			dbTransactionRepo.AssertNumberOfCalls(t, "RollbackTransactionQuery", 1)
+
+			if tc.wantErr {
+				assert.Nil(t, report)
+				l.AssertNotCalled(t, "Error", mock.Anything, mock.Anything)
+				return
+			}
+
+			l.AssertNumberOfCalls(t, "Error", 1)
+			assert.Equal(t, 100, report.Created)
+			assert.Equal(t, 50, report.Rejected)
+			assert.Len(t, report.Rows, 150)
+			for i, row := range report.Rows {
+				assert.Equal(t, i+2, row.Row)
+				assert.Equal(t, isbns[i], row.Isbn)
+				if i < 100 {
+					assert.Equal(t, entity.BookImportStatusCreated, row.Status)
+					continue
+				}
+
+				assert.Equal(t, entity.BookImportStatusRejected, row.Status)
+				assert.Equal(t, "Batch of the row could not be written, import the row again", row.Reason)
+			}
+		})
+	}
+}
+
+// isbn13 returns the n-th valid ISBN-13 of the 978-0-000 range
+func isbn13(n int) string {
+	digits := fmt.Sprintf("978000%06d", n)
+	sum := 0
+	for i, d := range digits {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(d-'0') * weight
+	}
+
+	return fmt.Sprintf("%s%d", digits, (10-sum%10)%10)
+}
+
+func newLogger() *testmock.LoggerInterface {
+	l := &testmock.LoggerInterface{}
+	l.On("WithContext", mock.Anything).Return(l)
+	l.On("Error", mock.Anything, mock.Anything)
+
+	return l
+}
+
+func TestUploadBookCover(t *testing.T) {
+	cover := &bytes.Buffer{}
+	png.Encode(cover, image.NewRGBA(image.Rect(0, 0, 400, 600)))
+
+	hugeCover := &bytes.Buffer{}
+	png.Encode(hugeCover, image.NewGray(image.Rect(0, 0, 7000, 1)))
+
+	testcases := []struct {
+		name         string
+		ctx          context.Context
+		file         []byte
+		rBookRes     *entity.Book
+		rBookErr     error
+		rPutErr      error
+		rUpdateErr   error
+		expectedErr  error
+		expectedURLs []string
+		wantErr      bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:        "record not found",
+			ctx:         context.Background(),
+			rBookErr:    response.ErrNotFound,
+			expectedErr: response.ErrNotFound,
+			wantErr:     true,
+		},
+		{
+			name:     "failed to get book",
+			ctx:      context.Background(),
+			rBookErr: errors.New("error get book"),
+			wantErr:  true,
+		},
+		{
+			name:        "empty file",
+			ctx:         context.Background(),
+			rBookRes:    &entity.Book{ID: 1},
+			expectedErr: response.ErrInvalidImage,
+			wantErr:     true,
+		},
+		{
+			name:        "unsupported image type",
+			ctx:         context.Background(),
+			file:        []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"),
+			rBookRes:    &entity.Book{ID: 1},
+			expectedErr: response.ErrUnsupportedImageType,
+			wantErr:     true,
+		},
+		{
+			name:        "image too large",
+			ctx:         context.Background(),
+			file:        hugeCover.Bytes(),
+			rBookRes:    &entity.Book{ID: 1},
+			expectedErr: response.ErrInvalidImage,
+			wantErr:     true,
+		},
+		{
+			name:        "corrupted image",
+			ctx:         context.Background(),
+			file:        cover.Bytes()[:100],
+			rBookRes:    &entity.Book{ID: 1},
+			expectedErr: response.ErrInvalidImage,
+			wantErr:     true,
+		},
+		{
+			name:     "failed to store cover",
+			ctx:      context.Background(),
+			file:     cover.Bytes(),
+			rBookRes: &entity.Book{ID: 1},
+			rPutErr:  errors.New("error put"),
+			wantErr:  true,
+		},
+		{
+			name:       "failed to update cover key",
+			ctx:        context.Background(),
+			file:       cover.Bytes(),
+			rBookRes:   &entity.Book{ID: 1},
+			rUpdateErr: errors.New("error update cover key"),
+			wantErr:    true,
+		},
+		{
+			name:         "success",
+			ctx:          context.Background(),
+			file:         cover.Bytes(),
+			rBookRes:     &entity.Book{ID: 1, CoverKey: "covers/1/1/original.jpg"},
+			expectedURLs: []string{"original", "small", "medium", "large"},
+			wantErr:      false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("GetBookByID", mock.Anything, 1).Return(tc.rBookRes, tc.rBookErr)
+			bookRepo.On("UpdateBookCoverKey", mock.Anything, 1, mock.Anything).Return(tc.rUpdateErr)
+
+			blobStore := &testmock.BlobStore{}
+			blobStore.On("Put", mock.Anything, mock.Anything, mock.Anything).Return(tc.rPutErr)
+			blobStore.On("DeletePrefix", mock.Anything, mock.Anything).Return(nil)
+			blobStore.On("URL", mock.Anything).Return(func(key string) string { return "http://localhost/" + key })
+
+			uc := usecase.NewBookUsecase(newLogger(), &testmock.PostgresTransactionRepositoryInterface{}, bookRepo, blobStore, newAuditRecorder())
+			book, err := uc.UploadBookCover(tc.ctx, 1, bytes.NewReader(tc.file))
+			assert.Equal(t, tc.wantErr, err != nil)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+
+			if !tc.wantErr {
+				assert.Regexp(t, "^covers/1/[0-9]+/original.png$", book.CoverKey)
+				assert.Len(t, book.CoverURLs, len(tc.expectedURLs))
+				for _, name := range tc.expectedURLs {
+					assert.Contains(t, book.CoverURLs, name)
+				}
+				assert.Equal(t, "http://localhost/"+book.CoverKey, book.CoverURLs["original"])
+				// The original and three thumbnails are stored and the previous cover is removed
+				blobStore.AssertNumberOfCalls(t, "Put", 4)
+				blobStore.AssertCalled(t, "DeletePrefix", mock.Anything, "covers/1/1")
+			}
+		})
+	}
+}
+
+func TestUploadBookCoverLocalBlobStore(t *testing.T) {
+	cover := &bytes.Buffer{}
+	png.Encode(cover, image.NewRGBA(image.Rect(0, 0, 800, 1200)))
+
+	bookRepo := &testmock.BookRepositoryInterface{}
+	bookRepo.On("GetBookByID", mock.Anything, 1).Return(&entity.Book{ID: 1}, nil)
+	bookRepo.On("UpdateBookCoverKey", mock.Anything, 1, mock.Anything).Return(nil)
+
+	blobStore := blobstore.NewLocalBlobStore(t.TempDir(), "http://localhost:9999/blobs")
+	uc := usecase.NewBookUsecase(newLogger(), &testmock.PostgresTransactionRepositoryInterface{}, bookRepo, blobStore, newAuditRecorder())
+	book, err := uc.UploadBookCover(context.Background(), 1, cover)
+	assert.Nil(t, err)
+
+	for name, width := range entity.BookCoverThumbnailWidths {
+		key := strings.TrimPrefix(book.CoverURLs[name], "http://localhost:9999/blobs/")
+		rc, err := blobStore.Get(context.Background(), key)
+		assert.Nil(t, err)
+
+		cfg, format, err := image.DecodeConfig(rc)
+		rc.Close()
+		assert.Nil(t, err)
+		assert.Equal(t, "jpeg", format)
+		assert.Equal(t, width, cfg.Width)
+	}
+}
+
+func TestRefreshBestsellers(t *testing.T) {
+	testcases := []struct {
+		name          string
+		ctx           context.Context
+		rStartTrxErr  error
+		rRefreshErr   error
+		rCommitTrxErr error
+		wantErr       bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          context.Background(),
+			rStartTrxErr: errors.New("error start transaction"),
+			wantErr:      true,
+		},
+		{
+			name:        "failed to refresh bestsellers",
+			ctx:         context.Background(),
+			rRefreshErr: errors.New("error refresh bestsellers"),
+			wantErr:     true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           context.Background(),
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantErr:       true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("RefreshBestsellers", mock.Anything, mock.Anything).Return(true, tc.rRefreshErr)
+
+			uc := usecase.NewBookUsecase(newLogger(), dbTransactionRepo, bookRepo, &testmock.BlobStore{}, newAuditRecorder())
+			err := uc.RefreshBestsellers(tc.ctx)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestGetBestsellers(t *testing.T) {
+	testcases := []struct {
+		name                    string
+		ctx                     context.Context
+		payload                 entity.GetBestsellersPayload
+		rGetBestsellersRes      []*entity.Bestseller
+		rGetBestsellersErr      error
+		rGetBestsellersCountErr error
+		rGetBooksErr            error
+		expectedRanks           []int
+		expectedErr             error
+		wantErr                 bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:        "invalid period",
+			ctx:         context.Background(),
+			payload:     entity.GetBestsellersPayload{Period: "year"},
+			expectedErr: response.ErrInvalidBestsellerPeriod,
+			wantErr:     true,
+		},
+		{
+			name:               "failed to get bestsellers",
+			ctx:                context.Background(),
+			rGetBestsellersErr: errors.New("error get bestsellers"),
+			wantErr:            true,
+		},
+		{
+			name:                    "failed to get bestsellers count",
+			ctx:                     context.Background(),
+			rGetBestsellersCountErr: errors.New("error get bestsellers count"),
+			wantErr:                 true,
+		},
+		{
+			name:               "failed to get books",
+			ctx:                context.Background(),
+			rGetBestsellersRes: []*entity.Bestseller{{BookID: 8, UnitsSold: 5}, {BookID: 7, UnitsSold: 3}},
+			rGetBooksErr:       errors.New("error get books"),
+			wantErr:            true,
+		},
+		{
+			name:               "success without bestsellers",
+			ctx:                context.Background(),
+			rGetBestsellersRes: []*entity.Bestseller{},
+			expectedRanks:      []int{},
+			wantErr:            false,
+		},
+		{
+			name:               "success",
+			ctx:                context.Background(),
+			payload:            entity.GetBestsellersPayload{Offset: 10},
+			rGetBestsellersRes: []*entity.Bestseller{{BookID: 8, UnitsSold: 5}, {BookID: 7, UnitsSold: 3}},
+			expectedRanks:      []int{11, 12},
+			wantErr:            false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("GetBestsellers", mock.Anything, mock.Anything).Return(tc.rGetBestsellersRes, tc.rGetBestsellersErr)
+			bookRepo.On("GetBestsellersCount", mock.Anything, mock.Anything).Return(len(tc.rGetBestsellersRes), tc.rGetBestsellersCountErr)
+			bookRepo.On("GetBooksByIDs", mock.Anything, []int{8, 7}).Return([]*entity.Book{{ID: 7}, {ID: 8}}, tc.rGetBooksErr)
+
+			uc := usecase.NewBookUsecase(newLogger(), &testmock.PostgresTransactionRepositoryInterface{}, bookRepo, &testmock.BlobStore{}, newAuditRecorder())
+			bestsellers, _, err := uc.GetBestsellers(tc.ctx, tc.payload)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+			if !tc.wantErr {
+				ranks := []int{}
+				for _, bestseller := range bestsellers {
+					ranks = append(ranks, bestseller.Rank)
+					assert.Equal(t, bestseller.BookID, bestseller.Book.ID)
+				}
+				assert.Equal(t, tc.expectedRanks, ranks)
+			}
+		})
+	}
+}
+
+func TestGetBookByIsbnSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.Install(sdktrace.NewSimpleSpanProcessor(exporter), "book-store", 1)
+	defer provider.Shutdown(context.Background())
+
+	ctx, parent := tracing.Start(context.Background(), "parent")
+
+	var repoSpanID trace.SpanID
+	bookRepo := &testmock.BookRepositoryInterface{}
+	bookRepo.On("GetBookByIsbn", mock.Anything, "9780545010221").Run(func(args mock.Arguments) {
+		repoSpanID = trace.SpanContextFromContext(args.Get(0).(context.Context)).SpanID()
+	}).Return(&entity.Book{}, nil)
+
+	uc := usecase.NewBookUsecase(newLogger(), &testmock.PostgresTransactionRepositoryInterface{}, bookRepo, &testmock.BlobStore{}, newAuditRecorder())
+	_, err := uc.GetBookByIsbn(ctx, "978-0-545-01022-1")
+	parent.End()
+	assert.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "BookUsecase.GetBookByIsbn", spans[0].Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, spans[0].SpanContext.SpanID(), repoSpanID)
+}
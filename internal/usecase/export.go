@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	repo "github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/pkg/exportfile"
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
+)
+
+var (
+	// BookExportColumns list the columns of the books export
+	BookExportColumns = []string{"id", "isbn", "title", "price", "created_at", "updated_at"}
+	// OrderExportColumns list the columns of the orders export
+	OrderExportColumns = []string{"id", "user_id", "fee", "total_price", "created_at", "updated_at"}
+)
+
+// ExportUsecaseInterface define contract for export related functions to usecase
+type ExportUsecaseInterface interface {
+	GetBooksCount(ctx context.Context, payload entity.GetBooksPayload) (int, error)
+	ExportBooks(ctx context.Context, payload entity.GetBooksPayload, format string, w io.Writer) error
+	GetOrdersCount(ctx context.Context, userID int) (int, error)
+	ExportOrders(ctx context.Context, userID int, format string, w io.Writer) error
+}
+
+type ExportUsecase struct {
+	bookRepo  repo.BookRepositoryInterface
+	orderRepo repo.OrderRepositoryInterface
+	auditor   AuditRecorder
+}
+
+func NewExportUsecase(br repo.BookRepositoryInterface, or repo.OrderRepositoryInterface, ar AuditRecorder) *ExportUsecase {
+	return &ExportUsecase{
+		bookRepo:  br,
+		orderRepo: or,
+		auditor:   ar,
+	}
+}
+
+func (uc *ExportUsecase) GetBooksCount(ctx context.Context, payload entity.GetBooksPayload) (int, error) {
+	functionName := "ExportUsecase.GetBooksCount"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return 0, errors.Wrap(err, functionName)
+	}
+
+	count, err := uc.bookRepo.GetBooksCount(ctx, payload)
+	if err != nil {
+		return 0, errors.Wrap(fmt.Errorf("uc.bookRepo.GetBooksCount: %w", err), functionName)
+	}
+
+	return count, nil
+}
+
+// ExportBooks streams every book matching the payload filters into w, pagination is ignored.
+// The export is audited before anything is streamed, so a dump is never taken unrecorded
+func (uc *ExportUsecase) ExportBooks(ctx context.Context, payload entity.GetBooksPayload, format string, w io.Writer) error {
+	functionName := "ExportUsecase.ExportBooks"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	writer, err := exportfile.NewWriter(format, w)
+	if err != nil {
+		return response.ErrUnsupportedFileFormat
+	}
+
+	event := entity.NewAuditEvent(entity.AuditActionBooksExported, entity.AuditTargetBook, "", entity.AuditDiff{
+		"format":        {To: format},
+		"title_keyword": {To: payload.TitleKeyword},
+	})
+	if err := uc.auditor.Record(ctx, nil, event); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.auditor.Record: %w", err), functionName)
+	}
+
+	if err := writer.WriteHeader(BookExportColumns); err != nil {
+		return errors.Wrap(fmt.Errorf("writer.WriteHeader: %w", err), functionName)
+	}
+
+	err = uc.bookRepo.StreamBooks(ctx, payload, func(book *entity.Book) error {
+		return writer.WriteRow([]interface{}{book.ID, book.Isbn, book.Title, book.Price, book.CreatedAt, book.UpdatedAt})
+	})
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.bookRepo.StreamBooks: %w", err), functionName)
+	}
+
+	if err := writer.Close(); err != nil {
+		return errors.Wrap(fmt.Errorf("writer.Close: %w", err), functionName)
+	}
+
+	return nil
+}
+
+func (uc *ExportUsecase) GetOrdersCount(ctx context.Context, userID int) (int, error) {
+	functionName := "ExportUsecase.GetOrdersCount"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return 0, errors.Wrap(err, functionName)
+	}
+
+	count, err := uc.orderRepo.GetOrdersCount(ctx, userID)
+	if err != nil {
+		return 0, errors.Wrap(fmt.Errorf("uc.orderRepo.GetOrdersCount: %w", err), functionName)
+	}
+
+	return count, nil
+}
+
+// ExportOrders streams the orders of the user into w, a zero user ID exports the orders of every user.
+// The export is audited before anything is streamed, so a dump is never taken unrecorded
+func (uc *ExportUsecase) ExportOrders(ctx context.Context, userID int, format string, w io.Writer) error {
+	functionName := "ExportUsecase.ExportOrders"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	writer, err := exportfile.NewWriter(format, w)
+	if err != nil {
+		return response.ErrUnsupportedFileFormat
+	}
+
+	event := entity.NewAuditEvent(entity.AuditActionOrdersExported, entity.AuditTargetOrder, "", entity.AuditDiff{
+		"format":  {To: format},
+		"user_id": {To: userID},
+	})
+	if err := uc.auditor.Record(ctx, nil, event); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.auditor.Record: %w", err), functionName)
+	}
+
+	if err := writer.WriteHeader(OrderExportColumns); err != nil {
+		return errors.Wrap(fmt.Errorf("writer.WriteHeader: %w", err), functionName)
+	}
+
+	err = uc.orderRepo.StreamOrders(ctx, userID, func(order *entity.Order) error {
+		return writer.WriteRow([]interface{}{order.ID, order.UserID, order.Fee, order.TotalPrice, order.CreatedAt, order.UpdatedAt})
+	})
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.orderRepo.StreamOrders: %w", err), functionName)
+	}
+
+	if err := writer.Close(); err != nil {
+		return errors.Wrap(fmt.Errorf("writer.Close: %w", err), functionName)
+	}
+
+	return nil
+}
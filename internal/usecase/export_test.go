@@ -0,0 +1,237 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExportUsecaseGetBooksCount(t *testing.T) {
+	testcases := []struct {
+		name              string
+		ctx               context.Context
+		rGetBooksCountErr error
+		wantErr           bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:              "failed to get books count",
+			ctx:               context.Background(),
+			rGetBooksCountErr: errors.New("error get books count"),
+			wantErr:           true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("GetBooksCount", mock.Anything, mock.Anything).Return(1, tc.rGetBooksCountErr)
+
+			uc := usecase.NewExportUsecase(bookRepo, &testmock.OrderRepositoryInterface{}, newAuditRecorder())
+			_, err := uc.GetBooksCount(tc.ctx, entity.GetBooksPayload{})
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestExportBooks(t *testing.T) {
+	testcases := []struct {
+		name       string
+		ctx        context.Context
+		format     string
+		rRecordErr error
+		rStreamErr error
+		expected   string
+		wantErr    bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "unsupported format",
+			ctx:     context.Background(),
+			format:  "pdf",
+			wantErr: true,
+		},
+		{
+			name:       "failed to record the export",
+			ctx:        context.Background(),
+			format:     "csv",
+			rRecordErr: errors.New("error record"),
+			wantErr:    true,
+		},
+		{
+			name:       "failed to stream books",
+			ctx:        context.Background(),
+			format:     "csv",
+			rStreamErr: errors.New("error stream books"),
+			wantErr:    true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			format:   "csv",
+			expected: "id,isbn,title,price,created_at,updated_at\n1,9780545010221,Harry Potter,25000,0001-01-01T00:00:00Z,0001-01-01T00:00:00Z\n",
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("StreamBooks", mock.Anything, mock.Anything, mock.Anything).Return(func(ctx context.Context, payload entity.GetBooksPayload, fn func(*entity.Book) error) error {
+				if tc.rStreamErr != nil {
+					return tc.rStreamErr
+				}
+
+				return fn(&entity.Book{ID: 1, Isbn: "9780545010221", Title: "Harry Potter", Price: 25000})
+			})
+
+			auditRecorder := &testmock.AuditRecorder{}
+			auditRecorder.On("Record", mock.Anything, nil, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+				return event.Action == entity.AuditActionBooksExported
+			})).Return(tc.rRecordErr)
+
+			buf := &bytes.Buffer{}
+			uc := usecase.NewExportUsecase(bookRepo, &testmock.OrderRepositoryInterface{}, auditRecorder)
+			err := uc.ExportBooks(tc.ctx, entity.GetBooksPayload{}, tc.format, buf)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				assert.Equal(t, tc.expected, buf.String())
+			}
+			if tc.rRecordErr != nil {
+				assert.Empty(t, buf.String())
+			}
+		})
+	}
+}
+
+func TestExportUsecaseGetOrdersCount(t *testing.T) {
+	testcases := []struct {
+		name               string
+		ctx                context.Context
+		rGetOrdersCountErr error
+		wantErr            bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:               "failed to get orders count",
+			ctx:                context.Background(),
+			rGetOrdersCountErr: errors.New("error get orders count"),
+			wantErr:            true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			orderRepo := &testmock.OrderRepositoryInterface{}
+			orderRepo.On("GetOrdersCount", mock.Anything, mock.Anything).Return(1, tc.rGetOrdersCountErr)
+
+			uc := usecase.NewExportUsecase(&testmock.BookRepositoryInterface{}, orderRepo, newAuditRecorder())
+			_, err := uc.GetOrdersCount(tc.ctx, 0)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestExportOrders(t *testing.T) {
+	testcases := []struct {
+		name       string
+		ctx        context.Context
+		format     string
+		rRecordErr error
+		rStreamErr error
+		expected   string
+		wantErr    bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "unsupported format",
+			ctx:     context.Background(),
+			format:  "pdf",
+			wantErr: true,
+		},
+		{
+			name:       "failed to record the export",
+			ctx:        context.Background(),
+			format:     "ndjson",
+			rRecordErr: errors.New("error record"),
+			wantErr:    true,
+		},
+		{
+			name:       "failed to stream orders",
+			ctx:        context.Background(),
+			format:     "ndjson",
+			rStreamErr: errors.New("error stream orders"),
+			wantErr:    true,
+		},
+		{
+			name:     "success",
+			ctx:      context.Background(),
+			format:   "ndjson",
+			expected: `{"id":1,"user_id":2,"fee":1000,"total_price":26000,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}` + "\n",
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			orderRepo := &testmock.OrderRepositoryInterface{}
+			orderRepo.On("StreamOrders", mock.Anything, mock.Anything, mock.Anything).Return(func(ctx context.Context, userID int, fn func(*entity.Order) error) error {
+				if tc.rStreamErr != nil {
+					return tc.rStreamErr
+				}
+
+				return fn(&entity.Order{ID: 1, UserID: 2, Fee: 1000, TotalPrice: 26000})
+			})
+
+			auditRecorder := &testmock.AuditRecorder{}
+			auditRecorder.On("Record", mock.Anything, nil, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+				return event.Action == entity.AuditActionOrdersExported
+			})).Return(tc.rRecordErr)
+
+			buf := &bytes.Buffer{}
+			uc := usecase.NewExportUsecase(&testmock.BookRepositoryInterface{}, orderRepo, auditRecorder)
+			err := uc.ExportOrders(tc.ctx, 0, tc.format, buf)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				assert.Equal(t, tc.expected, buf.String())
+			}
+			if tc.rRecordErr != nil {
+				assert.Empty(t, buf.String())
+			}
+		})
+	}
+}
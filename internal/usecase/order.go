@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
@@ -10,6 +11,8 @@ import (
 	"github.com/satriowisnugroho/book-store/internal/helper"
 	repo "github.com/satriowisnugroho/book-store/internal/repository/postgres"
 	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/pkg/metrics"
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
 )
 
 // OrderUsecaseInterface define contract for order related functions to usecase
@@ -19,30 +22,40 @@ type OrderUsecaseInterface interface {
 }
 
 type OrderUsecase struct {
-	dbTransactionRepo repo.PostgresTransactionRepositoryInterface
-	bookRepo          repo.BookRepositoryInterface
-	orderRepo         repo.OrderRepositoryInterface
-	orderItemRepo     repo.OrderItemRepositoryInterface
+	requireVerifiedEmail bool
+	dbTransactionRepo    repo.PostgresTransactionRepositoryInterface
+	bookRepo             repo.BookRepositoryInterface
+	orderRepo            repo.OrderRepositoryInterface
+	orderItemRepo        repo.OrderItemRepositoryInterface
+	userRepo             repo.UserRepositoryInterface
+	auditor              AuditRecorder
 }
 
 func NewOrderUsecase(
+	requireVerifiedEmail bool,
 	ptr repo.PostgresTransactionRepositoryInterface,
 	br repo.BookRepositoryInterface,
 	or repo.OrderRepositoryInterface,
 	oir repo.OrderItemRepositoryInterface,
+	ur repo.UserRepositoryInterface,
+	ar AuditRecorder,
 ) *OrderUsecase {
 	return &OrderUsecase{
-		dbTransactionRepo: ptr,
-		bookRepo:          br,
-		orderRepo:         or,
-		orderItemRepo:     oir,
+		requireVerifiedEmail: requireVerifiedEmail,
+		dbTransactionRepo:    ptr,
+		bookRepo:             br,
+		orderRepo:            or,
+		orderItemRepo:        oir,
+		userRepo:             ur,
+		auditor:              ar,
 	}
 }
 
 func (uc *OrderUsecase) CreateOrder(c *gin.Context, payload *entity.OrderPayload) (*entity.Order, error) {
 	functionName := "OrderUsecase.CreateOrder"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
 
-	ctx := c.Request.Context()
 	if err := helper.CheckDeadline(ctx); err != nil {
 		return nil, errors.Wrap(err, functionName)
 	}
@@ -54,6 +67,21 @@ func (uc *OrderUsecase) CreateOrder(c *gin.Context, payload *entity.OrderPayload
 		}
 	}
 
+	if uc.requireVerifiedEmail {
+		user, err := uc.userRepo.GetUserByID(ctx, helper.GetUserIDFromContext(c))
+		if err != nil {
+			if _, ok := err.(response.CustomError); ok {
+				return nil, err
+			}
+
+			return nil, errors.Wrap(fmt.Errorf("uc.userRepo.GetUserByID: %w", err), functionName)
+		}
+
+		if !user.IsVerified() {
+			return nil, response.ErrEmailNotVerified
+		}
+	}
+
 	// Begin transaction
 	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
 	if err != nil {
@@ -112,19 +140,39 @@ func (uc *OrderUsecase) CreateOrder(c *gin.Context, payload *entity.OrderPayload
 		return nil, errors.Wrap(fmt.Errorf("uc.orderRepo.UpdateOrder: %w", err), functionName)
 	}
 
+	items := make([]map[string]int, 0, len(order.OrderItems))
+	for _, orderItem := range order.OrderItems {
+		items = append(items, map[string]int{"book_id": orderItem.BookID, "quantity": orderItem.Quantity, "price": orderItem.Price})
+	}
+	event := entity.NewAuditEvent(entity.AuditActionOrderCreated, entity.AuditTargetOrder, strconv.Itoa(order.ID), entity.AuditDiff{
+		"items":       {To: items},
+		"fee":         {To: order.Fee},
+		"total_price": {To: order.TotalPrice},
+	})
+	if err := uc.auditor.Record(ctx, tx, event); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.auditor.Record: %w", err), functionName)
+	}
+
 	// Commit transaction
 	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
 		return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
 	}
 	rollbackProcess = false
 
+	metrics.OrdersCreatedTotal.Inc()
+	metrics.OrderRevenueTotal.Add(float64(order.TotalPrice))
+	for _, orderItem := range order.OrderItems {
+		metrics.BooksSoldTotal.Add(float64(orderItem.Quantity))
+	}
+
 	return order, nil
 }
 
 func (uc *OrderUsecase) GetOrdersByUserID(c *gin.Context, limit, offset int) ([]*entity.Order, int, error) {
 	functionName := "OrderUsecase.GetOrdersByUserID"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
 
-	ctx := c.Request.Context()
 	if err := helper.CheckDeadline(ctx); err != nil {
 		return nil, 0, errors.Wrap(err, functionName)
 	}
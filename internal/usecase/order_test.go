@@ -3,12 +3,15 @@ package usecase_test
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/satriowisnugroho/book-store/internal/entity"
 	"github.com/satriowisnugroho/book-store/internal/response"
 	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/metrics"
 	"github.com/satriowisnugroho/book-store/test/fixture"
 	testmock "github.com/satriowisnugroho/book-store/test/mock"
 	"github.com/stretchr/testify/assert"
@@ -16,18 +19,24 @@ import (
 )
 
 func TestCreateOrder(t *testing.T) {
+	verifiedAt := time.Now()
+
 	testcases := []struct {
-		name                string
-		ctx                 *gin.Context
-		payload             *entity.OrderPayload
-		rStartTrxErr        error
-		rCommitTrxErr       error
-		rBookRes            *entity.Book
-		rBookErr            error
-		rCreateOrderErr     error
-		rUpdateOrderErr     error
-		rCreateOrderItemErr error
-		wantErr             bool
+		name                 string
+		ctx                  *gin.Context
+		payload              *entity.OrderPayload
+		requireVerifiedEmail bool
+		rUserRes             *entity.User
+		rUserErr             error
+		rStartTrxErr         error
+		rCommitTrxErr        error
+		rBookRes             *entity.Book
+		rBookErr             error
+		rCreateOrderErr      error
+		rUpdateOrderErr      error
+		rCreateOrderItemErr  error
+		rRecordErr           error
+		wantErr              bool
 	}{
 		{
 			name:    "deadline context",
@@ -40,6 +49,31 @@ func TestCreateOrder(t *testing.T) {
 			payload: &entity.OrderPayload{OrderItems: []entity.OrderItemPayload{{Quantity: 0}}},
 			wantErr: true,
 		},
+		{
+			name:                 "failed to get user",
+			ctx:                  fixture.GinCtxBackground(),
+			payload:              &entity.OrderPayload{OrderItems: []entity.OrderItemPayload{{Quantity: 1}}},
+			requireVerifiedEmail: true,
+			rUserErr:             errors.New("error get user"),
+			wantErr:              true,
+		},
+		{
+			name:                 "email is not verified",
+			ctx:                  fixture.GinCtxBackground(),
+			payload:              &entity.OrderPayload{OrderItems: []entity.OrderItemPayload{{Quantity: 1}}},
+			requireVerifiedEmail: true,
+			rUserRes:             &entity.User{},
+			wantErr:              true,
+		},
+		{
+			name:                 "success with verified email",
+			ctx:                  fixture.GinCtxBackground(),
+			payload:              &entity.OrderPayload{OrderItems: []entity.OrderItemPayload{{Quantity: 1}}},
+			requireVerifiedEmail: true,
+			rUserRes:             &entity.User{VerifiedAt: &verifiedAt},
+			rBookRes:             &entity.Book{},
+			wantErr:              false,
+		},
 		{
 			name:         "failed to start transaction",
 			ctx:          fixture.GinCtxBackground(),
@@ -84,6 +118,14 @@ func TestCreateOrder(t *testing.T) {
 			rUpdateOrderErr: errors.New("error update order"),
 			wantErr:         true,
 		},
+		{
+			name:       "failed to record audit event",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.OrderPayload{OrderItems: []entity.OrderItemPayload{{Quantity: 1}}},
+			rBookRes:   &entity.Book{},
+			rRecordErr: errors.New("error record audit event"),
+			wantErr:    true,
+		},
 		{
 			name:          "failed to commit transaction",
 			ctx:           fixture.GinCtxBackground(),
@@ -95,8 +137,8 @@ func TestCreateOrder(t *testing.T) {
 		{
 			name:     "success",
 			ctx:      fixture.GinCtxBackground(),
-			payload:  &entity.OrderPayload{OrderItems: []entity.OrderItemPayload{{Quantity: 1}}},
-			rBookRes: &entity.Book{},
+			payload:  &entity.OrderPayload{OrderItems: []entity.OrderItemPayload{{Quantity: 2}}},
+			rBookRes: &entity.Book{Price: 10000},
 			wantErr:  false,
 		},
 	}
@@ -118,9 +160,32 @@ func TestCreateOrder(t *testing.T) {
 			orderItemRepo := &testmock.OrderItemRepositoryInterface{}
 			orderItemRepo.On("CreateOrderItem", mock.Anything, mock.Anything, mock.Anything).Return(tc.rCreateOrderItemErr)
 
-			uc := usecase.NewOrderUsecase(dbTransactionRepo, bookRepo, orderRepo, orderItemRepo)
-			_, err := uc.CreateOrder(tc.ctx, tc.payload)
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("GetUserByID", mock.Anything, mock.Anything).Return(tc.rUserRes, tc.rUserErr)
+
+			auditRecorder := &testmock.AuditRecorder{}
+			auditRecorder.On("Record", mock.Anything, mock.Anything, mock.Anything).Return(tc.rRecordErr)
+
+			ordersCreated := testutil.ToFloat64(metrics.OrdersCreatedTotal)
+			revenue := testutil.ToFloat64(metrics.OrderRevenueTotal)
+			booksSold := testutil.ToFloat64(metrics.BooksSoldTotal)
+
+			uc := usecase.NewOrderUsecase(tc.requireVerifiedEmail, dbTransactionRepo, bookRepo, orderRepo, orderItemRepo, userRepo, auditRecorder)
+			order, err := uc.CreateOrder(tc.ctx, tc.payload)
 			assert.Equal(t, tc.wantErr, err != nil)
+
+			// Only the committed orders are counted
+			if tc.wantErr {
+				assert.Equal(t, ordersCreated, testutil.ToFloat64(metrics.OrdersCreatedTotal))
+				assert.Equal(t, revenue, testutil.ToFloat64(metrics.OrderRevenueTotal))
+			} else {
+				assert.Equal(t, ordersCreated+1, testutil.ToFloat64(metrics.OrdersCreatedTotal))
+				assert.Equal(t, revenue+float64(order.TotalPrice), testutil.ToFloat64(metrics.OrderRevenueTotal))
+				for _, orderItem := range tc.payload.OrderItems {
+					booksSold += float64(orderItem.Quantity)
+				}
+				assert.Equal(t, booksSold, testutil.ToFloat64(metrics.BooksSoldTotal))
+			}
 		})
 	}
 }
@@ -192,7 +257,7 @@ func TestGetOrdersByUserID(t *testing.T) {
 			orderItemRepo := &testmock.OrderItemRepositoryInterface{}
 			orderItemRepo.On("GetOrderItemsByOrderID", mock.Anything, mock.Anything).Return(tc.rGetOrderItemsByOrderIDRes, tc.rGetOrderItemsByOrderIDErr)
 
-			uc := usecase.NewOrderUsecase(&testmock.PostgresTransactionRepositoryInterface{}, bookRepo, orderRepo, orderItemRepo)
+			uc := usecase.NewOrderUsecase(false, &testmock.PostgresTransactionRepositoryInterface{}, bookRepo, orderRepo, orderItemRepo, &testmock.UserRepositoryInterface{}, newAuditRecorder())
 			_, _, err := uc.GetOrdersByUserID(tc.ctx, 10, 0)
 			assert.Equal(t, tc.wantErr, err != nil)
 		})
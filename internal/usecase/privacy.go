@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	repo "github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
+)
+
+// PrivacyUsecaseInterface define contract for the data subject requests of a user to usecase
+type PrivacyUsecaseInterface interface {
+	ExportUserData(ctx context.Context, userID int, w io.Writer) error
+	EraseUser(ctx context.Context, userID int) error
+}
+
+type PrivacyUsecase struct {
+	dbTransactionRepo repo.PostgresTransactionRepositoryInterface
+	userRepo          repo.UserRepositoryInterface
+	orderRepo         repo.OrderRepositoryInterface
+	orderItemRepo     repo.OrderItemRepositoryInterface
+	reviewRepo        repo.ReviewRepositoryInterface
+	auditor           AuditRecorder
+}
+
+func NewPrivacyUsecase(
+	dbTransactionRepo repo.PostgresTransactionRepositoryInterface,
+	ur repo.UserRepositoryInterface,
+	or repo.OrderRepositoryInterface,
+	oir repo.OrderItemRepositoryInterface,
+	rr repo.ReviewRepositoryInterface,
+	ar AuditRecorder,
+) *PrivacyUsecase {
+	return &PrivacyUsecase{
+		dbTransactionRepo: dbTransactionRepo,
+		userRepo:          ur,
+		orderRepo:         or,
+		orderItemRepo:     oir,
+		reviewRepo:        rr,
+		auditor:           ar,
+	}
+}
+
+// ExportUserData writes a ZIP of JSON files holding the profile, the orders with their items and the reviews of the user.
+// Everything is read and the export is written to the audit log before the first byte is written, so a failure leaves w untouched
+func (uc *PrivacyUsecase) ExportUserData(ctx context.Context, userID int, w io.Writer) error {
+	functionName := "PrivacyUsecase.ExportUserData"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	// A zero user ID streams the orders of every user
+	if userID <= 0 {
+		return response.ErrNotFound
+	}
+
+	// A deleted account keeps its personal data until it is erased, so it can still be exported
+	user, err := uc.userRepo.GetUserByIDUnscoped(ctx, userID)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return err
+		}
+
+		return errors.Wrap(fmt.Errorf("uc.userRepo.GetUserByIDUnscoped: %w", err), functionName)
+	}
+
+	orders := make([]*entity.Order, 0)
+	err = uc.orderRepo.StreamOrders(ctx, userID, func(order *entity.Order) error {
+		orders = append(orders, order)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.orderRepo.StreamOrders: %w", err), functionName)
+	}
+
+	for _, order := range orders {
+		order.OrderItems, err = uc.orderItemRepo.GetOrderItemsByOrderID(ctx, order.ID)
+		if err != nil {
+			return errors.Wrap(fmt.Errorf("uc.orderItemRepo.GetOrderItemsByOrderID: %w", err), functionName)
+		}
+	}
+
+	reviews, err := uc.reviewRepo.GetReviewsByUserID(ctx, userID)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.reviewRepo.GetReviewsByUserID: %w", err), functionName)
+	}
+
+	if err := uc.auditor.Record(ctx, nil, entity.NewAuditEvent(entity.AuditActionUserDataExported, entity.AuditTargetUser, strconv.Itoa(userID), nil)); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.auditor.Record: %w", err), functionName)
+	}
+
+	archive := zip.NewWriter(w)
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", user},
+		{"orders.json", orders},
+		{"reviews.json", reviews},
+	}
+	for _, file := range files {
+		if err := writeJSONFile(archive, file.name, file.data); err != nil {
+			return errors.Wrap(err, functionName)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return errors.Wrap(fmt.Errorf("archive.Close: %w", err), functionName)
+	}
+
+	return nil
+}
+
+// EraseUser anonymizes the user and deletes the personal data kept about the user.
+// The orders are kept for accounting and the user can't login anymore
+func (uc *PrivacyUsecase) EraseUser(ctx context.Context, userID int) error {
+	functionName := "PrivacyUsecase.EraseUser"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	if err := uc.userRepo.EraseUser(ctx, tx, userID); err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return err
+		}
+
+		return errors.Wrap(fmt.Errorf("uc.userRepo.EraseUser: %w", err), functionName)
+	}
+
+	if err := uc.auditor.Record(ctx, tx, entity.NewAuditEvent(entity.AuditActionUserErased, entity.AuditTargetUser, strconv.Itoa(userID), nil)); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.auditor.Record: %w", err), functionName)
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	return nil
+}
+
+// writeJSONFile adds a file holding the indented JSON of data to the archive
+func writeJSONFile(archive *zip.Writer, name string, data interface{}) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("archive.Create: %w", err)
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("encoder.Encode: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package usecase_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExportUserData(t *testing.T) {
+	testcases := []struct {
+		name           string
+		ctx            context.Context
+		userID         int
+		rUserDeleted   bool
+		rUserErr       error
+		rStreamErr     error
+		rOrderItemsErr error
+		rReviewsErr    error
+		rRecordErr     error
+		wantErr        error
+		wantAnyErr     bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.CtxEnded(),
+			userID:     123,
+			wantAnyErr: true,
+		},
+		{
+			name:       "no user",
+			ctx:        context.Background(),
+			userID:     0,
+			wantErr:    response.ErrNotFound,
+			wantAnyErr: true,
+		},
+		{
+			name:       "user not found",
+			ctx:        context.Background(),
+			userID:     123,
+			rUserErr:   response.ErrNotFound,
+			wantErr:    response.ErrNotFound,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to get user",
+			ctx:        context.Background(),
+			userID:     123,
+			rUserErr:   errors.New("error get user"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to stream orders",
+			ctx:        context.Background(),
+			userID:     123,
+			rStreamErr: errors.New("error stream orders"),
+			wantAnyErr: true,
+		},
+		{
+			name:           "failed to get order items",
+			ctx:            context.Background(),
+			userID:         123,
+			rOrderItemsErr: errors.New("error get order items"),
+			wantAnyErr:     true,
+		},
+		{
+			name:        "failed to get reviews",
+			ctx:         context.Background(),
+			userID:      123,
+			rReviewsErr: errors.New("error get reviews"),
+			wantAnyErr:  true,
+		},
+		{
+			name:       "failed to record audit event",
+			ctx:        context.Background(),
+			userID:     123,
+			rRecordErr: errors.New("error record audit event"),
+			wantAnyErr: true,
+		},
+		{
+			name:   "success",
+			ctx:    context.Background(),
+			userID: 123,
+		},
+		{
+			name:         "success with a deleted user",
+			ctx:          context.Background(),
+			userID:       123,
+			rUserDeleted: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			user := &entity.User{ID: 123, Email: "foo@bar.com", Fullname: "Foo Bar"}
+			if tc.rUserDeleted {
+				deletedAt := time.Now()
+				user.DeletedAt = &deletedAt
+			}
+
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("GetUserByIDUnscoped", mock.Anything, 123).Return(user, tc.rUserErr)
+
+			orderRepo := &testmock.OrderRepositoryInterface{}
+			orderRepo.On("StreamOrders", mock.Anything, 123, mock.Anything).Return(func(ctx context.Context, userID int, fn func(*entity.Order) error) error {
+				if tc.rStreamErr != nil {
+					return tc.rStreamErr
+				}
+
+				return fn(&entity.Order{ID: 7, UserID: 123, TotalPrice: 25000})
+			})
+
+			orderItemRepo := &testmock.OrderItemRepositoryInterface{}
+			orderItemRepo.On("GetOrderItemsByOrderID", mock.Anything, 7).Return([]*entity.OrderItem{{ID: 1, OrderID: 7, BookID: 2, Quantity: 1}}, tc.rOrderItemsErr)
+
+			reviewRepo := &testmock.ReviewRepositoryInterface{}
+			reviewRepo.On("GetReviewsByUserID", mock.Anything, 123).Return([]*entity.Review{{ID: 3, UserID: 123, Rating: 5}}, tc.rReviewsErr)
+
+			auditRecorder := &testmock.AuditRecorder{}
+			auditRecorder.On("Record", mock.Anything, mock.Anything, mock.Anything).Return(tc.rRecordErr)
+
+			buf := &bytes.Buffer{}
+			uc := usecase.NewPrivacyUsecase(&testmock.PostgresTransactionRepositoryInterface{}, userRepo, orderRepo, orderItemRepo, reviewRepo, auditRecorder)
+			err := uc.ExportUserData(tc.ctx, tc.userID, buf)
+			assert.Equal(t, tc.wantAnyErr, err != nil)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if tc.wantAnyErr {
+				assert.Zero(t, buf.Len())
+				return
+			}
+
+			archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			assert.NoError(t, err)
+
+			files := map[string][]byte{}
+			for _, file := range archive.File {
+				reader, _ := file.Open()
+				files[file.Name], _ = io.ReadAll(reader)
+				reader.Close()
+			}
+
+			assert.Len(t, files, 3)
+
+			var profile entity.User
+			assert.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+			assert.Equal(t, "foo@bar.com", profile.Email)
+
+			var orders []*entity.Order
+			assert.NoError(t, json.Unmarshal(files["orders.json"], &orders))
+			assert.Len(t, orders, 1)
+			assert.Len(t, orders[0].OrderItems, 1)
+
+			var reviews []*entity.Review
+			assert.NoError(t, json.Unmarshal(files["reviews.json"], &reviews))
+			assert.Len(t, reviews, 1)
+		})
+	}
+}
+
+func TestEraseUser(t *testing.T) {
+	testcases := []struct {
+		name          string
+		ctx           context.Context
+		rStartTrxErr  error
+		rEraseErr     error
+		rRecordErr    error
+		rCommitTrxErr error
+		wantErr       error
+		wantAnyErr    bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.CtxEnded(),
+			wantAnyErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          context.Background(),
+			rStartTrxErr: errors.New("error start transaction"),
+			wantAnyErr:   true,
+		},
+		{
+			name:       "user not found",
+			ctx:        context.Background(),
+			rEraseErr:  response.ErrNotFound,
+			wantErr:    response.ErrNotFound,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to erase user",
+			ctx:        context.Background(),
+			rEraseErr:  errors.New("error erase user"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to record audit event",
+			ctx:        context.Background(),
+			rRecordErr: errors.New("error record audit event"),
+			wantAnyErr: true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           context.Background(),
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantAnyErr:    true,
+		},
+		{
+			name: "success",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("EraseUser", mock.Anything, mock.Anything, 123).Return(tc.rEraseErr)
+
+			auditRecorder := &testmock.AuditRecorder{}
+			auditRecorder.On("Record", mock.Anything, mock.Anything, mock.Anything).Return(tc.rRecordErr)
+
+			uc := usecase.NewPrivacyUsecase(dbTransactionRepo, userRepo, &testmock.OrderRepositoryInterface{}, &testmock.OrderItemRepositoryInterface{}, &testmock.ReviewRepositoryInterface{}, auditRecorder)
+			err := uc.EraseUser(tc.ctx, 123)
+			assert.Equal(t, tc.wantAnyErr, err != nil)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+		})
+	}
+}
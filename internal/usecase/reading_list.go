@@ -0,0 +1,584 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	repo "github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/pkg/blobstore"
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
+)
+
+// readingListShareSlugBytes is the number of random bytes of a share slug, enough to make slugs unguessable
+const readingListShareSlugBytes = 16
+
+// ReadingListUsecaseInterface define contract for wishlist and reading list related functions to usecase
+type ReadingListUsecaseInterface interface {
+	GetWishlist(c *gin.Context) (*entity.ReadingList, error)
+	AddWishlistBook(c *gin.Context, payload *entity.ReadingListBookPayload) (*entity.ReadingList, error)
+	RemoveWishlistBook(c *gin.Context, bookID int) (*entity.ReadingList, error)
+	GetWishlistOrderPayload(c *gin.Context) (*entity.OrderPayload, error)
+	CreateReadingList(c *gin.Context, payload *entity.ReadingListPayload) (*entity.ReadingList, error)
+	GetReadingLists(c *gin.Context, limit, offset int) ([]*entity.ReadingList, int, error)
+	GetPublicReadingLists(c *gin.Context, limit, offset int) ([]*entity.ReadingList, int, error)
+	GetReadingList(c *gin.Context, readingListID int) (*entity.ReadingList, error)
+	GetSharedReadingList(c *gin.Context, shareSlug string) (*entity.ReadingList, error)
+	UpdateReadingList(c *gin.Context, readingListID int, payload *entity.ReadingListPayload) (*entity.ReadingList, error)
+	DeleteReadingList(c *gin.Context, readingListID int) error
+	AddReadingListBook(c *gin.Context, readingListID int, payload *entity.ReadingListBookPayload) (*entity.ReadingList, error)
+	RemoveReadingListBook(c *gin.Context, readingListID, bookID int) (*entity.ReadingList, error)
+	GetReadingListOrderPayload(c *gin.Context, readingListID int) (*entity.OrderPayload, error)
+}
+
+type ReadingListUsecase struct {
+	bookRepo        repo.BookRepositoryInterface
+	readingListRepo repo.ReadingListRepositoryInterface
+	blobStore       blobstore.BlobStore
+}
+
+func NewReadingListUsecase(br repo.BookRepositoryInterface, rlr repo.ReadingListRepositoryInterface, bs blobstore.BlobStore) *ReadingListUsecase {
+	return &ReadingListUsecase{
+		bookRepo:        br,
+		readingListRepo: rlr,
+		blobStore:       bs,
+	}
+}
+
+// GetWishlist returns the wishlist of the logged in user with its books, the wishlist is created on first use
+func (uc *ReadingListUsecase) GetWishlist(c *gin.Context) (*entity.ReadingList, error) {
+	functionName := "ReadingListUsecase.GetWishlist"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	wishlist, err := uc.getWishlist(ctx, c)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := uc.resolveBooks(ctx, wishlist); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return wishlist, nil
+}
+
+func (uc *ReadingListUsecase) AddWishlistBook(c *gin.Context, payload *entity.ReadingListBookPayload) (*entity.ReadingList, error) {
+	functionName := "ReadingListUsecase.AddWishlistBook"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	wishlist, err := uc.getWishlist(ctx, c)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := uc.addBook(ctx, wishlist, payload.BookID); err != nil {
+		if err == response.ErrNotFound {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return wishlist, nil
+}
+
+func (uc *ReadingListUsecase) RemoveWishlistBook(c *gin.Context, bookID int) (*entity.ReadingList, error) {
+	functionName := "ReadingListUsecase.RemoveWishlistBook"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	wishlist, err := uc.getWishlist(ctx, c)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := uc.removeBook(ctx, wishlist, bookID); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return wishlist, nil
+}
+
+// GetWishlistOrderPayload builds the order payload of the wishlist, the wishlist is kept as is
+// so it still holds the books when the order is not placed
+func (uc *ReadingListUsecase) GetWishlistOrderPayload(c *gin.Context) (*entity.OrderPayload, error) {
+	functionName := "ReadingListUsecase.GetWishlistOrderPayload"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	wishlist, err := uc.getWishlist(ctx, c)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	payload, err := uc.orderPayload(ctx, wishlist)
+	if err != nil {
+		if err == response.ErrEmptyReadingList {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return payload, nil
+}
+
+// CreateReadingList creates a named reading list of the logged in user,
+// a share slug is generated when the list is made public
+func (uc *ReadingListUsecase) CreateReadingList(c *gin.Context, payload *entity.ReadingListPayload) (*entity.ReadingList, error) {
+	functionName := "ReadingListUsecase.CreateReadingList"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	readingList := &entity.ReadingList{
+		UserID: helper.GetUserIDFromContext(c),
+		Books:  []*entity.Book{},
+	}
+	if err := applyReadingListPayload(readingList, payload); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := uc.readingListRepo.CreateReadingList(ctx, readingList); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.readingListRepo.CreateReadingList: %w", err), functionName)
+	}
+
+	return readingList, nil
+}
+
+// GetReadingLists returns the reading lists of the logged in user with their books, the wishlist is not included
+func (uc *ReadingListUsecase) GetReadingLists(c *gin.Context, limit, offset int) ([]*entity.ReadingList, int, error) {
+	functionName := "ReadingListUsecase.GetReadingLists"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, 0, errors.Wrap(err, functionName)
+	}
+
+	userID := helper.GetUserIDFromContext(c)
+	readingLists, err := uc.readingListRepo.GetReadingListsByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(fmt.Errorf("uc.readingListRepo.GetReadingListsByUserID: %w", err), functionName)
+	}
+
+	count, err := uc.readingListRepo.GetReadingListsByUserIDCount(ctx, userID)
+	if err != nil {
+		return nil, 0, errors.Wrap(fmt.Errorf("uc.readingListRepo.GetReadingListsByUserIDCount: %w", err), functionName)
+	}
+
+	if err := uc.resolveBooks(ctx, readingLists...); err != nil {
+		return nil, 0, errors.Wrap(err, functionName)
+	}
+
+	return readingLists, count, nil
+}
+
+// GetPublicReadingLists returns the public reading lists of every user with their books
+func (uc *ReadingListUsecase) GetPublicReadingLists(c *gin.Context, limit, offset int) ([]*entity.ReadingList, int, error) {
+	functionName := "ReadingListUsecase.GetPublicReadingLists"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, 0, errors.Wrap(err, functionName)
+	}
+
+	readingLists, err := uc.readingListRepo.GetPublicReadingLists(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(fmt.Errorf("uc.readingListRepo.GetPublicReadingLists: %w", err), functionName)
+	}
+
+	count, err := uc.readingListRepo.GetPublicReadingListsCount(ctx)
+	if err != nil {
+		return nil, 0, errors.Wrap(fmt.Errorf("uc.readingListRepo.GetPublicReadingListsCount: %w", err), functionName)
+	}
+
+	if err := uc.resolveBooks(ctx, readingLists...); err != nil {
+		return nil, 0, errors.Wrap(err, functionName)
+	}
+
+	return readingLists, count, nil
+}
+
+// GetReadingList returns a reading list of the logged in user with its books
+func (uc *ReadingListUsecase) GetReadingList(c *gin.Context, readingListID int) (*entity.ReadingList, error) {
+	functionName := "ReadingListUsecase.GetReadingList"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	readingList, err := uc.getOwnReadingList(ctx, c, readingListID)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := uc.resolveBooks(ctx, readingList); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return readingList, nil
+}
+
+// GetSharedReadingList returns a public reading list by its share slug with its books
+func (uc *ReadingListUsecase) GetSharedReadingList(c *gin.Context, shareSlug string) (*entity.ReadingList, error) {
+	functionName := "ReadingListUsecase.GetSharedReadingList"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	readingList, err := uc.readingListRepo.GetReadingListByShareSlug(ctx, shareSlug)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.readingListRepo.GetReadingListByShareSlug: %w", err), functionName)
+	}
+
+	if err := uc.resolveBooks(ctx, readingList); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return readingList, nil
+}
+
+// UpdateReadingList renames a reading list of the logged in user and changes its visibility,
+// making a list private revokes its share slug so links shared before stop working
+func (uc *ReadingListUsecase) UpdateReadingList(c *gin.Context, readingListID int, payload *entity.ReadingListPayload) (*entity.ReadingList, error) {
+	functionName := "ReadingListUsecase.UpdateReadingList"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	readingList, err := uc.getOwnReadingList(ctx, c, readingListID)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := applyReadingListPayload(readingList, payload); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := uc.readingListRepo.UpdateReadingList(ctx, readingList); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.readingListRepo.UpdateReadingList: %w", err), functionName)
+	}
+
+	if err := uc.resolveBooks(ctx, readingList); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return readingList, nil
+}
+
+func (uc *ReadingListUsecase) DeleteReadingList(c *gin.Context, readingListID int) error {
+	functionName := "ReadingListUsecase.DeleteReadingList"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	readingList, err := uc.getOwnReadingList(ctx, c, readingListID)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return err
+		}
+
+		return errors.Wrap(err, functionName)
+	}
+
+	if err := uc.readingListRepo.DeleteReadingList(ctx, readingList.ID); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.readingListRepo.DeleteReadingList: %w", err), functionName)
+	}
+
+	return nil
+}
+
+func (uc *ReadingListUsecase) AddReadingListBook(c *gin.Context, readingListID int, payload *entity.ReadingListBookPayload) (*entity.ReadingList, error) {
+	functionName := "ReadingListUsecase.AddReadingListBook"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	readingList, err := uc.getOwnReadingList(ctx, c, readingListID)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := uc.addBook(ctx, readingList, payload.BookID); err != nil {
+		if err == response.ErrNotFound {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return readingList, nil
+}
+
+func (uc *ReadingListUsecase) RemoveReadingListBook(c *gin.Context, readingListID, bookID int) (*entity.ReadingList, error) {
+	functionName := "ReadingListUsecase.RemoveReadingListBook"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	readingList, err := uc.getOwnReadingList(ctx, c, readingListID)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := uc.removeBook(ctx, readingList, bookID); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return readingList, nil
+}
+
+// GetReadingListOrderPayload builds the order payload of a reading list of the logged in user
+func (uc *ReadingListUsecase) GetReadingListOrderPayload(c *gin.Context, readingListID int) (*entity.OrderPayload, error) {
+	functionName := "ReadingListUsecase.GetReadingListOrderPayload"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	readingList, err := uc.getOwnReadingList(ctx, c, readingListID)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	payload, err := uc.orderPayload(ctx, readingList)
+	if err != nil {
+		if err == response.ErrEmptyReadingList {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return payload, nil
+}
+
+func (uc *ReadingListUsecase) getWishlist(ctx context.Context, c *gin.Context) (*entity.ReadingList, error) {
+	wishlist, err := uc.readingListRepo.GetOrCreateWishlist(ctx, helper.GetUserIDFromContext(c))
+	if err != nil {
+		return nil, fmt.Errorf("uc.readingListRepo.GetOrCreateWishlist: %w", err)
+	}
+
+	return wishlist, nil
+}
+
+// getOwnReadingList returns a named reading list of the logged in user, the lists of other users
+// and the wishlist are reported as not found
+func (uc *ReadingListUsecase) getOwnReadingList(ctx context.Context, c *gin.Context, readingListID int) (*entity.ReadingList, error) {
+	readingList, err := uc.readingListRepo.GetReadingListByID(ctx, readingListID)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("uc.readingListRepo.GetReadingListByID: %w", err)
+	}
+
+	if readingList.UserID != helper.GetUserIDFromContext(c) || readingList.IsWishlist {
+		return nil, response.ErrNotFound
+	}
+
+	return readingList, nil
+}
+
+func (uc *ReadingListUsecase) addBook(ctx context.Context, readingList *entity.ReadingList, bookID int) error {
+	if _, err := uc.bookRepo.GetBookByID(ctx, bookID); err != nil {
+		if err == response.ErrNotFound {
+			return err
+		}
+
+		return fmt.Errorf("uc.bookRepo.GetBookByID: %w", err)
+	}
+
+	if err := uc.readingListRepo.AddReadingListBook(ctx, readingList.ID, bookID); err != nil {
+		return fmt.Errorf("uc.readingListRepo.AddReadingListBook: %w", err)
+	}
+
+	return uc.resolveBooks(ctx, readingList)
+}
+
+func (uc *ReadingListUsecase) removeBook(ctx context.Context, readingList *entity.ReadingList, bookID int) error {
+	if err := uc.readingListRepo.RemoveReadingListBook(ctx, readingList.ID, bookID); err != nil {
+		return fmt.Errorf("uc.readingListRepo.RemoveReadingListBook: %w", err)
+	}
+
+	return uc.resolveBooks(ctx, readingList)
+}
+
+func (uc *ReadingListUsecase) orderPayload(ctx context.Context, readingList *entity.ReadingList) (*entity.OrderPayload, error) {
+	if err := uc.resolveBooks(ctx, readingList); err != nil {
+		return nil, err
+	}
+
+	if len(readingList.Books) == 0 {
+		return nil, response.ErrEmptyReadingList
+	}
+
+	return readingList.OrderPayload(), nil
+}
+
+// resolveBooks fills the books of the reading lists with two queries whatever the number of lists,
+// books removed from the catalog are skipped
+func (uc *ReadingListUsecase) resolveBooks(ctx context.Context, readingLists ...*entity.ReadingList) error {
+	if len(readingLists) == 0 {
+		return nil
+	}
+
+	readingListIDs := make([]int, 0, len(readingLists))
+	for _, readingList := range readingLists {
+		readingListIDs = append(readingListIDs, readingList.ID)
+	}
+
+	bookIDsByReadingList, err := uc.readingListRepo.GetReadingListBookIDs(ctx, readingListIDs)
+	if err != nil {
+		return fmt.Errorf("uc.readingListRepo.GetReadingListBookIDs: %w", err)
+	}
+
+	bookIDs := []int{}
+	seenBookIDs := map[int]bool{}
+	for _, ids := range bookIDsByReadingList {
+		for _, bookID := range ids {
+			if !seenBookIDs[bookID] {
+				seenBookIDs[bookID] = true
+				bookIDs = append(bookIDs, bookID)
+			}
+		}
+	}
+
+	booksByID := map[int]*entity.Book{}
+	if len(bookIDs) > 0 {
+		books, err := uc.bookRepo.GetBooksByIDs(ctx, bookIDs)
+		if err != nil {
+			return fmt.Errorf("uc.bookRepo.GetBooksByIDs: %w", err)
+		}
+
+		for _, book := range books {
+			setBookCoverURLs(uc.blobStore, book)
+			booksByID[book.ID] = book
+		}
+	}
+
+	for _, readingList := range readingLists {
+		readingList.Books = []*entity.Book{}
+		for _, bookID := range bookIDsByReadingList[readingList.ID] {
+			if book, ok := booksByID[bookID]; ok {
+				readingList.Books = append(readingList.Books, book)
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyReadingListPayload sets the name and visibility of the reading list, a public list keeps
+// its share slug and a private list has none
+func applyReadingListPayload(readingList *entity.ReadingList, payload *entity.ReadingListPayload) error {
+	readingList.Name = payload.Name
+	readingList.IsPublic = payload.IsPublic
+
+	if !readingList.IsPublic {
+		readingList.ShareSlug = ""
+
+		return nil
+	}
+
+	if readingList.ShareSlug == "" {
+		shareSlug, err := generateShareSlug()
+		if err != nil {
+			return fmt.Errorf("generateShareSlug: %w", err)
+		}
+
+		readingList.ShareSlug = shareSlug
+	}
+
+	return nil
+}
+
+func generateShareSlug() (string, error) {
+	b := make([]byte, readingListShareSlugBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
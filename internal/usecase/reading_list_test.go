@@ -0,0 +1,925 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func readingListGinCtx(userID int) *gin.Context {
+	c := fixture.GinCtxBackground()
+	c.Set("user_id", userID)
+
+	return c
+}
+
+func newReadingListBlobStore() *testmock.BlobStore {
+	blobStore := &testmock.BlobStore{}
+	blobStore.On("URL", mock.Anything).Return(func(key string) string { return "http://localhost/" + key })
+
+	return blobStore
+}
+
+func TestGetWishlist(t *testing.T) {
+	testcases := []struct {
+		name            string
+		ctx             *gin.Context
+		rWishlistErr    error
+		rBookIDsErr     error
+		rBooksErr       error
+		expectedBookIDs []int
+		wantErr         bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:         "failed to get wishlist",
+			ctx:          readingListGinCtx(2),
+			rWishlistErr: errors.New("error get wishlist"),
+			wantErr:      true,
+		},
+		{
+			name:        "failed to get book ids",
+			ctx:         readingListGinCtx(2),
+			rBookIDsErr: errors.New("error get book ids"),
+			wantErr:     true,
+		},
+		{
+			name:      "failed to get books",
+			ctx:       readingListGinCtx(2),
+			rBooksErr: errors.New("error get books"),
+			wantErr:   true,
+		},
+		{
+			name:            "success skip removed books",
+			ctx:             readingListGinCtx(2),
+			expectedBookIDs: []int{8, 7},
+			wantErr:         false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("GetBooksByIDs", mock.Anything, []int{8, 9, 7}).Return([]*entity.Book{{ID: 7, CoverKey: "covers/7/1/original.jpg"}, {ID: 8}}, tc.rBooksErr)
+
+			readingListRepo := &testmock.ReadingListRepositoryInterface{}
+			readingListRepo.On("GetOrCreateWishlist", mock.Anything, 2).Return(&entity.ReadingList{ID: 1, UserID: 2, IsWishlist: true}, tc.rWishlistErr)
+			readingListRepo.On("GetReadingListBookIDs", mock.Anything, []int{1}).Return(map[int][]int{1: {8, 9, 7}}, tc.rBookIDsErr)
+
+			uc := usecase.NewReadingListUsecase(bookRepo, readingListRepo, newReadingListBlobStore())
+			wishlist, err := uc.GetWishlist(tc.ctx)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				bookIDs := []int{}
+				for _, book := range wishlist.Books {
+					bookIDs = append(bookIDs, book.ID)
+				}
+				assert.Equal(t, tc.expectedBookIDs, bookIDs)
+				assert.Equal(t, "http://localhost/covers/7/1/original.jpg", wishlist.Books[1].CoverURLs[entity.BookCoverOriginal])
+			}
+		})
+	}
+}
+
+func TestAddWishlistBook(t *testing.T) {
+	testcases := []struct {
+		name         string
+		ctx          *gin.Context
+		rWishlistErr error
+		rBookErr     error
+		rAddErr      error
+		expectedErr  error
+		wantErr      bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:         "failed to get wishlist",
+			ctx:          readingListGinCtx(2),
+			rWishlistErr: errors.New("error get wishlist"),
+			wantErr:      true,
+		},
+		{
+			name:        "book is not found",
+			ctx:         readingListGinCtx(2),
+			rBookErr:    response.ErrNotFound,
+			expectedErr: response.ErrNotFound,
+			wantErr:     true,
+		},
+		{
+			name:     "failed to get book",
+			ctx:      readingListGinCtx(2),
+			rBookErr: errors.New("error get book"),
+			wantErr:  true,
+		},
+		{
+			name:    "failed to add book",
+			ctx:     readingListGinCtx(2),
+			rAddErr: errors.New("error add book"),
+			wantErr: true,
+		},
+		{
+			name:    "success",
+			ctx:     readingListGinCtx(2),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("GetBookByID", mock.Anything, 7).Return(&entity.Book{ID: 7}, tc.rBookErr)
+			bookRepo.On("GetBooksByIDs", mock.Anything, []int{7}).Return([]*entity.Book{{ID: 7}}, nil)
+
+			readingListRepo := &testmock.ReadingListRepositoryInterface{}
+			readingListRepo.On("GetOrCreateWishlist", mock.Anything, 2).Return(&entity.ReadingList{ID: 1, UserID: 2, IsWishlist: true}, tc.rWishlistErr)
+			readingListRepo.On("AddReadingListBook", mock.Anything, 1, 7).Return(tc.rAddErr)
+			readingListRepo.On("GetReadingListBookIDs", mock.Anything, []int{1}).Return(map[int][]int{1: {7}}, nil)
+
+			uc := usecase.NewReadingListUsecase(bookRepo, readingListRepo, &testmock.BlobStore{})
+			wishlist, err := uc.AddWishlistBook(tc.ctx, &entity.ReadingListBookPayload{BookID: 7})
+			assert.Equal(t, tc.wantErr, err != nil)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+			if !tc.wantErr {
+				assert.Len(t, wishlist.Books, 1)
+			}
+		})
+	}
+}
+
+func TestRemoveWishlistBook(t *testing.T) {
+	testcases := []struct {
+		name         string
+		ctx          *gin.Context
+		rWishlistErr error
+		rRemoveErr   error
+		wantErr      bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:         "failed to get wishlist",
+			ctx:          readingListGinCtx(2),
+			rWishlistErr: errors.New("error get wishlist"),
+			wantErr:      true,
+		},
+		{
+			name:       "failed to remove book",
+			ctx:        readingListGinCtx(2),
+			rRemoveErr: errors.New("error remove book"),
+			wantErr:    true,
+		},
+		{
+			name:    "success",
+			ctx:     readingListGinCtx(2),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			readingListRepo := &testmock.ReadingListRepositoryInterface{}
+			readingListRepo.On("GetOrCreateWishlist", mock.Anything, 2).Return(&entity.ReadingList{ID: 1, UserID: 2, IsWishlist: true}, tc.rWishlistErr)
+			readingListRepo.On("RemoveReadingListBook", mock.Anything, 1, 7).Return(tc.rRemoveErr)
+			readingListRepo.On("GetReadingListBookIDs", mock.Anything, []int{1}).Return(map[int][]int{}, nil)
+
+			uc := usecase.NewReadingListUsecase(&testmock.BookRepositoryInterface{}, readingListRepo, &testmock.BlobStore{})
+			wishlist, err := uc.RemoveWishlistBook(tc.ctx, 7)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				assert.Empty(t, wishlist.Books)
+			}
+		})
+	}
+}
+
+func TestGetWishlistOrderPayload(t *testing.T) {
+	testcases := []struct {
+		name         string
+		ctx          *gin.Context
+		rWishlistErr error
+		rBookIDsRes  map[int][]int
+		rBookIDsErr  error
+		expected     *entity.OrderPayload
+		expectedErr  error
+		wantErr      bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:         "failed to get wishlist",
+			ctx:          readingListGinCtx(2),
+			rWishlistErr: errors.New("error get wishlist"),
+			wantErr:      true,
+		},
+		{
+			name:        "failed to get book ids",
+			ctx:         readingListGinCtx(2),
+			rBookIDsErr: errors.New("error get book ids"),
+			wantErr:     true,
+		},
+		{
+			name:        "empty wishlist",
+			ctx:         readingListGinCtx(2),
+			rBookIDsRes: map[int][]int{},
+			expectedErr: response.ErrEmptyReadingList,
+			wantErr:     true,
+		},
+		{
+			name:        "success",
+			ctx:         readingListGinCtx(2),
+			rBookIDsRes: map[int][]int{1: {7}},
+			expected:    &entity.OrderPayload{OrderItems: []entity.OrderItemPayload{{BookID: 7, Quantity: 1}}},
+			wantErr:     false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("GetBooksByIDs", mock.Anything, []int{7}).Return([]*entity.Book{{ID: 7}}, nil)
+
+			readingListRepo := &testmock.ReadingListRepositoryInterface{}
+			readingListRepo.On("GetOrCreateWishlist", mock.Anything, 2).Return(&entity.ReadingList{ID: 1, UserID: 2, IsWishlist: true}, tc.rWishlistErr)
+			readingListRepo.On("GetReadingListBookIDs", mock.Anything, []int{1}).Return(tc.rBookIDsRes, tc.rBookIDsErr)
+
+			uc := usecase.NewReadingListUsecase(bookRepo, readingListRepo, &testmock.BlobStore{})
+			payload, err := uc.GetWishlistOrderPayload(tc.ctx)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+			assert.Equal(t, tc.expected, payload)
+		})
+	}
+}
+
+func TestCreateReadingList(t *testing.T) {
+	testcases := []struct {
+		name           string
+		ctx            *gin.Context
+		payload        *entity.ReadingListPayload
+		rCreateErr     error
+		expectedShared bool
+		wantErr        bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid payload",
+			ctx:     readingListGinCtx(2),
+			payload: &entity.ReadingListPayload{},
+			wantErr: true,
+		},
+		{
+			name:       "failed to create reading list",
+			ctx:        readingListGinCtx(2),
+			payload:    &entity.ReadingListPayload{Name: "Summer reads"},
+			rCreateErr: errors.New("error create reading list"),
+			wantErr:    true,
+		},
+		{
+			name:    "success private",
+			ctx:     readingListGinCtx(2),
+			payload: &entity.ReadingListPayload{Name: "Summer reads"},
+			wantErr: false,
+		},
+		{
+			name:           "success public",
+			ctx:            readingListGinCtx(2),
+			payload:        &entity.ReadingListPayload{Name: "Summer reads", IsPublic: true},
+			expectedShared: true,
+			wantErr:        false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			readingListRepo := &testmock.ReadingListRepositoryInterface{}
+			readingListRepo.On("CreateReadingList", mock.Anything, mock.Anything).Return(tc.rCreateErr)
+
+			uc := usecase.NewReadingListUsecase(&testmock.BookRepositoryInterface{}, readingListRepo, &testmock.BlobStore{})
+			readingList, err := uc.CreateReadingList(tc.ctx, tc.payload)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				assert.Equal(t, 2, readingList.UserID)
+				assert.Equal(t, tc.expectedShared, readingList.IsPublic)
+				assert.Equal(t, tc.expectedShared, readingList.ShareSlug != "")
+			}
+		})
+	}
+}
+
+func TestGetReadingLists(t *testing.T) {
+	testcases := []struct {
+		name             string
+		ctx              *gin.Context
+		rReadingListsErr error
+		rCountErr        error
+		rBookIDsErr      error
+		wantErr          bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:             "failed to get reading lists",
+			ctx:              readingListGinCtx(2),
+			rReadingListsErr: errors.New("error get reading lists"),
+			wantErr:          true,
+		},
+		{
+			name:      "failed to get reading lists count",
+			ctx:       readingListGinCtx(2),
+			rCountErr: errors.New("error get reading lists count"),
+			wantErr:   true,
+		},
+		{
+			name:        "failed to resolve books",
+			ctx:         readingListGinCtx(2),
+			rBookIDsErr: errors.New("error get book ids"),
+			wantErr:     true,
+		},
+		{
+			name:    "success",
+			ctx:     readingListGinCtx(2),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("GetBooksByIDs", mock.Anything, []int{7}).Return([]*entity.Book{{ID: 7}}, nil)
+
+			readingListRepo := &testmock.ReadingListRepositoryInterface{}
+			readingListRepo.On("GetReadingListsByUserID", mock.Anything, 2, 10, 0).Return([]*entity.ReadingList{{ID: 1}, {ID: 3}}, tc.rReadingListsErr)
+			readingListRepo.On("GetReadingListsByUserIDCount", mock.Anything, 2).Return(2, tc.rCountErr)
+			readingListRepo.On("GetReadingListBookIDs", mock.Anything, []int{1, 3}).Return(map[int][]int{1: {7}, 3: {7}}, tc.rBookIDsErr)
+
+			uc := usecase.NewReadingListUsecase(bookRepo, readingListRepo, &testmock.BlobStore{})
+			readingLists, count, err := uc.GetReadingLists(tc.ctx, 10, 0)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				assert.Equal(t, 2, count)
+				assert.Len(t, readingLists[0].Books, 1)
+				assert.Len(t, readingLists[1].Books, 1)
+				bookRepo.AssertNumberOfCalls(t, "GetBooksByIDs", 1)
+			}
+		})
+	}
+}
+
+func TestGetPublicReadingLists(t *testing.T) {
+	testcases := []struct {
+		name             string
+		ctx              *gin.Context
+		rReadingListsErr error
+		rCountErr        error
+		rBooksErr        error
+		wantErr          bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:             "failed to get public reading lists",
+			ctx:              fixture.GinCtxBackground(),
+			rReadingListsErr: errors.New("error get public reading lists"),
+			wantErr:          true,
+		},
+		{
+			name:      "failed to get public reading lists count",
+			ctx:       fixture.GinCtxBackground(),
+			rCountErr: errors.New("error get public reading lists count"),
+			wantErr:   true,
+		},
+		{
+			name:      "failed to resolve books",
+			ctx:       fixture.GinCtxBackground(),
+			rBooksErr: errors.New("error get books"),
+			wantErr:   true,
+		},
+		{
+			name:    "success",
+			ctx:     fixture.GinCtxBackground(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("GetBooksByIDs", mock.Anything, []int{7}).Return([]*entity.Book{{ID: 7}}, tc.rBooksErr)
+
+			readingListRepo := &testmock.ReadingListRepositoryInterface{}
+			readingListRepo.On("GetPublicReadingLists", mock.Anything, 10, 0).Return([]*entity.ReadingList{{ID: 1, IsPublic: true}}, tc.rReadingListsErr)
+			readingListRepo.On("GetPublicReadingListsCount", mock.Anything).Return(1, tc.rCountErr)
+			readingListRepo.On("GetReadingListBookIDs", mock.Anything, []int{1}).Return(map[int][]int{1: {7}}, nil)
+
+			uc := usecase.NewReadingListUsecase(bookRepo, readingListRepo, &testmock.BlobStore{})
+			readingLists, count, err := uc.GetPublicReadingLists(tc.ctx, 10, 0)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				assert.Equal(t, 1, count)
+				assert.Equal(t, 7, readingLists[0].Books[0].ID)
+			}
+		})
+	}
+}
+
+func TestGetReadingList(t *testing.T) {
+	testcases := []struct {
+		name            string
+		ctx             *gin.Context
+		rReadingListRes *entity.ReadingList
+		rReadingListErr error
+		expectedErr     error
+		wantErr         bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:            "reading list is not found",
+			ctx:             readingListGinCtx(2),
+			rReadingListErr: response.ErrNotFound,
+			expectedErr:     response.ErrNotFound,
+			wantErr:         true,
+		},
+		{
+			name:            "failed to get reading list",
+			ctx:             readingListGinCtx(2),
+			rReadingListErr: errors.New("error get reading list"),
+			wantErr:         true,
+		},
+		{
+			name:            "reading list of another user",
+			ctx:             readingListGinCtx(3),
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2, IsPublic: true},
+			expectedErr:     response.ErrNotFound,
+			wantErr:         true,
+		},
+		{
+			name:            "wishlist",
+			ctx:             readingListGinCtx(2),
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2, IsWishlist: true},
+			expectedErr:     response.ErrNotFound,
+			wantErr:         true,
+		},
+		{
+			name:            "success",
+			ctx:             readingListGinCtx(2),
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2},
+			wantErr:         false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			readingListRepo := &testmock.ReadingListRepositoryInterface{}
+			readingListRepo.On("GetReadingListByID", mock.Anything, 1).Return(tc.rReadingListRes, tc.rReadingListErr)
+			readingListRepo.On("GetReadingListBookIDs", mock.Anything, []int{1}).Return(map[int][]int{}, nil)
+
+			uc := usecase.NewReadingListUsecase(&testmock.BookRepositoryInterface{}, readingListRepo, &testmock.BlobStore{})
+			readingList, err := uc.GetReadingList(tc.ctx, 1)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+			if !tc.wantErr {
+				assert.Equal(t, 1, readingList.ID)
+			}
+		})
+	}
+}
+
+func TestGetSharedReadingList(t *testing.T) {
+	testcases := []struct {
+		name            string
+		ctx             *gin.Context
+		rReadingListErr error
+		rBookIDsErr     error
+		expectedErr     error
+		wantErr         bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:            "reading list is not found",
+			ctx:             fixture.GinCtxBackground(),
+			rReadingListErr: response.ErrNotFound,
+			expectedErr:     response.ErrNotFound,
+			wantErr:         true,
+		},
+		{
+			name:            "failed to get reading list",
+			ctx:             fixture.GinCtxBackground(),
+			rReadingListErr: errors.New("error get reading list"),
+			wantErr:         true,
+		},
+		{
+			name:        "failed to resolve books",
+			ctx:         fixture.GinCtxBackground(),
+			rBookIDsErr: errors.New("error get book ids"),
+			wantErr:     true,
+		},
+		{
+			name:    "success",
+			ctx:     fixture.GinCtxBackground(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			readingListRepo := &testmock.ReadingListRepositoryInterface{}
+			readingListRepo.On("GetReadingListByShareSlug", mock.Anything, "slug").Return(&entity.ReadingList{ID: 1, IsPublic: true, ShareSlug: "slug"}, tc.rReadingListErr)
+			readingListRepo.On("GetReadingListBookIDs", mock.Anything, []int{1}).Return(map[int][]int{}, tc.rBookIDsErr)
+
+			uc := usecase.NewReadingListUsecase(&testmock.BookRepositoryInterface{}, readingListRepo, &testmock.BlobStore{})
+			readingList, err := uc.GetSharedReadingList(tc.ctx, "slug")
+			assert.Equal(t, tc.wantErr, err != nil)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+			if !tc.wantErr {
+				assert.Equal(t, "slug", readingList.ShareSlug)
+			}
+		})
+	}
+}
+
+func TestUpdateReadingList(t *testing.T) {
+	testcases := []struct {
+		name              string
+		ctx               *gin.Context
+		payload           *entity.ReadingListPayload
+		rReadingListRes   *entity.ReadingList
+		rReadingListErr   error
+		rUpdateErr        error
+		expectedShareSlug string
+		expectedNewSlug   bool
+		wantErr           bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid payload",
+			ctx:     readingListGinCtx(2),
+			payload: &entity.ReadingListPayload{},
+			wantErr: true,
+		},
+		{
+			name:            "reading list of another user",
+			ctx:             readingListGinCtx(3),
+			payload:         &entity.ReadingListPayload{Name: "Summer reads"},
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2},
+			wantErr:         true,
+		},
+		{
+			name:            "failed to get reading list",
+			ctx:             readingListGinCtx(2),
+			payload:         &entity.ReadingListPayload{Name: "Summer reads"},
+			rReadingListErr: errors.New("error get reading list"),
+			wantErr:         true,
+		},
+		{
+			name:            "failed to update reading list",
+			ctx:             readingListGinCtx(2),
+			payload:         &entity.ReadingListPayload{Name: "Summer reads"},
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2},
+			rUpdateErr:      errors.New("error update reading list"),
+			wantErr:         true,
+		},
+		{
+			name:            "success make public",
+			ctx:             readingListGinCtx(2),
+			payload:         &entity.ReadingListPayload{Name: "Summer reads", IsPublic: true},
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2},
+			expectedNewSlug: true,
+			wantErr:         false,
+		},
+		{
+			name:              "success keep share slug of public list",
+			ctx:               readingListGinCtx(2),
+			payload:           &entity.ReadingListPayload{Name: "Summer reads", IsPublic: true},
+			rReadingListRes:   &entity.ReadingList{ID: 1, UserID: 2, IsPublic: true, ShareSlug: "slug"},
+			expectedShareSlug: "slug",
+			wantErr:           false,
+		},
+		{
+			name:              "success make private revokes share slug",
+			ctx:               readingListGinCtx(2),
+			payload:           &entity.ReadingListPayload{Name: "Summer reads"},
+			rReadingListRes:   &entity.ReadingList{ID: 1, UserID: 2, IsPublic: true, ShareSlug: "slug"},
+			expectedShareSlug: "",
+			wantErr:           false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			readingListRepo := &testmock.ReadingListRepositoryInterface{}
+			readingListRepo.On("GetReadingListByID", mock.Anything, 1).Return(tc.rReadingListRes, tc.rReadingListErr)
+			readingListRepo.On("UpdateReadingList", mock.Anything, mock.Anything).Return(tc.rUpdateErr)
+			readingListRepo.On("GetReadingListBookIDs", mock.Anything, []int{1}).Return(map[int][]int{}, nil)
+
+			uc := usecase.NewReadingListUsecase(&testmock.BookRepositoryInterface{}, readingListRepo, &testmock.BlobStore{})
+			readingList, err := uc.UpdateReadingList(tc.ctx, 1, tc.payload)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				assert.Equal(t, tc.payload.Name, readingList.Name)
+				assert.Equal(t, tc.payload.IsPublic, readingList.IsPublic)
+				if tc.expectedNewSlug {
+					assert.NotEmpty(t, readingList.ShareSlug)
+				} else {
+					assert.Equal(t, tc.expectedShareSlug, readingList.ShareSlug)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteReadingList(t *testing.T) {
+	testcases := []struct {
+		name            string
+		ctx             *gin.Context
+		rReadingListRes *entity.ReadingList
+		rReadingListErr error
+		rDeleteErr      error
+		wantErr         bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:            "reading list is not found",
+			ctx:             readingListGinCtx(2),
+			rReadingListErr: response.ErrNotFound,
+			wantErr:         true,
+		},
+		{
+			name:            "failed to get reading list",
+			ctx:             readingListGinCtx(2),
+			rReadingListErr: errors.New("error get reading list"),
+			wantErr:         true,
+		},
+		{
+			name:            "failed to delete reading list",
+			ctx:             readingListGinCtx(2),
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2},
+			rDeleteErr:      errors.New("error delete reading list"),
+			wantErr:         true,
+		},
+		{
+			name:            "success",
+			ctx:             readingListGinCtx(2),
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2},
+			wantErr:         false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			readingListRepo := &testmock.ReadingListRepositoryInterface{}
+			readingListRepo.On("GetReadingListByID", mock.Anything, 1).Return(tc.rReadingListRes, tc.rReadingListErr)
+			readingListRepo.On("DeleteReadingList", mock.Anything, 1).Return(tc.rDeleteErr)
+
+			uc := usecase.NewReadingListUsecase(&testmock.BookRepositoryInterface{}, readingListRepo, &testmock.BlobStore{})
+			err := uc.DeleteReadingList(tc.ctx, 1)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestAddReadingListBook(t *testing.T) {
+	testcases := []struct {
+		name            string
+		ctx             *gin.Context
+		rReadingListRes *entity.ReadingList
+		rReadingListErr error
+		rBookErr        error
+		expectedErr     error
+		wantErr         bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:            "reading list of another user",
+			ctx:             readingListGinCtx(3),
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2},
+			expectedErr:     response.ErrNotFound,
+			wantErr:         true,
+		},
+		{
+			name:            "failed to get reading list",
+			ctx:             readingListGinCtx(2),
+			rReadingListErr: errors.New("error get reading list"),
+			wantErr:         true,
+		},
+		{
+			name:            "book is not found",
+			ctx:             readingListGinCtx(2),
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2},
+			rBookErr:        response.ErrNotFound,
+			expectedErr:     response.ErrNotFound,
+			wantErr:         true,
+		},
+		{
+			name:            "failed to get book",
+			ctx:             readingListGinCtx(2),
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2},
+			rBookErr:        errors.New("error get book"),
+			wantErr:         true,
+		},
+		{
+			name:            "success",
+			ctx:             readingListGinCtx(2),
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2},
+			wantErr:         false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("GetBookByID", mock.Anything, 7).Return(&entity.Book{ID: 7}, tc.rBookErr)
+			bookRepo.On("GetBooksByIDs", mock.Anything, []int{7}).Return([]*entity.Book{{ID: 7}}, nil)
+
+			readingListRepo := &testmock.ReadingListRepositoryInterface{}
+			readingListRepo.On("GetReadingListByID", mock.Anything, 1).Return(tc.rReadingListRes, tc.rReadingListErr)
+			readingListRepo.On("AddReadingListBook", mock.Anything, 1, 7).Return(nil)
+			readingListRepo.On("GetReadingListBookIDs", mock.Anything, []int{1}).Return(map[int][]int{1: {7}}, nil)
+
+			uc := usecase.NewReadingListUsecase(bookRepo, readingListRepo, &testmock.BlobStore{})
+			readingList, err := uc.AddReadingListBook(tc.ctx, 1, &entity.ReadingListBookPayload{BookID: 7})
+			assert.Equal(t, tc.wantErr, err != nil)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+			if !tc.wantErr {
+				assert.Len(t, readingList.Books, 1)
+			}
+		})
+	}
+}
+
+func TestRemoveReadingListBook(t *testing.T) {
+	testcases := []struct {
+		name            string
+		ctx             *gin.Context
+		rReadingListRes *entity.ReadingList
+		rReadingListErr error
+		rRemoveErr      error
+		wantErr         bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:            "reading list is not found",
+			ctx:             readingListGinCtx(2),
+			rReadingListErr: response.ErrNotFound,
+			wantErr:         true,
+		},
+		{
+			name:            "failed to remove book",
+			ctx:             readingListGinCtx(2),
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2},
+			rRemoveErr:      errors.New("error remove book"),
+			wantErr:         true,
+		},
+		{
+			name:            "success",
+			ctx:             readingListGinCtx(2),
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2},
+			wantErr:         false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			readingListRepo := &testmock.ReadingListRepositoryInterface{}
+			readingListRepo.On("GetReadingListByID", mock.Anything, 1).Return(tc.rReadingListRes, tc.rReadingListErr)
+			readingListRepo.On("RemoveReadingListBook", mock.Anything, 1, 7).Return(tc.rRemoveErr)
+			readingListRepo.On("GetReadingListBookIDs", mock.Anything, []int{1}).Return(map[int][]int{}, nil)
+
+			uc := usecase.NewReadingListUsecase(&testmock.BookRepositoryInterface{}, readingListRepo, &testmock.BlobStore{})
+			_, err := uc.RemoveReadingListBook(tc.ctx, 1, 7)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestGetReadingListOrderPayload(t *testing.T) {
+	testcases := []struct {
+		name            string
+		ctx             *gin.Context
+		rReadingListRes *entity.ReadingList
+		rReadingListErr error
+		rBookIDsRes     map[int][]int
+		expected        *entity.OrderPayload
+		expectedErr     error
+		wantErr         bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:            "reading list is not found",
+			ctx:             readingListGinCtx(2),
+			rReadingListErr: response.ErrNotFound,
+			expectedErr:     response.ErrNotFound,
+			wantErr:         true,
+		},
+		{
+			name:            "failed to get reading list",
+			ctx:             readingListGinCtx(2),
+			rReadingListErr: errors.New("error get reading list"),
+			wantErr:         true,
+		},
+		{
+			name:            "empty reading list",
+			ctx:             readingListGinCtx(2),
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2},
+			rBookIDsRes:     map[int][]int{},
+			expectedErr:     response.ErrEmptyReadingList,
+			wantErr:         true,
+		},
+		{
+			name:            "success",
+			ctx:             readingListGinCtx(2),
+			rReadingListRes: &entity.ReadingList{ID: 1, UserID: 2},
+			rBookIDsRes:     map[int][]int{1: {7, 8}},
+			expected:        &entity.OrderPayload{OrderItems: []entity.OrderItemPayload{{BookID: 7, Quantity: 1}, {BookID: 8, Quantity: 1}}},
+			wantErr:         false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("GetBooksByIDs", mock.Anything, []int{7, 8}).Return([]*entity.Book{{ID: 8}, {ID: 7}}, nil)
+
+			readingListRepo := &testmock.ReadingListRepositoryInterface{}
+			readingListRepo.On("GetReadingListByID", mock.Anything, 1).Return(tc.rReadingListRes, tc.rReadingListErr)
+			readingListRepo.On("GetReadingListBookIDs", mock.Anything, []int{1}).Return(tc.rBookIDsRes, nil)
+
+			uc := usecase.NewReadingListUsecase(bookRepo, readingListRepo, &testmock.BlobStore{})
+			payload, err := uc.GetReadingListOrderPayload(tc.ctx, 1)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+			assert.Equal(t, tc.expected, payload)
+		})
+	}
+}
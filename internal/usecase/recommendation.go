@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	repo "github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/pkg/blobstore"
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
+)
+
+// RecommendationUsecaseInterface define contract for book recommendation related functions to usecase
+type RecommendationUsecaseInterface interface {
+	RefreshRecommendations(ctx context.Context) error
+	GetRelatedBooks(ctx context.Context, bookID, limit int) ([]*entity.Book, error)
+	GetUserRecommendations(c *gin.Context, limit int) ([]*entity.Book, error)
+}
+
+type RecommendationUsecase struct {
+	dbTransactionRepo  repo.PostgresTransactionRepositoryInterface
+	bookRepo           repo.BookRepositoryInterface
+	recommendationRepo repo.RecommendationRepositoryInterface
+	blobStore          blobstore.BlobStore
+}
+
+func NewRecommendationUsecase(
+	ptr repo.PostgresTransactionRepositoryInterface,
+	br repo.BookRepositoryInterface,
+	rr repo.RecommendationRepositoryInterface,
+	bs blobstore.BlobStore,
+) *RecommendationUsecase {
+	return &RecommendationUsecase{
+		dbTransactionRepo:  ptr,
+		bookRepo:           br,
+		recommendationRepo: rr,
+		blobStore:          bs,
+	}
+}
+
+// RefreshRecommendations recomputes the co-purchase scores of every book from the order items,
+// it is a no-op when another instance is already refreshing them
+func (uc *RecommendationUsecase) RefreshRecommendations(ctx context.Context) error {
+	functionName := "RecommendationUsecase.RefreshRecommendations"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	if _, err := uc.recommendationRepo.RefreshBookRecommendations(ctx, tx, config.RecommendationsPerBook); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.recommendationRepo.RefreshBookRecommendations: %w", err), functionName)
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	return nil
+}
+
+// GetRelatedBooks returns the books most often bought together with the given book
+func (uc *RecommendationUsecase) GetRelatedBooks(ctx context.Context, bookID, limit int) ([]*entity.Book, error) {
+	functionName := "RecommendationUsecase.GetRelatedBooks"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if _, err := uc.bookRepo.GetBookByID(ctx, bookID); err != nil {
+		if err == response.ErrNotFound {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.bookRepo.GetBookByID: %w", err), functionName)
+	}
+
+	bookIDs, err := uc.recommendationRepo.GetRelatedBookIDs(ctx, bookID, limit)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.recommendationRepo.GetRelatedBookIDs: %w", err), functionName)
+	}
+
+	books, err := uc.resolveBooks(ctx, bookIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return books, nil
+}
+
+// GetUserRecommendations returns the books bought together with the purchases of the logged in user,
+// users without any recommendation yet get the bestsellers instead
+func (uc *RecommendationUsecase) GetUserRecommendations(c *gin.Context, limit int) ([]*entity.Book, error) {
+	functionName := "RecommendationUsecase.GetUserRecommendations"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	bookIDs, err := uc.recommendationRepo.GetUserRecommendedBookIDs(ctx, helper.GetUserIDFromContext(c), limit)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.recommendationRepo.GetUserRecommendedBookIDs: %w", err), functionName)
+	}
+
+	if len(bookIDs) == 0 {
+		bookIDs, err = uc.recommendationRepo.GetBestsellingBookIDs(ctx, limit)
+		if err != nil {
+			return nil, errors.Wrap(fmt.Errorf("uc.recommendationRepo.GetBestsellingBookIDs: %w", err), functionName)
+		}
+	}
+
+	books, err := uc.resolveBooks(ctx, bookIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return books, nil
+}
+
+// resolveBooks loads the books of the given IDs keeping their ranking order, deleted books are skipped
+func (uc *RecommendationUsecase) resolveBooks(ctx context.Context, bookIDs []int) ([]*entity.Book, error) {
+	result := []*entity.Book{}
+	if len(bookIDs) == 0 {
+		return result, nil
+	}
+
+	books, err := uc.bookRepo.GetBooksByIDs(ctx, bookIDs)
+	if err != nil {
+		return nil, fmt.Errorf("uc.bookRepo.GetBooksByIDs: %w", err)
+	}
+
+	booksByID := make(map[int]*entity.Book, len(books))
+	for _, book := range books {
+		setBookCoverURLs(uc.blobStore, book)
+		booksByID[book.ID] = book
+	}
+
+	for _, bookID := range bookIDs {
+		if book, ok := booksByID[bookID]; ok {
+			result = append(result, book)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,236 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRefreshRecommendations(t *testing.T) {
+	testcases := []struct {
+		name          string
+		ctx           context.Context
+		rStartTrxErr  error
+		rRefreshErr   error
+		rCommitTrxErr error
+		wantErr       bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          context.Background(),
+			rStartTrxErr: errors.New("error start transaction"),
+			wantErr:      true,
+		},
+		{
+			name:        "failed to refresh recommendations",
+			ctx:         context.Background(),
+			rRefreshErr: errors.New("error refresh recommendations"),
+			wantErr:     true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           context.Background(),
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantErr:       true,
+		},
+		{
+			name:    "success",
+			ctx:     context.Background(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			recommendationRepo := &testmock.RecommendationRepositoryInterface{}
+			recommendationRepo.On("RefreshBookRecommendations", mock.Anything, mock.Anything, config.RecommendationsPerBook).Return(true, tc.rRefreshErr)
+
+			uc := usecase.NewRecommendationUsecase(dbTransactionRepo, &testmock.BookRepositoryInterface{}, recommendationRepo, &testmock.BlobStore{})
+			err := uc.RefreshRecommendations(tc.ctx)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestGetRelatedBooks(t *testing.T) {
+	testcases := []struct {
+		name            string
+		ctx             context.Context
+		rBookErr        error
+		rRelatedRes     []int
+		rRelatedErr     error
+		rBooksErr       error
+		expectedBookIDs []int
+		expectedErr     error
+		wantErr         bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:        "book is not found",
+			ctx:         context.Background(),
+			rBookErr:    response.ErrNotFound,
+			expectedErr: response.ErrNotFound,
+			wantErr:     true,
+		},
+		{
+			name:     "failed to get book",
+			ctx:      context.Background(),
+			rBookErr: errors.New("error get book"),
+			wantErr:  true,
+		},
+		{
+			name:        "failed to get related book ids",
+			ctx:         context.Background(),
+			rRelatedErr: errors.New("error get related book ids"),
+			wantErr:     true,
+		},
+		{
+			name:        "failed to get books",
+			ctx:         context.Background(),
+			rRelatedRes: []int{8, 9, 7},
+			rBooksErr:   errors.New("error get books"),
+			wantErr:     true,
+		},
+		{
+			name:            "success without related books",
+			ctx:             context.Background(),
+			rRelatedRes:     []int{},
+			expectedBookIDs: []int{},
+			wantErr:         false,
+		},
+		{
+			name:            "success keep ranking and skip deleted books",
+			ctx:             context.Background(),
+			rRelatedRes:     []int{8, 9, 7},
+			expectedBookIDs: []int{8, 7},
+			wantErr:         false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("GetBookByID", mock.Anything, 1).Return(&entity.Book{ID: 1}, tc.rBookErr)
+			bookRepo.On("GetBooksByIDs", mock.Anything, []int{8, 9, 7}).Return([]*entity.Book{{ID: 7, CoverKey: "covers/7/1/original.jpg"}, {ID: 8}}, tc.rBooksErr)
+
+			recommendationRepo := &testmock.RecommendationRepositoryInterface{}
+			recommendationRepo.On("GetRelatedBookIDs", mock.Anything, 1, 10).Return(tc.rRelatedRes, tc.rRelatedErr)
+
+			uc := usecase.NewRecommendationUsecase(&testmock.PostgresTransactionRepositoryInterface{}, bookRepo, recommendationRepo, newReadingListBlobStore())
+			books, err := uc.GetRelatedBooks(tc.ctx, 1, 10)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+			if !tc.wantErr {
+				bookIDs := []int{}
+				for _, book := range books {
+					bookIDs = append(bookIDs, book.ID)
+				}
+				assert.Equal(t, tc.expectedBookIDs, bookIDs)
+			}
+		})
+	}
+}
+
+func TestGetUserRecommendations(t *testing.T) {
+	testcases := []struct {
+		name            string
+		ctx             *gin.Context
+		rRecommendedRes []int
+		rRecommendedErr error
+		rBestsellingRes []int
+		rBestsellingErr error
+		rBooksErr       error
+		expectedBookIDs []int
+		wantErr         bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:            "failed to get recommended book ids",
+			ctx:             readingListGinCtx(2),
+			rRecommendedErr: errors.New("error get recommended book ids"),
+			wantErr:         true,
+		},
+		{
+			name:            "failed to get bestselling book ids",
+			ctx:             readingListGinCtx(2),
+			rRecommendedRes: []int{},
+			rBestsellingErr: errors.New("error get bestselling book ids"),
+			wantErr:         true,
+		},
+		{
+			name:            "failed to get books",
+			ctx:             readingListGinCtx(2),
+			rRecommendedRes: []int{8, 9, 7},
+			rBooksErr:       errors.New("error get books"),
+			wantErr:         true,
+		},
+		{
+			name:            "success",
+			ctx:             readingListGinCtx(2),
+			rRecommendedRes: []int{8, 9, 7},
+			expectedBookIDs: []int{8, 7},
+			wantErr:         false,
+		},
+		{
+			name:            "success fallback to bestsellers",
+			ctx:             readingListGinCtx(2),
+			rRecommendedRes: []int{},
+			rBestsellingRes: []int{8, 9, 7},
+			expectedBookIDs: []int{8, 7},
+			wantErr:         false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("GetBooksByIDs", mock.Anything, []int{8, 9, 7}).Return([]*entity.Book{{ID: 7}, {ID: 8}}, tc.rBooksErr)
+
+			recommendationRepo := &testmock.RecommendationRepositoryInterface{}
+			recommendationRepo.On("GetUserRecommendedBookIDs", mock.Anything, 2, 10).Return(tc.rRecommendedRes, tc.rRecommendedErr)
+			recommendationRepo.On("GetBestsellingBookIDs", mock.Anything, 10).Return(tc.rBestsellingRes, tc.rBestsellingErr)
+
+			uc := usecase.NewRecommendationUsecase(&testmock.PostgresTransactionRepositoryInterface{}, bookRepo, recommendationRepo, newReadingListBlobStore())
+			books, err := uc.GetUserRecommendations(tc.ctx, 10)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				bookIDs := []int{}
+				for _, book := range books {
+					bookIDs = append(bookIDs, book.ID)
+				}
+				assert.Equal(t, tc.expectedBookIDs, bookIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package usecase
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	repo "github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/pkg/contentfilter"
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
+)
+
+// ReviewUsecaseInterface define contract for review related functions to usecase
+type ReviewUsecaseInterface interface {
+	CreateReview(c *gin.Context, bookID int, payload *entity.ReviewPayload) (*entity.Review, error)
+	GetReviewsByBookID(c *gin.Context, bookID, limit, offset int) ([]*entity.Review, int, error)
+	ReportReview(c *gin.Context, reviewID int, payload *entity.ReviewReportPayload) (*entity.ReviewReport, error)
+	GetReviewsByStatus(c *gin.Context, status string, limit, offset int) ([]*entity.Review, int, error)
+	ModerateReviews(c *gin.Context, payload *entity.ModerateReviewsPayload) error
+}
+
+type ReviewUsecase struct {
+	dbTransactionRepo repo.PostgresTransactionRepositoryInterface
+	bookRepo          repo.BookRepositoryInterface
+	orderItemRepo     repo.OrderItemRepositoryInterface
+	reviewRepo        repo.ReviewRepositoryInterface
+	contentFilter     contentfilter.Filter
+	auditor           AuditRecorder
+}
+
+func NewReviewUsecase(
+	ptr repo.PostgresTransactionRepositoryInterface,
+	br repo.BookRepositoryInterface,
+	oir repo.OrderItemRepositoryInterface,
+	rr repo.ReviewRepositoryInterface,
+	cf contentfilter.Filter,
+	ar AuditRecorder,
+) *ReviewUsecase {
+	return &ReviewUsecase{
+		dbTransactionRepo: ptr,
+		bookRepo:          br,
+		orderItemRepo:     oir,
+		reviewRepo:        rr,
+		contentFilter:     cf,
+		auditor:           ar,
+	}
+}
+
+// CreateReview stores the review of the logged in user and refreshes the book rating in the same transaction,
+// the review is flagged as verified purchase when the user has ordered the book.
+// Reviews flagged by the content filter are held for moderation instead of being published
+func (uc *ReviewUsecase) CreateReview(c *gin.Context, bookID int, payload *entity.ReviewPayload) (*entity.Review, error) {
+	functionName := "ReviewUsecase.CreateReview"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.bookRepo.GetBookByID(ctx, bookID); err != nil {
+		if err == response.ErrNotFound {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.bookRepo.GetBookByID: %w", err), functionName)
+	}
+
+	userID := helper.GetUserIDFromContext(c)
+	verifiedPurchase, err := uc.orderItemRepo.HasUserOrderedBook(ctx, userID, bookID)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.orderItemRepo.HasUserOrderedBook: %w", err), functionName)
+	}
+
+	status := entity.ReviewStatusApproved
+	result, err := uc.contentFilter.Check(ctx, payload.Body)
+	if err != nil || result.Flagged {
+		// A failing filter must not let unchecked reviews through, hold them for a moderator
+		status = entity.ReviewStatusPending
+	}
+
+	// Begin transaction
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	review := &entity.Review{
+		BookID:           bookID,
+		UserID:           userID,
+		Rating:           payload.Rating,
+		Body:             payload.Body,
+		VerifiedPurchase: verifiedPurchase,
+		Status:           status,
+	}
+	if err := uc.reviewRepo.CreateReview(ctx, tx, review); err != nil {
+		if err == response.ErrDuplicateReview {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.reviewRepo.CreateReview: %w", err), functionName)
+	}
+
+	if err := uc.bookRepo.UpdateBookRating(ctx, tx, bookID); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.bookRepo.UpdateBookRating: %w", err), functionName)
+	}
+
+	// Commit transaction
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	return review, nil
+}
+
+func (uc *ReviewUsecase) GetReviewsByBookID(c *gin.Context, bookID, limit, offset int) ([]*entity.Review, int, error) {
+	functionName := "ReviewUsecase.GetReviewsByBookID"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, 0, errors.Wrap(err, functionName)
+	}
+
+	reviews, err := uc.reviewRepo.GetReviewsByBookID(ctx, bookID, limit, offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(fmt.Errorf("uc.reviewRepo.GetReviewsByBookID: %w", err), functionName)
+	}
+
+	count, err := uc.reviewRepo.GetReviewsByBookIDCount(ctx, bookID)
+	if err != nil {
+		return nil, 0, errors.Wrap(fmt.Errorf("uc.reviewRepo.GetReviewsByBookIDCount: %w", err), functionName)
+	}
+
+	return reviews, count, nil
+}
+
+// ReportReview records an abuse report of the logged in user on a published review,
+// the review is held for moderation again once it reaches the report threshold
+func (uc *ReviewUsecase) ReportReview(c *gin.Context, reviewID int, payload *entity.ReviewReportPayload) (*entity.ReviewReport, error) {
+	functionName := "ReviewUsecase.ReportReview"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	review, err := uc.reviewRepo.GetReviewByID(ctx, reviewID)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.reviewRepo.GetReviewByID: %w", err), functionName)
+	}
+
+	// Only published reviews are visible, so only they can be reported
+	if review.Status != entity.ReviewStatusApproved {
+		return nil, response.ErrNotFound
+	}
+
+	// Begin transaction
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	report := &entity.ReviewReport{
+		ReviewID: review.ID,
+		UserID:   helper.GetUserIDFromContext(c),
+		Reason:   payload.Reason,
+	}
+	if err := uc.reviewRepo.CreateReviewReport(ctx, tx, report); err != nil {
+		if err == response.ErrDuplicateReviewReport {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.reviewRepo.CreateReviewReport: %w", err), functionName)
+	}
+
+	status, err := uc.reviewRepo.IncrementReviewReportCount(ctx, tx, review.ID, config.ReviewReportThreshold)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.reviewRepo.IncrementReviewReportCount: %w", err), functionName)
+	}
+
+	// A review put on hold no longer counts towards the book rating
+	if status != review.Status {
+		if err := uc.bookRepo.UpdateBookRating(ctx, tx, review.BookID); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("uc.bookRepo.UpdateBookRating: %w", err), functionName)
+		}
+	}
+
+	// Commit transaction
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	return report, nil
+}
+
+// GetReviewsByStatus returns the moderation queue of the status, the most reported reviews first
+func (uc *ReviewUsecase) GetReviewsByStatus(c *gin.Context, status string, limit, offset int) ([]*entity.Review, int, error) {
+	functionName := "ReviewUsecase.GetReviewsByStatus"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, 0, errors.Wrap(err, functionName)
+	}
+
+	if !entity.IsValidReviewStatus(status) {
+		return nil, 0, response.ErrInvalidReviewStatus
+	}
+
+	reviews, err := uc.reviewRepo.GetReviewsByStatus(ctx, status, limit, offset)
+	if err != nil {
+		return nil, 0, errors.Wrap(fmt.Errorf("uc.reviewRepo.GetReviewsByStatus: %w", err), functionName)
+	}
+
+	count, err := uc.reviewRepo.GetReviewsByStatusCount(ctx, status)
+	if err != nil {
+		return nil, 0, errors.Wrap(fmt.Errorf("uc.reviewRepo.GetReviewsByStatusCount: %w", err), functionName)
+	}
+
+	return reviews, count, nil
+}
+
+// ModerateReviews approves or rejects the reviews and refreshes the rating of their books in the same transaction
+func (uc *ReviewUsecase) ModerateReviews(c *gin.Context, payload *entity.ModerateReviewsPayload) error {
+	functionName := "ReviewUsecase.ModerateReviews"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if err := payload.Validate(); err != nil {
+		return err
+	}
+
+	// Begin transaction
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	bookIDs, err := uc.reviewRepo.UpdateReviewsStatus(ctx, tx, payload.ReviewIDs, payload.Status)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.reviewRepo.UpdateReviewsStatus: %w", err), functionName)
+	}
+
+	for _, bookID := range bookIDs {
+		if err := uc.bookRepo.UpdateBookRating(ctx, tx, bookID); err != nil {
+			return errors.Wrap(fmt.Errorf("uc.bookRepo.UpdateBookRating: %w", err), functionName)
+		}
+	}
+
+	for _, reviewID := range payload.ReviewIDs {
+		event := entity.NewAuditEvent(entity.AuditActionReviewsModerated, entity.AuditTargetReview, strconv.Itoa(reviewID), entity.AuditDiff{
+			"status": {To: payload.Status},
+		})
+		if err := uc.auditor.Record(ctx, tx, event); err != nil {
+			return errors.Wrap(fmt.Errorf("uc.auditor.Record: %w", err), functionName)
+		}
+	}
+
+	// Commit transaction
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	return nil
+}
@@ -0,0 +1,515 @@
+package usecase_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/response"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/contentfilter"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateReview(t *testing.T) {
+	testcases := []struct {
+		name                   string
+		ctx                    *gin.Context
+		payload                *entity.ReviewPayload
+		rBookErr               error
+		rHasOrderedRes         bool
+		rHasOrderedErr         error
+		rStartTrxErr           error
+		rCreateReviewErr       error
+		rUpdateBookRatingErr   error
+		rCommitTrxErr          error
+		rFilterRes             *contentfilter.Result
+		rFilterErr             error
+		expectedVerifiedReview bool
+		expectedStatus         string
+		wantErr                bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid payload",
+			ctx:     fixture.GinCtxBackground(),
+			payload: &entity.ReviewPayload{Rating: 6},
+			wantErr: true,
+		},
+		{
+			name:     "book is not found",
+			ctx:      fixture.GinCtxBackground(),
+			payload:  &entity.ReviewPayload{Rating: 5},
+			rBookErr: response.ErrNotFound,
+			wantErr:  true,
+		},
+		{
+			name:     "failed to get book",
+			ctx:      fixture.GinCtxBackground(),
+			payload:  &entity.ReviewPayload{Rating: 5},
+			rBookErr: errors.New("error get book"),
+			wantErr:  true,
+		},
+		{
+			name:           "failed to check purchase",
+			ctx:            fixture.GinCtxBackground(),
+			payload:        &entity.ReviewPayload{Rating: 5},
+			rHasOrderedErr: errors.New("error check purchase"),
+			wantErr:        true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          fixture.GinCtxBackground(),
+			payload:      &entity.ReviewPayload{Rating: 5},
+			rStartTrxErr: response.ErrNoSQLTransactionFound,
+			wantErr:      true,
+		},
+		{
+			name:             "duplicate review",
+			ctx:              fixture.GinCtxBackground(),
+			payload:          &entity.ReviewPayload{Rating: 5},
+			rCreateReviewErr: response.ErrDuplicateReview,
+			wantErr:          true,
+		},
+		{
+			name:             "failed to create review",
+			ctx:              fixture.GinCtxBackground(),
+			payload:          &entity.ReviewPayload{Rating: 5},
+			rCreateReviewErr: errors.New("error create review"),
+			wantErr:          true,
+		},
+		{
+			name:                 "failed to update book rating",
+			ctx:                  fixture.GinCtxBackground(),
+			payload:              &entity.ReviewPayload{Rating: 5},
+			rUpdateBookRatingErr: errors.New("error update book rating"),
+			wantErr:              true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.ReviewPayload{Rating: 5},
+			rCommitTrxErr: response.ErrNoSQLTransactionFound,
+			wantErr:       true,
+		},
+		{
+			name:           "success",
+			ctx:            fixture.GinCtxBackground(),
+			payload:        &entity.ReviewPayload{Rating: 5, Body: "Magical"},
+			expectedStatus: entity.ReviewStatusApproved,
+			wantErr:        false,
+		},
+		{
+			name:                   "success verified purchase",
+			ctx:                    fixture.GinCtxBackground(),
+			payload:                &entity.ReviewPayload{Rating: 5, Body: "Magical"},
+			rHasOrderedRes:         true,
+			expectedVerifiedReview: true,
+			expectedStatus:         entity.ReviewStatusApproved,
+			wantErr:                false,
+		},
+		{
+			name:           "success held by content filter",
+			ctx:            fixture.GinCtxBackground(),
+			payload:        &entity.ReviewPayload{Rating: 5, Body: "Visit my casino"},
+			rFilterRes:     &contentfilter.Result{Flagged: true, Reasons: []string{"casino"}},
+			expectedStatus: entity.ReviewStatusPending,
+			wantErr:        false,
+		},
+		{
+			name:           "success held when content filter fails",
+			ctx:            fixture.GinCtxBackground(),
+			payload:        &entity.ReviewPayload{Rating: 5, Body: "Magical"},
+			rFilterErr:     errors.New("error check content"),
+			expectedStatus: entity.ReviewStatusPending,
+			wantErr:        false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("GetBookByID", mock.Anything, 1).Return(&entity.Book{ID: 1}, tc.rBookErr)
+			bookRepo.On("UpdateBookRating", mock.Anything, mock.Anything, 1).Return(tc.rUpdateBookRatingErr)
+
+			orderItemRepo := &testmock.OrderItemRepositoryInterface{}
+			orderItemRepo.On("HasUserOrderedBook", mock.Anything, mock.Anything, 1).Return(tc.rHasOrderedRes, tc.rHasOrderedErr)
+
+			reviewRepo := &testmock.ReviewRepositoryInterface{}
+			reviewRepo.On("CreateReview", mock.Anything, mock.Anything, mock.Anything).Return(tc.rCreateReviewErr)
+
+			filterRes := tc.rFilterRes
+			if filterRes == nil {
+				filterRes = &contentfilter.Result{}
+			}
+			contentFilter := &testmock.Filter{}
+			contentFilter.On("Check", mock.Anything, mock.Anything).Return(filterRes, tc.rFilterErr)
+
+			uc := usecase.NewReviewUsecase(dbTransactionRepo, bookRepo, orderItemRepo, reviewRepo, contentFilter, newAuditRecorder())
+			review, err := uc.CreateReview(tc.ctx, 1, tc.payload)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				assert.Equal(t, tc.payload.Rating, review.Rating)
+				assert.Equal(t, tc.expectedVerifiedReview, review.VerifiedPurchase)
+				assert.Equal(t, tc.expectedStatus, review.Status)
+			}
+		})
+	}
+}
+
+func TestGetReviewsByBookID(t *testing.T) {
+	testcases := []struct {
+		name                        string
+		ctx                         *gin.Context
+		rGetReviewsByBookIDErr      error
+		rGetReviewsByBookIDCountRes int
+		rGetReviewsByBookIDCountErr error
+		wantErr                     bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:                   "failed to get reviews",
+			ctx:                    fixture.GinCtxBackground(),
+			rGetReviewsByBookIDErr: errors.New("error get reviews by book id"),
+			wantErr:                true,
+		},
+		{
+			name:                        "failed to get reviews count",
+			ctx:                         fixture.GinCtxBackground(),
+			rGetReviewsByBookIDCountErr: errors.New("error get reviews by book id count"),
+			wantErr:                     true,
+		},
+		{
+			name:                        "success",
+			ctx:                         fixture.GinCtxBackground(),
+			rGetReviewsByBookIDCountRes: 1,
+			wantErr:                     false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			reviewRepo := &testmock.ReviewRepositoryInterface{}
+			reviewRepo.On("GetReviewsByBookID", mock.Anything, 1, 10, 0).Return([]*entity.Review{{}}, tc.rGetReviewsByBookIDErr)
+			reviewRepo.On("GetReviewsByBookIDCount", mock.Anything, 1).Return(tc.rGetReviewsByBookIDCountRes, tc.rGetReviewsByBookIDCountErr)
+
+			uc := usecase.NewReviewUsecase(&testmock.PostgresTransactionRepositoryInterface{}, &testmock.BookRepositoryInterface{}, &testmock.OrderItemRepositoryInterface{}, reviewRepo, &testmock.Filter{}, newAuditRecorder())
+			_, count, err := uc.GetReviewsByBookID(tc.ctx, 1, 10, 0)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.rGetReviewsByBookIDCountRes, count)
+		})
+	}
+}
+
+func TestReportReview(t *testing.T) {
+	testcases := []struct {
+		name                 string
+		ctx                  *gin.Context
+		payload              *entity.ReviewReportPayload
+		rReviewRes           *entity.Review
+		rReviewErr           error
+		rStartTrxErr         error
+		rCreateReportErr     error
+		rIncrementRes        string
+		rIncrementErr        error
+		rUpdateBookRatingErr error
+		rCommitTrxErr        error
+		expectedRatingUpdate bool
+		wantErr              bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid payload",
+			ctx:     fixture.GinCtxBackground(),
+			payload: &entity.ReviewReportPayload{Reason: strings.Repeat("a", entity.ReviewReportMaxReasonLen+1)},
+			wantErr: true,
+		},
+		{
+			name:       "review is not found",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.ReviewReportPayload{},
+			rReviewErr: response.ErrNotFound,
+			wantErr:    true,
+		},
+		{
+			name:       "failed to get review",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.ReviewReportPayload{},
+			rReviewErr: errors.New("error get review"),
+			wantErr:    true,
+		},
+		{
+			name:       "review is not published",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.ReviewReportPayload{},
+			rReviewRes: &entity.Review{ID: 1, BookID: 2, Status: entity.ReviewStatusPending},
+			wantErr:    true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          fixture.GinCtxBackground(),
+			payload:      &entity.ReviewReportPayload{},
+			rStartTrxErr: response.ErrNoSQLTransactionFound,
+			wantErr:      true,
+		},
+		{
+			name:             "duplicate report",
+			ctx:              fixture.GinCtxBackground(),
+			payload:          &entity.ReviewReportPayload{},
+			rCreateReportErr: response.ErrDuplicateReviewReport,
+			wantErr:          true,
+		},
+		{
+			name:             "failed to create report",
+			ctx:              fixture.GinCtxBackground(),
+			payload:          &entity.ReviewReportPayload{},
+			rCreateReportErr: errors.New("error create report"),
+			wantErr:          true,
+		},
+		{
+			name:          "failed to increment report count",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.ReviewReportPayload{},
+			rIncrementErr: errors.New("error increment report count"),
+			wantErr:       true,
+		},
+		{
+			name:                 "failed to update book rating",
+			ctx:                  fixture.GinCtxBackground(),
+			payload:              &entity.ReviewReportPayload{},
+			rIncrementRes:        entity.ReviewStatusPending,
+			rUpdateBookRatingErr: errors.New("error update book rating"),
+			wantErr:              true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.ReviewReportPayload{},
+			rCommitTrxErr: response.ErrNoSQLTransactionFound,
+			wantErr:       true,
+		},
+		{
+			name:          "success",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.ReviewReportPayload{Reason: "Spam"},
+			rIncrementRes: entity.ReviewStatusApproved,
+			wantErr:       false,
+		},
+		{
+			name:                 "success review put on hold",
+			ctx:                  fixture.GinCtxBackground(),
+			payload:              &entity.ReviewReportPayload{Reason: "Spam"},
+			rIncrementRes:        entity.ReviewStatusPending,
+			expectedRatingUpdate: true,
+			wantErr:              false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			review := tc.rReviewRes
+			if review == nil {
+				review = &entity.Review{ID: 1, BookID: 2, Status: entity.ReviewStatusApproved}
+			}
+
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("UpdateBookRating", mock.Anything, mock.Anything, 2).Return(tc.rUpdateBookRatingErr)
+
+			reviewRepo := &testmock.ReviewRepositoryInterface{}
+			reviewRepo.On("GetReviewByID", mock.Anything, 1).Return(review, tc.rReviewErr)
+			reviewRepo.On("CreateReviewReport", mock.Anything, mock.Anything, mock.Anything).Return(tc.rCreateReportErr)
+			reviewRepo.On("IncrementReviewReportCount", mock.Anything, mock.Anything, 1, 3).Return(tc.rIncrementRes, tc.rIncrementErr)
+
+			uc := usecase.NewReviewUsecase(dbTransactionRepo, bookRepo, &testmock.OrderItemRepositoryInterface{}, reviewRepo, &testmock.Filter{}, newAuditRecorder())
+			report, err := uc.ReportReview(tc.ctx, 1, tc.payload)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				assert.Equal(t, 1, report.ReviewID)
+				assert.Equal(t, tc.payload.Reason, report.Reason)
+				if tc.expectedRatingUpdate {
+					bookRepo.AssertCalled(t, "UpdateBookRating", mock.Anything, mock.Anything, 2)
+				} else {
+					bookRepo.AssertNotCalled(t, "UpdateBookRating", mock.Anything, mock.Anything, 2)
+				}
+			}
+		})
+	}
+}
+
+func TestGetReviewsByStatus(t *testing.T) {
+	testcases := []struct {
+		name                string
+		ctx                 *gin.Context
+		status              string
+		rGetReviewsErr      error
+		rGetReviewsCountErr error
+		wantErr             bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid status",
+			ctx:     fixture.GinCtxBackground(),
+			status:  "deleted",
+			wantErr: true,
+		},
+		{
+			name:           "failed to get reviews",
+			ctx:            fixture.GinCtxBackground(),
+			status:         entity.ReviewStatusPending,
+			rGetReviewsErr: errors.New("error get reviews by status"),
+			wantErr:        true,
+		},
+		{
+			name:                "failed to get reviews count",
+			ctx:                 fixture.GinCtxBackground(),
+			status:              entity.ReviewStatusPending,
+			rGetReviewsCountErr: errors.New("error get reviews by status count"),
+			wantErr:             true,
+		},
+		{
+			name:    "success",
+			ctx:     fixture.GinCtxBackground(),
+			status:  entity.ReviewStatusPending,
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			reviewRepo := &testmock.ReviewRepositoryInterface{}
+			reviewRepo.On("GetReviewsByStatus", mock.Anything, tc.status, 10, 0).Return([]*entity.Review{{}}, tc.rGetReviewsErr)
+			reviewRepo.On("GetReviewsByStatusCount", mock.Anything, tc.status).Return(1, tc.rGetReviewsCountErr)
+
+			uc := usecase.NewReviewUsecase(&testmock.PostgresTransactionRepositoryInterface{}, &testmock.BookRepositoryInterface{}, &testmock.OrderItemRepositoryInterface{}, reviewRepo, &testmock.Filter{}, newAuditRecorder())
+			_, _, err := uc.GetReviewsByStatus(tc.ctx, tc.status, 10, 0)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestModerateReviews(t *testing.T) {
+	validPayload := &entity.ModerateReviewsPayload{ReviewIDs: []int{1, 2}, Status: entity.ReviewStatusApproved}
+
+	testcases := []struct {
+		name                 string
+		ctx                  *gin.Context
+		payload              *entity.ModerateReviewsPayload
+		rStartTrxErr         error
+		rUpdateStatusErr     error
+		rUpdateBookRatingErr error
+		rRecordErr           error
+		rCommitTrxErr        error
+		wantErr              bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid payload",
+			ctx:     fixture.GinCtxBackground(),
+			payload: &entity.ModerateReviewsPayload{Status: entity.ReviewStatusApproved},
+			wantErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          fixture.GinCtxBackground(),
+			payload:      validPayload,
+			rStartTrxErr: response.ErrNoSQLTransactionFound,
+			wantErr:      true,
+		},
+		{
+			name:             "failed to update reviews status",
+			ctx:              fixture.GinCtxBackground(),
+			payload:          validPayload,
+			rUpdateStatusErr: errors.New("error update reviews status"),
+			wantErr:          true,
+		},
+		{
+			name:                 "failed to update book rating",
+			ctx:                  fixture.GinCtxBackground(),
+			payload:              validPayload,
+			rUpdateBookRatingErr: errors.New("error update book rating"),
+			wantErr:              true,
+		},
+		{
+			name:       "failed to record audit event",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    validPayload,
+			rRecordErr: errors.New("error record audit event"),
+			wantErr:    true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       validPayload,
+			rCommitTrxErr: response.ErrNoSQLTransactionFound,
+			wantErr:       true,
+		},
+		{
+			name:    "success",
+			ctx:     fixture.GinCtxBackground(),
+			payload: validPayload,
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			bookRepo := &testmock.BookRepositoryInterface{}
+			bookRepo.On("UpdateBookRating", mock.Anything, mock.Anything, mock.Anything).Return(tc.rUpdateBookRatingErr)
+
+			reviewRepo := &testmock.ReviewRepositoryInterface{}
+			reviewRepo.On("UpdateReviewsStatus", mock.Anything, mock.Anything, []int{1, 2}, entity.ReviewStatusApproved).Return([]int{7, 8}, tc.rUpdateStatusErr)
+
+			auditRecorder := &testmock.AuditRecorder{}
+			auditRecorder.On("Record", mock.Anything, mock.Anything, mock.Anything).Return(tc.rRecordErr)
+
+			uc := usecase.NewReviewUsecase(dbTransactionRepo, bookRepo, &testmock.OrderItemRepositoryInterface{}, reviewRepo, &testmock.Filter{}, auditRecorder)
+			err := uc.ModerateReviews(tc.ctx, tc.payload)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				bookRepo.AssertNumberOfCalls(t, "UpdateBookRating", 2)
+				auditRecorder.AssertNumberOfCalls(t, "Record", 2)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/helper"
+	repo "github.com/satriowisnugroho/book-store/internal/repository/postgres"
+	"github.com/satriowisnugroho/book-store/pkg/auth"
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
+)
+
+// SigningKeyUsecaseInterface define contract for JWT signing key related functions to usecase
+type SigningKeyUsecaseInterface interface {
+	RotateSigningKeys(ctx context.Context) error
+	GetJWKS(ctx context.Context) (*auth.JWKS, error)
+	ParseAccessToken(tokenString string) (jwt.MapClaims, error)
+}
+
+type SigningKeyUsecase struct {
+	algorithm         string
+	rotationInterval  time.Duration
+	accessTokenTTL    time.Duration
+	keySet            *auth.KeySet
+	dbTransactionRepo repo.PostgresTransactionRepositoryInterface
+	signingKeyRepo    repo.SigningKeyRepositoryInterface
+}
+
+func NewSigningKeyUsecase(
+	algorithm string,
+	rotationInterval time.Duration,
+	accessTokenTTL time.Duration,
+	ks *auth.KeySet,
+	ptr repo.PostgresTransactionRepositoryInterface,
+	skr repo.SigningKeyRepositoryInterface,
+) *SigningKeyUsecase {
+	return &SigningKeyUsecase{
+		algorithm:         algorithm,
+		rotationInterval:  rotationInterval,
+		accessTokenTTL:    accessTokenTTL,
+		keySet:            ks,
+		dbTransactionRepo: ptr,
+		signingKeyRepo:    skr,
+	}
+}
+
+// RotateSigningKeys creates a new signing key once the newest one is older than the rotation interval,
+// deletes the keys which can no longer have signed a valid token, and reloads the key set from the database.
+// A key signs for about one rotation interval, so it is kept for another one plus the lifetime of its tokens
+func (uc *SigningKeyUsecase) RotateSigningKeys(ctx context.Context) error {
+	functionName := "SigningKeyUsecase.RotateSigningKeys"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	if err := uc.signingKeyRepo.LockSigningKeys(ctx, tx); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.signingKeyRepo.LockSigningKeys: %w", err), functionName)
+	}
+
+	keys, err := uc.signingKeyRepo.GetSigningKeys(ctx, tx)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.signingKeyRepo.GetSigningKeys: %w", err), functionName)
+	}
+
+	now := time.Now()
+	if len(keys) == 0 || keys[0].Algorithm != uc.algorithm || !keys[0].CreatedAt.After(now.Add(-uc.rotationInterval)) {
+		signingKey, err := auth.GenerateSigningKey(uc.algorithm)
+		if err != nil {
+			return errors.Wrap(fmt.Errorf("auth.GenerateSigningKey: %w", err), functionName)
+		}
+
+		privateKey, err := auth.MarshalPrivateKey(signingKey.PrivateKey)
+		if err != nil {
+			return errors.Wrap(fmt.Errorf("auth.MarshalPrivateKey: %w", err), functionName)
+		}
+
+		key := &entity.SigningKey{
+			KeyID:      signingKey.ID,
+			Algorithm:  signingKey.Algorithm,
+			PrivateKey: privateKey,
+			CreatedAt:  signingKey.CreatedAt,
+		}
+		if err := uc.signingKeyRepo.CreateSigningKey(ctx, tx, key); err != nil {
+			return errors.Wrap(fmt.Errorf("uc.signingKeyRepo.CreateSigningKey: %w", err), functionName)
+		}
+	}
+
+	retention := 2*uc.rotationInterval + uc.accessTokenTTL
+	if err := uc.signingKeyRepo.DeleteSigningKeysCreatedBefore(ctx, tx, now.Add(-retention)); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.signingKeyRepo.DeleteSigningKeysCreatedBefore: %w", err), functionName)
+	}
+
+	keys, err = uc.signingKeyRepo.GetSigningKeys(ctx, tx)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.signingKeyRepo.GetSigningKeys: %w", err), functionName)
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	signingKeys := make([]*auth.SigningKey, 0, len(keys))
+	for _, key := range keys {
+		privateKey, err := auth.ParsePrivateKey(key.Algorithm, key.PrivateKey)
+		if err != nil {
+			return errors.Wrap(fmt.Errorf("auth.ParsePrivateKey %s: %w", key.KeyID, err), functionName)
+		}
+
+		signingKeys = append(signingKeys, &auth.SigningKey{
+			ID:         key.KeyID,
+			Algorithm:  key.Algorithm,
+			PrivateKey: privateKey,
+			CreatedAt:  key.CreatedAt,
+		})
+	}
+	uc.keySet.SetKeys(signingKeys)
+
+	return nil
+}
+
+// GetJWKS returns the public keys verifying the access tokens
+func (uc *SigningKeyUsecase) GetJWKS(ctx context.Context) (*auth.JWKS, error) {
+	functionName := "SigningKeyUsecase.GetJWKS"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	return uc.keySet.JWKS(), nil
+}
+
+// ParseAccessToken verifies an access token and returns its claims
+func (uc *SigningKeyUsecase) ParseAccessToken(tokenString string) (jwt.MapClaims, error) {
+	return uc.keySet.Parse(tokenString)
+}
@@ -0,0 +1,221 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/satriowisnugroho/book-store/internal/entity"
+	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/auth"
+	"github.com/satriowisnugroho/book-store/test/fixture"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newStoredSigningKey(createdAt time.Time) *entity.SigningKey {
+	key, _ := auth.GenerateSigningKey(auth.SigningAlgorithmEdDSA)
+	privateKey, _ := auth.MarshalPrivateKey(key.PrivateKey)
+
+	return &entity.SigningKey{ID: 1, KeyID: key.ID, Algorithm: key.Algorithm, PrivateKey: privateKey, CreatedAt: createdAt}
+}
+
+func TestRotateSigningKeys(t *testing.T) {
+	currentKey := newStoredSigningKey(time.Now().Add(-time.Hour))
+	dueKey := newStoredSigningKey(time.Now().Add(-48 * time.Hour))
+
+	testcases := []struct {
+		name          string
+		ctx           context.Context
+		algorithm     string
+		rStartTrxErr  error
+		rLockErr      error
+		rKeysRes      []*entity.SigningKey
+		rKeysErr      error
+		rCreateErr    error
+		rDeleteErr    error
+		rCommitTrxErr error
+		wantCreate    bool
+		wantErr       bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          context.Background(),
+			rStartTrxErr: errors.New("error start transaction"),
+			wantErr:      true,
+		},
+		{
+			name:     "failed to lock signing keys",
+			ctx:      context.Background(),
+			rLockErr: errors.New("error lock"),
+			wantErr:  true,
+		},
+		{
+			name:     "failed to get signing keys",
+			ctx:      context.Background(),
+			rKeysErr: errors.New("error get signing keys"),
+			wantErr:  true,
+		},
+		{
+			name:      "unsupported algorithm",
+			ctx:       context.Background(),
+			algorithm: "HS256",
+			rKeysRes:  []*entity.SigningKey{},
+			wantErr:   true,
+		},
+		{
+			name:       "failed to create signing key",
+			ctx:        context.Background(),
+			rKeysRes:   []*entity.SigningKey{},
+			rCreateErr: errors.New("error create signing key"),
+			wantErr:    true,
+		},
+		{
+			name:       "failed to delete retired signing keys",
+			ctx:        context.Background(),
+			rKeysRes:   []*entity.SigningKey{currentKey},
+			rDeleteErr: errors.New("error delete signing keys"),
+			wantErr:    true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           context.Background(),
+			rKeysRes:      []*entity.SigningKey{currentKey},
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantErr:       true,
+		},
+		{
+			name:     "invalid stored private key",
+			ctx:      context.Background(),
+			rKeysRes: []*entity.SigningKey{{KeyID: "kid", Algorithm: auth.SigningAlgorithmEdDSA, PrivateKey: "not a key", CreatedAt: time.Now()}},
+			wantErr:  true,
+		},
+		{
+			name:       "first signing key",
+			ctx:        context.Background(),
+			rKeysRes:   []*entity.SigningKey{},
+			wantCreate: true,
+		},
+		{
+			name:       "signing key due for rotation",
+			ctx:        context.Background(),
+			rKeysRes:   []*entity.SigningKey{dueKey},
+			wantCreate: true,
+		},
+		{
+			name:       "signing algorithm changed",
+			ctx:        context.Background(),
+			algorithm:  auth.SigningAlgorithmRS256,
+			rKeysRes:   []*entity.SigningKey{currentKey},
+			wantCreate: true,
+		},
+		{
+			name:     "signing key not due for rotation",
+			ctx:      context.Background(),
+			rKeysRes: []*entity.SigningKey{currentKey},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			signingKeyRepo := &testmock.SigningKeyRepositoryInterface{}
+			signingKeyRepo.On("LockSigningKeys", mock.Anything, mock.Anything).Return(tc.rLockErr)
+			signingKeyRepo.On("GetSigningKeys", mock.Anything, mock.Anything).Return(tc.rKeysRes, tc.rKeysErr)
+			signingKeyRepo.On("CreateSigningKey", mock.Anything, mock.Anything, mock.Anything).Return(tc.rCreateErr)
+			signingKeyRepo.On("DeleteSigningKeysCreatedBefore", mock.Anything, mock.Anything, mock.Anything).Return(tc.rDeleteErr)
+
+			algorithm := tc.algorithm
+			if algorithm == "" {
+				algorithm = auth.SigningAlgorithmEdDSA
+			}
+
+			keySet := auth.NewKeySet("book-store", "book-store-api", time.Minute)
+			uc := usecase.NewSigningKeyUsecase(algorithm, 24*time.Hour, 15*time.Minute, keySet, dbTransactionRepo, signingKeyRepo)
+			err := uc.RotateSigningKeys(tc.ctx)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+
+			if tc.wantErr {
+				return
+			}
+
+			if tc.wantCreate {
+				signingKeyRepo.AssertCalled(t, "CreateSigningKey", mock.Anything, mock.Anything, mock.MatchedBy(func(key *entity.SigningKey) bool {
+					return key.Algorithm == algorithm && key.KeyID != "" && key.PrivateKey != ""
+				}))
+			} else {
+				signingKeyRepo.AssertNotCalled(t, "CreateSigningKey", mock.Anything, mock.Anything, mock.Anything)
+			}
+
+			signingKeyRepo.AssertCalled(t, "DeleteSigningKeysCreatedBefore", mock.Anything, mock.Anything, mock.MatchedBy(func(createdBefore time.Time) bool {
+				return createdBefore.Before(time.Now().Add(-48 * time.Hour))
+			}))
+
+			jwks, _ := uc.GetJWKS(context.Background())
+			assert.Len(t, jwks.Keys, len(tc.rKeysRes))
+		})
+	}
+}
+
+func TestGetJWKS(t *testing.T) {
+	testcases := []struct {
+		name    string
+		ctx     context.Context
+		wantErr bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name: "success",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, _ := auth.GenerateSigningKey(auth.SigningAlgorithmEdDSA)
+			keySet := auth.NewKeySet("book-store", "book-store-api", time.Minute)
+			keySet.SetKeys([]*auth.SigningKey{key})
+
+			uc := usecase.NewSigningKeyUsecase(auth.SigningAlgorithmEdDSA, time.Hour, time.Minute, keySet, &testmock.PostgresTransactionRepositoryInterface{}, &testmock.SigningKeyRepositoryInterface{})
+			jwks, err := uc.GetJWKS(tc.ctx)
+			assert.Equal(t, tc.wantErr, err != nil)
+
+			if !tc.wantErr {
+				assert.Len(t, jwks.Keys, 1)
+				assert.Equal(t, key.ID, jwks.Keys[0].KeyID)
+			}
+		})
+	}
+}
+
+func TestParseAccessToken(t *testing.T) {
+	key, _ := auth.GenerateSigningKey(auth.SigningAlgorithmEdDSA)
+	keySet := auth.NewKeySet("book-store", "book-store-api", time.Minute)
+	keySet.SetKeys([]*auth.SigningKey{key})
+
+	uc := usecase.NewSigningKeyUsecase(auth.SigningAlgorithmEdDSA, time.Hour, time.Minute, keySet, &testmock.PostgresTransactionRepositoryInterface{}, &testmock.SigningKeyRepositoryInterface{})
+
+	tokenString, _ := keySet.Sign(map[string]interface{}{"user_id": 123, "exp": time.Now().Add(time.Minute).Unix()})
+	claims, err := uc.ParseAccessToken(tokenString)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(123), claims["user_id"])
+
+	_, err = uc.ParseAccessToken("foo")
+	assert.NotNil(t, err)
+}
@@ -3,39 +3,122 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
 	"github.com/pkg/errors"
+	"github.com/satriowisnugroho/book-store/internal/config"
 	"github.com/satriowisnugroho/book-store/internal/entity"
 	"github.com/satriowisnugroho/book-store/internal/helper"
 	repo "github.com/satriowisnugroho/book-store/internal/repository/postgres"
 	"github.com/satriowisnugroho/book-store/internal/response"
 	"github.com/satriowisnugroho/book-store/pkg/auth"
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+	"github.com/satriowisnugroho/book-store/pkg/mailer"
+	"github.com/satriowisnugroho/book-store/pkg/metrics"
+	"github.com/satriowisnugroho/book-store/pkg/oidc"
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
 )
 
 // UserUsecaseInterface define contract for user related functions to usecase
 type UserUsecaseInterface interface {
 	CreateUser(ctx context.Context, payload *entity.RegisterPayload) (*entity.User, error)
-	Login(ctx context.Context, payload *entity.LoginPayload) (*entity.LoginResponse, error)
+	Login(ctx context.Context, ipAddress string, userAgent string, payload *entity.LoginPayload) (*entity.LoginResponse, error)
+	VerifyLoginChallenge(ctx context.Context, ipAddress string, userAgent string, payload *entity.LoginChallengePayload) (*entity.LoginResponse, error)
+	StartOIDCLogin(ctx context.Context) (*entity.OIDCAuthorization, error)
+	OIDCLogin(ctx context.Context, ipAddress string, userAgent string, payload *entity.OIDCCallbackPayload) (*entity.LoginResponse, error)
+	RefreshToken(ctx context.Context, ipAddress string, payload *entity.RefreshTokenPayload) (*entity.LoginResponse, error)
+	Logout(c *gin.Context, payload *entity.RefreshTokenPayload) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	GetSessions(c *gin.Context) ([]*entity.Session, error)
+	RevokeSession(c *gin.Context, sessionID int) error
+	IsSessionRevoked(ctx context.Context, sessionID int) (bool, error)
+	ForgotPassword(ctx context.Context, payload *entity.ForgotPasswordPayload) error
+	ResetPassword(ctx context.Context, payload *entity.ResetPasswordPayload) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerificationEmail(c *gin.Context) error
+	GetProfile(c *gin.Context) (*entity.User, error)
+	UpdateProfile(c *gin.Context, payload *entity.UpdateProfilePayload) (*entity.User, error)
+	ChangePassword(c *gin.Context, payload *entity.ChangePasswordPayload) error
+	DeleteAccount(c *gin.Context) error
+	EnrollTOTP(c *gin.Context) (*entity.TOTPEnrollment, error)
+	ConfirmTOTP(c *gin.Context, payload *entity.TOTPCodePayload) (*entity.RecoveryCodesResponse, error)
+	DisableTOTP(c *gin.Context, payload *entity.DisableTOTPPayload) error
+	DeleteExpiredTokens(ctx context.Context) error
+	DeleteExpiredLoginFailures(ctx context.Context) error
 }
 
 type UserUsecase struct {
-	jwtSecret      string
-	passwordHasher auth.PasswordHasher
-	userRepo       repo.UserRepositoryInterface
+	logger            logger.LoggerInterface
+	accessTokenTTL    time.Duration
+	refreshTokenTTL   time.Duration
+	passwordResetURL  string
+	verificationURL   string
+	tokenSigner       auth.TokenSigner
+	passwordHasher    auth.PasswordHasher
+	mailer            mailer.Mailer
+	dbTransactionRepo repo.PostgresTransactionRepositoryInterface
+	userRepo          repo.UserRepositoryInterface
+	tokenRepo         repo.TokenRepositoryInterface
+	loginFailureRepo  repo.LoginFailureRepositoryInterface
+	mfaRepo           repo.MFARepositoryInterface
+	// identityProvider is nil when OpenID Connect login is disabled
+	identityProvider oidc.IdentityProvider
+	identityRepo     repo.IdentityRepositoryInterface
+	auditor          AuditRecorder
+
+	// dummyHash is compared with the password of an unknown email, so the login takes as long as with a wrong password
+	dummyHash     string
+	dummyHashOnce sync.Once
 }
 
-func NewUserUsecase(js string, ph auth.PasswordHasher, ur repo.UserRepositoryInterface) *UserUsecase {
+func NewUserUsecase(
+	l logger.LoggerInterface,
+	accessTokenTTL time.Duration,
+	refreshTokenTTL time.Duration,
+	passwordResetURL string,
+	verificationURL string,
+	ts auth.TokenSigner,
+	ph auth.PasswordHasher,
+	m mailer.Mailer,
+	ptr repo.PostgresTransactionRepositoryInterface,
+	ur repo.UserRepositoryInterface,
+	tr repo.TokenRepositoryInterface,
+	lfr repo.LoginFailureRepositoryInterface,
+	mr repo.MFARepositoryInterface,
+	ip oidc.IdentityProvider,
+	ir repo.IdentityRepositoryInterface,
+	ar AuditRecorder,
+) *UserUsecase {
 	return &UserUsecase{
-		jwtSecret:      js,
-		passwordHasher: ph,
-		userRepo:       ur,
+		logger:            l,
+		accessTokenTTL:    accessTokenTTL,
+		refreshTokenTTL:   refreshTokenTTL,
+		passwordResetURL:  passwordResetURL,
+		verificationURL:   verificationURL,
+		tokenSigner:       ts,
+		passwordHasher:    ph,
+		mailer:            m,
+		dbTransactionRepo: ptr,
+		userRepo:          ur,
+		tokenRepo:         tr,
+		loginFailureRepo:  lfr,
+		mfaRepo:           mr,
+		identityProvider:  ip,
+		identityRepo:      ir,
+		auditor:           ar,
 	}
 }
 
 func (uc *UserUsecase) CreateUser(ctx context.Context, payload *entity.RegisterPayload) (*entity.User, error) {
 	functionName := "UserUsecase.CreateUser"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
 
 	if err := helper.CheckDeadline(ctx); err != nil {
 		return nil, errors.Wrap(err, functionName)
@@ -54,6 +137,7 @@ func (uc *UserUsecase) CreateUser(ctx context.Context, payload *entity.RegisterP
 	user.Email = payload.Email
 	user.Fullname = payload.Fullname
 	user.CryptedPassword = cryptedPassword
+	user.Role = entity.UserRoleCustomer
 	if err := uc.userRepo.CreateUser(ctx, user); err != nil {
 		if _, ok := err.(response.CustomError); ok {
 			return nil, err
@@ -61,43 +145,1680 @@ func (uc *UserUsecase) CreateUser(ctx context.Context, payload *entity.RegisterP
 
 		return nil, errors.Wrap(fmt.Errorf("uc.userRepo.CreateUser: %w", err), functionName)
 	}
+	metrics.UsersRegisteredTotal.Inc()
+
+	// The account exists whether the email is sent or not, the user can ask for another verification email
+	_ = uc.sendVerificationEmail(ctx, user)
 
 	return user, nil
 }
 
-func (uc *UserUsecase) Login(ctx context.Context, payload *entity.LoginPayload) (*entity.LoginResponse, error) {
+func (uc *UserUsecase) Login(ctx context.Context, ipAddress string, userAgent string, payload *entity.LoginPayload) (*entity.LoginResponse, error) {
 	functionName := "UserUsecase.Login"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
 
 	if err := helper.CheckDeadline(ctx); err != nil {
 		return nil, errors.Wrap(err, functionName)
 	}
 
-	user, err := uc.userRepo.GetUserByEmail(ctx, payload.Email)
-	if err != nil {
+	email := entity.NormalizeEmail(payload.Email)
+	if err := uc.checkLoginLockout(ctx, email, ipAddress); err != nil {
 		if _, ok := err.(response.CustomError); ok {
 			return nil, err
 		}
 
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	user, err := uc.userRepo.GetUserByEmail(ctx, email)
+	if err != nil && err != response.ErrNotFound {
 		return nil, errors.Wrap(fmt.Errorf("uc.userRepo.GetUserByEmail: %w", err), functionName)
 	}
 
-	err = uc.passwordHasher.CompareHashAndPassword(user.CryptedPassword, payload.Password)
+	// An unknown email is compared with a dummy hash and fails with the same error as a wrong password,
+	// so neither the response nor its duration tells whether the email is registered
+	hashedPassword := ""
+	if err == nil {
+		hashedPassword = user.CryptedPassword
+	} else {
+		hashedPassword = uc.getDummyHash()
+	}
+
+	if compareErr := uc.passwordHasher.CompareHashAndPassword(hashedPassword, payload.Password); compareErr != nil || err != nil {
+		if err := uc.loginFailureRepo.CreateLoginFailure(ctx, &entity.LoginFailure{Email: email, IPAddress: ipAddress}); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("uc.loginFailureRepo.CreateLoginFailure: %w", err), functionName)
+		}
+
+		// The typed email is left out of the append-only log, it may be a password or the email of
+		// a user to be erased, so only the ID of a registered user is recorded
+		targetID := ""
+		if user != nil {
+			targetID = strconv.Itoa(user.ID)
+		}
+		recordAuditEvent(ctx, uc.auditor, uc.logger, functionName, entity.NewAuditEvent(entity.AuditActionLoginFailed, entity.AuditTargetUser, targetID, entity.AuditDiff{
+			"reason": {To: "invalid_credentials"},
+		}))
+		metrics.LoginsTotal.WithLabelValues(metrics.LoginFailed).Inc()
+
+		return nil, response.ErrInvalidCredentials
+	}
+
+	// Upgrade a hash of a legacy algorithm or of weaker parameters now that the password is known.
+	// The login goes on when the upgrade fails, the next login tries again
+	if uc.passwordHasher.NeedsRehash(user.CryptedPassword) {
+		if cryptedPassword, err := uc.passwordHasher.GenerateFromPassword(payload.Password); err == nil {
+			_ = uc.userRepo.UpdateUserPassword(ctx, nil, user.ID, cryptedPassword)
+		}
+	}
+
+	credential, err := uc.mfaRepo.GetTOTPCredential(ctx, nil, user.ID)
+	if err != nil && err != response.ErrNotFound {
+		return nil, errors.Wrap(fmt.Errorf("uc.mfaRepo.GetTOTPCredential: %w", err), functionName)
+	}
+
+	// The failures are kept until the second factor is given too, so the wrong codes count towards the lockout
+	if err == nil && credential.IsEnabled() {
+		resp, err := uc.issueLoginChallenge(ctx, user)
+		if err != nil {
+			return nil, errors.Wrap(err, functionName)
+		}
+
+		return resp, nil
+	}
+
+	// The failures of the IP address are kept, logging into an own account must not unlock the others
+	_ = uc.loginFailureRepo.DeleteLoginFailuresByEmail(ctx, email)
+
+	resp, err := uc.startSession(ctx, nil, user, ipAddress, userAgent)
 	if err != nil {
-		return nil, response.ErrInvalidPassword
+		return nil, errors.Wrap(err, functionName)
 	}
+	metrics.LoginsTotal.WithLabelValues(metrics.LoginSucceeded).Inc()
 
-	// Generate JWT token
-	claims := jwt.MapClaims{
-		"user_id":  user.ID,
-		"email":    user.Email,
-		"fullname": user.Fullname,
-		"exp":      time.Now().Add(24 * time.Hour).Unix(),
+	return resp, nil
+}
+
+// VerifyLoginChallenge completes a login with two-factor authentication, the challenge token given by Login is exchanged
+// for an access token and a refresh token together with a TOTP code or an unused recovery code
+func (uc *UserUsecase) VerifyLoginChallenge(ctx context.Context, ipAddress string, userAgent string, payload *entity.LoginChallengePayload) (*entity.LoginResponse, error) {
+	functionName := "UserUsecase.VerifyLoginChallenge"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if payload.ChallengeToken == "" {
+		return nil, response.ErrUnauthorized("Invalid or expired challenge token")
+	}
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	challenge, err := uc.tokenRepo.GetLoginChallengeByHash(ctx, tx, auth.HashToken(payload.ChallengeToken))
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, response.ErrUnauthorized("Invalid or expired challenge token")
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.tokenRepo.GetLoginChallengeByHash: %w", err), functionName)
+	}
+
+	if !challenge.IsUsable(time.Now()) {
+		return nil, response.ErrUnauthorized("Invalid or expired challenge token")
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, response.ErrUnauthorized("Invalid or expired challenge token")
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.userRepo.GetUserByID: %w", err), functionName)
+	}
+
+	if err := uc.checkLoginLockout(ctx, user.Email, ipAddress); err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	credential, err := uc.mfaRepo.GetTOTPCredential(ctx, tx, user.ID)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, response.ErrUnauthorized("Invalid or expired challenge token")
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.mfaRepo.GetTOTPCredential: %w", err), functionName)
+	}
+
+	if !credential.IsEnabled() {
+		return nil, response.ErrUnauthorized("Invalid or expired challenge token")
+	}
+
+	verified, err := uc.verifyMFACode(ctx, tx, credential, payload.Code)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if !verified {
+		if err := uc.loginFailureRepo.CreateLoginFailure(ctx, &entity.LoginFailure{Email: user.Email, IPAddress: ipAddress}); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("uc.loginFailureRepo.CreateLoginFailure: %w", err), functionName)
+		}
+
+		// The transaction is rolled back, the failure is written on its own
+		recordAuditEvent(ctx, uc.auditor, uc.logger, functionName, entity.NewAuditEvent(entity.AuditActionLoginFailed, entity.AuditTargetUser, strconv.Itoa(user.ID), entity.AuditDiff{
+			"reason": {To: "invalid_mfa_code"},
+		}))
+		metrics.LoginsTotal.WithLabelValues(metrics.LoginFailed).Inc()
+
+		return nil, response.ErrInvalidMFACode
+	}
+
+	if err := uc.tokenRepo.UseLoginChallenge(ctx, tx, challenge.ID); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.tokenRepo.UseLoginChallenge: %w", err), functionName)
+	}
+
+	resp, err := uc.startSession(ctx, tx, user, ipAddress, userAgent)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+	metrics.LoginsTotal.WithLabelValues(metrics.LoginSucceeded).Inc()
+
+	_ = uc.loginFailureRepo.DeleteLoginFailuresByEmail(ctx, user.Email)
+
+	return resp, nil
+}
+
+// StartOIDCLogin starts a login at the OpenID Connect provider and returns the URL the user is sent to.
+// The state, the nonce and the PKCE code verifier are kept until OIDCLogin completes the login
+func (uc *UserUsecase) StartOIDCLogin(ctx context.Context) (*entity.OIDCAuthorization, error) {
+	functionName := "UserUsecase.StartOIDCLogin"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if uc.identityProvider == nil {
+		return nil, response.ErrOIDCNotEnabled
+	}
+
+	state, err := auth.GenerateToken()
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("auth.GenerateToken: %w", err), functionName)
+	}
+
+	nonce, err := auth.GenerateToken()
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("auth.GenerateToken: %w", err), functionName)
+	}
+
+	codeVerifier, err := oidc.GenerateCodeVerifier()
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("oidc.GenerateCodeVerifier: %w", err), functionName)
+	}
+
+	authorizationURL, err := uc.identityProvider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallengeS256(codeVerifier))
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.identityProvider.AuthCodeURL: %w", err), functionName)
+	}
+
+	loginState := &entity.OIDCLoginState{
+		StateHash:    auth.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    time.Now().Add(config.OIDCLoginStateTTL),
+	}
+	if err := uc.tokenRepo.CreateOIDCLoginState(ctx, loginState); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.tokenRepo.CreateOIDCLoginState: %w", err), functionName)
+	}
+
+	return &entity.OIDCAuthorization{AuthorizationURL: authorizationURL}, nil
+}
+
+// OIDCLogin completes a login at the OpenID Connect provider with the state and the authorization code of the redirect.
+// The account at the provider logs into the user it is linked to, else it is linked to the user of its verified email
+// or a new user is registered. Users with two-factor authentication are given a login challenge like with Login
+func (uc *UserUsecase) OIDCLogin(ctx context.Context, ipAddress string, userAgent string, payload *entity.OIDCCallbackPayload) (*entity.LoginResponse, error) {
+	functionName := "UserUsecase.OIDCLogin"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if uc.identityProvider == nil {
+		return nil, response.ErrOIDCNotEnabled
+	}
+
+	if payload.State == "" || payload.Code == "" {
+		return nil, response.ErrUnauthorized("Invalid or expired login state")
+	}
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	loginState, err := uc.tokenRepo.GetOIDCLoginStateByHash(ctx, tx, auth.HashToken(payload.State))
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, response.ErrUnauthorized("Invalid or expired login state")
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.tokenRepo.GetOIDCLoginStateByHash: %w", err), functionName)
+	}
+
+	if !loginState.IsUsable(time.Now()) {
+		return nil, response.ErrUnauthorized("Invalid or expired login state")
+	}
+
+	// The state is used up before the code is exchanged, an authorization code can't be redeemed twice anyway
+	if err := uc.tokenRepo.UseOIDCLoginState(ctx, tx, loginState.ID); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.tokenRepo.UseOIDCLoginState: %w", err), functionName)
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	claims, err := uc.identityProvider.Exchange(ctx, payload.Code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		if errors.Is(err, oidc.ErrTokenExchange) || errors.Is(err, oidc.ErrInvalidIDToken) {
+			return nil, response.ErrUnauthorized("Login at the identity provider failed")
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.identityProvider.Exchange: %w", err), functionName)
+	}
+
+	user, err := uc.getOIDCUser(ctx, claims)
+	if err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	credential, err := uc.mfaRepo.GetTOTPCredential(ctx, nil, user.ID)
+	if err != nil && err != response.ErrNotFound {
+		return nil, errors.Wrap(fmt.Errorf("uc.mfaRepo.GetTOTPCredential: %w", err), functionName)
+	}
+
+	if err == nil && credential.IsEnabled() {
+		resp, err := uc.issueLoginChallenge(ctx, user)
+		if err != nil {
+			return nil, errors.Wrap(err, functionName)
+		}
+
+		return resp, nil
+	}
+
+	resp, err := uc.startSession(ctx, nil, user, ipAddress, userAgent)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+	metrics.LoginsTotal.WithLabelValues(metrics.LoginSucceeded).Inc()
+
+	return resp, nil
+}
+
+// RefreshToken rotates a refresh token into a new access token and refresh token. A refresh token can be used once,
+// using it again means it leaked so every refresh token rotated from the same login is revoked
+func (uc *UserUsecase) RefreshToken(ctx context.Context, ipAddress string, payload *entity.RefreshTokenPayload) (*entity.LoginResponse, error) {
+	functionName := "UserUsecase.RefreshToken"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if payload.RefreshToken == "" {
+		return nil, response.ErrUnauthorized("Invalid refresh token")
+	}
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	refreshToken, err := uc.tokenRepo.GetRefreshTokenByHash(ctx, tx, auth.HashToken(payload.RefreshToken))
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, response.ErrUnauthorized("Invalid refresh token")
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.tokenRepo.GetRefreshTokenByHash: %w", err), functionName)
+	}
+
+	session, err := uc.tokenRepo.GetSessionByFamilyID(ctx, tx, refreshToken.FamilyID)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, response.ErrUnauthorized("Invalid refresh token")
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.tokenRepo.GetSessionByFamilyID: %w", err), functionName)
+	}
+
+	// Signing a session out revokes its refresh tokens as well, which must not be mistaken for a reuse
+	if session.RevokedAt != nil {
+		return nil, response.ErrUnauthorized("Session has been revoked, please login again")
+	}
+
+	if refreshToken.RevokedAt != nil {
+		if err := uc.tokenRepo.RevokeRefreshTokenFamily(ctx, tx, refreshToken.FamilyID); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("uc.tokenRepo.RevokeRefreshTokenFamily: %w", err), functionName)
+		}
+
+		// Keep the revocation of the family even though the request fails
+		if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+		}
+		rollbackProcess = false
+
+		return nil, response.ErrUnauthorized("Refresh token reuse detected, please login again")
+	}
+
+	if refreshToken.IsExpired(time.Now()) {
+		return nil, response.ErrUnauthorized("Refresh token has expired, please login again")
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, refreshToken.UserID)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, response.ErrUnauthorized("Invalid refresh token")
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.userRepo.GetUserByID: %w", err), functionName)
+	}
+
+	if _, err := uc.tokenRepo.RevokeRefreshToken(ctx, tx, refreshToken.ID); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.tokenRepo.RevokeRefreshToken: %w", err), functionName)
+	}
+
+	now := time.Now()
+	if err := uc.tokenRepo.TouchSession(ctx, tx, session.ID, ipAddress, now.Add(uc.refreshTokenTTL)); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.tokenRepo.TouchSession: %w", err), functionName)
+	}
+
+	resp, err := uc.issueTokens(ctx, tx, user, session)
+	if err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	return resp, nil
+}
+
+// Logout revokes the access token of the request until it expires, and the refresh token of the session when given
+func (uc *UserUsecase) Logout(c *gin.Context, payload *entity.RefreshTokenPayload) error {
+	functionName := "UserUsecase.Logout"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if err := uc.tokenRepo.RevokeAccessToken(ctx, helper.GetTokenIDFromContext(c), helper.GetTokenExpiresAtFromContext(c)); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.tokenRepo.RevokeAccessToken: %w", err), functionName)
+	}
+
+	if payload.RefreshToken == "" {
+		return nil
+	}
+
+	refreshToken, err := uc.tokenRepo.GetRefreshTokenByHash(ctx, nil, auth.HashToken(payload.RefreshToken))
+	if err != nil {
+		// Logging out with an unknown refresh token is not an error, the session is gone either way
+		if err == response.ErrNotFound {
+			return nil
+		}
+
+		return errors.Wrap(fmt.Errorf("uc.tokenRepo.GetRefreshTokenByHash: %w", err), functionName)
+	}
+
+	if refreshToken.UserID != helper.GetUserIDFromContext(c) {
+		return nil
+	}
+
+	if err := uc.tokenRepo.RevokeRefreshTokenFamily(ctx, nil, refreshToken.FamilyID); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.tokenRepo.RevokeRefreshTokenFamily: %w", err), functionName)
+	}
+
+	return nil
+}
+
+// IsAccessTokenRevoked reports whether the access token was revoked by a logout
+func (uc *UserUsecase) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	functionName := "UserUsecase.IsAccessTokenRevoked"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	revoked, err := uc.tokenRepo.IsAccessTokenRevoked(ctx, jti)
+	if err != nil {
+		return false, errors.Wrap(fmt.Errorf("uc.tokenRepo.IsAccessTokenRevoked: %w", err), functionName)
+	}
+
+	return revoked, nil
+}
+
+// GetSessions lists the active sessions of the logged in user, the session of the request is marked as current
+func (uc *UserUsecase) GetSessions(c *gin.Context) ([]*entity.Session, error) {
+	functionName := "UserUsecase.GetSessions"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	sessions, err := uc.tokenRepo.GetSessionsByUserID(ctx, helper.GetUserIDFromContext(c))
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.tokenRepo.GetSessionsByUserID: %w", err), functionName)
+	}
+
+	currentSessionID := helper.GetSessionIDFromContext(c)
+	for _, session := range sessions {
+		session.Current = session.ID == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession signs a session of the logged in user out. Its refresh tokens are revoked
+// and its access tokens are rejected from then on
+func (uc *UserUsecase) RevokeSession(c *gin.Context, sessionID int) error {
+	functionName := "UserUsecase.RevokeSession"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	if err := uc.tokenRepo.RevokeSession(ctx, tx, helper.GetUserIDFromContext(c), sessionID); err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return err
+		}
+
+		return errors.Wrap(fmt.Errorf("uc.tokenRepo.RevokeSession: %w", err), functionName)
+	}
+
+	if err := uc.auditor.Record(ctx, tx, entity.NewAuditEvent(entity.AuditActionSessionRevoked, entity.AuditTargetSession, strconv.Itoa(sessionID), nil)); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.auditor.Record: %w", err), functionName)
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	return nil
+}
+
+// IsSessionRevoked reports whether the session of an access token was signed out
+func (uc *UserUsecase) IsSessionRevoked(ctx context.Context, sessionID int) (bool, error) {
+	functionName := "UserUsecase.IsSessionRevoked"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return false, errors.Wrap(err, functionName)
+	}
+
+	revoked, err := uc.tokenRepo.IsSessionRevoked(ctx, sessionID)
+	if err != nil {
+		return false, errors.Wrap(fmt.Errorf("uc.tokenRepo.IsSessionRevoked: %w", err), functionName)
+	}
+
+	return revoked, nil
+}
+
+// ForgotPassword emails a single use password reset link to the user. It succeeds for an unknown email as well,
+// so the response does not tell whether an email is registered
+func (uc *UserUsecase) ForgotPassword(ctx context.Context, payload *entity.ForgotPasswordPayload) error {
+	functionName := "UserUsecase.ForgotPassword"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if err := payload.Validate(); err != nil {
+		return err
+	}
+
+	user, err := uc.userRepo.GetUserByEmail(ctx, payload.Email)
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil
+		}
+
+		return errors.Wrap(fmt.Errorf("uc.userRepo.GetUserByEmail: %w", err), functionName)
+	}
+
+	tokenStr, err := auth.GenerateToken()
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("auth.GenerateToken: %w", err), functionName)
+	}
+
+	resetToken := &entity.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: auth.HashToken(tokenStr),
+		ExpiresAt: time.Now().Add(config.PasswordResetTokenTTL),
+	}
+	if err := uc.tokenRepo.CreatePasswordResetToken(ctx, resetToken); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.tokenRepo.CreatePasswordResetToken: %w", err), functionName)
+	}
+
+	resetURL, err := url.Parse(uc.passwordResetURL)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("url.Parse: %w", err), functionName)
+	}
+	query := resetURL.Query()
+	query.Set("token", tokenStr)
+	resetURL.RawQuery = query.Encode()
+
+	msg := &mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your Book Store password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe received a request to reset the password of your Book Store account. "+
+				"Open the link below within %d minutes to choose a new password:\n\n%s\n\n"+
+				"If you did not request a password reset, you can ignore this email.\n",
+			user.Fullname,
+			int(config.PasswordResetTokenTTL.Minutes()),
+			resetURL.String(),
+		),
+	}
+	if err := uc.mailer.Send(ctx, msg); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.mailer.Send: %w", err), functionName)
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password with a password reset token. Every reset token of the user is used up
+// and every refresh token is revoked, so a session opened with the old password cannot be renewed
+func (uc *UserUsecase) ResetPassword(ctx context.Context, payload *entity.ResetPasswordPayload) error {
+	functionName := "UserUsecase.ResetPassword"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if err := payload.Validate(); err != nil {
+		return err
+	}
+
+	// Hash the password before locking the token, hashing is slow on purpose
+	cryptedPassword, err := uc.passwordHasher.GenerateFromPassword(payload.Password)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.passwordHasher.GenerateFromPassword: %w", err), functionName)
+	}
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	resetToken, err := uc.tokenRepo.GetPasswordResetTokenByHash(ctx, tx, auth.HashToken(payload.Token))
+	if err != nil {
+		if err == response.ErrNotFound {
+			return response.ErrInvalidPasswordResetToken
+		}
+
+		return errors.Wrap(fmt.Errorf("uc.tokenRepo.GetPasswordResetTokenByHash: %w", err), functionName)
+	}
+
+	if !resetToken.IsUsable(time.Now()) {
+		return response.ErrInvalidPasswordResetToken
+	}
+
+	if err := uc.userRepo.UpdateUserPassword(ctx, tx, resetToken.UserID, cryptedPassword); err != nil {
+		if err == response.ErrNotFound {
+			return response.ErrInvalidPasswordResetToken
+		}
+
+		return errors.Wrap(fmt.Errorf("uc.userRepo.UpdateUserPassword: %w", err), functionName)
+	}
+
+	if err := uc.tokenRepo.UsePasswordResetTokens(ctx, tx, resetToken.UserID); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.tokenRepo.UsePasswordResetTokens: %w", err), functionName)
+	}
+
+	if err := uc.tokenRepo.RevokeUserRefreshTokens(ctx, tx, resetToken.UserID, 0); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.tokenRepo.RevokeUserRefreshTokens: %w", err), functionName)
+	}
+
+	// The link holder isn't logged in, the user the link was sent to is the actor
+	event := entity.NewAuditEvent(entity.AuditActionPasswordReset, entity.AuditTargetUser, strconv.Itoa(resetToken.UserID), nil)
+	event.ActorID = resetToken.UserID
+	if err := uc.auditor.Record(ctx, tx, event); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.auditor.Record: %w", err), functionName)
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	return nil
+}
+
+// VerifyEmail marks the email of the user as verified with an email verification token, or replaces it with the
+// new email of the token. Every verification token of the user is used up
+func (uc *UserUsecase) VerifyEmail(ctx context.Context, token string) error {
+	functionName := "UserUsecase.VerifyEmail"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if token == "" {
+		return response.ErrInvalidEmailVerificationToken
+	}
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	verificationToken, err := uc.tokenRepo.GetEmailVerificationTokenByHash(ctx, tx, auth.HashToken(token))
+	if err != nil {
+		if err == response.ErrNotFound {
+			return response.ErrInvalidEmailVerificationToken
+		}
+
+		return errors.Wrap(fmt.Errorf("uc.tokenRepo.GetEmailVerificationTokenByHash: %w", err), functionName)
+	}
+
+	if !verificationToken.IsUsable(time.Now()) {
+		return response.ErrInvalidEmailVerificationToken
+	}
+
+	if verificationToken.NewEmail != nil {
+		if err := uc.userRepo.ChangeUserEmail(ctx, tx, verificationToken.UserID, *verificationToken.NewEmail); err != nil {
+			if _, ok := err.(response.CustomError); ok {
+				return err
+			}
+
+			return errors.Wrap(fmt.Errorf("uc.userRepo.ChangeUserEmail: %w", err), functionName)
+		}
+	} else if err := uc.userRepo.VerifyUser(ctx, tx, verificationToken.UserID); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.userRepo.VerifyUser: %w", err), functionName)
+	}
+
+	if err := uc.tokenRepo.UseEmailVerificationTokens(ctx, tx, verificationToken.UserID); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.tokenRepo.UseEmailVerificationTokens: %w", err), functionName)
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	return nil
+}
+
+// ResendVerificationEmail emails a new verification link to the logged in user,
+// at most EmailVerificationResendLimit emails are sent within EmailVerificationResendWindow
+func (uc *UserUsecase) ResendVerificationEmail(c *gin.Context) error {
+	functionName := "UserUsecase.ResendVerificationEmail"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, helper.GetUserIDFromContext(c))
+	if err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return err
+		}
+
+		return errors.Wrap(fmt.Errorf("uc.userRepo.GetUserByID: %w", err), functionName)
+	}
+
+	if user.IsVerified() {
+		return response.ErrEmailAlreadyVerified
+	}
+
+	if err := uc.checkVerificationEmailLimit(ctx, user.ID); err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return err
+		}
+
+		return errors.Wrap(err, functionName)
+	}
+
+	if err := uc.sendVerificationEmail(ctx, user); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	return nil
+}
+
+// GetProfile returns the logged in user
+func (uc *UserUsecase) GetProfile(c *gin.Context) (*entity.User, error) {
+	functionName := "UserUsecase.GetProfile"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, helper.GetUserIDFromContext(c))
+	if err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.userRepo.GetUserByID: %w", err), functionName)
+	}
+
+	return user, nil
+}
+
+// UpdateProfile changes the fullname of the logged in user at once. A new email needs the current password and
+// is kept pending until it is verified, the link is emailed to the new email and a notice of the change to the current one.
+// The verification links sent before are used up, so only the latest change can be verified
+func (uc *UserUsecase) UpdateProfile(c *gin.Context, payload *entity.UpdateProfilePayload) (*entity.User, error) {
+	functionName := "UserUsecase.UpdateProfile"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, helper.GetUserIDFromContext(c))
+	if err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.userRepo.GetUserByID: %w", err), functionName)
+	}
+
+	if payload.Fullname != nil {
+		user.Fullname = *payload.Fullname
+	}
+
+	emailChanged := payload.Email != nil && !strings.EqualFold(*payload.Email, user.Email)
+	if emailChanged {
+		if err := uc.passwordHasher.CompareHashAndPassword(user.CryptedPassword, payload.CurrentPassword); err != nil {
+			return nil, response.ErrInvalidPassword
+		}
+
+		if err := uc.checkVerificationEmailLimit(ctx, user.ID); err != nil {
+			if _, ok := err.(response.CustomError); ok {
+				return nil, err
+			}
+
+			return nil, errors.Wrap(err, functionName)
+		}
+
+		if _, err := uc.userRepo.GetUserByEmail(ctx, *payload.Email); err != response.ErrNotFound {
+			if err == nil {
+				return nil, response.ErrDuplicateEmail
+			}
+
+			return nil, errors.Wrap(fmt.Errorf("uc.userRepo.GetUserByEmail: %w", err), functionName)
+		}
+	}
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	if err := uc.userRepo.UpdateUser(ctx, tx, user); err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.userRepo.UpdateUser: %w", err), functionName)
+	}
+
+	if emailChanged {
+		if err := uc.tokenRepo.UseEmailVerificationTokens(ctx, tx, user.ID); err != nil {
+			return nil, errors.Wrap(fmt.Errorf("uc.tokenRepo.UseEmailVerificationTokens: %w", err), functionName)
+		}
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	if emailChanged {
+		if err := uc.sendEmailChangeEmails(ctx, user, *payload.Email); err != nil {
+			return nil, errors.Wrap(err, functionName)
+		}
+	}
+
+	return user, nil
+}
+
+// ChangePassword sets a new password for the logged in user after checking the current one.
+// Every password reset token and the refresh tokens of the other sessions of the user are revoked,
+// the other sessions have to login again
+func (uc *UserUsecase) ChangePassword(c *gin.Context, payload *entity.ChangePasswordPayload) error {
+	functionName := "UserUsecase.ChangePassword"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if err := payload.Validate(); err != nil {
+		return err
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, helper.GetUserIDFromContext(c))
+	if err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return err
+		}
+
+		return errors.Wrap(fmt.Errorf("uc.userRepo.GetUserByID: %w", err), functionName)
+	}
+
+	if err := uc.passwordHasher.CompareHashAndPassword(user.CryptedPassword, payload.CurrentPassword); err != nil {
+		return response.ErrInvalidPassword
+	}
+
+	cryptedPassword, err := uc.passwordHasher.GenerateFromPassword(payload.NewPassword)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.passwordHasher.GenerateFromPassword: %w", err), functionName)
+	}
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	if err := uc.userRepo.UpdateUserPassword(ctx, tx, user.ID, cryptedPassword); err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return err
+		}
+
+		return errors.Wrap(fmt.Errorf("uc.userRepo.UpdateUserPassword: %w", err), functionName)
+	}
+
+	if err := uc.tokenRepo.UsePasswordResetTokens(ctx, tx, user.ID); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.tokenRepo.UsePasswordResetTokens: %w", err), functionName)
+	}
+
+	// The other sessions are signed out, the session changing the password is kept
+	if err := uc.tokenRepo.RevokeUserRefreshTokens(ctx, tx, user.ID, helper.GetSessionIDFromContext(c)); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.tokenRepo.RevokeUserRefreshTokens: %w", err), functionName)
+	}
+
+	event := entity.NewAuditEvent(entity.AuditActionPasswordChanged, entity.AuditTargetUser, strconv.Itoa(user.ID), nil)
+	event.ActorID = user.ID
+	if err := uc.auditor.Record(ctx, tx, event); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.auditor.Record: %w", err), functionName)
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	return nil
+}
+
+// DeleteAccount soft deletes the logged in user, unlinks the OpenID Connect accounts and logs out every session.
+// The orders and reviews are kept, and the email can be registered again
+func (uc *UserUsecase) DeleteAccount(c *gin.Context) error {
+	functionName := "UserUsecase.DeleteAccount"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	userID := helper.GetUserIDFromContext(c)
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	if err := uc.userRepo.DeleteUser(ctx, tx, userID); err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return err
+		}
+
+		return errors.Wrap(fmt.Errorf("uc.userRepo.DeleteUser: %w", err), functionName)
+	}
+
+	if err := uc.tokenRepo.RevokeUserRefreshTokens(ctx, tx, userID, 0); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.tokenRepo.RevokeUserRefreshTokens: %w", err), functionName)
+	}
+
+	if err := uc.identityRepo.DeleteUserIdentities(ctx, tx, userID); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.identityRepo.DeleteUserIdentities: %w", err), functionName)
+	}
+
+	if err := uc.auditor.Record(ctx, tx, entity.NewAuditEvent(entity.AuditActionUserDeleted, entity.AuditTargetUser, strconv.Itoa(userID), nil)); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.auditor.Record: %w", err), functionName)
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	if err := uc.tokenRepo.RevokeAccessToken(ctx, helper.GetTokenIDFromContext(c), helper.GetTokenExpiresAtFromContext(c)); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.tokenRepo.RevokeAccessToken: %w", err), functionName)
+	}
+
+	return nil
+}
+
+// EnrollTOTP starts the TOTP enrolment of the logged in user with a new secret, replacing the secret of an unconfirmed enrolment.
+// The login asks for a code once the enrolment is confirmed with ConfirmTOTP
+func (uc *UserUsecase) EnrollTOTP(c *gin.Context) (*entity.TOTPEnrollment, error) {
+	functionName := "UserUsecase.EnrollTOTP"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, helper.GetUserIDFromContext(c))
+	if err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.userRepo.GetUserByID: %w", err), functionName)
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("auth.GenerateTOTPSecret: %w", err), functionName)
+	}
+
+	if err := uc.mfaRepo.UpsertTOTPCredential(ctx, &entity.TOTPCredential{UserID: user.ID, Secret: secret}); err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return nil, err
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.mfaRepo.UpsertTOTPCredential: %w", err), functionName)
+	}
+
+	return &entity.TOTPEnrollment{
+		Secret: secret,
+		URI:    auth.TOTPURI(config.TOTPIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables TOTP for the logged in user given a code of the enrolled secret, and returns new recovery codes.
+// The recovery codes are shown once, only their hashes are stored
+func (uc *UserUsecase) ConfirmTOTP(c *gin.Context, payload *entity.TOTPCodePayload) (*entity.RecoveryCodesResponse, error) {
+	functionName := "UserUsecase.ConfirmTOTP"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return nil, errors.Wrap(err, functionName)
+	}
+
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	credential, err := uc.mfaRepo.GetTOTPCredential(ctx, tx, helper.GetUserIDFromContext(c))
+	if err != nil {
+		if err == response.ErrNotFound {
+			return nil, response.ErrTOTPNotEnrolled
+		}
+
+		return nil, errors.Wrap(fmt.Errorf("uc.mfaRepo.GetTOTPCredential: %w", err), functionName)
+	}
+
+	if credential.IsEnabled() {
+		return nil, response.ErrTOTPAlreadyEnabled
+	}
+
+	step, ok := auth.ValidateTOTPCode(credential.Secret, payload.Code, time.Now())
+	if !ok {
+		return nil, response.ErrInvalidMFACode
+	}
+
+	if err := uc.mfaRepo.EnableTOTPCredential(ctx, tx, credential.UserID, step); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.mfaRepo.EnableTOTPCredential: %w", err), functionName)
+	}
+
+	recoveryCodes := make([]string, 0, config.RecoveryCodeCount)
+	codeHashes := make([]string, 0, config.RecoveryCodeCount)
+	for i := 0; i < config.RecoveryCodeCount; i++ {
+		code, err := auth.GenerateRecoveryCode()
+		if err != nil {
+			return nil, errors.Wrap(fmt.Errorf("auth.GenerateRecoveryCode: %w", err), functionName)
+		}
+
+		recoveryCodes = append(recoveryCodes, code)
+		codeHashes = append(codeHashes, auth.HashToken(auth.NormalizeRecoveryCode(code)))
+	}
+
+	if err := uc.mfaRepo.ReplaceRecoveryCodes(ctx, tx, credential.UserID, codeHashes); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.mfaRepo.ReplaceRecoveryCodes: %w", err), functionName)
+	}
+
+	if err := uc.auditor.Record(ctx, tx, entity.NewAuditEvent(entity.AuditActionTOTPEnabled, entity.AuditTargetUser, strconv.Itoa(credential.UserID), nil)); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.auditor.Record: %w", err), functionName)
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return nil, errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	return &entity.RecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// DisableTOTP turns off two-factor authentication of the logged in user given the password,
+// the TOTP secret and the recovery codes are deleted
+func (uc *UserUsecase) DisableTOTP(c *gin.Context, payload *entity.DisableTOTPPayload) error {
+	functionName := "UserUsecase.DisableTOTP"
+	ctx, span := tracing.Start(c.Request.Context(), functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	user, err := uc.userRepo.GetUserByID(ctx, helper.GetUserIDFromContext(c))
+	if err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return err
+		}
+
+		return errors.Wrap(fmt.Errorf("uc.userRepo.GetUserByID: %w", err), functionName)
+	}
+
+	if err := uc.passwordHasher.CompareHashAndPassword(user.CryptedPassword, payload.Password); err != nil {
+		return response.ErrInvalidPassword
+	}
+
+	tx, err := uc.dbTransactionRepo.StartTransactionQuery(ctx)
+	if err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.StartTransactionQuery: %w", err), functionName)
+	}
+
+	// Create flag and defer rollback when flag is true
+	rollbackProcess := true
+	defer func() {
+		if rollbackProcess {
+			uc.dbTransactionRepo.RollbackTransactionQuery(ctx, tx)
+		}
+	}()
+
+	if _, err := uc.mfaRepo.GetTOTPCredential(ctx, tx, user.ID); err != nil {
+		if err == response.ErrNotFound {
+			return response.ErrTOTPNotEnrolled
+		}
+
+		return errors.Wrap(fmt.Errorf("uc.mfaRepo.GetTOTPCredential: %w", err), functionName)
+	}
+
+	if err := uc.mfaRepo.DeleteTOTPCredential(ctx, tx, user.ID); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.mfaRepo.DeleteTOTPCredential: %w", err), functionName)
+	}
+
+	if err := uc.mfaRepo.DeleteRecoveryCodes(ctx, tx, user.ID); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.mfaRepo.DeleteRecoveryCodes: %w", err), functionName)
+	}
+
+	if err := uc.auditor.Record(ctx, tx, entity.NewAuditEvent(entity.AuditActionTOTPDisabled, entity.AuditTargetUser, strconv.Itoa(user.ID), nil)); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.auditor.Record: %w", err), functionName)
+	}
+
+	if err = uc.dbTransactionRepo.CommitTransactionQuery(ctx, tx); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.dbTransactionRepo.CommitTransactionQuery: %w", err), functionName)
+	}
+	rollbackProcess = false
+
+	return nil
+}
+
+// DeleteExpiredTokens deletes the tokens which can no longer be used anyway
+func (uc *UserUsecase) DeleteExpiredTokens(ctx context.Context) error {
+	functionName := "UserUsecase.DeleteExpiredTokens"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if err := uc.tokenRepo.DeleteExpiredTokens(ctx); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.tokenRepo.DeleteExpiredTokens: %w", err), functionName)
+	}
+
+	return nil
+}
+
+// DeleteExpiredLoginFailures deletes the failed logins which no longer count towards a lockout
+func (uc *UserUsecase) DeleteExpiredLoginFailures(ctx context.Context) error {
+	functionName := "UserUsecase.DeleteExpiredLoginFailures"
+	ctx, span := tracing.Start(ctx, functionName)
+	defer span.End()
+
+	if err := helper.CheckDeadline(ctx); err != nil {
+		return errors.Wrap(err, functionName)
+	}
+
+	if err := uc.loginFailureRepo.DeleteLoginFailuresBefore(ctx, time.Now().Add(-config.LoginFailureWindow)); err != nil {
+		return errors.Wrap(fmt.Errorf("uc.loginFailureRepo.DeleteLoginFailuresBefore: %w", err), functionName)
+	}
+
+	return nil
+}
+
+// checkLoginLockout returns ErrTooManyLoginAttempts while the email or the IP address is locked out by its failed logins.
+// The email is locked out whether it is registered or not
+func (uc *UserUsecase) checkLoginLockout(ctx context.Context, email string, ipAddress string) error {
+	now := time.Now()
+	since := now.Add(-config.LoginFailureWindow)
+
+	emailFailures, err := uc.loginFailureRepo.GetLoginFailureSummaryByEmail(ctx, email, since)
+	if err != nil {
+		return fmt.Errorf("uc.loginFailureRepo.GetLoginFailureSummaryByEmail: %w", err)
+	}
+
+	if now.Before(emailFailures.LockedUntil(config.LoginAccountFailureLimit)) {
+		return response.ErrTooManyLoginAttempts
+	}
+
+	ipAddressFailures, err := uc.loginFailureRepo.GetLoginFailureSummaryByIPAddress(ctx, ipAddress, since)
+	if err != nil {
+		return fmt.Errorf("uc.loginFailureRepo.GetLoginFailureSummaryByIPAddress: %w", err)
+	}
+
+	if now.Before(ipAddressFailures.LockedUntil(config.LoginIPAddressFailureLimit)) {
+		return response.ErrTooManyLoginAttempts
+	}
+
+	return nil
+}
+
+// getDummyHash returns a hash of a random password made by the configured hasher, so comparing with it costs as much as with a real hash
+func (uc *UserUsecase) getDummyHash() string {
+	uc.dummyHashOnce.Do(func() {
+		password, err := auth.GenerateToken()
+		if err != nil {
+			return
+		}
+
+		uc.dummyHash, _ = uc.passwordHasher.GenerateFromPassword(password)
+	})
+
+	return uc.dummyHash
+}
+
+// getOIDCUser returns the user the account at the provider is linked to. An account which isn't linked yet is linked
+// by its email, which the provider must have verified, to the user of that email if the user verified it too,
+// or else to a new verified customer
+func (uc *UserUsecase) getOIDCUser(ctx context.Context, claims *oidc.Claims) (*entity.User, error) {
+	provider := uc.identityProvider.Issuer()
+
+	identity, err := uc.identityRepo.GetUserIdentity(ctx, provider, claims.Subject)
+	if err != nil && err != response.ErrNotFound {
+		return nil, fmt.Errorf("uc.identityRepo.GetUserIdentity: %w", err)
+	}
+
+	// The linked user may have been deleted since, then the account is linked again by its email
+	if err == nil {
+		user, err := uc.userRepo.GetUserByID(ctx, identity.UserID)
+		if err == nil {
+			return user, nil
+		}
+
+		if err != response.ErrNotFound {
+			return nil, fmt.Errorf("uc.userRepo.GetUserByID: %w", err)
+		}
+	}
+
+	email := entity.NormalizeEmail(claims.Email)
+	if email == "" || !claims.EmailVerified {
+		return nil, response.ErrEmailNotVerified
+	}
+
+	user, err := uc.userRepo.GetUserByEmail(ctx, email)
+	if err != nil && err != response.ErrNotFound {
+		return nil, fmt.Errorf("uc.userRepo.GetUserByEmail: %w", err)
+	}
+
+	if err == nil && !user.IsVerified() {
+		return nil, response.ErrOIDCAccountNotLinkable
+	}
+
+	if err == response.ErrNotFound {
+		user, err = uc.createOIDCUser(ctx, email, claims.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	identity = &entity.UserIdentity{
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    email,
+	}
+	if err := uc.identityRepo.UpsertUserIdentity(ctx, identity); err != nil {
+		return nil, fmt.Errorf("uc.identityRepo.UpsertUserIdentity: %w", err)
+	}
+
+	return user, nil
+}
+
+// createOIDCUser registers a customer signed in at the provider. The email is verified by the provider,
+// and the password is random so the user logs in with the provider until a password is set with ForgotPassword
+func (uc *UserUsecase) createOIDCUser(ctx context.Context, email string, name string) (*entity.User, error) {
+	password, err := auth.GenerateToken()
+	if err != nil {
+		return nil, fmt.Errorf("auth.GenerateToken: %w", err)
+	}
+
+	cryptedPassword, err := uc.passwordHasher.GenerateFromPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("uc.passwordHasher.GenerateFromPassword: %w", err)
+	}
+
+	fullname := strings.TrimSpace(name)
+	if fullname == "" {
+		fullname = strings.SplitN(email, "@", 2)[0]
+	}
+
+	now := time.Now()
+	user := &entity.User{
+		Email:           email,
+		Fullname:        fullname,
+		CryptedPassword: cryptedPassword,
+		Role:            entity.UserRoleCustomer,
+		VerifiedAt:      &now,
+	}
+	if err := uc.userRepo.CreateUser(ctx, user); err != nil {
+		if _, ok := err.(response.CustomError); ok {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("uc.userRepo.CreateUser: %w", err)
+	}
+	metrics.UsersRegisteredTotal.Inc()
+
+	return user, nil
+}
+
+// issueLoginChallenge stores a new login challenge of the user and returns its token in place of the access token
+func (uc *UserUsecase) issueLoginChallenge(ctx context.Context, user *entity.User) (*entity.LoginResponse, error) {
+	tokenStr, err := auth.GenerateToken()
+	if err != nil {
+		return nil, fmt.Errorf("auth.GenerateToken: %w", err)
+	}
+
+	challenge := &entity.LoginChallenge{
+		UserID:    user.ID,
+		TokenHash: auth.HashToken(tokenStr),
+		ExpiresAt: time.Now().Add(config.LoginChallengeTTL),
+	}
+	if err := uc.tokenRepo.CreateLoginChallenge(ctx, challenge); err != nil {
+		return nil, fmt.Errorf("uc.tokenRepo.CreateLoginChallenge: %w", err)
+	}
+
+	return &entity.LoginResponse{MFARequired: true, ChallengeToken: tokenStr}, nil
+}
+
+// verifyMFACode reports whether the code is a TOTP code of the credential or an unused recovery code of its user.
+// A TOTP code is accepted once, so a code of the time step of the last accepted one or of an earlier step is refused,
+// and an accepted recovery code is marked as used
+func (uc *UserUsecase) verifyMFACode(ctx context.Context, dbTrx interface{}, credential *entity.TOTPCredential, code string) (bool, error) {
+	if step, ok := auth.ValidateTOTPCode(credential.Secret, code, time.Now()); ok {
+		if step <= credential.LastUsedStep {
+			return false, nil
+		}
+
+		if err := uc.mfaRepo.UpdateTOTPLastUsedStep(ctx, dbTrx, credential.UserID, step); err != nil {
+			return false, fmt.Errorf("uc.mfaRepo.UpdateTOTPLastUsedStep: %w", err)
+		}
+
+		return true, nil
+	}
+
+	used, err := uc.mfaRepo.UseRecoveryCode(ctx, dbTrx, credential.UserID, auth.HashToken(auth.NormalizeRecoveryCode(code)))
+	if err != nil {
+		return false, fmt.Errorf("uc.mfaRepo.UseRecoveryCode: %w", err)
+	}
+
+	return used, nil
+}
+
+// checkVerificationEmailLimit returns ErrTooManyVerificationEmails once EmailVerificationResendLimit emails
+// were sent to the user within EmailVerificationResendWindow
+func (uc *UserUsecase) checkVerificationEmailLimit(ctx context.Context, userID int) error {
+	sent, err := uc.tokenRepo.CountEmailVerificationTokensSince(ctx, userID, time.Now().Add(-config.EmailVerificationResendWindow))
+	if err != nil {
+		return fmt.Errorf("uc.tokenRepo.CountEmailVerificationTokensSince: %w", err)
+	}
+
+	if sent >= config.EmailVerificationResendLimit {
+		return response.ErrTooManyVerificationEmails
+	}
+
+	return nil
+}
+
+// sendVerificationEmail stores a new email verification token of the user and emails its link
+func (uc *UserUsecase) sendVerificationEmail(ctx context.Context, user *entity.User) error {
+	verificationURL, err := uc.newEmailVerificationURL(ctx, user.ID, nil)
+	if err != nil {
+		return err
+	}
+
+	msg := &mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your Book Store email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWelcome to Book Store! Open the link below within %d hours to verify your email:\n\n%s\n\n"+
+				"If you did not create a Book Store account, you can ignore this email.\n",
+			user.Fullname,
+			int(config.EmailVerificationTokenTTL.Hours()),
+			verificationURL,
+		),
+	}
+	if err := uc.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("uc.mailer.Send: %w", err)
+	}
+
+	return nil
+}
+
+// sendEmailChangeEmails emails a notice of the change to the current email of the user, then stores a new email
+// verification token for the new email and emails its link to the new email. No link is sent when the notice fails
+func (uc *UserUsecase) sendEmailChangeEmails(ctx context.Context, user *entity.User, newEmail string) error {
+	notice := &mailer.Message{
+		To:      user.Email,
+		Subject: "Your Book Store email is being changed",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nA change of the email of your Book Store account to %s was requested. "+
+				"Your email stays %s until the new one is verified.\n\n"+
+				"If you did not ask for it, reset your password and sign out of your other sessions now.\n",
+			user.Fullname,
+			newEmail,
+			user.Email,
+		),
+	}
+	if err := uc.mailer.Send(ctx, notice); err != nil {
+		return fmt.Errorf("uc.mailer.Send: %w", err)
+	}
+
+	verificationURL, err := uc.newEmailVerificationURL(ctx, user.ID, &newEmail)
+	if err != nil {
+		return err
+	}
+
+	msg := &mailer.Message{
+		To:      newEmail,
+		Subject: "Verify your new Book Store email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nOpen the link below within %d hours to verify your new email, it replaces %s once verified:\n\n%s\n\n"+
+				"If you did not ask for it, you can ignore this email.\n",
+			user.Fullname,
+			int(config.EmailVerificationTokenTTL.Hours()),
+			user.Email,
+			verificationURL,
+		),
+	}
+	if err := uc.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("uc.mailer.Send: %w", err)
+	}
+
+	return nil
+}
+
+// newEmailVerificationURL stores a new email verification token of the user and returns the verification link,
+// a new email is the email the token changes to once verified
+func (uc *UserUsecase) newEmailVerificationURL(ctx context.Context, userID int, newEmail *string) (string, error) {
+	tokenStr, err := auth.GenerateToken()
+	if err != nil {
+		return "", fmt.Errorf("auth.GenerateToken: %w", err)
+	}
+
+	verificationToken := &entity.EmailVerificationToken{
+		UserID:    userID,
+		NewEmail:  newEmail,
+		TokenHash: auth.HashToken(tokenStr),
+		ExpiresAt: time.Now().Add(config.EmailVerificationTokenTTL),
+	}
+	if err := uc.tokenRepo.CreateEmailVerificationToken(ctx, verificationToken); err != nil {
+		return "", fmt.Errorf("uc.tokenRepo.CreateEmailVerificationToken: %w", err)
+	}
+
+	verificationURL, err := url.Parse(uc.verificationURL)
+	if err != nil {
+		return "", fmt.Errorf("url.Parse: %w", err)
+	}
+	query := verificationURL.Query()
+	query.Set("token", tokenStr)
+	verificationURL.RawQuery = query.Encode()
+
+	return verificationURL.String(), nil
+}
+
+// startSession records a new session of the device for a login and issues its first tokens
+func (uc *UserUsecase) startSession(ctx context.Context, dbTrx interface{}, user *entity.User, ipAddress string, userAgent string) (*entity.LoginResponse, error) {
+	familyID, err := auth.GenerateToken()
+	if err != nil {
+		return nil, fmt.Errorf("auth.GenerateToken: %w", err)
+	}
+
+	session := &entity.Session{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		Device:    entity.DeviceFromUserAgent(userAgent),
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+		ExpiresAt: time.Now().Add(uc.refreshTokenTTL),
+	}
+	if err := uc.tokenRepo.CreateSession(ctx, dbTrx, session); err != nil {
+		return nil, fmt.Errorf("uc.tokenRepo.CreateSession: %w", err)
+	}
+
+	event := entity.NewAuditEvent(entity.AuditActionLoginSucceeded, entity.AuditTargetUser, strconv.Itoa(user.ID), entity.AuditDiff{
+		"session_id": {To: session.ID},
+		"device":     {To: session.Device},
+	})
+	event.ActorID = user.ID
+	if err := uc.auditor.Record(ctx, dbTrx, event); err != nil {
+		return nil, fmt.Errorf("uc.auditor.Record: %w", err)
+	}
+
+	return uc.issueTokens(ctx, dbTrx, user, session)
+}
+
+// issueTokens signs a short lived access token and stores a new refresh token of the token family of the session
+func (uc *UserUsecase) issueTokens(ctx context.Context, dbTrx interface{}, user *entity.User, session *entity.Session) (*entity.LoginResponse, error) {
+	jti, err := auth.GenerateToken()
+	if err != nil {
+		return nil, fmt.Errorf("auth.GenerateToken: %w", err)
+	}
+
+	// Generate JWT token
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"jti":      jti,
+		"user_id":  user.ID,
+		"email":    user.Email,
+		"fullname": user.Fullname,
+		"role":     user.Role,
+		"sid":      session.ID,
+		"iat":      now.Unix(),
+		"exp":      now.Add(uc.accessTokenTTL).Unix(),
+	}
+	tokenStr, err := uc.tokenSigner.Sign(claims)
+	if err != nil {
+		return nil, fmt.Errorf("uc.tokenSigner.Sign: %w", err)
+	}
+
+	refreshTokenStr, err := auth.GenerateToken()
+	if err != nil {
+		return nil, fmt.Errorf("auth.GenerateToken: %w", err)
+	}
+
+	refreshToken := &entity.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  session.FamilyID,
+		TokenHash: auth.HashToken(refreshTokenStr),
+		ExpiresAt: now.Add(uc.refreshTokenTTL),
+	}
+	if err := uc.tokenRepo.CreateRefreshToken(ctx, dbTrx, refreshToken); err != nil {
+		return nil, fmt.Errorf("uc.tokenRepo.CreateRefreshToken: %w", err)
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenStr, _ := token.SignedString([]byte(uc.jwtSecret))
 
 	resp := &entity.LoginResponse{
-		AccessToken: tokenStr,
+		AccessToken:  tokenStr,
+		RefreshToken: refreshTokenStr,
+		ExpiresIn:    int(uc.accessTokenTTL.Seconds()),
 	}
 
 	return resp, nil
@@ -3,11 +3,22 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/satriowisnugroho/book-store/internal/entity"
 	"github.com/satriowisnugroho/book-store/internal/response"
 	"github.com/satriowisnugroho/book-store/internal/usecase"
+	"github.com/satriowisnugroho/book-store/pkg/auth"
+	"github.com/satriowisnugroho/book-store/pkg/mailer"
+	"github.com/satriowisnugroho/book-store/pkg/metrics"
+	"github.com/satriowisnugroho/book-store/pkg/oidc"
 	"github.com/satriowisnugroho/book-store/test/fixture"
 	testmock "github.com/satriowisnugroho/book-store/test/mock"
 	"github.com/stretchr/testify/assert"
@@ -15,6 +26,13 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+func newTokenSigner() *testmock.TokenSigner {
+	tokenSigner := &testmock.TokenSigner{}
+	tokenSigner.On("Sign", mock.Anything).Return("anaccesstoken", nil)
+
+	return tokenSigner
+}
+
 func TestCreateUser(t *testing.T) {
 	testcases := []struct {
 		name      string
@@ -23,6 +41,7 @@ func TestCreateUser(t *testing.T) {
 		hasherRes string
 		hasherErr error
 		rUserErr  error
+		mailerErr error
 		wantErr   bool
 	}{
 		{
@@ -57,6 +76,13 @@ func TestCreateUser(t *testing.T) {
 			rUserErr: errors.New("error create user"),
 			wantErr:  true,
 		},
+		{
+			name:      "failed to send verification email",
+			ctx:       context.Background(),
+			payload:   &entity.RegisterPayload{Email: "foo@bar.com", Fullname: "Foo Bar", Password: "12345"},
+			mailerErr: errors.New("error send email"),
+			wantErr:   false,
+		},
 		{
 			name:    "success",
 			ctx:     context.Background(),
@@ -73,29 +99,99 @@ func TestCreateUser(t *testing.T) {
 			userRepo := &testmock.UserRepositoryInterface{}
 			userRepo.On("CreateUser", mock.Anything, mock.Anything).Return(tc.rUserErr)
 
-			uc := usecase.NewUserUsecase("", passwordHasher, userRepo)
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("CreateEmailVerificationToken", mock.Anything, mock.Anything).Return(nil)
+
+			m := &testmock.Mailer{}
+			m.On("Send", mock.Anything, mock.Anything).Return(tc.mailerErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), passwordHasher, m, &testmock.PostgresTransactionRepositoryInterface{}, userRepo, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
 			_, err := uc.CreateUser(tc.ctx, tc.payload)
 			assert.Equal(t, tc.wantErr, err != nil)
+
+			if !tc.wantErr {
+				m.AssertCalled(t, "Send", mock.Anything, mock.MatchedBy(func(msg *mailer.Message) bool {
+					return msg.To == "foo@bar.com"
+				}))
+			}
 		})
 	}
 }
 
 func TestLogin(t *testing.T) {
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	lastFailedAt := time.Now()
+	expiredFailedAt := time.Now().Add(-2 * time.Minute)
 
 	testcases := []struct {
-		name      string
-		ctx       context.Context
-		rUserRes  *entity.User
-		rUserErr  error
-		hasherErr error
-		wantErr   bool
+		name              string
+		ctx               context.Context
+		rEmailFailures    *entity.LoginFailureSummary
+		rEmailFailuresErr error
+		rIPFailures       *entity.LoginFailureSummary
+		rIPFailuresErr    error
+		rUserRes          *entity.User
+		rUserErr          error
+		hasherErr         error
+		rCreateFailureErr error
+		needsRehash       bool
+		rehashErr         error
+		rUpdateErr        error
+		rCredentialRes    *entity.TOTPCredential
+		rCredentialErr    error
+		rChallengeErr     error
+		rSessionErr       error
+		rTokenErr         error
+		rRecordErr        error
+		wantErr           bool
+		wantCustomErr     error
+		wantFailure       bool
+		wantRehash        bool
+		wantChallenge     bool
 	}{
 		{
 			name:    "deadline context",
 			ctx:     fixture.CtxEnded(),
 			wantErr: true,
 		},
+		{
+			name:              "failed to get login failures of the email",
+			ctx:               context.Background(),
+			rEmailFailuresErr: errors.New("error get login failures"),
+			wantErr:           true,
+		},
+		{
+			name:           "email locked out",
+			ctx:            context.Background(),
+			rEmailFailures: &entity.LoginFailureSummary{Count: 5, LastFailedAt: &lastFailedAt},
+			wantErr:        true,
+			wantCustomErr:  response.ErrTooManyLoginAttempts,
+		},
+		{
+			name:           "email no longer locked out",
+			ctx:            context.Background(),
+			rEmailFailures: &entity.LoginFailureSummary{Count: 5, LastFailedAt: &expiredFailedAt},
+			rUserRes: &entity.User{
+				ID:              123,
+				Email:           "foo@bar.com",
+				Fullname:        "Foo Bar",
+				CryptedPassword: string(hashedPassword),
+			},
+			wantErr: false,
+		},
+		{
+			name:           "failed to get login failures of the IP address",
+			ctx:            context.Background(),
+			rIPFailuresErr: errors.New("error get login failures"),
+			wantErr:        true,
+		},
+		{
+			name:          "IP address locked out",
+			ctx:           context.Background(),
+			rIPFailures:   &entity.LoginFailureSummary{Count: 20, LastFailedAt: &lastFailedAt},
+			wantErr:       true,
+			wantCustomErr: response.ErrTooManyLoginAttempts,
+		},
 		{
 			name:     "failed to get user by email",
 			ctx:      context.Background(),
@@ -103,13 +199,62 @@ func TestLogin(t *testing.T) {
 			wantErr:  true,
 		},
 		{
-			name:     "record not found",
-			ctx:      context.Background(),
-			rUserErr: response.ErrNotFound,
-			wantErr:  true,
+			name:          "unknown email",
+			ctx:           context.Background(),
+			rUserErr:      response.ErrNotFound,
+			wantErr:       true,
+			wantCustomErr: response.ErrInvalidCredentials,
+			wantFailure:   true,
+		},
+		{
+			name:              "failed to record login failure",
+			ctx:               context.Background(),
+			rUserErr:          response.ErrNotFound,
+			rCreateFailureErr: errors.New("error create login failure"),
+			wantErr:           true,
+			wantFailure:       true,
+		},
+		{
+			name: "wrong password",
+			ctx:  context.Background(),
+			rUserRes: &entity.User{
+				ID:              123,
+				Email:           "foo@bar.com",
+				Fullname:        "Foo Bar",
+				CryptedPassword: string(hashedPassword),
+			},
+			hasherErr:     errors.New("error compare password"),
+			wantErr:       true,
+			wantCustomErr: response.ErrInvalidCredentials,
+			wantFailure:   true,
+		},
+		{
+			name: "failed to get TOTP credential",
+			ctx:  context.Background(),
+			rUserRes: &entity.User{
+				ID:              123,
+				Email:           "foo@bar.com",
+				Fullname:        "Foo Bar",
+				CryptedPassword: string(hashedPassword),
+			},
+			rCredentialErr: errors.New("error get TOTP credential"),
+			wantErr:        true,
+		},
+		{
+			name: "failed to create login challenge",
+			ctx:  context.Background(),
+			rUserRes: &entity.User{
+				ID:              123,
+				Email:           "foo@bar.com",
+				Fullname:        "Foo Bar",
+				CryptedPassword: string(hashedPassword),
+			},
+			rCredentialRes: &entity.TOTPCredential{UserID: 123, EnabledAt: &lastFailedAt},
+			rChallengeErr:  errors.New("error create login challenge"),
+			wantErr:        true,
 		},
 		{
-			name: "failed to compare password",
+			name: "success with two-factor authentication",
 			ctx:  context.Background(),
 			rUserRes: &entity.User{
 				ID:              123,
@@ -117,9 +262,58 @@ func TestLogin(t *testing.T) {
 				Fullname:        "Foo Bar",
 				CryptedPassword: string(hashedPassword),
 			},
-			hasherErr: errors.New("error compare password"),
+			rCredentialRes: &entity.TOTPCredential{UserID: 123, EnabledAt: &lastFailedAt},
+			wantErr:        false,
+			wantChallenge:  true,
+		},
+		{
+			name: "success with an unconfirmed TOTP enrolment",
+			ctx:  context.Background(),
+			rUserRes: &entity.User{
+				ID:              123,
+				Email:           "foo@bar.com",
+				Fullname:        "Foo Bar",
+				CryptedPassword: string(hashedPassword),
+			},
+			rCredentialRes: &entity.TOTPCredential{UserID: 123},
+			wantErr:        false,
+		},
+		{
+			name: "failed to create session",
+			ctx:  context.Background(),
+			rUserRes: &entity.User{
+				ID:              123,
+				Email:           "foo@bar.com",
+				Fullname:        "Foo Bar",
+				CryptedPassword: string(hashedPassword),
+			},
+			rSessionErr: errors.New("error create session"),
+			wantErr:     true,
+		},
+		{
+			name: "failed to create refresh token",
+			ctx:  context.Background(),
+			rUserRes: &entity.User{
+				ID:              123,
+				Email:           "foo@bar.com",
+				Fullname:        "Foo Bar",
+				CryptedPassword: string(hashedPassword),
+			},
+			rTokenErr: errors.New("error create refresh token"),
 			wantErr:   true,
 		},
+		{
+			name: "failed to record login",
+			ctx:  context.Background(),
+			rUserRes: &entity.User{
+				ID:              123,
+				Email:           "foo@bar.com",
+				Fullname:        "Foo Bar",
+				CryptedPassword: string(hashedPassword),
+			},
+			rRecordErr: errors.New("error record audit event"),
+			wantErr:    true,
+		},
 		{
 			name: "success",
 			ctx:  context.Background(),
@@ -131,19 +325,2879 @@ func TestLogin(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			// The email is looked up normalized, whatever case it was registered and typed in
+			name: "success with a mixed-case registered email",
+			ctx:  context.Background(),
+			rUserRes: &entity.User{
+				ID:              123,
+				Email:           "Foo@Bar.com",
+				Fullname:        "Foo Bar",
+				CryptedPassword: string(hashedPassword),
+			},
+			wantErr: false,
+		},
+		{
+			name: "success upgrading a legacy hash",
+			ctx:  context.Background(),
+			rUserRes: &entity.User{
+				ID:              123,
+				Email:           "foo@bar.com",
+				Fullname:        "Foo Bar",
+				CryptedPassword: string(hashedPassword),
+			},
+			needsRehash: true,
+			wantErr:     false,
+			wantRehash:  true,
+		},
+		{
+			name: "success when failed to hash the password again",
+			ctx:  context.Background(),
+			rUserRes: &entity.User{
+				ID:              123,
+				Email:           "foo@bar.com",
+				Fullname:        "Foo Bar",
+				CryptedPassword: string(hashedPassword),
+			},
+			needsRehash: true,
+			rehashErr:   errors.New("error hashing password"),
+			wantErr:     false,
+		},
+		{
+			name: "success when failed to store the upgraded hash",
+			ctx:  context.Background(),
+			rUserRes: &entity.User{
+				ID:              123,
+				Email:           "foo@bar.com",
+				Fullname:        "Foo Bar",
+				CryptedPassword: string(hashedPassword),
+			},
+			needsRehash: true,
+			rUpdateErr:  errors.New("error update password"),
+			wantErr:     false,
+			wantRehash:  true,
+		},
 	}
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			passwordHasher := &testmock.PasswordHasher{}
 			passwordHasher.On("CompareHashAndPassword", mock.Anything, mock.Anything).Return(tc.hasherErr)
+			passwordHasher.On("NeedsRehash", mock.Anything).Return(tc.needsRehash)
+			passwordHasher.On("GenerateFromPassword", "password123").Return("upgraded", tc.rehashErr)
+			passwordHasher.On("GenerateFromPassword", mock.Anything).Return("dummy", nil)
+
+			emailFailures := tc.rEmailFailures
+			if emailFailures == nil {
+				emailFailures = &entity.LoginFailureSummary{}
+			}
+			ipFailures := tc.rIPFailures
+			if ipFailures == nil {
+				ipFailures = &entity.LoginFailureSummary{}
+			}
+
+			loginFailureRepo := &testmock.LoginFailureRepositoryInterface{}
+			loginFailureRepo.On("GetLoginFailureSummaryByEmail", mock.Anything, "foo@bar.com", mock.Anything).Return(emailFailures, tc.rEmailFailuresErr)
+			loginFailureRepo.On("GetLoginFailureSummaryByIPAddress", mock.Anything, "127.0.0.1", mock.Anything).Return(ipFailures, tc.rIPFailuresErr)
+			loginFailureRepo.On("CreateLoginFailure", mock.Anything, mock.Anything).Return(tc.rCreateFailureErr)
+			loginFailureRepo.On("DeleteLoginFailuresByEmail", mock.Anything, "foo@bar.com").Return(nil)
+
+			credentialErr := tc.rCredentialErr
+			if tc.rCredentialRes == nil && credentialErr == nil {
+				credentialErr = response.ErrNotFound
+			}
+
+			mfaRepo := &testmock.MFARepositoryInterface{}
+			mfaRepo.On("GetTOTPCredential", mock.Anything, mock.Anything, 123).Return(tc.rCredentialRes, credentialErr)
 
 			userRepo := &testmock.UserRepositoryInterface{}
-			userRepo.On("GetUserByEmail", mock.Anything, mock.Anything).Return(tc.rUserRes, tc.rUserErr)
+			userRepo.On("GetUserByEmail", mock.Anything, "foo@bar.com").Return(tc.rUserRes, tc.rUserErr)
+			userRepo.On("UpdateUserPassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.rUpdateErr)
+
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("CreateSession", mock.Anything, mock.Anything, mock.Anything).Return(tc.rSessionErr)
+			tokenRepo.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(tc.rTokenErr)
+			tokenRepo.On("CreateLoginChallenge", mock.Anything, mock.Anything).Return(tc.rChallengeErr)
+
+			auditRecorder := &testmock.AuditRecorder{}
+			auditRecorder.On("Record", mock.Anything, mock.Anything, mock.Anything).Return(tc.rRecordErr)
+
+			loginsSucceeded := testutil.ToFloat64(metrics.LoginsTotal.WithLabelValues(metrics.LoginSucceeded))
+			loginsFailed := testutil.ToFloat64(metrics.LoginsTotal.WithLabelValues(metrics.LoginFailed))
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), passwordHasher, &testmock.Mailer{}, &testmock.PostgresTransactionRepositoryInterface{}, userRepo, tokenRepo, loginFailureRepo, mfaRepo, nil, &testmock.IdentityRepositoryInterface{}, auditRecorder)
+			res, err := uc.Login(tc.ctx, "127.0.0.1", "Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0", &entity.LoginPayload{Email: " Foo@Bar.com ", Password: "password123"})
+			assert.Equal(t, tc.wantErr, err != nil)
+
+			if tc.wantCustomErr != nil {
+				assert.Equal(t, tc.wantCustomErr, err)
+			}
+
+			if !tc.wantErr && tc.wantChallenge {
+				assert.True(t, res.MFARequired)
+				assert.NotEmpty(t, res.ChallengeToken)
+				assert.Empty(t, res.AccessToken)
+				tokenRepo.AssertNotCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything, mock.Anything)
+			} else if !tc.wantErr {
+				assert.NotEmpty(t, res.AccessToken)
+				assert.NotEmpty(t, res.RefreshToken)
+				assert.Equal(t, 60, res.ExpiresIn)
+				tokenRepo.AssertCalled(t, "CreateSession", mock.Anything, mock.Anything, mock.MatchedBy(func(session *entity.Session) bool {
+					return session.UserID == 123 && session.Device == "Firefox on Linux" && session.IPAddress == "127.0.0.1" && session.FamilyID != ""
+				}))
+				auditRecorder.AssertCalled(t, "Record", mock.Anything, mock.Anything, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+					return event.Action == entity.AuditActionLoginSucceeded && event.ActorID == 123
+				}))
+				assert.Equal(t, loginsSucceeded+1, testutil.ToFloat64(metrics.LoginsTotal.WithLabelValues(metrics.LoginSucceeded)))
+			} else {
+				assert.Equal(t, loginsSucceeded, testutil.ToFloat64(metrics.LoginsTotal.WithLabelValues(metrics.LoginSucceeded)))
+			}
+
+			if tc.wantFailure && tc.rCreateFailureErr == nil {
+				assert.Equal(t, loginsFailed+1, testutil.ToFloat64(metrics.LoginsTotal.WithLabelValues(metrics.LoginFailed)))
+			} else {
+				assert.Equal(t, loginsFailed, testutil.ToFloat64(metrics.LoginsTotal.WithLabelValues(metrics.LoginFailed)))
+			}
+
+			if tc.wantFailure && tc.rCreateFailureErr == nil {
+				auditRecorder.AssertCalled(t, "Record", mock.Anything, nil, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+					return event.Action == entity.AuditActionLoginFailed && event.ActorID == 0 && !strings.Contains(string(event.Diff), "foo@bar.com")
+				}))
+			}
+
+			if tc.wantFailure {
+				loginFailureRepo.AssertCalled(t, "CreateLoginFailure", mock.Anything, &entity.LoginFailure{Email: "foo@bar.com", IPAddress: "127.0.0.1"})
+			} else {
+				loginFailureRepo.AssertNotCalled(t, "CreateLoginFailure", mock.Anything, mock.Anything)
+			}
+
+			if !tc.wantErr && !tc.wantChallenge {
+				loginFailureRepo.AssertCalled(t, "DeleteLoginFailuresByEmail", mock.Anything, "foo@bar.com")
+			} else if tc.wantChallenge || tc.wantFailure {
+				loginFailureRepo.AssertNotCalled(t, "DeleteLoginFailuresByEmail", mock.Anything, mock.Anything)
+			}
+
+			if tc.wantRehash {
+				userRepo.AssertCalled(t, "UpdateUserPassword", mock.Anything, mock.Anything, tc.rUserRes.ID, "upgraded")
+			} else {
+				userRepo.AssertNotCalled(t, "UpdateUserPassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	now := time.Now()
+	activeToken := &entity.RefreshToken{ID: 1, UserID: 123, FamilyID: "family", ExpiresAt: now.Add(time.Hour)}
+	activeSession := &entity.Session{ID: 7, UserID: 123, FamilyID: "family", ExpiresAt: now.Add(time.Hour)}
+
+	testcases := []struct {
+		name          string
+		ctx           context.Context
+		payload       *entity.RefreshTokenPayload
+		rStartTrxErr  error
+		rTokenRes     *entity.RefreshToken
+		rTokenErr     error
+		rSessionRes   *entity.Session
+		rSessionErr   error
+		rFamilyErr    error
+		rUserRes      *entity.User
+		rUserErr      error
+		rRevokeErr    error
+		rTouchErr     error
+		rCreateErr    error
+		rCommitTrxErr error
+		wantErr       error
+		wantAnyErr    bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.CtxEnded(),
+			payload:    &entity.RefreshTokenPayload{RefreshToken: "token"},
+			wantAnyErr: true,
+		},
+		{
+			name:       "empty refresh token",
+			ctx:        context.Background(),
+			payload:    &entity.RefreshTokenPayload{},
+			wantErr:    response.ErrUnauthorized("Invalid refresh token"),
+			wantAnyErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          context.Background(),
+			payload:      &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rStartTrxErr: errors.New("error start transaction"),
+			wantAnyErr:   true,
+		},
+		{
+			name:       "refresh token not found",
+			ctx:        context.Background(),
+			payload:    &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenErr:  response.ErrNotFound,
+			wantErr:    response.ErrUnauthorized("Invalid refresh token"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to get refresh token",
+			ctx:        context.Background(),
+			payload:    &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenErr:  errors.New("error get refresh token"),
+			wantAnyErr: true,
+		},
+		{
+			name:        "session not found",
+			ctx:         context.Background(),
+			payload:     &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes:   activeToken,
+			rSessionErr: response.ErrNotFound,
+			wantErr:     response.ErrUnauthorized("Invalid refresh token"),
+			wantAnyErr:  true,
+		},
+		{
+			name:        "failed to get session",
+			ctx:         context.Background(),
+			payload:     &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes:   activeToken,
+			rSessionErr: errors.New("error get session"),
+			wantAnyErr:  true,
+		},
+		{
+			name:        "revoked session",
+			ctx:         context.Background(),
+			payload:     &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes:   &entity.RefreshToken{ID: 1, UserID: 123, FamilyID: "family", ExpiresAt: now.Add(time.Hour), RevokedAt: &now},
+			rSessionRes: &entity.Session{ID: 7, UserID: 123, FamilyID: "family", ExpiresAt: now.Add(time.Hour), RevokedAt: &now},
+			wantErr:     response.ErrUnauthorized("Session has been revoked, please login again"),
+			wantAnyErr:  true,
+		},
+		{
+			name:       "failed to revoke family of reused refresh token",
+			ctx:        context.Background(),
+			payload:    &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes:  &entity.RefreshToken{ID: 1, UserID: 123, FamilyID: "family", ExpiresAt: now.Add(time.Hour), RevokedAt: &now},
+			rFamilyErr: errors.New("error revoke family"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "reused refresh token",
+			ctx:        context.Background(),
+			payload:    &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes:  &entity.RefreshToken{ID: 1, UserID: 123, FamilyID: "family", ExpiresAt: now.Add(time.Hour), RevokedAt: &now},
+			wantErr:    response.ErrUnauthorized("Refresh token reuse detected, please login again"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "expired refresh token",
+			ctx:        context.Background(),
+			payload:    &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes:  &entity.RefreshToken{ID: 1, UserID: 123, FamilyID: "family", ExpiresAt: now.Add(-time.Hour)},
+			wantErr:    response.ErrUnauthorized("Refresh token has expired, please login again"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "user not found",
+			ctx:        context.Background(),
+			payload:    &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes:  activeToken,
+			rUserErr:   response.ErrNotFound,
+			wantErr:    response.ErrUnauthorized("Invalid refresh token"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to get user",
+			ctx:        context.Background(),
+			payload:    &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes:  activeToken,
+			rUserErr:   errors.New("error get user"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to revoke refresh token",
+			ctx:        context.Background(),
+			payload:    &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes:  activeToken,
+			rUserRes:   &entity.User{ID: 123},
+			rRevokeErr: errors.New("error revoke refresh token"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to touch session",
+			ctx:        context.Background(),
+			payload:    &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes:  activeToken,
+			rUserRes:   &entity.User{ID: 123},
+			rTouchErr:  errors.New("error touch session"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to create refresh token",
+			ctx:        context.Background(),
+			payload:    &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes:  activeToken,
+			rUserRes:   &entity.User{ID: 123},
+			rCreateErr: errors.New("error create refresh token"),
+			wantAnyErr: true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           context.Background(),
+			payload:       &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes:     activeToken,
+			rUserRes:      &entity.User{ID: 123},
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantAnyErr:    true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			payload:   &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes: activeToken,
+			rUserRes:  &entity.User{ID: 123},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("GetUserByID", mock.Anything, mock.Anything).Return(tc.rUserRes, tc.rUserErr)
+
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			sessionRes := tc.rSessionRes
+			if sessionRes == nil && tc.rSessionErr == nil {
+				sessionRes = activeSession
+			}
+
+			tokenRepo.On("GetRefreshTokenByHash", mock.Anything, mock.Anything, mock.Anything).Return(tc.rTokenRes, tc.rTokenErr)
+			tokenRepo.On("GetSessionByFamilyID", mock.Anything, mock.Anything, "family").Return(sessionRes, tc.rSessionErr)
+			tokenRepo.On("RevokeRefreshTokenFamily", mock.Anything, mock.Anything, mock.Anything).Return(tc.rFamilyErr)
+			tokenRepo.On("RevokeRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(true, tc.rRevokeErr)
+			tokenRepo.On("TouchSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.rTouchErr)
+			tokenRepo.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(tc.rCreateErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, &testmock.Mailer{}, dbTransactionRepo, userRepo, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
+			res, err := uc.RefreshToken(tc.ctx, "127.0.0.1", tc.payload)
+			assert.Equal(t, tc.wantAnyErr, err != nil)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if !tc.wantAnyErr {
+				assert.NotEmpty(t, res.AccessToken)
+				assert.NotEqual(t, tc.payload.RefreshToken, res.RefreshToken)
+				tokenRepo.AssertCalled(t, "CreateRefreshToken", mock.Anything, mock.Anything, mock.MatchedBy(func(token *entity.RefreshToken) bool {
+					return token.FamilyID == activeToken.FamilyID && token.UserID == activeToken.UserID
+				}))
+				tokenRepo.AssertCalled(t, "TouchSession", mock.Anything, mock.Anything, activeSession.ID, "127.0.0.1", mock.Anything)
+			}
+
+			if tc.wantErr == response.ErrUnauthorized("Session has been revoked, please login again") {
+				tokenRepo.AssertNotCalled(t, "RevokeRefreshTokenFamily", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	userGinCtx := func(userID int) *gin.Context {
+		c := fixture.GinCtxBackground()
+		c.Set("user_id", userID)
+		c.Set("jti", "jti")
+		c.Set("token_expires_at", time.Now().Add(time.Minute))
+
+		return c
+	}
+
+	testcases := []struct {
+		name             string
+		ctx              *gin.Context
+		payload          *entity.RefreshTokenPayload
+		rAccessTokenErr  error
+		rTokenRes        *entity.RefreshToken
+		rTokenErr        error
+		rFamilyErr       error
+		wantErr          bool
+		wantFamilyRevoke bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			payload: &entity.RefreshTokenPayload{},
+			wantErr: true,
+		},
+		{
+			name:            "failed to revoke access token",
+			ctx:             userGinCtx(123),
+			payload:         &entity.RefreshTokenPayload{},
+			rAccessTokenErr: errors.New("error revoke access token"),
+			wantErr:         true,
+		},
+		{
+			name:    "success without refresh token",
+			ctx:     userGinCtx(123),
+			payload: &entity.RefreshTokenPayload{},
+		},
+		{
+			name:      "unknown refresh token",
+			ctx:       userGinCtx(123),
+			payload:   &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenErr: response.ErrNotFound,
+		},
+		{
+			name:      "failed to get refresh token",
+			ctx:       userGinCtx(123),
+			payload:   &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenErr: errors.New("error get refresh token"),
+			wantErr:   true,
+		},
+		{
+			name:      "refresh token of another user",
+			ctx:       userGinCtx(123),
+			payload:   &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes: &entity.RefreshToken{UserID: 456, FamilyID: "family"},
+		},
+		{
+			name:       "failed to revoke refresh token family",
+			ctx:        userGinCtx(123),
+			payload:    &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes:  &entity.RefreshToken{UserID: 123, FamilyID: "family"},
+			rFamilyErr: errors.New("error revoke family"),
+			wantErr:    true,
+		},
+		{
+			name:             "success with refresh token",
+			ctx:              userGinCtx(123),
+			payload:          &entity.RefreshTokenPayload{RefreshToken: "token"},
+			rTokenRes:        &entity.RefreshToken{UserID: 123, FamilyID: "family"},
+			wantFamilyRevoke: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("RevokeAccessToken", mock.Anything, mock.Anything, mock.Anything).Return(tc.rAccessTokenErr)
+			tokenRepo.On("GetRefreshTokenByHash", mock.Anything, mock.Anything, mock.Anything).Return(tc.rTokenRes, tc.rTokenErr)
+			tokenRepo.On("RevokeRefreshTokenFamily", mock.Anything, mock.Anything, mock.Anything).Return(tc.rFamilyErr)
 
-			uc := usecase.NewUserUsecase("", passwordHasher, userRepo)
-			_, err := uc.Login(tc.ctx, &entity.LoginPayload{Password: "password123"})
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, &testmock.Mailer{}, &testmock.PostgresTransactionRepositoryInterface{}, &testmock.UserRepositoryInterface{}, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
+			err := uc.Logout(tc.ctx, tc.payload)
 			assert.Equal(t, tc.wantErr, err != nil)
+
+			if tc.wantFamilyRevoke {
+				tokenRepo.AssertCalled(t, "RevokeRefreshTokenFamily", mock.Anything, mock.Anything, "family")
+			}
+		})
+	}
+}
+
+func TestIsAccessTokenRevoked(t *testing.T) {
+	testcases := []struct {
+		name        string
+		ctx         context.Context
+		rRevokedRes bool
+		rRevokedErr error
+		wantErr     bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:        "failed to check denylist",
+			ctx:         context.Background(),
+			rRevokedErr: errors.New("error check denylist"),
+			wantErr:     true,
+		},
+		{
+			name:        "success",
+			ctx:         context.Background(),
+			rRevokedRes: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("IsAccessTokenRevoked", mock.Anything, mock.Anything).Return(tc.rRevokedRes, tc.rRevokedErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, &testmock.Mailer{}, &testmock.PostgresTransactionRepositoryInterface{}, &testmock.UserRepositoryInterface{}, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
+			revoked, err := uc.IsAccessTokenRevoked(tc.ctx, "jti")
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.rRevokedRes, revoked)
+		})
+	}
+}
+
+func TestGetSessions(t *testing.T) {
+	testcases := []struct {
+		name         string
+		ctx          *gin.Context
+		rSessionsRes []*entity.Session
+		rSessionsErr error
+		wantErr      bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:         "failed to get sessions",
+			ctx:          fixture.GinCtxBackground(),
+			rSessionsErr: errors.New("error get sessions"),
+			wantErr:      true,
+		},
+		{
+			name:         "success",
+			ctx:          fixture.GinCtxBackground(),
+			rSessionsRes: []*entity.Session{{ID: 7, UserID: 123}, {ID: 8, UserID: 123}},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("GetSessionsByUserID", mock.Anything, 123).Return(tc.rSessionsRes, tc.rSessionsErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, &testmock.Mailer{}, &testmock.PostgresTransactionRepositoryInterface{}, &testmock.UserRepositoryInterface{}, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
+			tc.ctx.Set("user_id", 123)
+			tc.ctx.Set("session_id", 8)
+			sessions, err := uc.GetSessions(tc.ctx)
+			assert.Equal(t, tc.wantErr, err != nil)
+
+			if !tc.wantErr {
+				assert.Len(t, sessions, 2)
+				assert.False(t, sessions[0].Current)
+				assert.True(t, sessions[1].Current)
+			}
+		})
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	testcases := []struct {
+		name          string
+		ctx           *gin.Context
+		rStartTrxErr  error
+		rRevokeErr    error
+		rRecordErr    error
+		rCommitTrxErr error
+		wantErr       error
+		wantAnyErr    bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.GinCtxEnded(),
+			wantAnyErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          fixture.GinCtxBackground(),
+			rStartTrxErr: errors.New("error start transaction"),
+			wantAnyErr:   true,
+		},
+		{
+			name:       "session not found",
+			ctx:        fixture.GinCtxBackground(),
+			rRevokeErr: response.ErrNotFound,
+			wantErr:    response.ErrNotFound,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to revoke session",
+			ctx:        fixture.GinCtxBackground(),
+			rRevokeErr: errors.New("error revoke session"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to record the revocation",
+			ctx:        fixture.GinCtxBackground(),
+			rRecordErr: errors.New("error record"),
+			wantAnyErr: true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           fixture.GinCtxBackground(),
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantAnyErr:    true,
+		},
+		{
+			name: "success",
+			ctx:  fixture.GinCtxBackground(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("RevokeSession", mock.Anything, mock.Anything, 123, 7).Return(tc.rRevokeErr)
+
+			auditRecorder := &testmock.AuditRecorder{}
+			auditRecorder.On("Record", mock.Anything, mock.Anything, mock.Anything).Return(tc.rRecordErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, &testmock.Mailer{}, dbTransactionRepo, &testmock.UserRepositoryInterface{}, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, auditRecorder)
+			tc.ctx.Set("user_id", 123)
+			err := uc.RevokeSession(tc.ctx, 7)
+			assert.Equal(t, tc.wantAnyErr, err != nil)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if !tc.wantAnyErr {
+				auditRecorder.AssertCalled(t, "Record", mock.Anything, mock.Anything, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+					return event.Action == entity.AuditActionSessionRevoked && event.TargetType == entity.AuditTargetSession && event.TargetID == "7"
+				}))
+			}
+		})
+	}
+}
+
+func TestIsSessionRevoked(t *testing.T) {
+	testcases := []struct {
+		name        string
+		ctx         context.Context
+		rRevokedRes bool
+		rRevokedErr error
+		wantErr     bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:        "failed to check session",
+			ctx:         context.Background(),
+			rRevokedErr: errors.New("error check session"),
+			wantErr:     true,
+		},
+		{
+			name:        "success",
+			ctx:         context.Background(),
+			rRevokedRes: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("IsSessionRevoked", mock.Anything, 7).Return(tc.rRevokedRes, tc.rRevokedErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, &testmock.Mailer{}, &testmock.PostgresTransactionRepositoryInterface{}, &testmock.UserRepositoryInterface{}, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
+			revoked, err := uc.IsSessionRevoked(tc.ctx, 7)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.rRevokedRes, revoked)
+		})
+	}
+}
+
+func TestDeleteExpiredTokens(t *testing.T) {
+	testcases := []struct {
+		name      string
+		ctx       context.Context
+		rTokenErr error
+		wantErr   bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:      "failed to delete expired tokens",
+			ctx:       context.Background(),
+			rTokenErr: errors.New("error delete expired tokens"),
+			wantErr:   true,
+		},
+		{
+			name: "success",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("DeleteExpiredTokens", mock.Anything).Return(tc.rTokenErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, &testmock.Mailer{}, &testmock.PostgresTransactionRepositoryInterface{}, &testmock.UserRepositoryInterface{}, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
+			err := uc.DeleteExpiredTokens(tc.ctx)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestDeleteExpiredLoginFailures(t *testing.T) {
+	testcases := []struct {
+		name       string
+		ctx        context.Context
+		rDeleteErr error
+		wantErr    bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:       "failed to delete expired login failures",
+			ctx:        context.Background(),
+			rDeleteErr: errors.New("error delete login failures"),
+			wantErr:    true,
+		},
+		{
+			name: "success",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			loginFailureRepo := &testmock.LoginFailureRepositoryInterface{}
+			loginFailureRepo.On("DeleteLoginFailuresBefore", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+				return before.Before(time.Now().Add(-23 * time.Hour))
+			})).Return(tc.rDeleteErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, &testmock.Mailer{}, &testmock.PostgresTransactionRepositoryInterface{}, &testmock.UserRepositoryInterface{}, &testmock.TokenRepositoryInterface{}, loginFailureRepo, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
+			err := uc.DeleteExpiredLoginFailures(tc.ctx)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestForgotPassword(t *testing.T) {
+	testcases := []struct {
+		name       string
+		ctx        context.Context
+		payload    *entity.ForgotPasswordPayload
+		rUserRes   *entity.User
+		rUserErr   error
+		rTokenErr  error
+		mailerErr  error
+		wantErr    bool
+		wantMailed bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.CtxEnded(),
+			payload: &entity.ForgotPasswordPayload{Email: "foo@bar.com"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid payload",
+			ctx:     context.Background(),
+			payload: &entity.ForgotPasswordPayload{Email: "foo"},
+			wantErr: true,
+		},
+		{
+			name:     "unknown email",
+			ctx:      context.Background(),
+			payload:  &entity.ForgotPasswordPayload{Email: "foo@bar.com"},
+			rUserErr: response.ErrNotFound,
+		},
+		{
+			name:     "failed to get user by email",
+			ctx:      context.Background(),
+			payload:  &entity.ForgotPasswordPayload{Email: "foo@bar.com"},
+			rUserErr: errors.New("error get user by email"),
+			wantErr:  true,
+		},
+		{
+			name:      "failed to create password reset token",
+			ctx:       context.Background(),
+			payload:   &entity.ForgotPasswordPayload{Email: "foo@bar.com"},
+			rUserRes:  &entity.User{ID: 123, Email: "foo@bar.com", Fullname: "Foo Bar"},
+			rTokenErr: errors.New("error create password reset token"),
+			wantErr:   true,
+		},
+		{
+			name:      "failed to send email",
+			ctx:       context.Background(),
+			payload:   &entity.ForgotPasswordPayload{Email: "foo@bar.com"},
+			rUserRes:  &entity.User{ID: 123, Email: "foo@bar.com", Fullname: "Foo Bar"},
+			mailerErr: errors.New("error send email"),
+			wantErr:   true,
+		},
+		{
+			name:       "success",
+			ctx:        context.Background(),
+			payload:    &entity.ForgotPasswordPayload{Email: "foo@bar.com"},
+			rUserRes:   &entity.User{ID: 123, Email: "foo@bar.com", Fullname: "Foo Bar"},
+			wantMailed: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("GetUserByEmail", mock.Anything, mock.Anything).Return(tc.rUserRes, tc.rUserErr)
+
+			var resetToken *entity.PasswordResetToken
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("CreatePasswordResetToken", mock.Anything, mock.Anything).Return(tc.rTokenErr).Run(func(args mock.Arguments) {
+				resetToken = args.Get(1).(*entity.PasswordResetToken)
+			})
+
+			var sent *mailer.Message
+			m := &testmock.Mailer{}
+			m.On("Send", mock.Anything, mock.Anything).Return(tc.mailerErr).Run(func(args mock.Arguments) {
+				sent = args.Get(1).(*mailer.Message)
+			})
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, m, &testmock.PostgresTransactionRepositoryInterface{}, userRepo, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
+			err := uc.ForgotPassword(tc.ctx, tc.payload)
+			assert.Equal(t, tc.wantErr, err != nil)
+
+			if tc.wantMailed {
+				assert.Equal(t, "foo@bar.com", sent.To)
+				assert.Equal(t, 123, resetToken.UserID)
+				assert.True(t, resetToken.ExpiresAt.After(time.Now()))
+
+				// The email holds the token, only its hash is stored
+				link := regexp.MustCompile(`http://localhost:3000/reset-password\?token=(\S+)`).FindStringSubmatch(sent.Body)
+				assert.Len(t, link, 2)
+				assert.Equal(t, auth.HashToken(link[1]), resetToken.TokenHash)
+			}
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	now := time.Now()
+	usableToken := &entity.PasswordResetToken{ID: 1, UserID: 123, ExpiresAt: now.Add(time.Hour)}
+
+	testcases := []struct {
+		name            string
+		ctx             context.Context
+		payload         *entity.ResetPasswordPayload
+		hasherErr       error
+		rStartTrxErr    error
+		rTokenRes       *entity.PasswordResetToken
+		rTokenErr       error
+		rUpdateErr      error
+		rUseTokensErr   error
+		rRevokeTokenErr error
+		rCommitTrxErr   error
+		wantErr         error
+		wantAnyErr      bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.CtxEnded(),
+			payload:    &entity.ResetPasswordPayload{Token: "token", Password: "12345"},
+			wantAnyErr: true,
+		},
+		{
+			name:       "invalid payload",
+			ctx:        context.Background(),
+			payload:    &entity.ResetPasswordPayload{Token: "token", Password: "123"},
+			wantErr:    response.ErrInvalidPasswordLength,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to hash the password",
+			ctx:        context.Background(),
+			payload:    &entity.ResetPasswordPayload{Token: "token", Password: "12345"},
+			hasherErr:  errors.New("error hashing password"),
+			wantAnyErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          context.Background(),
+			payload:      &entity.ResetPasswordPayload{Token: "token", Password: "12345"},
+			rStartTrxErr: errors.New("error start transaction"),
+			wantAnyErr:   true,
+		},
+		{
+			name:       "unknown token",
+			ctx:        context.Background(),
+			payload:    &entity.ResetPasswordPayload{Token: "token", Password: "12345"},
+			rTokenErr:  response.ErrNotFound,
+			wantErr:    response.ErrInvalidPasswordResetToken,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to get token",
+			ctx:        context.Background(),
+			payload:    &entity.ResetPasswordPayload{Token: "token", Password: "12345"},
+			rTokenErr:  errors.New("error get token"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "used token",
+			ctx:        context.Background(),
+			payload:    &entity.ResetPasswordPayload{Token: "token", Password: "12345"},
+			rTokenRes:  &entity.PasswordResetToken{ID: 1, UserID: 123, ExpiresAt: now.Add(time.Hour), UsedAt: &now},
+			wantErr:    response.ErrInvalidPasswordResetToken,
+			wantAnyErr: true,
+		},
+		{
+			name:       "expired token",
+			ctx:        context.Background(),
+			payload:    &entity.ResetPasswordPayload{Token: "token", Password: "12345"},
+			rTokenRes:  &entity.PasswordResetToken{ID: 1, UserID: 123, ExpiresAt: now.Add(-time.Minute)},
+			wantErr:    response.ErrInvalidPasswordResetToken,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to update password",
+			ctx:        context.Background(),
+			payload:    &entity.ResetPasswordPayload{Token: "token", Password: "12345"},
+			rTokenRes:  usableToken,
+			rUpdateErr: errors.New("error update password"),
+			wantAnyErr: true,
+		},
+		{
+			name:          "failed to use the reset tokens",
+			ctx:           context.Background(),
+			payload:       &entity.ResetPasswordPayload{Token: "token", Password: "12345"},
+			rTokenRes:     usableToken,
+			rUseTokensErr: errors.New("error use tokens"),
+			wantAnyErr:    true,
+		},
+		{
+			name:            "failed to revoke the refresh tokens",
+			ctx:             context.Background(),
+			payload:         &entity.ResetPasswordPayload{Token: "token", Password: "12345"},
+			rTokenRes:       usableToken,
+			rRevokeTokenErr: errors.New("error revoke tokens"),
+			wantAnyErr:      true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           context.Background(),
+			payload:       &entity.ResetPasswordPayload{Token: "token", Password: "12345"},
+			rTokenRes:     usableToken,
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantAnyErr:    true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			payload:   &entity.ResetPasswordPayload{Token: "token", Password: "12345"},
+			rTokenRes: usableToken,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			passwordHasher := &testmock.PasswordHasher{}
+			passwordHasher.On("GenerateFromPassword", mock.Anything).Return("crypted", tc.hasherErr)
+
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("UpdateUserPassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.rUpdateErr)
+
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("GetPasswordResetTokenByHash", mock.Anything, mock.Anything, auth.HashToken("token")).Return(tc.rTokenRes, tc.rTokenErr)
+			tokenRepo.On("UsePasswordResetTokens", mock.Anything, mock.Anything, mock.Anything).Return(tc.rUseTokensErr)
+			tokenRepo.On("RevokeUserRefreshTokens", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.rRevokeTokenErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), passwordHasher, &testmock.Mailer{}, dbTransactionRepo, userRepo, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
+			err := uc.ResetPassword(tc.ctx, tc.payload)
+			assert.Equal(t, tc.wantAnyErr, err != nil)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if !tc.wantAnyErr {
+				userRepo.AssertCalled(t, "UpdateUserPassword", mock.Anything, mock.Anything, 123, "crypted")
+				tokenRepo.AssertCalled(t, "UsePasswordResetTokens", mock.Anything, mock.Anything, 123)
+				tokenRepo.AssertCalled(t, "RevokeUserRefreshTokens", mock.Anything, mock.Anything, 123, 0)
+			}
+		})
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	now := time.Now()
+	usableToken := &entity.EmailVerificationToken{ID: 1, UserID: 123, ExpiresAt: now.Add(time.Hour)}
+	newEmail := "baz@bar.com"
+	emailChangeToken := &entity.EmailVerificationToken{ID: 1, UserID: 123, NewEmail: &newEmail, ExpiresAt: now.Add(time.Hour)}
+
+	testcases := []struct {
+		name          string
+		ctx           context.Context
+		token         string
+		rStartTrxErr  error
+		rTokenRes     *entity.EmailVerificationToken
+		rTokenErr     error
+		rVerifyErr    error
+		rChangeErr    error
+		rUseTokensErr error
+		rCommitTrxErr error
+		wantErr       error
+		wantAnyErr    bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.CtxEnded(),
+			token:      "token",
+			wantAnyErr: true,
+		},
+		{
+			name:       "empty token",
+			ctx:        context.Background(),
+			wantErr:    response.ErrInvalidEmailVerificationToken,
+			wantAnyErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          context.Background(),
+			token:        "token",
+			rStartTrxErr: errors.New("error start transaction"),
+			wantAnyErr:   true,
+		},
+		{
+			name:       "unknown token",
+			ctx:        context.Background(),
+			token:      "token",
+			rTokenErr:  response.ErrNotFound,
+			wantErr:    response.ErrInvalidEmailVerificationToken,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to get token",
+			ctx:        context.Background(),
+			token:      "token",
+			rTokenErr:  errors.New("error get token"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "used token",
+			ctx:        context.Background(),
+			token:      "token",
+			rTokenRes:  &entity.EmailVerificationToken{ID: 1, UserID: 123, ExpiresAt: now.Add(time.Hour), UsedAt: &now},
+			wantErr:    response.ErrInvalidEmailVerificationToken,
+			wantAnyErr: true,
+		},
+		{
+			name:       "expired token",
+			ctx:        context.Background(),
+			token:      "token",
+			rTokenRes:  &entity.EmailVerificationToken{ID: 1, UserID: 123, ExpiresAt: now.Add(-time.Minute)},
+			wantErr:    response.ErrInvalidEmailVerificationToken,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to verify user",
+			ctx:        context.Background(),
+			token:      "token",
+			rTokenRes:  usableToken,
+			rVerifyErr: errors.New("error verify user"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "new email taken since the change",
+			ctx:        context.Background(),
+			token:      "token",
+			rTokenRes:  emailChangeToken,
+			rChangeErr: response.ErrDuplicateEmail,
+			wantErr:    response.ErrDuplicateEmail,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to change the email",
+			ctx:        context.Background(),
+			token:      "token",
+			rTokenRes:  emailChangeToken,
+			rChangeErr: errors.New("error change email"),
+			wantAnyErr: true,
+		},
+		{
+			name:          "failed to use the verification tokens",
+			ctx:           context.Background(),
+			token:         "token",
+			rTokenRes:     usableToken,
+			rUseTokensErr: errors.New("error use tokens"),
+			wantAnyErr:    true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           context.Background(),
+			token:         "token",
+			rTokenRes:     usableToken,
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantAnyErr:    true,
+		},
+		{
+			name:      "success",
+			ctx:       context.Background(),
+			token:     "token",
+			rTokenRes: usableToken,
+		},
+		{
+			name:      "success changing the email",
+			ctx:       context.Background(),
+			token:     "token",
+			rTokenRes: emailChangeToken,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("VerifyUser", mock.Anything, mock.Anything, mock.Anything).Return(tc.rVerifyErr)
+			userRepo.On("ChangeUserEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.rChangeErr)
+
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("GetEmailVerificationTokenByHash", mock.Anything, mock.Anything, auth.HashToken("token")).Return(tc.rTokenRes, tc.rTokenErr)
+			tokenRepo.On("UseEmailVerificationTokens", mock.Anything, mock.Anything, mock.Anything).Return(tc.rUseTokensErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, &testmock.Mailer{}, dbTransactionRepo, userRepo, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
+			err := uc.VerifyEmail(tc.ctx, tc.token)
+			assert.Equal(t, tc.wantAnyErr, err != nil)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if tc.wantAnyErr {
+				return
+			}
+
+			if tc.rTokenRes.NewEmail != nil {
+				userRepo.AssertCalled(t, "ChangeUserEmail", mock.Anything, mock.Anything, 123, newEmail)
+				userRepo.AssertNotCalled(t, "VerifyUser", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				userRepo.AssertCalled(t, "VerifyUser", mock.Anything, mock.Anything, 123)
+				userRepo.AssertNotCalled(t, "ChangeUserEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			tokenRepo.AssertCalled(t, "UseEmailVerificationTokens", mock.Anything, mock.Anything, 123)
+		})
+	}
+}
+
+func TestResendVerificationEmail(t *testing.T) {
+	now := time.Now()
+	unverifiedUser := &entity.User{ID: 123, Email: "foo@bar.com", Fullname: "Foo Bar"}
+
+	testcases := []struct {
+		name       string
+		ctx        *gin.Context
+		rUserRes   *entity.User
+		rUserErr   error
+		rCountRes  int
+		rCountErr  error
+		rTokenErr  error
+		mailerErr  error
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.GinCtxEnded(),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to get user",
+			ctx:        fixture.GinCtxBackground(),
+			rUserErr:   errors.New("error get user"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "email already verified",
+			ctx:        fixture.GinCtxBackground(),
+			rUserRes:   &entity.User{ID: 123, VerifiedAt: &now},
+			wantErr:    response.ErrEmailAlreadyVerified,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to count the verification emails",
+			ctx:        fixture.GinCtxBackground(),
+			rUserRes:   unverifiedUser,
+			rCountErr:  errors.New("error count tokens"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "too many verification emails",
+			ctx:        fixture.GinCtxBackground(),
+			rUserRes:   unverifiedUser,
+			rCountRes:  3,
+			wantErr:    response.ErrTooManyVerificationEmails,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to create verification token",
+			ctx:        fixture.GinCtxBackground(),
+			rUserRes:   unverifiedUser,
+			rTokenErr:  errors.New("error create token"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to send email",
+			ctx:        fixture.GinCtxBackground(),
+			rUserRes:   unverifiedUser,
+			mailerErr:  errors.New("error send email"),
+			wantAnyErr: true,
+		},
+		{
+			name:      "success",
+			ctx:       fixture.GinCtxBackground(),
+			rUserRes:  unverifiedUser,
+			rCountRes: 2,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("GetUserByID", mock.Anything, mock.Anything).Return(tc.rUserRes, tc.rUserErr)
+
+			var verificationToken *entity.EmailVerificationToken
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("CountEmailVerificationTokensSince", mock.Anything, mock.Anything, mock.Anything).Return(tc.rCountRes, tc.rCountErr)
+			tokenRepo.On("CreateEmailVerificationToken", mock.Anything, mock.Anything).Return(tc.rTokenErr).Run(func(args mock.Arguments) {
+				verificationToken = args.Get(1).(*entity.EmailVerificationToken)
+			})
+
+			var sent *mailer.Message
+			m := &testmock.Mailer{}
+			m.On("Send", mock.Anything, mock.Anything).Return(tc.mailerErr).Run(func(args mock.Arguments) {
+				sent = args.Get(1).(*mailer.Message)
+			})
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, m, &testmock.PostgresTransactionRepositoryInterface{}, userRepo, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
+			err := uc.ResendVerificationEmail(tc.ctx)
+			assert.Equal(t, tc.wantAnyErr, err != nil)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if !tc.wantAnyErr {
+				assert.Equal(t, "foo@bar.com", sent.To)
+				assert.Equal(t, 123, verificationToken.UserID)
+
+				// The email holds the token, only its hash is stored
+				link := regexp.MustCompile(`http://localhost:9999/v1/users/verify\?token=(\S+)`).FindStringSubmatch(sent.Body)
+				assert.Len(t, link, 2)
+				assert.Equal(t, auth.HashToken(link[1]), verificationToken.TokenHash)
+			}
+		})
+	}
+}
+
+func TestGetProfile(t *testing.T) {
+	testcases := []struct {
+		name     string
+		ctx      *gin.Context
+		rUserRes *entity.User
+		rUserErr error
+		wantErr  bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:     "user not found",
+			ctx:      fixture.GinCtxBackground(),
+			rUserErr: response.ErrNotFound,
+			wantErr:  true,
+		},
+		{
+			name:     "failed to get user",
+			ctx:      fixture.GinCtxBackground(),
+			rUserErr: errors.New("error get user"),
+			wantErr:  true,
+		},
+		{
+			name:     "success",
+			ctx:      fixture.GinCtxBackground(),
+			rUserRes: &entity.User{ID: 123},
+			wantErr:  false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("GetUserByID", mock.Anything, mock.Anything).Return(tc.rUserRes, tc.rUserErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, &testmock.Mailer{}, &testmock.PostgresTransactionRepositoryInterface{}, userRepo, &testmock.TokenRepositoryInterface{}, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
+			user, err := uc.GetProfile(tc.ctx)
+			assert.Equal(t, tc.wantErr, err != nil)
+
+			if !tc.wantErr {
+				assert.Equal(t, tc.rUserRes, user)
+			}
+		})
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	now := time.Now()
+	fullname := "Bar Baz"
+	sameEmail := "Foo@Bar.com"
+	newEmail := "baz@bar.com"
+	invalidEmail := "baz"
+
+	testcases := []struct {
+		name          string
+		ctx           *gin.Context
+		payload       *entity.UpdateProfilePayload
+		rUserErr      error
+		compareErr    error
+		rCountRes     int
+		rCountErr     error
+		rEmailUserErr error
+		rStartTrxErr  error
+		rUpdateErr    error
+		rUseTokensErr error
+		rCommitTrxErr error
+		rSendErr      error
+		wantErr       error
+		wantAnyErr    bool
+		wantMailed    bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.GinCtxEnded(),
+			payload:    &entity.UpdateProfilePayload{},
+			wantAnyErr: true,
+		},
+		{
+			name:       "invalid payload",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.UpdateProfilePayload{Email: &invalidEmail},
+			wantErr:    response.ErrInvalidEmail,
+			wantAnyErr: true,
+		},
+		{
+			name:          "failed to get user",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.UpdateProfilePayload{Fullname: &fullname},
+			rUserErr:      errors.New("error get user"),
+			rEmailUserErr: response.ErrNotFound,
+			wantAnyErr:    true,
+		},
+		{
+			name:          "wrong current password",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.UpdateProfilePayload{Email: &newEmail, CurrentPassword: "wrong"},
+			compareErr:    errors.New("mismatched password"),
+			rEmailUserErr: response.ErrNotFound,
+			wantErr:       response.ErrInvalidPassword,
+			wantAnyErr:    true,
+		},
+		{
+			name:          "failed to count the verification emails",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.UpdateProfilePayload{Email: &newEmail, CurrentPassword: "54321"},
+			rCountErr:     errors.New("error count tokens"),
+			rEmailUserErr: response.ErrNotFound,
+			wantAnyErr:    true,
+		},
+		{
+			name:          "too many verification emails",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.UpdateProfilePayload{Email: &newEmail, CurrentPassword: "54321"},
+			rCountRes:     3,
+			rEmailUserErr: response.ErrNotFound,
+			wantErr:       response.ErrTooManyVerificationEmails,
+			wantAnyErr:    true,
+		},
+		{
+			name:       "duplicate email",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.UpdateProfilePayload{Email: &newEmail, CurrentPassword: "54321"},
+			wantErr:    response.ErrDuplicateEmail,
+			wantAnyErr: true,
+		},
+		{
+			name:          "failed to get user by email",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.UpdateProfilePayload{Email: &newEmail, CurrentPassword: "54321"},
+			rEmailUserErr: errors.New("error get user by email"),
+			wantAnyErr:    true,
+		},
+		{
+			name:          "failed to start transaction",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.UpdateProfilePayload{Fullname: &fullname},
+			rEmailUserErr: response.ErrNotFound,
+			rStartTrxErr:  errors.New("error start transaction"),
+			wantAnyErr:    true,
+		},
+		{
+			name:          "failed to update user",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.UpdateProfilePayload{Fullname: &fullname},
+			rEmailUserErr: response.ErrNotFound,
+			rUpdateErr:    errors.New("error update user"),
+			wantAnyErr:    true,
+		},
+		{
+			name:          "failed to use the verification tokens",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.UpdateProfilePayload{Email: &newEmail, CurrentPassword: "54321"},
+			rEmailUserErr: response.ErrNotFound,
+			rUseTokensErr: errors.New("error use tokens"),
+			wantAnyErr:    true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.UpdateProfilePayload{Fullname: &fullname},
+			rEmailUserErr: response.ErrNotFound,
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantAnyErr:    true,
+		},
+		{
+			name:          "failed to email the notice of the change",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.UpdateProfilePayload{Email: &newEmail, CurrentPassword: "54321"},
+			rEmailUserErr: response.ErrNotFound,
+			rSendErr:      errors.New("error send email"),
+			wantAnyErr:    true,
+		},
+		{
+			name:          "success without changing the email",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.UpdateProfilePayload{Email: &sameEmail, Fullname: &fullname},
+			rEmailUserErr: response.ErrNotFound,
+		},
+		{
+			name:          "success changing the email",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.UpdateProfilePayload{Email: &newEmail, CurrentPassword: "54321"},
+			rEmailUserErr: response.ErrNotFound,
+			wantMailed:    true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			passwordHasher := &testmock.PasswordHasher{}
+			passwordHasher.On("CompareHashAndPassword", "crypted", mock.Anything).Return(tc.compareErr)
+
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			user := &entity.User{ID: 123, Email: "foo@bar.com", Fullname: "Foo Bar", CryptedPassword: "crypted", VerifiedAt: &now}
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("GetUserByID", mock.Anything, mock.Anything).Return(user, tc.rUserErr)
+			userRepo.On("GetUserByEmail", mock.Anything, newEmail).Return(&entity.User{ID: 456, Email: newEmail}, tc.rEmailUserErr)
+			userRepo.On("UpdateUser", mock.Anything, mock.Anything, mock.Anything).Return(tc.rUpdateErr)
+
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("CountEmailVerificationTokensSince", mock.Anything, mock.Anything, mock.Anything).Return(tc.rCountRes, tc.rCountErr)
+			tokenRepo.On("UseEmailVerificationTokens", mock.Anything, mock.Anything, mock.Anything).Return(tc.rUseTokensErr)
+			tokenRepo.On("CreateEmailVerificationToken", mock.Anything, mock.Anything).Return(nil)
+
+			m := &testmock.Mailer{}
+			m.On("Send", mock.Anything, mock.Anything).Return(tc.rSendErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), passwordHasher, m, dbTransactionRepo, userRepo, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
+			res, err := uc.UpdateProfile(tc.ctx, tc.payload)
+			assert.Equal(t, tc.wantAnyErr, err != nil)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if tc.rSendErr != nil {
+				// No verification link is sent without the notice to the current email
+				tokenRepo.AssertNotCalled(t, "CreateEmailVerificationToken", mock.Anything, mock.Anything)
+			}
+
+			if tc.wantAnyErr {
+				return
+			}
+
+			// The email is only changed once the new email is verified
+			assert.Equal(t, "foo@bar.com", res.Email)
+			assert.NotNil(t, res.VerifiedAt)
+
+			if tc.wantMailed {
+				tokenRepo.AssertCalled(t, "UseEmailVerificationTokens", mock.Anything, mock.Anything, 123)
+				tokenRepo.AssertCalled(t, "CreateEmailVerificationToken", mock.Anything, mock.MatchedBy(func(token *entity.EmailVerificationToken) bool {
+					return token.UserID == 123 && token.NewEmail != nil && *token.NewEmail == newEmail
+				}))
+				m.AssertCalled(t, "Send", mock.Anything, mock.MatchedBy(func(msg *mailer.Message) bool {
+					return msg.To == "foo@bar.com" && strings.Contains(msg.Body, newEmail)
+				}))
+				m.AssertCalled(t, "Send", mock.Anything, mock.MatchedBy(func(msg *mailer.Message) bool {
+					return msg.To == newEmail && strings.Contains(msg.Body, "http://localhost:9999/v1/users/verify?token=")
+				}))
+			} else {
+				assert.Equal(t, fullname, res.Fullname)
+				passwordHasher.AssertNotCalled(t, "CompareHashAndPassword", mock.Anything, mock.Anything)
+				tokenRepo.AssertNotCalled(t, "UseEmailVerificationTokens", mock.Anything, mock.Anything, mock.Anything)
+				m.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	testcases := []struct {
+		name            string
+		ctx             *gin.Context
+		payload         *entity.ChangePasswordPayload
+		rUserErr        error
+		compareErr      error
+		hasherErr       error
+		rStartTrxErr    error
+		rUpdateErr      error
+		rUseTokensErr   error
+		rRevokeTokenErr error
+		rRecordErr      error
+		rCommitTrxErr   error
+		wantErr         error
+		wantAnyErr      bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.GinCtxEnded(),
+			payload:    &entity.ChangePasswordPayload{CurrentPassword: "54321", NewPassword: "12345"},
+			wantAnyErr: true,
+		},
+		{
+			name:       "invalid payload",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.ChangePasswordPayload{CurrentPassword: "54321", NewPassword: "123"},
+			wantErr:    response.ErrInvalidPasswordLength,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to get user",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.ChangePasswordPayload{CurrentPassword: "54321", NewPassword: "12345"},
+			rUserErr:   errors.New("error get user"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "wrong current password",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.ChangePasswordPayload{CurrentPassword: "wrong", NewPassword: "12345"},
+			compareErr: errors.New("mismatched password"),
+			wantErr:    response.ErrInvalidPassword,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to hash the password",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.ChangePasswordPayload{CurrentPassword: "54321", NewPassword: "12345"},
+			hasherErr:  errors.New("error hashing password"),
+			wantAnyErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          fixture.GinCtxBackground(),
+			payload:      &entity.ChangePasswordPayload{CurrentPassword: "54321", NewPassword: "12345"},
+			rStartTrxErr: errors.New("error start transaction"),
+			wantAnyErr:   true,
+		},
+		{
+			name:       "failed to update password",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.ChangePasswordPayload{CurrentPassword: "54321", NewPassword: "12345"},
+			rUpdateErr: errors.New("error update password"),
+			wantAnyErr: true,
+		},
+		{
+			name:          "failed to use the reset tokens",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.ChangePasswordPayload{CurrentPassword: "54321", NewPassword: "12345"},
+			rUseTokensErr: errors.New("error use tokens"),
+			wantAnyErr:    true,
+		},
+		{
+			name:            "failed to revoke the refresh tokens",
+			ctx:             fixture.GinCtxBackground(),
+			payload:         &entity.ChangePasswordPayload{CurrentPassword: "54321", NewPassword: "12345"},
+			rRevokeTokenErr: errors.New("error revoke tokens"),
+			wantAnyErr:      true,
+		},
+		{
+			name:       "failed to record audit event",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.ChangePasswordPayload{CurrentPassword: "54321", NewPassword: "12345"},
+			rRecordErr: errors.New("error record audit event"),
+			wantAnyErr: true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.ChangePasswordPayload{CurrentPassword: "54321", NewPassword: "12345"},
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantAnyErr:    true,
+		},
+		{
+			name:    "success",
+			ctx:     fixture.GinCtxBackground(),
+			payload: &entity.ChangePasswordPayload{CurrentPassword: "54321", NewPassword: "12345"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			passwordHasher := &testmock.PasswordHasher{}
+			passwordHasher.On("CompareHashAndPassword", "oldcrypted", mock.Anything).Return(tc.compareErr)
+			passwordHasher.On("GenerateFromPassword", "12345").Return("crypted", tc.hasherErr)
+
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("GetUserByID", mock.Anything, mock.Anything).Return(&entity.User{ID: 123, CryptedPassword: "oldcrypted"}, tc.rUserErr)
+			userRepo.On("UpdateUserPassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.rUpdateErr)
+
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("UsePasswordResetTokens", mock.Anything, mock.Anything, mock.Anything).Return(tc.rUseTokensErr)
+			tokenRepo.On("RevokeUserRefreshTokens", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.rRevokeTokenErr)
+
+			auditRecorder := &testmock.AuditRecorder{}
+			auditRecorder.On("Record", mock.Anything, mock.Anything, mock.Anything).Return(tc.rRecordErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), passwordHasher, &testmock.Mailer{}, dbTransactionRepo, userRepo, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, &testmock.IdentityRepositoryInterface{}, auditRecorder)
+			tc.ctx.Set("session_id", 7)
+			err := uc.ChangePassword(tc.ctx, tc.payload)
+			assert.Equal(t, tc.wantAnyErr, err != nil)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if !tc.wantAnyErr {
+				userRepo.AssertCalled(t, "UpdateUserPassword", mock.Anything, mock.Anything, 123, "crypted")
+				tokenRepo.AssertCalled(t, "UsePasswordResetTokens", mock.Anything, mock.Anything, 123)
+				tokenRepo.AssertCalled(t, "RevokeUserRefreshTokens", mock.Anything, mock.Anything, 123, 7)
+				auditRecorder.AssertCalled(t, "Record", mock.Anything, mock.Anything, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+					return event.Action == entity.AuditActionPasswordChanged && event.ActorID == 123 && event.TargetID == "123"
+				}))
+			}
+		})
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	testcases := []struct {
+		name             string
+		ctx              *gin.Context
+		rStartTrxErr     error
+		rDeleteErr       error
+		rRevokeTokenErr  error
+		rUnlinkErr       error
+		rRecordErr       error
+		rCommitTrxErr    error
+		rRevokeAccessErr error
+		wantErr          bool
+	}{
+		{
+			name:    "deadline context",
+			ctx:     fixture.GinCtxEnded(),
+			wantErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          fixture.GinCtxBackground(),
+			rStartTrxErr: errors.New("error start transaction"),
+			wantErr:      true,
+		},
+		{
+			name:       "user not found",
+			ctx:        fixture.GinCtxBackground(),
+			rDeleteErr: response.ErrNotFound,
+			wantErr:    true,
+		},
+		{
+			name:       "failed to delete user",
+			ctx:        fixture.GinCtxBackground(),
+			rDeleteErr: errors.New("error delete user"),
+			wantErr:    true,
+		},
+		{
+			name:            "failed to revoke the refresh tokens",
+			ctx:             fixture.GinCtxBackground(),
+			rRevokeTokenErr: errors.New("error revoke tokens"),
+			wantErr:         true,
+		},
+		{
+			name:       "failed to unlink the OpenID Connect accounts",
+			ctx:        fixture.GinCtxBackground(),
+			rUnlinkErr: errors.New("error delete identities"),
+			wantErr:    true,
+		},
+		{
+			name:       "failed to record the deletion",
+			ctx:        fixture.GinCtxBackground(),
+			rRecordErr: errors.New("error record"),
+			wantErr:    true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           fixture.GinCtxBackground(),
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantErr:       true,
+		},
+		{
+			name:             "failed to revoke the access token",
+			ctx:              fixture.GinCtxBackground(),
+			rRevokeAccessErr: errors.New("error revoke access token"),
+			wantErr:          true,
+		},
+		{
+			name:    "success",
+			ctx:     fixture.GinCtxBackground(),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("DeleteUser", mock.Anything, mock.Anything, mock.Anything).Return(tc.rDeleteErr)
+
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("RevokeUserRefreshTokens", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tc.rRevokeTokenErr)
+			tokenRepo.On("RevokeAccessToken", mock.Anything, mock.Anything, mock.Anything).Return(tc.rRevokeAccessErr)
+
+			identityRepo := &testmock.IdentityRepositoryInterface{}
+			identityRepo.On("DeleteUserIdentities", mock.Anything, mock.Anything, mock.Anything).Return(tc.rUnlinkErr)
+
+			auditRecorder := &testmock.AuditRecorder{}
+			auditRecorder.On("Record", mock.Anything, mock.Anything, mock.Anything).Return(tc.rRecordErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, &testmock.Mailer{}, dbTransactionRepo, userRepo, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, nil, identityRepo, auditRecorder)
+			tc.ctx.Set("user_id", 123)
+			err := uc.DeleteAccount(tc.ctx)
+			assert.Equal(t, tc.wantErr, err != nil)
+
+			if !tc.wantErr {
+				auditRecorder.AssertCalled(t, "Record", mock.Anything, mock.Anything, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+					return event.Action == entity.AuditActionUserDeleted && event.TargetID == "123"
+				}))
+			}
+		})
+	}
+}
+
+func TestVerifyLoginChallenge(t *testing.T) {
+	now := time.Now()
+	secret, _ := auth.GenerateTOTPSecret()
+	code, _ := auth.GenerateTOTPCode(secret, now)
+	activeChallenge := &entity.LoginChallenge{ID: 1, UserID: 123, ExpiresAt: now.Add(time.Minute)}
+	enabledCredential := &entity.TOTPCredential{UserID: 123, Secret: secret, EnabledAt: &now}
+
+	testcases := []struct {
+		name               string
+		ctx                context.Context
+		payload            *entity.LoginChallengePayload
+		rStartTrxErr       error
+		rChallengeRes      *entity.LoginChallenge
+		rChallengeErr      error
+		rUserErr           error
+		rEmailFailuresRes  *entity.LoginFailureSummary
+		rCredentialRes     *entity.TOTPCredential
+		rCredentialErr     error
+		rUpdateStepErr     error
+		rUseRecoveryRes    bool
+		rUseRecoveryErr    error
+		rCreateFailureErr  error
+		rUseChallengeErr   error
+		rCreateSessionErr  error
+		rCreateRefreshErr  error
+		rCommitTrxErr      error
+		wantErr            error
+		wantAnyErr         bool
+		wantFailure        bool
+		wantRecoveryCodeOK bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.CtxEnded(),
+			payload:    &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			wantAnyErr: true,
+		},
+		{
+			name:       "empty challenge token",
+			ctx:        context.Background(),
+			payload:    &entity.LoginChallengePayload{Code: code},
+			wantErr:    response.ErrUnauthorized("Invalid or expired challenge token"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "empty code",
+			ctx:        context.Background(),
+			payload:    &entity.LoginChallengePayload{ChallengeToken: "token"},
+			wantErr:    response.ErrInvalidMFACode,
+			wantAnyErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          context.Background(),
+			payload:      &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rStartTrxErr: errors.New("error start transaction"),
+			wantAnyErr:   true,
+		},
+		{
+			name:          "challenge not found",
+			ctx:           context.Background(),
+			payload:       &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rChallengeErr: response.ErrNotFound,
+			wantErr:       response.ErrUnauthorized("Invalid or expired challenge token"),
+			wantAnyErr:    true,
+		},
+		{
+			name:          "failed to get challenge",
+			ctx:           context.Background(),
+			payload:       &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rChallengeErr: errors.New("error get challenge"),
+			wantAnyErr:    true,
+		},
+		{
+			name:          "used challenge",
+			ctx:           context.Background(),
+			payload:       &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rChallengeRes: &entity.LoginChallenge{ID: 1, UserID: 123, ExpiresAt: now.Add(time.Minute), UsedAt: &now},
+			wantErr:       response.ErrUnauthorized("Invalid or expired challenge token"),
+			wantAnyErr:    true,
+		},
+		{
+			name:          "expired challenge",
+			ctx:           context.Background(),
+			payload:       &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rChallengeRes: &entity.LoginChallenge{ID: 1, UserID: 123, ExpiresAt: now.Add(-time.Minute)},
+			wantErr:       response.ErrUnauthorized("Invalid or expired challenge token"),
+			wantAnyErr:    true,
+		},
+		{
+			name:       "user not found",
+			ctx:        context.Background(),
+			payload:    &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rUserErr:   response.ErrNotFound,
+			wantErr:    response.ErrUnauthorized("Invalid or expired challenge token"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to get user",
+			ctx:        context.Background(),
+			payload:    &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rUserErr:   errors.New("error get user"),
+			wantAnyErr: true,
+		},
+		{
+			name:              "locked out email",
+			ctx:               context.Background(),
+			payload:           &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rEmailFailuresRes: &entity.LoginFailureSummary{Count: 5, LastFailedAt: &now},
+			wantErr:           response.ErrTooManyLoginAttempts,
+			wantAnyErr:        true,
+		},
+		{
+			name:           "TOTP credential not found",
+			ctx:            context.Background(),
+			payload:        &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rCredentialErr: response.ErrNotFound,
+			wantErr:        response.ErrUnauthorized("Invalid or expired challenge token"),
+			wantAnyErr:     true,
+		},
+		{
+			name:           "failed to get TOTP credential",
+			ctx:            context.Background(),
+			payload:        &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rCredentialErr: errors.New("error get TOTP credential"),
+			wantAnyErr:     true,
+		},
+		{
+			name:           "TOTP not enabled",
+			ctx:            context.Background(),
+			payload:        &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rCredentialRes: &entity.TOTPCredential{UserID: 123, Secret: secret},
+			wantErr:        response.ErrUnauthorized("Invalid or expired challenge token"),
+			wantAnyErr:     true,
+		},
+		{
+			name:           "failed to update the last used step",
+			ctx:            context.Background(),
+			payload:        &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rUpdateStepErr: errors.New("error update last used step"),
+			wantAnyErr:     true,
+		},
+		{
+			name:            "failed to use recovery code",
+			ctx:             context.Background(),
+			payload:         &entity.LoginChallengePayload{ChallengeToken: "token", Code: "abcd-efgh-ijkl-mnop"},
+			rUseRecoveryErr: errors.New("error use recovery code"),
+			wantAnyErr:      true,
+		},
+		{
+			name:           "replayed TOTP code",
+			ctx:            context.Background(),
+			payload:        &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rCredentialRes: &entity.TOTPCredential{UserID: 123, Secret: secret, EnabledAt: &now, LastUsedStep: now.Unix()},
+			wantErr:        response.ErrInvalidMFACode,
+			wantAnyErr:     true,
+			wantFailure:    true,
+		},
+		{
+			name:        "invalid code",
+			ctx:         context.Background(),
+			payload:     &entity.LoginChallengePayload{ChallengeToken: "token", Code: "abcd-efgh-ijkl-mnop"},
+			wantErr:     response.ErrInvalidMFACode,
+			wantAnyErr:  true,
+			wantFailure: true,
+		},
+		{
+			name:              "failed to record failed login",
+			ctx:               context.Background(),
+			payload:           &entity.LoginChallengePayload{ChallengeToken: "token", Code: "abcd-efgh-ijkl-mnop"},
+			rCreateFailureErr: errors.New("error create login failure"),
+			wantAnyErr:        true,
+		},
+		{
+			name:             "failed to use challenge",
+			ctx:              context.Background(),
+			payload:          &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rUseChallengeErr: errors.New("error use challenge"),
+			wantAnyErr:       true,
+		},
+		{
+			name:              "failed to create session",
+			ctx:               context.Background(),
+			payload:           &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rCreateSessionErr: errors.New("error create session"),
+			wantAnyErr:        true,
+		},
+		{
+			name:              "failed to create refresh token",
+			ctx:               context.Background(),
+			payload:           &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rCreateRefreshErr: errors.New("error create refresh token"),
+			wantAnyErr:        true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           context.Background(),
+			payload:       &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantAnyErr:    true,
+		},
+		{
+			name:    "success with TOTP code",
+			ctx:     context.Background(),
+			payload: &entity.LoginChallengePayload{ChallengeToken: "token", Code: code},
+		},
+		{
+			name:               "success with recovery code",
+			ctx:                context.Background(),
+			payload:            &entity.LoginChallengePayload{ChallengeToken: "token", Code: "ABCD-EFGH-IJKL-MNOP"},
+			rUseRecoveryRes:    true,
+			wantRecoveryCodeOK: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			challenge := tc.rChallengeRes
+			if challenge == nil {
+				challenge = activeChallenge
+			}
+
+			emailFailures := tc.rEmailFailuresRes
+			if emailFailures == nil {
+				emailFailures = &entity.LoginFailureSummary{}
+			}
+
+			credential := tc.rCredentialRes
+			if credential == nil {
+				credential = enabledCredential
+			}
+
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("GetUserByID", mock.Anything, 123).Return(&entity.User{ID: 123, Email: "foo@bar.com"}, tc.rUserErr)
+
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("GetLoginChallengeByHash", mock.Anything, mock.Anything, auth.HashToken("token")).Return(challenge, tc.rChallengeErr)
+			tokenRepo.On("UseLoginChallenge", mock.Anything, mock.Anything, 1).Return(tc.rUseChallengeErr)
+			tokenRepo.On("CreateSession", mock.Anything, mock.Anything, mock.Anything).Return(tc.rCreateSessionErr)
+			tokenRepo.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(tc.rCreateRefreshErr)
+
+			loginFailureRepo := &testmock.LoginFailureRepositoryInterface{}
+			loginFailureRepo.On("GetLoginFailureSummaryByEmail", mock.Anything, "foo@bar.com", mock.Anything).Return(emailFailures, nil)
+			loginFailureRepo.On("GetLoginFailureSummaryByIPAddress", mock.Anything, "127.0.0.1", mock.Anything).Return(&entity.LoginFailureSummary{}, nil)
+			loginFailureRepo.On("CreateLoginFailure", mock.Anything, mock.Anything).Return(tc.rCreateFailureErr)
+			loginFailureRepo.On("DeleteLoginFailuresByEmail", mock.Anything, "foo@bar.com").Return(nil)
+
+			mfaRepo := &testmock.MFARepositoryInterface{}
+			mfaRepo.On("GetTOTPCredential", mock.Anything, mock.Anything, 123).Return(credential, tc.rCredentialErr)
+			mfaRepo.On("UpdateTOTPLastUsedStep", mock.Anything, mock.Anything, 123, mock.Anything).Return(tc.rUpdateStepErr)
+			mfaRepo.On("UseRecoveryCode", mock.Anything, mock.Anything, 123, auth.HashToken("abcdefghijklmnop")).Return(tc.rUseRecoveryRes, tc.rUseRecoveryErr)
+
+			auditRecorder := newAuditRecorder()
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, &testmock.Mailer{}, dbTransactionRepo, userRepo, tokenRepo, loginFailureRepo, mfaRepo, nil, &testmock.IdentityRepositoryInterface{}, auditRecorder)
+			res, err := uc.VerifyLoginChallenge(tc.ctx, "127.0.0.1", "curl/8.5.0", tc.payload)
+			assert.Equal(t, tc.wantAnyErr, err != nil, err)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if tc.wantFailure {
+				loginFailureRepo.AssertCalled(t, "CreateLoginFailure", mock.Anything, &entity.LoginFailure{Email: "foo@bar.com", IPAddress: "127.0.0.1"})
+				tokenRepo.AssertNotCalled(t, "UseLoginChallenge", mock.Anything, mock.Anything, mock.Anything)
+			}
+
+			if tc.wantFailure && tc.rCreateFailureErr == nil {
+				auditRecorder.AssertCalled(t, "Record", mock.Anything, nil, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+					return event.Action == entity.AuditActionLoginFailed && event.TargetID == "123" && !strings.Contains(string(event.Diff), "foo@bar.com")
+				}))
+			}
+
+			if !tc.wantAnyErr {
+				assert.Equal(t, "anaccesstoken", res.AccessToken)
+				assert.NotEmpty(t, res.RefreshToken)
+				assert.False(t, res.MFARequired)
+				tokenRepo.AssertCalled(t, "UseLoginChallenge", mock.Anything, mock.Anything, 1)
+				loginFailureRepo.AssertCalled(t, "DeleteLoginFailuresByEmail", mock.Anything, "foo@bar.com")
+
+				if tc.wantRecoveryCodeOK {
+					mfaRepo.AssertNotCalled(t, "UpdateTOTPLastUsedStep", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				} else {
+					mfaRepo.AssertCalled(t, "UpdateTOTPLastUsedStep", mock.Anything, mock.Anything, 123, mock.Anything)
+				}
+			}
+		})
+	}
+}
+
+func TestStartOIDCLogin(t *testing.T) {
+	testcases := []struct {
+		name            string
+		ctx             context.Context
+		disabled        bool
+		rAuthURLErr     error
+		rCreateStateErr error
+		wantErr         error
+		wantAnyErr      bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.CtxEnded(),
+			wantAnyErr: true,
+		},
+		{
+			name:       "OpenID Connect login disabled",
+			ctx:        context.Background(),
+			disabled:   true,
+			wantErr:    response.ErrOIDCNotEnabled,
+			wantAnyErr: true,
+		},
+		{
+			name:        "failed to build the authorization URL",
+			ctx:         context.Background(),
+			rAuthURLErr: errors.New("error discovery"),
+			wantAnyErr:  true,
+		},
+		{
+			name:            "failed to create login state",
+			ctx:             context.Background(),
+			rCreateStateErr: errors.New("error create login state"),
+			wantAnyErr:      true,
+		},
+		{
+			name: "success",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var codeChallenge string
+			identityProvider := &testmock.IdentityProvider{}
+			identityProvider.On("AuthCodeURL", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) { codeChallenge = args.String(3) }).
+				Return("https://accounts.example.com/authorize?state=state", tc.rAuthURLErr)
+
+			var loginState *entity.OIDCLoginState
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("CreateOIDCLoginState", mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) { loginState = args.Get(1).(*entity.OIDCLoginState) }).
+				Return(tc.rCreateStateErr)
+
+			var ip oidc.IdentityProvider = identityProvider
+			if tc.disabled {
+				ip = nil
+			}
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, &testmock.Mailer{}, &testmock.PostgresTransactionRepositoryInterface{}, &testmock.UserRepositoryInterface{}, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, &testmock.MFARepositoryInterface{}, ip, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
+			res, err := uc.StartOIDCLogin(tc.ctx)
+			assert.Equal(t, tc.wantAnyErr, err != nil, err)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if !tc.wantAnyErr {
+				assert.Equal(t, "https://accounts.example.com/authorize?state=state", res.AuthorizationURL)
+				assert.NotEmpty(t, loginState.StateHash)
+				assert.NotEmpty(t, loginState.Nonce)
+				assert.Equal(t, oidc.CodeChallengeS256(loginState.CodeVerifier), codeChallenge)
+				assert.True(t, loginState.ExpiresAt.After(time.Now()))
+			}
+		})
+	}
+}
+
+func TestOIDCLogin(t *testing.T) {
+	now := time.Now()
+	issuer := "https://accounts.example.com"
+	activeState := &entity.OIDCLoginState{ID: 1, StateHash: auth.HashToken("state"), Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: now.Add(time.Minute)}
+	verifiedClaims := &oidc.Claims{Subject: "sub", Email: "Foo@Bar.com", EmailVerified: true, Name: "Foo"}
+	linkedIdentity := &entity.UserIdentity{ID: 1, UserID: 123, Provider: issuer, Subject: "sub", Email: "foo@bar.com"}
+	verifiedUser := &entity.User{ID: 123, Email: "foo@bar.com", VerifiedAt: &now}
+
+	testcases := []struct {
+		name              string
+		ctx               context.Context
+		disabled          bool
+		payload           *entity.OIDCCallbackPayload
+		rStartTrxErr      error
+		rStateRes         *entity.OIDCLoginState
+		rStateErr         error
+		rUseStateErr      error
+		rCommitTrxErr     error
+		rClaimsRes        *oidc.Claims
+		rExchangeErr      error
+		rIdentityRes      *entity.UserIdentity
+		rIdentityErr      error
+		rUserByIDErr      error
+		rUserByEmailRes   *entity.User
+		rUserByEmailErr   error
+		rCreateUserErr    error
+		rUpsertErr        error
+		rCredentialRes    *entity.TOTPCredential
+		rCredentialErr    error
+		rCreateSessionErr error
+		rCreateRefreshErr error
+		wantErr           error
+		wantAnyErr        bool
+		wantCreatedUser   bool
+		wantLinked        bool
+		wantMFA           bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.CtxEnded(),
+			payload:    &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			wantAnyErr: true,
+		},
+		{
+			name:       "OpenID Connect login disabled",
+			ctx:        context.Background(),
+			disabled:   true,
+			payload:    &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			wantErr:    response.ErrOIDCNotEnabled,
+			wantAnyErr: true,
+		},
+		{
+			name:       "empty state",
+			ctx:        context.Background(),
+			payload:    &entity.OIDCCallbackPayload{Code: "code"},
+			wantErr:    response.ErrUnauthorized("Invalid or expired login state"),
+			wantAnyErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          context.Background(),
+			payload:      &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rStartTrxErr: errors.New("error start transaction"),
+			wantAnyErr:   true,
+		},
+		{
+			name:       "login state not found",
+			ctx:        context.Background(),
+			payload:    &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rStateErr:  response.ErrNotFound,
+			wantErr:    response.ErrUnauthorized("Invalid or expired login state"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to get login state",
+			ctx:        context.Background(),
+			payload:    &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rStateErr:  errors.New("error get login state"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "used login state",
+			ctx:        context.Background(),
+			payload:    &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rStateRes:  &entity.OIDCLoginState{ID: 1, ExpiresAt: now.Add(time.Minute), UsedAt: &now},
+			wantErr:    response.ErrUnauthorized("Invalid or expired login state"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "expired login state",
+			ctx:        context.Background(),
+			payload:    &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rStateRes:  &entity.OIDCLoginState{ID: 1, ExpiresAt: now.Add(-time.Minute)},
+			wantErr:    response.ErrUnauthorized("Invalid or expired login state"),
+			wantAnyErr: true,
+		},
+		{
+			name:         "failed to use login state",
+			ctx:          context.Background(),
+			payload:      &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rUseStateErr: errors.New("error use login state"),
+			wantAnyErr:   true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           context.Background(),
+			payload:       &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantAnyErr:    true,
+		},
+		{
+			name:         "provider rejected the code",
+			ctx:          context.Background(),
+			payload:      &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rExchangeErr: fmt.Errorf("%w: invalid_grant", oidc.ErrTokenExchange),
+			wantErr:      response.ErrUnauthorized("Login at the identity provider failed"),
+			wantAnyErr:   true,
+		},
+		{
+			name:         "invalid ID token",
+			ctx:          context.Background(),
+			payload:      &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rExchangeErr: fmt.Errorf("%w: bad nonce", oidc.ErrInvalidIDToken),
+			wantErr:      response.ErrUnauthorized("Login at the identity provider failed"),
+			wantAnyErr:   true,
+		},
+		{
+			name:         "provider unreachable",
+			ctx:          context.Background(),
+			payload:      &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rExchangeErr: errors.New("connection refused"),
+			wantAnyErr:   true,
+		},
+		{
+			name:         "failed to get identity",
+			ctx:          context.Background(),
+			payload:      &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rIdentityErr: errors.New("error get identity"),
+			wantAnyErr:   true,
+		},
+		{
+			name:         "failed to get linked user",
+			ctx:          context.Background(),
+			payload:      &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rIdentityRes: linkedIdentity,
+			rUserByIDErr: errors.New("error get user"),
+			wantAnyErr:   true,
+		},
+		{
+			name:         "success with linked identity",
+			ctx:          context.Background(),
+			payload:      &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rIdentityRes: linkedIdentity,
+		},
+		{
+			name:         "linked user deleted is linked again by email",
+			ctx:          context.Background(),
+			payload:      &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rIdentityRes: linkedIdentity,
+			rUserByIDErr: response.ErrNotFound,
+			wantLinked:   true,
+		},
+		{
+			name:       "email not verified by the provider",
+			ctx:        context.Background(),
+			payload:    &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rClaimsRes: &oidc.Claims{Subject: "sub", Email: "foo@bar.com"},
+			wantErr:    response.ErrEmailNotVerified,
+			wantAnyErr: true,
+		},
+		{
+			name:       "no email",
+			ctx:        context.Background(),
+			payload:    &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rClaimsRes: &oidc.Claims{Subject: "sub", EmailVerified: true},
+			wantErr:    response.ErrEmailNotVerified,
+			wantAnyErr: true,
+		},
+		{
+			name:            "failed to get user by email",
+			ctx:             context.Background(),
+			payload:         &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rUserByEmailErr: errors.New("error get user"),
+			wantAnyErr:      true,
+		},
+		{
+			name:            "existing user with unverified email",
+			ctx:             context.Background(),
+			payload:         &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rUserByEmailRes: &entity.User{ID: 123, Email: "foo@bar.com"},
+			wantErr:         response.ErrOIDCAccountNotLinkable,
+			wantAnyErr:      true,
+		},
+		{
+			name:       "success linking existing user",
+			ctx:        context.Background(),
+			payload:    &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			wantLinked: true,
+		},
+		{
+			// The local email is matched whatever case either side is in, so no second account is created
+			name:            "success linking existing user with a mixed-case email",
+			ctx:             context.Background(),
+			payload:         &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rClaimsRes:      &oidc.Claims{Subject: "sub", Email: " FOO@bar.com ", EmailVerified: true, Name: "Foo"},
+			rUserByEmailRes: &entity.User{ID: 123, Email: "Foo@Bar.COM", VerifiedAt: &now},
+			wantLinked:      true,
+		},
+		{
+			name:            "failed to create user",
+			ctx:             context.Background(),
+			payload:         &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rUserByEmailErr: response.ErrNotFound,
+			rCreateUserErr:  errors.New("error create user"),
+			wantAnyErr:      true,
+		},
+		{
+			name:            "email registered meanwhile",
+			ctx:             context.Background(),
+			payload:         &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rUserByEmailErr: response.ErrNotFound,
+			rCreateUserErr:  response.ErrDuplicateEmail,
+			wantErr:         response.ErrDuplicateEmail,
+			wantAnyErr:      true,
+		},
+		{
+			name:            "success creating user",
+			ctx:             context.Background(),
+			payload:         &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rUserByEmailErr: response.ErrNotFound,
+			wantCreatedUser: true,
+			wantLinked:      true,
+		},
+		{
+			name:       "failed to link identity",
+			ctx:        context.Background(),
+			payload:    &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rUpsertErr: errors.New("error upsert identity"),
+			wantAnyErr: true,
+		},
+		{
+			name:           "failed to get TOTP credential",
+			ctx:            context.Background(),
+			payload:        &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rIdentityRes:   linkedIdentity,
+			rCredentialErr: errors.New("error get credential"),
+			wantAnyErr:     true,
+		},
+		{
+			name:           "success with two-factor authentication",
+			ctx:            context.Background(),
+			payload:        &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rIdentityRes:   linkedIdentity,
+			rCredentialRes: &entity.TOTPCredential{UserID: 123, EnabledAt: &now},
+			wantMFA:        true,
+		},
+		{
+			name:              "failed to create session",
+			ctx:               context.Background(),
+			payload:           &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rIdentityRes:      linkedIdentity,
+			rCreateSessionErr: errors.New("error create session"),
+			wantAnyErr:        true,
+		},
+		{
+			name:              "failed to create refresh token",
+			ctx:               context.Background(),
+			payload:           &entity.OIDCCallbackPayload{State: "state", Code: "code"},
+			rIdentityRes:      linkedIdentity,
+			rCreateRefreshErr: errors.New("error create refresh token"),
+			wantAnyErr:        true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			loginState := tc.rStateRes
+			if loginState == nil {
+				loginState = activeState
+			}
+
+			claims := tc.rClaimsRes
+			if claims == nil {
+				claims = verifiedClaims
+			}
+
+			identityErr := tc.rIdentityErr
+			if tc.rIdentityRes == nil && identityErr == nil {
+				identityErr = response.ErrNotFound
+			}
+
+			userByEmail := tc.rUserByEmailRes
+			if userByEmail == nil {
+				userByEmail = verifiedUser
+			}
+
+			credentialErr := tc.rCredentialErr
+			if tc.rCredentialRes == nil && credentialErr == nil {
+				credentialErr = response.ErrNotFound
+			}
+
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			tokenRepo := &testmock.TokenRepositoryInterface{}
+			tokenRepo.On("GetOIDCLoginStateByHash", mock.Anything, mock.Anything, auth.HashToken("state")).Return(loginState, tc.rStateErr)
+			tokenRepo.On("UseOIDCLoginState", mock.Anything, mock.Anything, 1).Return(tc.rUseStateErr)
+			tokenRepo.On("CreateSession", mock.Anything, mock.Anything, mock.Anything).Return(tc.rCreateSessionErr)
+			tokenRepo.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(tc.rCreateRefreshErr)
+			tokenRepo.On("CreateLoginChallenge", mock.Anything, mock.Anything).Return(nil)
+
+			identityProvider := &testmock.IdentityProvider{}
+			identityProvider.On("Issuer").Return(issuer)
+			identityProvider.On("Exchange", mock.Anything, "code", "verifier", "nonce").Return(claims, tc.rExchangeErr)
+
+			identityRepo := &testmock.IdentityRepositoryInterface{}
+			identityRepo.On("GetUserIdentity", mock.Anything, issuer, "sub").Return(tc.rIdentityRes, identityErr)
+			identityRepo.On("UpsertUserIdentity", mock.Anything, mock.Anything).Return(tc.rUpsertErr)
+
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("GetUserByID", mock.Anything, 123).Return(verifiedUser, tc.rUserByIDErr)
+			userRepo.On("GetUserByEmail", mock.Anything, "foo@bar.com").Return(userByEmail, tc.rUserByEmailErr)
+			userRepo.On("CreateUser", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				args.Get(1).(*entity.User).ID = 456
+			}).Return(tc.rCreateUserErr)
+
+			passwordHasher := &testmock.PasswordHasher{}
+			passwordHasher.On("GenerateFromPassword", mock.Anything).Return("hashed", nil)
+
+			mfaRepo := &testmock.MFARepositoryInterface{}
+			mfaRepo.On("GetTOTPCredential", mock.Anything, mock.Anything, mock.Anything).Return(tc.rCredentialRes, credentialErr)
+
+			var ip oidc.IdentityProvider = identityProvider
+			if tc.disabled {
+				ip = nil
+			}
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), passwordHasher, &testmock.Mailer{}, dbTransactionRepo, userRepo, tokenRepo, &testmock.LoginFailureRepositoryInterface{}, mfaRepo, ip, identityRepo, newAuditRecorder())
+			res, err := uc.OIDCLogin(tc.ctx, "127.0.0.1", "curl/8.5.0", tc.payload)
+			assert.Equal(t, tc.wantAnyErr, err != nil, err)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if tc.wantAnyErr {
+				return
+			}
+
+			if tc.wantCreatedUser {
+				userRepo.AssertCalled(t, "CreateUser", mock.Anything, mock.MatchedBy(func(user *entity.User) bool {
+					return user.Email == "foo@bar.com" && user.Fullname == "Foo" && user.Role == entity.UserRoleCustomer && user.IsVerified() && user.CryptedPassword == "hashed"
+				}))
+			} else {
+				userRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+			}
+
+			if tc.wantLinked {
+				userID := 123
+				if tc.wantCreatedUser {
+					userID = 456
+				}
+
+				identityRepo.AssertCalled(t, "UpsertUserIdentity", mock.Anything, &entity.UserIdentity{UserID: userID, Provider: issuer, Subject: "sub", Email: "foo@bar.com"})
+			} else {
+				identityRepo.AssertNotCalled(t, "UpsertUserIdentity", mock.Anything, mock.Anything)
+			}
+
+			if tc.wantMFA {
+				assert.True(t, res.MFARequired)
+				assert.NotEmpty(t, res.ChallengeToken)
+				assert.Empty(t, res.AccessToken)
+			} else {
+				assert.Equal(t, "anaccesstoken", res.AccessToken)
+				assert.NotEmpty(t, res.RefreshToken)
+			}
+		})
+	}
+}
+
+func TestEnrollTOTP(t *testing.T) {
+	testcases := []struct {
+		name       string
+		ctx        *gin.Context
+		rUserErr   error
+		rUpsertErr error
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.GinCtxEnded(),
+			wantAnyErr: true,
+		},
+		{
+			name:       "user not found",
+			ctx:        fixture.GinCtxBackground(),
+			rUserErr:   response.ErrNotFound,
+			wantErr:    response.ErrNotFound,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to get user",
+			ctx:        fixture.GinCtxBackground(),
+			rUserErr:   errors.New("error get user"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "TOTP already enabled",
+			ctx:        fixture.GinCtxBackground(),
+			rUpsertErr: response.ErrTOTPAlreadyEnabled,
+			wantErr:    response.ErrTOTPAlreadyEnabled,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to store TOTP credential",
+			ctx:        fixture.GinCtxBackground(),
+			rUpsertErr: errors.New("error upsert TOTP credential"),
+			wantAnyErr: true,
+		},
+		{
+			name: "success",
+			ctx:  fixture.GinCtxBackground(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("GetUserByID", mock.Anything, mock.Anything).Return(&entity.User{ID: 123, Email: "foo@bar.com"}, tc.rUserErr)
+
+			mfaRepo := &testmock.MFARepositoryInterface{}
+			mfaRepo.On("UpsertTOTPCredential", mock.Anything, mock.Anything).Return(tc.rUpsertErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, &testmock.Mailer{}, &testmock.PostgresTransactionRepositoryInterface{}, userRepo, &testmock.TokenRepositoryInterface{}, &testmock.LoginFailureRepositoryInterface{}, mfaRepo, nil, &testmock.IdentityRepositoryInterface{}, newAuditRecorder())
+			res, err := uc.EnrollTOTP(tc.ctx)
+			assert.Equal(t, tc.wantAnyErr, err != nil)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if !tc.wantAnyErr {
+				assert.NotEmpty(t, res.Secret)
+				assert.Regexp(t, regexp.MustCompile(`^otpauth://totp/Book%20Store:foo@bar.com\?`), res.URI)
+				mfaRepo.AssertCalled(t, "UpsertTOTPCredential", mock.Anything, &entity.TOTPCredential{UserID: 123, Secret: res.Secret})
+			}
+		})
+	}
+}
+
+func TestConfirmTOTP(t *testing.T) {
+	now := time.Now()
+	secret, _ := auth.GenerateTOTPSecret()
+	code, _ := auth.GenerateTOTPCode(secret, now)
+
+	testcases := []struct {
+		name            string
+		ctx             *gin.Context
+		payload         *entity.TOTPCodePayload
+		rStartTrxErr    error
+		rCredentialRes  *entity.TOTPCredential
+		rCredentialErr  error
+		rEnableErr      error
+		rReplaceCodeErr error
+		rRecordErr      error
+		rCommitTrxErr   error
+		wantErr         error
+		wantAnyErr      bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.GinCtxEnded(),
+			payload:    &entity.TOTPCodePayload{Code: code},
+			wantAnyErr: true,
+		},
+		{
+			name:       "empty code",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.TOTPCodePayload{},
+			wantErr:    response.ErrInvalidMFACode,
+			wantAnyErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          fixture.GinCtxBackground(),
+			payload:      &entity.TOTPCodePayload{Code: code},
+			rStartTrxErr: errors.New("error start transaction"),
+			wantAnyErr:   true,
+		},
+		{
+			name:           "TOTP not enrolled",
+			ctx:            fixture.GinCtxBackground(),
+			payload:        &entity.TOTPCodePayload{Code: code},
+			rCredentialErr: response.ErrNotFound,
+			wantErr:        response.ErrTOTPNotEnrolled,
+			wantAnyErr:     true,
+		},
+		{
+			name:           "failed to get TOTP credential",
+			ctx:            fixture.GinCtxBackground(),
+			payload:        &entity.TOTPCodePayload{Code: code},
+			rCredentialErr: errors.New("error get TOTP credential"),
+			wantAnyErr:     true,
+		},
+		{
+			name:           "TOTP already enabled",
+			ctx:            fixture.GinCtxBackground(),
+			payload:        &entity.TOTPCodePayload{Code: code},
+			rCredentialRes: &entity.TOTPCredential{UserID: 123, Secret: secret, EnabledAt: &now},
+			wantErr:        response.ErrTOTPAlreadyEnabled,
+			wantAnyErr:     true,
+		},
+		{
+			name:       "invalid code",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.TOTPCodePayload{Code: "abcdef"},
+			wantErr:    response.ErrInvalidMFACode,
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to enable TOTP credential",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.TOTPCodePayload{Code: code},
+			rEnableErr: errors.New("error enable TOTP credential"),
+			wantAnyErr: true,
+		},
+		{
+			name:            "failed to store recovery codes",
+			ctx:             fixture.GinCtxBackground(),
+			payload:         &entity.TOTPCodePayload{Code: code},
+			rReplaceCodeErr: errors.New("error replace recovery codes"),
+			wantAnyErr:      true,
+		},
+		{
+			name:       "failed to record the enabling",
+			ctx:        fixture.GinCtxBackground(),
+			payload:    &entity.TOTPCodePayload{Code: code},
+			rRecordErr: errors.New("error record"),
+			wantAnyErr: true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           fixture.GinCtxBackground(),
+			payload:       &entity.TOTPCodePayload{Code: code},
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantAnyErr:    true,
+		},
+		{
+			name:    "success",
+			ctx:     fixture.GinCtxBackground(),
+			payload: &entity.TOTPCodePayload{Code: code},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			credential := tc.rCredentialRes
+			if credential == nil {
+				credential = &entity.TOTPCredential{UserID: 123, Secret: secret}
+			}
+
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			mfaRepo := &testmock.MFARepositoryInterface{}
+			mfaRepo.On("GetTOTPCredential", mock.Anything, mock.Anything, mock.Anything).Return(credential, tc.rCredentialErr)
+			mfaRepo.On("EnableTOTPCredential", mock.Anything, mock.Anything, 123, mock.Anything).Return(tc.rEnableErr)
+			mfaRepo.On("ReplaceRecoveryCodes", mock.Anything, mock.Anything, 123, mock.Anything).Return(tc.rReplaceCodeErr)
+
+			auditRecorder := &testmock.AuditRecorder{}
+			auditRecorder.On("Record", mock.Anything, mock.Anything, mock.Anything).Return(tc.rRecordErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), &testmock.PasswordHasher{}, &testmock.Mailer{}, dbTransactionRepo, &testmock.UserRepositoryInterface{}, &testmock.TokenRepositoryInterface{}, &testmock.LoginFailureRepositoryInterface{}, mfaRepo, nil, &testmock.IdentityRepositoryInterface{}, auditRecorder)
+			res, err := uc.ConfirmTOTP(tc.ctx, tc.payload)
+			assert.Equal(t, tc.wantAnyErr, err != nil)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if !tc.wantAnyErr {
+				assert.Len(t, res.RecoveryCodes, 10)
+				mfaRepo.AssertCalled(t, "ReplaceRecoveryCodes", mock.Anything, mock.Anything, 123, mock.MatchedBy(func(codeHashes []string) bool {
+					return len(codeHashes) == 10 && codeHashes[0] == auth.HashToken(auth.NormalizeRecoveryCode(res.RecoveryCodes[0]))
+				}))
+				auditRecorder.AssertCalled(t, "Record", mock.Anything, mock.Anything, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+					return event.Action == entity.AuditActionTOTPEnabled && event.TargetID == "123"
+				}))
+			}
+		})
+	}
+}
+
+func TestDisableTOTP(t *testing.T) {
+	testcases := []struct {
+		name              string
+		ctx               *gin.Context
+		rUserErr          error
+		compareErr        error
+		rStartTrxErr      error
+		rCredentialErr    error
+		rDeleteErr        error
+		rDeleteRecoverErr error
+		rRecordErr        error
+		rCommitTrxErr     error
+		wantErr           error
+		wantAnyErr        bool
+	}{
+		{
+			name:       "deadline context",
+			ctx:        fixture.GinCtxEnded(),
+			wantAnyErr: true,
+		},
+		{
+			name:       "failed to get user",
+			ctx:        fixture.GinCtxBackground(),
+			rUserErr:   errors.New("error get user"),
+			wantAnyErr: true,
+		},
+		{
+			name:       "wrong password",
+			ctx:        fixture.GinCtxBackground(),
+			compareErr: errors.New("mismatched password"),
+			wantErr:    response.ErrInvalidPassword,
+			wantAnyErr: true,
+		},
+		{
+			name:         "failed to start transaction",
+			ctx:          fixture.GinCtxBackground(),
+			rStartTrxErr: errors.New("error start transaction"),
+			wantAnyErr:   true,
+		},
+		{
+			name:           "TOTP not enrolled",
+			ctx:            fixture.GinCtxBackground(),
+			rCredentialErr: response.ErrNotFound,
+			wantErr:        response.ErrTOTPNotEnrolled,
+			wantAnyErr:     true,
+		},
+		{
+			name:           "failed to get TOTP credential",
+			ctx:            fixture.GinCtxBackground(),
+			rCredentialErr: errors.New("error get TOTP credential"),
+			wantAnyErr:     true,
+		},
+		{
+			name:       "failed to delete TOTP credential",
+			ctx:        fixture.GinCtxBackground(),
+			rDeleteErr: errors.New("error delete TOTP credential"),
+			wantAnyErr: true,
+		},
+		{
+			name:              "failed to delete recovery codes",
+			ctx:               fixture.GinCtxBackground(),
+			rDeleteRecoverErr: errors.New("error delete recovery codes"),
+			wantAnyErr:        true,
+		},
+		{
+			name:       "failed to record the disabling",
+			ctx:        fixture.GinCtxBackground(),
+			rRecordErr: errors.New("error record"),
+			wantAnyErr: true,
+		},
+		{
+			name:          "failed to commit transaction",
+			ctx:           fixture.GinCtxBackground(),
+			rCommitTrxErr: errors.New("error commit transaction"),
+			wantAnyErr:    true,
+		},
+		{
+			name: "success",
+			ctx:  fixture.GinCtxBackground(),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			passwordHasher := &testmock.PasswordHasher{}
+			passwordHasher.On("CompareHashAndPassword", "crypted", "12345").Return(tc.compareErr)
+
+			dbTransactionRepo := &testmock.PostgresTransactionRepositoryInterface{}
+			dbTransactionRepo.On("StartTransactionQuery", mock.Anything).Return(&sqlx.Tx{}, tc.rStartTrxErr)
+			dbTransactionRepo.On("CommitTransactionQuery", mock.Anything, mock.Anything).Return(tc.rCommitTrxErr)
+			dbTransactionRepo.On("RollbackTransactionQuery", mock.Anything, mock.Anything).Return(nil)
+
+			userRepo := &testmock.UserRepositoryInterface{}
+			userRepo.On("GetUserByID", mock.Anything, mock.Anything).Return(&entity.User{ID: 123, CryptedPassword: "crypted"}, tc.rUserErr)
+
+			mfaRepo := &testmock.MFARepositoryInterface{}
+			mfaRepo.On("GetTOTPCredential", mock.Anything, mock.Anything, 123).Return(&entity.TOTPCredential{UserID: 123}, tc.rCredentialErr)
+			mfaRepo.On("DeleteTOTPCredential", mock.Anything, mock.Anything, 123).Return(tc.rDeleteErr)
+			mfaRepo.On("DeleteRecoveryCodes", mock.Anything, mock.Anything, 123).Return(tc.rDeleteRecoverErr)
+
+			auditRecorder := &testmock.AuditRecorder{}
+			auditRecorder.On("Record", mock.Anything, mock.Anything, mock.Anything).Return(tc.rRecordErr)
+
+			uc := usecase.NewUserUsecase(newLogger(), time.Minute, time.Hour, "http://localhost:3000/reset-password", "http://localhost:9999/v1/users/verify", newTokenSigner(), passwordHasher, &testmock.Mailer{}, dbTransactionRepo, userRepo, &testmock.TokenRepositoryInterface{}, &testmock.LoginFailureRepositoryInterface{}, mfaRepo, nil, &testmock.IdentityRepositoryInterface{}, auditRecorder)
+			err := uc.DisableTOTP(tc.ctx, &entity.DisableTOTPPayload{Password: "12345"})
+			assert.Equal(t, tc.wantAnyErr, err != nil)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, tc.wantErr, err)
+			}
+
+			if !tc.wantAnyErr {
+				mfaRepo.AssertCalled(t, "DeleteTOTPCredential", mock.Anything, mock.Anything, 123)
+				mfaRepo.AssertCalled(t, "DeleteRecoveryCodes", mock.Anything, mock.Anything, 123)
+				auditRecorder.AssertCalled(t, "Record", mock.Anything, mock.Anything, mock.MatchedBy(func(event *entity.AuditEvent) bool {
+					return event.Action == entity.AuditActionTOTPDisabled && event.TargetID == "123"
+				}))
+			}
 		})
 	}
 }
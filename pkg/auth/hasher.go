@@ -1,23 +1,227 @@
 package auth
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// HashAlgorithmArgon2id hashes passwords with Argon2id
+	HashAlgorithmArgon2id = "argon2id"
+	// HashAlgorithmBcrypt hashes passwords with bcrypt
+	HashAlgorithmBcrypt = "bcrypt"
+
+	// argon2idPrefix starts every Argon2id hash, bcrypt hashes start with $2a$, $2b$ or $2y$
+	argon2idPrefix = "$argon2id$"
+)
+
+var (
+	// ErrUnsupportedHashAlgorithm is returned for a password hash algorithm other than argon2id and bcrypt
+	ErrUnsupportedHashAlgorithm = errors.New("auth: unsupported password hash algorithm")
+	// ErrInvalidHash is returned for a hashed password in an unknown format
+	ErrInvalidHash = errors.New("auth: invalid password hash")
+	// ErrMismatchedHashAndPassword is returned when the password does not match an Argon2id hash
+	ErrMismatchedHashAndPassword = errors.New("auth: hashed password is not the hash of the given password")
+)
 
 // PasswordHasher defines an interface for password hashing
 type PasswordHasher interface {
 	GenerateFromPassword(password string) (string, error)
 	CompareHashAndPassword(hashedPassword, password string) error
+	// NeedsRehash reports whether the hashed password was made by another algorithm or with other parameters
+	NeedsRehash(hashedPassword string) bool
+}
+
+// NewPasswordHasher returns the password hasher of the given algorithm, both hashers compare the hashes of either algorithm
+func NewPasswordHasher(algorithm string, argon2idParams Argon2idParams, bcryptCost int) (PasswordHasher, error) {
+	switch algorithm {
+	case HashAlgorithmArgon2id:
+		return NewArgon2idPasswordHasher(argon2idParams), nil
+	case HashAlgorithmBcrypt:
+		return &BcryptPasswordHasher{Cost: bcryptCost}, nil
+	default:
+		return nil, ErrUnsupportedHashAlgorithm
+	}
 }
 
-// BcryptPasswordHasher is the default implementation using bcrypt
-type BcryptPasswordHasher struct{}
+// BcryptPasswordHasher hashes passwords with bcrypt
+type BcryptPasswordHasher struct {
+	// Cost is the bcrypt cost, bcrypt.DefaultCost when zero
+	Cost int
+}
+
+func (h *BcryptPasswordHasher) cost() int {
+	if h.Cost == 0 {
+		return bcrypt.DefaultCost
+	}
+
+	return h.Cost
+}
 
 // GenerateFromPassword generates a bcrypt hash of the password
 func (h *BcryptPasswordHasher) GenerateFromPassword(password string) (string, error) {
-	byteCryptedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	byteCryptedPassword, err := bcrypt.GenerateFromPassword([]byte(password), h.cost())
 	return string(byteCryptedPassword), err
 }
 
-// GenerateFromPassword compares a bcrypt hashed password with its possible plaintext equivalent
+// CompareHashAndPassword compares a hashed password with its possible plaintext equivalent
 func (h *BcryptPasswordHasher) CompareHashAndPassword(hashedPassword, password string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	return compareHashAndPassword(hashedPassword, password)
+}
+
+// NeedsRehash reports whether the hashed password is not a bcrypt hash of the configured cost
+func (h *BcryptPasswordHasher) NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	return err != nil || cost != h.cost()
+}
+
+// Argon2idParams holds the parameters of Argon2id
+type Argon2idParams struct {
+	// Memory is the memory used in KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2idParams follows the second recommended option of RFC 9106 with a 64 MiB memory
+var DefaultArgon2idParams = Argon2idParams{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 4,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// Argon2idPasswordHasher hashes passwords with Argon2id in the PHC string format,
+// e.g. $argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>
+type Argon2idPasswordHasher struct {
+	params Argon2idParams
+}
+
+// NewArgon2idPasswordHasher creates an Argon2id password hasher, the zero parameters are taken from DefaultArgon2idParams
+func NewArgon2idPasswordHasher(params Argon2idParams) *Argon2idPasswordHasher {
+	if params.Memory == 0 {
+		params.Memory = DefaultArgon2idParams.Memory
+	}
+	if params.Iterations == 0 {
+		params.Iterations = DefaultArgon2idParams.Iterations
+	}
+	if params.Parallelism == 0 {
+		params.Parallelism = DefaultArgon2idParams.Parallelism
+	}
+	if params.SaltLength == 0 {
+		params.SaltLength = DefaultArgon2idParams.SaltLength
+	}
+	if params.KeyLength == 0 {
+		params.KeyLength = DefaultArgon2idParams.KeyLength
+	}
+
+	return &Argon2idPasswordHasher{params: params}
+}
+
+// GenerateFromPassword generates an Argon2id hash of the password with a random salt
+func (h *Argon2idPasswordHasher) GenerateFromPassword(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return encodeArgon2idHash(h.params, salt, key), nil
+}
+
+// CompareHashAndPassword compares a hashed password with its possible plaintext equivalent
+func (h *Argon2idPasswordHasher) CompareHashAndPassword(hashedPassword, password string) error {
+	return compareHashAndPassword(hashedPassword, password)
+}
+
+// NeedsRehash reports whether the hashed password is not an Argon2id hash of the configured parameters
+func (h *Argon2idPasswordHasher) NeedsRehash(hashedPassword string) bool {
+	params, salt, key, err := decodeArgon2idHash(hashedPassword)
+	if err != nil {
+		return true
+	}
+
+	return params.Memory != h.params.Memory ||
+		params.Iterations != h.params.Iterations ||
+		params.Parallelism != h.params.Parallelism ||
+		uint32(len(salt)) != h.params.SaltLength ||
+		uint32(len(key)) != h.params.KeyLength
+}
+
+// compareHashAndPassword compares the password with an Argon2id or bcrypt hash, picked by the prefix of the hash
+func compareHashAndPassword(hashedPassword, password string) error {
+	if !strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	}
+
+	params, salt, key, err := decodeArgon2idHash(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return ErrMismatchedHashAndPassword
+	}
+
+	return nil
+}
+
+func encodeArgon2idHash(params Argon2idParams, salt, key []byte) string {
+	return fmt.Sprintf(
+		"%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		params.Memory,
+		params.Iterations,
+		params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+func decodeArgon2idHash(hashedPassword string) (Argon2idParams, []byte, []byte, error) {
+	params := Argon2idParams{}
+
+	// "", "argon2id", "v=19", "m=65536,t=3,p=4", salt, key
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 || parts[1] != HashAlgorithmArgon2id {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	version := 0
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism)
+	if err != nil || params.Iterations == 0 || params.Parallelism == 0 {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
 }
@@ -0,0 +1,82 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testArgon2idParams = auth.Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestNewPasswordHasher(t *testing.T) {
+	hasher, err := auth.NewPasswordHasher(auth.HashAlgorithmArgon2id, testArgon2idParams, bcrypt.MinCost)
+	assert.Nil(t, err)
+	assert.IsType(t, &auth.Argon2idPasswordHasher{}, hasher)
+
+	hasher, err = auth.NewPasswordHasher(auth.HashAlgorithmBcrypt, testArgon2idParams, bcrypt.MinCost)
+	assert.Nil(t, err)
+	assert.IsType(t, &auth.BcryptPasswordHasher{}, hasher)
+
+	_, err = auth.NewPasswordHasher("md5", testArgon2idParams, bcrypt.MinCost)
+	assert.Equal(t, auth.ErrUnsupportedHashAlgorithm, err)
+}
+
+func TestArgon2idPasswordHasher(t *testing.T) {
+	hasher := auth.NewArgon2idPasswordHasher(testArgon2idParams)
+
+	hashedPassword, err := hasher.GenerateFromPassword("password123")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(hashedPassword, "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+	other, _ := hasher.GenerateFromPassword("password123")
+	assert.NotEqual(t, hashedPassword, other)
+
+	assert.Nil(t, hasher.CompareHashAndPassword(hashedPassword, "password123"))
+	assert.Equal(t, auth.ErrMismatchedHashAndPassword, hasher.CompareHashAndPassword(hashedPassword, "password124"))
+	assert.False(t, hasher.NeedsRehash(hashedPassword))
+
+	// Hashes of weaker parameters and bcrypt hashes still compare, but are upgraded
+	weakerHasher := auth.NewArgon2idPasswordHasher(auth.Argon2idParams{Memory: 512, Iterations: 1, Parallelism: 1})
+	weakerHash, _ := weakerHasher.GenerateFromPassword("password123")
+	assert.Nil(t, hasher.CompareHashAndPassword(weakerHash, "password123"))
+	assert.True(t, hasher.NeedsRehash(weakerHash))
+
+	bcryptHash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	assert.Nil(t, hasher.CompareHashAndPassword(string(bcryptHash), "password123"))
+	assert.NotNil(t, hasher.CompareHashAndPassword(string(bcryptHash), "password124"))
+	assert.True(t, hasher.NeedsRehash(string(bcryptHash)))
+}
+
+func TestArgon2idPasswordHasherInvalidHash(t *testing.T) {
+	hasher := auth.NewArgon2idPasswordHasher(testArgon2idParams)
+
+	for _, hashedPassword := range []string{
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA",
+		"$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=0$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=1$!!!$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA$",
+	} {
+		assert.Equal(t, auth.ErrInvalidHash, hasher.CompareHashAndPassword(hashedPassword, "password123"), hashedPassword)
+		assert.True(t, hasher.NeedsRehash(hashedPassword))
+	}
+}
+
+func TestBcryptPasswordHasher(t *testing.T) {
+	hasher := &auth.BcryptPasswordHasher{Cost: bcrypt.MinCost}
+
+	hashedPassword, err := hasher.GenerateFromPassword("password123")
+	assert.Nil(t, err)
+	assert.Nil(t, hasher.CompareHashAndPassword(hashedPassword, "password123"))
+	assert.NotNil(t, hasher.CompareHashAndPassword(hashedPassword, "password124"))
+	assert.False(t, hasher.NeedsRehash(hashedPassword))
+
+	assert.True(t, (&auth.BcryptPasswordHasher{}).NeedsRehash(hashedPassword))
+
+	argon2idHash, _ := auth.NewArgon2idPasswordHasher(testArgon2idParams).GenerateFromPassword("password123")
+	assert.Nil(t, hasher.CompareHashAndPassword(argon2idHash, "password123"))
+	assert.True(t, hasher.NeedsRehash(argon2idHash))
+}
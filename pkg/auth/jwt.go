@@ -0,0 +1,326 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	// SigningAlgorithmRS256 signs tokens with RSASSA-PKCS1-v1_5 using SHA-256
+	SigningAlgorithmRS256 = "RS256"
+	// SigningAlgorithmEdDSA signs tokens with Ed25519
+	SigningAlgorithmEdDSA = "EdDSA"
+
+	// rsaKeyBits is the size of a generated RSA signing key
+	rsaKeyBits = 2048
+	// keyIDBytes is the number of random bytes of a generated key ID
+	keyIDBytes = 12
+)
+
+var (
+	// ErrUnsupportedAlgorithm is returned for a signing algorithm other than RS256 and EdDSA
+	ErrUnsupportedAlgorithm = errors.New("auth: unsupported signing algorithm")
+	// ErrNoSigningKey is returned when signing before any key is loaded
+	ErrNoSigningKey = errors.New("auth: no signing key")
+	// ErrUnknownKeyID is returned for a token signed by a key which is not in the key set
+	ErrUnknownKeyID = errors.New("auth: unknown key id")
+	// ErrInvalidClaims is returned for a token with a wrong issuer or audience, or without expiry
+	ErrInvalidClaims = errors.New("auth: invalid claims")
+	// ErrInvalidJWK is returned for a JWK which is not an RSA, P-256 or Ed25519 public key
+	ErrInvalidJWK = errors.New("auth: invalid JWK")
+)
+
+// TokenSigner defines an interface for signing JWTs
+type TokenSigner interface {
+	Sign(claims jwt.MapClaims) (string, error)
+}
+
+// SigningKey is a private key signing JWTs, identified by the kid header of the tokens it signs
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	PrivateKey crypto.Signer
+	CreatedAt  time.Time
+}
+
+// GenerateSigningKey generates a new signing key with a random ID for the given algorithm
+func GenerateSigningKey(algorithm string) (*SigningKey, error) {
+	var privateKey crypto.Signer
+	var err error
+
+	switch algorithm {
+	case SigningAlgorithmRS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case SigningAlgorithmEdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, keyIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{
+		ID:         base64.RawURLEncoding.EncodeToString(b),
+		Algorithm:  algorithm,
+		PrivateKey: privateKey,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// MarshalPrivateKey encodes a private key as a PKCS #8 PEM block
+func MarshalPrivateKey(privateKey crypto.Signer) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// ParsePrivateKey decodes a PKCS #8 PEM block, the key has to match the given algorithm
+func ParsePrivateKey(algorithm, pemKey string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("auth: invalid PEM private key")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if algorithm == SigningAlgorithmRS256 {
+			return k, nil
+		}
+	case ed25519.PrivateKey:
+		if algorithm == SigningAlgorithmEdDSA {
+			return k, nil
+		}
+	}
+
+	return nil, fmt.Errorf("auth: private key does not match algorithm %s", algorithm)
+}
+
+// JWK is the public part of a signing key in the JSON Web Key format
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// PublicKey returns the RSA, P-256 or Ed25519 public key of the JWK
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch {
+	case k.KeyType == "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case k.KeyType == "EC" && k.Curve == "P-256":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		publicKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !publicKey.Curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, ErrInvalidJWK
+		}
+
+		return publicKey, nil
+	case k.KeyType == "OKP" && k.Curve == "Ed25519":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, ErrInvalidJWK
+		}
+
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, ErrInvalidJWK
+}
+
+// JWKS is the JSON Web Key Set published for the services verifying the tokens
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// KeySet signs JWTs with the current signing key and verifies them with any key of the set.
+// A new key is only used for signing once it is older than the activation delay,
+// so every verifier has picked it up before the first token signed by it is issued
+type KeySet struct {
+	issuer          string
+	audience        string
+	activationDelay time.Duration
+
+	mu   sync.RWMutex
+	keys []*SigningKey
+}
+
+// NewKeySet creates an empty key set issuing tokens for the given issuer and audience
+func NewKeySet(issuer, audience string, activationDelay time.Duration) *KeySet {
+	return &KeySet{
+		issuer:          issuer,
+		audience:        audience,
+		activationDelay: activationDelay,
+	}
+}
+
+// SetKeys replaces the keys of the set
+func (ks *KeySet) SetKeys(keys []*SigningKey) {
+	sorted := make([]*SigningKey, len(keys))
+	copy(sorted, keys)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	ks.keys = sorted
+}
+
+// Sign signs the claims with the current signing key, the issuer and audience claims are set by the key set
+func (ks *KeySet) Sign(claims jwt.MapClaims) (string, error) {
+	key := ks.signingKey(time.Now())
+	if key == nil {
+		return "", ErrNoSigningKey
+	}
+
+	claims["iss"] = ks.issuer
+	claims["aud"] = ks.audience
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.PrivateKey)
+}
+
+// Parse verifies the signature of the token with the key of its kid header, and validates the expiry, issuer and audience
+func (ks *KeySet) Parse(tokenString string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(tokenString, claims, ks.keyfunc); err != nil {
+		return nil, err
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) || !claims.VerifyIssuer(ks.issuer, true) || !claims.VerifyAudience(ks.audience, true) {
+		return nil, ErrInvalidClaims
+	}
+
+	return claims, nil
+}
+
+// JWKS returns the public keys of the set
+func (ks *KeySet) JWKS() *JWKS {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	jwks := &JWKS{Keys: make([]JWK, 0, len(ks.keys))}
+	for _, key := range ks.keys {
+		jwk := JWK{
+			KeyID:     key.ID,
+			Use:       "sig",
+			Algorithm: key.Algorithm,
+		}
+
+		switch publicKey := key.PrivateKey.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	return jwks
+}
+
+// signingKey returns the newest active key, or the oldest key when none of them is active yet
+func (ks *KeySet) signingKey(now time.Time) *SigningKey {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if len(ks.keys) == 0 {
+		return nil
+	}
+
+	for _, key := range ks.keys {
+		if !key.CreatedAt.After(now.Add(-ks.activationDelay)) {
+			return key
+		}
+	}
+
+	return ks.keys[len(ks.keys)-1]
+}
+
+// keyfunc picks the key of the kid header, rejecting a token whose algorithm is not the one of the key
+func (ks *KeySet) keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	for _, key := range ks.keys {
+		if key.ID != kid {
+			continue
+		}
+
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("auth: unexpected signing algorithm %s", token.Method.Alg())
+		}
+
+		return key.PrivateKey.Public(), nil
+	}
+
+	return nil, ErrUnknownKeyID
+}
@@ -0,0 +1,196 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/satriowisnugroho/book-store/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateSigningKey(t *testing.T) {
+	for _, algorithm := range []string{auth.SigningAlgorithmRS256, auth.SigningAlgorithmEdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			key, err := auth.GenerateSigningKey(algorithm)
+			assert.Nil(t, err)
+			assert.Equal(t, algorithm, key.Algorithm)
+			assert.Len(t, key.ID, 16)
+
+			pemKey, err := auth.MarshalPrivateKey(key.PrivateKey)
+			assert.Nil(t, err)
+
+			privateKey, err := auth.ParsePrivateKey(algorithm, pemKey)
+			assert.Nil(t, err)
+			assert.Equal(t, key.PrivateKey, privateKey)
+		})
+	}
+
+	_, err := auth.GenerateSigningKey("HS256")
+	assert.Equal(t, auth.ErrUnsupportedAlgorithm, err)
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key, _ := auth.GenerateSigningKey(auth.SigningAlgorithmEdDSA)
+	pemKey, _ := auth.MarshalPrivateKey(key.PrivateKey)
+
+	_, err := auth.ParsePrivateKey(auth.SigningAlgorithmRS256, pemKey)
+	assert.NotNil(t, err)
+
+	_, err = auth.ParsePrivateKey(auth.SigningAlgorithmEdDSA, "not a key")
+	assert.NotNil(t, err)
+}
+
+func TestKeySetSign(t *testing.T) {
+	ks := auth.NewKeySet("book-store", "book-store-api", time.Minute)
+
+	_, err := ks.Sign(jwt.MapClaims{})
+	assert.Equal(t, auth.ErrNoSigningKey, err)
+
+	oldKey, _ := auth.GenerateSigningKey(auth.SigningAlgorithmRS256)
+	oldKey.CreatedAt = time.Now().Add(-time.Hour)
+	newKey, _ := auth.GenerateSigningKey(auth.SigningAlgorithmEdDSA)
+	ks.SetKeys([]*auth.SigningKey{newKey, oldKey})
+
+	// The new key is not active yet
+	tokenString, err := ks.Sign(jwt.MapClaims{"exp": time.Now().Add(time.Minute).Unix()})
+	assert.Nil(t, err)
+	token, _, _ := new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
+	assert.Equal(t, oldKey.ID, token.Header["kid"])
+	assert.Equal(t, auth.SigningAlgorithmRS256, token.Method.Alg())
+
+	newKey.CreatedAt = time.Now().Add(-2 * time.Minute)
+	ks.SetKeys([]*auth.SigningKey{oldKey, newKey})
+
+	tokenString, err = ks.Sign(jwt.MapClaims{"exp": time.Now().Add(time.Minute).Unix()})
+	assert.Nil(t, err)
+	token, _, _ = new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
+	assert.Equal(t, newKey.ID, token.Header["kid"])
+	assert.Equal(t, auth.SigningAlgorithmEdDSA, token.Method.Alg())
+}
+
+func TestKeySetParse(t *testing.T) {
+	key, _ := auth.GenerateSigningKey(auth.SigningAlgorithmEdDSA)
+	ks := auth.NewKeySet("book-store", "book-store-api", time.Minute)
+	ks.SetKeys([]*auth.SigningKey{key})
+
+	otherKey, _ := auth.GenerateSigningKey(auth.SigningAlgorithmEdDSA)
+	otherKs := auth.NewKeySet("book-store", "book-store-api", time.Minute)
+	otherKs.SetKeys([]*auth.SigningKey{otherKey})
+
+	otherAudienceKs := auth.NewKeySet("book-store", "other-api", time.Minute)
+	otherAudienceKs.SetKeys([]*auth.SigningKey{key})
+
+	sign := func(ks *auth.KeySet, claims jwt.MapClaims) string {
+		tokenString, _ := ks.Sign(claims)
+		return tokenString
+	}
+
+	testcases := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{
+			name:    "malformed token",
+			token:   "foo",
+			wantErr: true,
+		},
+		{
+			name:    "unknown key",
+			token:   sign(otherKs, jwt.MapClaims{"exp": time.Now().Add(time.Minute).Unix()}),
+			wantErr: true,
+		},
+		{
+			name: "unexpected algorithm",
+			token: func() string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": time.Now().Add(time.Minute).Unix()})
+				token.Header["kid"] = key.ID
+				tokenString, _ := token.SignedString([]byte("secret"))
+				return tokenString
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "expired token",
+			token:   sign(ks, jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}),
+			wantErr: true,
+		},
+		{
+			name:    "token without expiry",
+			token:   sign(ks, jwt.MapClaims{}),
+			wantErr: true,
+		},
+		{
+			name:    "wrong audience",
+			token:   sign(otherAudienceKs, jwt.MapClaims{"exp": time.Now().Add(time.Minute).Unix()}),
+			wantErr: true,
+		},
+		{
+			name:    "valid token",
+			token:   sign(ks, jwt.MapClaims{"user_id": 123, "exp": time.Now().Add(time.Minute).Unix()}),
+			wantErr: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := ks.Parse(tc.token)
+			assert.Equal(t, tc.wantErr, err != nil)
+
+			if !tc.wantErr {
+				assert.Equal(t, float64(123), claims["user_id"])
+			}
+		})
+	}
+}
+
+func TestKeySetJWKS(t *testing.T) {
+	rsaKey, _ := auth.GenerateSigningKey(auth.SigningAlgorithmRS256)
+	edKey, _ := auth.GenerateSigningKey(auth.SigningAlgorithmEdDSA)
+	edKey.CreatedAt = rsaKey.CreatedAt.Add(time.Second)
+
+	ks := auth.NewKeySet("book-store", "book-store-api", time.Minute)
+	ks.SetKeys([]*auth.SigningKey{rsaKey, edKey})
+
+	jwks := ks.JWKS()
+	assert.Len(t, jwks.Keys, 2)
+
+	assert.Equal(t, edKey.ID, jwks.Keys[0].KeyID)
+	assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Curve)
+	assert.NotEmpty(t, jwks.Keys[0].X)
+
+	assert.Equal(t, rsaKey.ID, jwks.Keys[1].KeyID)
+	assert.Equal(t, "RSA", jwks.Keys[1].KeyType)
+	assert.Equal(t, "RS256", jwks.Keys[1].Algorithm)
+	assert.Equal(t, "AQAB", jwks.Keys[1].E)
+	assert.NotEmpty(t, jwks.Keys[1].N)
+}
+
+func TestJWKPublicKey(t *testing.T) {
+	rsaKey, _ := auth.GenerateSigningKey(auth.SigningAlgorithmRS256)
+	edKey, _ := auth.GenerateSigningKey(auth.SigningAlgorithmEdDSA)
+	edKey.CreatedAt = rsaKey.CreatedAt.Add(time.Second)
+
+	ks := auth.NewKeySet("book-store", "book-store-api", time.Minute)
+	ks.SetKeys([]*auth.SigningKey{rsaKey, edKey})
+	jwks := ks.JWKS()
+
+	publicKey, err := jwks.Keys[0].PublicKey()
+	assert.NoError(t, err)
+	assert.Equal(t, edKey.PrivateKey.Public(), publicKey)
+
+	publicKey, err = jwks.Keys[1].PublicKey()
+	assert.NoError(t, err)
+	assert.Equal(t, rsaKey.PrivateKey.Public(), publicKey)
+
+	_, err = auth.JWK{KeyType: "oct"}.PublicKey()
+	assert.ErrorIs(t, err, auth.ErrInvalidJWK)
+
+	_, err = auth.JWK{KeyType: "OKP", Curve: "Ed25519", X: "AQAB"}.PublicKey()
+	assert.ErrorIs(t, err, auth.ErrInvalidJWK)
+
+	_, err = auth.JWK{KeyType: "EC", Curve: "P-256", X: "AQAB", Y: "AQAB"}.PublicKey()
+	assert.ErrorIs(t, err, auth.ErrInvalidJWK)
+}
@@ -0,0 +1,23 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateToken(t *testing.T) {
+	token, err := auth.GenerateToken()
+	assert.Nil(t, err)
+	assert.Len(t, token, 43)
+
+	other, err := auth.GenerateToken()
+	assert.Nil(t, err)
+	assert.NotEqual(t, token, other)
+}
+
+func TestHashToken(t *testing.T) {
+	assert.Equal(t, "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", auth.HashToken("foo"))
+	assert.NotEqual(t, auth.HashToken("foo"), auth.HashToken("bar"))
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// totpSecretBytes is the number of random bytes of a TOTP secret, the size of an HMAC-SHA1 key
+	totpSecretBytes = 20
+	// totpDigits is the number of digits of a TOTP code
+	totpDigits = 6
+	// totpPeriod is how long a TOTP code is valid
+	totpPeriod = 30 * time.Second
+	// totpSkew is the number of periods before and after the current one whose codes are accepted, for clock drift
+	totpSkew = 1
+
+	// recoveryCodeBytes is the number of random bytes of a recovery code
+	recoveryCodeBytes = 10
+)
+
+// totpEncoding encodes the TOTP secrets and the recovery codes, authenticator apps expect base32 without padding
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a random base32 encoded TOTP secret
+func GenerateTOTPSecret() (string, error) {
+	b := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// TOTPURI returns the otpauth URI of a TOTP secret, which authenticator apps read from a QR code
+func TOTPURI(issuer, accountName, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + accountName,
+		RawQuery: query.Encode(),
+	}
+
+	return u.String()
+}
+
+// GenerateTOTPCode returns the RFC 6238 code of the base32 encoded secret at the given time
+func GenerateTOTPCode(secret string, now time.Time) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	return totpCode(key, now.Unix()/int64(totpPeriod.Seconds())), nil
+}
+
+// ValidateTOTPCode reports whether the code is the RFC 6238 code of the secret at the given time, give or take a period.
+// It returns the time step of the matching code, so the caller can refuse a code used before
+func ValidateTOTPCode(secret, code string, now time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	currentStep := now.Unix() / int64(totpPeriod.Seconds())
+	for step := currentStep - totpSkew; step <= currentStep+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// totpCode returns the HOTP code of RFC 4226 for the time step
+func totpCode(key []byte, step int64) string {
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, value%modulo)
+}
+
+// GenerateRecoveryCode returns a random recovery code such as abcd-efgh-ijkl-mnop
+func GenerateRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(totpEncoding.EncodeToString(b))
+	groups := make([]string, 0, len(code)/4)
+	for i := 0; i < len(code); i += 4 {
+		groups = append(groups, code[i:i+4])
+	}
+
+	return strings.Join(groups, "-"), nil
+}
+
+// NormalizeRecoveryCode removes the dashes and spaces of a recovery code typed by the user and lowers its case,
+// the recovery codes are hashed in this form
+func NormalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+}
@@ -0,0 +1,119 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/satriowisnugroho/book-store/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+// rfc6238Secret is the base32 encoding of the SHA1 secret of the RFC 6238 test vectors, 12345678901234567890
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := auth.GenerateTOTPSecret()
+	assert.Nil(t, err)
+	assert.Len(t, secret, 32)
+
+	other, err := auth.GenerateTOTPSecret()
+	assert.Nil(t, err)
+	assert.NotEqual(t, secret, other)
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := auth.TOTPURI("Book Store", "foo@bar.com", rfc6238Secret)
+	assert.Equal(t, "otpauth://totp/Book%20Store:foo@bar.com?algorithm=SHA1&digits=6&issuer=Book+Store&period=30&secret="+rfc6238Secret, uri)
+}
+
+func TestGenerateTOTPCode(t *testing.T) {
+	code, err := auth.GenerateTOTPCode(rfc6238Secret, time.Unix(1234567890, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, "005924", code)
+
+	_, err = auth.GenerateTOTPCode("not base32!", time.Now())
+	assert.NotNil(t, err)
+}
+
+func TestValidateTOTPCode(t *testing.T) {
+	testcases := []struct {
+		name     string
+		secret   string
+		code     string
+		now      time.Time
+		wantStep int64
+		wantOK   bool
+	}{
+		{
+			name:     "RFC 6238 test vector",
+			secret:   rfc6238Secret,
+			code:     "287082",
+			now:      time.Unix(59, 0),
+			wantStep: 1,
+			wantOK:   true,
+		},
+		{
+			name:     "RFC 6238 test vector with a lower case secret",
+			secret:   strings.ToLower(rfc6238Secret),
+			code:     "081804",
+			now:      time.Unix(1111111109, 0),
+			wantStep: 37037036,
+			wantOK:   true,
+		},
+		{
+			name:     "previous period",
+			secret:   rfc6238Secret,
+			code:     "287082",
+			now:      time.Unix(89, 0),
+			wantStep: 1,
+			wantOK:   true,
+		},
+		{
+			name:   "two periods ago",
+			secret: rfc6238Secret,
+			code:   "287082",
+			now:    time.Unix(119, 0),
+		},
+		{
+			name:   "wrong code",
+			secret: rfc6238Secret,
+			code:   "123456",
+			now:    time.Unix(59, 0),
+		},
+		{
+			name:   "wrong length",
+			secret: rfc6238Secret,
+			code:   "94287082",
+			now:    time.Unix(59, 0),
+		},
+		{
+			name:   "invalid secret",
+			secret: "not base32!",
+			code:   "287082",
+			now:    time.Unix(59, 0),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			step, ok := auth.ValidateTOTPCode(tc.secret, tc.code, tc.now)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.wantStep, step)
+		})
+	}
+}
+
+func TestGenerateRecoveryCode(t *testing.T) {
+	code, err := auth.GenerateRecoveryCode()
+	assert.Nil(t, err)
+	assert.Regexp(t, "^[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}$", code)
+
+	other, err := auth.GenerateRecoveryCode()
+	assert.Nil(t, err)
+	assert.NotEqual(t, code, other)
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	assert.Equal(t, "abcdefghijklmnop", auth.NormalizeRecoveryCode(" ABCD-efgh ijkl-mnop"))
+}
@@ -0,0 +1,40 @@
+// Package blobstore implements storage of binary objects addressed by slash separated keys.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when no blob is stored under the key
+	ErrNotFound = errors.New("blobstore: blob not found")
+	// ErrInvalidKey is returned when the key is empty, absolute or escapes the store
+	ErrInvalidKey = errors.New("blobstore: invalid key")
+)
+
+// BlobStore defines an interface for storing blobs, keys are slash separated paths such as "covers/1/small.jpg"
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	DeletePrefix(ctx context.Context, prefix string) error
+	URL(key string) string
+}
+
+// ValidateKey checks that the key is a clean relative path
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key {
+		return ErrInvalidKey
+	}
+
+	for _, part := range strings.Split(key, "/") {
+		if part == ".." || part == "." {
+			return ErrInvalidKey
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package blobstore_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/pkg/blobstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateKey(t *testing.T) {
+	assert.Nil(t, blobstore.ValidateKey("covers/1/small.jpg"))
+	assert.Equal(t, blobstore.ErrInvalidKey, blobstore.ValidateKey(""))
+	assert.Equal(t, blobstore.ErrInvalidKey, blobstore.ValidateKey("/etc/passwd"))
+	assert.Equal(t, blobstore.ErrInvalidKey, blobstore.ValidateKey("covers/../../etc/passwd"))
+	assert.Equal(t, blobstore.ErrInvalidKey, blobstore.ValidateKey("covers//small.jpg"))
+	assert.Equal(t, blobstore.ErrInvalidKey, blobstore.ValidateKey("..\\secret"))
+}
+
+func TestLocalBlobStore(t *testing.T) {
+	ctx := context.Background()
+	store := blobstore.NewLocalBlobStore(t.TempDir(), "http://localhost:9999/blobs/")
+
+	assert.Equal(t, "http://localhost:9999/blobs/covers/1/small.jpg", store.URL("covers/1/small.jpg"))
+
+	_, err := store.Get(ctx, "covers/1/small.jpg")
+	assert.Equal(t, blobstore.ErrNotFound, err)
+
+	assert.Nil(t, store.Put(ctx, "covers/1/small.jpg", strings.NewReader("image")))
+	assert.Equal(t, blobstore.ErrInvalidKey, store.Put(ctx, "../small.jpg", strings.NewReader("image")))
+
+	rc, err := store.Get(ctx, "covers/1/small.jpg")
+	assert.Nil(t, err)
+	content, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, "image", string(content))
+
+	assert.Nil(t, store.DeletePrefix(ctx, "covers/1/"))
+	_, err = store.Get(ctx, "covers/1/small.jpg")
+	assert.Equal(t, blobstore.ErrNotFound, err)
+}
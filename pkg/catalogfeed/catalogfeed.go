@@ -0,0 +1,78 @@
+// Package catalogfeed implements streaming readers for catalog feeds in CSV and ONIX 3.0 format.
+package catalogfeed
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"strings"
+)
+
+const (
+	// FormatCSV is the CSV feed format with a header row containing isbn, title, price and optionally category
+	FormatCSV = "csv"
+	// FormatONIX is the ONIX 3.0 XML feed format using reference tags
+	FormatONIX = "onix"
+)
+
+var (
+	// ErrUnsupportedFormat is returned when the feed format is not supported
+	ErrUnsupportedFormat = errors.New("catalogfeed: unsupported format")
+	// ErrMissingColumn is returned when the CSV header lacks a required column
+	ErrMissingColumn = errors.New("catalogfeed: missing required column")
+	// ErrInvalidPrice is returned when the price is not a whole non-negative amount
+	ErrInvalidPrice = errors.New("catalogfeed: invalid price")
+)
+
+// Record holds a single book read from a feed
+type Record struct {
+	// Row is the line number for CSV feeds and the product position for ONIX feeds
+	Row   int
+	Isbn  string
+	Title string
+	Price string
+	// Category is empty when the feed does not provide one
+	Category string
+	// Err is set when the row could not be parsed, the other fields may be empty
+	Err error
+}
+
+// Reader reads records one by one and returns io.EOF when the feed is exhausted
+type Reader interface {
+	Next() (*Record, error)
+}
+
+// NewReader returns the reader for the given format
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return NewCSVReader(r)
+	case FormatONIX:
+		return NewONIXReader(r), nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// FormatFromFilename guesses the feed format from the file extension
+func FormatFromFilename(filename string) string {
+	filename = strings.ToLower(filename)
+	switch {
+	case strings.HasSuffix(filename, ".csv"):
+		return FormatCSV
+	case strings.HasSuffix(filename, ".xml"), strings.HasSuffix(filename, ".onix"):
+		return FormatONIX
+	default:
+		return ""
+	}
+}
+
+// ParsePrice parses a price such as "25000" or "25000.00" into a whole amount
+func ParsePrice(s string) (int, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || r.Sign() < 0 || !r.IsInt() || !r.Num().IsInt64() {
+		return 0, ErrInvalidPrice
+	}
+
+	return int(r.Num().Int64()), nil
+}
@@ -0,0 +1,119 @@
+package catalogfeed_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/pkg/catalogfeed"
+	"github.com/stretchr/testify/assert"
+)
+
+func readAll(t *testing.T, r catalogfeed.Reader) []*catalogfeed.Record {
+	records := []*catalogfeed.Record{}
+	for {
+		record, err := r.Next()
+		if err == io.EOF {
+			return records
+		}
+		assert.Nil(t, err)
+		records = append(records, record)
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	_, err := catalogfeed.NewReader("pdf", strings.NewReader(""))
+	assert.Equal(t, catalogfeed.ErrUnsupportedFormat, err)
+
+	_, err = catalogfeed.NewReader("CSV", strings.NewReader("title,price\n"))
+	assert.ErrorIs(t, err, catalogfeed.ErrMissingColumn)
+
+	_, err = catalogfeed.NewReader("onix", strings.NewReader(""))
+	assert.Nil(t, err)
+}
+
+func TestCSVReader(t *testing.T) {
+	feed := "Title,ISBN,Price,Category\n" +
+		"Harry Potter,978-0-545-01022-1,25000,Fantasy\n" +
+		"\"Narnia, The Lion\",0-545-01022-5, 20000\n" +
+		"Broken \"quote,123,1\n" +
+		"Short row\n"
+
+	r, err := catalogfeed.NewCSVReader(strings.NewReader(feed))
+	assert.Nil(t, err)
+
+	records := readAll(t, r)
+	assert.Len(t, records, 4)
+	assert.Equal(t, &catalogfeed.Record{Row: 2, Isbn: "978-0-545-01022-1", Title: "Harry Potter", Price: "25000", Category: "Fantasy"}, records[0])
+	assert.Equal(t, &catalogfeed.Record{Row: 3, Isbn: "0-545-01022-5", Title: "Narnia, The Lion", Price: "20000"}, records[1])
+	assert.Equal(t, 4, records[2].Row)
+	assert.NotNil(t, records[2].Err)
+	assert.Equal(t, &catalogfeed.Record{Row: 5, Title: "Short row"}, records[3])
+}
+
+func TestONIXReader(t *testing.T) {
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<ONIXMessage release="3.0" xmlns="http://ns.editeur.org/onix/3.0/reference">
+  <Header><Sender><SenderName>Publisher</SenderName></Sender></Header>
+  <Product>
+    <RecordReference>pub-1</RecordReference>
+    <ProductIdentifier><ProductIDType>02</ProductIDType><IDValue>0545010225</IDValue></ProductIdentifier>
+    <ProductIdentifier><ProductIDType>15</ProductIDType><IDValue>9780545010221</IDValue></ProductIdentifier>
+    <DescriptiveDetail>
+      <TitleDetail>
+        <TitleType>01</TitleType>
+        <TitleElement><TitleElementLevel>01</TitleElementLevel><TitleText>Harry Potter</TitleText></TitleElement>
+      </TitleDetail>
+      <Subject><SubjectSchemeIdentifier>10</SubjectSchemeIdentifier><SubjectCode>JUV037000</SubjectCode><SubjectHeadingText>Juvenile Fiction</SubjectHeadingText></Subject>
+      <Subject><MainSubject/><SubjectSchemeIdentifier>10</SubjectSchemeIdentifier><SubjectHeadingText>Fantasy</SubjectHeadingText></Subject>
+    </DescriptiveDetail>
+    <ProductSupply><SupplyDetail>
+      <Price><PriceType>02</PriceType><PriceAmount>25000.00</PriceAmount><CurrencyCode>IDR</CurrencyCode></Price>
+    </SupplyDetail></ProductSupply>
+  </Product>
+  <Product>
+    <ProductIdentifier><ProductIDType>02</ProductIDType><IDValue>0545010225</IDValue></ProductIdentifier>
+    <DescriptiveDetail>
+      <TitleDetail>
+        <TitleType>01</TitleType>
+        <TitleElement><TitleElementLevel>01</TitleElementLevel><TitlePrefix>The</TitlePrefix><TitleWithoutPrefix>Godfather</TitleWithoutPrefix></TitleElement>
+      </TitleDetail>
+    </DescriptiveDetail>
+  </Product>
+</ONIXMessage>`
+
+	records := readAll(t, catalogfeed.NewONIXReader(strings.NewReader(feed)))
+	assert.Len(t, records, 2)
+	assert.Equal(t, &catalogfeed.Record{Row: 1, Isbn: "9780545010221", Title: "Harry Potter", Price: "25000.00", Category: "Fantasy"}, records[0])
+	assert.Equal(t, &catalogfeed.Record{Row: 2, Isbn: "0545010225", Title: "The Godfather"}, records[1])
+
+	_, err := catalogfeed.NewONIXReader(strings.NewReader("<ONIXMessage><Product>")).Next()
+	assert.NotNil(t, err)
+}
+
+func TestFormatFromFilename(t *testing.T) {
+	assert.Equal(t, catalogfeed.FormatCSV, catalogfeed.FormatFromFilename("books.CSV"))
+	assert.Equal(t, catalogfeed.FormatONIX, catalogfeed.FormatFromFilename("feed.xml"))
+	assert.Equal(t, "", catalogfeed.FormatFromFilename("feed.pdf"))
+}
+
+func TestParsePrice(t *testing.T) {
+	testcases := []struct {
+		input    string
+		expected int
+		wantErr  bool
+	}{
+		{input: "25000", expected: 25000},
+		{input: " 25000.00 ", expected: 25000},
+		{input: "250.50", wantErr: true},
+		{input: "-1", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "abc", wantErr: true},
+	}
+
+	for _, tc := range testcases {
+		res, err := catalogfeed.ParsePrice(tc.input)
+		assert.Equal(t, tc.wantErr, err != nil, tc.input)
+		assert.Equal(t, tc.expected, res, tc.input)
+	}
+}
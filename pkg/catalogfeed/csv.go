@@ -0,0 +1,73 @@
+package catalogfeed
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CSVReader reads records from a CSV feed
+type CSVReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+// NewCSVReader reads the header row and returns a reader for the remaining rows
+func NewCSVReader(r io.Reader) (*CSVReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	for _, name := range []string{"isbn", "title", "price"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingColumn, name)
+		}
+	}
+
+	return &CSVReader{reader: reader, columns: columns}, nil
+}
+
+// Next returns the next record of the feed
+func (r *CSVReader) Next() (*Record, error) {
+	row, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return &Record{Row: parseErr.StartLine, Err: parseErr.Err}, nil
+		}
+
+		return nil, err
+	}
+
+	line, _ := r.reader.FieldPos(0)
+
+	return &Record{
+		Row:      line,
+		Isbn:     r.field(row, "isbn"),
+		Title:    r.field(row, "title"),
+		Price:    r.field(row, "price"),
+		Category: r.field(row, "category"),
+	}, nil
+}
+
+func (r *CSVReader) field(row []string, name string) string {
+	i, ok := r.columns[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+
+	return strings.TrimSpace(row[i])
+}
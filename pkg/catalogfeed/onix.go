@@ -0,0 +1,152 @@
+package catalogfeed
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+const (
+	onixProductIDTypeISBN10      = "02"
+	onixProductIDTypeISBN13      = "15"
+	onixTitleTypeDistinctive     = "01"
+	onixTitleElementLevelProduct = "01"
+	onixPriceTypeRRPExcludingTax = "01"
+	onixPriceTypeRRPIncludingTax = "02"
+)
+
+// onixProduct maps the subset of an ONIX 3.0 <Product> composite used by the catalog
+type onixProduct struct {
+	ProductIdentifiers []struct {
+		ProductIDType string `xml:"ProductIDType"`
+		IDValue       string `xml:"IDValue"`
+	} `xml:"ProductIdentifier"`
+	TitleDetails []struct {
+		TitleType     string `xml:"TitleType"`
+		TitleElements []struct {
+			TitleElementLevel  string `xml:"TitleElementLevel"`
+			TitleText          string `xml:"TitleText"`
+			TitlePrefix        string `xml:"TitlePrefix"`
+			TitleWithoutPrefix string `xml:"TitleWithoutPrefix"`
+		} `xml:"TitleElement"`
+	} `xml:"DescriptiveDetail>TitleDetail"`
+	Subjects []struct {
+		MainSubject        *struct{} `xml:"MainSubject"`
+		SubjectHeadingText string    `xml:"SubjectHeadingText"`
+	} `xml:"DescriptiveDetail>Subject"`
+	Prices []struct {
+		PriceType   string `xml:"PriceType"`
+		PriceAmount string `xml:"PriceAmount"`
+	} `xml:"ProductSupply>SupplyDetail>Price"`
+}
+
+// ONIXReader reads records from an ONIX 3.0 feed one <Product> at a time
+type ONIXReader struct {
+	decoder  *xml.Decoder
+	position int
+}
+
+// NewONIXReader returns a reader for an ONIX 3.0 feed using reference tags
+func NewONIXReader(r io.Reader) *ONIXReader {
+	return &ONIXReader{decoder: xml.NewDecoder(r)}
+}
+
+// Next returns the next record of the feed
+func (r *ONIXReader) Next() (*Record, error) {
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "Product" {
+			continue
+		}
+
+		r.position++
+
+		var product onixProduct
+		if err := r.decoder.DecodeElement(&product, &start); err != nil {
+			return nil, err
+		}
+
+		return &Record{
+			Row:      r.position,
+			Isbn:     product.isbn(),
+			Title:    product.title(),
+			Price:    product.price(),
+			Category: product.category(),
+		}, nil
+	}
+}
+
+// isbn prefers the ISBN-13 identifier and falls back to the ISBN-10 one
+func (p *onixProduct) isbn() string {
+	isbn10 := ""
+	for _, identifier := range p.ProductIdentifiers {
+		switch identifier.ProductIDType {
+		case onixProductIDTypeISBN13:
+			return strings.TrimSpace(identifier.IDValue)
+		case onixProductIDTypeISBN10:
+			isbn10 = strings.TrimSpace(identifier.IDValue)
+		}
+	}
+
+	return isbn10
+}
+
+// title returns the product level distinctive title
+func (p *onixProduct) title() string {
+	for _, detail := range p.TitleDetails {
+		if detail.TitleType != onixTitleTypeDistinctive {
+			continue
+		}
+
+		for _, element := range detail.TitleElements {
+			if element.TitleElementLevel != onixTitleElementLevelProduct {
+				continue
+			}
+
+			if element.TitleText != "" {
+				return strings.TrimSpace(element.TitleText)
+			}
+
+			return strings.TrimSpace(strings.TrimSpace(element.TitlePrefix) + " " + strings.TrimSpace(element.TitleWithoutPrefix))
+		}
+	}
+
+	return ""
+}
+
+// price returns the first recommended retail price of the product
+func (p *onixProduct) price() string {
+	for _, price := range p.Prices {
+		if price.PriceType == onixPriceTypeRRPExcludingTax || price.PriceType == onixPriceTypeRRPIncludingTax {
+			return strings.TrimSpace(price.PriceAmount)
+		}
+	}
+
+	return ""
+}
+
+// category returns the heading of the main subject, or of the first subject with a heading when none is marked as main
+func (p *onixProduct) category() string {
+	category := ""
+	for _, subject := range p.Subjects {
+		heading := strings.TrimSpace(subject.SubjectHeadingText)
+		if heading == "" {
+			continue
+		}
+
+		if subject.MainSubject != nil {
+			return heading
+		}
+
+		if category == "" {
+			category = heading
+		}
+	}
+
+	return category
+}
@@ -0,0 +1,62 @@
+// Package contentfilter checks user submitted text for content that should be held for moderation.
+package contentfilter
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// Result holds the outcome of a content check
+type Result struct {
+	Flagged bool
+	Reasons []string
+}
+
+// Filter defines an interface for content filters, a filter backed by a remote service may return an error
+type Filter interface {
+	Check(ctx context.Context, text string) (*Result, error)
+}
+
+// BannedWordsFilter flags text containing any of its banned words or phrases.
+// Matching ignores case and punctuation and only matches whole words, so "class" does not match "ass"
+type BannedWordsFilter struct {
+	phrases []string
+}
+
+var _ Filter = (*BannedWordsFilter)(nil)
+
+// NewBannedWordsFilter returns a filter for the given words, a word may be a phrase of several words
+func NewBannedWordsFilter(words []string) *BannedWordsFilter {
+	f := &BannedWordsFilter{}
+	for _, word := range words {
+		if phrase := normalize(word); phrase != "" {
+			f.phrases = append(f.phrases, phrase)
+		}
+	}
+
+	return f
+}
+
+// Check flags the text when it contains a banned word, the matched words are the reasons
+func (f *BannedWordsFilter) Check(ctx context.Context, text string) (*Result, error) {
+	result := &Result{}
+
+	// Pad with spaces so a phrase only matches on word boundaries
+	normalized := " " + normalize(text) + " "
+	for _, phrase := range f.phrases {
+		if strings.Contains(normalized, " "+phrase+" ") {
+			result.Flagged = true
+			result.Reasons = append(result.Reasons, phrase)
+		}
+	}
+
+	return result, nil
+}
+
+// normalize lower cases the text and replaces every run of non alphanumeric characters with a single space
+func normalize(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ")
+}
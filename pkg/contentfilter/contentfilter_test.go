@@ -0,0 +1,48 @@
+package contentfilter_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/pkg/contentfilter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBannedWordsFilter(t *testing.T) {
+	f := contentfilter.NewBannedWordsFilter([]string{"Casino", "free  money", " "})
+
+	testcases := []struct {
+		name     string
+		text     string
+		expected *contentfilter.Result
+	}{
+		{
+			name:     "clean text",
+			text:     "A magical story, loved every chapter!",
+			expected: &contentfilter.Result{},
+		},
+		{
+			name:     "banned word inside another word",
+			text:     "Casinos and freemoney are not matched",
+			expected: &contentfilter.Result{},
+		},
+		{
+			name:     "banned word ignoring case and punctuation",
+			text:     "Visit my CASINO!!!",
+			expected: &contentfilter.Result{Flagged: true, Reasons: []string{"casino"}},
+		},
+		{
+			name:     "banned phrase across punctuation",
+			text:     "Get free... money now, casino",
+			expected: &contentfilter.Result{Flagged: true, Reasons: []string{"casino", "free money"}},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := f.Check(context.Background(), tc.text)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
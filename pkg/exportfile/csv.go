@@ -0,0 +1,39 @@
+package exportfile
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// CSVWriter writes rows as comma separated values
+type CSVWriter struct {
+	writer *csv.Writer
+	record []string
+}
+
+// NewCSVWriter returns a CSV writer
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{writer: csv.NewWriter(w)}
+}
+
+// WriteHeader writes the header row
+func (w *CSVWriter) WriteHeader(columns []string) error {
+	return w.writer.Write(columns)
+}
+
+// WriteRow writes a row
+func (w *CSVWriter) WriteRow(values []interface{}) error {
+	w.record = w.record[:0]
+	for _, value := range values {
+		w.record = append(w.record, formatValue(value))
+	}
+
+	return w.writer.Write(w.record)
+}
+
+// Close flushes the buffered rows
+func (w *CSVWriter) Close() error {
+	w.writer.Flush()
+
+	return w.writer.Error()
+}
@@ -0,0 +1,96 @@
+// Package exportfile implements streaming tabular writers for CSV, JSON Lines and XLSX files.
+package exportfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// FormatCSV is the comma separated values format
+	FormatCSV = "csv"
+	// FormatNDJSON is the newline delimited JSON (JSON Lines) format
+	FormatNDJSON = "ndjson"
+	// FormatXLSX is the Office Open XML spreadsheet format
+	FormatXLSX = "xlsx"
+)
+
+// ErrUnsupportedFormat is returned when the export format is not supported
+var ErrUnsupportedFormat = errors.New("exportfile: unsupported format")
+
+// Writer writes a header followed by rows, rows are written to the underlying writer as they come.
+// Close must be called to flush buffered data and finish the file, it does not close the underlying writer
+type Writer interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// NewWriter returns the writer for the given format
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatNDJSON:
+		return NewNDJSONWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w), nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// IsSupported reports whether the format can be written
+func IsSupported(format string) bool {
+	switch strings.ToLower(format) {
+	case FormatCSV, FormatNDJSON, FormatXLSX:
+		return true
+	default:
+		return false
+	}
+}
+
+// ContentType returns the MIME type of the format
+func ContentType(format string) string {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Filename returns the file name for the given base name and format
+func Filename(name, format string) string {
+	return fmt.Sprintf("%s.%s", name, strings.ToLower(format))
+}
+
+// formatValue converts a cell value into its textual representation
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,85 @@
+package exportfile_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/satriowisnugroho/book-store/pkg/exportfile"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testColumns = []string{"id", "title", "created_at"}
+	testRows    = [][]interface{}{
+		{1, "Harry Potter", time.Date(2024, 6, 7, 22, 43, 27, 0, time.UTC)},
+		{2, "Tom & \"Jerry\"", time.Date(2024, 6, 8, 0, 0, 0, 0, time.UTC)},
+	}
+)
+
+func write(t *testing.T, format string) []byte {
+	buf := &bytes.Buffer{}
+	w, err := exportfile.NewWriter(format, buf)
+	assert.Nil(t, err)
+
+	assert.Nil(t, w.WriteHeader(testColumns))
+	for _, row := range testRows {
+		assert.Nil(t, w.WriteRow(row))
+	}
+	assert.Nil(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func TestNewWriter(t *testing.T) {
+	_, err := exportfile.NewWriter("pdf", &bytes.Buffer{})
+	assert.Equal(t, exportfile.ErrUnsupportedFormat, err)
+	assert.False(t, exportfile.IsSupported("pdf"))
+	assert.True(t, exportfile.IsSupported("XLSX"))
+}
+
+func TestCSVWriter(t *testing.T) {
+	expected := "id,title,created_at\n" +
+		"1,Harry Potter,2024-06-07T22:43:27Z\n" +
+		"2,\"Tom & \"\"Jerry\"\"\",2024-06-08T00:00:00Z\n"
+
+	assert.Equal(t, expected, string(write(t, exportfile.FormatCSV)))
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	expected := `{"id":1,"title":"Harry Potter","created_at":"2024-06-07T22:43:27Z"}` + "\n" +
+		`{"id":2,"title":"Tom & \"Jerry\"","created_at":"2024-06-08T00:00:00Z"}` + "\n"
+
+	assert.Equal(t, expected, string(write(t, exportfile.FormatNDJSON)))
+}
+
+func TestXLSXWriter(t *testing.T) {
+	content := write(t, exportfile.FormatXLSX)
+
+	r, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	assert.Nil(t, err)
+
+	names := []string{}
+	var sheet []byte
+	for _, f := range r.File {
+		names = append(names, f.Name)
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			rc, _ := f.Open()
+			sheet, _ = io.ReadAll(rc)
+			rc.Close()
+		}
+	}
+
+	assert.Equal(t, []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"}, names)
+	assert.Contains(t, string(sheet), `<row r="1"><c r="A1" t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`)
+	assert.Contains(t, string(sheet), `<row r="3"><c r="A3"><v>2</v></c><c r="B3" t="inlineStr"><is><t xml:space="preserve">Tom &amp; &#34;Jerry&#34;</t></is></c>`)
+	assert.Contains(t, string(sheet), `</sheetData></worksheet>`)
+}
+
+func TestContentType(t *testing.T) {
+	assert.Equal(t, "text/csv; charset=utf-8", exportfile.ContentType("csv"))
+	assert.Equal(t, "application/octet-stream", exportfile.ContentType("pdf"))
+	assert.Equal(t, "books.xlsx", exportfile.Filename("books", "XLSX"))
+}
@@ -0,0 +1,85 @@
+package exportfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// NDJSONWriter writes every row as a JSON object keyed by the header columns, one object per line
+type NDJSONWriter struct {
+	writer  *bufio.Writer
+	buf     *bytes.Buffer
+	encoder *json.Encoder
+	columns []string
+}
+
+// NewNDJSONWriter returns a JSON Lines writer
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+
+	return &NDJSONWriter{writer: bufio.NewWriter(w), buf: buf, encoder: encoder}
+}
+
+// WriteHeader stores the columns used as object keys, nothing is written
+func (w *NDJSONWriter) WriteHeader(columns []string) error {
+	w.columns = columns
+
+	return nil
+}
+
+// WriteRow writes a row as a single line JSON object
+func (w *NDJSONWriter) WriteRow(values []interface{}) error {
+	// Write the keys in header order, a map would sort them
+	if err := w.writer.WriteByte('{'); err != nil {
+		return err
+	}
+
+	for i, column := range w.columns {
+		if i > 0 {
+			w.writer.WriteByte(',')
+		}
+
+		var value interface{}
+		if i < len(values) {
+			value = values[i]
+		}
+
+		if t, ok := value.(time.Time); ok {
+			value = formatValue(t)
+		}
+
+		if err := w.writeValue(column); err != nil {
+			return err
+		}
+		w.writer.WriteByte(':')
+		if err := w.writeValue(value); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.writer.WriteString("}\n")
+
+	return err
+}
+
+// writeValue writes a JSON value without the trailing newline added by the encoder
+func (w *NDJSONWriter) writeValue(value interface{}) error {
+	w.buf.Reset()
+	if err := w.encoder.Encode(value); err != nil {
+		return err
+	}
+
+	_, err := w.writer.Write(bytes.TrimSuffix(w.buf.Bytes(), []byte("\n")))
+
+	return err
+}
+
+// Close flushes the buffered rows
+func (w *NDJSONWriter) Close() error {
+	return w.writer.Flush()
+}
@@ -0,0 +1,151 @@
+package exportfile
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// XLSXWriter writes rows into the single sheet of a workbook, the sheet is the last zip entry
+// so rows are compressed and written as they come instead of being kept in memory
+type XLSXWriter struct {
+	zip    *zip.Writer
+	sheet  *bufio.Writer
+	rowNum int
+	err    error
+}
+
+// NewXLSXWriter returns an XLSX writer
+func NewXLSXWriter(w io.Writer) *XLSXWriter {
+	return &XLSXWriter{zip: zip.NewWriter(w)}
+}
+
+// WriteHeader writes the workbook parts followed by the header row
+func (w *XLSXWriter) WriteHeader(columns []string) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		values[i] = column
+	}
+
+	return w.writeRow(values)
+}
+
+// WriteRow writes a row, numbers are written as numeric cells and everything else as inline strings
+func (w *XLSXWriter) WriteRow(values []interface{}) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	return w.writeRow(values)
+}
+
+// Close finishes the sheet and writes the zip central directory
+func (w *XLSXWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	if _, err := w.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+
+	return w.zip.Close()
+}
+
+// start writes the static workbook parts and opens the sheet entry once
+func (w *XLSXWriter) start() error {
+	if w.sheet != nil || w.err != nil {
+		return w.err
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+
+	for _, part := range parts {
+		f, err := w.zip.Create(part.name)
+		if err != nil {
+			w.err = err
+			return err
+		}
+
+		if _, err := io.WriteString(f, part.content); err != nil {
+			w.err = err
+			return err
+		}
+	}
+
+	f, err := w.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		w.err = err
+		return err
+	}
+
+	w.sheet = bufio.NewWriter(f)
+	_, w.err = w.sheet.WriteString(xlsxSheetStart)
+
+	return w.err
+}
+
+func (w *XLSXWriter) writeRow(values []interface{}) error {
+	w.rowNum++
+	row := strconv.Itoa(w.rowNum)
+
+	w.sheet.WriteString(`<row r="` + row + `">`)
+	for i, value := range values {
+		ref := columnName(i) + row
+
+		switch v := value.(type) {
+		case int, int64, float64:
+			w.sheet.WriteString(`<c r="` + ref + `"><v>` + formatValue(v) + `</v></c>`)
+		default:
+			w.sheet.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`)
+			xml.EscapeText(w.sheet, []byte(formatValue(v)))
+			w.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := w.sheet.WriteString(`</row>`)
+
+	return err
+}
+
+// columnName converts a zero based column index into its spreadsheet name, e.g. 0 is A and 27 is AB
+func columnName(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+
+	return name
+}
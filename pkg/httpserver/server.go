@@ -25,7 +25,7 @@ type Server struct {
 // New -.
 func New(handler http.Handler, opts ...Option) *Server {
 	httpServer := &http.Server{
-		Handler:      handler,
+		Handler:      withResponseController(handler),
 		ReadTimeout:  _defaultReadTimeout,
 		WriteTimeout: _defaultWriteTimeout,
 		Addr:         _defaultAddr,
@@ -67,3 +67,25 @@ func (s *Server) Shutdown() error {
 
 	return s.server.Shutdown(ctx)
 }
+
+type responseControllerKey struct{}
+
+// withResponseController makes the response controller of every request reachable from the context of the request,
+// for the handlers given a response writer hiding the connection, such as the one of gin
+func withResponseController(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), responseControllerKey{}, http.NewResponseController(w))
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// SetWriteDeadline overrides the write timeout of the server for the response of the request of ctx,
+// it returns http.ErrNotSupported when the request is not served by a Server
+func SetWriteDeadline(ctx context.Context, deadline time.Time) error {
+	rc, ok := ctx.Value(responseControllerKey{}).(*http.ResponseController)
+	if !ok {
+		return http.ErrNotSupported
+	}
+
+	return rc.SetWriteDeadline(deadline)
+}
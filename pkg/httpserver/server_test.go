@@ -0,0 +1,59 @@
+package httpserver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetWriteDeadline(t *testing.T) {
+	assert.Equal(t, http.ErrNotSupported, SetWriteDeadline(context.Background(), time.Now()))
+
+	// The response is written after the write timeout of the server, which only a later deadline lets through
+	testcases := []struct {
+		name     string
+		extend   bool
+		wantBody string
+	}{
+		{
+			name:     "write timeout",
+			wantBody: "",
+		},
+		{
+			name:     "extended write deadline",
+			extend:   true,
+			wantBody: "exported",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewUnstartedServer(withResponseController(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.extend {
+					assert.NoError(t, SetWriteDeadline(r.Context(), time.Now().Add(time.Second)))
+				}
+
+				time.Sleep(100 * time.Millisecond)
+				w.Write([]byte("exported"))
+			})))
+			server.Config.WriteTimeout = 50 * time.Millisecond
+			server.Start()
+			defer server.Close()
+
+			body := ""
+			res, err := http.Get(server.URL)
+			if err == nil {
+				b, _ := io.ReadAll(res.Body)
+				res.Body.Close()
+				body = string(b)
+			}
+
+			assert.Equal(t, tc.wantBody, body)
+		})
+	}
+}
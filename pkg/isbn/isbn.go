@@ -0,0 +1,190 @@
+// Package isbn implements validation, normalization and conversion of ISBN-10 and ISBN-13.
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	// Length10 is the length of a compact ISBN-10
+	Length10 = 10
+	// Length13 is the length of a compact ISBN-13
+	Length13 = 13
+	// BooklandPrefix is the EAN prefix used when converting ISBN-10 to ISBN-13
+	BooklandPrefix = "978"
+)
+
+var (
+	// ErrInvalidLength is returned when the compact ISBN is neither 10 nor 13 characters long
+	ErrInvalidLength = errors.New("isbn: invalid length")
+	// ErrInvalidCharacter is returned when the ISBN contains a character that is not allowed
+	ErrInvalidCharacter = errors.New("isbn: invalid character")
+	// ErrInvalidCheckDigit is returned when the check digit does not match
+	ErrInvalidCheckDigit = errors.New("isbn: invalid check digit")
+	// ErrNotConvertible is returned when an ISBN-13 has no ISBN-10 equivalent
+	ErrNotConvertible = errors.New("isbn: not convertible to ISBN-10")
+)
+
+// Compact removes hyphens and spaces and uppercases the ISBN-10 check character
+func Compact(s string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(s) {
+		switch r {
+		case '-', ' ':
+			continue
+		case 'x':
+			b.WriteRune('X')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// Validate10 validates the format and check digit of an ISBN-10
+func Validate10(s string) error {
+	s = Compact(s)
+	if len(s) != Length10 {
+		return ErrInvalidLength
+	}
+
+	for i := 0; i < Length10-1; i++ {
+		if !isDigit(s[i]) {
+			return ErrInvalidCharacter
+		}
+	}
+
+	last := s[Length10-1]
+	if !isDigit(last) && last != 'X' {
+		return ErrInvalidCharacter
+	}
+
+	if checkDigit10(s[:Length10-1]) != last {
+		return ErrInvalidCheckDigit
+	}
+
+	return nil
+}
+
+// Validate13 validates the format and check digit of an ISBN-13
+func Validate13(s string) error {
+	s = Compact(s)
+	if len(s) != Length13 {
+		return ErrInvalidLength
+	}
+
+	for i := 0; i < Length13; i++ {
+		if !isDigit(s[i]) {
+			return ErrInvalidCharacter
+		}
+	}
+
+	if !strings.HasPrefix(s, "978") && !strings.HasPrefix(s, "979") {
+		return ErrInvalidCharacter
+	}
+
+	if checkDigit13(s[:Length13-1]) != s[Length13-1] {
+		return ErrInvalidCheckDigit
+	}
+
+	return nil
+}
+
+// Validate validates an ISBN in either the 10 or 13 digit format
+func Validate(s string) error {
+	s = Compact(s)
+	switch len(s) {
+	case Length10:
+		return Validate10(s)
+	case Length13:
+		return Validate13(s)
+	default:
+		return ErrInvalidLength
+	}
+}
+
+// IsValid reports whether s is a valid ISBN-10 or ISBN-13
+func IsValid(s string) bool {
+	return Validate(s) == nil
+}
+
+// To13 converts a valid ISBN-10 into its compact ISBN-13 equivalent
+func To13(s string) (string, error) {
+	s = Compact(s)
+	if err := Validate10(s); err != nil {
+		return "", err
+	}
+
+	body := BooklandPrefix + s[:Length10-1]
+
+	return body + string(checkDigit13(body)), nil
+}
+
+// To10 converts a valid 978-prefixed ISBN-13 into its compact ISBN-10 equivalent
+func To10(s string) (string, error) {
+	s = Compact(s)
+	if err := Validate13(s); err != nil {
+		return "", err
+	}
+
+	if !strings.HasPrefix(s, BooklandPrefix) {
+		return "", ErrNotConvertible
+	}
+
+	body := s[len(BooklandPrefix) : Length13-1]
+
+	return body + string(checkDigit10(body)), nil
+}
+
+// Normalize validates an ISBN in any supported format and returns its canonical form,
+// which is the compact ISBN-13 without hyphens
+func Normalize(s string) (string, error) {
+	s = Compact(s)
+	switch len(s) {
+	case Length10:
+		return To13(s)
+	case Length13:
+		if err := Validate13(s); err != nil {
+			return "", err
+		}
+
+		return s, nil
+	default:
+		return "", ErrInvalidLength
+	}
+}
+
+// checkDigit10 computes the ISBN-10 check character of the first 9 digits
+func checkDigit10(body string) byte {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		sum += (10 - i) * int(body[i]-'0')
+	}
+
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+
+	return byte('0' + check)
+}
+
+// checkDigit13 computes the ISBN-13 check digit of the first 12 digits
+func checkDigit13(body string) byte {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(body[i]-'0')
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
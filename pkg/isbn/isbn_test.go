@@ -0,0 +1,128 @@
+package isbn_test
+
+import (
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/pkg/isbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	testcases := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{
+			name:    "valid isbn-10",
+			input:   "0-545-01022-5",
+			wantErr: nil,
+		},
+		{
+			name:    "valid isbn-10 with X check digit",
+			input:   "0-8044-2957-x",
+			wantErr: nil,
+		},
+		{
+			name:    "valid isbn-13",
+			input:   "978-0-545-01022-1",
+			wantErr: nil,
+		},
+		{
+			name:    "valid isbn-13 with spaces",
+			input:   "979 10 90636 07 1",
+			wantErr: nil,
+		},
+		{
+			name:    "invalid length",
+			input:   "978-0-545-01022-10",
+			wantErr: isbn.ErrInvalidLength,
+		},
+		{
+			name:    "invalid character",
+			input:   "0-545-0102A-5",
+			wantErr: isbn.ErrInvalidCharacter,
+		},
+		{
+			name:    "invalid prefix",
+			input:   "977-0-545-01022-1",
+			wantErr: isbn.ErrInvalidCharacter,
+		},
+		{
+			name:    "invalid isbn-10 check digit",
+			input:   "0-545-01022-6",
+			wantErr: isbn.ErrInvalidCheckDigit,
+		},
+		{
+			name:    "invalid isbn-13 check digit",
+			input:   "978-0-545-01022-2",
+			wantErr: isbn.ErrInvalidCheckDigit,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantErr, isbn.Validate(tc.input))
+			assert.Equal(t, tc.wantErr == nil, isbn.IsValid(tc.input))
+		})
+	}
+}
+
+func TestTo13(t *testing.T) {
+	res, err := isbn.To13("0-545-01022-5")
+	assert.Nil(t, err)
+	assert.Equal(t, "9780545010221", res)
+
+	_, err = isbn.To13("978-0-545-01022-1")
+	assert.Equal(t, isbn.ErrInvalidLength, err)
+}
+
+func TestTo10(t *testing.T) {
+	res, err := isbn.To10("978-0-8044-2957-3")
+	assert.Nil(t, err)
+	assert.Equal(t, "080442957X", res)
+
+	_, err = isbn.To10("979-10-90636-07-1")
+	assert.Equal(t, isbn.ErrNotConvertible, err)
+
+	_, err = isbn.To10("0-545-01022-5")
+	assert.Equal(t, isbn.ErrInvalidLength, err)
+}
+
+func TestNormalize(t *testing.T) {
+	testcases := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "isbn-10 is converted",
+			input:    "0-545-01022-5",
+			expected: "9780545010221",
+		},
+		{
+			name:     "isbn-13 is compacted",
+			input:    " 978-0-545-01022-1 ",
+			expected: "9780545010221",
+		},
+		{
+			name:    "invalid isbn-13",
+			input:   "978-0-545-01022-2",
+			wantErr: true,
+		},
+		{
+			name:    "invalid length",
+			input:   "978-0-545-01022-10",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := isbn.Normalize(tc.input)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
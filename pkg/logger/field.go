@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Field is a typed key and value added to a log entry
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// String -.
+func String(key string, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int -.
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 -.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool -.
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Duration -.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err is the error of a log entry under the error key
+func Err(err error) Field {
+	return Field{Key: zerolog.ErrorFieldName, Value: err}
+}
+
+// Any is a value of another type, logged as JSON
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+func (f Field) apply(c zerolog.Context) zerolog.Context {
+	switch v := f.Value.(type) {
+	case string:
+		return c.Str(f.Key, v)
+	case int:
+		return c.Int(f.Key, v)
+	case int64:
+		return c.Int64(f.Key, v)
+	case bool:
+		return c.Bool(f.Key, v)
+	case time.Duration:
+		return c.Dur(f.Key, v)
+	case error:
+		return c.AnErr(f.Key, v)
+	default:
+		return c.Interface(f.Key, v)
+	}
+}
+
+type fieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx carrying the fields along with the ones ctx already carries,
+// they are added to every entry of a logger given ctx by WithContext
+func ContextWithFields(ctx context.Context, fields ...Field) context.Context {
+	current := FieldsFromContext(ctx)
+	merged := make([]Field, 0, len(current)+len(fields))
+	merged = append(merged, current...)
+	merged = append(merged, fields...)
+
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// FieldsFromContext get the fields carried by ctx
+func FieldsFromContext(ctx context.Context) []Field {
+	fields, _ := ctx.Value(fieldsKey{}).([]Field)
+
+	return fields
+}
@@ -1,11 +1,14 @@
 package logger
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // LoggerInterface -.
@@ -15,6 +18,10 @@ type LoggerInterface interface {
 	Warn(message string, args ...interface{})
 	Error(message interface{}, args ...interface{})
 	Fatal(message interface{}, args ...interface{})
+	// With returns a logger adding the fields to every entry
+	With(fields ...Field) LoggerInterface
+	// WithContext returns a logger adding the fields carried by ctx, such as the request ID, and the IDs of the span in ctx to every entry
+	WithContext(ctx context.Context) LoggerInterface
 }
 
 // Logger -.
@@ -26,6 +33,10 @@ var _ LoggerInterface = (*Logger)(nil)
 
 // New -.
 func New(level string) *Logger {
+	return newLogger(os.Stdout, level)
+}
+
+func newLogger(w io.Writer, level string) *Logger {
 	var l zerolog.Level
 
 	switch strings.ToLower(level) {
@@ -41,10 +52,8 @@ func New(level string) *Logger {
 		l = zerolog.InfoLevel
 	}
 
-	zerolog.SetGlobalLevel(l)
-
-	skipFrameCount := 3
-	logger := zerolog.New(os.Stdout).With().Timestamp().CallerWithSkipFrameCount(zerolog.CallerSkipFrameCount + skipFrameCount).Logger()
+	skipFrameCount := 2
+	logger := zerolog.New(w).Level(l).With().Timestamp().CallerWithSkipFrameCount(zerolog.CallerSkipFrameCount + skipFrameCount).Logger()
 
 	return &Logger{
 		logger: &logger,
@@ -53,50 +62,88 @@ func New(level string) *Logger {
 
 // Debug -.
 func (l *Logger) Debug(message interface{}, args ...interface{}) {
-	l.msg("debug", message, args...)
+	l.msg(zerolog.DebugLevel, message, args...)
 }
 
 // Info -.
 func (l *Logger) Info(message string, args ...interface{}) {
-	l.log(message, args...)
+	l.msg(zerolog.InfoLevel, message, args...)
 }
 
 // Warn -.
 func (l *Logger) Warn(message string, args ...interface{}) {
-	l.log(message, args...)
+	l.msg(zerolog.WarnLevel, message, args...)
 }
 
-// Error -.
+// Error logs the message at error level. An error message is logged under the error key,
+// with args as the message such as Error(err, "http - v1 - order - CreateOrder: CreateOrder")
 func (l *Logger) Error(message interface{}, args ...interface{}) {
-	if l.logger.GetLevel() == zerolog.DebugLevel {
-		l.Debug(message, args...)
-	}
-
-	l.msg("error", message, args...)
+	l.msg(zerolog.ErrorLevel, message, args...)
 }
 
 // Fatal -.
 func (l *Logger) Fatal(message interface{}, args ...interface{}) {
-	l.msg("fatal", message, args...)
+	l.msg(zerolog.FatalLevel, message, args...)
 
 	os.Exit(1)
 }
 
-func (l *Logger) log(message string, args ...interface{}) {
-	if len(args) == 0 {
-		l.logger.Info().Msg(message)
-	} else {
-		l.logger.Info().Msgf(message, args...)
+// With -.
+func (l *Logger) With(fields ...Field) LoggerInterface {
+	if len(fields) == 0 {
+		return l
+	}
+
+	c := l.logger.With()
+	for _, field := range fields {
+		c = field.apply(c)
+	}
+	logger := c.Logger()
+
+	return &Logger{
+		logger: &logger,
 	}
 }
 
-func (l *Logger) msg(level string, message interface{}, args ...interface{}) {
+// WithContext -.
+func (l *Logger) WithContext(ctx context.Context) LoggerInterface {
+	fields := FieldsFromContext(ctx)
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		fields = append(fields[:len(fields):len(fields)], String("trace_id", spanContext.TraceID().String()), String("span_id", spanContext.SpanID().String()))
+	}
+
+	return l.With(fields...)
+}
+
+func (l *Logger) msg(level zerolog.Level, message interface{}, args ...interface{}) {
+	e := l.logger.WithLevel(level)
+
 	switch msg := message.(type) {
 	case error:
-		l.log(msg.Error(), args...)
+		if len(args) == 0 {
+			args = []interface{}{msg.Error()}
+		}
+		e.Err(msg).Msg(format(args...))
 	case string:
-		l.log(msg, args...)
+		e.Msg(format(append([]interface{}{msg}, args...)...))
 	default:
-		l.log(fmt.Sprintf("%s message %v has unknown type %v", level, message, msg), args...)
+		e.Msg(fmt.Sprintf("%s message %v has unknown type %v", level, message, msg))
+	}
+}
+
+// format returns the first arg formatted with the rest when it is a string
+func format(args ...interface{}) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	if f, ok := args[0].(string); ok {
+		if len(args) == 1 {
+			return f
+		}
+
+		return fmt.Sprintf(f, args[1:]...)
 	}
+
+	return fmt.Sprint(args...)
 }
@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func decodeEntries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	entries := []map[string]interface{}{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		entry := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func TestLevels(t *testing.T) {
+	testcases := []struct {
+		name           string
+		level          string
+		log            func(l *Logger)
+		expectedLevels []string
+	}{
+		{
+			name:  "debug level",
+			level: "debug",
+			log: func(l *Logger) {
+				l.Debug("debug")
+				l.Info("info")
+				l.Warn("warn")
+				l.Error("error")
+			},
+			expectedLevels: []string{"debug", "info", "warn", "error"},
+		},
+		{
+			name:  "warn level",
+			level: "warn",
+			log: func(l *Logger) {
+				l.Debug("debug")
+				l.Info("info")
+				l.Warn("warn")
+				l.Error(errors.New("error"))
+			},
+			expectedLevels: []string{"warn", "error"},
+		},
+		{
+			name:  "unknown level",
+			level: "verbose",
+			log: func(l *Logger) {
+				l.Debug("debug")
+				l.Info("info")
+			},
+			expectedLevels: []string{"info"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			tc.log(newLogger(buf, tc.level))
+
+			levels := []string{}
+			for _, entry := range decodeEntries(t, buf) {
+				levels = append(levels, entry["level"].(string))
+			}
+			assert.Equal(t, tc.expectedLevels, levels)
+		})
+	}
+}
+
+func TestMessage(t *testing.T) {
+	testcases := []struct {
+		name            string
+		log             func(l *Logger)
+		expectedMessage string
+		expectedError   interface{}
+	}{
+		{
+			name:            "string",
+			log:             func(l *Logger) { l.Info("signal: interrupt") },
+			expectedMessage: "signal: interrupt",
+		},
+		{
+			name:            "formatted string",
+			log:             func(l *Logger) { l.Info("done in %s", time.Second) },
+			expectedMessage: "done in 1s",
+		},
+		{
+			name:            "error",
+			log:             func(l *Logger) { l.Error(errors.New("connection refused")) },
+			expectedMessage: "connection refused",
+			expectedError:   "connection refused",
+		},
+		{
+			name: "error with message",
+			log: func(l *Logger) {
+				l.Error(errors.New("connection refused"), "http - v1 - order - CreateOrder: CreateOrder")
+			},
+			expectedMessage: "http - v1 - order - CreateOrder: CreateOrder",
+			expectedError:   "connection refused",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			tc.log(newLogger(buf, "info"))
+
+			entries := decodeEntries(t, buf)
+			assert.Len(t, entries, 1)
+			assert.Equal(t, tc.expectedMessage, entries[0]["message"])
+			assert.Equal(t, tc.expectedError, entries[0]["error"])
+			assert.Contains(t, entries[0]["caller"], "logger_test.go")
+		})
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := newLogger(buf, "info")
+
+	ctx := ContextWithFields(context.Background(), String("request_id", "abc"), String("route", "/v1/orders/"))
+	ctx = ContextWithFields(ctx, Int("user_id", 7))
+	l.WithContext(ctx).With(Duration("latency", time.Millisecond), Bool("retried", false)).Warn("slow")
+	l.Info("plain")
+
+	entries := decodeEntries(t, buf)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "abc", entries[0]["request_id"])
+	assert.Equal(t, "/v1/orders/", entries[0]["route"])
+	assert.Equal(t, float64(7), entries[0]["user_id"])
+	assert.Equal(t, float64(1), entries[0]["latency"])
+	assert.Equal(t, false, entries[0]["retried"])
+	assert.NotContains(t, entries[1], "request_id")
+}
+
+func TestWithContextSpan(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := newLogger(buf, "info")
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+	ctx = ContextWithFields(ctx, String("request_id", "abc"))
+	l.WithContext(ctx).Error(errors.New("connection refused"), "http - v1 - order - CreateOrder: CreateOrder")
+
+	entries := decodeEntries(t, buf)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "abc", entries[0]["request_id"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entries[0]["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", entries[0]["span_id"])
+	assert.Len(t, FieldsFromContext(ctx), 1)
+}
@@ -0,0 +1,72 @@
+// Package mailer sends transactional emails such as password reset links.
+package mailer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Message holds a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer defines an interface for sending emails
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// FileMailer writes every email as an .eml file under a directory instead of sending it,
+// for local development and tests
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+var _ Mailer = (*FileMailer)(nil)
+
+// NewFileMailer returns a mailer writing emails from the given address under dir
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{
+		dir:  dir,
+		from: from,
+	}
+}
+
+// Send writes the email to a new file named after the time it was sent
+func (m *FileMailer) Send(ctx context.Context, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+
+	// Only the recipient should read the links in the email
+	return os.WriteFile(filepath.Join(m.dir, name), []byte(b.String()), 0o600)
+}
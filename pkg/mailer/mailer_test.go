@@ -0,0 +1,37 @@
+package mailer_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/pkg/mailer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m := mailer.NewFileMailer(dir, "no-reply@book-store.local")
+
+	msg := &mailer.Message{To: "foo@bar.com", Subject: "Hello", Body: "Hello Foo\n"}
+	assert.Nil(t, m.Send(context.Background(), msg))
+	assert.Nil(t, m.Send(context.Background(), msg))
+
+	files, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Len(t, files, 2)
+	assert.True(t, strings.HasSuffix(files[0].Name(), ".eml"))
+
+	content, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), "From: no-reply@book-store.local\r\n")
+	assert.Contains(t, string(content), "To: foo@bar.com\r\n")
+	assert.Contains(t, string(content), "Subject: Hello\r\n")
+	assert.True(t, strings.HasSuffix(string(content), "\r\n\r\nHello Foo\n"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NotNil(t, m.Send(ctx, msg))
+}
@@ -0,0 +1,133 @@
+// Package metrics holds the Prometheus metrics of the book store and serves them.
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "book_store"
+
+// Registry holds every metric of the book store with the Go runtime and process metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestsTotal counts the served requests by method, route and status
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests served by method, route and status.",
+	}, []string{"method", "route", "status"})
+	// HTTPRequestDuration observes the latency of the served requests by method, route and status
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// DBTransactionsTotal counts the database transactions ended by operation, commit or rollback, and status, success or error
+	DBTransactionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "transactions_total",
+		Help:      "Number of database transactions ended by operation and status.",
+	}, []string{"operation", "status"})
+
+	// OrdersCreatedTotal counts the placed orders
+	OrdersCreatedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_created_total",
+		Help:      "Number of orders placed.",
+	})
+	// OrderRevenueTotal sums the total price of the placed orders, fees included
+	OrderRevenueTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "order_revenue_total",
+		Help:      "Sum of the total price of the orders placed, fees included.",
+	})
+	// BooksSoldTotal counts the books sold by the placed orders
+	BooksSoldTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "books_sold_total",
+		Help:      "Number of books sold by the orders placed.",
+	})
+	// LoginsTotal counts the logins by result, succeeded or failed
+	LoginsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Number of logins by result.",
+	}, []string{"result"})
+	// UsersRegisteredTotal counts the registered users
+	UsersRegisteredTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "users_registered_total",
+		Help:      "Number of users registered.",
+	})
+)
+
+const (
+	// TransactionCommit -.
+	TransactionCommit = "commit"
+	// TransactionRollback -.
+	TransactionRollback = "rollback"
+
+	// StatusSuccess -.
+	StatusSuccess = "success"
+	// StatusError -.
+	StatusError = "error"
+
+	// LoginSucceeded -.
+	LoginSucceeded = "succeeded"
+	// LoginFailed -.
+	LoginFailed = "failed"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		DBTransactionsTotal,
+		OrdersCreatedTotal,
+		OrderRevenueTotal,
+		BooksSoldTotal,
+		LoginsTotal,
+		UsersRegisteredTotal,
+	)
+}
+
+// Status returns StatusError when err is not nil, else StatusSuccess
+func Status(err error) string {
+	if err != nil {
+		return StatusError
+	}
+
+	return StatusSuccess
+}
+
+// RegisterDB exposes the connection pool statistics of db, labelled with the database name.
+// A pool of a database already registered is ignored
+func RegisterDB(db *sql.DB, name string) error {
+	err := Registry.Register(collectors.NewDBStatsCollector(db, name))
+
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		return nil
+	}
+
+	return err
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,37 @@
+package metrics_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/satriowisnugroho/book-store/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus(t *testing.T) {
+	assert.Equal(t, metrics.StatusSuccess, metrics.Status(nil))
+	assert.Equal(t, metrics.StatusError, metrics.Status(errors.New("error commit tx")))
+}
+
+func TestRegisterDB(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	assert.NoError(t, metrics.RegisterDB(db, "book_store_test"))
+	// A second pool of the same database is ignored
+	assert.NoError(t, metrics.RegisterDB(db, "book_store_test"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	metrics.Handler().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `go_sql_max_open_connections{db_name="book_store_test"}`)
+	assert.Contains(t, w.Body.String(), "go_goroutines")
+}
@@ -0,0 +1,406 @@
+// Package oidc signs users in with an OpenID Connect provider, using the authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/satriowisnugroho/book-store/pkg/auth"
+)
+
+const (
+	// discoveryPath is where a provider serves its metadata, relative to the issuer URL
+	discoveryPath = "/.well-known/openid-configuration"
+	// codeVerifierBytes is the number of random bytes of a PKCE code verifier
+	codeVerifierBytes = 32
+	// maxResponseBytes caps the size of a response read from the provider
+	maxResponseBytes = 1 << 20
+	// clockSkew is how far the issued at time of an ID token may be in the future
+	clockSkew = time.Minute
+)
+
+var (
+	// ErrDiscovery is returned when the provider metadata or keys can't be fetched or are invalid
+	ErrDiscovery = errors.New("oidc: discovery failed")
+	// ErrTokenExchange is returned when the provider rejects the authorization code
+	ErrTokenExchange = errors.New("oidc: token exchange failed")
+	// ErrInvalidIDToken is returned for an ID token with a bad signature or claims
+	ErrInvalidIDToken = errors.New("oidc: invalid ID token")
+)
+
+// signingMethods are the ID token algorithms which are accepted, none and the HMAC ones never are
+var signingMethods = map[string]bool{
+	jwt.SigningMethodRS256.Alg(): true,
+	jwt.SigningMethodES256.Alg(): true,
+	jwt.SigningMethodEdDSA.Alg(): true,
+}
+
+// Config holds the client registration at the provider
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Claims holds the identity of a user signed in at the provider
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// IdentityProvider defines an interface for signing users in with an OpenID Connect provider
+type IdentityProvider interface {
+	Issuer() string
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Claims, error)
+}
+
+// metadata holds the part of the provider metadata used by the flow
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// tokenResponse holds the response of the token endpoint
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Provider is an OpenID Connect provider, its metadata and keys are fetched on first use and cached
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *metadata
+	keys     map[string]auth.JWK
+}
+
+var _ IdentityProvider = (*Provider)(nil)
+
+// NewProvider returns a provider for the given client registration
+func NewProvider(config Config, client *http.Client) *Provider {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &Provider{
+		config: config,
+		client: client,
+	}
+}
+
+// Issuer returns the issuer URL of the provider
+func (p *Provider) Issuer() string {
+	return p.config.IssuerURL
+}
+
+// AuthCodeURL returns the URL the user is redirected to for signing in at the provider
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(md.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// Exchange redeems an authorization code and returns the claims of the verified ID token
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Claims, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	if p.config.ClientSecret == "" {
+		form.Set("client_id", p.config.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	token := tokenResponse{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&token); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenExchange, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s %s", ErrTokenExchange, token.Error, token.ErrorDescription)
+	}
+
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("%w: no id_token", ErrTokenExchange)
+	}
+
+	return p.verify(ctx, md, token.IDToken, nonce)
+}
+
+// verify checks the signature and the claims of an ID token
+func (p *Provider) verify(ctx context.Context, md *metadata, idToken, nonce string) (*Claims, error) {
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return p.keyfunc(ctx, token)
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	now := time.Now()
+	if !claims.VerifyIssuer(md.Issuer, true) || !claims.VerifyExpiresAt(now.Unix(), true) || !claims.VerifyIssuedAt(now.Add(clockSkew).Unix(), true) {
+		return nil, fmt.Errorf("%w: bad issuer or lifetime", ErrInvalidIDToken)
+	}
+
+	audience := stringList(claims["aud"])
+	if !contains(audience, p.config.ClientID) {
+		return nil, fmt.Errorf("%w: bad audience", ErrInvalidIDToken)
+	}
+
+	// The authorized party must be this client when the token is issued to several audiences
+	if azp, ok := claims["azp"].(string); (len(audience) > 1 || ok) && azp != p.config.ClientID {
+		return nil, fmt.Errorf("%w: bad authorized party", ErrInvalidIDToken)
+	}
+
+	if n, _ := claims["nonce"].(string); n == "" || n != nonce {
+		return nil, fmt.Errorf("%w: bad nonce", ErrInvalidIDToken)
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+
+	result := &Claims{Subject: subject}
+	result.Email, _ = claims["email"].(string)
+	result.Name, _ = claims["name"].(string)
+
+	// Some providers send email_verified as a string
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		result.EmailVerified = verified
+	case string:
+		result.EmailVerified = verified == "true"
+	}
+
+	return result, nil
+}
+
+// keyfunc returns the provider key which signed a token, the keys are refetched once for an unknown key ID
+func (p *Provider) keyfunc(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	alg := token.Method.Alg()
+	if !signingMethods[alg] {
+		return nil, fmt.Errorf("oidc: unexpected signing algorithm %s", alg)
+	}
+
+	kid, _ := token.Header["kid"].(string)
+
+	p.mu.Lock()
+	key, ok := p.keys[kid]
+	p.mu.Unlock()
+
+	if !ok {
+		if err := p.fetchKeys(ctx); err != nil {
+			return nil, err
+		}
+
+		p.mu.Lock()
+		key, ok = p.keys[kid]
+		p.mu.Unlock()
+
+		if !ok {
+			return nil, auth.ErrUnknownKeyID
+		}
+	}
+
+	if key.Algorithm != "" && key.Algorithm != alg {
+		return nil, fmt.Errorf("oidc: unexpected signing algorithm %s", alg)
+	}
+
+	return key.PublicKey()
+}
+
+// discover returns the provider metadata, fetching it on first use
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	md := p.metadata
+	p.mu.Unlock()
+
+	if md != nil {
+		return md, nil
+	}
+
+	md = &metadata{}
+	if err := p.getJSON(ctx, strings.TrimSuffix(p.config.IssuerURL, "/")+discoveryPath, md); err != nil {
+		return nil, err
+	}
+
+	if md.Issuer != p.config.IssuerURL {
+		return nil, fmt.Errorf("%w: issuer %s does not match %s", ErrDiscovery, md.Issuer, p.config.IssuerURL)
+	}
+
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: missing endpoints", ErrDiscovery)
+	}
+
+	p.mu.Lock()
+	p.metadata = md
+	p.mu.Unlock()
+
+	return md, nil
+}
+
+// fetchKeys replaces the cached provider keys with the ones served at the JWKS endpoint
+func (p *Provider) fetchKeys(ctx context.Context) error {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return err
+	}
+
+	jwks := auth.JWKS{}
+	if err := p.getJSON(ctx, md.JWKSURI, &jwks); err != nil {
+		return err
+	}
+
+	keys := make(map[string]auth.JWK, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		if key.Use == "" || key.Use == "sig" {
+			keys[key.KeyID] = key
+		}
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	return nil
+}
+
+// getJSON decodes the JSON document served at the given URL
+func (p *Provider) getJSON(ctx context.Context, u string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s returned %d", ErrDiscovery, u, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+
+	return nil
+}
+
+// GenerateCodeVerifier returns a random PKCE code verifier
+func GenerateCodeVerifier() (string, error) {
+	b := make([]byte, codeVerifierBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallengeS256 returns the S256 PKCE code challenge of a code verifier
+func CodeChallengeS256(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// stringList returns a claim which is either a string or a list of strings as a list
+func stringList(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+
+		return list
+	}
+
+	return nil
+}
+
+// contains reports whether s is in the list
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
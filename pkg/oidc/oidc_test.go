@@ -0,0 +1,306 @@
+package oidc_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/satriowisnugroho/book-store/pkg/auth"
+	"github.com/satriowisnugroho/book-store/pkg/oidc"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubProvider is an OpenID Connect provider serving discovery, authorization, token and JWKS endpoints in-process
+type stubProvider struct {
+	*httptest.Server
+	key      *auth.SigningKey
+	keySet   *auth.KeySet
+	requests map[string]url.Values
+	claims   func(authRequest url.Values) jwt.MapClaims
+}
+
+func newStubProvider(t *testing.T) *stubProvider {
+	key, err := auth.GenerateSigningKey(auth.SigningAlgorithmRS256)
+	assert.NoError(t, err)
+
+	s := &stubProvider{key: key, requests: map[string]url.Values{}}
+	s.keySet = auth.NewKeySet("", "", time.Minute)
+	s.keySet.SetKeys([]*auth.SigningKey{key})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 s.URL,
+			"authorization_endpoint": s.URL + "/authorize",
+			"token_endpoint":         s.URL + "/token",
+			"jwks_uri":               s.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		code := "code-" + r.URL.Query().Get("state")
+		s.requests[code] = r.URL.Query()
+		http.Redirect(w, r, r.URL.Query().Get("redirect_uri")+"?code="+code+"&state="+r.URL.Query().Get("state"), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		authRequest, ok := s.requests[r.FormValue("code")]
+		clientID, clientSecret, _ := r.BasicAuth()
+		if !ok || clientID != "book-store" || clientSecret != "secret" ||
+			oidc.CodeChallengeS256(r.FormValue("code_verifier")) != authRequest.Get("code_challenge") {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		delete(s.requests, r.FormValue("code"))
+
+		idToken := s.sign(s.claims(authRequest))
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "access", "token_type": "Bearer", "id_token": idToken})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(s.keySet.JWKS())
+	})
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	s.claims = func(authRequest url.Values) jwt.MapClaims {
+		return s.validClaims(authRequest)
+	}
+
+	return s
+}
+
+func (s *stubProvider) validClaims(authRequest url.Values) jwt.MapClaims {
+	now := time.Now()
+
+	return jwt.MapClaims{
+		"iss":            s.URL,
+		"aud":            "book-store",
+		"sub":            "user-1",
+		"email":          "foo@bar.com",
+		"email_verified": true,
+		"name":           "Foo",
+		"nonce":          authRequest.Get("nonce"),
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Minute).Unix(),
+	}
+}
+
+func (s *stubProvider) sign(claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(s.key.Algorithm), claims)
+	token.Header["kid"] = s.key.ID
+	signed, _ := token.SignedString(s.key.PrivateKey)
+
+	return signed
+}
+
+// authorize follows the authorization URL like a browser and returns the authorization code of the redirect
+func authorize(t *testing.T, authURL string) string {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authURL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	assert.NoError(t, err)
+
+	return location.Query().Get("code")
+}
+
+func TestCodeChallengeS256(t *testing.T) {
+	assert.Equal(t, "3gD-mGlnFachYOr1I8cBccnTYyIyLSxTsgbaOuL-tZ8", oidc.CodeChallengeS256("dBjjuSoXYdNUoNHtZy-YarNrY8vRaaMexOvbWBfuxK_jEbP8WA"))
+
+	verifier, err := oidc.GenerateCodeVerifier()
+	assert.NoError(t, err)
+	assert.Len(t, verifier, 43)
+}
+
+func TestProviderAuthCodeURL(t *testing.T) {
+	s := newStubProvider(t)
+	p := oidc.NewProvider(oidc.Config{IssuerURL: s.URL, ClientID: "book-store", RedirectURL: "http://localhost:3000/callback"}, s.Client())
+
+	authURL, err := p.AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+	assert.NoError(t, err)
+
+	u, _ := url.Parse(authURL)
+	assert.Equal(t, s.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "code", u.Query().Get("response_type"))
+	assert.Equal(t, "book-store", u.Query().Get("client_id"))
+	assert.Equal(t, "http://localhost:3000/callback", u.Query().Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", u.Query().Get("scope"))
+	assert.Equal(t, "state", u.Query().Get("state"))
+	assert.Equal(t, "nonce", u.Query().Get("nonce"))
+	assert.Equal(t, "challenge", u.Query().Get("code_challenge"))
+	assert.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+	assert.Equal(t, s.URL, p.Issuer())
+
+	p = oidc.NewProvider(oidc.Config{IssuerURL: s.URL + "/other", ClientID: "book-store"}, s.Client())
+	_, err = p.AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+	assert.ErrorIs(t, err, oidc.ErrDiscovery)
+}
+
+func TestProviderExchange(t *testing.T) {
+	testcases := []struct {
+		name          string
+		claims        func(s *stubProvider, authRequest url.Values) jwt.MapClaims
+		sign          func(s *stubProvider, claims jwt.MapClaims) string
+		codeVerifier  string
+		nonce         string
+		expectedEmail string
+		expectedErr   error
+	}{
+		{
+			name:          "success",
+			expectedEmail: "foo@bar.com",
+		},
+		{
+			name: "success with several audiences",
+			claims: func(s *stubProvider, authRequest url.Values) jwt.MapClaims {
+				claims := s.validClaims(authRequest)
+				claims["aud"] = []string{"other", "book-store"}
+				claims["azp"] = "book-store"
+				return claims
+			},
+			expectedEmail: "foo@bar.com",
+		},
+		{
+			name:         "wrong code verifier",
+			codeVerifier: "wrong",
+			expectedErr:  oidc.ErrTokenExchange,
+		},
+		{
+			name:        "wrong nonce",
+			nonce:       "wrong",
+			expectedErr: oidc.ErrInvalidIDToken,
+		},
+		{
+			name: "wrong issuer",
+			claims: func(s *stubProvider, authRequest url.Values) jwt.MapClaims {
+				claims := s.validClaims(authRequest)
+				claims["iss"] = "https://evil.example"
+				return claims
+			},
+			expectedErr: oidc.ErrInvalidIDToken,
+		},
+		{
+			name: "wrong audience",
+			claims: func(s *stubProvider, authRequest url.Values) jwt.MapClaims {
+				claims := s.validClaims(authRequest)
+				claims["aud"] = "other"
+				return claims
+			},
+			expectedErr: oidc.ErrInvalidIDToken,
+		},
+		{
+			name: "several audiences without authorized party",
+			claims: func(s *stubProvider, authRequest url.Values) jwt.MapClaims {
+				claims := s.validClaims(authRequest)
+				claims["aud"] = []string{"other", "book-store"}
+				return claims
+			},
+			expectedErr: oidc.ErrInvalidIDToken,
+		},
+		{
+			name: "expired",
+			claims: func(s *stubProvider, authRequest url.Values) jwt.MapClaims {
+				claims := s.validClaims(authRequest)
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+				return claims
+			},
+			expectedErr: oidc.ErrInvalidIDToken,
+		},
+		{
+			name: "signed by an unknown key",
+			sign: func(s *stubProvider, claims jwt.MapClaims) string {
+				key, _ := auth.GenerateSigningKey(auth.SigningAlgorithmRS256)
+				token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+				token.Header["kid"] = s.key.ID
+				signed, _ := token.SignedString(key.PrivateKey)
+				return signed
+			},
+			expectedErr: oidc.ErrInvalidIDToken,
+		},
+		{
+			name: "unsigned",
+			sign: func(s *stubProvider, claims jwt.MapClaims) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+				token.Header["kid"] = s.key.ID
+				signed, _ := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+				return signed
+			},
+			expectedErr: oidc.ErrInvalidIDToken,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newStubProvider(t)
+			s.claims = func(authRequest url.Values) jwt.MapClaims {
+				if tc.claims != nil {
+					return tc.claims(s, authRequest)
+				}
+				return s.validClaims(authRequest)
+			}
+
+			p := oidc.NewProvider(oidc.Config{IssuerURL: s.URL, ClientID: "book-store", ClientSecret: "secret", RedirectURL: "http://localhost:3000/callback"}, s.Client())
+
+			if tc.sign != nil {
+				mux := s.Config.Handler
+				s.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/token" {
+						_ = json.NewEncoder(w).Encode(map[string]string{"id_token": tc.sign(s, s.validClaims(url.Values{"nonce": {"nonce"}}))})
+						return
+					}
+					mux.ServeHTTP(w, r)
+				})
+			}
+
+			codeVerifier, _ := oidc.GenerateCodeVerifier()
+			authURL, err := p.AuthCodeURL(context.Background(), "state", "nonce", oidc.CodeChallengeS256(codeVerifier))
+			assert.NoError(t, err)
+
+			code := authorize(t, authURL)
+
+			if tc.codeVerifier != "" {
+				codeVerifier = tc.codeVerifier
+			}
+
+			nonce := "nonce"
+			if tc.nonce != "" {
+				nonce = tc.nonce
+			}
+
+			claims, err := p.Exchange(context.Background(), code, codeVerifier, nonce)
+			assert.ErrorIs(t, err, tc.expectedErr)
+			if tc.expectedErr == nil {
+				assert.Equal(t, "user-1", claims.Subject)
+				assert.Equal(t, tc.expectedEmail, claims.Email)
+				assert.True(t, claims.EmailVerified)
+				assert.Equal(t, "Foo", claims.Name)
+			}
+		})
+	}
+}
+
+func TestProviderExchangeKeyRotation(t *testing.T) {
+	s := newStubProvider(t)
+	p := oidc.NewProvider(oidc.Config{IssuerURL: s.URL, ClientID: "book-store", ClientSecret: "secret", RedirectURL: "http://localhost:3000/callback"}, s.Client())
+
+	codeVerifier, _ := oidc.GenerateCodeVerifier()
+	authURL, _ := p.AuthCodeURL(context.Background(), "first", "nonce", oidc.CodeChallengeS256(codeVerifier))
+	_, err := p.Exchange(context.Background(), authorize(t, authURL), codeVerifier, "nonce")
+	assert.NoError(t, err)
+
+	// The provider rotates to an Ed25519 key which isn't cached yet
+	key, _ := auth.GenerateSigningKey(auth.SigningAlgorithmEdDSA)
+	s.key = key
+	s.keySet.SetKeys([]*auth.SigningKey{key})
+
+	authURL, _ = p.AuthCodeURL(context.Background(), "second", "nonce", oidc.CodeChallengeS256(codeVerifier))
+	_, err = p.Exchange(context.Background(), authorize(t, authURL), codeVerifier, "nonce")
+	assert.NoError(t, err)
+}
@@ -3,10 +3,13 @@ package postgres
 import (
 	"fmt"
 
+	"github.com/XSAM/otelsql"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 
 	"github.com/satriowisnugroho/book-store/internal/config"
+	"github.com/satriowisnugroho/book-store/pkg/metrics"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Postgres holds database connection to postgreSQL
@@ -16,12 +19,24 @@ type Postgres struct {
 
 // NewPostgres initializes postgres database connection from configs
 func NewPostgres(opt *config.DatabaseConfig) (*Postgres, error) {
-	postgresDb, err := sqlx.Open(opt.Driver, fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%s sslmode=disable", opt.Username, opt.Password, opt.Name, opt.Host, opt.Port))
+	// Every query is traced as a child span of the span in its context
+	db, err := otelsql.Open(
+		opt.Driver,
+		fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%s sslmode=disable", opt.Username, opt.Password, opt.Name, opt.Host, opt.Port),
+		otelsql.WithAttributes(attribute.String("db.system", "postgresql"), attribute.String("db.name", opt.Name)),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}),
+	)
 	if err != nil {
 		return &Postgres{}, err
 	}
+	postgresDb := sqlx.NewDb(db, opt.Driver)
 
 	postgresDb.SetMaxOpenConns(opt.Pool)
 
+	// Expose the pool statistics such as the open, in use and idle connections
+	if err := metrics.RegisterDB(postgresDb.DB, opt.Name); err != nil {
+		return &Postgres{}, err
+	}
+
 	return &Postgres{Db: postgresDb}, nil
 }
@@ -0,0 +1,73 @@
+// Package scheduler runs background jobs at a fixed interval.
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+)
+
+// Job is a unit of background work, a failed run is logged and the job runs again at the next tick
+type Job func(ctx context.Context) error
+
+// Scheduler -.
+type Scheduler struct {
+	logger logger.LoggerInterface
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New -.
+func New(l logger.LoggerInterface) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Scheduler{
+		logger: l,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Every runs the job right away and then every interval until the scheduler is stopped,
+// runs of the same job never overlap
+func (s *Scheduler) Every(name string, interval time.Duration, job Job) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.run(name, job)
+
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the running jobs and waits for them to return
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(name string, job Job) {
+	l := s.logger.With(logger.String("job", name))
+
+	start := time.Now()
+	if err := job(s.ctx); err != nil {
+		l.Error(err, "scheduler - run: failed")
+
+		return
+	}
+
+	l.With(logger.Duration("duration", time.Since(start))).Info("scheduler - run: done")
+}
@@ -0,0 +1,58 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/satriowisnugroho/book-store/pkg/logger"
+	"github.com/satriowisnugroho/book-store/pkg/scheduler"
+	testmock "github.com/satriowisnugroho/book-store/test/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEvery(t *testing.T) {
+	l := &testmock.LoggerInterface{}
+	l.On("With", mock.Anything).Return(l)
+	l.On("Info", mock.Anything)
+	l.On("Error", mock.Anything, mock.Anything)
+
+	var runs int32
+	s := scheduler.New(l)
+	s.Every("test", 5*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+
+		return nil
+	})
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&runs) >= 3 }, time.Second, time.Millisecond)
+	s.Stop()
+
+	stoppedRuns := atomic.LoadInt32(&runs)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, stoppedRuns, atomic.LoadInt32(&runs))
+	l.AssertNotCalled(t, "Error", mock.Anything, mock.Anything)
+}
+
+func TestEveryLogsFailedRun(t *testing.T) {
+	l := &testmock.LoggerInterface{}
+	l.On("With", mock.Anything).Return(l)
+	l.On("Error", mock.Anything, mock.Anything)
+
+	var runs int32
+	s := scheduler.New(l)
+	s.Every("test", time.Hour, func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+
+		return errors.New("error run job")
+	})
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&runs) == 1 }, time.Second, time.Millisecond)
+	s.Stop()
+
+	l.AssertCalled(t, "With", logger.String("job", "test"))
+	l.AssertCalled(t, "Error", mock.Anything, "scheduler - run: failed")
+}
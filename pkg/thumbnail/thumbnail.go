@@ -0,0 +1,68 @@
+// Package thumbnail scales images down with the standard image packages.
+package thumbnail
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+)
+
+// Quality is the JPEG quality of the encoded thumbnails
+const Quality = 85
+
+// Resize scales src down to the given width keeping the aspect ratio, images narrower than width keep their size.
+// Transparent pixels are flattened onto a white background since thumbnails are encoded as JPEG
+func Resize(src image.Image, width int) *image.RGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	flat := image.NewRGBA(image.Rect(0, 0, srcW, srcH))
+	draw.Draw(flat, flat.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, bounds.Min, draw.Over)
+
+	if width <= 0 || srcW <= width {
+		return flat
+	}
+
+	height := srcH * width / srcW
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcH/height, (y+1)*srcH/height
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcW/width, (x+1)*srcW/width
+
+			// Box filter, average every source pixel covered by the destination pixel
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				offset := flat.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += int(flat.Pix[offset])
+					g += int(flat.Pix[offset+1])
+					b += int(flat.Pix[offset+2])
+					a += int(flat.Pix[offset+3])
+					n++
+					offset += 4
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
+
+// Encode writes the thumbnail of src at the given width as JPEG
+func Encode(w io.Writer, src image.Image, width int) error {
+	return jpeg.Encode(w, Resize(src, width), &jpeg.Options{Quality: Quality})
+}
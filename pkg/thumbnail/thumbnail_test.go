@@ -0,0 +1,44 @@
+package thumbnail_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/pkg/thumbnail"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResize(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 400, 600))
+	for y := 0; y < 600; y++ {
+		for x := 0; x < 400; x++ {
+			if x < 200 {
+				src.Set(x, y, color.NRGBA{R: 255, A: 255})
+			} else {
+				src.Set(x, y, color.NRGBA{A: 0})
+			}
+		}
+	}
+
+	dst := thumbnail.Resize(src, 100)
+	assert.Equal(t, image.Rect(0, 0, 100, 150), dst.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, dst.RGBAAt(10, 10))
+	// Transparent pixels are flattened onto white
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, dst.RGBAAt(90, 10))
+
+	// Smaller images are not upscaled
+	assert.Equal(t, image.Rect(0, 0, 400, 600), thumbnail.Resize(src, 800).Bounds())
+}
+
+func TestEncode(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.Nil(t, thumbnail.Encode(buf, image.NewRGBA(image.Rect(0, 0, 300, 450)), 120))
+
+	cfg, err := jpeg.DecodeConfig(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, 120, cfg.Width)
+	assert.Equal(t, 180, cfg.Height)
+}
@@ -0,0 +1,95 @@
+// Package tracing exports the OpenTelemetry spans of the book store.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ExporterOTLP sends the spans to the OTLP/HTTP endpoint set by OTEL_EXPORTER_OTLP_ENDPOINT
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes the spans to stdout as JSON, for local development
+	ExporterStdout = "stdout"
+
+	instrumentationName = "github.com/satriowisnugroho/book-store"
+)
+
+// Config -.
+type Config struct {
+	// Exporter is ExporterOTLP or ExporterStdout, spans are not recorded when it is empty
+	Exporter    string
+	ServiceName string
+	// SampleRatio is the ratio of the traces started here which are recorded, a trace started by the caller follows its decision
+	SampleRatio float64
+}
+
+// New sets the global tracer provider exporting the spans with the exporter of cfg, and the propagator of the W3C trace context.
+// The trace context of the caller is propagated even when no exporter is set. It returns a func flushing the spans left and
+// stopping the provider
+func New(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("new %s exporter: %w", cfg.Exporter, err)
+	}
+
+	provider := Install(sdktrace.NewBatchSpanProcessor(exporter), cfg.ServiceName, cfg.SampleRatio)
+
+	return provider.Shutdown, nil
+}
+
+// Install sets the global tracer provider sending the spans to the processor, tests record the spans in memory with
+// a simple span processor of a tracetest.InMemoryExporter
+func Install(processor sdktrace.SpanProcessor, serviceName string, sampleRatio float64) *sdktrace.TracerProvider {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider
+}
+
+// Start starts a span named after the function it traces, such as OrderUsecase.CreateOrder, as a child of the span in ctx
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// RecordError records err on the span in ctx, for an error which is handled rather than returned
+func RecordError(ctx context.Context, err error) {
+	trace.SpanFromContext(ctx).RecordError(err)
+}
+
+// TraceID returns the ID of the trace of the span in ctx as hex, it is empty outside of a trace
+func TraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+
+	return spanContext.TraceID().String()
+}
@@ -0,0 +1,66 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/satriowisnugroho/book-store/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNew(t *testing.T) {
+	testcases := []struct {
+		name     string
+		exporter string
+		wantErr  bool
+	}{
+		{
+			name: "no exporter",
+		},
+		{
+			name:     "stdout exporter",
+			exporter: tracing.ExporterStdout,
+		},
+		{
+			name:     "unknown exporter",
+			exporter: "zipkin",
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			shutdown, err := tracing.New(context.Background(), tracing.Config{Exporter: tc.exporter, ServiceName: "book-store-test", SampleRatio: 1})
+			assert.Equal(t, tc.wantErr, err != nil)
+			if !tc.wantErr {
+				assert.NoError(t, shutdown(context.Background()))
+			}
+		})
+	}
+}
+
+func TestStart(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.Install(sdktrace.NewSimpleSpanProcessor(exporter), "book-store-test", 1)
+	defer provider.Shutdown(context.Background())
+
+	// The trace context of the caller
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+
+	ctx, span := tracing.Start(ctx, "OrderUsecase.CreateOrder")
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tracing.TraceID(ctx))
+	span.End()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "OrderUsecase.CreateOrder", spans[0].Name)
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+	assert.Empty(t, tracing.TraceID(context.Background()))
+}
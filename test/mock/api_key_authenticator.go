@@ -0,0 +1,69 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// APIKeyAuthenticator is an autogenerated mock type for the APIKeyAuthenticator type
+type APIKeyAuthenticator struct {
+	mock.Mock
+}
+
+// AuthenticateAPIKey provides a mock function with given fields: ctx, key
+func (_m *APIKeyAuthenticator) AuthenticateAPIKey(ctx context.Context, key string) (*entity.APIKey, *entity.User, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthenticateAPIKey")
+	}
+
+	var r0 *entity.APIKey
+	var r1 *entity.User
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.APIKey, *entity.User, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.APIKey); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *entity.User); ok {
+		r1 = rf(ctx, key)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, key)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewAPIKeyAuthenticator creates a new instance of APIKeyAuthenticator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAPIKeyAuthenticator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *APIKeyAuthenticator {
+	mock := &APIKeyAuthenticator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,145 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// APIKeyRepositoryInterface is an autogenerated mock type for the APIKeyRepositoryInterface type
+type APIKeyRepositoryInterface struct {
+	mock.Mock
+}
+
+// CreateAPIKey provides a mock function with given fields: ctx, apiKey
+func (_m *APIKeyRepositoryInterface) CreateAPIKey(ctx context.Context, apiKey *entity.APIKey) error {
+	ret := _m.Called(ctx, apiKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.APIKey) error); ok {
+		r0 = rf(ctx, apiKey)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAPIKeyByHash provides a mock function with given fields: ctx, keyHash
+func (_m *APIKeyRepositoryInterface) GetAPIKeyByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	ret := _m.Called(ctx, keyHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPIKeyByHash")
+	}
+
+	var r0 *entity.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.APIKey, error)); ok {
+		return rf(ctx, keyHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.APIKey); ok {
+		r0 = rf(ctx, keyHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, keyHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPIKeysByUserID provides a mock function with given fields: ctx, userID
+func (_m *APIKeyRepositoryInterface) GetAPIKeysByUserID(ctx context.Context, userID int) ([]*entity.APIKey, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPIKeysByUserID")
+	}
+
+	var r0 []*entity.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*entity.APIKey, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*entity.APIKey); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIKey provides a mock function with given fields: ctx, userID, apiKeyID
+func (_m *APIKeyRepositoryInterface) RevokeAPIKey(ctx context.Context, userID int, apiKeyID int) error {
+	ret := _m.Called(ctx, userID, apiKeyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, userID, apiKeyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateAPIKeyLastUsedAt provides a mock function with given fields: ctx, apiKeyID, lastUsedAt
+func (_m *APIKeyRepositoryInterface) UpdateAPIKeyLastUsedAt(ctx context.Context, apiKeyID int, lastUsedAt time.Time) error {
+	ret := _m.Called(ctx, apiKeyID, lastUsedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAPIKeyLastUsedAt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) error); ok {
+		r0 = rf(ctx, apiKeyID, lastUsedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAPIKeyRepositoryInterface creates a new instance of APIKeyRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAPIKeyRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *APIKeyRepositoryInterface {
+	mock := &APIKeyRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
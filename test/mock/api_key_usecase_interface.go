@@ -0,0 +1,146 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// APIKeyUsecaseInterface is an autogenerated mock type for the APIKeyUsecaseInterface type
+type APIKeyUsecaseInterface struct {
+	mock.Mock
+}
+
+// AuthenticateAPIKey provides a mock function with given fields: ctx, key
+func (_m *APIKeyUsecaseInterface) AuthenticateAPIKey(ctx context.Context, key string) (*entity.APIKey, *entity.User, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthenticateAPIKey")
+	}
+
+	var r0 *entity.APIKey
+	var r1 *entity.User
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.APIKey, *entity.User, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.APIKey); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *entity.User); ok {
+		r1 = rf(ctx, key)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, key)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// CreateAPIKey provides a mock function with given fields: ctx, userID, payload
+func (_m *APIKeyUsecaseInterface) CreateAPIKey(ctx context.Context, userID int, payload *entity.APIKeyPayload) (*entity.CreatedAPIKey, error) {
+	ret := _m.Called(ctx, userID, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAPIKey")
+	}
+
+	var r0 *entity.CreatedAPIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *entity.APIKeyPayload) (*entity.CreatedAPIKey, error)); ok {
+		return rf(ctx, userID, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, *entity.APIKeyPayload) *entity.CreatedAPIKey); ok {
+		r0 = rf(ctx, userID, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.CreatedAPIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, *entity.APIKeyPayload) error); ok {
+		r1 = rf(ctx, userID, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAPIKeys provides a mock function with given fields: ctx, userID
+func (_m *APIKeyUsecaseInterface) GetAPIKeys(ctx context.Context, userID int) ([]*entity.APIKey, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAPIKeys")
+	}
+
+	var r0 []*entity.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*entity.APIKey, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*entity.APIKey); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAPIKey provides a mock function with given fields: ctx, userID, apiKeyID
+func (_m *APIKeyUsecaseInterface) RevokeAPIKey(ctx context.Context, userID int, apiKeyID int) error {
+	ret := _m.Called(ctx, userID, apiKeyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, userID, apiKeyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAPIKeyUsecaseInterface creates a new instance of APIKeyUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAPIKeyUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *APIKeyUsecaseInterface {
+	mock := &APIKeyUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,47 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditRecorder is an autogenerated mock type for the AuditRecorder type
+type AuditRecorder struct {
+	mock.Mock
+}
+
+// Record provides a mock function with given fields: ctx, dbTrx, event
+func (_m *AuditRecorder) Record(ctx context.Context, dbTrx interface{}, event *entity.AuditEvent) error {
+	ret := _m.Called(ctx, dbTrx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Record")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, *entity.AuditEvent) error); ok {
+		r0 = rf(ctx, dbTrx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAuditRecorder creates a new instance of AuditRecorder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditRecorder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditRecorder {
+	mock := &AuditRecorder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
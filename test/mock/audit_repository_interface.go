@@ -0,0 +1,123 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditRepositoryInterface is an autogenerated mock type for the AuditRepositoryInterface type
+type AuditRepositoryInterface struct {
+	mock.Mock
+}
+
+// CreateAuditEvent provides a mock function with given fields: ctx, dbTrx, event
+func (_m *AuditRepositoryInterface) CreateAuditEvent(ctx context.Context, dbTrx interface{}, event *entity.AuditEvent) error {
+	ret := _m.Called(ctx, dbTrx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAuditEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, *entity.AuditEvent) error); ok {
+		r0 = rf(ctx, dbTrx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAuditEvents provides a mock function with given fields: ctx, payload
+func (_m *AuditRepositoryInterface) GetAuditEvents(ctx context.Context, payload entity.GetAuditEventsPayload) ([]*entity.AuditEvent, error) {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuditEvents")
+	}
+
+	var r0 []*entity.AuditEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetAuditEventsPayload) ([]*entity.AuditEvent, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetAuditEventsPayload) []*entity.AuditEvent); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.AuditEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GetAuditEventsPayload) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAuditEventsCount provides a mock function with given fields: ctx, payload
+func (_m *AuditRepositoryInterface) GetAuditEventsCount(ctx context.Context, payload entity.GetAuditEventsPayload) (int, error) {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuditEventsCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetAuditEventsPayload) (int, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetAuditEventsPayload) int); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GetAuditEventsPayload) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StreamAuditEvents provides a mock function with given fields: ctx, fn
+func (_m *AuditRepositoryInterface) StreamAuditEvents(ctx context.Context, fn func(*entity.AuditEvent) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamAuditEvents")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(*entity.AuditEvent) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAuditRepositoryInterface creates a new instance of AuditRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditRepositoryInterface {
+	mock := &AuditRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,114 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditUsecaseInterface is an autogenerated mock type for the AuditUsecaseInterface type
+type AuditUsecaseInterface struct {
+	mock.Mock
+}
+
+// GetAuditEvents provides a mock function with given fields: ctx, payload
+func (_m *AuditUsecaseInterface) GetAuditEvents(ctx context.Context, payload entity.GetAuditEventsPayload) ([]*entity.AuditEvent, int, error) {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuditEvents")
+	}
+
+	var r0 []*entity.AuditEvent
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetAuditEventsPayload) ([]*entity.AuditEvent, int, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetAuditEventsPayload) []*entity.AuditEvent); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.AuditEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GetAuditEventsPayload) int); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.GetAuditEventsPayload) error); ok {
+		r2 = rf(ctx, payload)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Record provides a mock function with given fields: ctx, dbTrx, event
+func (_m *AuditUsecaseInterface) Record(ctx context.Context, dbTrx interface{}, event *entity.AuditEvent) error {
+	ret := _m.Called(ctx, dbTrx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Record")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, *entity.AuditEvent) error); ok {
+		r0 = rf(ctx, dbTrx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyAuditEvents provides a mock function with given fields: ctx
+func (_m *AuditUsecaseInterface) VerifyAuditEvents(ctx context.Context) (*entity.AuditVerification, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyAuditEvents")
+	}
+
+	var r0 *entity.AuditVerification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*entity.AuditVerification, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *entity.AuditVerification); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.AuditVerification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAuditUsecaseInterface creates a new instance of AuditUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditUsecaseInterface {
+	mock := &AuditUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
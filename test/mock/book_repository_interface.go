@@ -14,6 +14,64 @@ type BookRepositoryInterface struct {
 	mock.Mock
 }
 
+// GetBestsellers provides a mock function with given fields: ctx, payload
+func (_m *BookRepositoryInterface) GetBestsellers(ctx context.Context, payload entity.GetBestsellersPayload) ([]*entity.Bestseller, error) {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBestsellers")
+	}
+
+	var r0 []*entity.Bestseller
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetBestsellersPayload) ([]*entity.Bestseller, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetBestsellersPayload) []*entity.Bestseller); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Bestseller)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GetBestsellersPayload) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBestsellersCount provides a mock function with given fields: ctx, payload
+func (_m *BookRepositoryInterface) GetBestsellersCount(ctx context.Context, payload entity.GetBestsellersPayload) (int, error) {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBestsellersCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetBestsellersPayload) (int, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetBestsellersPayload) int); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GetBestsellersPayload) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBookByID provides a mock function with given fields: ctx, bookID
 func (_m *BookRepositoryInterface) GetBookByID(ctx context.Context, bookID int) (*entity.Book, error) {
 	ret := _m.Called(ctx, bookID)
@@ -44,6 +102,36 @@ func (_m *BookRepositoryInterface) GetBookByID(ctx context.Context, bookID int)
 	return r0, r1
 }
 
+// GetBookByIsbn provides a mock function with given fields: ctx, isbn
+func (_m *BookRepositoryInterface) GetBookByIsbn(ctx context.Context, isbn string) (*entity.Book, error) {
+	ret := _m.Called(ctx, isbn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBookByIsbn")
+	}
+
+	var r0 *entity.Book
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.Book, error)); ok {
+		return rf(ctx, isbn)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.Book); ok {
+		r0 = rf(ctx, isbn)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, isbn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBooks provides a mock function with given fields: ctx, payload
 func (_m *BookRepositoryInterface) GetBooks(ctx context.Context, payload entity.GetBooksPayload) ([]*entity.Book, error) {
 	ret := _m.Called(ctx, payload)
@@ -74,6 +162,36 @@ func (_m *BookRepositoryInterface) GetBooks(ctx context.Context, payload entity.
 	return r0, r1
 }
 
+// GetBooksByIDs provides a mock function with given fields: ctx, bookIDs
+func (_m *BookRepositoryInterface) GetBooksByIDs(ctx context.Context, bookIDs []int) ([]*entity.Book, error) {
+	ret := _m.Called(ctx, bookIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBooksByIDs")
+	}
+
+	var r0 []*entity.Book
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) ([]*entity.Book, error)); ok {
+		return rf(ctx, bookIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int) []*entity.Book); ok {
+		r0 = rf(ctx, bookIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, bookIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBooksCount provides a mock function with given fields: ctx, payload
 func (_m *BookRepositoryInterface) GetBooksCount(ctx context.Context, payload entity.GetBooksPayload) (int, error) {
 	ret := _m.Called(ctx, payload)
@@ -102,6 +220,123 @@ func (_m *BookRepositoryInterface) GetBooksCount(ctx context.Context, payload en
 	return r0, r1
 }
 
+// RefreshBestsellers provides a mock function with given fields: ctx, dbTrx
+func (_m *BookRepositoryInterface) RefreshBestsellers(ctx context.Context, dbTrx interface{}) (bool, error) {
+	ret := _m.Called(ctx, dbTrx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshBestsellers")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}) (bool, error)); ok {
+		return rf(ctx, dbTrx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}) bool); ok {
+		r0 = rf(ctx, dbTrx)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}) error); ok {
+		r1 = rf(ctx, dbTrx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StreamBooks provides a mock function with given fields: ctx, payload, fn
+func (_m *BookRepositoryInterface) StreamBooks(ctx context.Context, payload entity.GetBooksPayload, fn func(*entity.Book) error) error {
+	ret := _m.Called(ctx, payload, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamBooks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetBooksPayload, func(*entity.Book) error) error); ok {
+		r0 = rf(ctx, payload, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateBookCoverKey provides a mock function with given fields: ctx, bookID, coverKey
+func (_m *BookRepositoryInterface) UpdateBookCoverKey(ctx context.Context, bookID int, coverKey string) error {
+	ret := _m.Called(ctx, bookID, coverKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBookCoverKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, bookID, coverKey)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateBookRating provides a mock function with given fields: ctx, dbTrx, bookID
+func (_m *BookRepositoryInterface) UpdateBookRating(ctx context.Context, dbTrx interface{}, bookID int) error {
+	ret := _m.Called(ctx, dbTrx, bookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBookRating")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) error); ok {
+		r0 = rf(ctx, dbTrx, bookID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertBook provides a mock function with given fields: ctx, dbTrx, book
+func (_m *BookRepositoryInterface) UpsertBook(ctx context.Context, dbTrx interface{}, book *entity.Book) (int, bool, error) {
+	ret := _m.Called(ctx, dbTrx, book)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertBook")
+	}
+
+	var r0 int
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, *entity.Book) (int, bool, error)); ok {
+		return rf(ctx, dbTrx, book)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, *entity.Book) int); ok {
+		r0 = rf(ctx, dbTrx, book)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, *entity.Book) bool); ok {
+		r1 = rf(ctx, dbTrx, book)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, interface{}, *entity.Book) error); ok {
+		r2 = rf(ctx, dbTrx, book)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // NewBookRepositoryInterface creates a new instance of BookRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewBookRepositoryInterface(t interface {
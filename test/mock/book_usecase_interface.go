@@ -4,8 +4,10 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 
 	entity "github.com/satriowisnugroho/book-store/internal/entity"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -14,6 +16,73 @@ type BookUsecaseInterface struct {
 	mock.Mock
 }
 
+// GetBestsellers provides a mock function with given fields: ctx, payload
+func (_m *BookUsecaseInterface) GetBestsellers(ctx context.Context, payload entity.GetBestsellersPayload) ([]*entity.Bestseller, int, error) {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBestsellers")
+	}
+
+	var r0 []*entity.Bestseller
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetBestsellersPayload) ([]*entity.Bestseller, int, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetBestsellersPayload) []*entity.Bestseller); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Bestseller)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GetBestsellersPayload) int); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.GetBestsellersPayload) error); ok {
+		r2 = rf(ctx, payload)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetBookByIsbn provides a mock function with given fields: ctx, isbnStr
+func (_m *BookUsecaseInterface) GetBookByIsbn(ctx context.Context, isbnStr string) (*entity.Book, error) {
+	ret := _m.Called(ctx, isbnStr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBookByIsbn")
+	}
+
+	var r0 *entity.Book
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.Book, error)); ok {
+		return rf(ctx, isbnStr)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.Book); ok {
+		r0 = rf(ctx, isbnStr)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, isbnStr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBooks provides a mock function with given fields: ctx, payload
 func (_m *BookUsecaseInterface) GetBooks(ctx context.Context, payload entity.GetBooksPayload) ([]*entity.Book, int, error) {
 	ret := _m.Called(ctx, payload)
@@ -51,6 +120,84 @@ func (_m *BookUsecaseInterface) GetBooks(ctx context.Context, payload entity.Get
 	return r0, r1, r2
 }
 
+// ImportBooks provides a mock function with given fields: ctx, format, r
+func (_m *BookUsecaseInterface) ImportBooks(ctx context.Context, format string, r io.Reader) (*entity.BookImportReport, error) {
+	ret := _m.Called(ctx, format, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportBooks")
+	}
+
+	var r0 *entity.BookImportReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader) (*entity.BookImportReport, error)); ok {
+		return rf(ctx, format, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader) *entity.BookImportReport); ok {
+		r0 = rf(ctx, format, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.BookImportReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.Reader) error); ok {
+		r1 = rf(ctx, format, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshBestsellers provides a mock function with given fields: ctx
+func (_m *BookUsecaseInterface) RefreshBestsellers(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshBestsellers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UploadBookCover provides a mock function with given fields: ctx, bookID, r
+func (_m *BookUsecaseInterface) UploadBookCover(ctx context.Context, bookID int, r io.Reader) (*entity.Book, error) {
+	ret := _m.Called(ctx, bookID, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadBookCover")
+	}
+
+	var r0 *entity.Book
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, io.Reader) (*entity.Book, error)); ok {
+		return rf(ctx, bookID, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, io.Reader) *entity.Book); ok {
+		r0 = rf(ctx, bookID, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, io.Reader) error); ok {
+		r1 = rf(ctx, bookID, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewBookUsecaseInterface creates a new instance of BookUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewBookUsecaseInterface(t interface {
@@ -0,0 +1,123 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ExportUsecaseInterface is an autogenerated mock type for the ExportUsecaseInterface type
+type ExportUsecaseInterface struct {
+	mock.Mock
+}
+
+// ExportBooks provides a mock function with given fields: ctx, payload, format, w
+func (_m *ExportUsecaseInterface) ExportBooks(ctx context.Context, payload entity.GetBooksPayload, format string, w io.Writer) error {
+	ret := _m.Called(ctx, payload, format, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportBooks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetBooksPayload, string, io.Writer) error); ok {
+		r0 = rf(ctx, payload, format, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportOrders provides a mock function with given fields: ctx, userID, format, w
+func (_m *ExportUsecaseInterface) ExportOrders(ctx context.Context, userID int, format string, w io.Writer) error {
+	ret := _m.Called(ctx, userID, format, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportOrders")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, io.Writer) error); ok {
+		r0 = rf(ctx, userID, format, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetBooksCount provides a mock function with given fields: ctx, payload
+func (_m *ExportUsecaseInterface) GetBooksCount(ctx context.Context, payload entity.GetBooksPayload) (int, error) {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBooksCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetBooksPayload) (int, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.GetBooksPayload) int); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.GetBooksPayload) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrdersCount provides a mock function with given fields: ctx, userID
+func (_m *ExportUsecaseInterface) GetOrdersCount(ctx context.Context, userID int) (int, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrdersCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewExportUsecaseInterface creates a new instance of ExportUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExportUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExportUsecaseInterface {
+	mock := &ExportUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	contentfilter "github.com/satriowisnugroho/book-store/pkg/contentfilter"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Filter is an autogenerated mock type for the Filter type
+type Filter struct {
+	mock.Mock
+}
+
+// Check provides a mock function with given fields: ctx, text
+func (_m *Filter) Check(ctx context.Context, text string) (*contentfilter.Result, error) {
+	ret := _m.Called(ctx, text)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Check")
+	}
+
+	var r0 *contentfilter.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*contentfilter.Result, error)); ok {
+		return rf(ctx, text)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *contentfilter.Result); ok {
+		r0 = rf(ctx, text)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*contentfilter.Result)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, text)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewFilter creates a new instance of Filter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFilter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Filter {
+	mock := &Filter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
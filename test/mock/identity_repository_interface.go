@@ -0,0 +1,95 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IdentityRepositoryInterface is an autogenerated mock type for the IdentityRepositoryInterface type
+type IdentityRepositoryInterface struct {
+	mock.Mock
+}
+
+// DeleteUserIdentities provides a mock function with given fields: ctx, dbTrx, userID
+func (_m *IdentityRepositoryInterface) DeleteUserIdentities(ctx context.Context, dbTrx interface{}, userID int) error {
+	ret := _m.Called(ctx, dbTrx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserIdentities")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) error); ok {
+		r0 = rf(ctx, dbTrx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetUserIdentity provides a mock function with given fields: ctx, provider, subject
+func (_m *IdentityRepositoryInterface) GetUserIdentity(ctx context.Context, provider string, subject string) (*entity.UserIdentity, error) {
+	ret := _m.Called(ctx, provider, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserIdentity")
+	}
+
+	var r0 *entity.UserIdentity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*entity.UserIdentity, error)); ok {
+		return rf(ctx, provider, subject)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *entity.UserIdentity); ok {
+		r0 = rf(ctx, provider, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.UserIdentity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, provider, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertUserIdentity provides a mock function with given fields: ctx, identity
+func (_m *IdentityRepositoryInterface) UpsertUserIdentity(ctx context.Context, identity *entity.UserIdentity) error {
+	ret := _m.Called(ctx, identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertUserIdentity")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.UserIdentity) error); ok {
+		r0 = rf(ctx, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIdentityRepositoryInterface creates a new instance of IdentityRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdentityRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdentityRepositoryInterface {
+	mock := &IdentityRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
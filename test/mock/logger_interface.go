@@ -2,7 +2,12 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	logger "github.com/satriowisnugroho/book-store/pkg/logger"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // LoggerInterface is an autogenerated mock type for the LoggerInterface type
 type LoggerInterface struct {
@@ -49,6 +54,52 @@ func (_m *LoggerInterface) Warn(message string, args ...interface{}) {
 	_m.Called(_ca...)
 }
 
+// With provides a mock function with given fields: fields
+func (_m *LoggerInterface) With(fields ...logger.Field) logger.LoggerInterface {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for With")
+	}
+
+	var r0 logger.LoggerInterface
+	if rf, ok := ret.Get(0).(func(...logger.Field) logger.LoggerInterface); ok {
+		r0 = rf(fields...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(logger.LoggerInterface)
+		}
+	}
+
+	return r0
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *LoggerInterface) WithContext(ctx context.Context) logger.LoggerInterface {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 logger.LoggerInterface
+	if rf, ok := ret.Get(0).(func(context.Context) logger.LoggerInterface); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(logger.LoggerInterface)
+		}
+	}
+
+	return r0
+}
+
 // NewLoggerInterface creates a new instance of LoggerInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewLoggerInterface(t interface {
@@ -0,0 +1,145 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// LoginFailureRepositoryInterface is an autogenerated mock type for the LoginFailureRepositoryInterface type
+type LoginFailureRepositoryInterface struct {
+	mock.Mock
+}
+
+// CreateLoginFailure provides a mock function with given fields: ctx, failure
+func (_m *LoginFailureRepositoryInterface) CreateLoginFailure(ctx context.Context, failure *entity.LoginFailure) error {
+	ret := _m.Called(ctx, failure)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateLoginFailure")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.LoginFailure) error); ok {
+		r0 = rf(ctx, failure)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteLoginFailuresBefore provides a mock function with given fields: ctx, before
+func (_m *LoginFailureRepositoryInterface) DeleteLoginFailuresBefore(ctx context.Context, before time.Time) error {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLoginFailuresBefore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteLoginFailuresByEmail provides a mock function with given fields: ctx, email
+func (_m *LoginFailureRepositoryInterface) DeleteLoginFailuresByEmail(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLoginFailuresByEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetLoginFailureSummaryByEmail provides a mock function with given fields: ctx, email, since
+func (_m *LoginFailureRepositoryInterface) GetLoginFailureSummaryByEmail(ctx context.Context, email string, since time.Time) (*entity.LoginFailureSummary, error) {
+	ret := _m.Called(ctx, email, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginFailureSummaryByEmail")
+	}
+
+	var r0 *entity.LoginFailureSummary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*entity.LoginFailureSummary, error)); ok {
+		return rf(ctx, email, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *entity.LoginFailureSummary); ok {
+		r0 = rf(ctx, email, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.LoginFailureSummary)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, email, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLoginFailureSummaryByIPAddress provides a mock function with given fields: ctx, ipAddress, since
+func (_m *LoginFailureRepositoryInterface) GetLoginFailureSummaryByIPAddress(ctx context.Context, ipAddress string, since time.Time) (*entity.LoginFailureSummary, error) {
+	ret := _m.Called(ctx, ipAddress, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginFailureSummaryByIPAddress")
+	}
+
+	var r0 *entity.LoginFailureSummary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*entity.LoginFailureSummary, error)); ok {
+		return rf(ctx, ipAddress, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *entity.LoginFailureSummary); ok {
+		r0 = rf(ctx, ipAddress, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.LoginFailureSummary)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, ipAddress, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLoginFailureRepositoryInterface creates a new instance of LoginFailureRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoginFailureRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoginFailureRepositoryInterface {
+	mock := &LoginFailureRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,195 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MFARepositoryInterface is an autogenerated mock type for the MFARepositoryInterface type
+type MFARepositoryInterface struct {
+	mock.Mock
+}
+
+// DeleteRecoveryCodes provides a mock function with given fields: ctx, dbTrx, userID
+func (_m *MFARepositoryInterface) DeleteRecoveryCodes(ctx context.Context, dbTrx interface{}, userID int) error {
+	ret := _m.Called(ctx, dbTrx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteRecoveryCodes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) error); ok {
+		r0 = rf(ctx, dbTrx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTOTPCredential provides a mock function with given fields: ctx, dbTrx, userID
+func (_m *MFARepositoryInterface) DeleteTOTPCredential(ctx context.Context, dbTrx interface{}, userID int) error {
+	ret := _m.Called(ctx, dbTrx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTOTPCredential")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) error); ok {
+		r0 = rf(ctx, dbTrx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableTOTPCredential provides a mock function with given fields: ctx, dbTrx, userID, step
+func (_m *MFARepositoryInterface) EnableTOTPCredential(ctx context.Context, dbTrx interface{}, userID int, step int64) error {
+	ret := _m.Called(ctx, dbTrx, userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnableTOTPCredential")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int64) error); ok {
+		r0 = rf(ctx, dbTrx, userID, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetTOTPCredential provides a mock function with given fields: ctx, dbTrx, userID
+func (_m *MFARepositoryInterface) GetTOTPCredential(ctx context.Context, dbTrx interface{}, userID int) (*entity.TOTPCredential, error) {
+	ret := _m.Called(ctx, dbTrx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTOTPCredential")
+	}
+
+	var r0 *entity.TOTPCredential
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) (*entity.TOTPCredential, error)); ok {
+		return rf(ctx, dbTrx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) *entity.TOTPCredential); ok {
+		r0 = rf(ctx, dbTrx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.TOTPCredential)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int) error); ok {
+		r1 = rf(ctx, dbTrx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplaceRecoveryCodes provides a mock function with given fields: ctx, dbTrx, userID, codeHashes
+func (_m *MFARepositoryInterface) ReplaceRecoveryCodes(ctx context.Context, dbTrx interface{}, userID int, codeHashes []string) error {
+	ret := _m.Called(ctx, dbTrx, userID, codeHashes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceRecoveryCodes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, []string) error); ok {
+		r0 = rf(ctx, dbTrx, userID, codeHashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTOTPLastUsedStep provides a mock function with given fields: ctx, dbTrx, userID, step
+func (_m *MFARepositoryInterface) UpdateTOTPLastUsedStep(ctx context.Context, dbTrx interface{}, userID int, step int64) error {
+	ret := _m.Called(ctx, dbTrx, userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTOTPLastUsedStep")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int64) error); ok {
+		r0 = rf(ctx, dbTrx, userID, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertTOTPCredential provides a mock function with given fields: ctx, credential
+func (_m *MFARepositoryInterface) UpsertTOTPCredential(ctx context.Context, credential *entity.TOTPCredential) error {
+	ret := _m.Called(ctx, credential)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertTOTPCredential")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.TOTPCredential) error); ok {
+		r0 = rf(ctx, credential)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseRecoveryCode provides a mock function with given fields: ctx, dbTrx, userID, codeHash
+func (_m *MFARepositoryInterface) UseRecoveryCode(ctx context.Context, dbTrx interface{}, userID int, codeHash string) (bool, error) {
+	ret := _m.Called(ctx, dbTrx, userID, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, string) (bool, error)); ok {
+		return rf(ctx, dbTrx, userID, codeHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, string) bool); ok {
+		r0 = rf(ctx, dbTrx, userID, codeHash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, string) error); ok {
+		r1 = rf(ctx, dbTrx, userID, codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMFARepositoryInterface creates a new instance of MFARepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMFARepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MFARepositoryInterface {
+	mock := &MFARepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -62,6 +62,34 @@ func (_m *OrderItemRepositoryInterface) GetOrderItemsByOrderID(ctx context.Conte
 	return r0, r1
 }
 
+// HasUserOrderedBook provides a mock function with given fields: ctx, userID, bookID
+func (_m *OrderItemRepositoryInterface) HasUserOrderedBook(ctx context.Context, userID int, bookID int) (bool, error) {
+	ret := _m.Called(ctx, userID, bookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasUserOrderedBook")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (bool, error)); ok {
+		return rf(ctx, userID, bookID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) bool); ok {
+		r0 = rf(ctx, userID, bookID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, userID, bookID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewOrderItemRepositoryInterface creates a new instance of OrderItemRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewOrderItemRepositoryInterface(t interface {
@@ -90,6 +90,52 @@ func (_m *OrderRepositoryInterface) GetOrdersByUserIDCount(ctx context.Context,
 	return r0, r1
 }
 
+// GetOrdersCount provides a mock function with given fields: ctx, userID
+func (_m *OrderRepositoryInterface) GetOrdersCount(ctx context.Context, userID int) (int, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrdersCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StreamOrders provides a mock function with given fields: ctx, userID, fn
+func (_m *OrderRepositoryInterface) StreamOrders(ctx context.Context, userID int, fn func(*entity.Order) error) error {
+	ret := _m.Called(ctx, userID, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamOrders")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, func(*entity.Order) error) error); ok {
+		r0 = rf(ctx, userID, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateOrder provides a mock function with given fields: ctx, dbTrx, order
 func (_m *OrderRepositoryInterface) UpdateOrder(ctx context.Context, dbTrx interface{}, order *entity.Order) error {
 	ret := _m.Called(ctx, dbTrx, order)
@@ -55,6 +55,24 @@ func (_m *PasswordHasher) GenerateFromPassword(password string) (string, error)
 	return r0, r1
 }
 
+// NeedsRehash provides a mock function with given fields: hashedPassword
+func (_m *PasswordHasher) NeedsRehash(hashedPassword string) bool {
+	ret := _m.Called(hashedPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NeedsRehash")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(hashedPassword)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // NewPasswordHasher creates a new instance of PasswordHasher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPasswordHasher(t interface {
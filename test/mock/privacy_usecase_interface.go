@@ -0,0 +1,65 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PrivacyUsecaseInterface is an autogenerated mock type for the PrivacyUsecaseInterface type
+type PrivacyUsecaseInterface struct {
+	mock.Mock
+}
+
+// EraseUser provides a mock function with given fields: ctx, userID
+func (_m *PrivacyUsecaseInterface) EraseUser(ctx context.Context, userID int) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EraseUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportUserData provides a mock function with given fields: ctx, userID, w
+func (_m *PrivacyUsecaseInterface) ExportUserData(ctx context.Context, userID int, w io.Writer) error {
+	ret := _m.Called(ctx, userID, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportUserData")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, io.Writer) error); ok {
+		r0 = rf(ctx, userID, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPrivacyUsecaseInterface creates a new instance of PrivacyUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPrivacyUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PrivacyUsecaseInterface {
+	mock := &PrivacyUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	catalogfeed "github.com/satriowisnugroho/book-store/pkg/catalogfeed"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Reader is an autogenerated mock type for the Reader type
+type Reader struct {
+	mock.Mock
+}
+
+// Next provides a mock function with no fields
+func (_m *Reader) Next() (*catalogfeed.Record, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Next")
+	}
+
+	var r0 *catalogfeed.Record
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*catalogfeed.Record, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *catalogfeed.Record); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*catalogfeed.Record)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewReader creates a new instance of Reader. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReader(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Reader {
+	mock := &Reader{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,355 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReadingListRepositoryInterface is an autogenerated mock type for the ReadingListRepositoryInterface type
+type ReadingListRepositoryInterface struct {
+	mock.Mock
+}
+
+// AddReadingListBook provides a mock function with given fields: ctx, readingListID, bookID
+func (_m *ReadingListRepositoryInterface) AddReadingListBook(ctx context.Context, readingListID int, bookID int) error {
+	ret := _m.Called(ctx, readingListID, bookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddReadingListBook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, readingListID, bookID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateReadingList provides a mock function with given fields: ctx, readingList
+func (_m *ReadingListRepositoryInterface) CreateReadingList(ctx context.Context, readingList *entity.ReadingList) error {
+	ret := _m.Called(ctx, readingList)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateReadingList")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ReadingList) error); ok {
+		r0 = rf(ctx, readingList)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteReadingList provides a mock function with given fields: ctx, readingListID
+func (_m *ReadingListRepositoryInterface) DeleteReadingList(ctx context.Context, readingListID int) error {
+	ret := _m.Called(ctx, readingListID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteReadingList")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, readingListID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetOrCreateWishlist provides a mock function with given fields: ctx, userID
+func (_m *ReadingListRepositoryInterface) GetOrCreateWishlist(ctx context.Context, userID int) (*entity.ReadingList, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrCreateWishlist")
+	}
+
+	var r0 *entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*entity.ReadingList, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *entity.ReadingList); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicReadingLists provides a mock function with given fields: ctx, limit, offset
+func (_m *ReadingListRepositoryInterface) GetPublicReadingLists(ctx context.Context, limit int, offset int) ([]*entity.ReadingList, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPublicReadingLists")
+	}
+
+	var r0 []*entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*entity.ReadingList, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*entity.ReadingList); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicReadingListsCount provides a mock function with given fields: ctx
+func (_m *ReadingListRepositoryInterface) GetPublicReadingListsCount(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPublicReadingListsCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReadingListBookIDs provides a mock function with given fields: ctx, readingListIDs
+func (_m *ReadingListRepositoryInterface) GetReadingListBookIDs(ctx context.Context, readingListIDs []int) (map[int][]int, error) {
+	ret := _m.Called(ctx, readingListIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReadingListBookIDs")
+	}
+
+	var r0 map[int][]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) (map[int][]int, error)); ok {
+		return rf(ctx, readingListIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int) map[int][]int); ok {
+		r0 = rf(ctx, readingListIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int][]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, readingListIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReadingListByID provides a mock function with given fields: ctx, readingListID
+func (_m *ReadingListRepositoryInterface) GetReadingListByID(ctx context.Context, readingListID int) (*entity.ReadingList, error) {
+	ret := _m.Called(ctx, readingListID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReadingListByID")
+	}
+
+	var r0 *entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*entity.ReadingList, error)); ok {
+		return rf(ctx, readingListID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *entity.ReadingList); ok {
+		r0 = rf(ctx, readingListID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, readingListID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReadingListByShareSlug provides a mock function with given fields: ctx, shareSlug
+func (_m *ReadingListRepositoryInterface) GetReadingListByShareSlug(ctx context.Context, shareSlug string) (*entity.ReadingList, error) {
+	ret := _m.Called(ctx, shareSlug)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReadingListByShareSlug")
+	}
+
+	var r0 *entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.ReadingList, error)); ok {
+		return rf(ctx, shareSlug)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.ReadingList); ok {
+		r0 = rf(ctx, shareSlug)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, shareSlug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReadingListsByUserID provides a mock function with given fields: ctx, userID, limit, offset
+func (_m *ReadingListRepositoryInterface) GetReadingListsByUserID(ctx context.Context, userID int, limit int, offset int) ([]*entity.ReadingList, error) {
+	ret := _m.Called(ctx, userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReadingListsByUserID")
+	}
+
+	var r0 []*entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) ([]*entity.ReadingList, error)); ok {
+		return rf(ctx, userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) []*entity.ReadingList); ok {
+		r0 = rf(ctx, userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReadingListsByUserIDCount provides a mock function with given fields: ctx, userID
+func (_m *ReadingListRepositoryInterface) GetReadingListsByUserIDCount(ctx context.Context, userID int) (int, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReadingListsByUserIDCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveReadingListBook provides a mock function with given fields: ctx, readingListID, bookID
+func (_m *ReadingListRepositoryInterface) RemoveReadingListBook(ctx context.Context, readingListID int, bookID int) error {
+	ret := _m.Called(ctx, readingListID, bookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveReadingListBook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, readingListID, bookID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateReadingList provides a mock function with given fields: ctx, readingList
+func (_m *ReadingListRepositoryInterface) UpdateReadingList(ctx context.Context, readingList *entity.ReadingList) error {
+	ret := _m.Called(ctx, readingList)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateReadingList")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ReadingList) error); ok {
+		r0 = rf(ctx, readingList)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewReadingListRepositoryInterface creates a new instance of ReadingListRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReadingListRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReadingListRepositoryInterface {
+	mock := &ReadingListRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
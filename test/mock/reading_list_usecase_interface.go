@@ -0,0 +1,451 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReadingListUsecaseInterface is an autogenerated mock type for the ReadingListUsecaseInterface type
+type ReadingListUsecaseInterface struct {
+	mock.Mock
+}
+
+// AddReadingListBook provides a mock function with given fields: c, readingListID, payload
+func (_m *ReadingListUsecaseInterface) AddReadingListBook(c *gin.Context, readingListID int, payload *entity.ReadingListBookPayload) (*entity.ReadingList, error) {
+	ret := _m.Called(c, readingListID, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddReadingListBook")
+	}
+
+	var r0 *entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, *entity.ReadingListBookPayload) (*entity.ReadingList, error)); ok {
+		return rf(c, readingListID, payload)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, *entity.ReadingListBookPayload) *entity.ReadingList); ok {
+		r0 = rf(c, readingListID, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, int, *entity.ReadingListBookPayload) error); ok {
+		r1 = rf(c, readingListID, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddWishlistBook provides a mock function with given fields: c, payload
+func (_m *ReadingListUsecaseInterface) AddWishlistBook(c *gin.Context, payload *entity.ReadingListBookPayload) (*entity.ReadingList, error) {
+	ret := _m.Called(c, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddWishlistBook")
+	}
+
+	var r0 *entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, *entity.ReadingListBookPayload) (*entity.ReadingList, error)); ok {
+		return rf(c, payload)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, *entity.ReadingListBookPayload) *entity.ReadingList); ok {
+		r0 = rf(c, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, *entity.ReadingListBookPayload) error); ok {
+		r1 = rf(c, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateReadingList provides a mock function with given fields: c, payload
+func (_m *ReadingListUsecaseInterface) CreateReadingList(c *gin.Context, payload *entity.ReadingListPayload) (*entity.ReadingList, error) {
+	ret := _m.Called(c, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateReadingList")
+	}
+
+	var r0 *entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, *entity.ReadingListPayload) (*entity.ReadingList, error)); ok {
+		return rf(c, payload)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, *entity.ReadingListPayload) *entity.ReadingList); ok {
+		r0 = rf(c, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, *entity.ReadingListPayload) error); ok {
+		r1 = rf(c, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteReadingList provides a mock function with given fields: c, readingListID
+func (_m *ReadingListUsecaseInterface) DeleteReadingList(c *gin.Context, readingListID int) error {
+	ret := _m.Called(c, readingListID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteReadingList")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, int) error); ok {
+		r0 = rf(c, readingListID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPublicReadingLists provides a mock function with given fields: c, limit, offset
+func (_m *ReadingListUsecaseInterface) GetPublicReadingLists(c *gin.Context, limit int, offset int) ([]*entity.ReadingList, int, error) {
+	ret := _m.Called(c, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPublicReadingLists")
+	}
+
+	var r0 []*entity.ReadingList
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, int) ([]*entity.ReadingList, int, error)); ok {
+		return rf(c, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, int) []*entity.ReadingList); ok {
+		r0 = rf(c, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, int, int) int); ok {
+		r1 = rf(c, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(*gin.Context, int, int) error); ok {
+		r2 = rf(c, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetReadingList provides a mock function with given fields: c, readingListID
+func (_m *ReadingListUsecaseInterface) GetReadingList(c *gin.Context, readingListID int) (*entity.ReadingList, error) {
+	ret := _m.Called(c, readingListID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReadingList")
+	}
+
+	var r0 *entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, int) (*entity.ReadingList, error)); ok {
+		return rf(c, readingListID)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, int) *entity.ReadingList); ok {
+		r0 = rf(c, readingListID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, int) error); ok {
+		r1 = rf(c, readingListID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReadingListOrderPayload provides a mock function with given fields: c, readingListID
+func (_m *ReadingListUsecaseInterface) GetReadingListOrderPayload(c *gin.Context, readingListID int) (*entity.OrderPayload, error) {
+	ret := _m.Called(c, readingListID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReadingListOrderPayload")
+	}
+
+	var r0 *entity.OrderPayload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, int) (*entity.OrderPayload, error)); ok {
+		return rf(c, readingListID)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, int) *entity.OrderPayload); ok {
+		r0 = rf(c, readingListID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.OrderPayload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, int) error); ok {
+		r1 = rf(c, readingListID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReadingLists provides a mock function with given fields: c, limit, offset
+func (_m *ReadingListUsecaseInterface) GetReadingLists(c *gin.Context, limit int, offset int) ([]*entity.ReadingList, int, error) {
+	ret := _m.Called(c, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReadingLists")
+	}
+
+	var r0 []*entity.ReadingList
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, int) ([]*entity.ReadingList, int, error)); ok {
+		return rf(c, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, int) []*entity.ReadingList); ok {
+		r0 = rf(c, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, int, int) int); ok {
+		r1 = rf(c, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(*gin.Context, int, int) error); ok {
+		r2 = rf(c, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetSharedReadingList provides a mock function with given fields: c, shareSlug
+func (_m *ReadingListUsecaseInterface) GetSharedReadingList(c *gin.Context, shareSlug string) (*entity.ReadingList, error) {
+	ret := _m.Called(c, shareSlug)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSharedReadingList")
+	}
+
+	var r0 *entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, string) (*entity.ReadingList, error)); ok {
+		return rf(c, shareSlug)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, string) *entity.ReadingList); ok {
+		r0 = rf(c, shareSlug)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, string) error); ok {
+		r1 = rf(c, shareSlug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWishlist provides a mock function with given fields: c
+func (_m *ReadingListUsecaseInterface) GetWishlist(c *gin.Context) (*entity.ReadingList, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWishlist")
+	}
+
+	var r0 *entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context) (*entity.ReadingList, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context) *entity.ReadingList); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWishlistOrderPayload provides a mock function with given fields: c
+func (_m *ReadingListUsecaseInterface) GetWishlistOrderPayload(c *gin.Context) (*entity.OrderPayload, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWishlistOrderPayload")
+	}
+
+	var r0 *entity.OrderPayload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context) (*entity.OrderPayload, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context) *entity.OrderPayload); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.OrderPayload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveReadingListBook provides a mock function with given fields: c, readingListID, bookID
+func (_m *ReadingListUsecaseInterface) RemoveReadingListBook(c *gin.Context, readingListID int, bookID int) (*entity.ReadingList, error) {
+	ret := _m.Called(c, readingListID, bookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveReadingListBook")
+	}
+
+	var r0 *entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, int) (*entity.ReadingList, error)); ok {
+		return rf(c, readingListID, bookID)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, int) *entity.ReadingList); ok {
+		r0 = rf(c, readingListID, bookID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, int, int) error); ok {
+		r1 = rf(c, readingListID, bookID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveWishlistBook provides a mock function with given fields: c, bookID
+func (_m *ReadingListUsecaseInterface) RemoveWishlistBook(c *gin.Context, bookID int) (*entity.ReadingList, error) {
+	ret := _m.Called(c, bookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveWishlistBook")
+	}
+
+	var r0 *entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, int) (*entity.ReadingList, error)); ok {
+		return rf(c, bookID)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, int) *entity.ReadingList); ok {
+		r0 = rf(c, bookID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, int) error); ok {
+		r1 = rf(c, bookID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateReadingList provides a mock function with given fields: c, readingListID, payload
+func (_m *ReadingListUsecaseInterface) UpdateReadingList(c *gin.Context, readingListID int, payload *entity.ReadingListPayload) (*entity.ReadingList, error) {
+	ret := _m.Called(c, readingListID, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateReadingList")
+	}
+
+	var r0 *entity.ReadingList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, *entity.ReadingListPayload) (*entity.ReadingList, error)); ok {
+		return rf(c, readingListID, payload)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, *entity.ReadingListPayload) *entity.ReadingList); ok {
+		r0 = rf(c, readingListID, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, int, *entity.ReadingListPayload) error); ok {
+		r1 = rf(c, readingListID, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewReadingListUsecaseInterface creates a new instance of ReadingListUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReadingListUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReadingListUsecaseInterface {
+	mock := &ReadingListUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,146 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RecommendationRepositoryInterface is an autogenerated mock type for the RecommendationRepositoryInterface type
+type RecommendationRepositoryInterface struct {
+	mock.Mock
+}
+
+// GetBestsellingBookIDs provides a mock function with given fields: ctx, limit
+func (_m *RecommendationRepositoryInterface) GetBestsellingBookIDs(ctx context.Context, limit int) ([]int, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBestsellingBookIDs")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]int, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []int); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRelatedBookIDs provides a mock function with given fields: ctx, bookID, limit
+func (_m *RecommendationRepositoryInterface) GetRelatedBookIDs(ctx context.Context, bookID int, limit int) ([]int, error) {
+	ret := _m.Called(ctx, bookID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRelatedBookIDs")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]int, error)); ok {
+		return rf(ctx, bookID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []int); ok {
+		r0 = rf(ctx, bookID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, bookID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserRecommendedBookIDs provides a mock function with given fields: ctx, userID, limit
+func (_m *RecommendationRepositoryInterface) GetUserRecommendedBookIDs(ctx context.Context, userID int, limit int) ([]int, error) {
+	ret := _m.Called(ctx, userID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserRecommendedBookIDs")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]int, error)); ok {
+		return rf(ctx, userID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []int); ok {
+		r0 = rf(ctx, userID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, userID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshBookRecommendations provides a mock function with given fields: ctx, dbTrx, perBook
+func (_m *RecommendationRepositoryInterface) RefreshBookRecommendations(ctx context.Context, dbTrx interface{}, perBook int) (bool, error) {
+	ret := _m.Called(ctx, dbTrx, perBook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshBookRecommendations")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) (bool, error)); ok {
+		return rf(ctx, dbTrx, perBook)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) bool); ok {
+		r0 = rf(ctx, dbTrx, perBook)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int) error); ok {
+		r1 = rf(ctx, dbTrx, perBook)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRecommendationRepositoryInterface creates a new instance of RecommendationRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRecommendationRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RecommendationRepositoryInterface {
+	mock := &RecommendationRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,109 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	gin "github.com/gin-gonic/gin"
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RecommendationUsecaseInterface is an autogenerated mock type for the RecommendationUsecaseInterface type
+type RecommendationUsecaseInterface struct {
+	mock.Mock
+}
+
+// GetRelatedBooks provides a mock function with given fields: ctx, bookID, limit
+func (_m *RecommendationUsecaseInterface) GetRelatedBooks(ctx context.Context, bookID int, limit int) ([]*entity.Book, error) {
+	ret := _m.Called(ctx, bookID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRelatedBooks")
+	}
+
+	var r0 []*entity.Book
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*entity.Book, error)); ok {
+		return rf(ctx, bookID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*entity.Book); ok {
+		r0 = rf(ctx, bookID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, bookID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserRecommendations provides a mock function with given fields: c, limit
+func (_m *RecommendationUsecaseInterface) GetUserRecommendations(c *gin.Context, limit int) ([]*entity.Book, error) {
+	ret := _m.Called(c, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserRecommendations")
+	}
+
+	var r0 []*entity.Book
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, int) ([]*entity.Book, error)); ok {
+		return rf(c, limit)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, int) []*entity.Book); ok {
+		r0 = rf(c, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, int) error); ok {
+		r1 = rf(c, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshRecommendations provides a mock function with given fields: ctx
+func (_m *RecommendationUsecaseInterface) RefreshRecommendations(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshRecommendations")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRecommendationUsecaseInterface creates a new instance of RecommendationUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRecommendationUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RecommendationUsecaseInterface {
+	mock := &RecommendationUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,299 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReviewRepositoryInterface is an autogenerated mock type for the ReviewRepositoryInterface type
+type ReviewRepositoryInterface struct {
+	mock.Mock
+}
+
+// CreateReview provides a mock function with given fields: ctx, dbTrx, review
+func (_m *ReviewRepositoryInterface) CreateReview(ctx context.Context, dbTrx interface{}, review *entity.Review) error {
+	ret := _m.Called(ctx, dbTrx, review)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateReview")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, *entity.Review) error); ok {
+		r0 = rf(ctx, dbTrx, review)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateReviewReport provides a mock function with given fields: ctx, dbTrx, report
+func (_m *ReviewRepositoryInterface) CreateReviewReport(ctx context.Context, dbTrx interface{}, report *entity.ReviewReport) error {
+	ret := _m.Called(ctx, dbTrx, report)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateReviewReport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, *entity.ReviewReport) error); ok {
+		r0 = rf(ctx, dbTrx, report)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetReviewByID provides a mock function with given fields: ctx, reviewID
+func (_m *ReviewRepositoryInterface) GetReviewByID(ctx context.Context, reviewID int) (*entity.Review, error) {
+	ret := _m.Called(ctx, reviewID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviewByID")
+	}
+
+	var r0 *entity.Review
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*entity.Review, error)); ok {
+		return rf(ctx, reviewID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *entity.Review); ok {
+		r0 = rf(ctx, reviewID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, reviewID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReviewsByBookID provides a mock function with given fields: ctx, bookID, limit, offset
+func (_m *ReviewRepositoryInterface) GetReviewsByBookID(ctx context.Context, bookID int, limit int, offset int) ([]*entity.Review, error) {
+	ret := _m.Called(ctx, bookID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviewsByBookID")
+	}
+
+	var r0 []*entity.Review
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) ([]*entity.Review, error)); ok {
+		return rf(ctx, bookID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, int) []*entity.Review); ok {
+		r0 = rf(ctx, bookID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, int) error); ok {
+		r1 = rf(ctx, bookID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReviewsByBookIDCount provides a mock function with given fields: ctx, bookID
+func (_m *ReviewRepositoryInterface) GetReviewsByBookIDCount(ctx context.Context, bookID int) (int, error) {
+	ret := _m.Called(ctx, bookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviewsByBookIDCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, bookID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, bookID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, bookID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReviewsByStatus provides a mock function with given fields: ctx, status, limit, offset
+func (_m *ReviewRepositoryInterface) GetReviewsByStatus(ctx context.Context, status string, limit int, offset int) ([]*entity.Review, error) {
+	ret := _m.Called(ctx, status, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviewsByStatus")
+	}
+
+	var r0 []*entity.Review
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) ([]*entity.Review, error)); ok {
+		return rf(ctx, status, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []*entity.Review); ok {
+		r0 = rf(ctx, status, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, status, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReviewsByStatusCount provides a mock function with given fields: ctx, status
+func (_m *ReviewRepositoryInterface) GetReviewsByStatusCount(ctx context.Context, status string) (int, error) {
+	ret := _m.Called(ctx, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviewsByStatusCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int, error)); ok {
+		return rf(ctx, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int); ok {
+		r0 = rf(ctx, status)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReviewsByUserID provides a mock function with given fields: ctx, userID
+func (_m *ReviewRepositoryInterface) GetReviewsByUserID(ctx context.Context, userID int) ([]*entity.Review, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviewsByUserID")
+	}
+
+	var r0 []*entity.Review
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*entity.Review, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*entity.Review); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrementReviewReportCount provides a mock function with given fields: ctx, dbTrx, reviewID, threshold
+func (_m *ReviewRepositoryInterface) IncrementReviewReportCount(ctx context.Context, dbTrx interface{}, reviewID int, threshold int) (string, error) {
+	ret := _m.Called(ctx, dbTrx, reviewID, threshold)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementReviewReportCount")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) (string, error)); ok {
+		return rf(ctx, dbTrx, reviewID, threshold)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) string); ok {
+		r0 = rf(ctx, dbTrx, reviewID, threshold)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int, int) error); ok {
+		r1 = rf(ctx, dbTrx, reviewID, threshold)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateReviewsStatus provides a mock function with given fields: ctx, dbTrx, reviewIDs, status
+func (_m *ReviewRepositoryInterface) UpdateReviewsStatus(ctx context.Context, dbTrx interface{}, reviewIDs []int, status string) ([]int, error) {
+	ret := _m.Called(ctx, dbTrx, reviewIDs, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateReviewsStatus")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, []int, string) ([]int, error)); ok {
+		return rf(ctx, dbTrx, reviewIDs, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, []int, string) []int); ok {
+		r0 = rf(ctx, dbTrx, reviewIDs, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, []int, string) error); ok {
+		r1 = rf(ctx, dbTrx, reviewIDs, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewReviewRepositoryInterface creates a new instance of ReviewRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReviewRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReviewRepositoryInterface {
+	mock := &ReviewRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,181 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	gin "github.com/gin-gonic/gin"
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReviewUsecaseInterface is an autogenerated mock type for the ReviewUsecaseInterface type
+type ReviewUsecaseInterface struct {
+	mock.Mock
+}
+
+// CreateReview provides a mock function with given fields: c, bookID, payload
+func (_m *ReviewUsecaseInterface) CreateReview(c *gin.Context, bookID int, payload *entity.ReviewPayload) (*entity.Review, error) {
+	ret := _m.Called(c, bookID, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateReview")
+	}
+
+	var r0 *entity.Review
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, *entity.ReviewPayload) (*entity.Review, error)); ok {
+		return rf(c, bookID, payload)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, *entity.ReviewPayload) *entity.Review); ok {
+		r0 = rf(c, bookID, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, int, *entity.ReviewPayload) error); ok {
+		r1 = rf(c, bookID, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReviewsByBookID provides a mock function with given fields: c, bookID, limit, offset
+func (_m *ReviewUsecaseInterface) GetReviewsByBookID(c *gin.Context, bookID int, limit int, offset int) ([]*entity.Review, int, error) {
+	ret := _m.Called(c, bookID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviewsByBookID")
+	}
+
+	var r0 []*entity.Review
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, int, int) ([]*entity.Review, int, error)); ok {
+		return rf(c, bookID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, int, int) []*entity.Review); ok {
+		r0 = rf(c, bookID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, int, int, int) int); ok {
+		r1 = rf(c, bookID, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(*gin.Context, int, int, int) error); ok {
+		r2 = rf(c, bookID, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetReviewsByStatus provides a mock function with given fields: c, status, limit, offset
+func (_m *ReviewUsecaseInterface) GetReviewsByStatus(c *gin.Context, status string, limit int, offset int) ([]*entity.Review, int, error) {
+	ret := _m.Called(c, status, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviewsByStatus")
+	}
+
+	var r0 []*entity.Review
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, string, int, int) ([]*entity.Review, int, error)); ok {
+		return rf(c, status, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, string, int, int) []*entity.Review); ok {
+		r0 = rf(c, status, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, string, int, int) int); ok {
+		r1 = rf(c, status, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(*gin.Context, string, int, int) error); ok {
+		r2 = rf(c, status, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ModerateReviews provides a mock function with given fields: c, payload
+func (_m *ReviewUsecaseInterface) ModerateReviews(c *gin.Context, payload *entity.ModerateReviewsPayload) error {
+	ret := _m.Called(c, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ModerateReviews")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, *entity.ModerateReviewsPayload) error); ok {
+		r0 = rf(c, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReportReview provides a mock function with given fields: c, reviewID, payload
+func (_m *ReviewUsecaseInterface) ReportReview(c *gin.Context, reviewID int, payload *entity.ReviewReportPayload) (*entity.ReviewReport, error) {
+	ret := _m.Called(c, reviewID, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReportReview")
+	}
+
+	var r0 *entity.ReviewReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, *entity.ReviewReportPayload) (*entity.ReviewReport, error)); ok {
+		return rf(c, reviewID, payload)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, int, *entity.ReviewReportPayload) *entity.ReviewReport); ok {
+		r0 = rf(c, reviewID, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ReviewReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, int, *entity.ReviewReportPayload) error); ok {
+		r1 = rf(c, reviewID, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewReviewUsecaseInterface creates a new instance of ReviewUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReviewUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReviewUsecaseInterface {
+	mock := &ReviewUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
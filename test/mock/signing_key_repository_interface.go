@@ -0,0 +1,115 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// SigningKeyRepositoryInterface is an autogenerated mock type for the SigningKeyRepositoryInterface type
+type SigningKeyRepositoryInterface struct {
+	mock.Mock
+}
+
+// CreateSigningKey provides a mock function with given fields: ctx, dbTrx, key
+func (_m *SigningKeyRepositoryInterface) CreateSigningKey(ctx context.Context, dbTrx interface{}, key *entity.SigningKey) error {
+	ret := _m.Called(ctx, dbTrx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSigningKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, *entity.SigningKey) error); ok {
+		r0 = rf(ctx, dbTrx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSigningKeysCreatedBefore provides a mock function with given fields: ctx, dbTrx, createdBefore
+func (_m *SigningKeyRepositoryInterface) DeleteSigningKeysCreatedBefore(ctx context.Context, dbTrx interface{}, createdBefore time.Time) error {
+	ret := _m.Called(ctx, dbTrx, createdBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSigningKeysCreatedBefore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, time.Time) error); ok {
+		r0 = rf(ctx, dbTrx, createdBefore)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetSigningKeys provides a mock function with given fields: ctx, dbTrx
+func (_m *SigningKeyRepositoryInterface) GetSigningKeys(ctx context.Context, dbTrx interface{}) ([]*entity.SigningKey, error) {
+	ret := _m.Called(ctx, dbTrx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSigningKeys")
+	}
+
+	var r0 []*entity.SigningKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}) ([]*entity.SigningKey, error)); ok {
+		return rf(ctx, dbTrx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}) []*entity.SigningKey); ok {
+		r0 = rf(ctx, dbTrx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.SigningKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}) error); ok {
+		r1 = rf(ctx, dbTrx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockSigningKeys provides a mock function with given fields: ctx, dbTrx
+func (_m *SigningKeyRepositoryInterface) LockSigningKeys(ctx context.Context, dbTrx interface{}) error {
+	ret := _m.Called(ctx, dbTrx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockSigningKeys")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}) error); ok {
+		r0 = rf(ctx, dbTrx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSigningKeyRepositoryInterface creates a new instance of SigningKeyRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSigningKeyRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SigningKeyRepositoryInterface {
+	mock := &SigningKeyRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,110 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	auth "github.com/satriowisnugroho/book-store/pkg/auth"
+
+	jwt "github.com/golang-jwt/jwt"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SigningKeyUsecaseInterface is an autogenerated mock type for the SigningKeyUsecaseInterface type
+type SigningKeyUsecaseInterface struct {
+	mock.Mock
+}
+
+// GetJWKS provides a mock function with given fields: ctx
+func (_m *SigningKeyUsecaseInterface) GetJWKS(ctx context.Context) (*auth.JWKS, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJWKS")
+	}
+
+	var r0 *auth.JWKS
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*auth.JWKS, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *auth.JWKS); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*auth.JWKS)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseAccessToken provides a mock function with given fields: tokenString
+func (_m *SigningKeyUsecaseInterface) ParseAccessToken(tokenString string) (jwt.MapClaims, error) {
+	ret := _m.Called(tokenString)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseAccessToken")
+	}
+
+	var r0 jwt.MapClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (jwt.MapClaims, error)); ok {
+		return rf(tokenString)
+	}
+	if rf, ok := ret.Get(0).(func(string) jwt.MapClaims); ok {
+		r0 = rf(tokenString)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(jwt.MapClaims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tokenString)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RotateSigningKeys provides a mock function with given fields: ctx
+func (_m *SigningKeyUsecaseInterface) RotateSigningKeys(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateSigningKeys")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSigningKeyUsecaseInterface creates a new instance of SigningKeyUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSigningKeyUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SigningKeyUsecaseInterface {
+	mock := &SigningKeyUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
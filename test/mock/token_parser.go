@@ -0,0 +1,58 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	jwt "github.com/golang-jwt/jwt"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TokenParser is an autogenerated mock type for the TokenParser type
+type TokenParser struct {
+	mock.Mock
+}
+
+// ParseAccessToken provides a mock function with given fields: tokenString
+func (_m *TokenParser) ParseAccessToken(tokenString string) (jwt.MapClaims, error) {
+	ret := _m.Called(tokenString)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseAccessToken")
+	}
+
+	var r0 jwt.MapClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (jwt.MapClaims, error)); ok {
+		return rf(tokenString)
+	}
+	if rf, ok := ret.Get(0).(func(string) jwt.MapClaims); ok {
+		r0 = rf(tokenString)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(jwt.MapClaims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tokenString)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTokenParser creates a new instance of TokenParser. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenParser(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenParser {
+	mock := &TokenParser{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
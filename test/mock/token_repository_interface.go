@@ -0,0 +1,641 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/satriowisnugroho/book-store/internal/entity"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// TokenRepositoryInterface is an autogenerated mock type for the TokenRepositoryInterface type
+type TokenRepositoryInterface struct {
+	mock.Mock
+}
+
+// CountEmailVerificationTokensSince provides a mock function with given fields: ctx, userID, since
+func (_m *TokenRepositoryInterface) CountEmailVerificationTokensSince(ctx context.Context, userID int, since time.Time) (int, error) {
+	ret := _m.Called(ctx, userID, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountEmailVerificationTokensSince")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) (int, error)); ok {
+		return rf(ctx, userID, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Time) int); ok {
+		r0 = rf(ctx, userID, since)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Time) error); ok {
+		r1 = rf(ctx, userID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateEmailVerificationToken provides a mock function with given fields: ctx, token
+func (_m *TokenRepositoryInterface) CreateEmailVerificationToken(ctx context.Context, token *entity.EmailVerificationToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateEmailVerificationToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.EmailVerificationToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateLoginChallenge provides a mock function with given fields: ctx, challenge
+func (_m *TokenRepositoryInterface) CreateLoginChallenge(ctx context.Context, challenge *entity.LoginChallenge) error {
+	ret := _m.Called(ctx, challenge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateLoginChallenge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.LoginChallenge) error); ok {
+		r0 = rf(ctx, challenge)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateOIDCLoginState provides a mock function with given fields: ctx, state
+func (_m *TokenRepositoryInterface) CreateOIDCLoginState(ctx context.Context, state *entity.OIDCLoginState) error {
+	ret := _m.Called(ctx, state)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOIDCLoginState")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.OIDCLoginState) error); ok {
+		r0 = rf(ctx, state)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreatePasswordResetToken provides a mock function with given fields: ctx, token
+func (_m *TokenRepositoryInterface) CreatePasswordResetToken(ctx context.Context, token *entity.PasswordResetToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePasswordResetToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.PasswordResetToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateRefreshToken provides a mock function with given fields: ctx, dbTrx, token
+func (_m *TokenRepositoryInterface) CreateRefreshToken(ctx context.Context, dbTrx interface{}, token *entity.RefreshToken) error {
+	ret := _m.Called(ctx, dbTrx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, *entity.RefreshToken) error); ok {
+		r0 = rf(ctx, dbTrx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateSession provides a mock function with given fields: ctx, dbTrx, session
+func (_m *TokenRepositoryInterface) CreateSession(ctx context.Context, dbTrx interface{}, session *entity.Session) error {
+	ret := _m.Called(ctx, dbTrx, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, *entity.Session) error); ok {
+		r0 = rf(ctx, dbTrx, session)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpiredTokens provides a mock function with given fields: ctx
+func (_m *TokenRepositoryInterface) DeleteExpiredTokens(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetEmailVerificationTokenByHash provides a mock function with given fields: ctx, dbTrx, tokenHash
+func (_m *TokenRepositoryInterface) GetEmailVerificationTokenByHash(ctx context.Context, dbTrx interface{}, tokenHash string) (*entity.EmailVerificationToken, error) {
+	ret := _m.Called(ctx, dbTrx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEmailVerificationTokenByHash")
+	}
+
+	var r0 *entity.EmailVerificationToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string) (*entity.EmailVerificationToken, error)); ok {
+		return rf(ctx, dbTrx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string) *entity.EmailVerificationToken); ok {
+		r0 = rf(ctx, dbTrx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.EmailVerificationToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, string) error); ok {
+		r1 = rf(ctx, dbTrx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLoginChallengeByHash provides a mock function with given fields: ctx, dbTrx, tokenHash
+func (_m *TokenRepositoryInterface) GetLoginChallengeByHash(ctx context.Context, dbTrx interface{}, tokenHash string) (*entity.LoginChallenge, error) {
+	ret := _m.Called(ctx, dbTrx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginChallengeByHash")
+	}
+
+	var r0 *entity.LoginChallenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string) (*entity.LoginChallenge, error)); ok {
+		return rf(ctx, dbTrx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string) *entity.LoginChallenge); ok {
+		r0 = rf(ctx, dbTrx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.LoginChallenge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, string) error); ok {
+		r1 = rf(ctx, dbTrx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOIDCLoginStateByHash provides a mock function with given fields: ctx, dbTrx, stateHash
+func (_m *TokenRepositoryInterface) GetOIDCLoginStateByHash(ctx context.Context, dbTrx interface{}, stateHash string) (*entity.OIDCLoginState, error) {
+	ret := _m.Called(ctx, dbTrx, stateHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOIDCLoginStateByHash")
+	}
+
+	var r0 *entity.OIDCLoginState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string) (*entity.OIDCLoginState, error)); ok {
+		return rf(ctx, dbTrx, stateHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string) *entity.OIDCLoginState); ok {
+		r0 = rf(ctx, dbTrx, stateHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.OIDCLoginState)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, string) error); ok {
+		r1 = rf(ctx, dbTrx, stateHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPasswordResetTokenByHash provides a mock function with given fields: ctx, dbTrx, tokenHash
+func (_m *TokenRepositoryInterface) GetPasswordResetTokenByHash(ctx context.Context, dbTrx interface{}, tokenHash string) (*entity.PasswordResetToken, error) {
+	ret := _m.Called(ctx, dbTrx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPasswordResetTokenByHash")
+	}
+
+	var r0 *entity.PasswordResetToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string) (*entity.PasswordResetToken, error)); ok {
+		return rf(ctx, dbTrx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string) *entity.PasswordResetToken); ok {
+		r0 = rf(ctx, dbTrx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.PasswordResetToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, string) error); ok {
+		r1 = rf(ctx, dbTrx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRefreshTokenByHash provides a mock function with given fields: ctx, dbTrx, tokenHash
+func (_m *TokenRepositoryInterface) GetRefreshTokenByHash(ctx context.Context, dbTrx interface{}, tokenHash string) (*entity.RefreshToken, error) {
+	ret := _m.Called(ctx, dbTrx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRefreshTokenByHash")
+	}
+
+	var r0 *entity.RefreshToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string) (*entity.RefreshToken, error)); ok {
+		return rf(ctx, dbTrx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string) *entity.RefreshToken); ok {
+		r0 = rf(ctx, dbTrx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RefreshToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, string) error); ok {
+		r1 = rf(ctx, dbTrx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessionByFamilyID provides a mock function with given fields: ctx, dbTrx, familyID
+func (_m *TokenRepositoryInterface) GetSessionByFamilyID(ctx context.Context, dbTrx interface{}, familyID string) (*entity.Session, error) {
+	ret := _m.Called(ctx, dbTrx, familyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSessionByFamilyID")
+	}
+
+	var r0 *entity.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string) (*entity.Session, error)); ok {
+		return rf(ctx, dbTrx, familyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string) *entity.Session); ok {
+		r0 = rf(ctx, dbTrx, familyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, string) error); ok {
+		r1 = rf(ctx, dbTrx, familyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessionsByUserID provides a mock function with given fields: ctx, userID
+func (_m *TokenRepositoryInterface) GetSessionsByUserID(ctx context.Context, userID int) ([]*entity.Session, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSessionsByUserID")
+	}
+
+	var r0 []*entity.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*entity.Session, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*entity.Session); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsAccessTokenRevoked provides a mock function with given fields: ctx, jti
+func (_m *TokenRepositoryInterface) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	ret := _m.Called(ctx, jti)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsAccessTokenRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, jti)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, jti)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, jti)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsSessionRevoked provides a mock function with given fields: ctx, sessionID
+func (_m *TokenRepositoryInterface) IsSessionRevoked(ctx context.Context, sessionID int) (bool, error) {
+	ret := _m.Called(ctx, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsSessionRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (bool, error)); ok {
+		return rf(ctx, sessionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) bool); ok {
+		r0 = rf(ctx, sessionID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeAccessToken provides a mock function with given fields: ctx, jti, expiresAt
+func (_m *TokenRepositoryInterface) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	ret := _m.Called(ctx, jti, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAccessToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, jti, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeRefreshToken provides a mock function with given fields: ctx, dbTrx, tokenID
+func (_m *TokenRepositoryInterface) RevokeRefreshToken(ctx context.Context, dbTrx interface{}, tokenID int) (bool, error) {
+	ret := _m.Called(ctx, dbTrx, tokenID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshToken")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) (bool, error)); ok {
+		return rf(ctx, dbTrx, tokenID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) bool); ok {
+		r0 = rf(ctx, dbTrx, tokenID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int) error); ok {
+		r1 = rf(ctx, dbTrx, tokenID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeRefreshTokenFamily provides a mock function with given fields: ctx, dbTrx, familyID
+func (_m *TokenRepositoryInterface) RevokeRefreshTokenFamily(ctx context.Context, dbTrx interface{}, familyID string) error {
+	ret := _m.Called(ctx, dbTrx, familyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshTokenFamily")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string) error); ok {
+		r0 = rf(ctx, dbTrx, familyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeSession provides a mock function with given fields: ctx, dbTrx, userID, sessionID
+func (_m *TokenRepositoryInterface) RevokeSession(ctx context.Context, dbTrx interface{}, userID int, sessionID int) error {
+	ret := _m.Called(ctx, dbTrx, userID, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) error); ok {
+		r0 = rf(ctx, dbTrx, userID, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeUserRefreshTokens provides a mock function with given fields: ctx, dbTrx, userID, keepSessionID
+func (_m *TokenRepositoryInterface) RevokeUserRefreshTokens(ctx context.Context, dbTrx interface{}, userID int, keepSessionID int) error {
+	ret := _m.Called(ctx, dbTrx, userID, keepSessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserRefreshTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, int) error); ok {
+		r0 = rf(ctx, dbTrx, userID, keepSessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TouchSession provides a mock function with given fields: ctx, dbTrx, sessionID, ipAddress, expiresAt
+func (_m *TokenRepositoryInterface) TouchSession(ctx context.Context, dbTrx interface{}, sessionID int, ipAddress string, expiresAt time.Time) error {
+	ret := _m.Called(ctx, dbTrx, sessionID, ipAddress, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, string, time.Time) error); ok {
+		r0 = rf(ctx, dbTrx, sessionID, ipAddress, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseEmailVerificationTokens provides a mock function with given fields: ctx, dbTrx, userID
+func (_m *TokenRepositoryInterface) UseEmailVerificationTokens(ctx context.Context, dbTrx interface{}, userID int) error {
+	ret := _m.Called(ctx, dbTrx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseEmailVerificationTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) error); ok {
+		r0 = rf(ctx, dbTrx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseLoginChallenge provides a mock function with given fields: ctx, dbTrx, challengeID
+func (_m *TokenRepositoryInterface) UseLoginChallenge(ctx context.Context, dbTrx interface{}, challengeID int) error {
+	ret := _m.Called(ctx, dbTrx, challengeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseLoginChallenge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) error); ok {
+		r0 = rf(ctx, dbTrx, challengeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseOIDCLoginState provides a mock function with given fields: ctx, dbTrx, stateID
+func (_m *TokenRepositoryInterface) UseOIDCLoginState(ctx context.Context, dbTrx interface{}, stateID int) error {
+	ret := _m.Called(ctx, dbTrx, stateID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseOIDCLoginState")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) error); ok {
+		r0 = rf(ctx, dbTrx, stateID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UsePasswordResetTokens provides a mock function with given fields: ctx, dbTrx, userID
+func (_m *TokenRepositoryInterface) UsePasswordResetTokens(ctx context.Context, dbTrx interface{}, userID int) error {
+	ret := _m.Called(ctx, dbTrx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UsePasswordResetTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) error); ok {
+		r0 = rf(ctx, dbTrx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTokenRepositoryInterface creates a new instance of TokenRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenRepositoryInterface {
+	mock := &TokenRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -14,6 +14,24 @@ type UserRepositoryInterface struct {
 	mock.Mock
 }
 
+// ChangeUserEmail provides a mock function with given fields: ctx, dbTrx, userID, email
+func (_m *UserRepositoryInterface) ChangeUserEmail(ctx context.Context, dbTrx interface{}, userID int, email string) error {
+	ret := _m.Called(ctx, dbTrx, userID, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeUserEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, string) error); ok {
+		r0 = rf(ctx, dbTrx, userID, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateUser provides a mock function with given fields: ctx, user
 func (_m *UserRepositoryInterface) CreateUser(ctx context.Context, user *entity.User) error {
 	ret := _m.Called(ctx, user)
@@ -32,6 +50,42 @@ func (_m *UserRepositoryInterface) CreateUser(ctx context.Context, user *entity.
 	return r0
 }
 
+// DeleteUser provides a mock function with given fields: ctx, dbTrx, userID
+func (_m *UserRepositoryInterface) DeleteUser(ctx context.Context, dbTrx interface{}, userID int) error {
+	ret := _m.Called(ctx, dbTrx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) error); ok {
+		r0 = rf(ctx, dbTrx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EraseUser provides a mock function with given fields: ctx, dbTrx, userID
+func (_m *UserRepositoryInterface) EraseUser(ctx context.Context, dbTrx interface{}, userID int) error {
+	ret := _m.Called(ctx, dbTrx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EraseUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) error); ok {
+		r0 = rf(ctx, dbTrx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetUserByEmail provides a mock function with given fields: ctx, email
 func (_m *UserRepositoryInterface) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
 	ret := _m.Called(ctx, email)
@@ -62,6 +116,120 @@ func (_m *UserRepositoryInterface) GetUserByEmail(ctx context.Context, email str
 	return r0, r1
 }
 
+// GetUserByID provides a mock function with given fields: ctx, userID
+func (_m *UserRepositoryInterface) GetUserByID(ctx context.Context, userID int) (*entity.User, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*entity.User, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *entity.User); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByIDUnscoped provides a mock function with given fields: ctx, userID
+func (_m *UserRepositoryInterface) GetUserByIDUnscoped(ctx context.Context, userID int) (*entity.User, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByIDUnscoped")
+	}
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*entity.User, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *entity.User); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUser provides a mock function with given fields: ctx, dbTrx, user
+func (_m *UserRepositoryInterface) UpdateUser(ctx context.Context, dbTrx interface{}, user *entity.User) error {
+	ret := _m.Called(ctx, dbTrx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, *entity.User) error); ok {
+		r0 = rf(ctx, dbTrx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUserPassword provides a mock function with given fields: ctx, dbTrx, userID, cryptedPassword
+func (_m *UserRepositoryInterface) UpdateUserPassword(ctx context.Context, dbTrx interface{}, userID int, cryptedPassword string) error {
+	ret := _m.Called(ctx, dbTrx, userID, cryptedPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int, string) error); ok {
+		r0 = rf(ctx, dbTrx, userID, cryptedPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyUser provides a mock function with given fields: ctx, dbTrx, userID
+func (_m *UserRepositoryInterface) VerifyUser(ctx context.Context, dbTrx interface{}, userID int) error {
+	ret := _m.Called(ctx, dbTrx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int) error); ok {
+		r0 = rf(ctx, dbTrx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserRepositoryInterface creates a new instance of UserRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepositoryInterface(t interface {
@@ -5,7 +5,9 @@ package mocks
 import (
 	context "context"
 
+	gin "github.com/gin-gonic/gin"
 	entity "github.com/satriowisnugroho/book-store/internal/entity"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -14,6 +16,54 @@ type UserUsecaseInterface struct {
 	mock.Mock
 }
 
+// ChangePassword provides a mock function with given fields: c, payload
+func (_m *UserUsecaseInterface) ChangePassword(c *gin.Context, payload *entity.ChangePasswordPayload) error {
+	ret := _m.Called(c, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, *entity.ChangePasswordPayload) error); ok {
+		r0 = rf(c, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConfirmTOTP provides a mock function with given fields: c, payload
+func (_m *UserUsecaseInterface) ConfirmTOTP(c *gin.Context, payload *entity.TOTPCodePayload) (*entity.RecoveryCodesResponse, error) {
+	ret := _m.Called(c, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmTOTP")
+	}
+
+	var r0 *entity.RecoveryCodesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, *entity.TOTPCodePayload) (*entity.RecoveryCodesResponse, error)); ok {
+		return rf(c, payload)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, *entity.TOTPCodePayload) *entity.RecoveryCodesResponse); ok {
+		r0 = rf(c, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RecoveryCodesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, *entity.TOTPCodePayload) error); ok {
+		r1 = rf(c, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateUser provides a mock function with given fields: ctx, payload
 func (_m *UserUsecaseInterface) CreateUser(ctx context.Context, payload *entity.RegisterPayload) (*entity.User, error) {
 	ret := _m.Called(ctx, payload)
@@ -44,29 +94,505 @@ func (_m *UserUsecaseInterface) CreateUser(ctx context.Context, payload *entity.
 	return r0, r1
 }
 
-// Login provides a mock function with given fields: ctx, payload
-func (_m *UserUsecaseInterface) Login(ctx context.Context, payload *entity.LoginPayload) (*entity.LoginResponse, error) {
+// DeleteAccount provides a mock function with given fields: c
+func (_m *UserUsecaseInterface) DeleteAccount(c *gin.Context) error {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*gin.Context) error); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpiredLoginFailures provides a mock function with given fields: ctx
+func (_m *UserUsecaseInterface) DeleteExpiredLoginFailures(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredLoginFailures")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpiredTokens provides a mock function with given fields: ctx
+func (_m *UserUsecaseInterface) DeleteExpiredTokens(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableTOTP provides a mock function with given fields: c, payload
+func (_m *UserUsecaseInterface) DisableTOTP(c *gin.Context, payload *entity.DisableTOTPPayload) error {
+	ret := _m.Called(c, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, *entity.DisableTOTPPayload) error); ok {
+		r0 = rf(c, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnrollTOTP provides a mock function with given fields: c
+func (_m *UserUsecaseInterface) EnrollTOTP(c *gin.Context) (*entity.TOTPEnrollment, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnrollTOTP")
+	}
+
+	var r0 *entity.TOTPEnrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context) (*entity.TOTPEnrollment, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context) *entity.TOTPEnrollment); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.TOTPEnrollment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ForgotPassword provides a mock function with given fields: ctx, payload
+func (_m *UserUsecaseInterface) ForgotPassword(ctx context.Context, payload *entity.ForgotPasswordPayload) error {
 	ret := _m.Called(ctx, payload)
 
+	if len(ret) == 0 {
+		panic("no return value specified for ForgotPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ForgotPasswordPayload) error); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetProfile provides a mock function with given fields: c
+func (_m *UserUsecaseInterface) GetProfile(c *gin.Context) (*entity.User, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProfile")
+	}
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context) (*entity.User, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context) *entity.User); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessions provides a mock function with given fields: c
+func (_m *UserUsecaseInterface) GetSessions(c *gin.Context) ([]*entity.Session, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSessions")
+	}
+
+	var r0 []*entity.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context) ([]*entity.Session, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context) []*entity.Session); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsAccessTokenRevoked provides a mock function with given fields: ctx, jti
+func (_m *UserUsecaseInterface) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	ret := _m.Called(ctx, jti)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsAccessTokenRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, jti)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, jti)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, jti)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsSessionRevoked provides a mock function with given fields: ctx, sessionID
+func (_m *UserUsecaseInterface) IsSessionRevoked(ctx context.Context, sessionID int) (bool, error) {
+	ret := _m.Called(ctx, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsSessionRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (bool, error)); ok {
+		return rf(ctx, sessionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) bool); ok {
+		r0 = rf(ctx, sessionID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: ctx, ipAddress, userAgent, payload
+func (_m *UserUsecaseInterface) Login(ctx context.Context, ipAddress string, userAgent string, payload *entity.LoginPayload) (*entity.LoginResponse, error) {
+	ret := _m.Called(ctx, ipAddress, userAgent, payload)
+
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
 	}
 
 	var r0 *entity.LoginResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.LoginPayload) (*entity.LoginResponse, error)); ok {
-		return rf(ctx, payload)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *entity.LoginPayload) (*entity.LoginResponse, error)); ok {
+		return rf(ctx, ipAddress, userAgent, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *entity.LoginPayload) *entity.LoginResponse); ok {
+		r0 = rf(ctx, ipAddress, userAgent, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.LoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *entity.LoginPayload) error); ok {
+		r1 = rf(ctx, ipAddress, userAgent, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logout provides a mock function with given fields: c, payload
+func (_m *UserUsecaseInterface) Logout(c *gin.Context, payload *entity.RefreshTokenPayload) error {
+	ret := _m.Called(c, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, *entity.RefreshTokenPayload) error); ok {
+		r0 = rf(c, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OIDCLogin provides a mock function with given fields: ctx, ipAddress, userAgent, payload
+func (_m *UserUsecaseInterface) OIDCLogin(ctx context.Context, ipAddress string, userAgent string, payload *entity.OIDCCallbackPayload) (*entity.LoginResponse, error) {
+	ret := _m.Called(ctx, ipAddress, userAgent, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OIDCLogin")
+	}
+
+	var r0 *entity.LoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *entity.OIDCCallbackPayload) (*entity.LoginResponse, error)); ok {
+		return rf(ctx, ipAddress, userAgent, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *entity.OIDCCallbackPayload) *entity.LoginResponse); ok {
+		r0 = rf(ctx, ipAddress, userAgent, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.LoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *entity.OIDCCallbackPayload) error); ok {
+		r1 = rf(ctx, ipAddress, userAgent, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshToken provides a mock function with given fields: ctx, ipAddress, payload
+func (_m *UserUsecaseInterface) RefreshToken(ctx context.Context, ipAddress string, payload *entity.RefreshTokenPayload) (*entity.LoginResponse, error) {
+	ret := _m.Called(ctx, ipAddress, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshToken")
+	}
+
+	var r0 *entity.LoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *entity.RefreshTokenPayload) (*entity.LoginResponse, error)); ok {
+		return rf(ctx, ipAddress, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *entity.RefreshTokenPayload) *entity.LoginResponse); ok {
+		r0 = rf(ctx, ipAddress, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.LoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *entity.RefreshTokenPayload) error); ok {
+		r1 = rf(ctx, ipAddress, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResendVerificationEmail provides a mock function with given fields: c
+func (_m *UserUsecaseInterface) ResendVerificationEmail(c *gin.Context) error {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResendVerificationEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*gin.Context) error); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Error(0)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.LoginPayload) *entity.LoginResponse); ok {
+
+	return r0
+}
+
+// ResetPassword provides a mock function with given fields: ctx, payload
+func (_m *UserUsecaseInterface) ResetPassword(ctx context.Context, payload *entity.ResetPasswordPayload) error {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ResetPasswordPayload) error); ok {
 		r0 = rf(ctx, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeSession provides a mock function with given fields: c, sessionID
+func (_m *UserUsecaseInterface) RevokeSession(c *gin.Context, sessionID int) error {
+	ret := _m.Called(c, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, int) error); ok {
+		r0 = rf(c, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StartOIDCLogin provides a mock function with given fields: ctx
+func (_m *UserUsecaseInterface) StartOIDCLogin(ctx context.Context) (*entity.OIDCAuthorization, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartOIDCLogin")
+	}
+
+	var r0 *entity.OIDCAuthorization
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*entity.OIDCAuthorization, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *entity.OIDCAuthorization); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.OIDCAuthorization)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProfile provides a mock function with given fields: c, payload
+func (_m *UserUsecaseInterface) UpdateProfile(c *gin.Context, payload *entity.UpdateProfilePayload) (*entity.User, error) {
+	ret := _m.Called(c, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*gin.Context, *entity.UpdateProfilePayload) (*entity.User, error)); ok {
+		return rf(c, payload)
+	}
+	if rf, ok := ret.Get(0).(func(*gin.Context, *entity.UpdateProfilePayload) *entity.User); ok {
+		r0 = rf(c, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*gin.Context, *entity.UpdateProfilePayload) error); ok {
+		r1 = rf(c, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyEmail provides a mock function with given fields: ctx, token
+func (_m *UserUsecaseInterface) VerifyEmail(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyLoginChallenge provides a mock function with given fields: ctx, ipAddress, userAgent, payload
+func (_m *UserUsecaseInterface) VerifyLoginChallenge(ctx context.Context, ipAddress string, userAgent string, payload *entity.LoginChallengePayload) (*entity.LoginResponse, error) {
+	ret := _m.Called(ctx, ipAddress, userAgent, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyLoginChallenge")
+	}
+
+	var r0 *entity.LoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *entity.LoginChallengePayload) (*entity.LoginResponse, error)); ok {
+		return rf(ctx, ipAddress, userAgent, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *entity.LoginChallengePayload) *entity.LoginResponse); ok {
+		r0 = rf(ctx, ipAddress, userAgent, payload)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.LoginResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *entity.LoginPayload) error); ok {
-		r1 = rf(ctx, payload)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *entity.LoginChallengePayload) error); ok {
+		r1 = rf(ctx, ipAddress, userAgent, payload)
 	} else {
 		r1 = ret.Error(1)
 	}
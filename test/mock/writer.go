@@ -0,0 +1,78 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// Writer is an autogenerated mock type for the Writer type
+type Writer struct {
+	mock.Mock
+}
+
+// Close provides a mock function with no fields
+func (_m *Writer) Close() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WriteHeader provides a mock function with given fields: columns
+func (_m *Writer) WriteHeader(columns []string) error {
+	ret := _m.Called(columns)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WriteHeader")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]string) error); ok {
+		r0 = rf(columns)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WriteRow provides a mock function with given fields: values
+func (_m *Writer) WriteRow(values []interface{}) error {
+	ret := _m.Called(values)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WriteRow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]interface{}) error); ok {
+		r0 = rf(values)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWriter creates a new instance of Writer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWriter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Writer {
+	mock := &Writer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}